@@ -14,6 +14,7 @@ package excel
 import (
 	"bytes"
 	"container/list"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"math"
@@ -27,6 +28,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 	"unsafe"
@@ -199,6 +201,14 @@ type calcContext struct {
 	sync.Mutex
 	entry      string
 	iterations map[string]uint
+	iterate    bool
+	circular   bool
+	stack      map[string]bool
+	values     map[string]formulaArg
+	results    map[string]formulaArg
+	recalc     map[string]bool
+	names      map[string]bool
+	tables     []calcTable
 }
 
 // cellRef defines the structure of a cell reference.
@@ -266,6 +276,27 @@ func (fa formulaArg) Value() (value string) {
 	return
 }
 
+// firstElement returns the first element as string formula argument if the
+// formula argument is an array, otherwise returns itself.
+func (fa formulaArg) firstElement() formulaArg {
+	if fa.Type != ArgMatrix {
+		return fa
+	}
+	if len(fa.Matrix) > 0 && len(fa.Matrix[0]) > 0 {
+		return newStringFormulaArg(fa.Matrix[0][0].Value())
+	}
+	return newStringFormulaArg("")
+}
+
+// toMatrix returns the formula argument as an array, the single value will be
+// returned as an array with one row and one column.
+func (fa formulaArg) toMatrix() [][]formulaArg {
+	if fa.Type == ArgMatrix && len(fa.Matrix) > 0 && len(fa.Matrix[0]) > 0 {
+		return fa.Matrix
+	}
+	return [][]formulaArg{{fa}}
+}
+
 // ToNumber returns a formula argument with number data type.
 func (fa formulaArg) ToNumber() formulaArg {
 	var n float64
@@ -326,8 +357,12 @@ type formulaFuncs struct {
 
 // CalcCellValue provides a function to get calculated cell value. This feature
 // is currently in working processing. Iterative calculation, implicit
-// intersection, explicit intersection, array formula, table formula and some
-// other formulas are not supported currently.
+// intersection, explicit intersection, array formula and some other formulas
+// are not supported currently.
+//
+// The structured references of the tables such as Table1[Amount],
+// Table1[[#This Row],[Amount]] and [@Amount] will be resolved by the table
+// definitions in the workbook.
 //
 // Supported formula functions:
 //
@@ -477,6 +512,7 @@ type formulaFuncs struct {
 //	F.DIST
 //	F.DIST.RT
 //	FDIST
+//	FILTER
 //	FIND
 //	FINDB
 //	F.INV
@@ -504,6 +540,7 @@ type formulaFuncs struct {
 //	GCD
 //	GEOMEAN
 //	GESTEP
+//	GETPIVOTDATA
 //	GROWTH
 //	HARMEAN
 //	HEX2BIN
@@ -628,6 +665,9 @@ type formulaFuncs struct {
 //	OCT2HEX
 //	ODD
 //	ODDFPRICE
+//	ODDFYIELD
+//	ODDLPRICE
+//	ODDLYIELD
 //	OR
 //	PDURATION
 //	PEARSON
@@ -649,6 +689,7 @@ type formulaFuncs struct {
 //	PRICE
 //	PRICEDISC
 //	PRICEMAT
+//	PROB
 //	PRODUCT
 //	PROPER
 //	PV
@@ -679,6 +720,7 @@ type formulaFuncs struct {
 //	SEC
 //	SECH
 //	SECOND
+//	SEQUENCE
 //	SERIESSUM
 //	SHEET
 //	SHEETS
@@ -690,6 +732,7 @@ type formulaFuncs struct {
 //	SLN
 //	SLOPE
 //	SMALL
+//	SORT
 //	SQRT
 //	SQRTPI
 //	STANDARDIZE
@@ -740,6 +783,7 @@ type formulaFuncs struct {
 //	TYPE
 //	UNICHAR
 //	UNICODE
+//	UNIQUE
 //	UPPER
 //	VALUE
 //	VAR
@@ -773,28 +817,758 @@ func (f *File) CalcCellValue(sheet, cell string, opts ...Options) (result string
 		styleIdx     int
 		token        formulaArg
 	)
-	if token, err = f.calcCellValue(&calcContext{
-		entry:      fmt.Sprintf("%s!%s", sheet, cell),
-		iterations: make(map[string]uint),
-	}, sheet, cell); err != nil {
+	if token, err = f.calcEntryCellValue(sheet, cell); err != nil {
 		return
 	}
 	if !rawCellValue {
 		styleIdx, _ = f.GetCellStyle(sheet, cell)
 	}
-	result = token.Value()
+	return f.formattedCalcResult(styleIdx, token.firstElement().Value(), rawCellValue)
+}
+
+// formattedCalcResult provides a function to format the numeric formula
+// calculation result by given style index.
+func (f *File) formattedCalcResult(styleIdx int, result string, rawCellValue bool) (string, error) {
+	var err error
 	if isNum, precision, decimal := isNumeric(result); isNum {
 		if precision > 15 {
-			result, err = f.formattedValue(styleIdx, strings.ToUpper(strconv.FormatFloat(decimal, 'G', 15, 64)), rawCellValue)
-			return
+			return f.formattedValue(styleIdx, strings.ToUpper(strconv.FormatFloat(decimal, 'G', 15, 64)), rawCellValue)
 		}
 		if !strings.HasPrefix(result, "0") {
 			result, err = f.formattedValue(styleIdx, strings.ToUpper(strconv.FormatFloat(decimal, 'f', -1, 64)), rawCellValue)
 		}
 	}
+	return result, err
+}
+
+// CalcArrayFormula provides a function to get the calculated values of the
+// array formula or dynamic array formula by given worksheet name and cell
+// reference, the array result will be spilled into a two-dimensional array
+// with the rows and columns of the result. The single value result will be
+// returned as an array with one row and one column. For example, get the
+// sorted unique values of the range A1:A10 on Sheet1:
+//
+//	err := f.SetCellFormula("Sheet1", "B1", "SORT(UNIQUE(A1:A10))")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	values, err := f.CalcArrayFormula("Sheet1", "B1")
+//
+// Note that the spilled values will not be written into the worksheet.
+func (f *File) CalcArrayFormula(sheet, cell string, opts ...Options) ([][]string, error) {
+	var (
+		rawCellValue = parseOptions(opts...).RawCellValue
+		styleIdx     int
+		values       [][]string
+	)
+	token, err := f.calcEntryCellValue(sheet, cell)
+	if err != nil {
+		return values, err
+	}
+	if !rawCellValue {
+		styleIdx, _ = f.GetCellStyle(sheet, cell)
+	}
+	mtx := token.Matrix
+	if token.Type != ArgMatrix || len(mtx) == 0 {
+		mtx = [][]formulaArg{{token}}
+	}
+	for _, row := range mtx {
+		var rowValues []string
+		for _, arg := range row {
+			result, err := f.formattedCalcResult(styleIdx, arg.Value(), rawCellValue)
+			if err != nil {
+				return values, err
+			}
+			rowValues = append(rowValues, result)
+		}
+		values = append(values, rowValues)
+	}
+	return values, err
+}
+
+// formulaCell defines the formula cell in the workbook recalculation.
+type formulaCell struct {
+	sheet, cell    string
+	col, row       int
+	rowIdx, colIdx int
+	formula        string
+	result         formulaArg
+	err            error
+}
+
+// formulaGraph defines the cached dependency graph of the formula cells, the
+// graph is valid until the formulas or the defined names of the workbook
+// changed.
+type formulaGraph struct {
+	cells      []formulaCell
+	names      []xlsxDefinedName
+	deps       [][]formulaDependency
+	dependents [][]int
+}
+
+// formulaCellKey defines the worksheet name and the coordinates of the
+// formula cell.
+type formulaCellKey struct {
+	sheet    string
+	col, row int
+}
+
+// formulaDependency defines the worksheet name and range coordinates which
+// referenced by a formula.
+type formulaDependency struct {
+	sheet       string
+	coordinates []int
+}
+
+// CalcError directly maps the formula cell which calculated as an error in
+// the workbook recalculation. The Value is the formula error value of the
+// cell, such as #DIV/0!, and the Err is the calculation error which not a
+// formula error, the Value will be empty in this case.
+type CalcError struct {
+	Sheet   string
+	Cell    string
+	Formula string
+	Value   string
+	Err     error
+}
+
+// CalcAll provides a function to recalculate all formulas in the workbook and
+// write the calculated results as cached values of the formula cells, so that
+// the spreadsheet can be read by the applications which not recalculate
+// formulas. The dependencies between the formulas will be analyzed, and each
+// formula will be calculated once after the formulas it depends on. The
+// formula cells in the circular references will be calculated in the order of
+// the worksheets and cells. The formula errors will be saved as the error
+// values of the cells, and the first calculation error which not a formula
+// error will be returned after all formulas have been calculated, the cached
+// value of that cell will be kept. For example:
+//
+//	if err := f.CalcAll(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) CalcAll() error {
+	cells, err := f.calcFormulaCells()
+	if err != nil {
+		return err
+	}
+	for i := range cells {
+		if e := f.setFormulaCellResult(&cells[i]); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// CalcAllWithReport provides a function to recalculate all formulas in the
+// workbook like CalcAll, and returns the report of all formula cells which
+// calculated as errors in the order of the worksheets and cells, instead of
+// the first calculation error. The report includes the formula error values
+// of the cells, and the calculation errors which not a formula error, the
+// cached values of the cells will be kept in the latter case. The error will
+// be returned only if the workbook can't be read. For example, print the
+// formula cells with errors in the workbook:
+//
+//	report, err := f.CalcAllWithReport()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, e := range report {
+//	    fmt.Println(e.Sheet, e.Cell, e.Formula, e.Value, e.Err)
+//	}
+func (f *File) CalcAllWithReport() ([]CalcError, error) {
+	var report []CalcError
+	cells, err := f.calcFormulaCells()
+	if err != nil {
+		return report, err
+	}
+	for i := range cells {
+		fc := &cells[i]
+		e := CalcError{Sheet: fc.sheet, Cell: fc.cell, Formula: fc.formula}
+		if fc.err != nil && !isFormulaErrorValue(fc.err.Error()) {
+			e.Err = fc.err
+			report = append(report, e)
+			continue
+		}
+		if err = f.setFormulaCellResult(fc); err != nil {
+			return report, err
+		}
+		if fc.err != nil {
+			e.Value = fc.err.Error()
+		} else if fc.result.Type == ArgError {
+			e.Value = fc.result.String
+		}
+		if e.Value != "" {
+			report = append(report, e)
+		}
+	}
+	return report, err
+}
+
+// calcFormulaCells calculate all formula cells in the workbook in the order
+// of the dependencies between the formulas, and returns the formula cells
+// with the calculated results.
+func (f *File) calcFormulaCells() ([]formulaCell, error) {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, err
+	}
+	cells, err := f.getFormulaCells()
+	if err != nil {
+		return cells, err
+	}
+	atomic.StoreInt32(&f.trackDirtyCells, 1)
+	f.dirtyCells.Range(func(ref, _ interface{}) bool {
+		f.dirtyCells.Delete(ref)
+		return true
+	})
+	iterate := wb.CalcPr != nil && wb.CalcPr.Iterate
+	ctx := &calcContext{results: make(map[string]formulaArg)}
+	_, dependents := f.getFormulaGraph(ctx, wb, cells)
+	for _, idx := range sortFormulaCells(dependents, nil) {
+		fc := &cells[idx]
+		ref := fmt.Sprintf("%s!%s", fc.sheet, fc.cell)
+		if iterate {
+			fc.result, fc.err = f.calcEntryCellValue(fc.sheet, fc.cell)
+		} else {
+			ctx.entry, ctx.iterations = ref, make(map[string]uint)
+			fc.result, fc.err = f.calcCellValue(ctx, fc.sheet, fc.cell)
+		}
+		if fc.result = fc.result.firstElement(); fc.err == nil {
+			ctx.results[ref] = fc.result
+		} else if isFormulaErrorValue(fc.err.Error()) {
+			ctx.results[ref] = newErrorFormulaArg(fc.err.Error(), fc.err.Error())
+		}
+	}
+	return cells, err
+}
+
+// getFormulaCells returns all formula cells in the worksheets of the workbook
+// in the order of the worksheets and cells.
+func (f *File) getFormulaCells() ([]formulaCell, error) {
+	var cells []formulaCell
+	for _, name := range f.GetSheetList() {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(name).Error() {
+				continue
+			}
+			return cells, err
+		}
+		ws.Lock()
+		for rowIdx := range ws.SheetData.Row {
+			for colIdx, c := range ws.SheetData.Row[rowIdx].C {
+				if c.F == nil {
+					continue
+				}
+				fc := formulaCell{sheet: name, cell: c.R, rowIdx: rowIdx, colIdx: colIdx, formula: c.F.Content}
+				if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+					fc.formula = getSharedFormula(ws, *c.F.Si, c.R)
+				}
+				if fc.col, fc.row, err = CellNameToCoordinates(c.R); err != nil {
+					ws.Unlock()
+					return cells, err
+				}
+				cells = append(cells, fc)
+			}
+		}
+		ws.Unlock()
+	}
+	return cells, nil
+}
+
+// Recalculate provides a function to recalculate the formulas affected by the
+// changed cells since the last recalculation, and write the calculated
+// results as cached values of the formula cells. The changed cells will be
+// tracked after the first calculation by the "Recalculate", "CalcAll" or
+// "CalcAllWithReport" function, so the first call of this function will
+// calculate all formulas in the workbook like "CalcAll". After that, the
+// cells changed by the functions which set the cell values or formulas will
+// be tracked, and only the formulas which reference the changed cells
+// directly or indirectly will be calculated in the dependency order, the
+// cached values of the other formula cells will be used without calculation.
+// The dependency graph of the formulas will be cached until the formulas,
+// defined names or tables changed. This makes the what-if analysis on a large
+// workbook fast, for example, change the input cell and get the result of the
+// model:
+//
+//	if err := f.SetCellValue("Sheet1", "B1", 0.05); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.Recalculate(); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	result, err := f.GetCellValue("Sheet1", "B10")
+//
+// Note that the changes by other functions, such as inserting or removing
+// rows and columns, will not be tracked, please use the "CalcAll" function
+// to recalculate all formulas in the workbook in this case.
+func (f *File) Recalculate() error {
+	if atomic.LoadInt32(&f.trackDirtyCells) == 0 {
+		return f.CalcAll()
+	}
+	var changed []cellRef
+	f.dirtyCells.Range(func(ref, _ interface{}) bool {
+		f.dirtyCells.Delete(ref)
+		idx := strings.LastIndex(ref.(string), "!")
+		if col, row, err := CellNameToCoordinates(ref.(string)[idx+1:]); err == nil {
+			changed = append(changed, cellRef{Col: col, Row: row, Sheet: ref.(string)[:idx]})
+		}
+		return true
+	})
+	if len(changed) == 0 {
+		return nil
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	cells, err := f.getFormulaCells()
+	if err != nil {
+		return err
+	}
+	iterate := wb.CalcPr != nil && wb.CalcPr.Iterate
+	ctx := &calcContext{results: make(map[string]formulaArg), recalc: make(map[string]bool)}
+	deps, dependents := f.getFormulaGraph(ctx, wb, cells)
+	affected, queue := make([]bool, len(cells)), []int{}
+	for i, fc := range cells {
+		for _, ref := range changed {
+			if (strings.EqualFold(ref.Sheet, fc.sheet) && ref.Col == fc.col && ref.Row == fc.row) ||
+				formulaDependsOn(deps[i], ref) {
+				affected[i], queue = true, append(queue, i)
+				break
+			}
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range dependents[i] {
+			if !affected[j] {
+				affected[j], queue = true, append(queue, j)
+			}
+		}
+	}
+	for i, fc := range cells {
+		if affected[i] {
+			ctx.recalc[fmt.Sprintf("%s!%s", fc.sheet, fc.cell)] = true
+		}
+	}
+	order := sortFormulaCells(dependents, affected)
+	for _, idx := range order {
+		fc := &cells[idx]
+		ref := fmt.Sprintf("%s!%s", fc.sheet, fc.cell)
+		if iterate {
+			fc.result, fc.err = f.calcEntryCellValue(fc.sheet, fc.cell)
+		} else {
+			ctx.entry, ctx.iterations = ref, make(map[string]uint)
+			fc.result, fc.err = f.calcCellValue(ctx, fc.sheet, fc.cell)
+		}
+		if fc.result = fc.result.firstElement(); fc.err == nil {
+			ctx.results[ref] = fc.result
+		}
+	}
+	for _, idx := range order {
+		if e := f.setFormulaCellResult(&cells[idx]); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// markCellDirty provides a function to track the changed cell by given
+// worksheet name and cell reference for the minimal recalculation, the cells
+// will be tracked only after the first calculation.
+func (f *File) markCellDirty(sheet, cell string) {
+	if atomic.LoadInt32(&f.trackDirtyCells) == 0 {
+		return
+	}
+	f.dirtyCells.Store(fmt.Sprintf("%s!%s", sheet, cell), true)
+}
+
+// getFormulaGraph returns the dependencies of each formula cell, and the
+// indexes of the formula cells which depend on each formula cell. The cached
+// dependency graph will be used if the formula cells and the defined names
+// are not changed since the graph was built.
+func (f *File) getFormulaGraph(ctx *calcContext, wb *xlsxWorkbook, cells []formulaCell) ([][]formulaDependency, [][]int) {
+	var names []xlsxDefinedName
+	if wb.DefinedNames != nil {
+		names = wb.DefinedNames.DefinedName
+	}
+	if g := f.formulaGraph; g != nil && len(g.cells) == len(cells) && reflect.DeepEqual(g.names, names) {
+		matched := true
+		for i, fc := range cells {
+			if g.cells[i].sheet != fc.sheet || g.cells[i].cell != fc.cell || g.cells[i].formula != fc.formula {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return g.deps, g.dependents
+		}
+	}
+	g := &formulaGraph{cells: make([]formulaCell, len(cells)), names: make([]xlsxDefinedName, len(names))}
+	for i, fc := range cells {
+		g.cells[i] = formulaCell{sheet: fc.sheet, cell: fc.cell, formula: fc.formula}
+	}
+	for i, name := range names {
+		g.names[i] = name
+		if name.LocalSheetID != nil {
+			g.names[i].LocalSheetID = intPtr(*name.LocalSheetID)
+		}
+	}
+	g.deps, g.dependents = f.getFormulaDependents(ctx, cells)
+	f.formulaGraph = g
+	return g.deps, g.dependents
+}
+
+// formulaDependsOn returns if the cell is in any range referenced by the
+// formula with given dependencies of the formula.
+func formulaDependsOn(deps []formulaDependency, ref cellRef) bool {
+	for _, dep := range deps {
+		if strings.EqualFold(dep.sheet, ref.Sheet) && cellInRange([]int{ref.Col, ref.Row}, dep.coordinates) {
+			return true
+		}
+	}
+	return false
+}
+
+// getFormulaDependents returns the dependencies of each formula cell, and the
+// indexes of the formula cells which depend on each formula cell.
+func (f *File) getFormulaDependents(ctx *calcContext, cells []formulaCell) ([][]formulaDependency, [][]int) {
+	sheetCells, index := make(map[string][]int), make(map[formulaCellKey]int, len(cells))
+	for i, fc := range cells {
+		sheetCells[fc.sheet] = append(sheetCells[fc.sheet], i)
+		index[formulaCellKey{sheet: fc.sheet, col: fc.col, row: fc.row}] = i
+	}
+	deps, dependents := make([][]formulaDependency, len(cells)), make([][]int, len(cells))
+	for i, fc := range cells {
+		deps[i] = f.getFormulaDependencies(ctx, fc.sheet, fc.cell, fc.formula)
+		for _, dep := range deps[i] {
+			x1, y1, x2, y2 := dep.coordinates[0], dep.coordinates[1], dep.coordinates[2], dep.coordinates[3]
+			// lookup the cells in the small range by the index, and scan the
+			// formula cells of the worksheet for the large range
+			if (x2-x1+1)*(y2-y1+1) <= len(sheetCells[dep.sheet]) {
+				for col := x1; col <= x2; col++ {
+					for row := y1; row <= y2; row++ {
+						if j, ok := index[formulaCellKey{sheet: dep.sheet, col: col, row: row}]; ok && i != j {
+							dependents[j] = append(dependents[j], i)
+						}
+					}
+				}
+				continue
+			}
+			for _, j := range sheetCells[dep.sheet] {
+				if i == j || !cellInRange([]int{cells[j].col, cells[j].row}, dep.coordinates) {
+					continue
+				}
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+	return deps, dependents
+}
+
+// sortFormulaCells returns the calculation order of the formula cells by
+// topological sorting on the dependency graph of the formulas. Only the
+// selected formula cells will be sorted if the selected flags are given.
+func sortFormulaCells(dependents [][]int, selected []bool) []int {
+	isSelected := func(i int) bool { return selected == nil || selected[i] }
+	inDegrees := make([]int, len(dependents))
+	for i := range dependents {
+		if !isSelected(i) {
+			continue
+		}
+		for _, j := range dependents[i] {
+			inDegrees[j]++
+		}
+	}
+	var order, queue []int
+	for i := range dependents {
+		if isSelected(i) && inDegrees[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue, order = queue[1:], append(order, i)
+		for _, j := range dependents[i] {
+			if inDegrees[j]--; inDegrees[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	// the formula cells in the circular references
+	for i := range dependents {
+		if isSelected(i) && inDegrees[i] > 0 {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// getFormulaDependencies returns the ranges which referenced by the given
+// formula. The 3-D references and the external references will be ignored.
+func (f *File) getFormulaDependencies(ctx *calcContext, sheet, cell, formula string) []formulaDependency {
+	var deps []formulaDependency
+	formula, _ = f.expandStructuredReferences(ctx, sheet, cell, formula)
+	ps := efp.ExcelParser()
+	for _, token := range ps.Parse(formula) {
+		if token.TSubType != efp.TokenSubTypeRange {
+			continue
+		}
+		ref := token.TValue
+		if refTo := f.getDefinedNameRefTo(ref, sheet); refTo != "" {
+			ref = refTo
+		}
+		for _, r := range strings.Split(ref, ",") {
+			if dep, ok := parseFormulaDependency(sheet, r); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps
+}
+
+// parseFormulaDependency parse the worksheet name and range coordinates by
+// given reference, the second result will be false if the reference can't be
+// parsed.
+func parseFormulaDependency(sheet, ref string) (formulaDependency, bool) {
+	dep := formulaDependency{sheet: sheet}
+	ref = strings.ReplaceAll(strings.TrimSpace(ref), "$", "")
+	if idx := strings.LastIndex(ref, "!"); idx != -1 {
+		if dep.sheet = ref[:idx]; strings.HasPrefix(dep.sheet, "'") && strings.HasSuffix(dep.sheet, "'") {
+			dep.sheet = strings.ReplaceAll(dep.sheet[1:len(dep.sheet)-1], "''", "'")
+		}
+		if strings.ContainsAny(dep.sheet, "[:") {
+			return dep, false
+		}
+		ref = ref[idx+1:]
+	}
+	parts := strings.Split(ref, ":")
+	if len(parts) > 2 {
+		return dep, false
+	}
+	for _, part := range parts {
+		coordinates := make([]int, 4)
+		if col, row, err := CellNameToCoordinates(part); err == nil {
+			coordinates = []int{col, row, col, row}
+		} else if col, err := ColumnNameToNumber(part); err == nil {
+			coordinates = []int{col, 1, col, TotalRows}
+		} else if row, err := strconv.Atoi(part); err == nil {
+			coordinates = []int{1, row, MaxColumns, row}
+		} else {
+			return dep, false
+		}
+		if dep.coordinates == nil {
+			dep.coordinates = coordinates
+			continue
+		}
+		dep.coordinates = []int{
+			int(math.Min(float64(dep.coordinates[0]), float64(coordinates[0]))),
+			int(math.Min(float64(dep.coordinates[1]), float64(coordinates[1]))),
+			int(math.Max(float64(dep.coordinates[2]), float64(coordinates[2]))),
+			int(math.Max(float64(dep.coordinates[3]), float64(coordinates[3]))),
+		}
+	}
+	return dep, true
+}
+
+// isFormulaErrorValue determine if the given value is a formula error value.
+func isFormulaErrorValue(value string) bool {
+	return inStrSlice([]string{
+		formulaErrorDIV, formulaErrorNAME, formulaErrorNA, formulaErrorNUM,
+		formulaErrorVALUE, formulaErrorREF, formulaErrorNULL, formulaErrorSPILL,
+		formulaErrorCALC, formulaErrorGETTINGDATA,
+	}, value, true) != -1
+}
+
+// setFormulaCellResult write the calculated result as the cached value of the
+// formula cell.
+func (f *File) setFormulaCellResult(fc *formulaCell) error {
+	result, err := fc.result, fc.err
+	if err != nil {
+		if !isFormulaErrorValue(err.Error()) {
+			return err
+		}
+		result = newErrorFormulaArg(err.Error(), err.Error())
+	}
+	ws, err := f.workSheetReader(fc.sheet)
+	if err != nil {
+		return err
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	c := &ws.SheetData.Row[fc.rowIdx].C[fc.colIdx]
+	c.IS = nil
+	switch result.Type {
+	case ArgNumber:
+		if c.T, c.V = "", strconv.FormatFloat(result.Number, 'f', -1, 64); result.Boolean {
+			c.T, c.V = "b", "0"
+			if result.Number != 0 {
+				c.V = "1"
+			}
+		}
+	case ArgString:
+		c.T, c.V = "str", result.String
+	case ArgError:
+		c.T, c.V = "e", result.String
+	default:
+		c.T, c.V = "", "0"
+	}
+	return nil
+}
+
+// FreezeSheetValues provides a function to replace all formulas in the
+// worksheet with their values by given worksheet name, the styles of the
+// cells will be kept. The cached value of the formula cell will be used, and
+// the formula without cached value will be calculated. This is useful to
+// distribute the results of the spreadsheet without exposing the formulas.
+// For example, replace the formulas in the worksheet named Sheet1 with their
+// values:
+//
+//	if err := f.FreezeSheetValues("Sheet1"); err != nil {
+//	    fmt.Println(err)
+//	}
+//
+// Note that the calculation error which not a formula error will be returned
+// and the formulas will be kept in this case.
+func (f *File) FreezeSheetValues(sheet string) error {
+	if _, err := f.workSheetReader(sheet); err != nil {
+		return err
+	}
+	return f.freezeValues(sheet)
+}
+
+// FreezeWorkbookValues provides a function to replace all formulas in the
+// worksheets of the workbook with their values, the styles of the cells will
+// be kept. The cached value of the formula cell will be used, and the formula
+// without cached value will be calculated. The formulas which reference
+// other worksheets will be calculated before any formula has been replaced.
+func (f *File) FreezeWorkbookValues() error {
+	return f.freezeValues(f.GetSheetList()...)
+}
+
+// freezeValues provides a function to replace the formulas with their cached
+// or calculated values by given worksheet names.
+func (f *File) freezeValues(sheets ...string) error {
+	cells, err := f.getFormulaCells()
+	if err != nil {
+		return err
+	}
+	var frozen []formulaCell
+	for _, fc := range cells {
+		if inStrSlice(sheets, fc.sheet, false) != -1 {
+			frozen = append(frozen, fc)
+		}
+	}
+	for i := range frozen {
+		fc := &frozen[i]
+		ws, err := f.workSheetReader(fc.sheet)
+		if err != nil {
+			return err
+		}
+		if ws.SheetData.Row[fc.rowIdx].C[fc.colIdx].V != "" {
+			continue
+		}
+		fc.result, fc.err = f.calcEntryCellValue(fc.sheet, fc.cell)
+		fc.result = fc.result.firstElement()
+		if err = f.setFormulaCellResult(fc); err != nil {
+			return err
+		}
+	}
+	for _, fc := range frozen {
+		ws, err := f.workSheetReader(fc.sheet)
+		if err != nil {
+			return err
+		}
+		ws.Lock()
+		c := &ws.SheetData.Row[fc.rowIdx].C[fc.colIdx]
+		if c.F = nil; c.T == "str" {
+			value := bstrUnmarshal(c.V)
+			c.T, c.V, c.XMLSpace = "", "", xml.Attr{}
+			if value != "" {
+				c.T, c.V, err = f.setCellString(value)
+			}
+		}
+		ws.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	for _, sheet := range sheets {
+		if err = f.deleteCalcChain(f.getSheetID(sheet), ""); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// calcEntryCellValue calculate the cell value of the calculation entry by
+// given worksheet name and cell reference. The iterative calculation will be
+// used for the circular references if it has been enabled by the calculation
+// properties of the workbook, the formulas in the circular references will be
+// calculated repeatedly with the results of the previous iteration, until the
+// maximum change of the results less than the maximum change or reach the
+// maximum iterations.
+func (f *File) calcEntryCellValue(sheet, cell string) (result formulaArg, err error) {
+	ctx := &calcContext{
+		entry:      fmt.Sprintf("%s!%s", sheet, cell),
+		iterations: make(map[string]uint),
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return
+	}
+	if wb.CalcPr == nil || !wb.CalcPr.Iterate {
+		return f.calcCellValue(ctx, sheet, cell)
+	}
+	maxIterations, maxChange := 100, 0.001
+	if wb.CalcPr.IterateCount > 0 {
+		maxIterations = wb.CalcPr.IterateCount
+	}
+	if wb.CalcPr.IterateDelta > 0 {
+		maxChange = wb.CalcPr.IterateDelta
+	}
+	ctx.iterate, ctx.values = true, make(map[string]formulaArg)
+	for i := 0; i < maxIterations; i++ {
+		ctx.circular, ctx.stack, ctx.results = false, map[string]bool{ctx.entry: true}, make(map[string]formulaArg)
+		if result, err = f.calcCellValue(ctx, sheet, cell); err != nil || !ctx.circular {
+			return
+		}
+		ctx.results[ctx.entry] = result.firstElement()
+		change := calcIterationChange(ctx.values, ctx.results)
+		if ctx.values = ctx.results; change < maxChange {
+			return
+		}
+	}
 	return
 }
 
+// calcIterationChange returns the maximum change of the formula results
+// between two iterations of the iterative calculation. The change of the
+// non-numeric results will be infinity if the results are different.
+func calcIterationChange(prev, curr map[string]formulaArg) float64 {
+	var change float64
+	for ref, arg := range curr {
+		prevArg, ok := prev[ref]
+		if !ok {
+			return math.Inf(1)
+		}
+		if arg.Type == ArgNumber && prevArg.Type == ArgNumber {
+			change = math.Max(change, math.Abs(arg.Number-prevArg.Number))
+			continue
+		}
+		if arg.Value() != prevArg.Value() {
+			return math.Inf(1)
+		}
+	}
+	return change
+}
+
 // calcCellValue calculate cell value by given context, worksheet name and cell
 // reference.
 func (f *File) calcCellValue(ctx *calcContext, sheet, cell string) (result formulaArg, err error) {
@@ -802,12 +1576,26 @@ func (f *File) calcCellValue(ctx *calcContext, sheet, cell string) (result formu
 	if formula, err = f.GetCellFormula(sheet, cell); err != nil {
 		return
 	}
+	return f.evalFormula(ctx, sheet, cell, formula)
+}
+
+// evalFormula provides a function to evaluate the formula by given calculation
+// context, worksheet name and cell reference, the formula will be evaluated
+// as it is in the given cell.
+func (f *File) evalFormula(ctx *calcContext, sheet, cell, formula string) (result formulaArg, err error) {
+	if formula, err = f.expandStructuredReferences(ctx, sheet, cell, formula); err != nil {
+		result = newErrorFormulaArg(err.Error(), err.Error())
+		return
+	}
 	ps := efp.ExcelParser()
 	tokens := ps.Parse(formula)
 	if tokens == nil {
 		return
 	}
-	result, err = f.evalInfixExp(ctx, sheet, cell, tokens)
+	if result, err = f.evalInfixExp(ctx, sheet, cell, tokens); err == nil && result.Type == ArgEmpty &&
+		f.options != nil && f.options.CalcBlankAsZero {
+		result = newNumberFormulaArg(0)
+	}
 	return
 }
 
@@ -881,6 +1669,7 @@ func newEmptyFormulaArg() formulaArg {
 func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.Token) (formulaArg, error) {
 	var err error
 	opdStack, optStack, opfStack, opfdStack, opftStack, argsStack := NewStack(), NewStack(), NewStack(), NewStack(), NewStack(), NewStack()
+	opfdLenStack := NewStack() // the length of the opfdStack at each function start
 	var inArray, inArrayRow bool
 	for i := 0; i < len(tokens); i++ {
 		token := tokens[i]
@@ -903,6 +1692,7 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 				continue
 			}
 			opfStack.Push(token)
+			opfdLenStack.Push(opfdStack.Len())
 			argsStack.Push(list.New().Init())
 			opftStack.Push(token) // to know which operators belong to a function use the function as a separator
 			continue
@@ -918,12 +1708,8 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 			// current token is args or range, skip next token, order required: parse reference first
 			if token.TSubType == efp.TokenSubTypeRange {
 				if opftStack.Peek().(efp.Token) != opfStack.Peek().(efp.Token) {
-					refTo := f.getDefinedNameRefTo(token.TValue, sheet)
-					if refTo != "" {
-						token.TValue = refTo
-					}
 					// parse reference: must reference at here
-					result, err := f.parseReference(ctx, sheet, token.TValue)
+					result, err := f.parseNameReference(ctx, sheet, token.TValue)
 					if err != nil {
 						return result, err
 					}
@@ -935,11 +1721,7 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 				}
 				if nextToken.TType == efp.TokenTypeArgument || nextToken.TType == efp.TokenTypeFunction {
 					// parse reference: reference or range at here
-					refTo := f.getDefinedNameRefTo(token.TValue, sheet)
-					if refTo != "" {
-						token.TValue = refTo
-					}
-					result, err := f.parseReference(ctx, sheet, token.TValue)
+					result, err := f.parseNameReference(ctx, sheet, token.TValue)
 					if err != nil {
 						return newEmptyFormulaArg(), err
 					}
@@ -971,10 +1753,14 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 			
 			// current token is arg
 			if token.TType == efp.TokenTypeArgument {
+				if isEmptyArgumentToken(tokens, i) {
+					argsStack.Peek().(*list.List).PushBack(newEmptyFormulaArg())
+					continue
+				}
 				for opftStack.Peek().(efp.Token) != opfStack.Peek().(efp.Token) {
 					// calculate trigger
 					topOpt := opftStack.Peek().(efp.Token)
-					if err := calculate(opfdStack, topOpt); err != nil {
+					if err := f.calculate(opfdStack, topOpt); err != nil {
 						argsStack.Peek().(*list.List).PushFront(newErrorFormulaArg(formulaErrorVALUE, err.Error()))
 					}
 					opftStack.Pop()
@@ -997,14 +1783,17 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 				inArray = false
 				continue
 			}
-			if err = f.evalInfixExpFunc(ctx, sheet, cell, token, nextToken, opfStack, opdStack, opftStack, opfdStack, argsStack); err != nil {
+			if isFunctionStopToken(token) && isEmptyArgumentToken(tokens, i) {
+				argsStack.Peek().(*list.List).PushBack(newEmptyFormulaArg())
+			}
+			if err = f.evalInfixExpFunc(ctx, sheet, cell, token, nextToken, opfStack, opdStack, opftStack, opfdStack, opfdLenStack, argsStack); err != nil {
 				return newEmptyFormulaArg(), err
 			}
 		}
 	}
 	for optStack.Len() != 0 {
 		topOpt := optStack.Peek().(efp.Token)
-		if err = calculate(opdStack, topOpt); err != nil {
+		if err = f.calculate(opdStack, topOpt); err != nil {
 			return newEmptyFormulaArg(), err
 		}
 		optStack.Pop()
@@ -1016,21 +1805,26 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 }
 
 // evalInfixExpFunc evaluate formula function in the infix expression.
-func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nextToken efp.Token, opfStack, opdStack, opftStack, opfdStack, argsStack *Stack) error {
+func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nextToken efp.Token, opfStack, opdStack, opftStack, opfdStack, opfdLenStack, argsStack *Stack) error {
 	if !isFunctionStopToken(token) {
 		return nil
 	}
-	prepareEvalInfixExp(opfStack, opftStack, opfdStack, argsStack)
+	f.prepareEvalInfixExp(opfStack, opftStack, opfdStack, opfdLenStack, argsStack)
 	// call formula function to evaluate
-	arg := callFuncByName(&formulaFuncs{f: f, sheet: sheet, cell: cell, ctx: ctx}, strings.NewReplacer(
-		"_xlfn.", "", ".", "dot").Replace(opfStack.Peek().(efp.Token).TValue),
-		[]reflect.Value{reflect.ValueOf(argsStack.Peek().(*list.List))})
+	name := opfStack.Peek().(efp.Token).TValue
+	arg, ok := f.callCalcFunc(name, argsStack.Peek().(*list.List))
+	if !ok {
+		arg = callFuncByName(&formulaFuncs{f: f, sheet: sheet, cell: cell, ctx: ctx}, strings.NewReplacer(
+			"_xlfn.", "", "_xlws.", "", ".", "dot").Replace(name),
+			[]reflect.Value{reflect.ValueOf(argsStack.Peek().(*list.List))})
+	}
 	if arg.Type == ArgError && opfStack.Len() == 1 {
 		return errors.New(arg.Value())
 	}
 	argsStack.Pop()
 	opftStack.Pop() // remove current function separator
 	opfStack.Pop()
+	opfdLenStack.Pop()
 	if opfStack.Len() > 0 { // still in function stack
 		if nextToken.TType == efp.TokenTypeOperatorInfix || (opftStack.Len() > 1 && opfdStack.Len() > 0) {
 			// mathematics calculate in formula function
@@ -1039,41 +1833,76 @@ func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nex
 			argsStack.Peek().(*list.List).PushBack(arg)
 		}
 	} else {
-		val := arg.Value()
-		if arg.Type == ArgMatrix && len(arg.Matrix) > 0 && len(arg.Matrix[0]) > 0 {
-			val = arg.Matrix[0][0].Value()
-		}
-		opdStack.Push(newStringFormulaArg(val))
+		opdStack.Push(arg)
 	}
 	return nil
 }
 
 // prepareEvalInfixExp check the token and stack state for formula function
 // evaluate.
-func prepareEvalInfixExp(opfStack, opftStack, opfdStack, argsStack *Stack) {
+func (f *File) prepareEvalInfixExp(opfStack, opftStack, opfdStack, opfdLenStack, argsStack *Stack) {
 	// current token is function stop
 	for opftStack.Peek().(efp.Token) != opfStack.Peek().(efp.Token) {
 		// calculate trigger
 		topOpt := opftStack.Peek().(efp.Token)
-		if err := calculate(opfdStack, topOpt); err != nil {
+		if err := f.calculate(opfdStack, topOpt); err != nil {
 			argsStack.Peek().(*list.List).PushBack(newErrorFormulaArg(err.Error(), err.Error()))
 			opftStack.Pop()
 			continue
 		}
 		opftStack.Pop()
 	}
-	argument := true
-	if opftStack.Len() > 2 && opfdStack.Len() == 1 {
-		topOpt := opftStack.Pop()
-		if opftStack.Peek().(efp.Token).TType == efp.TokenTypeOperatorInfix {
-			argument = false
+	// push opfd to args, the operands before the function start belong to the
+	// operators out of the function
+	if opfdStack.Len() > opfdLenStack.Peek().(int) {
+		argsStack.Peek().(*list.List).PushBack(opfdStack.Pop().(formulaArg))
+	}
+}
+
+// calcMatrix evaluate the arithmetic operations element-wise when any of the
+// operands is an array, the operand with single row or column will be
+// expanded to the size of the other operand, and the elements out of the
+// range of the smaller array will be #N/A error.
+func calcMatrix(rOpd, lOpd formulaArg, opdStack *Stack, fn func(rOpd, lOpd formulaArg, opdStack *Stack) error) {
+	element := func(mtx [][]formulaArg, row, col int) formulaArg {
+		if len(mtx) == 1 {
+			row = 0
+		}
+		if row < len(mtx) && len(mtx[row]) == 1 {
+			col = 0
+		}
+		if row >= len(mtx) || col >= len(mtx[row]) {
+			return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
 		}
-		opftStack.Push(topOpt)
+		return mtx[row][col]
 	}
-	// push opfd to args
-	if argument && opfdStack.Len() > 0 {
-		argsStack.Peek().(*list.List).PushBack(opfdStack.Pop().(formulaArg))
+	rMtx, lMtx := rOpd.toMatrix(), lOpd.toMatrix()
+	rows, cols := len(rMtx), len(rMtx[0])
+	if len(lMtx) > rows {
+		rows = len(lMtx)
+	}
+	if len(lMtx[0]) > cols {
+		cols = len(lMtx[0])
+	}
+	mtx := make([][]formulaArg, rows)
+	for row := range mtx {
+		mtx[row] = make([]formulaArg, cols)
+		for col := range mtx[row] {
+			r, l, stack := element(rMtx, row, col), element(lMtx, row, col), NewStack()
+			if mtx[row][col] = l; l.Type == ArgError {
+				continue
+			}
+			if mtx[row][col] = r; r.Type == ArgError {
+				continue
+			}
+			if err := fn(r, l, stack); err != nil || stack.Empty() {
+				mtx[row][col] = newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+				continue
+			}
+			mtx[row][col] = stack.Pop().(formulaArg)
+		}
 	}
+	opdStack.Push(newMatrixFormulaArg(mtx))
 }
 
 // calcPow evaluate exponentiation arithmetic operations.
@@ -1235,13 +2064,40 @@ func calcDiv(rOpd, lOpd formulaArg, opdStack *Stack) error {
 	return nil
 }
 
+// calcOperands returns the operator evaluate function which converts the
+// operands by the calculation options of the workbook before evaluating. The
+// text operands of the arithmetic operators which can't be converted to
+// numbers will be zero with the CalcTextAsZero option, and the blank operands
+// compared with numbers will be zero with the CalcBlankAsZero option.
+func (f *File) calcOperands(arithmetic bool, fn func(rOpd, lOpd formulaArg, opdStack *Stack) error) func(rOpd, lOpd formulaArg, opdStack *Stack) error {
+	if f.options == nil || (!f.options.CalcTextAsZero && !f.options.CalcBlankAsZero) {
+		return fn
+	}
+	convert := func(opd, other formulaArg) formulaArg {
+		if arithmetic && f.options.CalcTextAsZero && opd.Type == ArgString && opd.ToNumber().Type != ArgNumber {
+			return newNumberFormulaArg(0)
+		}
+		if !arithmetic && f.options.CalcBlankAsZero && opd.Type == ArgEmpty && other.Type == ArgNumber {
+			return newNumberFormulaArg(0)
+		}
+		return opd
+	}
+	return func(rOpd, lOpd formulaArg, opdStack *Stack) error {
+		return fn(convert(rOpd, lOpd), convert(lOpd, rOpd), opdStack)
+	}
+}
+
 // calculate evaluate basic arithmetic operations.
-func calculate(opdStack *Stack, opt efp.Token) error {
+func (f *File) calculate(opdStack *Stack, opt efp.Token) error {
 	if opt.TValue == "-" && opt.TType == efp.TokenTypeOperatorPrefix {
 		if opdStack.Len() < 1 {
 			return ErrInvalidFormula
 		}
 		opd := opdStack.Pop().(formulaArg)
+		if opd.Type == ArgMatrix {
+			calcMatrix(opd, newNumberFormulaArg(0), opdStack, f.calcOperands(true, calcSubtract))
+			return nil
+		}
 		opdStack.Push(newNumberFormulaArg(0 - opd.ToNumber().Number))
 	}
 	if opt.TValue == "-" && opt.TType == efp.TokenTypeOperatorInfix {
@@ -1250,21 +2106,25 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 		}
 		rOpd := opdStack.Pop().(formulaArg)
 		lOpd := opdStack.Pop().(formulaArg)
-		if err := calcSubtract(rOpd, lOpd, opdStack); err != nil {
+		if rOpd.Type == ArgMatrix || lOpd.Type == ArgMatrix {
+			calcMatrix(rOpd, lOpd, opdStack, f.calcOperands(true, calcSubtract))
+			return nil
+		}
+		if err := f.calcOperands(true, calcSubtract)(rOpd, lOpd, opdStack); err != nil {
 			return err
 		}
 	}
 	tokenCalcFunc := map[string]func(rOpd, lOpd formulaArg, opdStack *Stack) error{
-		"^":  calcPow,
-		"*":  calcMultiply,
-		"/":  calcDiv,
-		"+":  calcAdd,
-		"=":  calcEq,
-		"<>": calcNEq,
-		"<":  calcL,
-		"<=": calcLe,
-		">":  calcG,
-		">=": calcGe,
+		"^":  f.calcOperands(true, calcPow),
+		"*":  f.calcOperands(true, calcMultiply),
+		"/":  f.calcOperands(true, calcDiv),
+		"+":  f.calcOperands(true, calcAdd),
+		"=":  f.calcOperands(false, calcEq),
+		"<>": f.calcOperands(false, calcNEq),
+		"<":  f.calcOperands(false, calcL),
+		"<=": f.calcOperands(false, calcLe),
+		">":  f.calcOperands(false, calcG),
+		">=": f.calcOperands(false, calcGe),
 		"&":  calcSplice,
 	}
 	fn, ok := tokenCalcFunc[opt.TValue]
@@ -1274,6 +2134,10 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 		}
 		rOpd := opdStack.Pop().(formulaArg)
 		lOpd := opdStack.Pop().(formulaArg)
+		if rOpd.Type == ArgMatrix || lOpd.Type == ArgMatrix {
+			calcMatrix(rOpd, lOpd, opdStack, fn)
+			return nil
+		}
 		if rOpd.Type == ArgError {
 			return errors.New(rOpd.Value())
 		}
@@ -1302,7 +2166,7 @@ func (f *File) parseOperatorPrefixToken(optStack, opdStack *Stack, token efp.Tok
 	}
 	for tokenPriority <= topOptPriority {
 		optStack.Pop()
-		if err = calculate(opdStack, topOpt); err != nil {
+		if err = f.calculate(opdStack, topOpt); err != nil {
 			return
 		}
 		if optStack.Len() > 0 {
@@ -1331,6 +2195,13 @@ func isBeginParenthesesToken(token efp.Token) bool {
 	return token.TType == efp.TokenTypeSubexpression && token.TSubType == efp.TokenSubTypeStart
 }
 
+// isEmptyArgumentToken determine if the argument separator or function stop
+// token at the given index follows an omitted argument, such as the second
+// argument of the formula SORT(A1:A3,,-1).
+func isEmptyArgumentToken(tokens []efp.Token, i int) bool {
+	return i > 0 && (tokens[i-1].TType == efp.TokenTypeArgument || (tokens[i].TType == efp.TokenTypeArgument && isFunctionStartToken(tokens[i-1])))
+}
+
 // isEndParenthesesToken determine if the token is end parentheses: ).
 func isEndParenthesesToken(token efp.Token) bool {
 	return token.TType == efp.TokenTypeSubexpression && token.TSubType == efp.TokenSubTypeStop
@@ -1370,122 +2241,652 @@ func formulaArgToToken(arg formulaArg) efp.Token {
 		}
 		return efp.Token{TValue: arg.Value(), TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeNumber}
 	default:
-		return efp.Token{TValue: arg.Value(), TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeText}
+		return efp.Token{TValue: arg.Value(), TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeText}
+	}
+}
+
+// parseToken parse basic arithmetic operator priority and evaluate based on
+// operators and operands.
+func (f *File) parseToken(ctx *calcContext, sheet string, token efp.Token, opdStack, optStack *Stack) error {
+	// parse reference: must reference at here
+	if token.TSubType == efp.TokenSubTypeRange {
+		result, err := f.parseNameReference(ctx, sheet, token.TValue)
+		if err != nil {
+			return errors.New(formulaErrorNAME)
+		}
+		if result.Type == ArgError {
+			return errors.New(result.Error)
+		}
+		if result.Type == ArgMatrix || result.Type == ArgEmpty {
+			opdStack.Push(result)
+			return nil
+		}
+		token = formulaArgToToken(result)
+	}
+	if isOperatorPrefixToken(token) {
+		if err := f.parseOperatorPrefixToken(optStack, opdStack, token); err != nil {
+			return err
+		}
+	}
+	if isBeginParenthesesToken(token) { // (
+		optStack.Push(token)
+	}
+	if isEndParenthesesToken(token) { // )
+		for !isBeginParenthesesToken(optStack.Peek().(efp.Token)) { // != (
+			topOpt := optStack.Peek().(efp.Token)
+			if err := f.calculate(opdStack, topOpt); err != nil {
+				return err
+			}
+			optStack.Pop()
+		}
+		optStack.Pop()
+	}
+	if token.TType == efp.TokenTypeOperatorPostfix && !opdStack.Empty() {
+		topOpd := opdStack.Pop().(formulaArg)
+		opdStack.Push(newNumberFormulaArg(topOpd.Number / 100))
+	}
+	// opd
+	if isOperand(token) {
+		opdStack.Push(tokenToFormulaArg(token))
+	}
+	return nil
+}
+
+// parseReference parse reference and extract values by given reference
+// characters and default sheet name.
+func (f *File) parseReference(ctx *calcContext, sheet, reference string) (arg formulaArg, err error) {
+	reference = strings.ReplaceAll(reference, "$", "")
+	var ok bool
+	if arg, ok, err = f.parseSheetsReference(ctx, reference); ok {
+		return
+	}
+	refs, cellRanges, cellRefs := list.New(), list.New(), list.New()
+	for _, ref := range strings.Split(reference, ":") {
+		tokens := strings.Split(ref, "!")
+		cr := cellRef{}
+		if len(tokens) == 2 { // have a worksheet name
+			cr.Sheet = tokens[0]
+			if idx, _ := f.GetSheetIndex(cr.Sheet); idx == -1 {
+				arg = newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+				return
+			}
+			// cast to cell reference
+			if cr.Col, cr.Row, err = CellNameToCoordinates(tokens[1]); err != nil {
+				// cast to column
+				if cr.Col, err = ColumnNameToNumber(tokens[1]); err != nil {
+					// cast to row
+					if cr.Row, err = strconv.Atoi(tokens[1]); err != nil {
+						err = newInvalidColumnNameError(tokens[1])
+						return
+					}
+					cr.Col = MaxColumns
+				}
+			}
+			if refs.Len() > 0 {
+				e := refs.Back()
+				cellRefs.PushBack(e.Value.(cellRef))
+				refs.Remove(e)
+			}
+			refs.PushBack(cr)
+			continue
+		}
+		// cast to cell reference
+		if cr.Col, cr.Row, err = CellNameToCoordinates(tokens[0]); err != nil {
+			// cast to column
+			if cr.Col, err = ColumnNameToNumber(tokens[0]); err != nil {
+				// cast to row
+				if cr.Row, err = strconv.Atoi(tokens[0]); err != nil {
+					err = newInvalidColumnNameError(tokens[0])
+					return
+				}
+				cr.Col = MaxColumns
+			}
+			cellRanges.PushBack(cellRange{
+				From: cellRef{Sheet: sheet, Col: cr.Col, Row: 1},
+				To:   cellRef{Sheet: sheet, Col: cr.Col, Row: TotalRows},
+			})
+			cellRefs.Init()
+			arg, err = f.rangeResolver(ctx, cellRefs, cellRanges)
+			return
+		}
+		e := refs.Back()
+		if e == nil {
+			cr.Sheet = sheet
+			refs.PushBack(cr)
+			continue
+		}
+		cellRanges.PushBack(cellRange{
+			From: e.Value.(cellRef),
+			To:   cr,
+		})
+		refs.Remove(e)
+	}
+	if refs.Len() > 0 {
+		e := refs.Back()
+		cellRefs.PushBack(e.Value.(cellRef))
+		refs.Remove(e)
+	}
+	arg, err = f.rangeResolver(ctx, cellRefs, cellRanges)
+	return
+}
+
+// parseNameReference parse the reference by given worksheet name and
+// reference, the reference will be resolved as the defined name with the
+// worksheet scope taking precedence over the workbook scope if it exists. The
+// defined name can refer to a reference, a multi-area range such as
+// "Sheet1!$A$1:$A$2,Sheet1!$C$1:$C$2", a constant or a formula.
+func (f *File) parseNameReference(ctx *calcContext, sheet, reference string) (formulaArg, error) {
+	refTo := f.getDefinedNameRefTo(reference, sheet)
+	if refTo == "" {
+		if !strings.Contains(reference, "[") && isCalcRangeReference(reference) {
+			return f.parseReference(ctx, sheet, reference)
+		}
+		tbl, ok := findCalcTable(f.getCalcTables(ctx), reference, sheet, "")
+		if !ok {
+			return f.parseReference(ctx, sheet, reference)
+		}
+		refTo = tbl.name + "[]"
+	}
+	refTo, err := f.expandStructuredReferences(ctx, sheet, "", refTo)
+	if err != nil {
+		return newErrorFormulaArg(err.Error(), err.Error()), nil
+	}
+	ps := efp.ExcelParser()
+	tokens := ps.Parse(refTo)
+	var areas []string
+	for i, token := range tokens {
+		if i%2 == 1 && token.TSubType == efp.TokenSubTypeUnion {
+			continue
+		}
+		if i%2 == 0 && token.TSubType == efp.TokenSubTypeRange {
+			areas = append(areas, token.TValue)
+			continue
+		}
+		areas = nil
+		break
+	}
+	if len(areas) == 0 || len(tokens)%2 == 0 {
+		ctx.Lock()
+		if ctx.names == nil {
+			ctx.names = make(map[string]bool)
+		}
+		if ctx.names[reference] {
+			ctx.Unlock()
+			return newErrorFormulaArg(formulaErrorNAME, formulaErrorNAME), nil
+		}
+		ctx.names[reference] = true
+		ctx.Unlock()
+		defer func() {
+			ctx.Lock()
+			delete(ctx.names, reference)
+			ctx.Unlock()
+		}()
+		return f.evalInfixExp(ctx, sheet, "", tokens)
+	}
+	if len(areas) == 1 {
+		return f.parseReference(ctx, sheet, areas[0])
+	}
+	// merge the values of the multi-area range into one matrix
+	var matrix [][]formulaArg
+	for _, area := range areas {
+		arg, err := f.parseReference(ctx, sheet, area)
+		if err != nil || arg.Type == ArgError {
+			return arg, err
+		}
+		matrix = append(matrix, arg.ToList())
+	}
+	return newMatrixFormulaArg(matrix), nil
+}
+
+// isCalcRangeReference returns if the given reference is a cell reference or
+// a range reference such as "A1", "Sheet1!$A$1:$B$2", "A:B" and "1:2".
+func isCalcRangeReference(reference string) bool {
+	if i := strings.LastIndex(reference, "!"); i != -1 {
+		reference = reference[i+1:]
+	}
+	parts := strings.Split(strings.ReplaceAll(reference, "$", ""), ":")
+	if len(parts) > 2 {
+		return false
+	}
+	for _, part := range parts {
+		if _, _, err := CellNameToCoordinates(part); err == nil {
+			continue
+		}
+		if len(parts) == 1 {
+			return false
+		}
+		if _, err := ColumnNameToNumber(part); err == nil {
+			continue
+		}
+		if row, err := strconv.Atoi(part); err != nil || row < 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// calcTable defines the table used for resolving the structured references
+// in the formula calculation.
+type calcTable struct {
+	name        string
+	sheet       string
+	columns     []string
+	coordinates []int
+	totalsRows  int
+}
+
+// getCalcTables returns the tables in the workbook, the tables will be read
+// by the relationships of the worksheets without loading the worksheets, and
+// cached in the given calculation context.
+func (f *File) getCalcTables(ctx *calcContext) []calcTable {
+	if ctx != nil {
+		ctx.Lock()
+		defer ctx.Unlock()
+		if ctx.tables != nil {
+			return ctx.tables
+		}
+	}
+	tables := []calcTable{}
+	for _, sheet := range f.GetSheetList() {
+		sheetTables, _ := f.getSheetTablesByRels(sheet)
+		for _, t := range sheetTables {
+			coordinates, err := rangeRefToCoordinates(t.Ref)
+			if err != nil {
+				continue
+			}
+			_ = sortCoordinates(coordinates)
+			tbl := calcTable{name: t.DisplayName, sheet: sheet, coordinates: coordinates, totalsRows: t.TotalsRowCount}
+			if tbl.name == "" {
+				tbl.name = t.Name
+			}
+			if t.TableColumns != nil {
+				for _, col := range t.TableColumns.TableColumn {
+					tbl.columns = append(tbl.columns, col.Name)
+				}
+			}
+			tables = append(tables, tbl)
+		}
+	}
+	if ctx != nil {
+		ctx.tables = tables
+	}
+	return tables
+}
+
+// expandStructuredReferences returns the formula with the structured
+// references of the tables such as Table1[Amount] and
+// Table1[[#This Row],[Amount]] replaced by the cell references. The current
+// row of the table and the table of the structured references without table
+// name such as [@Amount] will be resolved by given worksheet name and cell
+// reference of the formula.
+func (f *File) expandStructuredReferences(ctx *calcContext, sheet, cell, formula string) (string, error) {
+	if !strings.Contains(formula, "[") {
+		return formula, nil
+	}
+	tables := f.getCalcTables(ctx)
+	if len(tables) == 0 {
+		return formula, nil
+	}
+	var (
+		buf               strings.Builder
+		inString, inQuote bool
+		last              int
+		runes             = []rune(formula)
+	)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case inString:
+			inString = r != '"'
+		case inQuote:
+			inQuote = r != '\''
+		case r == '"':
+			inString = true
+		case r == '\'':
+			inQuote = true
+		case r == '[':
+			end := matchStructuredReferenceBracket(runes, i)
+			if end == -1 {
+				return formula, nil
+			}
+			start := i
+			for start > 0 && isTableNameRune(runes[start-1]) {
+				start--
+			}
+			tbl, ok := findCalcTable(tables, string(runes[start:i]), sheet, cell)
+			if !ok || (start == i && end+1 < len(runes) && (isTableNameRune(runes[end+1]) || runes[end+1] == '\'')) {
+				i = end
+				continue
+			}
+			ref, err := tbl.resolve(string(runes[i+1:end]), sheet, cell)
+			if err != nil {
+				return formula, err
+			}
+			buf.WriteString(string(runes[last:start]))
+			buf.WriteString(ref)
+			last, i = end+1, end
+		}
+	}
+	buf.WriteString(string(runes[last:]))
+	return buf.String(), nil
+}
+
+// isTableNameRune returns if the given character can be used in the name of
+// the table.
+func isTableNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '\\'
+}
+
+// matchStructuredReferenceBracket returns the index of the close bracket
+// which matches the open bracket at the given index, the special characters
+// in the structured references are escaped by the single quotation mark. The
+// result will be -1 if there is no matched close bracket.
+func matchStructuredReferenceBracket(runes []rune, start int) int {
+	var depth int
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '\'':
+			i++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findCalcTable returns the table by given table name, the table which
+// contains the given cell will be returned if the table name is empty.
+func findCalcTable(tables []calcTable, name, sheet, cell string) (calcTable, bool) {
+	if name != "" {
+		for _, tbl := range tables {
+			if strings.EqualFold(tbl.name, name) {
+				return tbl, true
+			}
+		}
+		return calcTable{}, false
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return calcTable{}, false
+	}
+	for _, tbl := range tables {
+		if tbl.sheet == sheet && cellInRange([]int{col, row}, tbl.coordinates) {
+			return tbl, true
+		}
+	}
+	return calcTable{}, false
+}
+
+// splitStructuredReference splits the structured reference by given
+// separator outside the brackets.
+func splitStructuredReference(ref string, sep rune) []string {
+	var (
+		parts       []string
+		depth, last int
+		runes       = []rune(ref)
+	)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '\'':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, string(runes[last:i]))
+				last = i + 1
+			}
+		}
+	}
+	return append(parts, string(runes[last:]))
+}
+
+// unescapeStructuredReference returns the column name or special item
+// specifier of the structured reference without the enclosing brackets and
+// the escape characters.
+func unescapeStructuredReference(item string) string {
+	item = strings.TrimSpace(item)
+	if strings.HasPrefix(item, "[") && strings.HasSuffix(item, "]") {
+		item = item[1 : len(item)-1]
+	}
+	var buf strings.Builder
+	runes := []rune(item)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\'' && i+1 < len(runes) {
+			i++
+		}
+		buf.WriteRune(runes[i])
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// resolve returns the cell reference of the structured reference by given
+// content in the brackets of the structured reference, and the worksheet
+// name and cell reference of the formula.
+func (tbl calcTable) resolve(content, sheet, cell string) (string, error) {
+	var specifiers, columns []string
+	parseColumns := func(item string) {
+		for _, col := range splitStructuredReference(item, ':') {
+			columns = append(columns, unescapeStructuredReference(col))
+		}
+	}
+	switch content = strings.TrimSpace(content); {
+	case content == "":
+	case strings.HasPrefix(content, "@"):
+		specifiers = append(specifiers, "#This Row")
+		if item := strings.TrimSpace(content[1:]); item != "" {
+			parseColumns(item)
+		}
+	case strings.HasPrefix(content, "["):
+		for _, item := range splitStructuredReference(content, ',') {
+			if name := unescapeStructuredReference(item); strings.HasPrefix(name, "#") {
+				specifiers = append(specifiers, name)
+				continue
+			}
+			parseColumns(item)
+		}
+	case strings.HasPrefix(content, "#"):
+		specifiers = append(specifiers, unescapeStructuredReference(content))
+	default:
+		columns = append(columns, unescapeStructuredReference(content))
+	}
+	x1, y1, x2, y2 := tbl.coordinates[0], tbl.coordinates[1], tbl.coordinates[2], tbl.coordinates[3]
+	fromRow, toRow := y1+1, y2-tbl.totalsRows
+	if len(specifiers) > 0 {
+		fromRow, toRow = y2, y1
+	}
+	for _, specifier := range specifiers {
+		var from, to int
+		switch strings.ToLower(specifier) {
+		case "#all":
+			from, to = y1, y2
+		case "#data":
+			from, to = y1+1, y2-tbl.totalsRows
+		case "#headers":
+			from, to = y1, y1
+		case "#totals":
+			if tbl.totalsRows == 0 {
+				return "", errors.New(formulaErrorREF)
+			}
+			from, to = y2-tbl.totalsRows+1, y2
+		case "#this row":
+			_, row, err := CellNameToCoordinates(cell)
+			if err != nil || sheet != tbl.sheet || row <= y1 || row > y2-tbl.totalsRows {
+				return "", errors.New(formulaErrorVALUE)
+			}
+			from, to = row, row
+		default:
+			return "", errors.New(formulaErrorREF)
+		}
+		fromRow, toRow = int(math.Min(float64(fromRow), float64(from))), int(math.Max(float64(toRow), float64(to)))
+	}
+	fromCol, toCol := x1, x2
+	if len(columns) > 0 {
+		fromCol, toCol = x2, x1
+	}
+	for _, name := range columns {
+		idx := inStrSlice(tbl.columns, name, false)
+		if idx == -1 {
+			return "", errors.New(formulaErrorREF)
+		}
+		fromCol, toCol = int(math.Min(float64(fromCol), float64(x1+idx))), int(math.Max(float64(toCol), float64(x1+idx)))
 	}
+	if fromRow > toRow {
+		return "", errors.New(formulaErrorREF)
+	}
+	from, _ := CoordinatesToCellName(fromCol, fromRow, true)
+	to, _ := CoordinatesToCellName(toCol, toRow, true)
+	ref := quoteSheetName(tbl.sheet) + "!" + from
+	if from != to {
+		ref += ":" + to
+	}
+	return ref, nil
 }
 
-// parseToken parse basic arithmetic operator priority and evaluate based on
-// operators and operands.
-func (f *File) parseToken(ctx *calcContext, sheet string, token efp.Token, opdStack, optStack *Stack) error {
-	// parse reference: must reference at here
-	if token.TSubType == efp.TokenSubTypeRange {
-		refTo := f.getDefinedNameRefTo(token.TValue, sheet)
-		if refTo != "" {
-			token.TValue = refTo
+// parseSheetsReference parse the external workbook reference such as
+// [Book2.xlsx]Sheet1!A1 and the 3-D reference across the worksheets such as
+// Sheet1:Sheet3!A1:B2 by given reference characters. The values of the 3-D
+// reference will be stacked into a single matrix by the order of the
+// worksheets in the workbook. The ok result will be false if the reference is
+// neither of them.
+func (f *File) parseSheetsReference(ctx *calcContext, reference string) (arg formulaArg, ok bool, err error) {
+	idx := strings.LastIndex(reference, "!")
+	if idx == -1 || strings.Contains(reference[:idx], "!") {
+		return
+	}
+	book, fromSheet, toSheet := splitSheetsReference(reference[:idx])
+	if book == "" && !strings.Contains(reference[:idx], ":") {
+		return
+	}
+	ok, file := true, f
+	if book != "" {
+		if file, err = f.getExternalWorkbook(book); err != nil {
+			return
 		}
-		result, err := f.parseReference(ctx, sheet, token.TValue)
-		if err != nil {
-			return errors.New(formulaErrorNAME)
+		if file == nil {
+			arg = newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+			return
 		}
-		token = formulaArgToToken(result)
+		ctx = &calcContext{entry: reference, iterations: make(map[string]uint)}
 	}
-	if isOperatorPrefixToken(token) {
-		if err := f.parseOperatorPrefixToken(optStack, opdStack, token); err != nil {
-			return err
-		}
+	sheets := file.GetSheetList()
+	from, to := inStrSlice(sheets, fromSheet, false), inStrSlice(sheets, toSheet, false)
+	if from == -1 || to == -1 {
+		arg = newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+		return
 	}
-	if isBeginParenthesesToken(token) { // (
-		optStack.Push(token)
+	if from > to {
+		from, to = to, from
 	}
-	if isEndParenthesesToken(token) { // )
-		for !isBeginParenthesesToken(optStack.Peek().(efp.Token)) { // != (
-			topOpt := optStack.Peek().(efp.Token)
-			if err := calculate(opdStack, topOpt); err != nil {
-				return err
-			}
-			optStack.Pop()
+	if from == to {
+		arg, err = file.parseReference(ctx, sheets[from], sheets[from]+"!"+reference[idx+1:])
+		return
+	}
+	var mtx [][]formulaArg
+	for _, sheet := range sheets[from : to+1] {
+		var val formulaArg
+		if val, err = file.parseReference(ctx, sheet, sheet+"!"+reference[idx+1:]); err != nil {
+			return
 		}
-		optStack.Pop()
+		if val.Type == ArgMatrix {
+			mtx = append(mtx, val.Matrix...)
+			continue
+		}
+		mtx = append(mtx, []formulaArg{val})
 	}
-	if token.TType == efp.TokenTypeOperatorPostfix && !opdStack.Empty() {
-		topOpd := opdStack.Pop().(formulaArg)
-		opdStack.Push(newNumberFormulaArg(topOpd.Number / 100))
+	arg = newMatrixFormulaArg(mtx)
+	arg.cellRefs, arg.cellRanges = list.New(), list.New()
+	return
+}
+
+// splitSheetsReference split the worksheets part of the reference into the
+// external workbook name and the first and last worksheet names. For example,
+// C:\dir\[Book2.xlsx]Sheet1:Sheet3 will be split into C:\dir\Book2.xlsx,
+// Sheet1 and Sheet3.
+func splitSheetsReference(ref string) (book, fromSheet, toSheet string) {
+	if end := strings.LastIndex(ref, "]"); end != -1 {
+		if start := strings.LastIndex(ref[:end], "["); start != -1 {
+			book, ref = ref[:start]+ref[start+1:end], ref[end+1:]
+		}
 	}
-	// opd
-	if isOperand(token) {
-		opdStack.Push(tokenToFormulaArg(token))
+	fromSheet, toSheet = ref, ref
+	if idx := strings.Index(ref, ":"); idx != -1 {
+		fromSheet, toSheet = ref[:idx], ref[idx+1:]
 	}
-	return nil
+	return
 }
 
-// parseReference parse reference and extract values by given reference
-// characters and default sheet name.
-func (f *File) parseReference(ctx *calcContext, sheet, reference string) (arg formulaArg, err error) {
-	reference = strings.ReplaceAll(reference, "$", "")
-	refs, cellRanges, cellRefs := list.New(), list.New(), list.New()
-	for _, ref := range strings.Split(reference, ":") {
-		tokens := strings.Split(ref, "!")
-		cr := cellRef{}
-		if len(tokens) == 2 { // have a worksheet name
-			cr.Sheet = tokens[0]
-			// cast to cell reference
-			if cr.Col, cr.Row, err = CellNameToCoordinates(tokens[1]); err != nil {
-				// cast to column
-				if cr.Col, err = ColumnNameToNumber(tokens[1]); err != nil {
-					// cast to row
-					if cr.Row, err = strconv.Atoi(tokens[1]); err != nil {
-						err = newInvalidColumnNameError(tokens[1])
-						return
-					}
-					cr.Col = MaxColumns
-				}
-			}
-			if refs.Len() > 0 {
-				e := refs.Back()
-				cellRefs.PushBack(e.Value.(cellRef))
-				refs.Remove(e)
-			}
-			refs.PushBack(cr)
-			continue
+// getExternalWorkbook provides a function to get the external workbook by
+// given workbook name or one-based index of the external workbooks. The
+// external workbook reader function will be used to open the workbook if it
+// has been set, otherwise the workbook with the cached values of the external
+// workbook will be used. The opened workbooks will be cached in the
+// spreadsheet and closed with it. It returns nil if the external workbook not
+// found.
+func (f *File) getExternalWorkbook(book string) (*File, error) {
+	if file, ok := f.externalBooks.Load(book); ok {
+		return file.(*File), nil
+	}
+	books, err := f.getExternalBooks()
+	if err != nil {
+		return nil, err
+	}
+	target, link := book, (*xlsxExternalLink)(nil)
+	if idx, err := strconv.Atoi(book); err == nil {
+		if idx < 1 || idx > len(books) {
+			return nil, nil
 		}
-		// cast to cell reference
-		if cr.Col, cr.Row, err = CellNameToCoordinates(tokens[0]); err != nil {
-			// cast to column
-			if cr.Col, err = ColumnNameToNumber(tokens[0]); err != nil {
-				// cast to row
-				if cr.Row, err = strconv.Atoi(tokens[0]); err != nil {
-					err = newInvalidColumnNameError(tokens[0])
-					return
-				}
-				cr.Col = MaxColumns
+		target, link = books[idx-1].target, books[idx-1].link
+	} else {
+		name := book[strings.LastIndexAny(book, "/\\")+1:]
+		for _, b := range books {
+			if strings.EqualFold(b.target[strings.LastIndexAny(b.target, "/\\")+1:], name) {
+				link = b.link
+				break
 			}
-			cellRanges.PushBack(cellRange{
-				From: cellRef{Sheet: sheet, Col: cr.Col, Row: 1},
-				To:   cellRef{Sheet: sheet, Col: cr.Col, Row: TotalRows},
-			})
-			cellRefs.Init()
-			arg, err = f.rangeResolver(ctx, cellRefs, cellRanges)
-			return
 		}
-		e := refs.Back()
-		if e == nil {
-			cr.Sheet = sheet
-			refs.PushBack(cr)
-			continue
+	}
+	var file *File
+	if f.ExternalReader != nil {
+		if file, err = f.ExternalReader(target); err != nil {
+			return nil, err
+		}
+	} else if link != nil {
+		if file, err = newExternalBookFile(link); err != nil {
+			return nil, err
 		}
-		cellRanges.PushBack(cellRange{
-			From: e.Value.(cellRef),
-			To:   cr,
-		})
-		refs.Remove(e)
 	}
-	if refs.Len() > 0 {
-		e := refs.Back()
-		cellRefs.PushBack(e.Value.(cellRef))
-		refs.Remove(e)
+	if file == nil {
+		return nil, nil
 	}
-	arg, err = f.rangeResolver(ctx, cellRefs, cellRanges)
-	return
+	if cached, loaded := f.externalBooks.LoadOrStore(book, file); loaded {
+		if file != cached.(*File) {
+			_ = file.Close()
+		}
+		return cached.(*File), nil
+	}
+	return file, nil
+}
+
+// closeExternalWorkbooks provides a function to close the cached external
+// workbooks which have been opened for the calculation.
+func (f *File) closeExternalWorkbooks() error {
+	var err error
+	f.externalBooks.Range(func(k, v interface{}) bool {
+		if file := v.(*File); file != f {
+			if err = file.Close(); err != nil {
+				return false
+			}
+		}
+		f.externalBooks.Delete(k)
+		return true
+	})
+	return err
 }
 
 // prepareValueRange prepare value range.
@@ -1528,19 +2929,34 @@ func (f *File) cellResolver(ctx *calcContext, sheet, cell string) (formulaArg, e
 		err   error
 	)
 	ref := fmt.Sprintf("%s!%s", sheet, cell)
-	if formula, _ := f.GetCellFormula(sheet, cell); len(formula) != 0 {
-		ctx.Lock()
-		if ctx.entry != ref && ctx.iterations[ref] <= f.options.MaxCalcIterations {
-			ctx.iterations[ref]++
+	formula, _ := f.GetCellFormula(sheet, cell)
+	if len(formula) != 0 {
+		if ctx.iterate {
+			if arg, ok := f.iterativeCellResolver(ctx, sheet, cell); ok {
+				return arg, nil
+			}
+		} else {
+			ctx.Lock()
+			if arg, ok := ctx.results[ref]; ok { // the formula has been calculated in the workbook recalculation
+				ctx.Unlock()
+				return arg, nil
+			}
+			if ctx.entry != ref && ctx.iterations[ref] <= f.options.MaxCalcIterations &&
+				(ctx.recalc == nil || ctx.recalc[ref]) {
+				ctx.iterations[ref]++
+				ctx.Unlock()
+				arg, _ = f.calcCellValue(ctx, sheet, cell)
+				return arg.firstElement(), nil
+			}
 			ctx.Unlock()
-			arg, _ = f.calcCellValue(ctx, sheet, cell)
-			return arg, nil
 		}
-		ctx.Unlock()
 	}
 	if value, err = f.GetCellValue(sheet, cell, Options{RawCellValue: true}); err != nil {
 		return arg, err
 	}
+	if len(formula) != 0 && value == "" { // the formula in the circular reference has no cached value
+		return newEmptyFormulaArg(), err
+	}
 	arg = newStringFormulaArg(value)
 	cellType, _ := f.GetCellType(sheet, cell)
 	switch cellType {
@@ -1551,11 +2967,44 @@ func (f *File) cellResolver(ctx *calcContext, sheet, cell string) (formulaArg, e
 			return newEmptyFormulaArg(), err
 		}
 		return arg.ToNumber(), err
+	case CellTypeError:
+		return newErrorFormulaArg(value, value), err
 	default:
 		return arg, err
 	}
 }
 
+// iterativeCellResolver calculate the formula cell value in the iterative
+// calculation by given context, worksheet name and cell reference. The result
+// of each formula cell will be calculated once in an iteration, and the
+// result of the previous iteration will be used for the circular reference.
+// The ok result will be false if the cell in the circular reference has no
+// result of the previous iteration, the stored cell value should be used in
+// this case.
+func (f *File) iterativeCellResolver(ctx *calcContext, sheet, cell string) (formulaArg, bool) {
+	ref := fmt.Sprintf("%s!%s", sheet, cell)
+	ctx.Lock()
+	if ctx.stack[ref] {
+		ctx.circular = true
+		arg, ok := ctx.values[ref]
+		ctx.Unlock()
+		return arg, ok
+	}
+	if arg, ok := ctx.results[ref]; ok {
+		ctx.Unlock()
+		return arg, ok
+	}
+	ctx.stack[ref] = true
+	ctx.Unlock()
+	arg, _ := f.calcCellValue(ctx, sheet, cell)
+	arg = arg.firstElement()
+	ctx.Lock()
+	delete(ctx.stack, ref)
+	ctx.results[ref] = arg
+	ctx.Unlock()
+	return arg, true
+}
+
 // rangeResolver extract value as string from given reference and range list.
 // This function will not ignore the empty cell. For example, A1:A2:A2:B3 will
 // be reference A1:B3.
@@ -1635,6 +3084,116 @@ func callFuncByName(receiver interface{}, name string, params []reflect.Value) (
 	return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("not support %s function", name))
 }
 
+// FormulaArg directly maps the argument and result of the custom formula
+// function. The range argument will be passed as an array with the Matrix
+// field, and the Error field should be one of the formula errors such as
+// "#VALUE!" if the Type is ArgError.
+type FormulaArg struct {
+	Type    ArgType
+	Number  float64
+	String  string
+	Boolean bool
+	Error   string
+	List    []FormulaArg
+	Matrix  [][]FormulaArg
+}
+
+// Value returns a string data type of the formula argument.
+func (fa FormulaArg) Value() string {
+	return fa.toFormulaArg().Value()
+}
+
+// toFormulaArg converts the custom formula function argument to the formula
+// argument.
+func (fa FormulaArg) toFormulaArg() formulaArg {
+	arg := formulaArg{Type: fa.Type, Number: fa.Number, String: fa.String, Boolean: fa.Boolean, Error: fa.Error}
+	if fa.Type == ArgError {
+		arg.String = fa.Error
+	}
+	for _, item := range fa.List {
+		arg.List = append(arg.List, item.toFormulaArg())
+	}
+	for _, row := range fa.Matrix {
+		var matrixRow []formulaArg
+		for _, item := range row {
+			matrixRow = append(matrixRow, item.toFormulaArg())
+		}
+		arg.Matrix = append(arg.Matrix, matrixRow)
+	}
+	return arg
+}
+
+// newFormulaArg converts the formula argument to the argument of the custom
+// formula function.
+func newFormulaArg(arg formulaArg) FormulaArg {
+	fa := FormulaArg{Type: arg.Type, Number: arg.Number, String: arg.String, Boolean: arg.Boolean, Error: arg.Error}
+	if arg.Type == ArgError {
+		fa.String, fa.Error = "", arg.String
+	}
+	for _, item := range arg.List {
+		fa.List = append(fa.List, newFormulaArg(item))
+	}
+	for _, row := range arg.Matrix {
+		var matrixRow []FormulaArg
+		for _, item := range row {
+			matrixRow = append(matrixRow, newFormulaArg(item))
+		}
+		fa.Matrix = append(fa.Matrix, matrixRow)
+	}
+	return fa
+}
+
+// RegisterCalcFunc provides a function to register the custom formula
+// function by given function name, the registered function can be used in
+// the formulas calculated by CalcCellValue and other calculation functions.
+// The function name is case-insensitive and can not be the name of the
+// built-in function. For example, register a function named "MARGIN" for
+// calculating the margin by given revenue and cost:
+//
+//	err := f.RegisterCalcFunc("MARGIN", func(args ...excelize.FormulaArg) excelize.FormulaArg {
+//	    if len(args) != 2 || args[0].Type != excelize.ArgNumber || args[1].Type != excelize.ArgNumber {
+//	        return excelize.FormulaArg{Type: excelize.ArgError, Error: "#VALUE!"}
+//	    }
+//	    return excelize.FormulaArg{Type: excelize.ArgNumber, Number: (args[0].Number - args[1].Number) / args[0].Number}
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.SetCellFormula("Sheet1", "C1", "MARGIN(A1,B1)")
+//
+// Register a function with nil function will remove the registered custom
+// function.
+func (f *File) RegisterCalcFunc(name string, fn func(args ...FormulaArg) FormulaArg) error {
+	if name = strings.ToUpper(strings.TrimSpace(name)); name == "" {
+		return ErrParameterRequired
+	}
+	if function := reflect.ValueOf(&formulaFuncs{}).MethodByName(strings.ReplaceAll(name, ".", "dot")); function.IsValid() {
+		return newBuiltInFunctionError(name)
+	}
+	if fn == nil {
+		f.calcFuncs.Delete(name)
+		return nil
+	}
+	f.calcFuncs.Store(name, fn)
+	return nil
+}
+
+// callCalcFunc calls the registered custom formula function by given function
+// name and arguments, the second result will be false if the function has
+// not been registered.
+func (f *File) callCalcFunc(name string, argsList *list.List) (formulaArg, bool) {
+	fn, ok := f.calcFuncs.Load(strings.ToUpper(strings.TrimPrefix(name, "_xludf.")))
+	if !ok {
+		return formulaArg{}, ok
+	}
+	var args []FormulaArg
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		args = append(args, newFormulaArg(arg.Value.(formulaArg)))
+	}
+	return fn.(func(args ...FormulaArg) FormulaArg)(args...).toFormulaArg(), ok
+}
+
 // formulaCriteriaParser parse formula criteria.
 func formulaCriteriaParser(exp string) (fc *formulaCriteria) {
 	fc = &formulaCriteria{}
@@ -3631,17 +5190,161 @@ func (fn *formulaFuncs) AGGREGATE(argsList *list.List) formulaArg {
 	if opts = argsList.Front().Next().Value.(formulaArg).ToNumber(); opts.Type != ArgNumber {
 		return opts
 	}
-	// TODO: apply option argument values to be ignored during the calculation
 	if int(opts.Number) < 0 || int(opts.Number) > 7 {
 		return newErrorFormulaArg(formulaErrorVALUE, "AGGREGATE has invalid options")
 	}
+	filter := &subtotalFilter{
+		nested:     int(opts.Number) < 4,
+		hiddenRows: int(opts.Number)%2 == 1,
+		errors:     int(opts.Number) == 2 || int(opts.Number) == 3 || int(opts.Number) >= 6,
+	}
 	subArgList := list.New().Init()
 	for arg := argsList.Front().Next().Next(); arg != nil; arg = arg.Next() {
+		// The options only apply to the array argument of the functions 14-19
+		if fnNum.Number < 14 || arg == argsList.Front().Next().Next() {
+			if value, ok := fn.subtotalFilterArg(arg.Value.(formulaArg), filter); ok {
+				subArgList.PushBack(value)
+			}
+			continue
+		}
 		subArgList.PushBack(arg.Value.(formulaArg))
 	}
 	return subFn(subArgList)
 }
 
+// subtotalFilter defined the values to be ignored by the SUBTOTAL and
+// AGGREGATE functions, the ignored cells of each worksheet will be cached in
+// the sheets for the arguments on the same worksheet.
+type subtotalFilter struct {
+	nested, hiddenRows, filteredRows, errors bool
+	sheets                                   map[string]*subtotalIgnoredCells
+}
+
+// subtotalIgnoredCells defined the hidden rows and the cells contain nested
+// SUBTOTAL or AGGREGATE functions in a worksheet.
+type subtotalIgnoredCells struct {
+	hiddenRows  map[int]bool
+	nestedCells map[[2]int]bool
+}
+
+// subtotalFilterArg removes the values to be ignored from the given formula
+// argument by the filter of the SUBTOTAL and AGGREGATE functions, returns
+// false if the whole argument should be ignored. The cells in hidden rows,
+// the cells in rows hidden by the auto filter, the cells contain nested
+// SUBTOTAL or AGGREGATE functions and the error values can be ignored.
+func (fn *formulaFuncs) subtotalFilterArg(arg formulaArg, filter *subtotalFilter) (formulaArg, bool) {
+	if filter.errors && arg.Type == ArgError {
+		return arg, false
+	}
+	if arg.cellRanges == nil && arg.cellRefs == nil {
+		return arg, true
+	}
+	valueRange, sheet := []int{0, 0, 0, 0}, fn.sheet
+	if arg.cellRanges != nil {
+		for temp := arg.cellRanges.Front(); temp != nil; temp = temp.Next() {
+			cr := temp.Value.(cellRange)
+			rng := []int{cr.From.Col, cr.From.Row, cr.To.Col, cr.To.Row}
+			_ = sortCoordinates(rng)
+			cr.From.Col, cr.From.Row, cr.To.Col, cr.To.Row = rng[0], rng[1], rng[2], rng[3]
+			prepareValueRange(cr, valueRange)
+			if cr.From.Sheet != "" {
+				sheet = cr.From.Sheet
+			}
+		}
+	}
+	if arg.cellRefs != nil {
+		for temp := arg.cellRefs.Front(); temp != nil; temp = temp.Next() {
+			cr := temp.Value.(cellRef)
+			prepareValueRef(cr, valueRange)
+			if cr.Sheet != "" {
+				sheet = cr.Sheet
+			}
+		}
+	}
+	if filter.sheets == nil {
+		filter.sheets = map[string]*subtotalIgnoredCells{}
+	}
+	cells, ok := filter.sheets[sheet]
+	if !ok {
+		cells = fn.f.getSubtotalIgnoredCells(sheet, filter)
+		filter.sheets[sheet] = cells
+	}
+	hiddenRows := cells.hiddenRows
+	ignored := func(col, row int) bool {
+		return hiddenRows[row] || cells.nestedCells[[2]int{col, row}]
+	}
+	if arg.Type != ArgMatrix {
+		if ignored(valueRange[2], valueRange[0]) {
+			return arg, false
+		}
+		return arg, true
+	}
+	var matrix [][]formulaArg
+	for rowIdx, row := range arg.Matrix {
+		if hiddenRows[valueRange[0]+rowIdx] {
+			continue
+		}
+		var matrixRow []formulaArg
+		for colIdx, value := range row {
+			if ignored(valueRange[2]+colIdx, valueRange[0]+rowIdx) || filter.errors && value.Type == ArgError {
+				value = newStringFormulaArg("")
+			}
+			matrixRow = append(matrixRow, value)
+		}
+		matrix = append(matrix, matrixRow)
+	}
+	value := newMatrixFormulaArg(matrix)
+	value.cellRefs, value.cellRanges = arg.cellRefs, arg.cellRanges
+	return value, true
+}
+
+// getSubtotalIgnoredCells returns the hidden rows and the cells contain
+// nested SUBTOTAL or AGGREGATE functions in the worksheet to be ignored by the
+// SUBTOTAL and AGGREGATE functions with the given filter.
+func (f *File) getSubtotalIgnoredCells(sheet string, filter *subtotalFilter) *subtotalIgnoredCells {
+	hiddenRows, nestedCells := map[int]bool{}, map[[2]int]bool{}
+	cells := &subtotalIgnoredCells{hiddenRows: hiddenRows, nestedCells: nestedCells}
+	if !filter.hiddenRows && !filter.filteredRows && !filter.nested {
+		return cells
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return cells
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	filterRange := []int{0, 0, 0, 0}
+	if filter.filteredRows && ws.AutoFilter != nil {
+		if coordinates, err := rangeRefToCoordinates(ws.AutoFilter.Ref); err == nil {
+			_ = sortCoordinates(coordinates)
+			filterRange = coordinates
+		}
+	}
+	for _, row := range ws.SheetData.Row {
+		if row.Hidden && (filter.hiddenRows || filter.filteredRows && row.R >= filterRange[1] && row.R <= filterRange[3]) {
+			hiddenRows[row.R] = true
+		}
+		if !filter.nested {
+			continue
+		}
+		for _, c := range row.C {
+			if c.F == nil {
+				continue
+			}
+			formula := c.F.Content
+			if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+				formula = getSharedFormula(ws, *c.F.Si, c.R)
+			}
+			if formula = strings.ToUpper(formula); strings.Contains(formula, "SUBTOTAL(") || strings.Contains(formula, "AGGREGATE(") {
+				if col, row, err := CellNameToCoordinates(c.R); err == nil {
+					nestedCells[[2]int{col, row}] = true
+				}
+			}
+		}
+	}
+	return cells
+}
+
 // ARABIC function converts a Roman numeral into an Arabic numeral. The syntax
 // of the function is:
 //
@@ -5049,6 +6752,19 @@ func (fn *formulaFuncs) RAND(argsList *list.List) formulaArg {
 	if argsList.Len() != 0 {
 		return newErrorFormulaArg(formulaErrorVALUE, "RAND accepts no arguments")
 	}
+	return fn.random()
+}
+
+// random returns a pseudo-random number in the half-open interval [0.0,1.0)
+// by the random number generator of the calculation options, it returns the
+// #NUM! error if the generated number is out of the interval.
+func (fn *formulaFuncs) random() formulaArg {
+	if fn.f.options != nil && fn.f.options.CalcRand != nil {
+		if num := fn.f.options.CalcRand(); num >= 0 && num < 1 {
+			return newNumberFormulaArg(num)
+		}
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
 	return newNumberFormulaArg(rand.New(rand.NewSource(time.Now().UnixNano())).Float64())
 }
 
@@ -5071,7 +6787,11 @@ func (fn *formulaFuncs) RANDBETWEEN(argsList *list.List) formulaArg {
 	if top.Number < bottom.Number {
 		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
 	}
-	num := rand.New(rand.NewSource(time.Now().UnixNano())).Int63n(int64(top.Number - bottom.Number + 1))
+	rnd := fn.random()
+	if rnd.Type == ArgError {
+		return rnd
+	}
+	num := int64(rnd.Number * float64(int64(top.Number-bottom.Number+1)))
 	return newNumberFormulaArg(float64(num + int64(bottom.Number)))
 }
 
@@ -5633,7 +7353,10 @@ func (fn *formulaFuncs) POISSON(argsList *list.List) formulaArg {
 }
 
 // SUBTOTAL function performs a specified calculation (e.g. the sum, product,
-// average, etc.) for a supplied set of values. The syntax of the function is:
+// average, etc.) for a supplied set of values. The function ignores the rows
+// hidden by the auto filter and the nested SUBTOTAL and AGGREGATE functions,
+// and also ignores the manually hidden rows with the function number 101-111.
+// The syntax of the function is:
 //
 //	SUBTOTAL(function_num,ref1,[ref2],...)
 func (fn *formulaFuncs) SUBTOTAL(argsList *list.List) formulaArg {
@@ -5660,9 +7383,12 @@ func (fn *formulaFuncs) SUBTOTAL(argsList *list.List) formulaArg {
 	if !ok {
 		return newErrorFormulaArg(formulaErrorVALUE, "SUBTOTAL has invalid function_num")
 	}
+	filter := &subtotalFilter{nested: true, hiddenRows: fnNum.Number > 100, filteredRows: true}
 	subArgList := list.New().Init()
 	for arg := argsList.Front().Next(); arg != nil; arg = arg.Next() {
-		subArgList.PushBack(arg.Value.(formulaArg))
+		if value, ok := fn.subtotalFilterArg(arg.Value.(formulaArg), filter); ok {
+			subArgList.PushBack(value)
+		}
 	}
 	return subFn(subArgList)
 }
@@ -7718,7 +9444,7 @@ func (fn *formulaFuncs) COUNTA(argsList *list.List) formulaArg {
 			if arg.String != "" {
 				count++
 			}
-		case ArgNumber:
+		case ArgNumber, ArgError:
 			count++
 		case ArgMatrix:
 			for _, row := range arg.ToList() {
@@ -7727,7 +9453,7 @@ func (fn *formulaFuncs) COUNTA(argsList *list.List) formulaArg {
 					if row.String != "" {
 						count++
 					}
-				case ArgNumber:
+				case ArgNumber, ArgError:
 					count++
 				}
 			}
@@ -7744,8 +9470,12 @@ func (fn *formulaFuncs) COUNTBLANK(argsList *list.List) formulaArg {
 	if argsList.Len() != 1 {
 		return newErrorFormulaArg(formulaErrorVALUE, "COUNTBLANK requires 1 argument")
 	}
+	arg := argsList.Front().Value.(formulaArg)
+	if arg.Type == ArgEmpty {
+		return newNumberFormulaArg(1)
+	}
 	var count float64
-	for _, cell := range argsList.Front().Value.(formulaArg).ToList() {
+	for _, cell := range arg.ToList() {
 		if cell.Value() == "" {
 			count++
 		}
@@ -10265,11 +11995,64 @@ func (fn *formulaFuncs) PHI(argsList *list.List) formulaArg {
 	if argsList.Len() != 1 {
 		return newErrorFormulaArg(formulaErrorVALUE, "PHI requires 1 argument")
 	}
-	x := argsList.Front().Value.(formulaArg).ToNumber()
-	if x.Type != ArgNumber {
-		return x
+	x := argsList.Front().Value.(formulaArg).ToNumber()
+	if x.Type != ArgNumber {
+		return x
+	}
+	return newNumberFormulaArg(0.39894228040143268 * math.Exp(-(x.Number*x.Number)/2))
+}
+
+// PROB function calculates the probability that values in a range are
+// between two limits, or equal to a lower limit if the upper limit is
+// omitted. The syntax of the function is:
+//
+//	PROB(x_range,prob_range,lower_limit,[upper_limit])
+func (fn *formulaFuncs) PROB(argsList *list.List) formulaArg {
+	if argsList.Len() < 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "PROB requires at least 3 arguments")
+	}
+	if argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "PROB requires at most 4 arguments")
+	}
+	xRange := argsList.Front().Value.(formulaArg).ToList()
+	probRange := argsList.Front().Next().Value.(formulaArg).ToList()
+	if len(xRange) != len(probRange) {
+		return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	}
+	lower := argsList.Front().Next().Next().Value.(formulaArg).ToNumber()
+	if lower.Type != ArgNumber {
+		return lower
+	}
+	upper := lower
+	if argsList.Len() == 4 {
+		if upper = argsList.Back().Value.(formulaArg).ToNumber(); upper.Type != ArgNumber {
+			return upper
+		}
 	}
-	return newNumberFormulaArg(0.39894228040143268 * math.Exp(-(x.Number*x.Number)/2))
+	var sum, prob float64
+	for i := 0; i < len(xRange); i++ {
+		x, p := xRange[i], probRange[i]
+		if x.Type == ArgEmpty && p.Type == ArgEmpty {
+			continue
+		}
+		if x = x.ToNumber(); x.Type != ArgNumber {
+			return x
+		}
+		if p = p.ToNumber(); p.Type != ArgNumber {
+			return p
+		}
+		if p.Number <= 0 || p.Number > 1 {
+			return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+		}
+		sum += p.Number
+		if x.Number >= lower.Number && x.Number <= upper.Number {
+			prob += p.Number
+		}
+	}
+	if math.Abs(sum-1) > 1e-7 {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	return newNumberFormulaArg(prob)
 }
 
 // QUARTILE function returns a requested quartile of a supplied range of
@@ -12927,7 +14710,7 @@ func (fn *formulaFuncs) NOW(argsList *list.List) formulaArg {
 	if argsList.Len() != 0 {
 		return newErrorFormulaArg(formulaErrorVALUE, "NOW accepts no arguments")
 	}
-	now := time.Now()
+	now := fn.now()
 	_, offset := now.Zone()
 	return newNumberFormulaArg(25569.0 + float64(now.Unix()+int64(offset))/86400)
 }
@@ -13023,11 +14806,19 @@ func (fn *formulaFuncs) TODAY(argsList *list.List) formulaArg {
 	if argsList.Len() != 0 {
 		return newErrorFormulaArg(formulaErrorVALUE, "TODAY accepts no arguments")
 	}
-	now := time.Now()
+	now := fn.now()
 	_, offset := now.Zone()
 	return newNumberFormulaArg(daysBetween(excelMinTime1900.Unix(), now.Unix()+int64(offset)) + 1)
 }
 
+// now returns the current time by the clock of the calculation options.
+func (fn *formulaFuncs) now() time.Time {
+	if fn.f.options != nil && fn.f.options.CalcClock != nil {
+		return fn.f.options.CalcClock()
+	}
+	return time.Now()
+}
+
 // makeDate return date as a Unix time, the number of seconds elapsed since
 // January 1, 1970 UTC.
 func makeDate(y int, m time.Month, d int) int64 {
@@ -14171,6 +15962,57 @@ func (fn *formulaFuncs) COLUMNS(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(float64(result))
 }
 
+// FILTER function filters a range or an array based on the given criteria and
+// returns the rows or columns which meet the criteria. The syntax of the
+// function is:
+//
+//	FILTER(array,include,[if_empty])
+func (fn *formulaFuncs) FILTER(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 || argsList.Len() > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "FILTER requires 2 or 3 arguments")
+	}
+	array, include := argsList.Front().Value.(formulaArg).toMatrix(), argsList.Front().Next().Value.(formulaArg).toMatrix()
+	rows, cols := len(array), len(array[0])
+	byRow := len(include) == rows && len(include[0]) == 1
+	if !byRow && (len(include) != 1 || len(include[0]) != cols) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	var result [][]formulaArg
+	for idx, cell := range newMatrixFormulaArg(include).ToList() {
+		var included bool
+		switch cell.Type {
+		case ArgError:
+			return cell
+		case ArgNumber:
+			included = cell.Number != 0
+		case ArgString:
+			if cell.String != "" {
+				return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+		}
+		if !included {
+			continue
+		}
+		if byRow {
+			result = append(result, array[idx])
+			continue
+		}
+		for r := 0; r < rows; r++ {
+			if len(result) < rows {
+				result = append(result, []formulaArg{})
+			}
+			result[r] = append(result[r], array[r][idx])
+		}
+	}
+	if len(result) == 0 {
+		if argsList.Len() == 3 {
+			return argsList.Back().Value.(formulaArg)
+		}
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // FORMULATEXT function returns a formula as a text string. The syntax of the
 // function is:
 //
@@ -14196,6 +16038,156 @@ func (fn *formulaFuncs) FORMULATEXT(argsList *list.List) formulaArg {
 	return newStringFormulaArg(formula)
 }
 
+// GETPIVOTDATA function extracts the data stored in a pivot table by given
+// data field name, a reference to any cell in the pivot table and the pairs
+// of the field name and item. The data will be summarized from the source
+// data of the pivot table by the summary function of the data field. The
+// syntax of the function is:
+//
+//	GETPIVOTDATA(data_field,pivot_table,[field1,item1],...)
+func (fn *formulaFuncs) GETPIVOTDATA(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "GETPIVOTDATA requires at least 2 arguments")
+	}
+	if argsList.Len()%2 != 0 {
+		return newErrorFormulaArg(formulaErrorVALUE, "GETPIVOTDATA requires the pairs of field and item arguments")
+	}
+	var ref cellRef
+	pivotTable := argsList.Front().Next().Value.(formulaArg)
+	if pivotTable.cellRanges != nil && pivotTable.cellRanges.Len() > 0 {
+		ref = pivotTable.cellRanges.Front().Value.(cellRange).From
+	}
+	if pivotTable.cellRefs != nil && pivotTable.cellRefs.Len() > 0 {
+		ref = pivotTable.cellRefs.Front().Value.(cellRef)
+	}
+	if ref.Sheet == "" {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+	}
+	pt, pc, err := fn.f.getPivotTableByCell(ref.Sheet, ref.Col, ref.Row)
+	if err != nil || pt == nil || pc == nil || pc.CacheSource == nil || pc.CacheSource.WorksheetSource == nil {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+	}
+	source, err := fn.getPivotTableSource(pc.CacheSource.WorksheetSource, ref.Sheet)
+	if err != nil || source.Type != ArgMatrix || len(source.Matrix) < 2 {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+	}
+	fields := source.Matrix[0]
+	dataField := argsList.Front().Value.(formulaArg).Value()
+	fld, subtotal := -1, "sum"
+	if pt.DataFields != nil {
+		for _, df := range pt.DataFields.DataField {
+			if df.Fld >= 0 && df.Fld < len(fields) && (strings.EqualFold(df.Name, dataField) || strings.EqualFold(fields[df.Fld].Value(), dataField)) {
+				if fld = df.Fld; df.Subtotal != "" {
+					subtotal = df.Subtotal
+				}
+				break
+			}
+		}
+	}
+	if fld == -1 {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+	}
+	axisFields := getPivotTableAxisFields(pt)
+	criteria := make(map[int]formulaArg)
+	for arg := argsList.Front().Next().Next(); arg != nil; arg = arg.Next().Next() {
+		field := arg.Value.(formulaArg).Value()
+		idx := -1
+		for i, name := range fields {
+			if strings.EqualFold(name.Value(), field) && axisFields[i] {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+		}
+		criteria[idx] = arg.Next().Value.(formulaArg)
+	}
+	var values []formulaArg
+	for _, row := range source.Matrix[1:] {
+		if fld < len(row) && pivotTableRowMatched(row, criteria) {
+			values = append(values, row[fld])
+		}
+	}
+	if len(values) == 0 {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+	}
+	args := list.New()
+	args.PushBack(newMatrixFormulaArg([][]formulaArg{values}))
+	if summarize, ok := map[string]func(argsList *list.List) formulaArg{
+		"average":   fn.AVERAGE,
+		"count":     fn.COUNTA,
+		"countNums": fn.COUNT,
+		"max":       fn.MAX,
+		"min":       fn.MIN,
+		"product":   fn.PRODUCT,
+		"stdDev":    fn.STDEV,
+		"stdDevp":   fn.STDEVP,
+		"sum":       fn.SUM,
+		"var":       fn.VAR,
+		"varp":      fn.VARP,
+	}[subtotal]; ok {
+		return summarize(args)
+	}
+	return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+}
+
+// getPivotTableSource returns the source data of the pivot table by given
+// worksheet source of the pivot cache and the worksheet name of the pivot
+// table. The worksheet source can be a range reference, a defined name or a
+// table name.
+func (fn *formulaFuncs) getPivotTableSource(ws *xlsxWorksheetSource, sheet string) (formulaArg, error) {
+	if ws.Name != "" {
+		return fn.f.parseNameReference(fn.ctx, sheet, ws.Name)
+	}
+	if ws.Sheet != "" {
+		sheet = ws.Sheet
+	}
+	return fn.f.parseReference(fn.ctx, sheet, ws.Ref)
+}
+
+// getPivotTableAxisFields returns the index of the fields on the row, column
+// and page axis of the pivot table.
+func getPivotTableAxisFields(pt *xlsxPivotTableDefinition) map[int]bool {
+	fields := make(map[int]bool)
+	if pt.RowFields != nil {
+		for _, field := range pt.RowFields.Field {
+			fields[field.X] = true
+		}
+	}
+	if pt.ColFields != nil {
+		for _, field := range pt.ColFields.Field {
+			fields[field.X] = true
+		}
+	}
+	if pt.PageFields != nil {
+		for _, field := range pt.PageFields.PageField {
+			fields[field.Fld] = true
+		}
+	}
+	return fields
+}
+
+// pivotTableRowMatched returns if the row of the pivot table source data
+// matches all the pairs of the field index and item.
+func pivotTableRowMatched(row []formulaArg, criteria map[int]formulaArg) bool {
+	for idx, item := range criteria {
+		if idx >= len(row) {
+			return false
+		}
+		if cell, num := row[idx].ToNumber(), item.ToNumber(); cell.Type == ArgNumber && num.Type == ArgNumber {
+			if cell.Number != num.Number {
+				return false
+			}
+			continue
+		}
+		if !strings.EqualFold(row[idx].Value(), item.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
 // checkHVLookupArgs checking arguments, prepare extract mode, lookup value,
 // and data for the formula functions HLOOKUP and VLOOKUP.
 func checkHVLookupArgs(name string, argsList *list.List) (idx int, lookupValue, tableArray, matchMode, errArg formulaArg) {
@@ -14360,6 +16352,135 @@ func (fn *formulaFuncs) MATCH(argsList *list.List) formulaArg {
 	return calcMatch(matchType, formulaCriteriaParser(argsList.Front().Value.(formulaArg).Value()), lookupArray)
 }
 
+// SEQUENCE function generates a list of sequential numbers in an array. The
+// syntax of the function is:
+//
+//	SEQUENCE(rows,[columns],[start],[step])
+func (fn *formulaFuncs) SEQUENCE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 || argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SEQUENCE requires at least 1 argument and at most 4 arguments")
+	}
+	args := []formulaArg{newNumberFormulaArg(1), newNumberFormulaArg(1), newNumberFormulaArg(1), newNumberFormulaArg(1)}
+	i := 0
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		if arg.Value.(formulaArg).Type != ArgEmpty {
+			if args[i] = arg.Value.(formulaArg).ToNumber(); args[i].Type != ArgNumber {
+				return args[i]
+			}
+		}
+		i++
+	}
+	rows, cols := int(args[0].Number), int(args[1].Number)
+	if rows < 1 || cols < 1 || rows > TotalRows || cols > MaxColumns {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	mtx := make([][]formulaArg, rows)
+	for r := range mtx {
+		mtx[r] = make([]formulaArg, cols)
+		for c := range mtx[r] {
+			mtx[r][c] = newNumberFormulaArg(args[2].Number + float64(r*cols+c)*args[3].Number)
+		}
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
+// sortFormulaArgRank returns the order rank of the formula argument for the
+// formula function SORT and UNIQUE. The numbers are sorted before the texts,
+// logical values, errors and blanks.
+func sortFormulaArgRank(arg formulaArg) int {
+	switch arg.Type {
+	case ArgNumber:
+		if arg.Boolean {
+			return 2
+		}
+		return 0
+	case ArgString:
+		if arg.String == "" {
+			return 4
+		}
+		return 1
+	case ArgError:
+		return 3
+	}
+	return 4
+}
+
+// compareSortFormulaArg compares the formula arguments for the formula
+// function SORT, the texts are compared case-insensitively.
+func compareSortFormulaArg(lhs, rhs formulaArg) int {
+	if l, r := sortFormulaArgRank(lhs), sortFormulaArgRank(rhs); l != r {
+		return l - r
+	}
+	switch lhs.Type {
+	case ArgNumber:
+		if lhs.Number < rhs.Number {
+			return -1
+		}
+		if lhs.Number > rhs.Number {
+			return 1
+		}
+	case ArgString:
+		return strings.Compare(strings.ToLower(lhs.String), strings.ToLower(rhs.String))
+	}
+	return 0
+}
+
+// transposeMatrix returns the transposed matrix of the given formula arguments
+// matrix.
+func transposeMatrix(mtx [][]formulaArg) [][]formulaArg {
+	result := make([][]formulaArg, len(mtx[0]))
+	for c := range result {
+		result[c] = make([]formulaArg, len(mtx))
+		for r := range mtx {
+			result[c][r] = mtx[r][c]
+		}
+	}
+	return result
+}
+
+// SORT function sorts the contents of a range or an array by the given row or
+// column. The syntax of the function is:
+//
+//	SORT(array,[sort_index],[sort_order],[by_col])
+func (fn *formulaFuncs) SORT(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 || argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SORT requires at least 1 argument and at most 4 arguments")
+	}
+	mtx := argsList.Front().Value.(formulaArg).toMatrix()
+	sortIndex, sortOrder, byCol := newNumberFormulaArg(1), newNumberFormulaArg(1), newBoolFormulaArg(false)
+	if argsList.Len() > 1 && argsList.Front().Next().Value.(formulaArg).Type != ArgEmpty {
+		if sortIndex = argsList.Front().Next().Value.(formulaArg).ToNumber(); sortIndex.Type != ArgNumber {
+			return sortIndex
+		}
+	}
+	if argsList.Len() > 2 && argsList.Front().Next().Next().Value.(formulaArg).Type != ArgEmpty {
+		if sortOrder = argsList.Front().Next().Next().Value.(formulaArg).ToNumber(); sortOrder.Type != ArgNumber {
+			return sortOrder
+		}
+	}
+	if argsList.Len() > 3 {
+		if byCol = argsList.Back().Value.(formulaArg).ToBool(); byCol.Type != ArgNumber {
+			return byCol
+		}
+	}
+	if byCol.Number == 1 {
+		mtx = transposeMatrix(mtx)
+	}
+	idx := int(sortIndex.Number) - 1
+	if idx < 0 || idx >= len(mtx[0]) || (sortOrder.Number != 1 && sortOrder.Number != -1) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	sorted := make([][]formulaArg, len(mtx))
+	copy(sorted, mtx)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareSortFormulaArg(sorted[i][idx], sorted[j][idx])*int(sortOrder.Number) < 0
+	})
+	if byCol.Number == 1 {
+		sorted = transposeMatrix(sorted)
+	}
+	return newMatrixFormulaArg(sorted)
+}
+
 // TRANSPOSE function 'transposes' an array of cells (i.e. the function copies
 // a horizontal range of cells into a vertical range and vice versa). The
 // syntax of the function is:
@@ -14391,6 +16512,59 @@ func (fn *formulaFuncs) TRANSPOSE(argsList *list.List) formulaArg {
 	return newMatrixFormulaArg(mtx)
 }
 
+// UNIQUE function returns a list of unique values in a range or an array. The
+// syntax of the function is:
+//
+//	UNIQUE(array,[by_col],[exactly_once])
+func (fn *formulaFuncs) UNIQUE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 || argsList.Len() > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "UNIQUE requires at least 1 argument and at most 3 arguments")
+	}
+	mtx := argsList.Front().Value.(formulaArg).toMatrix()
+	byCol, exactlyOnce := newBoolFormulaArg(false), newBoolFormulaArg(false)
+	if argsList.Len() > 1 && argsList.Front().Next().Value.(formulaArg).Type != ArgEmpty {
+		if byCol = argsList.Front().Next().Value.(formulaArg).ToBool(); byCol.Type != ArgNumber {
+			return byCol
+		}
+	}
+	if argsList.Len() > 2 {
+		if exactlyOnce = argsList.Back().Value.(formulaArg).ToBool(); exactlyOnce.Type != ArgNumber {
+			return exactlyOnce
+		}
+	}
+	if byCol.Number == 1 {
+		mtx = transposeMatrix(mtx)
+	}
+	var keys []string
+	counts, rows := make(map[string]int), make(map[string][]formulaArg)
+	for _, row := range mtx {
+		var key strings.Builder
+		for _, cell := range row {
+			key.WriteString(strconv.Itoa(sortFormulaArgRank(cell)))
+			key.WriteString(strings.ToLower(cell.Value()))
+			key.WriteByte(0)
+		}
+		if counts[key.String()]++; counts[key.String()] == 1 {
+			keys = append(keys, key.String())
+			rows[key.String()] = row
+		}
+	}
+	var result [][]formulaArg
+	for _, key := range keys {
+		if exactlyOnce.Number == 1 && counts[key] > 1 {
+			continue
+		}
+		result = append(result, rows[key])
+	}
+	if len(result) == 0 {
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	if byCol.Number == 1 {
+		result = transposeMatrix(result)
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // lookupLinearSearch sequentially checks each look value of the lookup array until
 // a match is found or the whole list has been searched.
 func lookupLinearSearch(vertical bool, lookupValue, lookupArray, matchMode, searchMode formulaArg) (int, bool) {
@@ -16431,43 +18605,46 @@ func coupNumber(maturity, settlement, numMonths float64) float64 {
 	return result
 }
 
-// prepareOddfpriceArgs checking and prepare arguments for the formula
-// function ODDFPRICE.
-func (fn *formulaFuncs) prepareOddfpriceArgs(argsList *list.List) formulaArg {
+// prepareOddfArgs checking and prepare arguments for the formula functions
+// ODDFPRICE and ODDFYIELD.
+func (fn *formulaFuncs) prepareOddfArgs(name string, argsList *list.List) formulaArg {
 	dateValues := fn.prepareDataValueArgs(4, argsList)
 	if dateValues.Type != ArgList {
 		return dateValues
 	}
 	settlement, maturity, issue, firstCoupon := dateValues.List[0], dateValues.List[1], dateValues.List[2], dateValues.List[3]
 	if issue.Number >= settlement.Number {
-		return newErrorFormulaArg(formulaErrorNUM, "ODDFPRICE requires settlement > issue")
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires settlement > issue")
 	}
 	if settlement.Number >= firstCoupon.Number {
-		return newErrorFormulaArg(formulaErrorNUM, "ODDFPRICE requires first_coupon > settlement")
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires first_coupon > settlement")
 	}
 	if firstCoupon.Number >= maturity.Number {
-		return newErrorFormulaArg(formulaErrorNUM, "ODDFPRICE requires maturity > first_coupon")
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires maturity > first_coupon")
 	}
 	rate := argsList.Front().Next().Next().Next().Next().Value.(formulaArg).ToNumber()
 	if rate.Type != ArgNumber {
 		return rate
 	}
 	if rate.Number < 0 {
-		return newErrorFormulaArg(formulaErrorNUM, "ODDFPRICE requires rate >= 0")
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires rate >= 0")
 	}
-	yld := argsList.Front().Next().Next().Next().Next().Next().Value.(formulaArg).ToNumber()
-	if yld.Type != ArgNumber {
-		return yld
+	yldOrPr := argsList.Front().Next().Next().Next().Next().Next().Value.(formulaArg).ToNumber()
+	if yldOrPr.Type != ArgNumber {
+		return yldOrPr
 	}
-	if yld.Number < 0 {
+	if name == "ODDFPRICE" && yldOrPr.Number < 0 {
 		return newErrorFormulaArg(formulaErrorNUM, "ODDFPRICE requires yld >= 0")
 	}
+	if name == "ODDFYIELD" && yldOrPr.Number <= 0 {
+		return newErrorFormulaArg(formulaErrorNUM, "ODDFYIELD requires pr > 0")
+	}
 	redemption := argsList.Front().Next().Next().Next().Next().Next().Next().Value.(formulaArg).ToNumber()
 	if redemption.Type != ArgNumber {
 		return redemption
 	}
 	if redemption.Number <= 0 {
-		return newErrorFormulaArg(formulaErrorNUM, "ODDFPRICE requires redemption > 0")
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires redemption > 0")
 	}
 	frequency := argsList.Front().Next().Next().Next().Next().Next().Next().Next().Value.(formulaArg).ToNumber()
 	if frequency.Type != ArgNumber {
@@ -16482,7 +18659,7 @@ func (fn *formulaFuncs) prepareOddfpriceArgs(argsList *list.List) formulaArg {
 			return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
 		}
 	}
-	return newListFormulaArg([]formulaArg{settlement, maturity, issue, firstCoupon, rate, yld, redemption, frequency, basis})
+	return newListFormulaArg([]formulaArg{settlement, maturity, issue, firstCoupon, rate, yldOrPr, redemption, frequency, basis})
 }
 
 // ODDFPRICE function calculates the price per $100 face value of a security
@@ -16493,7 +18670,7 @@ func (fn *formulaFuncs) ODDFPRICE(argsList *list.List) formulaArg {
 	if argsList.Len() != 8 && argsList.Len() != 9 {
 		return newErrorFormulaArg(formulaErrorVALUE, "ODDFPRICE requires 8 or 9 arguments")
 	}
-	args := fn.prepareOddfpriceArgs(argsList)
+	args := fn.prepareOddfArgs("ODDFPRICE", argsList)
 	if args.Type != ArgList {
 		return args
 	}
@@ -16501,6 +18678,12 @@ func (fn *formulaFuncs) ODDFPRICE(argsList *list.List) formulaArg {
 	if basisArg.Number < 0 || basisArg.Number > 4 {
 		return newErrorFormulaArg(formulaErrorNUM, "invalid basis")
 	}
+	return fn.oddfprice(settlement, maturity, issue, firstCoupon, rate, yld, redemption, frequency, basisArg)
+}
+
+// oddfprice is an implementation of the formula functions ODDFPRICE and
+// ODDFYIELD.
+func (fn *formulaFuncs) oddfprice(settlement, maturity, issue, firstCoupon, rate, yld, redemption, frequency, basisArg formulaArg) formulaArg {
 	issueTime := timeFromExcelTime(issue.Number, false)
 	settlementTime := timeFromExcelTime(settlement.Number, false)
 	maturityTime := timeFromExcelTime(maturity.Number, false)
@@ -16613,6 +18796,162 @@ func (fn *formulaFuncs) ODDFPRICE(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(term1 + term2 + term3[0] - term4)
 }
 
+// ODDFYIELD function calculates the yield of a security with an odd (short
+// or long) first period. The syntax of the function is:
+//
+//	ODDFYIELD(settlement,maturity,issue,first_coupon,rate,pr,redemption,frequency,[basis])
+func (fn *formulaFuncs) ODDFYIELD(argsList *list.List) formulaArg {
+	if argsList.Len() != 8 && argsList.Len() != 9 {
+		return newErrorFormulaArg(formulaErrorVALUE, "ODDFYIELD requires 8 or 9 arguments")
+	}
+	args := fn.prepareOddfArgs("ODDFYIELD", argsList)
+	if args.Type != ArgList {
+		return args
+	}
+	settlement, maturity, issue, firstCoupon, rate, pr, redemption, frequency, basis := args.List[0], args.List[1], args.List[2], args.List[3], args.List[4], args.List[5], args.List[6], args.List[7], args.List[8]
+	if basis.Number < 0 || basis.Number > 4 {
+		return newErrorFormulaArg(formulaErrorNUM, "invalid basis")
+	}
+	price := func(yld float64) formulaArg {
+		return fn.oddfprice(settlement, maturity, issue, firstCoupon, rate, newNumberFormulaArg(yld), redemption, frequency, basis)
+	}
+	return oddYield(pr.Number, price)
+}
+
+// oddYield solves the yield of the security by the bisection method with
+// given price and the price function, the price of the security decreases as
+// the yield increases.
+func oddYield(pr float64, price func(yld float64) formulaArg) formulaArg {
+	low, high := 0.0, 1.0
+	lowPrice := price(low)
+	if lowPrice.Type != ArgNumber {
+		return lowPrice
+	}
+	if lowPrice.Number < pr {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	for iter := 0; iter < 64; iter++ {
+		highPrice := price(high)
+		if highPrice.Type != ArgNumber {
+			return highPrice
+		}
+		if highPrice.Number <= pr {
+			break
+		}
+		low, high = high, high*2
+	}
+	for iter := 0; iter < 100 && high-low > 1e-15; iter++ {
+		mid := (low + high) / 2
+		midPrice := price(mid)
+		if midPrice.Type != ArgNumber {
+			return midPrice
+		}
+		if midPrice.Number > pr {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return newNumberFormulaArg((low + high) / 2)
+}
+
+// prepareOddlArgs checking and prepare arguments for the formula functions
+// ODDLPRICE and ODDLYIELD.
+func (fn *formulaFuncs) prepareOddlArgs(name string, argsList *list.List) formulaArg {
+	if argsList.Len() != 7 && argsList.Len() != 8 {
+		return newErrorFormulaArg(formulaErrorVALUE, name+" requires 7 or 8 arguments")
+	}
+	dateValues := fn.prepareDataValueArgs(3, argsList)
+	if dateValues.Type != ArgList {
+		return dateValues
+	}
+	settlement, maturity, lastInterest := dateValues.List[0], dateValues.List[1], dateValues.List[2]
+	if lastInterest.Number >= settlement.Number {
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires settlement > last_interest")
+	}
+	if settlement.Number >= maturity.Number {
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires maturity > settlement")
+	}
+	rate := argsList.Front().Next().Next().Next().Value.(formulaArg).ToNumber()
+	if rate.Type != ArgNumber {
+		return rate
+	}
+	if rate.Number < 0 {
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires rate >= 0")
+	}
+	yldOrPr := argsList.Front().Next().Next().Next().Next().Value.(formulaArg).ToNumber()
+	if yldOrPr.Type != ArgNumber {
+		return yldOrPr
+	}
+	if name == "ODDLPRICE" && yldOrPr.Number < 0 {
+		return newErrorFormulaArg(formulaErrorNUM, "ODDLPRICE requires yld >= 0")
+	}
+	if name == "ODDLYIELD" && yldOrPr.Number <= 0 {
+		return newErrorFormulaArg(formulaErrorNUM, "ODDLYIELD requires pr > 0")
+	}
+	redemption := argsList.Front().Next().Next().Next().Next().Next().Value.(formulaArg).ToNumber()
+	if redemption.Type != ArgNumber {
+		return redemption
+	}
+	if redemption.Number <= 0 {
+		return newErrorFormulaArg(formulaErrorNUM, name+" requires redemption > 0")
+	}
+	frequency := argsList.Front().Next().Next().Next().Next().Next().Next().Value.(formulaArg).ToNumber()
+	if frequency.Type != ArgNumber {
+		return frequency
+	}
+	if !validateFrequency(frequency.Number) {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	basis := newNumberFormulaArg(0)
+	if argsList.Len() == 8 {
+		if basis = argsList.Back().Value.(formulaArg).ToNumber(); basis.Type != ArgNumber {
+			return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+		}
+	}
+	if basis.Number < 0 || basis.Number > 4 {
+		return newErrorFormulaArg(formulaErrorNUM, "invalid basis")
+	}
+	// the coupon periods from the last interest date to the maturity, from the
+	// settlement to the maturity and from the last interest date to the
+	// settlement
+	dci := yearFrac(lastInterest.Number, maturity.Number, int(basis.Number)).Number * frequency.Number
+	dsci := yearFrac(settlement.Number, maturity.Number, int(basis.Number)).Number * frequency.Number
+	ai := yearFrac(lastInterest.Number, settlement.Number, int(basis.Number)).Number * frequency.Number
+	return newListFormulaArg([]formulaArg{
+		newNumberFormulaArg(dci), newNumberFormulaArg(dsci), newNumberFormulaArg(ai),
+		rate, yldOrPr, redemption, frequency,
+	})
+}
+
+// ODDLPRICE function calculates the price per $100 face value of a security
+// with an odd (short or long) last period. The syntax of the function is:
+//
+//	ODDLPRICE(settlement,maturity,last_interest,rate,yld,redemption,frequency,[basis])
+func (fn *formulaFuncs) ODDLPRICE(argsList *list.List) formulaArg {
+	args := fn.prepareOddlArgs("ODDLPRICE", argsList)
+	if args.Type != ArgList {
+		return args
+	}
+	dci, dsci, ai, rate, yld, redemption, frequency := args.List[0].Number, args.List[1].Number, args.List[2].Number, args.List[3].Number, args.List[4].Number, args.List[5].Number, args.List[6].Number
+	price := (redemption + dci*100*rate/frequency) / (dsci*yld/frequency + 1)
+	return newNumberFormulaArg(price - ai*100*rate/frequency)
+}
+
+// ODDLYIELD function calculates the yield of a security with an odd (short
+// or long) last period. The syntax of the function is:
+//
+//	ODDLYIELD(settlement,maturity,last_interest,rate,pr,redemption,frequency,[basis])
+func (fn *formulaFuncs) ODDLYIELD(argsList *list.List) formulaArg {
+	args := fn.prepareOddlArgs("ODDLYIELD", argsList)
+	if args.Type != ArgList {
+		return args
+	}
+	dci, dsci, ai, rate, pr, redemption, frequency := args.List[0].Number, args.List[1].Number, args.List[2].Number, args.List[3].Number, args.List[4].Number, args.List[5].Number, args.List[6].Number
+	yld := (redemption+dci*100*rate/frequency)/(pr+ai*100*rate/frequency) - 1
+	return newNumberFormulaArg(yld * frequency / dsci)
+}
+
 // PDURATION function calculates the number of periods required for an
 // investment to reach a specified future value. The syntax of the function
 // is:
@@ -2,10 +2,13 @@ package excel
 
 import (
 	"container/list"
+	"errors"
 	"math"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 	
 	"github.com/stretchr/testify/assert"
 	"github.com/xuri/efp"
@@ -854,6 +857,8 @@ func TestCalcCellValue(t *testing.T) {
 		"=1+SUM(SUM(1,2*3),4)*4/3+5+(4+2)*3":  "38.6666666666667",
 		"=SUM(1+ROW())":                       "2",
 		"=SUM((SUM(2))+1)":                    "3",
+		"=SUM(1+(MAX(A1:A4)))":                "4",
+		"=SUM(3*(1+MIN(A1:A4)))":              "3",
 		"=SUM({1,2,3,4,\"\"})":                "10",
 		// SUMIF
 		`=SUMIF(F1:F5, "")`:             "0",
@@ -2031,6 +2036,16 @@ func TestCalcCellValue(t *testing.T) {
 		"=ODDFPRICE(\"11/11/2008\",\"03/01/2021\",\"10/15/2008\",\"03/01/2009\",7.85%,6.25%,100,2,1)":          "113.597717474079",
 		"=ODDFPRICE(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"09/30/2017\",5.5%,3.5%,100,4,0)":            "106.72930611878",
 		"=ODDFPRICE(\"11/11/2008\",\"03/29/2021\", \"08/15/2008\", \"03/29/2009\", 0.0785, 0.0625, 100, 2, 1)": "113.61826640814",
+		// ODDFYIELD
+		"=ODDFYIELD(\"11/11/2008\",\"03/01/2021\",\"10/15/2008\",\"03/01/2009\",5.75%,84.5,100,2,0)":             "0.0772455415978173",
+		"=ODDFYIELD(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/31/2017\",5.5%,107.691830256629,100,2)":    "0.0350000000000006",
+		"=ODDFYIELD(\"11/11/2008\",\"03/01/2021\",\"10/15/2008\",\"03/01/2009\",7.85%,113.597717474079,100,2,1)": "0.0624999999999996",
+		// ODDLPRICE
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,4.05%,100,2)":   "99.8782860147213",
+		"=ODDLPRICE(\"04/20/2008\",\"06/15/2008\",\"12/24/2007\",3.75%,4.05%,100,2,1)": "99.9469352504689",
+		// ODDLYIELD
+		"=ODDLYIELD(\"04/20/2008\",\"06/15/2008\",\"12/24/2007\",3.75%,99.875,100,2)":   "0.045192235629169",
+		"=ODDLYIELD(\"04/20/2008\",\"06/15/2008\",\"12/24/2007\",3.75%,99.875,100,4,3)": "0.0451563237304758",
 		// PDURATION
 		"=PDURATION(0.04,10000,15000)": "10.3380350715076",
 		// PMT
@@ -4156,6 +4171,33 @@ func TestCalcCellValue(t *testing.T) {
 		"=ODDFPRICE(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/31/2017\",5.5%,3.5%,100,3)":      "#NUM!",
 		"=ODDFPRICE(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/30/2017\",5.5%,3.5%,100,4)":      "#NUM!",
 		"=ODDFPRICE(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/31/2017\",5.5%,3.5%,100,2,5)":    "invalid basis",
+		// ODDFYIELD
+		"=ODDFYIELD()": "ODDFYIELD requires 8 or 9 arguments",
+		"=ODDFYIELD(\"\",\"03/31/2021\",\"12/01/2016\",\"03/31/2017\",5.5%,100,100,2)":             "#VALUE!",
+		"=ODDFYIELD(\"02/01/2017\",\"03/31/2021\",\"02/01/2017\",\"03/31/2017\",5.5%,100,100,2)":   "ODDFYIELD requires settlement > issue",
+		"=ODDFYIELD(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/31/2017\",5.5%,\"\",100,2)":  "strconv.ParseFloat: parsing \"\": invalid syntax",
+		"=ODDFYIELD(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/31/2017\",5.5%,0,100,2)":     "ODDFYIELD requires pr > 0",
+		"=ODDFYIELD(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/31/2017\",5.5%,1000,100,2)":  "#NUM!",
+		"=ODDFYIELD(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/30/2017\",5.5%,100,100,4)":   "#NUM!",
+		"=ODDFYIELD(\"02/01/2017\",\"03/31/2021\",\"12/01/2016\",\"03/31/2017\",5.5%,100,100,2,5)": "invalid basis",
+		// ODDLPRICE
+		"=ODDLPRICE()": "ODDLPRICE requires 7 or 8 arguments",
+		"=ODDLPRICE(\"\",\"06/15/2008\",\"10/15/2007\",3.75%,4.05%,100,2)":                "#VALUE!",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"02/07/2008\",3.75%,4.05%,100,2)":      "ODDLPRICE requires settlement > last_interest",
+		"=ODDLPRICE(\"02/07/2008\",\"02/07/2008\",\"10/15/2007\",3.75%,4.05%,100,2)":      "ODDLPRICE requires maturity > settlement",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",\"\",4.05%,100,2)":       "strconv.ParseFloat: parsing \"\": invalid syntax",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",-1,4.05%,100,2)":         "ODDLPRICE requires rate >= 0",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,\"\",100,2)":       "strconv.ParseFloat: parsing \"\": invalid syntax",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,-1,100,2)":         "ODDLPRICE requires yld >= 0",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,4.05%,\"\",2)":     "strconv.ParseFloat: parsing \"\": invalid syntax",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,4.05%,0,2)":        "ODDLPRICE requires redemption > 0",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,4.05%,100,\"\")":   "strconv.ParseFloat: parsing \"\": invalid syntax",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,4.05%,100,3)":      "#NUM!",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,4.05%,100,2,\"\")": "#NUM!",
+		"=ODDLPRICE(\"02/07/2008\",\"06/15/2008\",\"10/15/2007\",3.75%,4.05%,100,2,5)":    "invalid basis",
+		// ODDLYIELD
+		"=ODDLYIELD()": "ODDLYIELD requires 7 or 8 arguments",
+		"=ODDLYIELD(\"04/20/2008\",\"06/15/2008\",\"12/24/2007\",3.75%,0,100,2)": "ODDLYIELD requires pr > 0",
 		// PDURATION
 		"=PDURATION()":         "PDURATION requires 3 arguments",
 		"=PDURATION(\"\",0,0)": "strconv.ParseFloat: parsing \"\": invalid syntax",
@@ -4461,6 +4503,43 @@ func TestCalcWithDefinedName(t *testing.T) {
 	result, err = f.CalcCellValue("Sheet1", "D1")
 	assert.NoError(t, err)
 	assert.Equal(t, "YES", result, `=IF("B1_as_string"=defined_name1,"YES","NO")`)
+	
+	// Test calculate with defined names refer to the constants, formulas and
+	// multi-area ranges
+	f = prepareCalcData([][]interface{}{{1, 2, 3}, {4, 5, 6}})
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	for _, definedName := range []*DefinedName{
+		{Name: "TaxRate", RefersTo: "0.5"},
+		{Name: "TaxRate", RefersTo: "0.25", Scope: "Sheet2"},
+		{Name: "Greeting", RefersTo: `="Hello"`},
+		{Name: "Total", RefersTo: "=SUM(Sheet1!$A$1:$C$1)"},
+		{Name: "Areas", RefersTo: "Sheet1!$A$1:$A$2,Sheet1!$C$1:$C$2"},
+		{Name: "FirstRow", RefersTo: "=Sheet1!$A$1:$C$1"},
+		{Name: "Loop", RefersTo: "=Loop+1"},
+	} {
+		assert.NoError(t, f.SetDefinedName(definedName))
+	}
+	for formula, expected := range map[string][]string{
+		"=A2*TaxRate":          {"Sheet1", "2"},
+		"=Sheet1!A2*TaxRate":   {"Sheet2", "1"},
+		"=A2*taxrate":          {"Sheet1", "2"},
+		"=Greeting&\" World\"": {"Sheet1", "Hello World"},
+		"=Total*2":             {"Sheet1", "12"},
+		"=SUM(Areas)":          {"Sheet1", "14"},
+		"=COUNT(Areas)":        {"Sheet1", "4"},
+		"=MAX(FirstRow)":       {"Sheet1", "3"},
+		"=SUM(FirstRow,Areas)": {"Sheet1", "20"},
+	} {
+		assert.NoError(t, f.SetCellFormula(expected[0], "E1", formula))
+		result, err := f.CalcCellValue(expected[0], "E1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected[1], result, formula)
+	}
+	// Test calculate with defined name refer to itself
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "=Loop"))
+	_, err = f.CalcCellValue("Sheet1", "E1")
+	assert.EqualError(t, err, formulaErrorNAME)
 }
 
 func TestCalcISBLANK(t *testing.T) {
@@ -5463,6 +5542,51 @@ func TestCalcPEARSON(t *testing.T) {
 	}
 }
 
+func TestCalcPROB(t *testing.T) {
+	cellData := [][]interface{}{
+		{"x", "prob"},
+		{0, 0.2},
+		{1, 0.3},
+		{2, 0.1},
+		{3, 0.4},
+		{4, 0},
+		{5, 1.2},
+		{"a", 0.5},
+	}
+	f := prepareCalcData(cellData)
+	formulaList := map[string]string{
+		"=PROB(A2:A5,B2:B5,2)":   "0.1",
+		"=PROB(A2:A5,B2:B5,1,3)": "0.8",
+		"=PROB(A2:A5,B2:B5,3,1)": "0",
+		"=PROB(A2:A5,B2:B5,10)":  "0",
+	}
+	for formula, expected := range formulaList {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	calcError := map[string]string{
+		"=PROB()":                   "PROB requires at least 3 arguments",
+		"=PROB(A2:A5,B2:B5,1,2,3)":  "PROB requires at most 4 arguments",
+		"=PROB(A2:A5,B2:B4,1)":      "#N/A",
+		"=PROB(A2:A5,B2:B5,\"\")":   "strconv.ParseFloat: parsing \"\": invalid syntax",
+		"=PROB(A2:A5,B2:B5,1,\"\")": "strconv.ParseFloat: parsing \"\": invalid syntax",
+		"=PROB(A2:A6,B2:B6,1)":      "#NUM!",
+		"=PROB(A2:A7,B2:B7,1)":      "#NUM!",
+		"=PROB(A2:A3,B2:B3,1)":      "#NUM!",
+		"=PROB(A2:A9,B2:B9,1)":      "#NUM!",
+		"=PROB(A8,B8,1)":            "strconv.ParseFloat: parsing \"a\": invalid syntax",
+		"=PROB(B8,A8,1)":            "strconv.ParseFloat: parsing \"a\": invalid syntax",
+	}
+	for formula, expected := range calcError {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.EqualError(t, err, expected, formula)
+		assert.Equal(t, "", result, formula)
+	}
+}
+
 func TestCalcRSQ(t *testing.T) {
 	cellData := [][]interface{}{
 		{"known_y's", "known_x's"},
@@ -5871,3 +5995,917 @@ func TestCalcColRowQRDecomposition(t *testing.T) {
 	assert.False(t, calcRowQRDecomposition([][]float64{{0, 0}, {0, 0}}, []float64{0, 0}, 1, 0))
 	assert.False(t, calcColQRDecomposition([][]float64{{0, 0}, {0, 0}}, []float64{0, 0}, 1, 0))
 }
+
+func TestCalcArrayFormula(t *testing.T) {
+	cellData := [][]interface{}{
+		{"Name", "Score", "Pass"},
+		{"b", 3, "Y"},
+		{"a", 1, "N"},
+		{"c", 5, "Y"},
+		{"a", 2, "Y"},
+		{"d", 4, "N"},
+	}
+	f := prepareCalcData(cellData)
+	formulaList := map[string][][]string{
+		"=FILTER(A2:A6,C2:C6=\"Y\")":       {{"b"}, {"c"}, {"a"}},
+		"=FILTER(A2:A6,B2:B6>9,\"none\")":  {{"none"}},
+		"=SORT(A2:B6,2,-1)":                {{"c", "5"}, {"d", "4"}, {"b", "3"}, {"a", "2"}, {"a", "1"}},
+		"=SORT(B2:B6,,-1)":                 {{"5"}, {"4"}, {"3"}, {"2"}, {"1"}},
+		"=_xlfn._xlws.SORT(B2:B6)":         {{"1"}, {"2"}, {"3"}, {"4"}, {"5"}},
+		"=UNIQUE(A2:A6)":                   {{"b"}, {"a"}, {"c"}, {"d"}},
+		"=UNIQUE(A2:A6,,TRUE)":             {{"b"}, {"c"}, {"d"}},
+		"=_xlfn.UNIQUE(C2:C6)":             {{"Y"}, {"N"}},
+		"=SORT(UNIQUE(A2:A6))":             {{"a"}, {"b"}, {"c"}, {"d"}},
+		"=SEQUENCE(2,3,10,5)":              {{"10", "15", "20"}, {"25", "30", "35"}},
+		"=SEQUENCE(3)*2":                   {{"2"}, {"4"}, {"6"}},
+		"=B2:B6*2":                         {{"6"}, {"2"}, {"10"}, {"4"}, {"8"}},
+		"=-B2:B3":                          {{"-3"}, {"-1"}},
+		"=SUM(FILTER(B2:B6,C2:C6=\"Y\"))":  {{"10"}},
+		"=SUMPRODUCT((C2:C6=\"Y\")*B2:B6)": {{"10"}},
+		"=XLOOKUP(\"c\",A2:A6,B2:C6)":      {{"5", "Y"}},
+	}
+	for formula, expected := range formulaList {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcArrayFormula("Sheet1", "E1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+		value, err := f.CalcCellValue("Sheet1", "E1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected[0][0], value, formula)
+	}
+	calcError := map[string]string{
+		"=FILTER()":              "FILTER requires 2 or 3 arguments",
+		"=FILTER(A2:A6,B2:B6>9)": "#CALC!",
+		"=FILTER(A2:A6,B2:B3>0)": "#VALUE!",
+		"=SEQUENCE()":            "SEQUENCE requires at least 1 argument and at most 4 arguments",
+		"=SEQUENCE(0)":           "#VALUE!",
+		"=SORT()":                "SORT requires at least 1 argument and at most 4 arguments",
+		"=SORT(A2:A6,3)":         "#VALUE!",
+		"=SORT(A2:A6,1,2)":       "#VALUE!",
+		"=UNIQUE()":              "UNIQUE requires at least 1 argument and at most 3 arguments",
+	}
+	for formula, expected := range calcError {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcArrayFormula("Sheet1", "E1")
+		assert.EqualError(t, err, expected, formula)
+		assert.Empty(t, result, formula)
+	}
+	// Test calculate array formula with not exist worksheet
+	_, err := f.CalcArrayFormula("SheetN", "E1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test calculate array formula with invalid cell reference
+	_, err = f.CalcArrayFormula("Sheet1", "E")
+	assert.Equal(t, newCellNameToCoordinatesError("E", newInvalidCellNameError("E")), err)
+}
+
+func TestCalcSheetsReference(t *testing.T) {
+	f := NewFile()
+	for i, sheet := range []string{"Sheet1", "Sheet2", "Sheet 3", "Sheet4"} {
+		if i > 0 {
+			_, err := f.NewSheet(sheet)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, f.SetSheetRow(sheet, "A1", &[]interface{}{i + 1, (i + 1) * 10}))
+	}
+	formulaList := map[string]string{
+		"='Sheet 3'!A1":                 "3",
+		"=SUM(Sheet1:Sheet4!A1)":        "10",
+		"=SUM(Sheet2:Sheet4!A1:B1)":     "99",
+		"=SUM('Sheet2:Sheet 3'!B1)":     "50",
+		"=COUNT(Sheet4:Sheet2!A1:B1)":   "6",
+		"=MAX(Sheet1:Sheet4!B1)":        "40",
+		"=SUM(Sheet2:Sheet2!A1:B1)":     "22",
+		"=AVERAGE('Sheet1:Sheet 3'!A1)": "2",
+		"=SUM(Sheet1:Sheet4!A1)+A1":     "11",
+	}
+	for formula, expected := range formulaList {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	calcError := map[string]string{
+		"=SheetN!A1":             "#REF!",
+		"=SUM(SheetN!A1:B1)":     "#REF!",
+		"=SUM(Sheet1:SheetN!A1)": "#REF!",
+		"=[Book2.xlsx]Sheet1!A1": "#REF!",
+		"=SUM([1]Sheet1!A1:A2)":  "#REF!",
+	}
+	for formula, expected := range calcError {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.EqualError(t, err, expected, formula)
+		assert.Equal(t, "", result, formula)
+	}
+	// Test calculate external workbook references with the cached values
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=[1]Data!A1+[Book2.xlsx]Data!A2"))
+	f.Pkg.Store("xl/externalLinks/externalLink1.xml", []byte(`<externalLink xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><externalBook r:id="rId1"><sheetNames><sheetName val="Sheet1"/><sheetName val="Data"/></sheetNames><sheetDataSet><sheetData sheetId="1"><row r="1"><cell r="A1"><v>5</v></cell><cell r="B1" t="s"><v>Name</v></cell><cell r="C1" t="b"><v>1</v></cell></row><row r="2"><cell r="A2" t="n"><v>7</v></cell></row></sheetData><sheetData sheetId="2"/></sheetDataSet></externalBook></externalLink>`))
+	f.Pkg.Store("xl/externalLinks/_rels/externalLink1.xml.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/externalLinkPath" Target="file:///C:\dir\Book2.xlsx" TargetMode="External"/></Relationships>`))
+	rID := f.addRels(f.getWorkbookRelsPath(), "http://schemas.openxmlformats.org/officeDocument/2006/relationships/externalLink", "externalLinks/externalLink1.xml", "")
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.ExternalReferences = &xlsxExternalReferences{ExternalReference: []xlsxExternalReference{{RID: "rId" + strconv.Itoa(rID)}}}
+	result, err := f.CalcCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "12", result)
+	for formula, expected := range map[string]string{
+		"=[1]Data!B1":      "Name",
+		"=AND([1]Data!C1)": "TRUE",
+		"=[1]Sheet1!A1":    "",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for _, formula := range []string{"=[2]Data!A1", "=[1]SheetN!A1", "=[Book3.xlsx]Data!A1"} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		_, err := f.CalcCellValue("Sheet1", "C1")
+		assert.EqualError(t, err, "#REF!", formula)
+	}
+	// Test calculate external workbook references with the external workbook resolver
+	ext := NewFile()
+	assert.NoError(t, ext.SetSheetRow("Sheet1", "A1", &[]interface{}{100, 200}))
+	assert.NoError(t, ext.SetCellFormula("Sheet1", "C1", "=A1+B1"))
+	var targets []string
+	f.ExternalWorkbookResolver(func(workbook string) (*File, error) {
+		targets = append(targets, workbook)
+		return ext, nil
+	})
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=SUM([Book2.xlsx]Sheet1!A1:C1)+[1]Sheet1!A1+'C:\\dir\\[Book2.xlsx]Sheet1'!A1"))
+	result, err = f.CalcCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "800", result)
+	assert.Equal(t, []string{"Book2.xlsx", `file:///C:\dir\Book2.xlsx`, `C:\dir\Book2.xlsx`}, targets)
+	// Test calculate again with the cached external workbooks
+	result, err = f.CalcCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "800", result)
+	assert.Len(t, targets, 3)
+	assert.False(t, ext.closed)
+	f.ExternalWorkbookResolver(func(workbook string) (*File, error) {
+		return nil, ErrParameterInvalid
+	})
+	assert.True(t, ext.closed)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=SUM([Book2.xlsx]Sheet1!A1:C1)"))
+	_, err = f.CalcCellValue("Sheet1", "C1")
+	assert.Equal(t, ErrParameterInvalid, err)
+	// Test calculate external workbook references with unsupported charset external link
+	f.ExternalWorkbookResolver(nil)
+	f.Pkg.Store("xl/externalLinks/externalLink1.xml", MacintoshCyrillicCharset)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=SUM([1]Data!A1)"))
+	_, err = f.CalcCellValue("Sheet1", "C1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestCalcSubtotalHiddenRows(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Amount"))
+	for row, value := range []int{10, 20, 30, 40, 50, 60} {
+		assert.NoError(t, f.SetCellValue("Sheet1", "A"+strconv.Itoa(row+2), value))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A8", "=SUBTOTAL(9,A2:A7)"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A9", 100))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A10", formulaErrorDIV))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[9].C[0].T, ws.SheetData.Row[9].C[0].V = "e", formulaErrorDIV
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1:A7", nil))
+	// Row 3 is hidden by the auto filter, and row 9 is hidden manually
+	assert.NoError(t, f.SetRowVisible("Sheet1", 3, false))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 9, false))
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	for formula, expected := range map[string]string{
+		"=A8":                             "190",
+		"=SUM(A2:A9)":                     "500",
+		"=SUBTOTAL(9,A2:A9)":              "290",
+		"=SUBTOTAL(109,A2:A9)":            "190",
+		"=SUBTOTAL(102,A2:A9)":            "5",
+		"=SUBTOTAL(9,A3)":                 "0",
+		"=SUBTOTAL(9,A3,A4)":              "30",
+		"=SUBTOTAL(109,Sheet2!A1,A2:A9)":  "190",
+		"=_xlfn.AGGREGATE(9,0,A2:A9)":     "310",
+		"=_xlfn.AGGREGATE(9,1,A2:A9)":     "190",
+		"=_xlfn.AGGREGATE(9,4,A2:A9)":     "500",
+		"=_xlfn.AGGREGATE(9,5,A2:A9)":     "380",
+		"=_xlfn.AGGREGATE(3,4,A2:A10)":    "9",
+		"=_xlfn.AGGREGATE(3,6,A2:A10)":    "8",
+		"=_xlfn.AGGREGATE(9,4,A10,A2)":    "#DIV/0!",
+		"=_xlfn.AGGREGATE(9,6,A10,A2)":    "10",
+		"=_xlfn.AGGREGATE(14,3,A2:A10,1)": "60",
+		"=_xlfn.AGGREGATE(14,3,A2:A10,2)": "50",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "B1", formula))
+		result, err := f.CalcCellValue("Sheet1", "B1")
+		if expected == formulaErrorDIV {
+			assert.EqualError(t, err, expected, formula)
+			continue
+		}
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	// Test calculate SUBTOTAL across the worksheets
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "=SUBTOTAL(109,Sheet1!A2:A9)"))
+	result, err := f.CalcCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "190", result)
+	// Test the ignored cells of the worksheet are collected once for the
+	// arguments on the same worksheet
+	fn := &formulaFuncs{f: f, sheet: "Sheet1"}
+	filter := &subtotalFilter{hiddenRows: true}
+	arg := newNumberFormulaArg(20)
+	arg.cellRefs = list.New()
+	arg.cellRefs.PushBack(cellRef{Col: 1, Row: 3, Sheet: "Sheet1"})
+	_, ok := fn.subtotalFilterArg(arg, filter)
+	assert.False(t, ok)
+	assert.Len(t, filter.sheets, 1)
+	filter.sheets["Sheet1"].hiddenRows[3] = false
+	_, ok = fn.subtotalFilterArg(arg, filter)
+	assert.True(t, ok)
+}
+
+func TestCalcErrorCellReference(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", formulaErrorDIV))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[1].C[0].T, ws.SheetData.Row[1].C[0].V = "e", formulaErrorDIV
+	for formula, expected := range map[string]string{
+		"=ISERROR(A2)":    "TRUE",
+		"=ISERR(A2)":      "TRUE",
+		"=ISTEXT(A2)":     "FALSE",
+		"=IFERROR(A2,0)":  "0",
+		"=ERROR.TYPE(A2)": "2",
+		"=COUNTA(A2)":     "1",
+		"=COUNTA(A1:A2)":  "2",
+		"=A2+1":           "#DIV/0!",
+		"=SUM(A1,A2)":     "#DIV/0!",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "B1", formula))
+		result, err := f.CalcCellValue("Sheet1", "B1")
+		if expected == formulaErrorDIV {
+			assert.EqualError(t, err, expected, formula)
+			continue
+		}
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestCalcIterativeCalculation(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "A1*0.5+10"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "C1+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "B1/2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D1", 5))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", "SUM(D1,D1*2)"))
+	// Test calculate circular references without iterative calculation
+	result, err := f.CalcCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", result)
+	// Test calculate circular references with iterative calculation
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{Iterate: boolPtr(true)}))
+	for cell, expected := range map[string]float64{"A1": 20, "B1": 2, "C1": 1, "D2": 15} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err, cell)
+		val, err := strconv.ParseFloat(result, 64)
+		assert.NoError(t, err, cell)
+		assert.InDelta(t, expected, val, 0.001, cell)
+	}
+	// Test calculate with maximum iterations count
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{IterateCount: uintPtr(3)}))
+	result, err = f.CalcCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "17.5", result)
+	// Test calculate with maximum change between iterations
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{IterateCount: uintPtr(100), IterateDelta: float64Ptr(0.5)}))
+	result, err = f.CalcCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "19.6875", result)
+	// Test calculate with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.CalcCellValue("Sheet1", "A1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestCalcAll(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet 2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
+	for cell, formula := range map[string]string{
+		"C1": "B1*2",
+		"B1": "'Sheet 2'!A1+A1",
+		"D1": "1/0",
+		"E1": "\"a\"&C1",
+		"F1": "C1>1",
+		"G1": "SUM(A1:C1)",
+		"H1": "AND(F1,TRUE)",
+		"I1": "Z1",
+		"J1": "J1+1",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet 2", "A1", "Sheet1!$A$1*10"))
+	assert.NoError(t, f.SetCellFormula("Sheet 2", "A2", "A1+1", FormulaOpts{Type: stringPtr(STCellFormulaTypeShared), Ref: stringPtr("A2:A3")}))
+	assert.NoError(t, f.CalcAll())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCalcAll.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestCalcAll.xlsx"))
+	assert.NoError(t, err)
+	for _, c := range []struct {
+		sheet, cell, value string
+		cellType           CellType
+	}{
+		{"Sheet1", "B1", "22", CellTypeUnset},
+		{"Sheet1", "C1", "44", CellTypeUnset},
+		{"Sheet1", "D1", "#DIV/0!", CellTypeError},
+		{"Sheet1", "E1", "a44", CellTypeFormula},
+		{"Sheet1", "F1", "TRUE", CellTypeBool},
+		{"Sheet1", "G1", "68", CellTypeUnset},
+		{"Sheet1", "H1", "TRUE", CellTypeBool},
+		{"Sheet1", "I1", "0", CellTypeUnset},
+		{"Sheet 2", "A1", "20", CellTypeUnset},
+		{"Sheet 2", "A2", "21", CellTypeUnset},
+		{"Sheet 2", "A3", "22", CellTypeUnset},
+	} {
+		value, err := f.GetCellValue(c.sheet, c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.value, value, c.cell)
+		cellType, err := f.GetCellType(c.sheet, c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.cellType, cellType, c.cell)
+	}
+	// Test recalculate with iterative calculation
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{Iterate: boolPtr(true), IterateCount: uintPtr(10)}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "J1", "J1*0.5+10"))
+	assert.NoError(t, f.CalcAll())
+	value, err := f.GetCellValue("Sheet1", "J1")
+	assert.NoError(t, err)
+	result, err := strconv.ParseFloat(value, 64)
+	assert.NoError(t, err)
+	assert.InDelta(t, 20, result, 0.1)
+	// Test recalculate with unsupported function
+	assert.NoError(t, f.SetCellFormula("Sheet1", "K1", "UNSUPPORTED()"))
+	assert.EqualError(t, f.CalcAll(), "not support UNSUPPORTED function")
+	value, err = f.GetCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "44", value)
+	assert.NoError(t, f.Close())
+	// Test recalculate with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.CalcAll(), "XML syntax error on line 1: invalid UTF-8")
+	// Test recalculate with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.CalcAll(), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestCalcAllWithReport(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
+	for cell, formula := range map[string]string{
+		"B1": "A1*2",
+		"C1": "A1/0",
+		"D1": "NA()",
+		"E1": "UNSUPPORTED()",
+		"F1": "C1+1",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	report, err := f.CalcAllWithReport()
+	assert.NoError(t, err)
+	assert.Equal(t, []CalcError{
+		{Sheet: "Sheet1", Cell: "C1", Formula: "A1/0", Value: "#DIV/0!"},
+		{Sheet: "Sheet1", Cell: "D1", Formula: "NA()", Value: "#N/A"},
+		{Sheet: "Sheet1", Cell: "E1", Formula: "UNSUPPORTED()", Err: errors.New("not support UNSUPPORTED function")},
+		{Sheet: "Sheet1", Cell: "F1", Formula: "C1+1", Value: "#DIV/0!"},
+	}, report)
+	for cell, expected := range map[string]string{"B1": "4", "C1": "#DIV/0!", "D1": "#N/A", "E1": "", "F1": "#DIV/0!"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	assert.NoError(t, f.Close())
+	// Test recalculate with report without errors
+	f = NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "1+1"))
+	report, err = f.CalcAllWithReport()
+	assert.NoError(t, err)
+	assert.Empty(t, report)
+	// Test recalculate with report with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.CalcAllWithReport()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestCalcErrorOptions(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "abc"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 2))
+	formulas := map[string]string{
+		"C1": "A1",
+		"C2": "A1=0",
+		"C3": "A1<1",
+		"C4": "B1+1",
+		"C5": "B1*B2",
+		"C6": "B1:B2*2",
+		"C7": "B1&\"d\"",
+		"C8": "A1=\"\"",
+	}
+	for cell, formula := range formulas {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	for cell, expected := range map[string]string{"C1": "", "C2": "FALSE", "C6": "#VALUE!", "C7": "abcd", "C8": "TRUE"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err, cell)
+		assert.Equal(t, expected, result, cell)
+	}
+	_, err := f.CalcCellValue("Sheet1", "C4")
+	assert.EqualError(t, err, "strconv.ParseFloat: parsing \"abc\": invalid syntax")
+	// Test calculate with the blank cells and the text values as zero
+	f.options.CalcBlankAsZero, f.options.CalcTextAsZero = true, true
+	for cell, expected := range map[string]string{
+		"C1": "0", "C2": "TRUE", "C3": "TRUE", "C4": "1", "C5": "0", "C6": "0", "C7": "abcd", "C8": "TRUE",
+	} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err, cell)
+		assert.Equal(t, expected, result, cell)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestRecalculate(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{2, nil, nil, nil, 1}))
+	for cell, formula := range map[string]string{
+		"B1": "A1*2",
+		"C1": "B1+1",
+		"D1": "E1*3",
+		"F1": "D1+C1",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!A1+1"))
+	assert.NoError(t, f.CalcAll())
+	// Test recalculate without changed cells
+	assert.NoError(t, f.Recalculate())
+	checkValues := func(expected map[string]string) {
+		for ref, value := range expected {
+			parts := strings.Split(ref, "!")
+			result, err := f.GetCellValue(parts[0], parts[1])
+			assert.NoError(t, err)
+			assert.Equal(t, value, result, ref)
+		}
+	}
+	checkValues(map[string]string{"Sheet1!B1": "4", "Sheet1!C1": "5", "Sheet1!D1": "3", "Sheet1!F1": "8", "Sheet2!A1": "3"})
+	// Test recalculate only the formulas affected by the changed cell, the
+	// cached value of the unaffected formula will be used
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[3].V = "100"
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 10))
+	assert.NoError(t, f.Recalculate())
+	checkValues(map[string]string{"Sheet1!B1": "20", "Sheet1!C1": "21", "Sheet1!D1": "100", "Sheet1!F1": "121", "Sheet2!A1": "11"})
+	// Test recalculate with changed formula
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "E1*4"))
+	assert.NoError(t, f.Recalculate())
+	checkValues(map[string]string{"Sheet1!D1": "4", "Sheet1!F1": "25"})
+	// Test recalculate with iterative calculation
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{Iterate: boolPtr(true)}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", 2))
+	assert.NoError(t, f.Recalculate())
+	checkValues(map[string]string{"Sheet1!D1": "8", "Sheet1!F1": "29"})
+	// Test recalculate with unsupported function
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G1", "UNSUPPORTED()"))
+	assert.EqualError(t, f.Recalculate(), "not support UNSUPPORTED function")
+	assert.NoError(t, f.Close())
+	// Test recalculate with unsupported charset workbook
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Recalculate(), "XML syntax error on line 1: invalid UTF-8")
+	// Test recalculate with unsupported charset worksheet
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Recalculate(), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestRecalculateTracking(t *testing.T) {
+	f := NewFile()
+	countDirtyCells := func() int {
+		var count int
+		f.dirtyCells.Range(func(_, _ interface{}) bool {
+			count++
+			return true
+		})
+		return count
+	}
+	// Test the changed cells will not be tracked before the first calculation
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 2}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "A1+B1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "Total*2"))
+	assert.Equal(t, 0, countDirtyCells())
+	assert.Nil(t, f.formulaGraph)
+	// Test the first recalculation calculates all formulas
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$C$1"}))
+	assert.NoError(t, f.Recalculate())
+	for cell, expected := range map[string]string{"C1": "3", "D1": "6"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	graph := f.formulaGraph
+	assert.NotNil(t, graph)
+	// Test reuse the dependency graph for the unchanged formulas
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 10))
+	assert.Equal(t, 1, countDirtyCells())
+	assert.NoError(t, f.Recalculate())
+	assert.Equal(t, 0, countDirtyCells())
+	assert.True(t, graph == f.formulaGraph)
+	val, err := f.GetCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "24", val)
+	// Test rebuild the dependency graph with changed formula and defined name
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "A1-B1"))
+	assert.NoError(t, f.Recalculate())
+	assert.False(t, graph == f.formulaGraph)
+	graph = f.formulaGraph
+	_, err = f.SetDefinedNames([]DefinedName{{Name: "Total", RefersTo: "Sheet1!$A$1"}}, DefinedNameConflictOverwrite)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 5))
+	assert.NoError(t, f.Recalculate())
+	assert.False(t, graph == f.formulaGraph)
+	val, err = f.GetCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", val)
+	// Test invalidate the dependency graph on adding table
+	assert.NoError(t, f.AddTable("Sheet1", "F1:G2", nil))
+	assert.Nil(t, f.formulaGraph)
+	assert.NoError(t, f.Close())
+}
+
+func TestFreezeSheetValues(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet 2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
+	for cell, formula := range map[string]string{
+		"B1": "'Sheet 2'!A1+A1",
+		"C1": "\"a\"&B1",
+		"D1": "B1>1",
+		"E1": "1/0",
+		"F1": "A1*3",
+		"G1": "\"1\"",
+		"H1": "IF(TRUE,\"TRUE\")",
+		"I1": "SUM(A1,1)",
+		"J1": "NOT(FALSE)",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet 2", "A1", "Sheet1!A1*10"))
+	style, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	// Test freeze values with the cached value of the formula cell
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData.Row[0].C[5].T, ws.(*xlsxWorksheet).SheetData.Row[0].C[5].V = "", "100"
+	assert.NoError(t, f.FreezeSheetValues("Sheet1"))
+	for _, c := range []struct {
+		cell, value string
+		cellType    CellType
+	}{
+		{"B1", "22.00", CellTypeUnset},
+		{"C1", "a22", CellTypeSharedString},
+		{"D1", "TRUE", CellTypeBool},
+		{"E1", "#DIV/0!", CellTypeError},
+		{"F1", "100", CellTypeUnset},
+		{"G1", "1", CellTypeSharedString},
+		{"H1", "TRUE", CellTypeSharedString},
+		{"I1", "3", CellTypeUnset},
+		{"J1", "TRUE", CellTypeBool},
+	} {
+		value, err := f.GetCellValue("Sheet1", c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.value, value, c.cell)
+		cellType, err := f.GetCellType("Sheet1", c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.cellType, cellType, c.cell)
+		formula, err := f.GetCellFormula("Sheet1", c.cell)
+		assert.NoError(t, err)
+		assert.Empty(t, formula, c.cell)
+	}
+	styleID, err := f.GetCellStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	formula, err := f.GetCellFormula("Sheet 2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet1!A1*10", formula)
+	// Test freeze values in all worksheets of the workbook
+	assert.NoError(t, f.FreezeWorkbookValues())
+	formula, err = f.GetCellFormula("Sheet 2", "A1")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+	value, err := f.GetCellValue("Sheet 2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "20", value)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestFreezeSheetValues.xlsx")))
+	// Test freeze values with unsupported function
+	assert.NoError(t, f.SetCellFormula("Sheet1", "K1", "UNSUPPORTED()"))
+	assert.EqualError(t, f.FreezeSheetValues("Sheet1"), "not support UNSUPPORTED function")
+	formula, err = f.GetCellFormula("Sheet1", "K1")
+	assert.NoError(t, err)
+	assert.Equal(t, "UNSUPPORTED()", formula)
+	// Test freeze values on not exists worksheet
+	assert.EqualError(t, f.FreezeSheetValues("SheetN"), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+	// Test freeze values with unsupported charset worksheet
+	f = NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "1+1"))
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = nil
+	assert.EqualError(t, f.FreezeWorkbookValues(), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestParseFormulaDependency(t *testing.T) {
+	for ref, expected := range map[string]formulaDependency{
+		"A1":            {sheet: "Sheet1", coordinates: []int{1, 1, 1, 1}},
+		"$B$2:A1":       {sheet: "Sheet1", coordinates: []int{1, 1, 2, 2}},
+		"'It''s'!A:C":   {sheet: "It's", coordinates: []int{1, 1, 3, TotalRows}},
+		"Sheet2!2:3":    {sheet: "Sheet2", coordinates: []int{1, 2, MaxColumns, 3}},
+		" Sheet2!A1:C3": {sheet: "Sheet2", coordinates: []int{1, 1, 3, 3}},
+	} {
+		dep, ok := parseFormulaDependency("Sheet1", ref)
+		assert.True(t, ok, ref)
+		assert.Equal(t, expected, dep, ref)
+	}
+	for _, ref := range []string{"Sheet1:Sheet2!A1", "[1]Sheet1!A1", "A1:B2:C3", "A1:*"} {
+		_, ok := parseFormulaDependency("Sheet1", ref)
+		assert.False(t, ok, ref)
+	}
+}
+
+func TestRegisterCalcFunc(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{200, 150}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{1, 2}))
+	assert.NoError(t, f.RegisterCalcFunc("margin", func(args ...FormulaArg) FormulaArg {
+		if len(args) != 2 || args[0].Type != ArgNumber || args[1].Type != ArgNumber {
+			return FormulaArg{Type: ArgError, Error: formulaErrorVALUE}
+		}
+		return FormulaArg{Type: ArgNumber, Number: (args[0].Number - args[1].Number) / args[0].Number}
+	}))
+	assert.NoError(t, f.RegisterCalcFunc("MY.TOTAL", func(args ...FormulaArg) FormulaArg {
+		var total float64
+		for _, arg := range args {
+			for _, row := range arg.Matrix {
+				for _, cell := range row {
+					total += cell.Number
+				}
+			}
+		}
+		return FormulaArg{Type: ArgNumber, Number: total}
+	}))
+	for formula, expected := range map[string]string{
+		"MARGIN(A1,B1)":          "0.25",
+		"Margin(A1,B1)*100":      "25",
+		"SUM(MARGIN(A1,B1),1)":   "1.25",
+		"MY.TOTAL(A1:B2)":        "353",
+		"_xludf.MY.TOTAL(A2:B2)": "3",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	// Test calculate custom function with error result
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "MARGIN(A1)"))
+	result, err := f.CalcCellValue("Sheet1", "C1")
+	assert.EqualError(t, err, formulaErrorVALUE)
+	assert.Empty(t, result)
+	// Test remove the custom function
+	assert.NoError(t, f.RegisterCalcFunc("MARGIN", nil))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "MARGIN(A1,B1)"))
+	_, err = f.CalcCellValue("Sheet1", "C1")
+	assert.EqualError(t, err, "not support MARGIN function")
+	// Test register custom function with invalid name
+	assert.Equal(t, ErrParameterRequired, f.RegisterCalcFunc(" ", nil))
+	assert.EqualError(t, f.RegisterCalcFunc("sum", nil), newBuiltInFunctionError("SUM").Error())
+	assert.EqualError(t, f.RegisterCalcFunc("NORM.DIST", nil), newBuiltInFunctionError("NORM.DIST").Error())
+	// Test convert the custom function arguments
+	arg := FormulaArg{Type: ArgList, List: []FormulaArg{{Type: ArgError, Error: formulaErrorNA}}}
+	assert.Equal(t, arg, newFormulaArg(arg.toFormulaArg()))
+	assert.Equal(t, formulaErrorNA, arg.List[0].Value())
+}
+
+func TestCalcVolatileFunctions(t *testing.T) {
+	f := NewFile()
+	f.options.CalcClock = func() time.Time {
+		return time.Date(2022, 5, 20, 12, 0, 0, 0, time.UTC)
+	}
+	f.options.CalcRand = func() float64 { return 0.5 }
+	for formula, expected := range map[string]string{
+		"NOW()":              "44701.5",
+		"TODAY()":            "44701",
+		"HOUR(NOW())":        "12",
+		"YEAR(TODAY())":      "2022",
+		"RAND()":             "0.5",
+		"RANDBETWEEN(1,10)":  "6",
+		"RANDBETWEEN(-5,-5)": "-5",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A1", formula))
+		result, err := f.CalcCellValue("Sheet1", "A1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	// Test random number generator returns a number out of range
+	for _, num := range []float64{1, -0.5, math.NaN()} {
+		f.options.CalcRand = func() float64 { return num }
+		for _, formula := range []string{"RAND()", "RANDBETWEEN(1,1)"} {
+			assert.NoError(t, f.SetCellFormula("Sheet1", "A1", formula))
+			result, err := f.CalcCellValue("Sheet1", "A1")
+			assert.EqualError(t, err, formulaErrorNUM, formula)
+			assert.Equal(t, "", result, formula)
+		}
+	}
+}
+
+func TestCalcStructuredReference(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sales Data")
+	assert.NoError(t, err)
+	for r, row := range [][]interface{}{{"Item", "Unit Price", "Qty#"}, {"A", 2, 3}, {"B", 4, 5}, {"C", 6, 7}} {
+		assert.NoError(t, f.SetSheetRow("Sales Data", "A"+strconv.Itoa(r+1), &row))
+	}
+	assert.NoError(t, f.AddTable("Sales Data", "A1:C4", &TableOptions{Name: "Sales"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Quantity", RefersTo: "Sales[Qty'#]"}))
+	for formula, expected := range map[string]string{
+		"SUM(Sales[Qty'#])":                    "15",
+		"SUM(sales[[Unit Price]:[Qty'#]])":     "27",
+		"SUM(Sales)":                           "27",
+		"SUM(Sales[])":                         "27",
+		"SUM(Sales[#Data])":                    "27",
+		"ROWS(Sales[#All])":                    "4",
+		"COUNTA(Sales[#Headers])":              "3",
+		"INDEX(Sales[[#Headers],[Item]],1)":    "Item",
+		"ROWS(Sales[[#Headers],[#Data]])":      "4",
+		"SUM(Quantity)":                        "15",
+		`"[x]"&Sales[[#Headers],[Unit Price]]`: "[x]Unit Price",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A1", formula))
+		result, err := f.CalcCellValue("Sheet1", "A1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	// Test calculate the structured references with the current row
+	for formula, expected := range map[string]string{
+		"Sales[@Item]":                      "B",
+		"Sales[@]":                          "B",
+		"[@[Unit Price]]*[@[Qty'#]]":        "20",
+		"Sales[[#This Row],[Unit Price]]":   "4",
+		"SUM(Sales[@[Unit Price]:[Qty'#]])": "9",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sales Data", "C3", formula))
+		result, err := f.CalcCellValue("Sales Data", "C3")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for formula, expected := range map[string]string{
+		"Sales[#Totals]":     formulaErrorREF,
+		"Sales[#Unknown]":    formulaErrorREF,
+		"SUM(Sales[Amount])": formulaErrorREF,
+		"Sales[@Item]":       formulaErrorVALUE,
+		"[@Item]":            formulaErrorNAME,
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A1", formula))
+		result, err := f.CalcCellValue("Sheet1", "A1")
+		assert.EqualError(t, err, expected, formula)
+		assert.Empty(t, result, formula)
+	}
+	// Test recalculate the workbook with the structured references
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "SUM(Sales[Qty'#])"))
+	assert.NoError(t, f.SetCellFormula("Sales Data", "C3", "[@[Unit Price]]*10"))
+	assert.NoError(t, f.CalcAll())
+	result, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "50", result)
+	// Test calculate the cell and table references without loading the other
+	// worksheets
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 50))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "A1"))
+	result, err = f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "50", result)
+	_, ok := f.Sheet.Load("xl/worksheets/sheet2.xml")
+	assert.False(t, ok)
+	tables, err := f.getSheetTablesByRels("Sales Data")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	_, ok = f.Sheet.Load("xl/worksheets/sheet2.xml")
+	assert.False(t, ok)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "SUM(Sales)"))
+	result, err = f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "62", result)
+	assert.NoError(t, f.Close())
+	// Test check the cell and range references
+	for reference, expected := range map[string]bool{
+		"A1": true, "Sheet1!$A$1:$B$2": true, "A:B": true, "1:2": true,
+		"Sales": false, "ABC": false, "A1:B2:C3": false, "A:0": false,
+	} {
+		assert.Equal(t, expected, isCalcRangeReference(reference), reference)
+	}
+	// Test get the tables by the relationships with invalid worksheet
+	f = NewFile()
+	_, err = f.getSheetTablesByRels("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	f.Pkg.Store("xl/worksheets/_rels/sheet1.xml.rels", MacintoshCyrillicCharset)
+	_, err = f.getSheetTablesByRels("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	f.Pkg.Store("xl/worksheets/_rels/sheet1.xml.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="`+SourceRelationshipTable+`" Target="../tables/table1.xml"/><Relationship Id="rId2" Type="`+SourceRelationshipTable+`" Target="../tables/table2.xml"/></Relationships>`))
+	f.Relationships.Delete("xl/worksheets/_rels/sheet1.xml.rels")
+	f.Pkg.Store("xl/tables/table2.xml", MacintoshCyrillicCharset)
+	_, err = f.getSheetTablesByRels("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+
+func TestCalcGETPIVOTDATA(t *testing.T) {
+	f := NewFile()
+	for r, row := range [][]interface{}{
+		{"Month", "Year", "Type", "Sales", "Region"},
+		{"Jan", 2017, "Meat", 100, "East"},
+		{"Jan", 2017, "Dairy", 50, "West"},
+		{"Feb", 2018, "Meat", 30, "East"},
+		{"Feb", 2017, "Meat", 7, "West"},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(r+1), &row))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$E$5",
+		PivotTableRange: "Sheet1!$G$2:$M$20",
+		Rows:            []PivotTableField{{Data: "Month"}, {Data: "Year"}},
+		Columns:         []PivotTableField{{Data: "Type"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Sum", Name: "Summarize by Sum"}},
+		Filter:          []PivotTableField{{Data: "Region"}},
+	}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Source", RefersTo: "Sheet1!$A$1:$E$5"}))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Source",
+		PivotTableRange: "Sheet1!$G$30:$M$40",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Average"}},
+	}))
+	for formula, expected := range map[string]string{
+		`GETPIVOTDATA("Sales",$G$2)`:                         "187",
+		`GETPIVOTDATA("Summarize by Sum",H3,"Month","jan")`:  "150",
+		`GETPIVOTDATA("Sales",H3,"Year",2017,"Type","Meat")`: "107",
+		`GETPIVOTDATA("Sales",G2:H3,"Region","East")`:        "130",
+		`GETPIVOTDATA("Sales",G30,"Month","Feb")`:            "18.5",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A10", formula))
+		result, err := f.CalcCellValue("Sheet1", "A10")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for formula, expected := range map[string]string{
+		`GETPIVOTDATA("Sales")`:                   "GETPIVOTDATA requires at least 2 arguments",
+		`GETPIVOTDATA("Sales",G2,"Month")`:        "GETPIVOTDATA requires the pairs of field and item arguments",
+		`GETPIVOTDATA("Sales","G2")`:              formulaErrorREF,
+		`GETPIVOTDATA("Sales",A1)`:                formulaErrorREF,
+		`GETPIVOTDATA("Amount",G2)`:               formulaErrorREF,
+		`GETPIVOTDATA("Sales",G2,"Month","Mar")`:  formulaErrorREF,
+		`GETPIVOTDATA("Sales",G30,"Year",2017)`:   formulaErrorREF,
+		`GETPIVOTDATA("Sales",G2,"Quarter","Q1")`: formulaErrorREF,
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A10", formula))
+		result, err := f.CalcCellValue("Sheet1", "A10")
+		assert.EqualError(t, err, expected, formula)
+		assert.Empty(t, result, formula)
+	}
+}
+
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -253,6 +254,7 @@ func (f *File) setCellTimeFunc(sheet, cell string, value time.Time) error {
 	if isNum, err = c.setCellTime(value, date1904); err != nil {
 		return err
 	}
+	f.markCellDirty(sheet, c.R)
 	if isNum {
 		_ = f.setDefaultTimeStyle(sheet, cell, 22)
 	}
@@ -300,6 +302,7 @@ func (f *File) SetCellInt(sheet, cell string, value int) error {
 	c.S = f.prepareCellStyle(ws, col, row, c.S)
 	c.T, c.V = setCellInt(value)
 	c.IS = nil
+	f.markCellDirty(sheet, c.R)
 	return f.removeFormula(c, ws, sheet)
 }
 
@@ -326,6 +329,7 @@ func (f *File) SetCellBool(sheet, cell string, value bool) error {
 	c.S = f.prepareCellStyle(ws, col, row, c.S)
 	c.T, c.V = setCellBool(value)
 	c.IS = nil
+	f.markCellDirty(sheet, c.R)
 	return f.removeFormula(c, ws, sheet)
 }
 
@@ -363,6 +367,7 @@ func (f *File) SetCellFloat(sheet, cell string, value float64, precision, bitSiz
 	c.S = f.prepareCellStyle(ws, col, row, c.S)
 	c.T, c.V = setCellFloat(value, precision, bitSize)
 	c.IS = nil
+	f.markCellDirty(sheet, c.R)
 	return f.removeFormula(c, ws, sheet)
 }
 
@@ -391,6 +396,7 @@ func (f *File) SetCellStr(sheet, cell, value string) error {
 		return err
 	}
 	c.IS = nil
+	f.markCellDirty(sheet, c.R)
 	return f.removeFormula(c, ws, sheet)
 }
 
@@ -456,6 +462,31 @@ func (f *File) setSharedString(val string) (int, error) {
 	return sst.UniqueCount - 1, nil
 }
 
+// addSharedString provides a function to append the string to the shared
+// string table without updating the deduplication map of the workbook, and
+// returns the index of the string. The existing index will be returned if the
+// string exists in the shared string table before.
+func (f *File) addSharedString(val string) (int, error) {
+	if err := f.sharedStringsLoader(); err != nil {
+		return 0, err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return 0, err
+	}
+	f.Lock()
+	defer f.Unlock()
+	if i, ok := f.sharedStringsMap[val]; ok {
+		return i, nil
+	}
+	sst.Count++
+	sst.UniqueCount++
+	t := xlsxT{Val: val}
+	t.Val, t.Space = trimCellValue(val)
+	sst.SI = append(sst.SI, xlsxSI{T: &t})
+	return len(sst.SI) - 1, nil
+}
+
 // trimCellValue provides a function to set string type to cell.
 func trimCellValue(value string) (v string, ns xml.Attr) {
 	if len(value) > TotalCellChars {
@@ -605,13 +636,26 @@ func (f *File) SetCellDefault(sheet, cell, value string) error {
 	defer ws.Unlock()
 	c.S = f.prepareCellStyle(ws, col, row, c.S)
 	c.setCellDefault(value)
+	f.markCellDirty(sheet, c.R)
 	return f.removeFormula(c, ws, sheet)
 }
 
 // GetCellFormula provides a function to get formula from cell by given
-// worksheet name and cell reference in spreadsheet.
-func (f *File) GetCellFormula(sheet, cell string) (string, error) {
-	return f.getCellStringFunc(sheet, cell, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
+// worksheet name and cell reference in spreadsheet. The formula was returned
+// in A1 reference style by default, specify the "RefMode" of the optional
+// formula settings as "R1C1" to get the formula in R1C1 reference style. For
+// example, get the formula of the cell "C1" on "Sheet1" in R1C1 reference
+// style:
+//
+//	refMode := "R1C1"
+//	formula, err := f.GetCellFormula("Sheet1", "C1",
+//	    excelize.FormulaOpts{RefMode: &refMode})
+func (f *File) GetCellFormula(sheet, cell string, opts ...FormulaOpts) (string, error) {
+	refMode, err := getFormulaRefMode(opts...)
+	if err != nil {
+		return "", err
+	}
+	formula, err := f.getCellStringFunc(sheet, cell, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
 		if c.F == nil {
 			return "", false, nil
 		}
@@ -620,12 +664,32 @@ func (f *File) GetCellFormula(sheet, cell string) (string, error) {
 		}
 		return c.F.Content, true, nil
 	})
+	if err != nil || formula == "" || refMode != "R1C1" {
+		return formula, err
+	}
+	return FormulaA1ToR1C1(formula, cell)
 }
 
 // FormulaOpts can be passed to SetCellFormula to use other formula types.
 type FormulaOpts struct {
-	Type *string // Formula type
-	Ref  *string // Shared formula ref
+	Type    *string // Formula type
+	Ref     *string // Shared formula ref
+	RefMode *string // Formula reference style, "A1" or "R1C1"
+}
+
+// getFormulaRefMode returns the reference style of the formula by given
+// formula settings, the default reference style is "A1".
+func getFormulaRefMode(opts ...FormulaOpts) (string, error) {
+	refMode := "A1"
+	for _, opt := range opts {
+		if opt.RefMode != nil {
+			if inStrSlice(supportedRefMode, *opt.RefMode, true) == -1 {
+				return refMode, newInvalidOptionalValue("RefMode", *opt.RefMode, supportedRefMode)
+			}
+			refMode = *opt.RefMode
+		}
+	}
+	return refMode, nil
 }
 
 // SetCellFormula provides a function to set formula on the cell is taken
@@ -710,7 +774,27 @@ type FormulaOpts struct {
 //	        fmt.Println(err)
 //	    }
 //	}
+//
+// Example 8, set formula "=SUM(R1C1:R[-1]C)" in R1C1 reference style for the
+// cell "B3" on "Sheet1", the formula will be stored as "=SUM($A$1:B2)":
+//
+//	refMode := "R1C1"
+//	err := f.SetCellFormula("Sheet1", "B3", "=SUM(R1C1:R[-1]C)",
+//	    excelize.FormulaOpts{RefMode: &refMode})
+//
+// Note that the formula in the workbook was always stored in A1 reference
+// style, please use the "RefMode" option of the "SetCalcProps" function to
+// change the reference style displayed by the spreadsheet application.
 func (f *File) SetCellFormula(sheet, cell, formula string, opts ...FormulaOpts) error {
+	refMode, err := getFormulaRefMode(opts...)
+	if err != nil {
+		return err
+	}
+	if refMode == "R1C1" && formula != "" {
+		if formula, err = FormulaR1C1ToA1(formula, cell); err != nil {
+			return err
+		}
+	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
@@ -719,6 +803,7 @@ func (f *File) SetCellFormula(sheet, cell, formula string, opts ...FormulaOpts)
 	if err != nil {
 		return err
 	}
+	f.markCellDirty(sheet, c.R)
 	if formula == "" {
 		c.F = nil
 		return f.deleteCalcChain(f.getSheetID(sheet), cell)
@@ -737,7 +822,11 @@ func (f *File) SetCellFormula(sheet, cell, formula string, opts ...FormulaOpts)
 			}
 			c.F.T = *opt.Type
 			if c.F.T == STCellFormulaTypeShared {
-				if err = ws.setSharedFormula(*opt.Ref); err != nil {
+				if err = ws.setSharedFormula(c.R, *opt.Ref); err != nil {
+					return err
+				}
+				// The cells may be reallocated on expanding the worksheet
+				if c, _, _, err = f.prepareCell(ws, cell); err != nil {
 					return err
 				}
 			}
@@ -750,8 +839,39 @@ func (f *File) SetCellFormula(sheet, cell, formula string, opts ...FormulaOpts)
 	return err
 }
 
-// setSharedFormula set shared formula for the cells.
-func (ws *xlsxWorksheet) setSharedFormula(ref string) error {
+// SetSheetFormula provides a function to set shared formula for the cells in
+// the range by given worksheet name, range reference and formula. The formula
+// will be stored in the top-left cell of the range as the master formula, and
+// the other cells in the range only refer to the master formula by the shared
+// index, this can reduce the size of the workbook with repeated formulas in
+// the rows or columns. The relative references in the formula will be
+// translated for each cell when getting the formula of the cell by the
+// "GetCellFormula" function. For example, set the formula "=A1*B1" for the
+// cells "C1:C100" on "Sheet1", then the formula of the cell "C5" will be
+// "=A5*B5":
+//
+//	err := f.SetSheetFormula("Sheet1", "C1:C100", "=A1*B1")
+func (f *File) SetSheetFormula(sheet, rangeRef, formula string) error {
+	if !strings.Contains(rangeRef, ":") {
+		rangeRef += ":" + rangeRef
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	if rangeRef, err = f.coordinatesToRangeRef(coordinates); err != nil {
+		return err
+	}
+	cell, _ := CoordinatesToCellName(coordinates[0], coordinates[1])
+	formulaType := STCellFormulaTypeShared
+	return f.SetCellFormula(sheet, cell, formula, FormulaOpts{Type: &formulaType, Ref: &rangeRef})
+}
+
+// setSharedFormula set shared formula for the cells by given master cell
+// reference and shared formula range reference, the formulas of the other
+// cells in the range will be replaced by the shared formula.
+func (ws *xlsxWorksheet) setSharedFormula(master, ref string) error {
 	coordinates, err := rangeRefToCoordinates(ref)
 	if err != nil {
 		return err
@@ -762,7 +882,7 @@ func (ws *xlsxWorksheet) setSharedFormula(ref string) error {
 		for r := coordinates[1]; r <= coordinates[3]; r++ {
 			prepareSheetXML(ws, c, r)
 			cell := &ws.SheetData.Row[r-1].C[c-1]
-			if cell.F == nil {
+			if cell.F == nil || cell.R != master {
 				cell.F = &xlsxF{}
 			}
 			cell.F.T = STCellFormulaTypeShared
@@ -920,13 +1040,13 @@ func (f *File) SetCellHyperLink(sheet, cell, link, linkType string, opts ...Hype
 }
 
 // getCellRichText returns rich text of cell by given string item.
-func getCellRichText(si *xlsxSI) (runs []RichTextRun) {
+func (f *File) getCellRichText(si *xlsxSI) (runs []RichTextRun) {
 	for _, v := range si.R {
 		run := RichTextRun{
 			Text: v.T.Val,
 		}
 		if v.RPr != nil {
-			run.Font = newFont(v.RPr)
+			run.Font = f.newRichTextFont(v.RPr)
 		}
 		runs = append(runs, run)
 	}
@@ -934,7 +1054,10 @@ func getCellRichText(si *xlsxSI) (runs []RichTextRun) {
 }
 
 // GetCellRichText provides a function to get rich text of cell by given
-// worksheet.
+// worksheet. The run properties of the rich text, such as the vertical
+// alignment, strikethrough, condense and character set will be preserved in
+// the font settings of each run, and the rich text of the inline string cell
+// is also supported.
 func (f *File) GetCellRichText(sheet, cell string) (runs []RichTextRun, err error) {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -944,6 +1067,10 @@ func (f *File) GetCellRichText(sheet, cell string) (runs []RichTextRun, err erro
 	if err != nil {
 		return
 	}
+	if c.T == "inlineStr" && c.IS != nil {
+		runs = f.getCellRichText(c.IS)
+		return
+	}
 	siIdx, err := strconv.Atoi(c.V)
 	if err != nil || c.T != "s" {
 		return
@@ -955,7 +1082,7 @@ func (f *File) GetCellRichText(sheet, cell string) (runs []RichTextRun, err erro
 	if len(sst.SI) <= siIdx || siIdx < 0 {
 		return
 	}
-	runs = getCellRichText(&sst.SI[siIdx])
+	runs = f.getCellRichText(&sst.SI[siIdx])
 	return
 }
 
@@ -972,6 +1099,20 @@ func newRpr(fnt *Font) *xlsxRPr {
 	if fnt.Strike {
 		rpr.Strike = &trueVal
 	}
+	for val, enable := range map[**string]bool{
+		&rpr.Condense: fnt.Condense, &rpr.Extend: fnt.Extend,
+		&rpr.Outline: fnt.Outline, &rpr.Shadow: fnt.Shadow,
+	} {
+		if enable {
+			*val = &trueVal
+		}
+	}
+	if fnt.Charset != nil {
+		rpr.Charset = &attrValInt{Val: intPtr(*fnt.Charset)}
+	}
+	if fnt.Scheme != "" {
+		rpr.Scheme = &attrValString{Val: stringPtr(fnt.Scheme)}
+	}
 	if fnt.Underline != "" {
 		rpr.U = &attrValString{Val: &fnt.Underline}
 	}
@@ -988,8 +1129,10 @@ func newRpr(fnt *Font) *xlsxRPr {
 	return &rpr
 }
 
-// newFont create font format by given run properties for the rich text.
-func newFont(rPr *xlsxRPr) *Font {
+// newRichTextFont create font format by given run properties for the rich
+// text, the theme color without RGB color will be resolved to RGB color by
+// the color scheme of the theme.
+func (f *File) newRichTextFont(rPr *xlsxRPr) *Font {
 	font := Font{Underline: "none"}
 	font.Bold = rPr.B != nil
 	font.Italic = rPr.I != nil
@@ -1006,10 +1149,24 @@ func newFont(rPr *xlsxRPr) *Font {
 		font.Size = *rPr.Sz.Val
 	}
 	font.Strike = rPr.Strike != nil
+	font.Condense, font.Extend = rPr.Condense != nil, rPr.Extend != nil
+	font.Outline, font.Shadow = rPr.Outline != nil, rPr.Shadow != nil
+	if rPr.VertAlign != nil && rPr.VertAlign.Val != nil {
+		font.VertAlign = *rPr.VertAlign.Val
+	}
+	if rPr.Charset != nil && rPr.Charset.Val != nil {
+		font.Charset = intPtr(*rPr.Charset.Val)
+	}
+	if rPr.Scheme != nil && rPr.Scheme.Val != nil {
+		font.Scheme = *rPr.Scheme.Val
+	}
 	if rPr.Color != nil {
 		font.Color = strings.TrimPrefix(rPr.Color.RGB, "FF")
 		if rPr.Color.Theme != nil {
 			font.ColorTheme = rPr.Color.Theme
+			if font.Color == "" {
+				font.Color = f.getThemeColor(rPr.Color)
+			}
 		}
 		font.ColorIndexed = rPr.Color.Indexed
 		font.ColorTint = rPr.Color.Tint
@@ -1175,6 +1332,7 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 		return err
 	}
 	c.S = f.prepareCellStyle(ws, col, row, c.S)
+	f.markCellDirty(sheet, c.R)
 	si := xlsxSI{}
 	sst, err := f.sharedStringsReader()
 	if err != nil {
@@ -1183,17 +1341,94 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 	if si.R, err = setRichText(runs); err != nil {
 		return err
 	}
+	setSharedRichText(sst, c, si)
+	return err
+}
+
+// AppendCellRichText provides a function to append the rich text runs to the
+// end of the existing cell value by given worksheet name and cell reference,
+// the existing runs of the cell will be kept as they are without rebuilding.
+// The plain text value of the cell will be kept as a run without font
+// settings, and this function works as SetCellRichText for the empty cell.
+// For example, append a bold run to the A1 cell of the worksheet named
+// Sheet1:
+//
+//	err := f.AppendCellRichText("Sheet1", "A1", []excelize.RichTextRun{
+//	    {Text: " (updated)", Font: &excelize.Font{Bold: true}},
+//	})
+func (f *File) AppendCellRichText(sheet, cell string, runs []RichTextRun) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	c, col, row, err := f.prepareCell(ws, cell)
+	if err != nil {
+		return err
+	}
+	if err := f.sharedStringsLoader(); err != nil {
+		return err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	textRuns, err := setRichText(runs)
+	if err != nil {
+		return err
+	}
+	si := xlsxSI{R: getCellTextRuns(c, sst)}
+	var totalCellChars int
+	for _, run := range append(si.R, textRuns...) {
+		if totalCellChars += len(run.T.Val); totalCellChars > TotalCellChars {
+			return ErrCellCharsLength
+		}
+	}
+	c.S = f.prepareCellStyle(ws, col, row, c.S)
+	f.markCellDirty(sheet, c.R)
+	si.R = append(si.R, textRuns...)
+	setSharedRichText(sst, c, si)
+	return err
+}
+
+// getCellTextRuns returns a copy of the rich text runs of the cell by given
+// shared strings table, the plain text value of the cell will be converted
+// to a run without run properties.
+func getCellTextRuns(c *xlsxC, sst *xlsxSST) []xlsxR {
+	si := c.IS
+	if c.T == "s" {
+		if idx, err := strconv.Atoi(c.V); err == nil && idx >= 0 && idx < len(sst.SI) {
+			si = &sst.SI[idx]
+		}
+	}
+	if si == nil {
+		if c.T == "s" || c.V == "" {
+			return nil
+		}
+		si = &xlsxSI{T: &xlsxT{Val: c.V}}
+	}
+	if len(si.R) == 0 {
+		if si.T == nil || si.T.Val == "" {
+			return nil
+		}
+		return []xlsxR{{T: &xlsxT{Val: si.T.Val, Space: si.T.Space}}}
+	}
+	return append(make([]xlsxR, 0, len(si.R)), si.R...)
+}
+
+// setSharedRichText provides a function to store the rich text string item
+// into the shared strings table, and reference it by given cell.
+func setSharedRichText(sst *xlsxSST, c *xlsxC, si xlsxSI) {
+	c.IS = nil
 	for idx, strItem := range sst.SI {
 		if reflect.DeepEqual(strItem, si) {
 			c.T, c.V = "s", strconv.Itoa(idx)
-			return err
+			return
 		}
 	}
 	sst.SI = append(sst.SI, si)
 	sst.Count++
 	sst.UniqueCount++
 	c.T, c.V = "s", strconv.Itoa(len(sst.SI)-1)
-	return err
 }
 
 // SetSheetRow writes an array to row by given worksheet name, starting
@@ -1352,14 +1587,16 @@ func (f *File) formattedValue(s int, v string, raw bool) (string, error) {
 	if ok := builtInNumFmtFunc[numFmtID]; ok != nil {
 		return ok(v, builtInNumFmt[numFmtID], date1904), err
 	}
-	if styleSheet.NumFmts == nil {
-		return v, err
-	}
-	for _, xlsxFmt := range styleSheet.NumFmts.NumFmt {
-		if xlsxFmt.NumFmtID == numFmtID {
-			return format(v, xlsxFmt.FormatCode, date1904), err
+	if styleSheet.NumFmts != nil {
+		for _, xlsxFmt := range styleSheet.NumFmts.NumFmt {
+			if xlsxFmt.NumFmtID == numFmtID {
+				return format(v, xlsxFmt.FormatCode, date1904), err
+			}
 		}
 	}
+	if fmtCode, ok := getCultureNumFmtCode(f.options.CultureInfo, numFmtID); ok {
+		return format(v, fmtCode, date1904), err
+	}
 	return v, err
 }
 
@@ -1498,22 +1735,29 @@ func getSharedFormula(ws *xlsxWorksheet, si int, cell string) string {
 	for _, r := range ws.SheetData.Row {
 		for _, c := range r.C {
 			if c.F != nil && c.F.Ref != "" && c.F.T == STCellFormulaTypeShared && c.F.Si != nil && *c.F.Si == si {
-				col, row, _ := CellNameToCoordinates(cell)
-				sharedCol, sharedRow, _ := CellNameToCoordinates(c.R)
-				dCol := col - sharedCol
-				dRow := row - sharedRow
-				orig := []byte(c.F.Content)
-				res, start := parseSharedFormula(dCol, dRow, orig)
-				if start < len(orig) {
-					res += string(orig[start:])
-				}
-				return res
+				return shiftSharedFormula(c.F.Content, c.R, cell)
 			}
 		}
 	}
 	return ""
 }
 
+// shiftSharedFormula provides a function to get the formula of the cell in
+// the shared formula by given formula of the master cell, the master cell
+// reference and the cell reference.
+func shiftSharedFormula(formula, sharedCell, cell string) string {
+	col, row, _ := CellNameToCoordinates(cell)
+	sharedCol, sharedRow, _ := CellNameToCoordinates(sharedCell)
+	dCol := col - sharedCol
+	dRow := row - sharedRow
+	orig := []byte(formula)
+	res, start := parseSharedFormula(dCol, dRow, orig)
+	if start < len(orig) {
+		res += string(orig[start:])
+	}
+	return res
+}
+
 // shiftCell returns the cell shifted according to dCol and dRow taking into
 // consideration absolute references with dollar sign ($)
 func shiftCell(cellID string, dCol, dRow int) string {
@@ -1534,3 +1778,268 @@ func shiftCell(cellID string, dCol, dRow int) string {
 	colName, _ := ColumnNumberToName(fCol)
 	return signCol + colName + signRow + strconv.Itoa(fRow)
 }
+
+var (
+	// a1RefExp defined the regular expression for the cell reference in A1
+	// reference style.
+	a1RefExp = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})(\$?)(\d+)$`)
+	// a1ColRefExp and a1RowRefExp defined the regular expressions for the
+	// parts of the whole column and whole row references in A1 reference
+	// style, such as A:C and 1:3.
+	a1ColRefExp = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})$`)
+	a1RowRefExp = regexp.MustCompile(`^(\$?)(\d+)$`)
+	// r1c1RefExp defined the regular expression for the cell reference in R1C1
+	// reference style.
+	r1c1RefExp = regexp.MustCompile(`^[Rr](\[-?\d+\]|\d+)?[Cc](\[-?\d+\]|\d+)?$`)
+	// r1c1RowRefExp and r1c1ColRefExp defined the regular expressions for the
+	// whole row and whole column references in R1C1 reference style, such as
+	// R1, R[-1] and C2.
+	r1c1RowRefExp = regexp.MustCompile(`^[Rr](\[-?\d+\]|\d+)?$`)
+	r1c1ColRefExp = regexp.MustCompile(`^[Cc](\[-?\d+\]|\d+)?$`)
+)
+
+// FormulaA1ToR1C1 provides a function to convert the cell references in the
+// formula from A1 reference style to R1C1 reference style, relative
+// references are converted relative to the given cell. The whole column and
+// whole row references, such as A:C and 1:3, will be converted to the column
+// and row references in R1C1 reference style. For example, convert the
+// formula "=SUM($A$1:B2)" of the cell "B3":
+//
+//	formula, err := excelize.FormulaA1ToR1C1("=SUM($A$1:B2)", "B3")
+//
+// The result of the formula will be "=SUM(R1C1:R[-1]C)".
+func FormulaA1ToR1C1(formula, cell string) (string, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return formula, err
+	}
+	return convertFormulaRef(formula, func(ref string) (string, bool, error) {
+		parts := strings.Split(ref, ":")
+		if len(parts) == 2 {
+			if cols := [][]string{a1ColRefExp.FindStringSubmatch(parts[0]), a1ColRefExp.FindStringSubmatch(parts[1])}; cols[0] != nil && cols[1] != nil {
+				for i, matches := range cols {
+					refCol, err := ColumnNameToNumber(matches[2])
+					if err != nil {
+						return ref, false, nil
+					}
+					parts[i] = "C" + r1c1RefPart(matches[1] == "$", refCol, col)
+				}
+				return strings.Join(parts, ":"), true, nil
+			}
+			if rows := [][]string{a1RowRefExp.FindStringSubmatch(parts[0]), a1RowRefExp.FindStringSubmatch(parts[1])}; rows[0] != nil && rows[1] != nil {
+				for i, matches := range rows {
+					refRow, err := strconv.Atoi(matches[2])
+					if err != nil || refRow < 1 || refRow > TotalRows {
+						return ref, false, nil
+					}
+					parts[i] = "R" + r1c1RefPart(matches[1] == "$", refRow, row)
+				}
+				return strings.Join(parts, ":"), true, nil
+			}
+		}
+		var converted bool
+		for i, part := range parts {
+			matches := a1RefExp.FindStringSubmatch(part)
+			if matches == nil {
+				continue
+			}
+			refCol, err := ColumnNameToNumber(matches[2])
+			if err != nil {
+				continue
+			}
+			refRow, err := strconv.Atoi(matches[4])
+			if err != nil || refRow < 1 || refRow > TotalRows {
+				continue
+			}
+			parts[i], converted = "R"+r1c1RefPart(matches[3] == "$", refRow, row)+
+				"C"+r1c1RefPart(matches[1] == "$", refCol, col), true
+		}
+		return strings.Join(parts, ":"), converted, nil
+	})
+}
+
+// FormulaR1C1ToA1 provides a function to convert the cell references in the
+// formula from R1C1 reference style to A1 reference style, relative
+// references are resolved relative to the given cell. The row and column
+// references, such as R1:R3, R[-1] and C2, will be converted to the whole
+// row and whole column references in A1 reference style. For example,
+// convert the formula "=SUM(R1C1:R[-1]C)" of the cell "B3":
+//
+//	formula, err := excelize.FormulaR1C1ToA1("=SUM(R1C1:R[-1]C)", "B3")
+//
+// The result of the formula will be "=SUM($A$1:B2)".
+func FormulaR1C1ToA1(formula, cell string) (string, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return formula, err
+	}
+	rowPart := func(part string) (string, error) {
+		refRow, absRow, err := a1RefPart(part, row)
+		if err != nil {
+			return "", err
+		}
+		if refRow < 1 || refRow > TotalRows {
+			return "", ErrMaxRows
+		}
+		if absRow {
+			return "$" + strconv.Itoa(refRow), err
+		}
+		return strconv.Itoa(refRow), err
+	}
+	colPart := func(part string) (string, error) {
+		refCol, absCol, err := a1RefPart(part, col)
+		if err != nil {
+			return "", err
+		}
+		colName, err := ColumnNumberToName(refCol)
+		if absCol {
+			return "$" + colName, err
+		}
+		return colName, err
+	}
+	return convertFormulaRef(formula, func(ref string) (string, bool, error) {
+		parts := strings.Split(ref, ":")
+		for _, kind := range []struct {
+			exp  *regexp.Regexp
+			conv func(part string) (string, error)
+		}{{r1c1RowRefExp, rowPart}, {r1c1ColRefExp, colPart}} {
+			var matches [][]string
+			for _, part := range parts {
+				if m := kind.exp.FindStringSubmatch(part); m != nil {
+					matches = append(matches, m)
+				}
+			}
+			if len(parts) > 2 || len(matches) != len(parts) {
+				continue
+			}
+			// the single row or column reference will be converted to the
+			// range of the whole row or column
+			if len(matches) == 1 {
+				matches = append(matches, matches[0])
+			}
+			converted := make([]string, len(matches))
+			for i, m := range matches {
+				if converted[i], err = kind.conv(m[1]); err != nil {
+					return ref, false, err
+				}
+			}
+			return strings.Join(converted, ":"), true, nil
+		}
+		var converted bool
+		for i, part := range parts {
+			matches := r1c1RefExp.FindStringSubmatch(part)
+			if matches == nil {
+				continue
+			}
+			rowRef, err := rowPart(matches[1])
+			if err != nil {
+				return ref, false, err
+			}
+			colRef, err := colPart(matches[2])
+			if err != nil {
+				return ref, false, err
+			}
+			parts[i], converted = colRef+rowRef, true
+		}
+		return strings.Join(parts, ":"), converted, nil
+	})
+}
+
+// r1c1RefPart returns the row or column part of the cell reference in R1C1
+// reference style by given absolute flag, reference and base number.
+func r1c1RefPart(abs bool, num, base int) string {
+	if abs {
+		return strconv.Itoa(num)
+	}
+	if num == base {
+		return ""
+	}
+	return "[" + strconv.Itoa(num-base) + "]"
+}
+
+// a1RefPart returns the row or column number and absolute flag by given row
+// or column part of the cell reference in R1C1 reference style and base
+// number.
+func a1RefPart(part string, base int) (int, bool, error) {
+	if part == "" {
+		return base, false, nil
+	}
+	if strings.HasPrefix(part, "[") {
+		offset, err := strconv.Atoi(strings.Trim(part, "[]"))
+		return base + offset, false, err
+	}
+	num, err := strconv.Atoi(part)
+	return num, true, err
+}
+
+// convertFormulaRef walks through the formula and replaces each cell
+// reference by the given convert function, the string literals, quoted sheet
+// names, function names and sheet name prefixes in the formula will be kept.
+// The range reference, such as A1:B2 and A:C, will be passed to the convert
+// function as a whole.
+func convertFormulaRef(formula string, fn func(ref string) (string, bool, error)) (string, error) {
+	var (
+		res    strings.Builder
+		quoted byte
+	)
+	isNamePart := func(c byte) bool {
+		return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '$' || c == '_' || c == '.' || c > 127
+	}
+	for i := 0; i < len(formula); i++ {
+		c := formula[i]
+		if quoted != 0 {
+			if c == quoted {
+				quoted = 0
+			}
+			res.WriteByte(c)
+			continue
+		}
+		if c == '"' || c == '\'' {
+			quoted = c
+			res.WriteByte(c)
+			continue
+		}
+		if !isNamePart(c) || i > 0 && isNamePart(formula[i-1]) {
+			res.WriteByte(c)
+			continue
+		}
+		scan := func(end int) int {
+			for end < len(formula) {
+				if formula[end] == '[' {
+					if idx := strings.IndexByte(formula[end:], ']'); idx != -1 {
+						end += idx + 1
+						continue
+					}
+				}
+				if !isNamePart(formula[end]) {
+					break
+				}
+				end++
+			}
+			return end
+		}
+		end := scan(i)
+		if end < len(formula) && (formula[end] == '(' || formula[end] == '!') {
+			res.WriteString(formula[i:end])
+			i = end - 1
+			continue
+		}
+		if end+1 < len(formula) && formula[end] == ':' && isNamePart(formula[end+1]) {
+			if next := scan(end + 1); next == len(formula) || formula[next] != '!' && formula[next] != '(' {
+				end = next
+			}
+		}
+		token := formula[i:end]
+		ref, ok, err := fn(token)
+		if err != nil {
+			return formula, err
+		}
+		if !ok {
+			ref = token
+		}
+		res.WriteString(ref)
+		i = end - 1
+	}
+	return res.String(), nil
+}
@@ -578,6 +578,117 @@ func TestSetCellFormula(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellFormula6.xlsx")))
 }
 
+func TestSetSheetFormula(t *testing.T) {
+	f := NewFile()
+	for r := 1; r <= 3; r++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", r), &[]interface{}{r, r * 10}))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", "=1"))
+	assert.NoError(t, f.SetSheetFormula("Sheet1", "E3:C1", "=$A1+B1"))
+	for cell, expected := range map[string][]string{
+		"C1": {"=$A1+B1", "11"},
+		"D2": {"=$A2+C2", "24"},
+		"E3": {"=$A3+D3", ""},
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[0], formula, cell)
+		if expected[1] != "" {
+			result, err := f.CalcCellValue("Sheet1", cell)
+			assert.NoError(t, err)
+			assert.Equal(t, expected[1], result, cell)
+		}
+	}
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	master, member := ws.(*xlsxWorksheet).SheetData.Row[0].C[2], ws.(*xlsxWorksheet).SheetData.Row[1].C[3]
+	assert.Equal(t, "C1:E3", master.F.Ref)
+	assert.Equal(t, 0, *master.F.Si)
+	assert.Equal(t, &xlsxF{T: STCellFormulaTypeShared, Si: master.F.Si}, member.F)
+	// Test set shared formula for a single cell
+	assert.NoError(t, f.SetSheetFormula("Sheet1", "F1", "=A1*2"))
+	formula, err := f.GetCellFormula("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "=A1*2", formula)
+	assert.Equal(t, 1, *ws.(*xlsxWorksheet).SheetData.Row[0].C[5].F.Si)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetSheetFormula.xlsx")))
+	// Test set shared formula with invalid range reference
+	assert.EqualError(t, f.SetSheetFormula("Sheet1", "A:B", "=1"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.SetSheetFormula("Sheet1", "A1:XFE1", "=1"), ErrColumnNumber.Error())
+	// Test set shared formula on not exist worksheet
+	assert.EqualError(t, f.SetSheetFormula("SheetN", "A1:A2", "=1"), "sheet SheetN does not exist")
+}
+
+func TestFormulaRefMode(t *testing.T) {
+	for a1, r1c1 := range map[string]string{
+		"=SUM($A$1:B2)":                  "=SUM(R1C1:R[-1]C)",
+		"=A3+$B1*C$2":                    "=RC[-1]+R[-2]C2*R2C[1]",
+		"=Sheet1!A1&\"A1\"&'Sheet 2'!C5": "=Sheet1!R[-2]C[-1]&\"A1\"&'Sheet 2'!R[2]C[1]",
+		"=LOG10(A1)+SUM(Table1[Col1])":   "=LOG10(R[-2]C[-1])+SUM(Table1[Col1])",
+		"=TRUE":                          "=TRUE",
+		"=SUM($1:$3)+SUM(2:4)":           "=SUM(R1:R3)+SUM(R[-1]:R[1])",
+		"=SUM($A:$C)+SUM(B:D)+1":         "=SUM(C1:C3)+SUM(C:C[2])+1",
+		"=Sheet1!$A:A*'Sheet 2'!$2:$2":   "=Sheet1!C1:C[-1]*'Sheet 2'!R2:R2",
+	} {
+		formula, err := FormulaA1ToR1C1(a1, "B3")
+		assert.NoError(t, err)
+		assert.Equal(t, r1c1, formula)
+		formula, err = FormulaR1C1ToA1(r1c1, "B3")
+		assert.NoError(t, err)
+		assert.Equal(t, a1, formula)
+	}
+	// Test convert the single row and column references in R1C1 reference
+	// style to the whole row and column references
+	for r1c1, a1 := range map[string]string{
+		"=SUM(R2)*C[1]":      "=SUM($2:$2)*C:C",
+		"=SUM(R)+C2":         "=SUM(3:3)+$B:$B",
+		"=SUM(R1:R[-1]C1)+1": "=SUM(R1:$A2)+1",
+	} {
+		formula, err := FormulaR1C1ToA1(r1c1, "B3")
+		assert.NoError(t, err)
+		assert.Equal(t, a1, formula)
+	}
+	// Test convert the numbers and names which look like the row and column
+	// references in A1 reference style
+	for _, a1 := range []string{"=1+3", "=A", "=SUM(A1:B)", "=1:XFE1"} {
+		formula, err := FormulaA1ToR1C1(a1, "B3")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.NewReplacer("A1", "R[-2]C[-1]").Replace(a1), formula)
+	}
+	// Test convert formula with invalid cell reference
+	_, err := FormulaA1ToR1C1("=A1", "A")
+	assert.EqualError(t, err, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	_, err = FormulaR1C1ToA1("=R1C1", "A")
+	assert.EqualError(t, err, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test convert formula with out of range references
+	_, err = FormulaR1C1ToA1("=R[-1]C", "A1")
+	assert.EqualError(t, err, ErrMaxRows.Error())
+	_, err = FormulaR1C1ToA1("=RC[-1]", "A1")
+	assert.EqualError(t, err, ErrColumnNumber.Error())
+	_, err = FormulaR1C1ToA1("=SUM(R[-1]:R1)", "A1")
+	assert.EqualError(t, err, ErrMaxRows.Error())
+	_, err = FormulaR1C1ToA1("=C[-1]", "A1")
+	assert.EqualError(t, err, ErrColumnNumber.Error())
+	
+	// Test set and get cell formula in R1C1 reference style
+	f := NewFile()
+	refMode := "R1C1"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B3", "=SUM(R1C1:R[-1]C)", FormulaOpts{RefMode: &refMode}))
+	formula, err := f.GetCellFormula("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, "=SUM($A$1:B2)", formula)
+	formula, err = f.GetCellFormula("Sheet1", "B3", FormulaOpts{RefMode: &refMode})
+	assert.NoError(t, err)
+	assert.Equal(t, "=SUM(R1C1:R[-1]C)", formula)
+	assert.EqualError(t, f.SetCellFormula("Sheet1", "A1", "=R[-1]C", FormulaOpts{RefMode: &refMode}), ErrMaxRows.Error())
+	// Test set and get cell formula with invalid reference style
+	refMode = "unknown"
+	assert.EqualError(t, f.SetCellFormula("Sheet1", "B3", "=A1", FormulaOpts{RefMode: &refMode}),
+		newInvalidOptionalValue("RefMode", "unknown", supportedRefMode).Error())
+	_, err = f.GetCellFormula("Sheet1", "B3", FormulaOpts{RefMode: &refMode})
+	assert.EqualError(t, err, newInvalidOptionalValue("RefMode", "unknown", supportedRefMode).Error())
+}
+
 func TestGetCellRichText(t *testing.T) {
 	f, theme := NewFile(), 1
 	
@@ -597,6 +708,13 @@ func TestGetCellRichText(t *testing.T) {
 				Family:     "Times New Roman",
 				Size:       100,
 				Strike:     true,
+				VertAlign:  "superscript",
+				Charset:    intPtr(134),
+				Condense:   true,
+				Extend:     true,
+				Outline:    true,
+				Shadow:     true,
+				Scheme:     "minor",
 			},
 		},
 	}
@@ -617,24 +735,26 @@ func TestGetCellRichText(t *testing.T) {
 	runsSource[1].Font.Color = strings.ToUpper(runsSource[1].Font.Color)
 	assert.True(t, reflect.DeepEqual(runsSource[1].Font, runs[1].Font), "should get the same font")
 	
+	// Test get cell rich text of the inline string cell
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[1].C[0] = xlsxC{R: "A2", T: "inlineStr", IS: &xlsxSI{R: []xlsxR{{T: &xlsxT{Val: "c"}, RPr: &xlsxRPr{VertAlign: &attrValString{Val: stringPtr("subscript")}}}}}}
+	runs, err = f.GetCellRichText("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, []RichTextRun{{Text: "c", Font: &Font{Underline: "none", VertAlign: "subscript"}}}, runs)
+
 	// Test get cell rich text when string item index overflow
-	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
-	assert.True(t, ok)
-	ws.(*xlsxWorksheet).SheetData.Row[0].C[0].V = "2"
+	ws.SheetData.Row[0].C[0].V = "2"
 	runs, err = f.GetCellRichText("Sheet1", "A1")
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(runs))
 	// Test get cell rich text when string item index is negative
-	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
-	assert.True(t, ok)
-	ws.(*xlsxWorksheet).SheetData.Row[0].C[0].V = "-1"
+	ws.SheetData.Row[0].C[0].V = "-1"
 	runs, err = f.GetCellRichText("Sheet1", "A1")
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(runs))
 	// Test get cell rich text on invalid string item index
-	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
-	assert.True(t, ok)
-	ws.(*xlsxWorksheet).SheetData.Row[0].C[0].V = "x"
+	ws.SheetData.Row[0].C[0].V = "x"
 	_, err = f.GetCellRichText("Sheet1", "A1")
 	assert.EqualError(t, err, "strconv.Atoi: parsing \"x\": invalid syntax")
 	// Test set cell rich text on not exists worksheet
@@ -662,6 +782,63 @@ func TestGetCellRichText(t *testing.T) {
 	assert.EqualError(t, err, ErrSheetNameInvalid.Error())
 }
 
+func TestAppendCellRichText(t *testing.T) {
+	f := NewFile()
+	bold := &Font{Bold: true, Underline: "none"}
+	// Test append rich text runs to the empty cell
+	assert.NoError(t, f.AppendCellRichText("Sheet1", "A1", []RichTextRun{{Text: "a"}}))
+	assert.NoError(t, f.AppendCellRichText("Sheet1", "A1", []RichTextRun{{Text: "b", Font: bold}}))
+	runs, err := f.GetCellRichText("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, []RichTextRun{{Text: "a"}, {Text: "b", Font: bold}}, runs)
+	// Test append rich text runs to the cell which shared the string item
+	assert.NoError(t, f.SetCellRichText("Sheet1", "A2", runs))
+	assert.NoError(t, f.AppendCellRichText("Sheet1", "A2", []RichTextRun{{Text: " c"}}))
+	runs, err = f.GetCellRichText("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	val, err := f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "ab c", val)
+	// Test append rich text runs to the plain text and number cells
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "plain"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 100))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B3", ""))
+	for cell, expected := range map[string][]RichTextRun{
+		"B1": {{Text: "plain"}, {Text: "!", Font: bold}},
+		"B2": {{Text: "100"}, {Text: "!", Font: bold}},
+		"B3": {{Text: "!", Font: bold}},
+	} {
+		assert.NoError(t, f.AppendCellRichText("Sheet1", cell, []RichTextRun{{Text: "!", Font: bold}}))
+		runs, err = f.GetCellRichText("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, runs)
+	}
+	// Test append rich text runs to the inline string cell
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[1] = xlsxC{R: "B1", T: "inlineStr", IS: &xlsxSI{T: &xlsxT{Val: "inline"}}}
+	assert.NoError(t, f.AppendCellRichText("Sheet1", "B1", []RichTextRun{{Text: "!"}}))
+	assert.Nil(t, ws.SheetData.Row[0].C[1].IS)
+	runs, err = f.GetCellRichText("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, []RichTextRun{{Text: "inline"}, {Text: "!"}}, runs)
+	// Test append rich text runs exceeds the maximum characters limit
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", strings.Repeat("c", TotalCellChars)))
+	assert.Equal(t, ErrCellCharsLength, f.AppendCellRichText("Sheet1", "C1", []RichTextRun{{Text: "c"}}))
+	assert.Equal(t, ErrCellCharsLength, f.AppendCellRichText("Sheet1", "C2", []RichTextRun{{Text: strings.Repeat("c", TotalCellChars+1)}}))
+	// Test append rich text runs with invalid sheet name, not exists worksheet
+	// and invalid cell reference
+	assert.EqualError(t, f.AppendCellRichText("Sheet:1", "A1", nil), ErrSheetNameInvalid.Error())
+	assert.EqualError(t, f.AppendCellRichText("SheetN", "A1", nil), "sheet SheetN does not exist")
+	assert.EqualError(t, f.AppendCellRichText("Sheet1", "A", nil), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test append rich text runs with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AppendCellRichText("Sheet1", "A1", nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestSetCellRichText(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetRowHeight("Sheet1", 1, 35))
@@ -844,6 +1021,38 @@ func TestFormattedValue(t *testing.T) {
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestFormattedValueCulture(t *testing.T) {
+	for _, c := range []struct {
+		culture  CultureName
+		numFmtID int
+		expected string
+	}{
+		{CultureNameUnknown, 31, "43528"},
+		{CultureNameEnUS, 31, "March 4, 2019"},
+		{CultureNameEnUS, 5, "43528"},
+		{CultureNameZhCN, 31, "2019年3月4日"},
+		{CultureNameZhCN, 33, "0时00分00秒"},
+		{CultureNameJaJP, 31, "2019年3月4日"},
+		{CultureNameJaJP, 27, "H31.3.4"},
+		{CultureNameJaJP, 28, "平成31年3月4日"},
+		{CultureNameZhTW, 28, "108年3月4日"},
+	} {
+		f := NewFile(Options{CultureInfo: c.culture})
+		f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, xlsxXf{NumFmtID: intPtr(c.numFmtID)})
+		result, err := f.formattedValue(len(f.Styles.CellXfs.Xf)-1, "43528", false)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, result)
+	}
+	// Test format value with the custom number format which has the same ID
+	// with the language dependent built-in number format
+	f := NewFile(Options{CultureInfo: CultureNameZhCN})
+	f.Styles.NumFmts = &xlsxNumFmts{NumFmt: []*xlsxNumFmt{{NumFmtID: 31, FormatCode: "yyyy-mm-dd"}}}
+	f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, xlsxXf{NumFmtID: intPtr(31)})
+	result, err := f.formattedValue(len(f.Styles.CellXfs.Xf)-1, "43528", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-03-04", result)
+}
+
 func TestFormattedValueNilXfs(t *testing.T) {
 	// Set the CellXfs to nil and verify that the formattedValue function does not crash
 	f := NewFile()
@@ -12,13 +12,26 @@
 package excel
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
-// This section defines the currently supported chart types.
+// ChartType is the type of supported chart types, the value of the chart type
+// can be used as the 'Chart.Type' by converting it to a string. The
+// 'Chart.Type' keeps the string type for backward compatibility, so the chart
+// type will not be checked at compile time, use ParseChartType to validate the
+// chart type name before adding the chart.
+type ChartType string
+
+// This section defines the currently supported chart types, these untyped
+// constants can be used as both 'Chart.Type' and ChartType.
 const (
 	Area                        = "area"
 	AreaStacked                 = "areaStacked"
@@ -75,6 +88,13 @@ const (
 	WireframeContour            = "wireframeContour"
 	Bubble                      = "bubble"
 	Bubble3D                    = "bubble3D"
+	Waterfall                   = "waterfall"
+	Funnel                      = "funnel"
+	Treemap                     = "treemap"
+	Sunburst                    = "sunburst"
+	Histogram                   = "histogram"
+	Pareto                      = "pareto"
+	BoxWhisker                  = "boxWhisker"
 )
 
 // This section defines the default value of chart properties.
@@ -475,8 +495,69 @@ var (
 		Contour:          "none",
 		WireframeContour: "none",
 	}
+	chartExLayoutID = map[string]string{
+		Waterfall:  "waterfall",
+		Funnel:     "funnel",
+		Treemap:    "treemap",
+		Sunburst:   "sunburst",
+		Histogram:  "clusteredColumn",
+		Pareto:     "clusteredColumn",
+		BoxWhisker: "boxWhisker",
+	}
+	chartExRequires = map[string]xml.Attr{
+		Waterfall:  NameSpaceChartEx2015,
+		Funnel:     NameSpaceChartEx201510,
+		Treemap:    NameSpaceChartEx2015,
+		Sunburst:   NameSpaceChartEx2015,
+		Histogram:  NameSpaceChartEx2015,
+		Pareto:     NameSpaceChartEx2015,
+		BoxWhisker: NameSpaceChartEx2015,
+	}
+	chartErrorBarsValType = map[string]string{
+		"fixed":      "fixedVal",
+		"percentage": "percentage",
+		"stdDev":     "stdDev",
+		"stdErr":     "stdErr",
+		"custom":     "cust",
+	}
+	chartErrorBarsSupported = map[string]bool{
+		Area: true, AreaStacked: true, AreaPercentStacked: true,
+		Bar: true, BarStacked: true, BarPercentStacked: true,
+		Col: true, ColStacked: true, ColPercentStacked: true,
+		Line: true, Scatter: true, Bubble: true,
+	}
+	chartDataLabelPosition = map[string]string{
+		"best_fit":    "bestFit",
+		"bottom":      "b",
+		"center":      "ctr",
+		"inside_base": "inBase",
+		"inside_end":  "inEnd",
+		"left":        "l",
+		"outside_end": "outEnd",
+		"right":       "r",
+		"top":         "t",
+	}
 )
 
+// String returns the name of the chart type.
+func (t ChartType) String() string {
+	return string(t)
+}
+
+// ParseChartType provides a function to parse the chart type by given chart
+// type name, for example, parse the clustered column chart type:
+//
+//	chartType, err := excelize.ParseChartType("col")
+func ParseChartType(name string) (ChartType, error) {
+	if _, ok := chartValAxNumFmtFormatCode[name]; ok {
+		return ChartType(name), nil
+	}
+	if _, ok := chartExLayoutID[name]; ok {
+		return ChartType(name), nil
+	}
+	return ChartType(name), newUnsupportedChartType(name)
+}
+
 // parseChartOptions provides a function to parse the format settings of the
 // chart with default value.
 func parseChartOptions(opts *Chart) (*Chart, error) {
@@ -513,9 +594,55 @@ func parseChartOptions(opts *Chart) (*Chart, error) {
 	if opts.ShowBlanksAs == "" {
 		opts.ShowBlanksAs = defaultChartShowBlanksAs
 	}
+	for _, ser := range opts.Series {
+		for _, errBars := range []ChartErrorBars{ser.ErrorBars, ser.XErrorBars} {
+			if err := parseChartErrorBarsOptions(errBars); err != nil {
+				return opts, err
+			}
+		}
+		if ser.DataLabel != nil {
+			if err := parseChartDataLabelOptions(*ser.DataLabel); err != nil {
+				return opts, err
+			}
+		}
+		for _, pt := range ser.DataPointLabels {
+			if pt.Index < 0 {
+				return opts, ErrParameterInvalid
+			}
+			if err := parseChartDataLabelOptions(pt.Label); err != nil {
+				return opts, err
+			}
+		}
+	}
 	return opts, nil
 }
 
+// parseChartErrorBarsOptions provides a function to validate the format
+// settings of the error bars of the chart series.
+func parseChartErrorBarsOptions(opts ChartErrorBars) error {
+	if opts.Type == "" {
+		return nil
+	}
+	if _, ok := chartErrorBarsValType[opts.Type]; !ok {
+		return newInvalidOptionalValue("Type", opts.Type, []string{"fixed", "percentage", "stdDev", "stdErr", "custom"})
+	}
+	if opts.Direction != "" && inStrSlice([]string{"both", "plus", "minus"}, opts.Direction, true) == -1 {
+		return newInvalidOptionalValue("Direction", opts.Direction, []string{"both", "plus", "minus"})
+	}
+	return nil
+}
+
+// parseChartDataLabelOptions provides a function to validate the format
+// settings of the data labels of the chart series or data point.
+func parseChartDataLabelOptions(opts ChartDataLabel) error {
+	if _, ok := chartDataLabelPosition[opts.Position]; opts.Position != "" && !ok {
+		return newInvalidOptionalValue("Position", opts.Position, []string{
+			"best_fit", "bottom", "center", "inside_base", "inside_end", "left", "outside_end", "right", "top",
+		})
+	}
+	return nil
+}
+
 // AddChart provides the method to add chart in a sheet by given chart format
 // set (such as offset, scale, aspect ratio setting and print settings) and
 // properties set. For example, create 3D clustered column chart with data
@@ -649,6 +776,20 @@ func parseChartOptions(opts *Chart) (*Chart, error) {
 //	 wireframeContour            | wireframe contour chart
 //	 bubble                      | bubble chart
 //	 bubble3D                    | 3D bubble chart
+//	 waterfall                   | waterfall chart
+//	 funnel                      | funnel chart
+//	 treemap                     | treemap chart
+//	 sunburst                    | sunburst chart
+//	 histogram                   | histogram chart
+//	 pareto                      | pareto chart
+//	 boxWhisker                  | box and whisker chart
+//
+// The waterfall, funnel, treemap, sunburst, histogram, pareto and box and
+// whisker chart are the extended chart types, which will be shown as a
+// placeholder shape in the spreadsheet applications earlier than Excel 2016.
+// These chart types don't support the 'combo' parameter, and the treemap
+// and sunburst chart categories could be a multiple columns cell range to
+// specify the hierarchical levels.
 //
 // In Excel a chart series is a collection of information that defines which
 // data is plotted such as values, axis labels and formatting.
@@ -657,9 +798,15 @@ func parseChartOptions(opts *Chart) (*Chart, error) {
 //
 //	Name
 //	Categories
+//	CategoriesLiteral
 //	Values
+//	ValuesLiteral
 //	Line
 //	Marker
+//	ErrorBars
+//	XErrorBars
+//	DataLabel
+//	DataPointLabels
 //
 // Name: Set the name for the series. The name is displayed in the chart legend
 // and in the formula bar. The 'Name' property is optional and if it isn't
@@ -670,10 +817,31 @@ func parseChartOptions(opts *Chart) (*Chart, error) {
 // the same as the X axis. In most chart types the 'Categories' property is
 // optional and the chart will just assume a sequential series from 1..n.
 //
+// CategoriesLiteral: This sets the chart category labels by the given string
+// literals instead of the worksheet data, it will be used only when the
+// 'Categories' property is empty.
+//
 // Values: This is the most important property of a series and is the only
 // mandatory option for every chart object. This option links the chart with
 // the worksheet data that it displays.
 //
+// ValuesLiteral: This sets the values of the series by the given numbers
+// which embedded in the chart without writing them into the worksheet, it
+// will be used only when the 'Values' property is empty. This is useful for
+// building a chart without distributing the raw data. For example, create a
+// column chart from the literal values:
+//
+//	err := f.AddChart("Sheet1", "E1", &excelize.Chart{
+//	    Type: "col",
+//	    Series: []excelize.ChartSeries{
+//	        {
+//	            Name:              "Sheet1!$A$1",
+//	            CategoriesLiteral: []string{"Q1", "Q2", "Q3", "Q4"},
+//	            ValuesLiteral:     []float64{120, 96.5, 143, 180},
+//	        },
+//	    },
+//	})
+//
 // Line: This sets the line format of the line chart. The 'Line' property is
 // optional and if it isn't supplied it will default style. The options that
 // can be set are width and color. The range of width is 0.25pt - 999pt. If the
@@ -698,6 +866,124 @@ func parseChartOptions(opts *Chart) (*Chart, error) {
 //	x
 //	auto
 //
+// ErrorBars: This sets the vertical error bars of the series for the area,
+// bar, column, line, scatter and bubble chart. The options that can be set
+// are:
+//
+//	Type
+//	Direction
+//	Value
+//	Plus
+//	Minus
+//	NoEndCap
+//	Line
+//
+// Type: Specifies the type of the error amount, the available types are
+// 'fixed', 'percentage', 'stdDev', 'stdErr' and 'custom'. The error bars
+// will not be drawn if the 'Type' is empty.
+//
+// Direction: Specifies the direction of the error bars, the available
+// directions are 'both', 'plus' and 'minus'. The default direction is 'both'.
+//
+// Value: Specifies the error amount of the 'fixed', 'percentage' and 'stdDev'
+// type error bars.
+//
+// Plus and Minus: Specifies the reference of the cells range contains the
+// positive and negative error amounts of the 'custom' type error bars, such
+// as Sheet1!$E$2:$E$6.
+//
+// NoEndCap: Specifies the end caps of the error bars shall not be drawn.
+//
+// Line: Specifies the color and width of the error bars line.
+//
+// XErrorBars: This sets the horizontal error bars of the series for the
+// scatter and bubble chart, the options are the same as 'ErrorBars'. For
+// example, create a scatter chart with the error bars in both directions:
+//
+//	err := f.AddChart("Sheet1", "E1", &excelize.Chart{
+//	    Type: "scatter",
+//	    Series: []excelize.ChartSeries{
+//	        {
+//	            Name:       "Sheet1!$B$1",
+//	            Categories: "Sheet1!$A$2:$A$6",
+//	            Values:     "Sheet1!$B$2:$B$6",
+//	            ErrorBars: excelize.ChartErrorBars{
+//	                Type:  "custom",
+//	                Plus:  "Sheet1!$C$2:$C$6",
+//	                Minus: "Sheet1!$D$2:$D$6",
+//	            },
+//	            XErrorBars: excelize.ChartErrorBars{Type: "percentage", Value: 5},
+//	        },
+//	    },
+//	})
+//
+// DataLabel: This sets the data labels of the series, which overrides the
+// data labels settings of the plot area for the series. The options that can
+// be set are:
+//
+//	Position
+//	NumFmt
+//	CellRange
+//	Separator
+//	ShowLegendKey
+//	ShowVal
+//	ShowCatName
+//	ShowSerName
+//	ShowPercent
+//	ShowBubbleSize
+//	ShowLeaderLines
+//
+// Position: Specifies the position of the data labels, the available
+// positions are "best_fit", "bottom", "center", "inside_base", "inside_end",
+// "left", "outside_end", "right" and "top". The available positions depend
+// on the chart type, for example, the "best_fit" position is only available
+// for the pie chart.
+//
+// NumFmt: Specifies the number format of the data labels by 'CustomNumFmt',
+// and 'SourceLinked' specifies the number format is linked to the source
+// data.
+//
+// CellRange: Specifies the values of the data labels from the cell range,
+// such as "Sheet1!$C$2:$C$6". This works on Excel 2013 and later.
+//
+// Separator: Specifies the separator between the contents of the data
+// labels, such as ", " or "; ".
+//
+// ShowLegendKey, ShowVal, ShowCatName, ShowSerName, ShowPercent,
+// ShowBubbleSize and ShowLeaderLines specifies the legend key, value,
+// category name, series name, percentage, bubble size and leader lines shall
+// be shown in the data labels.
+//
+// DataPointLabels: This sets the data labels of the single data points in the
+// series by the zero-based 'Index' of the data point, and the 'Label' options
+// are the same as 'DataLabel', except 'CellRange' and 'ShowLeaderLines'. The
+// values from the cell range of the series will be shown in the data labels
+// of the data points if 'CellRange' of the series has been set. For example,
+// show the values and category names of the series, and the value from the
+// cell range for the last data point:
+//
+//	err := f.AddChart("Sheet1", "E1", &excelize.Chart{
+//	    Type: "line",
+//	    Series: []excelize.ChartSeries{
+//	        {
+//	            Name:       "Sheet1!$B$1",
+//	            Categories: "Sheet1!$A$2:$A$6",
+//	            Values:     "Sheet1!$B$2:$B$6",
+//	            DataLabel: &excelize.ChartDataLabel{
+//	                Position:    "top",
+//	                NumFmt:      excelize.ChartNumFmt{CustomNumFmt: "0.0"},
+//	                CellRange:   "Sheet1!$C$2:$C$6",
+//	                Separator:   "; ",
+//	                ShowVal:     true,
+//	                ShowCatName: true,
+//	            },
+//	            DataPointLabels: []excelize.ChartDataPointLabel{
+//	                {Index: 4, Label: excelize.ChartDataLabel{Position: "right"}},
+//	            },
+//	        },
+//	    },
+//	})
+//
 // Set properties of the chart legend. The options that can be set are:
 //
 //	Position
@@ -783,6 +1069,8 @@ func parseChartOptions(opts *Chart) (*Chart, error) {
 //	Maximum
 //	Minimum
 //	Font
+//	Title
+//	NumFmt
 //
 // The properties of 'YAxis' that can be set are:
 //
@@ -795,6 +1083,9 @@ func parseChartOptions(opts *Chart) (*Chart, error) {
 //	Maximum
 //	Minimum
 //	Font
+//	Secondary
+//	Title
+//	NumFmt
 //
 // none: Disable axes.
 //
@@ -831,9 +1122,72 @@ func parseChartOptions(opts *Chart) (*Chart, error) {
 //	Color
 //	VertAlign
 //
+// Secondary: Specifies the vertical axis of the chart as the secondary axis,
+// which is displayed on the right side of the plot area with independent
+// scaling, title and number format. This only works for the charts in the
+// 'combo' parameter, the series of the combo chart will be plotted on the
+// secondary axis.
+//
+// Title: Specifies the title of the axis. The 'Title' property is optional.
+// The default is to have no axis title.
+//
+// NumFmt: Specifies the number format of the axis labels by 'CustomNumFmt',
+// such as "0.00%", and 'SourceLinked' specifies the number format is linked
+// to the source data. The 'NumFmt' property is optional.
+//
+// Set the layout properties of the extended chart types by 'Layout'. The
+// properties that can be set are:
+//
+//	Subtotals
+//	BinSize
+//	BinCount
+//	QuartileMethod
+//	ShowMeanLine
+//	ShowMeanMarker
+//	ShowInnerPoints
+//	ShowOutliers
+//	ParentLabelLayout
+//
+// Subtotals: Specifies the zero-based indexes of the data points which are
+// shown as the subtotals in the waterfall chart.
+//
+// BinSize: Specifies the bin width of the histogram and pareto chart. The
+// 'BinSize' property is optional. The default value is auto.
+//
+// BinCount: Specifies the number of the bins of the histogram and pareto
+// chart, it will be ignored if the 'BinSize' property has been set. The
+// 'BinCount' property is optional. The default value is auto. The histogram
+// and pareto chart series with the 'Categories' property will be grouped by
+// category instead of binning.
+//
+// QuartileMethod: Specifies the quartile calculation method of the box and
+// whisker chart, the available values are "exclusive" and "inclusive". The
+// default value is "exclusive".
+//
+// ShowMeanLine: Specifies the line connecting the means of the box and
+// whisker chart shall be shown. The default value is false.
+//
+// ShowMeanMarker: Specifies the mean markers of the box and whisker chart
+// shall be shown. The default value is true.
+//
+// ShowInnerPoints: Specifies the inner points of the box and whisker chart
+// shall be shown. The default value is false.
+//
+// ShowOutliers: Specifies the outlier points of the box and whisker chart
+// shall be shown. The default value is true.
+//
+// ParentLabelLayout: Specifies the layout of the parent category labels of
+// the treemap chart, the available values are "overlapping", "banner" and
+// "none". The default value is "overlapping".
+//
 // Set chart size by 'Dimension' property. The 'Dimension' property is optional.
 // The default width is 480, and height is 290.
 //
+// Set the name of the chart object by 'Name' property, the name can be used to
+// delete the chart by the DeleteChartByName function. The 'Name' property is
+// optional. The default name is "Chart N", and N is the object index in the
+// drawing.
+//
 // combo: Specifies the create a chart that combines two or more chart types in
 // a single chart. For example, create a clustered column - line chart with
 // data Sheet1!$E$1:$L$15:
@@ -954,13 +1308,13 @@ func (f *File) AddChart(sheet, cell string, chart *Chart, combo ...*Chart) error
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
 	drawingID, drawingXML = f.prepareDrawing(ws, drawingID, sheet, drawingXML)
 	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
-	drawingRID := f.addRels(drawingRels, SourceRelationshipChart, "../charts/chart"+strconv.Itoa(chartID)+".xml", "")
-	err = f.addDrawingChart(sheet, drawingXML, cell, int(opts.Dimension.Width), int(opts.Dimension.Height), drawingRID, &opts.Format)
-	if err != nil {
+	relType, target, contentType := getChartPart(chartID, opts.Type)
+	drawingRID := f.addRels(drawingRels, relType, target, "")
+	if err = f.addDrawingChart(sheet, drawingXML, cell, drawingRID, opts); err != nil {
 		return err
 	}
 	f.addChart(opts, comboCharts)
-	if err = f.addContentTypePart(chartID, "chart"); err != nil {
+	if err = f.addContentTypePart(chartID, contentType); err != nil {
 		return err
 	}
 	_ = f.addContentTypePart(drawingID, "drawings")
@@ -1007,12 +1361,13 @@ func (f *File) AddChartSheet(sheet string, chart *Chart, combo ...*Chart) error
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
 	f.prepareChartSheetDrawing(&cs, drawingID, sheet)
 	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
-	drawingRID := f.addRels(drawingRels, SourceRelationshipChart, "../charts/chart"+strconv.Itoa(chartID)+".xml", "")
-	if err = f.addSheetDrawingChart(drawingXML, drawingRID, &opts.Format); err != nil {
+	relType, target, contentType := getChartPart(chartID, opts.Type)
+	drawingRID := f.addRels(drawingRels, relType, target, "")
+	if err = f.addSheetDrawingChart(drawingXML, drawingRID, opts); err != nil {
 		return err
 	}
 	f.addChart(opts, comboCharts)
-	if err = f.addContentTypePart(chartID, "chart"); err != nil {
+	if err = f.addContentTypePart(chartID, contentType); err != nil {
 		return err
 	}
 	_ = f.addContentTypePart(sheetID, "chartsheet")
@@ -1027,6 +1382,15 @@ func (f *File) AddChartSheet(sheet string, chart *Chart, combo ...*Chart) error
 	return err
 }
 
+// getChartPart provides a function to get the relationship type, target and
+// content type of the chart part by given chart ID and chart type.
+func getChartPart(chartID int, chartType string) (string, string, string) {
+	if _, ok := chartExLayoutID[chartType]; ok {
+		return SourceRelationshipChartEx, "../charts/chartEx" + strconv.Itoa(chartID) + ".xml", "chartEx"
+	}
+	return SourceRelationshipChart, "../charts/chart" + strconv.Itoa(chartID) + ".xml", "chart"
+}
+
 // getChartOptions provides a function to check format set of the chart and
 // create chart format.
 func (f *File) getChartOptions(opts *Chart, combo []*Chart) (*Chart, []*Chart, error) {
@@ -1045,12 +1409,33 @@ func (f *File) getChartOptions(opts *Chart, combo []*Chart) (*Chart, []*Chart, e
 		}
 		comboCharts = append(comboCharts, comboChart)
 	}
+	if _, ok := chartExLayoutID[options.Type]; ok {
+		if len(comboCharts) > 0 {
+			return options, comboCharts, newUnsupportedChartType(comboCharts[0].Type)
+		}
+		return options, comboCharts, parseChartLayoutOptions(&options.Layout)
+	}
 	if _, ok := chartValAxNumFmtFormatCode[options.Type]; !ok {
 		return options, comboCharts, newUnsupportedChartType(options.Type)
 	}
 	return options, comboCharts, err
 }
 
+// parseChartLayoutOptions provides a function to validate the layout
+// properties of the extended chart types.
+func parseChartLayoutOptions(opts *ChartLayout) error {
+	if opts.QuartileMethod != "" && inStrSlice([]string{"exclusive", "inclusive"}, opts.QuartileMethod, true) == -1 {
+		return newInvalidOptionalValue("QuartileMethod", opts.QuartileMethod, []string{"exclusive", "inclusive"})
+	}
+	if opts.ParentLabelLayout != "" && inStrSlice([]string{"overlapping", "banner", "none"}, opts.ParentLabelLayout, true) == -1 {
+		return newInvalidOptionalValue("ParentLabelLayout", opts.ParentLabelLayout, []string{"overlapping", "banner", "none"})
+	}
+	if opts.BinSize < 0 || opts.BinCount < 0 {
+		return ErrParameterInvalid
+	}
+	return nil
+}
+
 // DeleteChart provides a function to delete chart in spreadsheet by given
 // worksheet name and cell reference.
 func (f *File) DeleteChart(sheet, cell string) error {
@@ -1071,6 +1456,561 @@ func (f *File) DeleteChart(sheet, cell string) error {
 	return f.deleteDrawing(col, row, drawingXML, "Chart")
 }
 
+// chartAnchor defines the cell anchor which contains a chart graphic frame in
+// the drawing part.
+type chartAnchor struct {
+	anchors *[]*xdrCellAnchor
+	anchor  *xdrCellAnchor
+	frame   *decodeChartAnchor
+}
+
+// GetCharts provides a function to get all charts in the worksheet or
+// chartsheet by given sheet name. The charts will be returned in the order of
+// the drawing objects, and the properties which not supported by the AddChart
+// function will be ignored. Only the first chart type will be returned for the
+// combo chart. For example, get the type and the series of the charts in the
+// worksheet named Sheet1:
+//
+//	charts, err := f.GetCharts("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, chart := range charts {
+//	    fmt.Println(chart.Name, chart.Type)
+//	    for _, series := range chart.Series {
+//	        fmt.Println(series.Name, series.Categories, series.Values)
+//	    }
+//	}
+func (f *File) GetCharts(sheet string) ([]Chart, error) {
+	var charts []Chart
+	err := f.rangeCharts(sheet, func(chartXML string, chart *Chart) error {
+		charts = append(charts, *chart)
+		return nil
+	})
+	return charts, err
+}
+
+// ExportCharts provides a function to export all charts in the worksheet or
+// chartsheet by given sheet name as the standalone files. The chart parts and
+// the image parts referenced by the charts will be exported with the original
+// file names and content types in the workbook package, and the format
+// settings of the charts are the same as the GetCharts function returns,
+// which can be encoded as JSON for the description of the charts. For
+// example, export the charts in the worksheet named Sheet1 to the files:
+//
+//	charts, err := f.ExportCharts("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, chart := range charts {
+//	    if err := os.WriteFile(chart.Part.Name, chart.Part.Content, 0644); err != nil {
+//	        fmt.Println(err)
+//	    }
+//	    description, err := json.Marshal(chart.Chart)
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	    if err := os.WriteFile(chart.Part.Name+".json", description, 0644); err != nil {
+//	        fmt.Println(err)
+//	    }
+//	    for _, image := range chart.Images {
+//	        if err := os.WriteFile(image.Name, image.Content, 0644); err != nil {
+//	            fmt.Println(err)
+//	        }
+//	    }
+//	}
+func (f *File) ExportCharts(sheet string) ([]ExportedChart, error) {
+	var charts []ExportedChart
+	err := f.rangeCharts(sheet, func(chartXML string, chart *Chart) error {
+		exported := ExportedChart{Chart: *chart}
+		var err error
+		if exported.Part, err = f.exportPart(chartXML); err != nil {
+			return err
+		}
+		rels, err := f.relsReader(path.Join(path.Dir(chartXML), "_rels", path.Base(chartXML)+".rels"))
+		if err != nil || rels == nil {
+			charts = append(charts, exported)
+			return err
+		}
+		rels.Lock()
+		defer rels.Unlock()
+		for _, rel := range rels.Relationships {
+			if rel.Type != SourceRelationshipImage || rel.TargetMode == "External" {
+				continue
+			}
+			image, err := f.exportPart(getRelationshipsPartPath(path.Dir(chartXML), rel.Target))
+			if err != nil {
+				return err
+			}
+			exported.Images = append(exported.Images, image)
+		}
+		charts = append(charts, exported)
+		return err
+	})
+	return charts, err
+}
+
+// rangeCharts provides a function to parse all charts in the worksheet or
+// chartsheet by given sheet name, and call the given function with the path
+// of the chart part and the format settings of each chart in order.
+func (f *File) rangeCharts(sheet string, fn func(chartXML string, chart *Chart) error) error {
+	drawingXML, _, anchors, err := f.getChartAnchors(sheet)
+	if err != nil {
+		return err
+	}
+	drawingRels := "xl/drawings/_rels/" + path.Base(drawingXML) + ".rels"
+	for _, anchor := range anchors {
+		rel := f.getDrawingRelationships(drawingRels, anchor.frame.GraphicFrame.Graphic.GraphicData.Chart.RID)
+		if rel == nil {
+			continue
+		}
+		chartXML := getRelationshipsPartPath(path.Dir(drawingXML), rel.Target)
+		chart, err := f.getChart(sheet, chartXML, anchor.frame)
+		if err != nil {
+			return err
+		}
+		if err = fn(chartXML, chart); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// DeleteChartByName provides a function to delete chart in the worksheet or
+// chartsheet by given sheet name and chart name. The chart name could be
+// specified by the 'Name' property in the AddChart function, or get by the
+// GetCharts function. All charts with the given name will be deleted, and an
+// error will be returned if the chart doesn't exist.
+func (f *File) DeleteChartByName(sheet, name string) error {
+	return f.deleteChartAnchor(sheet, newNoExistChartError(name), func(idx int, anchor *chartAnchor) bool {
+		return anchor.frame.GraphicFrame.NvGraphicFramePr.CNvPr.Name == name
+	})
+}
+
+// DeleteChartByIndex provides a function to delete chart in the worksheet or
+// chartsheet by given sheet name and the index of the chart in the charts
+// which returned by the GetCharts function, the index starts from 0. An error
+// will be returned if the chart doesn't exist.
+func (f *File) DeleteChartByIndex(sheet string, index int) error {
+	return f.deleteChartAnchor(sheet, newNoExistChartIndexError(index), func(idx int, anchor *chartAnchor) bool {
+		return idx == index
+	})
+}
+
+// deleteChartAnchor provides a function to delete the chart graphic frames
+// which matched by the given function in the worksheet or chartsheet, the
+// charts are indexed in the same order as the GetCharts function returns.
+// The given not exists error will be returned if no chart matched.
+func (f *File) deleteChartAnchor(sheet string, notExistErr error, fn func(idx int, anchor *chartAnchor) bool) error {
+	drawingXML, wsDr, anchors, err := f.getChartAnchors(sheet)
+	if err != nil {
+		return err
+	}
+	if wsDr == nil {
+		return notExistErr
+	}
+	drawingRels := "xl/drawings/_rels/" + path.Base(drawingXML) + ".rels"
+	var idx int
+	for _, anchor := range anchors {
+		if f.getDrawingRelationships(drawingRels, anchor.frame.GraphicFrame.Graphic.GraphicData.Chart.RID) == nil {
+			continue
+		}
+		if idx++; !fn(idx-1, anchor) {
+			continue
+		}
+		wsDr.Lock()
+		for i, cellAnchor := range *anchor.anchors {
+			if cellAnchor == anchor.anchor {
+				*anchor.anchors = append((*anchor.anchors)[:i], (*anchor.anchors)[i+1:]...)
+				break
+			}
+		}
+		wsDr.Unlock()
+		notExistErr = nil
+	}
+	return notExistErr
+}
+
+// getSheetDrawingXML provides a function to get the path of the drawing part
+// by given worksheet or chartsheet name, returns empty string if the sheet
+// has no drawing.
+func (f *File) getSheetDrawingXML(sheet string) (string, error) {
+	name, ok := f.getSheetXMLPath(sheet)
+	if !ok {
+		return "", newNoExistSheetError(sheet)
+	}
+	if !strings.HasPrefix(name, "xl/chartsheets/") {
+		ws, err := f.workSheetReader(sheet)
+		if err != nil || ws.Drawing == nil {
+			return "", err
+		}
+		target := f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID)
+		if target == "" {
+			return "", err
+		}
+		return getRelationshipsPartPath("xl/worksheets", target), err
+	}
+	cs := new(xlsxChartsheet)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(name)))).
+		Decode(cs); err != nil && err != io.EOF {
+		return "", err
+	}
+	if cs.Drawing == nil {
+		return "", nil
+	}
+	rel := f.getDrawingRelationships("xl/chartsheets/_rels/"+path.Base(name)+".rels", cs.Drawing.RID)
+	if rel == nil {
+		return "", nil
+	}
+	return getRelationshipsPartPath("xl/chartsheets", rel.Target), nil
+}
+
+// getChartAnchors provides a function to get the path of the drawing part,
+// the drawing and the cell anchors which contains chart graphic frame by given
+// worksheet or chartsheet name.
+func (f *File) getChartAnchors(sheet string) (string, *xlsxWsDr, []*chartAnchor, error) {
+	var anchors []*chartAnchor
+	drawingXML, err := f.getSheetDrawingXML(sheet)
+	if err != nil || drawingXML == "" {
+		return drawingXML, nil, anchors, err
+	}
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return drawingXML, wsDr, anchors, err
+	}
+	wsDr.Lock()
+	defer wsDr.Unlock()
+	for _, cellAnchors := range []*[]*xdrCellAnchor{&wsDr.TwoCellAnchor, &wsDr.OneCellAnchor, &wsDr.AbsoluteAnchor} {
+		for _, cellAnchor := range *cellAnchors {
+			frame := &decodeChartAnchor{ClientData: &decodeClientData{FLocksWithSheet: true, FPrintsWithSheet: true}}
+			if err = f.xmlNewDecoder(strings.NewReader("<decodeChartAnchor>" + cellAnchor.GraphicFrame + "</decodeChartAnchor>")).
+				Decode(frame); err != nil && err != io.EOF {
+				return drawingXML, wsDr, anchors, err
+			}
+			err = nil
+			if frame.GraphicFrame == nil || frame.GraphicFrame.Graphic.GraphicData.Chart == nil {
+				continue
+			}
+			if cellAnchor.From != nil {
+				frame.From = &decodeFrom{Col: cellAnchor.From.Col, ColOff: cellAnchor.From.ColOff, Row: cellAnchor.From.Row, RowOff: cellAnchor.From.RowOff}
+			}
+			if cellAnchor.To != nil {
+				frame.To = &decodeTo{Col: cellAnchor.To.Col, ColOff: cellAnchor.To.ColOff, Row: cellAnchor.To.Row, RowOff: cellAnchor.To.RowOff}
+			}
+			if cellAnchor.ClientData != nil {
+				frame.ClientData = &decodeClientData{FLocksWithSheet: cellAnchor.ClientData.FLocksWithSheet, FPrintsWithSheet: cellAnchor.ClientData.FPrintsWithSheet}
+			}
+			anchors = append(anchors, &chartAnchor{anchors: cellAnchors, anchor: cellAnchor, frame: frame})
+		}
+	}
+	return drawingXML, wsDr, anchors, err
+}
+
+// getChart provides a function to parse the chart part by given sheet name,
+// path of the chart part and the cell anchor of the chart.
+func (f *File) getChart(sheet, chartXML string, anchor *decodeChartAnchor) (*Chart, error) {
+	var (
+		cs     xlsxChartSpace
+		decode decodeChartSpace
+		chart  = &Chart{Name: anchor.GraphicFrame.NvGraphicFramePr.CNvPr.Name}
+	)
+	content := namespaceStrictToTransitional(f.readXML(chartXML))
+	if err := f.xmlNewDecoder(bytes.NewReader(content)).Decode(&cs); err != nil && err != io.EOF {
+		return chart, err
+	}
+	if err := f.xmlNewDecoder(bytes.NewReader(content)).Decode(&decode); err != nil && err != io.EOF {
+		return chart, err
+	}
+	chart.Format = GraphicOptions{
+		Locked:      boolPtr(anchor.ClientData.FLocksWithSheet),
+		PrintObject: boolPtr(anchor.ClientData.FPrintsWithSheet),
+		ScaleX:      defaultPictureScale,
+		ScaleY:      defaultPictureScale,
+	}
+	if anchor.From != nil && anchor.To != nil {
+		chart.Format.OffsetX, chart.Format.OffsetY = anchor.From.ColOff/EMU, anchor.From.RowOff/EMU
+		width, height := anchor.To.ColOff/EMU-chart.Format.OffsetX, anchor.To.RowOff/EMU-chart.Format.OffsetY
+		for col := anchor.From.Col; col < anchor.To.Col; col++ {
+			width += f.getColWidth(sheet, col+1)
+		}
+		for row := anchor.From.Row; row < anchor.To.Row; row++ {
+			height += f.getRowHeight(sheet, row+1)
+		}
+		chart.Dimension = ChartDimension{Width: uint(math.Max(float64(width), 0)), Height: uint(math.Max(float64(height), 0))}
+	}
+	if decode.Title != nil {
+		var paragraphs []string
+		for _, p := range decode.Title.P {
+			var text string
+			for _, r := range p.R {
+				text += r.T
+			}
+			paragraphs = append(paragraphs, text)
+		}
+		chart.Title.Name = strings.Join(paragraphs, "\n")
+	}
+	chart.Legend.Position = "none"
+	if legend := cs.Chart.Legend; legend != nil {
+		chart.Legend.Position = defaultChartLegendPosition
+		if legend.LegendPos != nil && legend.LegendPos.Val != nil {
+			for position, val := range chartLegendPosition {
+				if val == *legend.LegendPos.Val {
+					chart.Legend.Position = position
+				}
+			}
+		}
+	}
+	if cs.Chart.DispBlanksAs != nil && cs.Chart.DispBlanksAs.Val != nil {
+		chart.ShowBlanksAs = *cs.Chart.DispBlanksAs.Val
+	}
+	plotArea := cs.Chart.PlotArea
+	chartType, group := f.getChartType(plotArea)
+	if group == nil {
+		return chart, nil
+	}
+	chart.Type = chartType
+	if group.VaryColors != nil {
+		chart.VaryColors = group.VaryColors.Val
+	}
+	if group.HoleSize != nil && group.HoleSize.Val != nil {
+		chart.HoleSize = *group.HoleSize.Val
+	}
+	if dLbls := group.DLbls; dLbls != nil {
+		chart.Legend.ShowLegendKey = getAttrValBool(dLbls.ShowLegendKey)
+		chart.PlotArea = ChartPlotArea{
+			ShowBubbleSize:  getAttrValBool(dLbls.ShowBubbleSize),
+			ShowCatName:     getAttrValBool(dLbls.ShowCatName),
+			ShowLeaderLines: getAttrValBool(dLbls.ShowLeaderLines),
+			ShowPercent:     getAttrValBool(dLbls.ShowPercent),
+			ShowSerName:     getAttrValBool(dLbls.ShowSerName),
+			ShowVal:         getAttrValBool(dLbls.ShowVal),
+		}
+	}
+	if len(plotArea.CatAx) > 0 {
+		chart.XAxis = getChartAxis(plotArea.CatAx[0])
+	}
+	if len(plotArea.ValAx) > 0 {
+		chart.YAxis = getChartAxis(plotArea.ValAx[0])
+	}
+	if len(plotArea.CatAx) == 0 && len(plotArea.ValAx) > 1 {
+		chart.XAxis, chart.YAxis = chart.YAxis, getChartAxis(plotArea.ValAx[1])
+	}
+	var lines []decodeChartSer
+	if decode.PlotArea != nil {
+		for _, g := range decode.PlotArea.Charts {
+			if g.XMLName.Local == getChartGroupName(plotArea, group) {
+				lines = g.Ser
+				break
+			}
+		}
+	}
+	if group.Ser != nil {
+		for i, ser := range *group.Ser {
+			series := getChartSeries(ser)
+			if i < len(lines) && lines[i].Ln != nil && chartType == Line {
+				series.Line.Width = float64(lines[i].Ln.W) / 12700
+				if lines[i].Ln.SrgbClr != nil && lines[i].Ln.SrgbClr.Val != nil {
+					series.Line.Color = *lines[i].Ln.SrgbClr.Val
+				}
+			}
+			chart.Series = append(chart.Series, series)
+		}
+	}
+	return chart, nil
+}
+
+// getChartType provides a function to get the chart type and the chart group
+// element by given plot area, the first chart group in the plot area will be
+// used for the combo chart.
+func (f *File) getChartType(plotArea *cPlotArea) (string, *cCharts) {
+	if plotArea == nil {
+		return "", nil
+	}
+	for _, group := range []struct {
+		element *cCharts
+		types   []string
+	}{
+		{plotArea.AreaChart, []string{Area, AreaStacked, AreaPercentStacked}},
+		{plotArea.Area3DChart, []string{Area3D, Area3DStacked, Area3DPercentStacked}},
+		{plotArea.BarChart, []string{Col, ColStacked, ColPercentStacked, Bar, BarStacked, BarPercentStacked}},
+		{plotArea.Bar3DChart, []string{
+			Col3DClustered, Col3DStacked, Col3DPercentStacked, Col3D,
+			Col3DConeClustered, Col3DConeStacked, Col3DConePercentStacked, Col3DCone,
+			Col3DPyramidClustered, Col3DPyramidStacked, Col3DPyramidPercentStacked, Col3DPyramid,
+			Col3DCylinderClustered, Col3DCylinderStacked, Col3DCylinderPercentStacked, Col3DCylinder,
+			Bar3DClustered, Bar3DStacked, Bar3DPercentStacked,
+			Bar3DConeClustered, Bar3DConeStacked, Bar3DConePercentStacked,
+			Bar3DPyramidClustered, Bar3DPyramidStacked, Bar3DPyramidPercentStacked,
+			Bar3DCylinderClustered, Bar3DCylinderStacked, Bar3DCylinderPercentStacked,
+		}},
+		{plotArea.BubbleChart, []string{Bubble, Bubble3D}},
+		{plotArea.DoughnutChart, []string{Doughnut}},
+		{plotArea.LineChart, []string{Line}},
+		{plotArea.Line3DChart, []string{Line3D}},
+		{plotArea.PieChart, []string{Pie}},
+		{plotArea.Pie3DChart, []string{Pie3D}},
+		{plotArea.OfPieChart, []string{PieOfPieChart, BarOfPieChart}},
+		{plotArea.RadarChart, []string{Radar}},
+		{plotArea.ScatterChart, []string{Scatter}},
+		{plotArea.Surface3DChart, []string{Surface3D, WireframeSurface3D}},
+		{plotArea.SurfaceChart, []string{Contour, WireframeContour}},
+	} {
+		if group.element == nil {
+			continue
+		}
+		for _, chartType := range group.types {
+			if f.matchChartType(chartType, group.element) {
+				return chartType, group.element
+			}
+		}
+		return group.types[0], group.element
+	}
+	return "", nil
+}
+
+// matchChartType provides a function to check if the chart group element
+// matches the given chart type.
+func (f *File) matchChartType(chartType string, c *cCharts) bool {
+	getVal := func(attr *attrValString, defaultVal string) string {
+		if attr == nil || attr.Val == nil {
+			return defaultVal
+		}
+		return *attr.Val
+	}
+	if barDir, ok := plotAreaChartBarDir[chartType]; ok && getVal(c.BarDir, "col") != barDir {
+		return false
+	}
+	if grouping, ok := plotAreaChartGrouping[chartType]; ok && getVal(c.Grouping, "clustered") != grouping {
+		return false
+	}
+	if getVal(f.drawChartShape(&Chart{Type: chartType}), "box") != getVal(c.Shape, "box") {
+		return false
+	}
+	if ofPieType, ok := map[string]string{PieOfPieChart: "pie", BarOfPieChart: "bar"}[chartType]; ok && getVal(c.OfPieType, "pie") != ofPieType {
+		return false
+	}
+	if (chartType == WireframeSurface3D || chartType == WireframeContour) != getAttrValBool(c.Wireframe) {
+		return false
+	}
+	var bubble3D bool
+	if c.Ser != nil {
+		for _, ser := range *c.Ser {
+			bubble3D = bubble3D || getAttrValBool(ser.Bubble3D)
+		}
+	}
+	return (chartType == Bubble3D) == bubble3D
+}
+
+// getChartGroupName provides a function to get the element name of the chart
+// group in the plot area.
+func getChartGroupName(plotArea *cPlotArea, group *cCharts) string {
+	for name, element := range map[string]*cCharts{
+		"areaChart": plotArea.AreaChart, "area3DChart": plotArea.Area3DChart,
+		"barChart": plotArea.BarChart, "bar3DChart": plotArea.Bar3DChart,
+		"bubbleChart": plotArea.BubbleChart, "doughnutChart": plotArea.DoughnutChart,
+		"lineChart": plotArea.LineChart, "line3DChart": plotArea.Line3DChart,
+		"pieChart": plotArea.PieChart, "pie3DChart": plotArea.Pie3DChart,
+		"ofPieChart": plotArea.OfPieChart, "radarChart": plotArea.RadarChart,
+		"scatterChart": plotArea.ScatterChart, "surface3DChart": plotArea.Surface3DChart,
+		"surfaceChart": plotArea.SurfaceChart,
+	} {
+		if element == group {
+			return name
+		}
+	}
+	return ""
+}
+
+// getChartAxis provides a function to get the chart axis settings by given
+// axis element.
+func getChartAxis(ax *cAxs) ChartAxis {
+	axis := ChartAxis{
+		None:           getAttrValBool(ax.Delete),
+		MajorGridLines: ax.MajorGridlines != nil,
+		MinorGridLines: ax.MinorGridlines != nil,
+	}
+	if ax.MajorUnit != nil && ax.MajorUnit.Val != nil {
+		axis.MajorUnit = *ax.MajorUnit.Val
+	}
+	if ax.TickLblSkip != nil && ax.TickLblSkip.Val != nil {
+		axis.TickLabelSkip = *ax.TickLblSkip.Val
+	}
+	if scaling := ax.Scaling; scaling != nil {
+		if scaling.Orientation != nil && scaling.Orientation.Val != nil {
+			axis.ReverseOrder = *scaling.Orientation.Val == orientation[true]
+		}
+		if scaling.Max != nil {
+			axis.Maximum = scaling.Max.Val
+		}
+		if scaling.Min != nil {
+			axis.Minimum = scaling.Min.Val
+		}
+		if scaling.LogBase != nil && scaling.LogBase.Val != nil {
+			axis.LogBase = *scaling.LogBase.Val
+		}
+	}
+	return axis
+}
+
+// getChartSeries provides a function to get the chart series settings by
+// given series element.
+func getChartSeries(ser cSer) ChartSeries {
+	var series ChartSeries
+	if ser.Tx != nil && ser.Tx.StrRef != nil {
+		series.Name = ser.Tx.StrRef.F
+	}
+	for _, cat := range []*cCat{ser.Cat, ser.XVal} {
+		if cat == nil {
+			continue
+		}
+		if cat.StrRef != nil {
+			series.Categories = cat.StrRef.F
+		}
+		if cat.StrLit != nil {
+			for _, pt := range cat.StrLit.Pt {
+				if pt.V != nil {
+					series.CategoriesLiteral = append(series.CategoriesLiteral, *pt.V)
+				}
+			}
+		}
+	}
+	for _, val := range []*cVal{ser.Val, ser.YVal} {
+		if val == nil {
+			continue
+		}
+		if val.NumRef != nil {
+			series.Values = val.NumRef.F
+		}
+		if val.NumLit != nil {
+			for _, pt := range val.NumLit.Pt {
+				if pt.V != nil {
+					value, _ := strconv.ParseFloat(*pt.V, 64)
+					series.ValuesLiteral = append(series.ValuesLiteral, value)
+				}
+			}
+		}
+	}
+	series.Line.Smooth = getAttrValBool(ser.Smooth)
+	if ser.Marker != nil {
+		if ser.Marker.Symbol != nil && ser.Marker.Symbol.Val != nil {
+			series.Marker.Symbol = *ser.Marker.Symbol.Val
+		}
+		if ser.Marker.Size != nil && ser.Marker.Size.Val != nil {
+			series.Marker.Size = *ser.Marker.Size.Val
+		}
+	}
+	return series
+}
+
+// getAttrValBool provides a function to get the boolean value by given
+// attribute, returns false if the attribute or the value is nil.
+func getAttrValBool(attr *attrValBool) bool {
+	return attr != nil && attr.Val != nil && *attr.Val
+}
+
 // countCharts provides a function to get chart files count storage in the
 // folder xl/charts.
 func (f *File) countCharts() int {
@@ -1093,3 +2033,184 @@ func (f *File) ptToEMUs(pt float64) int {
 	}
 	return int(12700 * pt)
 }
+
+// ChartDataSheetName defined the name of the very hidden worksheet which
+// stores the chart data set by the SetChartData function.
+const ChartDataSheetName = "_xlChartData"
+
+// chartDataNamePrefix defined the prefix of the hidden defined names which
+// refer to the chart data in the chart data worksheet.
+const chartDataNamePrefix = "_xlChartData."
+
+// chartDataNameExp defined the regular expression of the chart data name.
+var chartDataNameExp = regexp.MustCompile(`^[\p{L}_][\p{L}\p{N}_.]*$`)
+
+// SetChartData provides a function to write the chart data into the very
+// hidden chart data worksheet by given chart data name and data, and returns
+// the chart series which bind to the data for the AddChart and AddChartSheet
+// functions. The chart data worksheet will be created automatically, and the
+// chart data with the same name will be replaced. The name of the chart data
+// can contains letters, digits, underscores and periods, and should starts
+// with a letter or an underscore. For example, create a column chart without
+// writing the data into the visible worksheets:
+//
+//	series, err := f.SetChartData("Sales", &excelize.ChartData{
+//	    Categories: []string{"Q1", "Q2", "Q3", "Q4"},
+//	    Series: []excelize.ChartDataSeries{
+//	        {Name: "2022", Values: []float64{120, 96, 143, 180}},
+//	        {Name: "2023", Values: []float64{132, 110, 151, 196}},
+//	    },
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.AddChart("Sheet1", "E1", &excelize.Chart{Type: "col", Series: series})
+//
+// Note that the data will be kept in the workbook after deleting the chart,
+// use the DeleteChartData function to remove the data which are no longer in
+// use.
+func (f *File) SetChartData(name string, data *ChartData) ([]ChartSeries, error) {
+	var series []ChartSeries
+	if data == nil || !chartDataNameExp.MatchString(name) {
+		return series, ErrParameterInvalid
+	}
+	if err := f.DeleteChartData(name); err != nil && err != ErrDefinedNameScope {
+		return series, err
+	}
+	idx, err := f.GetSheetIndex(ChartDataSheetName)
+	if err != nil {
+		return series, err
+	}
+	if idx == -1 {
+		if _, err = f.NewSheet(ChartDataSheetName); err != nil {
+			return series, err
+		}
+		if err = f.SetSheetVisible(ChartDataSheetName, false, true); err != nil {
+			return series, err
+		}
+	}
+	col := 1
+	for _, coordinates := range f.getChartDataRanges() {
+		if coordinates[2]+2 > col {
+			col = coordinates[2] + 2
+		}
+	}
+	rows := len(data.Categories)
+	for _, s := range data.Series {
+		if len(s.Values) > rows {
+			rows = len(s.Values)
+		}
+	}
+	sheet := quoteSheetName(ChartDataSheetName)
+	categories := make([]interface{}, rows+1)
+	categories[0] = name
+	for i, category := range data.Categories {
+		categories[i+1] = category
+	}
+	cell, _ := CoordinatesToCellName(col, 1)
+	if err = f.SetSheetCol(ChartDataSheetName, cell, &categories); err != nil {
+		return series, err
+	}
+	for i, s := range data.Series {
+		values := make([]interface{}, len(s.Values)+1)
+		values[0] = s.Name
+		for j, value := range s.Values {
+			values[j+1] = value
+		}
+		header, _ := CoordinatesToCellName(col+i+1, 1, true)
+		if err = f.SetSheetCol(ChartDataSheetName, strings.ReplaceAll(header, "$", ""), &values); err != nil {
+			return series, err
+		}
+		chartSeries := ChartSeries{Name: sheet + "!" + header}
+		if len(data.Categories) > 0 {
+			from, _ := CoordinatesToCellName(col, 2, true)
+			to, _ := CoordinatesToCellName(col, len(data.Categories)+1, true)
+			chartSeries.Categories = sheet + "!" + from + ":" + to
+		}
+		from, _ := CoordinatesToCellName(col+i+1, 2, true)
+		to, _ := CoordinatesToCellName(col+i+1, int(math.Max(float64(len(s.Values)), 1))+1, true)
+		chartSeries.Values = sheet + "!" + from + ":" + to
+		series = append(series, chartSeries)
+	}
+	from, _ := CoordinatesToCellName(col, 1, true)
+	to, _ := CoordinatesToCellName(col+len(data.Series), rows+1, true)
+	wb, err := f.workbookReader()
+	if err != nil {
+		return series, err
+	}
+	if wb.DefinedNames == nil {
+		wb.DefinedNames = &xlsxDefinedNames{}
+	}
+	wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, xlsxDefinedName{
+		Name:   chartDataNamePrefix + name,
+		Hidden: true,
+		Data:   sheet + "!" + from + ":" + to,
+	})
+	return series, err
+}
+
+// DeleteChartData provides a function to delete the chart data by given chart
+// data name which set by the SetChartData function. The chart data worksheet
+// will be deleted if there is no chart data in it. Note that the charts which
+// refer to the deleted data will show empty series.
+func (f *File) DeleteChartData(name string) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.DefinedNames == nil {
+		return ErrDefinedNameScope
+	}
+	for idx, dn := range wb.DefinedNames.DefinedName {
+		if dn.LocalSheetID != nil || dn.Name != chartDataNamePrefix+name {
+			continue
+		}
+		wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName[:idx], wb.DefinedNames.DefinedName[idx+1:]...)
+		if coordinates, err := getChartDataRange(dn.Data); err == nil {
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				for row := coordinates[1]; row <= coordinates[3]; row++ {
+					cell, _ := CoordinatesToCellName(col, row)
+					if err = f.SetCellValue(ChartDataSheetName, cell, nil); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(f.getChartDataRanges()) == 0 {
+			return f.DeleteSheet(ChartDataSheetName)
+		}
+		return nil
+	}
+	return ErrDefinedNameScope
+}
+
+// getChartDataRanges provides a function to get the range coordinates of all
+// chart data in the chart data worksheet.
+func (f *File) getChartDataRanges() [][]int {
+	var ranges [][]int
+	wb, _ := f.workbookReader()
+	if wb == nil || wb.DefinedNames == nil {
+		return ranges
+	}
+	for _, dn := range wb.DefinedNames.DefinedName {
+		if dn.LocalSheetID != nil || !strings.HasPrefix(dn.Name, chartDataNamePrefix) {
+			continue
+		}
+		if coordinates, err := getChartDataRange(dn.Data); err == nil {
+			ranges = append(ranges, coordinates)
+		}
+	}
+	return ranges
+}
+
+// getChartDataRange provides a function to get the range coordinates by given
+// reference of the chart data.
+func getChartDataRange(ref string) ([]int, error) {
+	ref = strings.ReplaceAll(ref[strings.LastIndex(ref, "!")+1:], "$", "")
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return coordinates, err
+	}
+	return coordinates, sortCoordinates(coordinates)
+}
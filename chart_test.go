@@ -102,18 +102,18 @@ func TestChartSize(t *testing.T) {
 
 func TestAddDrawingChart(t *testing.T) {
 	f := NewFile()
-	assert.EqualError(t, f.addDrawingChart("SheetN", "", "", 0, 0, 0, nil), newCellNameToCoordinatesError("", newInvalidCellNameError("")).Error())
+	assert.EqualError(t, f.addDrawingChart("SheetN", "", "", 0, nil), newCellNameToCoordinatesError("", newInvalidCellNameError("")).Error())
 	
 	path := "xl/drawings/drawing1.xml"
 	f.Pkg.Store(path, MacintoshCyrillicCharset)
-	assert.EqualError(t, f.addDrawingChart("Sheet1", path, "A1", 0, 0, 0, &GraphicOptions{PrintObject: boolPtr(true), Locked: boolPtr(false)}), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.addDrawingChart("Sheet1", path, "A1", 0, &Chart{Format: GraphicOptions{PrintObject: boolPtr(true), Locked: boolPtr(false)}}), "XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestAddSheetDrawingChart(t *testing.T) {
 	f := NewFile()
 	path := "xl/drawings/drawing1.xml"
 	f.Pkg.Store(path, MacintoshCyrillicCharset)
-	assert.EqualError(t, f.addSheetDrawingChart(path, 0, &GraphicOptions{PrintObject: boolPtr(true), Locked: boolPtr(false)}), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.addSheetDrawingChart(path, 0, &Chart{Format: GraphicOptions{PrintObject: boolPtr(true), Locked: boolPtr(false)}}), "XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestDeleteDrawing(t *testing.T) {
@@ -484,3 +484,594 @@ func TestChartWithLogarithmicBase(t *testing.T) {
 		}
 	}
 }
+
+func TestAddChartWithLiteralSeries(t *testing.T) {
+	f := NewFile()
+	series := []ChartSeries{
+		{Name: "Sheet1!$A$1", CategoriesLiteral: []string{"Q1", "Q2", "Q3"}, ValuesLiteral: []float64{120, 96.5, 143}},
+		{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", CategoriesLiteral: []string{"Q1"}, Values: "Sheet1!$B$2:$D$2", ValuesLiteral: []float64{1}},
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "A1", &Chart{Type: "col", Series: series}))
+	assert.NoError(t, f.AddChart("Sheet1", "J1", &Chart{Type: "scatter", Series: series[:1]}))
+	assert.NoError(t, f.AddChart("Sheet1", "A20", &Chart{Type: "bubble", Series: series[:1]}))
+	for _, chartPath := range []string{"xl/charts/chart1.xml", "xl/charts/chart2.xml", "xl/charts/chart3.xml"} {
+		chart, ok := f.Pkg.Load(chartPath)
+		assert.True(t, ok)
+		content := string(chart.([]byte))
+		assert.Contains(t, content, `<numLit><formatCode>General</formatCode><ptCount val="3"></ptCount><pt idx="0"><v>120</v></pt><pt idx="1"><v>96.5</v></pt><pt idx="2"><v>143</v></pt></numLit>`)
+	}
+	chart, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	content := string(chart.([]byte))
+	assert.Contains(t, content, `<strLit><ptCount val="3"></ptCount><pt idx="0"><v>Q1</v></pt><pt idx="1"><v>Q2</v></pt><pt idx="2"><v>Q3</v></pt></strLit>`)
+	// Test the references take precedence over the literals
+	assert.Contains(t, content, `<strRef><f>Sheet1!$B$1:$D$1</f></strRef>`)
+	assert.Contains(t, content, `<numRef><f>Sheet1!$B$2:$D$2</f></numRef>`)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartWithLiteralSeries.xlsx")))
+}
+
+func TestSetChartData(t *testing.T) {
+	f := NewFile()
+	series, err := f.SetChartData("Sales", &ChartData{
+		Categories: []string{"Q1", "Q2", "Q3"},
+		Series: []ChartDataSeries{
+			{Name: "2022", Values: []float64{120, 96, 143}},
+			{Name: "2023", Values: []float64{132, 110, 151}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ChartSeries{
+		{Name: "_xlChartData!$B$1", Categories: "_xlChartData!$A$2:$A$4", Values: "_xlChartData!$B$2:$B$4"},
+		{Name: "_xlChartData!$C$1", Categories: "_xlChartData!$A$2:$A$4", Values: "_xlChartData!$C$2:$C$4"},
+	}, series)
+	visible, err := f.GetSheetVisible(ChartDataSheetName)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	val, err := f.GetCellValue(ChartDataSheetName, "C4")
+	assert.NoError(t, err)
+	assert.Equal(t, "151", val)
+	assert.NoError(t, f.AddChart("Sheet1", "E1", &Chart{Type: Col, Series: series}))
+	// Test set the chart data next to the existing chart data
+	series, err = f.SetChartData("Share", &ChartData{Series: []ChartDataSeries{{Name: "Share", Values: []float64{0.4, 0.6}}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []ChartSeries{{Name: "_xlChartData!$F$1", Values: "_xlChartData!$F$2:$F$3"}}, series)
+	// Test replace the existing chart data
+	series, err = f.SetChartData("Sales", &ChartData{Series: []ChartDataSeries{{Name: "2024", Values: []float64{150}}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []ChartSeries{{Name: "_xlChartData!$I$1", Values: "_xlChartData!$I$2:$I$2"}}, series)
+	val, err = f.GetCellValue(ChartDataSheetName, "C4")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetChartData.xlsx")))
+	// Test set the chart data with invalid parameters
+	_, err = f.SetChartData("Sales", nil)
+	assert.Equal(t, ErrParameterInvalid, err)
+	_, err = f.SetChartData("1Sales", &ChartData{})
+	assert.Equal(t, ErrParameterInvalid, err)
+	// Test delete the chart data
+	assert.NoError(t, f.DeleteChartData("Sales"))
+	assert.Equal(t, ErrDefinedNameScope, f.DeleteChartData("Sales"))
+	assert.NoError(t, f.DeleteChartData("Share"))
+	assert.Equal(t, []string{"Sheet1"}, f.GetSheetList())
+	assert.Equal(t, ErrDefinedNameScope, f.DeleteChartData("Share"))
+	// Test set the chart data with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.SetChartData("Sales", &ChartData{})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	f.WorkBook = nil
+	assert.EqualError(t, f.DeleteChartData("Sales"), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetCharts(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{nil, "Apple", "Orange"}, {"Small", 2, 3}, {"Normal", 5, 2}, {"Large", 6, 7}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	maximum := 10.0
+	assert.NoError(t, f.AddChart("Sheet1", "E1", &Chart{
+		Type: Line,
+		Name: "Sales",
+		Series: []ChartSeries{
+			{Name: "Sheet1!$B$1", Categories: "Sheet1!$A$2:$A$4", Values: "Sheet1!$B$2:$B$4", Line: ChartLine{Color: "FF0000", Width: 1.5, Smooth: true}, Marker: ChartMarker{Symbol: "square", Size: 7}},
+			{Name: "Sheet1!$C$1", Categories: "Sheet1!$A$2:$A$4", Values: "Sheet1!$C$2:$C$4"},
+		},
+		Format:       GraphicOptions{OffsetX: 15, OffsetY: 10},
+		Legend:       ChartLegend{Position: "top", ShowLegendKey: true},
+		Title:        ChartTitle{Name: "Fruits"},
+		XAxis:        ChartAxis{ReverseOrder: true, TickLabelSkip: 2},
+		YAxis:        ChartAxis{MajorGridLines: true, MajorUnit: 2, Maximum: &maximum},
+		PlotArea:     ChartPlotArea{ShowVal: true},
+		ShowBlanksAs: "zero",
+	}))
+	assert.NoError(t, f.AddChart("Sheet1", "E20", &Chart{
+		Type:     Doughnut,
+		Series:   []ChartSeries{{CategoriesLiteral: []string{"A", "B"}, ValuesLiteral: []float64{1, 2}}},
+		Legend:   ChartLegend{Position: "none"},
+		HoleSize: 30,
+	}))
+	check := func(charts []Chart) {
+		assert.Len(t, charts, 2)
+		chart := charts[0]
+		assert.Equal(t, "Sales", chart.Name)
+		assert.Equal(t, Line, chart.Type)
+		assert.Equal(t, "Fruits", chart.Title.Name)
+		assert.Equal(t, ChartLegend{Position: "top", ShowLegendKey: true}, chart.Legend)
+		assert.Equal(t, "zero", chart.ShowBlanksAs)
+		assert.Equal(t, ChartPlotArea{ShowVal: true}, chart.PlotArea)
+		assert.Equal(t, ChartDimension{Width: 480, Height: 290}, chart.Dimension)
+		assert.Equal(t, 15, chart.Format.OffsetX)
+		assert.Equal(t, 10, chart.Format.OffsetY)
+		assert.True(t, *chart.Format.PrintObject)
+		assert.False(t, *chart.Format.Locked)
+		assert.Equal(t, ChartAxis{ReverseOrder: true, TickLabelSkip: 2}, chart.XAxis)
+		assert.Equal(t, ChartAxis{MajorGridLines: true, MajorUnit: 2, Maximum: &maximum}, chart.YAxis)
+		assert.Equal(t, []ChartSeries{
+			{Name: "Sheet1!$B$1", Categories: "Sheet1!$A$2:$A$4", Values: "Sheet1!$B$2:$B$4", Line: ChartLine{Color: "FF0000", Width: 1.5, Smooth: true}, Marker: ChartMarker{Symbol: "square", Size: 7}},
+			{Name: "Sheet1!$C$1", Categories: "Sheet1!$A$2:$A$4", Values: "Sheet1!$C$2:$C$4", Line: ChartLine{Width: 2}, Marker: ChartMarker{Size: 5}},
+		}, chart.Series)
+		chart = charts[1]
+		assert.Equal(t, "Chart 3", chart.Name)
+		assert.Equal(t, Doughnut, chart.Type)
+		assert.Equal(t, "none", chart.Legend.Position)
+		assert.Equal(t, 30, chart.HoleSize)
+		assert.Equal(t, []ChartSeries{{CategoriesLiteral: []string{"A", "B"}, ValuesLiteral: []float64{1, 2}}}, chart.Series)
+	}
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	check(charts)
+	// Test get charts after save and reopen the workbook
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{Type: Bar3DConeStacked, Name: "Bar", Series: []ChartSeries{{Values: "Sheet1!$B$2:$B$4"}}}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetCharts.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestGetCharts.xlsx"))
+	assert.NoError(t, err)
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	check(charts)
+	charts, err = f.GetCharts("Chart1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	assert.Equal(t, "Bar", charts[0].Name)
+	assert.Equal(t, Bar3DConeStacked, charts[0].Type)
+	// Test get charts on worksheet without drawing
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	charts, err = f.GetCharts("Sheet2")
+	assert.NoError(t, err)
+	assert.Empty(t, charts)
+	// Test get charts on not exists worksheet
+	_, err = f.GetCharts("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get charts with unsupported charset chart part
+	f.Pkg.Store("xl/charts/chart1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetCharts("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get charts with unsupported charset chartsheet
+	f.Pkg.Store("xl/chartsheets/sheet2.xml", MacintoshCyrillicCharset)
+	_, err = f.GetCharts("Chart1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestGetChartType(t *testing.T) {
+	f := NewFile()
+	for chartType := range chartValAxNumFmtFormatCode {
+		chart, err := parseChartOptions(&Chart{Type: chartType, Series: []ChartSeries{{Values: "Sheet1!$A$1:$A$2"}}})
+		assert.NoError(t, err)
+		plotArea := map[string]func(*Chart) *cPlotArea{
+			Doughnut: f.drawDoughnutChart, Line: f.drawLineChart, Line3D: f.drawLine3DChart,
+			Pie: f.drawPieChart, Pie3D: f.drawPie3DChart, PieOfPieChart: f.drawPieOfPieChart,
+			BarOfPieChart: f.drawBarOfPieChart, Radar: f.drawRadarChart, Scatter: f.drawScatterChart,
+			Surface3D: f.drawSurface3DChart, WireframeSurface3D: f.drawSurface3DChart,
+			Contour: f.drawSurfaceChart, WireframeContour: f.drawSurfaceChart,
+		}
+		draw, ok := plotArea[chartType]
+		if !ok {
+			draw = f.drawBaseChart
+		}
+		actual, _ := f.getChartType(draw(chart))
+		assert.Equal(t, chartType, actual)
+	}
+	chartType, group := f.getChartType(nil)
+	assert.Empty(t, chartType)
+	assert.Nil(t, group)
+	chartType, group = f.getChartType(&cPlotArea{})
+	assert.Empty(t, chartType)
+	assert.Nil(t, group)
+	chartType, _ = f.getChartType(&cPlotArea{AreaChart: &cCharts{Grouping: &attrValString{Val: stringPtr("unknown")}}})
+	assert.Equal(t, Area, chartType)
+}
+
+func TestParseChartType(t *testing.T) {
+	for _, name := range []string{"col", "bar3DConeStacked", "waterfall", "boxWhisker"} {
+		chartType, err := ParseChartType(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, chartType.String())
+	}
+	chartType, err := ParseChartType("column")
+	assert.EqualError(t, err, newUnsupportedChartType("column").Error())
+	assert.Equal(t, ChartType("column"), chartType)
+}
+
+func TestDeleteChartByName(t *testing.T) {
+	f := NewFile()
+	series := []ChartSeries{{Values: "Sheet1!$A$1:$A$2"}}
+	assert.NoError(t, f.AddChart("Sheet1", "A1", &Chart{Type: Col, Name: "Chart A", Series: series}))
+	assert.NoError(t, f.AddChart("Sheet1", "A20", &Chart{Type: Pie, Name: "Chart B", Series: series}))
+	assert.NoError(t, f.AddChart("Sheet1", "A40", &Chart{Type: Line, Name: "Chart C", Series: series}))
+	assert.NoError(t, f.AddPicture("Sheet1", "K1", filepath.Join("test", "images", "excel.png"), nil))
+	assert.NoError(t, f.DeleteChartByName("Sheet1", "Chart B"))
+	assert.Equal(t, newNoExistChartError("Chart N"), f.DeleteChartByName("Sheet1", "Chart N"))
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 2)
+	assert.Equal(t, "Chart A", charts[0].Name)
+	assert.Equal(t, "Chart C", charts[1].Name)
+	assert.NoError(t, f.DeleteChartByIndex("Sheet1", 1))
+	assert.Equal(t, newNoExistChartIndexError(5), f.DeleteChartByIndex("Sheet1", 5))
+	assert.Equal(t, newNoExistChartIndexError(-1), f.DeleteChartByIndex("Sheet1", -1))
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	assert.Equal(t, "Chart A", charts[0].Name)
+	name, _, err := f.GetPicture("Sheet1", "K1")
+	assert.NoError(t, err)
+	assert.Equal(t, "image1.png", name)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteChartByName.xlsx")))
+	// Test delete chart on worksheet without drawing
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, newNoExistChartError("Chart A"), f.DeleteChartByName("Sheet2", "Chart A"))
+	// Test delete chart by index with the chart which relationship missing
+	f2 := NewFile()
+	assert.NoError(t, f2.AddChart("Sheet1", "A1", &Chart{Type: Col, Name: "Chart A", Series: series}))
+	assert.NoError(t, f2.AddChart("Sheet1", "A20", &Chart{Type: Pie, Name: "Chart B", Series: series}))
+	rels, err := f2.relsReader("xl/drawings/_rels/drawing1.xml.rels")
+	assert.NoError(t, err)
+	rels.Relationships = rels.Relationships[1:]
+	charts, err = f2.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	assert.NoError(t, f2.DeleteChartByIndex("Sheet1", 0))
+	wsDr, _, err := f2.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, wsDr.TwoCellAnchor, 1)
+	assert.Contains(t, wsDr.TwoCellAnchor[0].GraphicFrame, "Chart A")
+	assert.NoError(t, f2.Close())
+	// Test delete chart on not exists worksheet
+	assert.EqualError(t, f.DeleteChartByName("SheetN", "Chart A"), "sheet SheetN does not exist")
+	assert.EqualError(t, f.DeleteChartByIndex("SheetN", 0), "sheet SheetN does not exist")
+	// Test delete chart with unsupported charset drawing part
+	f.Drawings.Delete("xl/drawings/drawing1.xml")
+	f.Pkg.Store("xl/drawings/drawing1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.DeleteChartByName("Sheet1", "Chart A"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestAddChartWithSecondaryAxis(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{"Month", "Sales", "Rate", "Cost"}, {"Jan", 120, 0.25, 80}, {"Feb", 150, 0.32, 95}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "F1", &Chart{
+		Type:   Col,
+		Series: []ChartSeries{{Name: "Sheet1!$B$1", Categories: "Sheet1!$A$2:$A$3", Values: "Sheet1!$B$2:$B$3"}},
+		XAxis:  ChartAxis{Title: ChartTitle{Name: "Month"}},
+		YAxis:  ChartAxis{Title: ChartTitle{Name: "Sales"}, NumFmt: ChartNumFmt{CustomNumFmt: "#,##0"}},
+	}, &Chart{
+		Type:   Line,
+		Series: []ChartSeries{{Name: "Sheet1!$C$1", Categories: "Sheet1!$A$2:$A$3", Values: "Sheet1!$C$2:$C$3"}},
+		YAxis:  ChartAxis{Secondary: true, Title: ChartTitle{Name: "Rate"}, NumFmt: ChartNumFmt{CustomNumFmt: "0%"}},
+	}, &Chart{
+		Type:   Line,
+		Series: []ChartSeries{{Name: "Sheet1!$D$1", Categories: "Sheet1!$A$2:$A$3", Values: "Sheet1!$D$2:$D$3"}},
+		YAxis:  ChartAxis{Secondary: true},
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartWithSecondaryAxis.xlsx")))
+	content, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	chart := xlsxChartSpace{}
+	assert.NoError(t, xml.Unmarshal(content.([]byte), &chart))
+	plotArea := chart.Chart.PlotArea
+	// Test the secondary axes should be appended only once
+	assert.Len(t, plotArea.CatAx, 2)
+	assert.Len(t, plotArea.ValAx, 2)
+	assert.Equal(t, 753999905, *plotArea.ValAx[1].AxID.Val)
+	assert.Equal(t, "r", *plotArea.ValAx[1].AxPos.Val)
+	assert.True(t, *plotArea.CatAx[1].Delete.Val)
+	assert.Equal(t, "0%", plotArea.ValAx[1].NumFmt.FormatCode)
+	assert.Equal(t, "#,##0", plotArea.ValAx[0].NumFmt.FormatCode)
+	assert.NotNil(t, plotArea.CatAx[0].Title)
+	assert.NotNil(t, plotArea.ValAx[1].Title)
+	assert.Equal(t, 754001152, *plotArea.BarChart.AxID[0].Val)
+	assert.Equal(t, 754001153, *plotArea.LineChart.AxID[0].Val)
+	assert.Equal(t, 753999905, *plotArea.LineChart.AxID[1].Val)
+	assert.NoError(t, f.Close())
+}
+
+func TestAddChartWithErrorBars(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{"X", "Y", "Plus", "Minus"}, {1, 2.5, 0.2, 0.1}, {2, 3.1, 0.3, 0.2}, {3, 4.2, 0.1, 0.4}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	series := []ChartSeries{{
+		Name:       "Sheet1!$B$1",
+		Categories: "Sheet1!$A$2:$A$4",
+		Values:     "Sheet1!$B$2:$B$4",
+		ErrorBars:  ChartErrorBars{Type: "custom", Plus: "Sheet1!$C$2:$C$4", Minus: "Sheet1!$D$2:$D$4", Line: ChartLine{Color: "#FF0000", Width: 1.5}},
+		XErrorBars: ChartErrorBars{Type: "percentage", Direction: "plus", Value: 5, NoEndCap: true},
+	}}
+	assert.NoError(t, f.AddChart("Sheet1", "F1", &Chart{Type: Scatter, Series: series}))
+	assert.NoError(t, f.AddChart("Sheet1", "F20", &Chart{Type: Col, Series: []ChartSeries{{
+		Values: "Sheet1!$B$2:$B$4", ErrorBars: ChartErrorBars{Type: "stdErr"}, XErrorBars: ChartErrorBars{Type: "fixed", Value: 1},
+	}}}))
+	assert.NoError(t, f.AddChart("Sheet1", "F40", &Chart{Type: Pie, Series: []ChartSeries{{
+		Values: "Sheet1!$B$2:$B$4", ErrorBars: ChartErrorBars{Type: "stdDev", Value: 1},
+	}}}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartWithErrorBars.xlsx")))
+	for chartPath, expected := range map[string][]*cErrBars{
+		"xl/charts/chart1.xml": {
+			{
+				ErrDir: &attrValString{Val: stringPtr("x")}, ErrBarType: &attrValString{Val: stringPtr("plus")},
+				ErrValType: &attrValString{Val: stringPtr("percentage")}, NoEndCap: &attrValBool{Val: boolPtr(true)},
+				Val: &attrValFloat{Val: float64Ptr(5)},
+			},
+			{
+				ErrDir: &attrValString{Val: stringPtr("y")}, ErrBarType: &attrValString{Val: stringPtr("both")},
+				ErrValType: &attrValString{Val: stringPtr("cust")}, NoEndCap: &attrValBool{Val: boolPtr(false)},
+				Plus: &cVal{NumRef: &cNumRef{F: "Sheet1!$C$2:$C$4"}}, Minus: &cVal{NumRef: &cNumRef{F: "Sheet1!$D$2:$D$4"}},
+				SpPr: &cSpPr{},
+			},
+		},
+		"xl/charts/chart2.xml": {
+			{
+				ErrBarType: &attrValString{Val: stringPtr("both")}, ErrValType: &attrValString{Val: stringPtr("stdErr")},
+				NoEndCap: &attrValBool{Val: boolPtr(false)},
+			},
+		},
+	} {
+		chart, ok := f.Pkg.Load(chartPath)
+		assert.True(t, ok)
+		var chartSpace xlsxChartSpace
+		assert.NoError(t, xml.Unmarshal(chart.([]byte), &chartSpace))
+		var ser []cSer
+		if chartSpace.Chart.PlotArea.ScatterChart != nil {
+			ser = *chartSpace.Chart.PlotArea.ScatterChart.Ser
+		} else {
+			ser = *chartSpace.Chart.PlotArea.BarChart.Ser
+		}
+		assert.Equal(t, expected, ser[0].ErrBars)
+	}
+	chart, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(chart.([]byte)), `<spPr><a:ln w="19050"><a:solidFill><a:srgbClr val="FF0000"></a:srgbClr></a:solidFill></a:ln></spPr></errBars>`)
+	chart, ok = f.Pkg.Load("xl/charts/chart3.xml")
+	assert.True(t, ok)
+	assert.NotContains(t, string(chart.([]byte)), "errBars")
+	// Test add chart with unsupported error bars options
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Line, Series: []ChartSeries{{
+		Values: "Sheet1!$B$2:$B$4", ErrorBars: ChartErrorBars{Type: "unknown"},
+	}}}), newInvalidOptionalValue("Type", "unknown", []string{"fixed", "percentage", "stdDev", "stdErr", "custom"}).Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Line, Series: []ChartSeries{{
+		Values: "Sheet1!$B$2:$B$4", XErrorBars: ChartErrorBars{Type: "fixed", Direction: "up"},
+	}}}), newInvalidOptionalValue("Direction", "up", []string{"both", "plus", "minus"}).Error())
+	assert.NoError(t, f.Close())
+}
+
+func TestAddChartWithDataLabels(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{"Month", "Sales", "Note"}, {"Jan", 12.5, "low"}, {"Feb", 18.25, "mid"}, {"Mar", 30, "high"}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", &Chart{Type: Line, Series: []ChartSeries{{
+		Name:       "Sheet1!$B$1",
+		Categories: "Sheet1!$A$2:$A$4",
+		Values:     "Sheet1!$B$2:$B$4",
+		DataLabel: &ChartDataLabel{
+			Position: "top", NumFmt: ChartNumFmt{CustomNumFmt: "0.0"}, CellRange: "Sheet1!$C$2:$C$4",
+			Separator: "; ", ShowVal: true, ShowCatName: true, ShowLeaderLines: true,
+		},
+		DataPointLabels: []ChartDataPointLabel{{Index: 2, Label: ChartDataLabel{Position: "right", ShowSerName: true}}},
+	}}}))
+	assert.NoError(t, f.AddChart("Sheet1", "E20", &Chart{Type: Pie, Series: []ChartSeries{{
+		Categories:      "Sheet1!$A$2:$A$4",
+		Values:          "Sheet1!$B$2:$B$4",
+		DataPointLabels: []ChartDataPointLabel{{Index: 0, Label: ChartDataLabel{Position: "best_fit", ShowPercent: true}}},
+	}}, PlotArea: ChartPlotArea{ShowVal: true}}))
+	assert.NoError(t, f.AddChart("Sheet1", "E40", &Chart{Type: Scatter, Series: []ChartSeries{{
+		Values: "Sheet1!$B$2:$B$4", DataLabel: &ChartDataLabel{Position: "left", ShowVal: true},
+	}}}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartWithDataLabels.xlsx")))
+	chart, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	var chartSpace xlsxChartSpace
+	assert.NoError(t, xml.Unmarshal(chart.([]byte), &chartSpace))
+	ser := (*chartSpace.Chart.PlotArea.LineChart.Ser)[0]
+	assert.Equal(t, &cNumFmt{FormatCode: "0.0"}, ser.DLbls.NumFmt)
+	assert.Equal(t, "t", *ser.DLbls.DLblPos.Val)
+	assert.Equal(t, "; ", *ser.DLbls.Separator)
+	assert.True(t, *ser.DLbls.ShowVal.Val)
+	assert.True(t, *ser.DLbls.ShowCatName.Val)
+	assert.False(t, *ser.DLbls.ShowSerName.Val)
+	assert.True(t, *ser.DLbls.ShowLeaderLines.Val)
+	assert.Len(t, ser.DLbls.DLbl, 1)
+	assert.Equal(t, 2, *ser.DLbls.DLbl[0].IDx.Val)
+	assert.Equal(t, "r", *ser.DLbls.DLbl[0].DLblPos.Val)
+	assert.True(t, *ser.DLbls.DLbl[0].ShowSerName.Val)
+	assert.Contains(t, ser.DLbls.ExtLst.Ext, `<c15:showDataLabelsRange val="1"/>`)
+	assert.Contains(t, ser.DLbls.DLbl[0].ExtLst.Ext, `<c15:showDataLabelsRange val="1"/>`)
+	assert.Contains(t, ser.ExtLst.Ext, `<c15:datalabelsRange><c15:f>Sheet1!$C$2:$C$4</c15:f></c15:datalabelsRange>`)
+	chart, ok = f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	chartSpace = xlsxChartSpace{}
+	assert.NoError(t, xml.Unmarshal(chart.([]byte), &chartSpace))
+	ser = (*chartSpace.Chart.PlotArea.PieChart.Ser)[0]
+	assert.True(t, *ser.DLbls.ShowVal.Val)
+	assert.Nil(t, ser.DLbls.ExtLst)
+	assert.Nil(t, ser.ExtLst)
+	assert.Equal(t, "bestFit", *ser.DLbls.DLbl[0].DLblPos.Val)
+	assert.True(t, *ser.DLbls.DLbl[0].ShowPercent.Val)
+	assert.Nil(t, ser.DLbls.DLbl[0].ExtLst)
+	chart, ok = f.Pkg.Load("xl/charts/chart3.xml")
+	assert.True(t, ok)
+	chartSpace = xlsxChartSpace{}
+	assert.NoError(t, xml.Unmarshal(chart.([]byte), &chartSpace))
+	ser = (*chartSpace.Chart.PlotArea.ScatterChart.Ser)[0]
+	assert.Equal(t, "l", *ser.DLbls.DLblPos.Val)
+	// Test add chart with invalid data label options
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Line, Series: []ChartSeries{{
+		Values: "Sheet1!$B$2:$B$4", DataLabel: &ChartDataLabel{Position: "unknown"},
+	}}}), newInvalidOptionalValue("Position", "unknown", []string{
+		"best_fit", "bottom", "center", "inside_base", "inside_end", "left", "outside_end", "right", "top",
+	}).Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Line, Series: []ChartSeries{{
+		Values: "Sheet1!$B$2:$B$4", DataPointLabels: []ChartDataPointLabel{{Index: 0, Label: ChartDataLabel{Position: "unknown"}}},
+	}}}), newInvalidOptionalValue("Position", "unknown", []string{
+		"best_fit", "bottom", "center", "inside_base", "inside_end", "left", "outside_end", "right", "top",
+	}).Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Line, Series: []ChartSeries{{
+		Values: "Sheet1!$B$2:$B$4", DataPointLabels: []ChartDataPointLabel{{Index: -1}},
+	}}}), ErrParameterInvalid.Error())
+	assert.NoError(t, f.Close())
+}
+
+func TestAddChartEx(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Region", "Country", "Sales", "Score"},
+		{"Asia", "China", 120, 68}, {"Asia", "Japan", 80, 75},
+		{"Europe", "France", 60, 91}, {"Europe", "Germany", -30, 54},
+		{"America", "Canada", 95, 87},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	series := []ChartSeries{{Name: "Sheet1!$C$1", Categories: "Sheet1!$B$2:$B$6", Values: "Sheet1!$C$2:$C$6"}}
+	for idx, chart := range []*Chart{
+		{Type: Waterfall, Series: series, Title: ChartTitle{Name: "Waterfall"}, Layout: ChartLayout{Subtotals: []int{4}}, PlotArea: ChartPlotArea{ShowVal: true}},
+		{Type: Funnel, Series: series, Legend: ChartLegend{Position: "none"}},
+		{Type: Treemap, Series: []ChartSeries{{Categories: "Sheet1!$A$2:$B$6", Values: "Sheet1!$C$2:$C$6"}}, Layout: ChartLayout{ParentLabelLayout: "banner"}},
+		{Type: Sunburst, Series: []ChartSeries{{Categories: "Sheet1!$A$2:$B$6", Values: "Sheet1!$C$2:$C$6"}}, Legend: ChartLegend{Position: "top_right"}},
+		{Type: Histogram, Series: []ChartSeries{{Values: "Sheet1!$D$2:$D$6"}}, Layout: ChartLayout{BinSize: 10}, YAxis: ChartAxis{MajorGridLines: true}},
+		{Type: Pareto, Series: []ChartSeries{{Values: "Sheet1!$D$2:$D$6"}}, Layout: ChartLayout{BinCount: 3}},
+		{Type: BoxWhisker, Series: []ChartSeries{{Values: "Sheet1!$D$2:$D$6"}}, Layout: ChartLayout{QuartileMethod: "inclusive", ShowMeanLine: true, ShowOutliers: boolPtr(false)}},
+	} {
+		cell, err := CoordinatesToCellName(6, idx*20+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.AddChart("Sheet1", cell, chart))
+	}
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{Type: Histogram, Series: series}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartEx.xlsx")))
+	for chartPath, expected := range map[string][]string{
+		"xl/charts/chartEx1.xml": {
+			`<cx:title pos="t" align="ctr" overlay="false"><cx:tx><cx:txData><cx:v>Waterfall</cx:v></cx:txData></cx:tx></cx:title>`,
+			`<cx:series layoutId="waterfall"><cx:tx><cx:txData><cx:f>Sheet1!$C$1</cx:f></cx:txData></cx:tx><cx:dataLabels pos="outEnd"><cx:visibility seriesName="false" categoryName="false" value="true"></cx:visibility></cx:dataLabels><cx:dataId val="0"></cx:dataId><cx:layoutPr><cx:subtotals><cx:idx val="4"></cx:idx></cx:subtotals></cx:layoutPr></cx:series>`,
+			`<cx:strDim type="cat"><cx:f>Sheet1!$B$2:$B$6</cx:f></cx:strDim><cx:numDim type="val"><cx:f>Sheet1!$C$2:$C$6</cx:f></cx:numDim>`,
+			`<cx:legend pos="b" align="ctr" overlay="false"></cx:legend>`,
+		},
+		"xl/charts/chartEx2.xml": {`<cx:series layoutId="funnel">`, `<cx:axis id="0"><cx:catScaling gapWidth="0.06"></cx:catScaling><cx:tickLabels></cx:tickLabels></cx:axis></cx:plotArea></cx:chart>`},
+		"xl/charts/chartEx3.xml": {`<cx:f dir="row">Sheet1!$A$2:$B$6</cx:f>`, `<cx:numDim type="size">`, `<cx:parentLabelLayout val="banner"></cx:parentLabelLayout>`},
+		"xl/charts/chartEx4.xml": {`<cx:series layoutId="sunburst">`, `<cx:legend pos="r" align="ctr" overlay="false"></cx:legend>`},
+		"xl/charts/chartEx5.xml": {`<cx:binning intervalClosed="r"><cx:binSize val="10"></cx:binSize></cx:binning>`, `<cx:majorGridlines></cx:majorGridlines>`},
+		"xl/charts/chartEx6.xml": {
+			`<cx:binning intervalClosed="r"><cx:binCount val="3"></cx:binCount></cx:binning></cx:layoutPr><cx:axisId val="1"></cx:axisId></cx:series><cx:series layoutId="paretoLine" ownerIdx="0"><cx:axisId val="2"></cx:axisId></cx:series>`,
+			`<cx:axis id="2"><cx:valScaling max="1" min="0"></cx:valScaling><cx:units unit="percentage"></cx:units><cx:tickLabels></cx:tickLabels></cx:axis>`,
+		},
+		"xl/charts/chartEx7.xml": {`<cx:visibility meanLine="true" meanMarker="true" nonoutliers="false" outliers="false"></cx:visibility><cx:statistics quartileMethod="inclusive"></cx:statistics>`},
+		"xl/charts/chartEx8.xml": {`<cx:aggregation></cx:aggregation>`},
+	} {
+		chart, ok := f.Pkg.Load(chartPath)
+		assert.True(t, ok, chartPath)
+		for _, str := range expected {
+			assert.Contains(t, string(chart.([]byte)), str)
+		}
+	}
+	drawing, ok := f.Drawings.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, drawing.(*xlsxWsDr).TwoCellAnchor[0].GraphicFrame, `<mc:Choice xmlns:cx1="http://schemas.microsoft.com/office/drawing/2015/9/8/chartex" Requires="cx1">`)
+	assert.Contains(t, drawing.(*xlsxWsDr).TwoCellAnchor[1].GraphicFrame, `<mc:Choice xmlns:cx2="http://schemas.microsoft.com/office/drawing/2015/10/21/chartex" Requires="cx2">`)
+	assert.Contains(t, drawing.(*xlsxWsDr).TwoCellAnchor[0].GraphicFrame, `<mc:Fallback>`)
+	chartSheetDrawing, ok := f.Drawings.Load("xl/drawings/drawing2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, chartSheetDrawing.(*xlsxWsDr).AbsoluteAnchor[0].GraphicFrame, `<cx:chart xmlns:cx="http://schemas.microsoft.com/office/drawing/2014/chartex"`)
+	content, ok := f.Pkg.Load(defaultXMLPathContentTypes)
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), ContentTypeChartEx)
+	// Test the extended charts are not returned by get charts
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, charts)
+	// Test delete the extended chart
+	assert.NoError(t, f.DeleteChart("Sheet1", "F1"))
+	assert.Len(t, drawing.(*xlsxWsDr).TwoCellAnchor, 6)
+	// Test add the extended chart with combo chart
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Waterfall, Series: series}, &Chart{Type: Line, Series: series}), newUnsupportedChartType(Line).Error())
+	// Test add the extended chart with invalid layout options
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: BoxWhisker, Series: series, Layout: ChartLayout{QuartileMethod: "unknown"}}),
+		newInvalidOptionalValue("QuartileMethod", "unknown", []string{"exclusive", "inclusive"}).Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Treemap, Series: series, Layout: ChartLayout{ParentLabelLayout: "unknown"}}),
+		newInvalidOptionalValue("ParentLabelLayout", "unknown", []string{"overlapping", "banner", "none"}).Error())
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Histogram, Series: series, Layout: ChartLayout{BinSize: -1}}), ErrParameterInvalid.Error())
+	assert.NoError(t, f.Close())
+}
+
+func TestExportCharts(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{nil, "Apple", "Orange"}, {"Small", 2, 3}, {"Normal", 5, 2}, {"Large", 6, 7}} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", &Chart{
+		Type:   Col,
+		Name:   "Sales",
+		Series: []ChartSeries{{Name: "Sheet1!$B$1", Categories: "Sheet1!$A$2:$A$4", Values: "Sheet1!$B$2:$B$4"}},
+		Title:  ChartTitle{Name: "Fruits"},
+	}))
+	// Test export chart with the image referenced by the chart
+	assert.NoError(t, f.AddPicture("Sheet1", "P1", filepath.Join("test", "images", "excel.png"), nil))
+	f.addRels("xl/charts/_rels/chart1.xml.rels", SourceRelationshipImage, "../media/image1.png", "")
+	f.addRels("xl/charts/_rels/chart1.xml.rels", SourceRelationshipImage, "https://example.com/image.png", "External")
+	charts, err := f.ExportCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	assert.Equal(t, "Sales", charts[0].Chart.Name)
+	assert.Equal(t, "Fruits", charts[0].Chart.Title.Name)
+	assert.Equal(t, "chart1.xml", charts[0].Part.Name)
+	assert.Equal(t, ContentTypeDrawingML, charts[0].Part.ContentType)
+	assert.Equal(t, f.readXML("xl/charts/chart1.xml"), charts[0].Part.Content)
+	assert.Len(t, charts[0].Images, 1)
+	assert.Equal(t, "image1.png", charts[0].Images[0].Name)
+	assert.Equal(t, "image/png", charts[0].Images[0].ContentType)
+	assert.Len(t, charts[0].Images[0].Content, 13233)
+	// Test export charts in the worksheet without charts
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	charts, err = f.ExportCharts("Sheet2")
+	assert.NoError(t, err)
+	assert.Empty(t, charts)
+	// Test export charts in not exist worksheet
+	_, err = f.ExportCharts("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test export charts with unsupported charset chart relationships
+	f.Relationships.Delete("xl/charts/_rels/chart1.xml.rels")
+	f.Pkg.Store("xl/charts/_rels/chart1.xml.rels", MacintoshCyrillicCharset)
+	_, err = f.ExportCharts("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test export charts with unsupported charset content types
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	_, err = f.ExportCharts("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
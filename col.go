@@ -27,6 +27,7 @@ const (
 	defaultColWidthPixels  float64 = 64
 	defaultRowHeight       float64 = 15
 	defaultRowHeightPixels float64 = 20
+	autoWidthPadding       float64 = 2
 	EMU                    int     = 9525
 )
 
@@ -0,0 +1,407 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColorScaleLegendOptions directly maps the settings of the color scale legend
+// block.
+//
+// Format specifies the color scale conditional format settings of the data
+// range, the type of the conditional format should be 2_color_scale or
+// 3_color_scale. The MinType, MidType and MaxType could be min, max, num,
+// percent or percentile, the default types are min, percentile (50) and max.
+//
+// Cell specifies the top-left cell reference of the legend block, the legend
+// will be placed at the right side of the data range with a blank column if
+// this value is empty.
+//
+// Steps specifies the number of the legend cells, the default value is 5 and
+// it should be greater than or equal to 2, for the 3 color scale, it will be
+// increased to an odd number for including the midpoint.
+//
+// Horizontal specifies if the legend cells are placed in a row, the legend
+// cells are placed in a column by default.
+//
+// NumFmt and CustomNumFmt specifies the number format of the legend labels.
+type ColorScaleLegendOptions struct {
+	Format       ConditionalFormatOptions
+	Cell         string
+	Steps        int
+	Horizontal   bool
+	NumFmt       int
+	CustomNumFmt *string
+}
+
+// colorScaleStop defined the threshold value and RGB color of the color scale.
+type colorScaleStop struct {
+	value   float64
+	r, g, b float64
+}
+
+// AddColorScaleLegend provides a function to add a color scale legend block
+// for the data range with the color scale conditional format, the legend
+// cells are labeled with the values spanning the minimum to maximum of the
+// color scale and filled with the corresponding colors. For example, add a 3
+// color scale for the range A1:D10 and its legend at the F1:F5:
+//
+//	format := excelize.ConditionalFormatOptions{
+//	    Type:     "3_color_scale",
+//	    Criteria: "=",
+//	    MinType:  "min",
+//	    MidType:  "percentile",
+//	    MaxType:  "max",
+//	    MinColor: "#F8696B",
+//	    MidColor: "#FFEB84",
+//	    MaxColor: "#63BE7B",
+//	}
+//	err := f.SetConditionalFormat("Sheet1", "A1:D10", []excelize.ConditionalFormatOptions{format})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.AddColorScaleLegend("Sheet1", "A1:D10", &excelize.ColorScaleLegendOptions{
+//	    Format: format,
+//	    Cell:   "F1",
+//	})
+func (f *File) AddColorScaleLegend(sheet, rangeRef string, opts *ColorScaleLegendOptions) error {
+	if opts == nil {
+		return ErrParameterInvalid
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	values, err := f.getRangeNumbers(sheet, coordinates)
+	if err != nil {
+		return err
+	}
+	stops, err := getColorScaleStops(&opts.Format, values)
+	if err != nil {
+		return err
+	}
+	col, row := coordinates[2]+2, coordinates[1]
+	if opts.Cell != "" {
+		if col, row, err = CellNameToCoordinates(opts.Cell); err != nil {
+			return err
+		}
+	}
+	for i, value := range getColorScaleLegendValues(stops, opts.Steps) {
+		cell, err := CoordinatesToCellName(col, row+i)
+		if opts.Horizontal {
+			cell, err = CoordinatesToCellName(col+i, row)
+		}
+		if err != nil {
+			return err
+		}
+		styleID, err := f.NewStyle(&Style{
+			Fill:         Fill{Type: "pattern", Pattern: 1, Color: []string{getColorScaleColor(stops, value)}},
+			NumFmt:       opts.NumFmt,
+			CustomNumFmt: opts.CustomNumFmt,
+		})
+		if err != nil {
+			return err
+		}
+		if err = f.SetCellFloat(sheet, cell, value, -1, 64); err != nil {
+			return err
+		}
+		if err = f.SetCellStyle(sheet, cell, cell, styleID); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// ApplyHeatMap provides a function to fill the numeric cells in the range
+// with the static graduated colors by given worksheet name, range reference
+// and palette. Unlike the color scale conditional format, the fills are
+// applied as the concrete cell styles, so the heat map could be shown in the
+// viewers which ignore the conditional formats. The palette should contain
+// at least 2 hex colors ordered from the minimum to the maximum value, the
+// colors are evenly distributed between the minimum and maximum values of
+// the range. The other formats of the cells will be kept, and the
+// non-numeric cells are ignored. For example, apply a heat map from red to
+// yellow to green for the range A1:D10 on Sheet1:
+//
+//	err := f.ApplyHeatMap("Sheet1", "A1:D10", []string{"#F8696B", "#FFEB84", "#63BE7B"})
+//
+// Note that the fills will not be updated after the cell values changed, call
+// this function again to refresh the heat map.
+func (f *File) ApplyHeatMap(sheet, rangeRef string, palette []string) error {
+	if len(palette) < 2 {
+		return ErrParameterInvalid
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	values, err := f.getRangeNumbers(sheet, coordinates)
+	if err != nil {
+		return err
+	}
+	stops, err := getHeatMapStops(palette, values)
+	if err != nil {
+		return err
+	}
+	styles := make(map[string]int)
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cell, _ := CoordinatesToCellName(col, row)
+			val, err := f.GetCellValue(sheet, cell, Options{RawCellValue: true})
+			if err != nil {
+				return err
+			}
+			num, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				continue
+			}
+			styleID, err := f.GetCellStyle(sheet, cell)
+			if err != nil {
+				return err
+			}
+			color := getColorScaleColor(stops, num)
+			key := strconv.Itoa(styleID) + color
+			if _, ok := styles[key]; !ok {
+				if styles[key], err = f.setStyleFill(styleID, color); err != nil {
+					return err
+				}
+			}
+			if err = f.SetCellStyle(sheet, cell, cell, styles[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// getHeatMapStops returns the evenly distributed threshold values and colors
+// of the heat map by given palette and data values.
+func getHeatMapStops(palette []string, values []float64) ([]colorScaleStop, error) {
+	if len(values) == 0 {
+		return nil, ErrParameterInvalid
+	}
+	min, max := values[0], values[0]
+	for _, value := range values {
+		min, max = math.Min(min, value), math.Max(max, value)
+	}
+	stops := make([]colorScaleStop, len(palette))
+	for i, color := range palette {
+		r, g, b, err := parseHexColor(color)
+		if err != nil {
+			return stops, err
+		}
+		stops[i] = colorScaleStop{value: min + (max-min)*float64(i)/float64(len(palette)-1), r: r, g: g, b: b}
+	}
+	return stops, nil
+}
+
+// setStyleFill provides a function to get the cell style index which has the
+// same formats with the given style index but using the solid pattern fill
+// with the given color, the new cell style will be created if not exist.
+func (f *File) setStyleFill(styleID int, color string) (int, error) {
+	fill := newFills(&Style{Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{color}}}, true)
+	s, err := f.stylesReader()
+	if err != nil {
+		return styleID, err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.Fills == nil {
+		s.Fills = &xlsxFills{}
+	}
+	fillID := -1
+	for idx, fl := range s.Fills.Fill {
+		if reflect.DeepEqual(fl, fill) {
+			fillID = idx
+			break
+		}
+	}
+	if fillID == -1 {
+		s.Fills.Fill = append(s.Fills.Fill, fill)
+		s.Fills.Count = len(s.Fills.Fill)
+		fillID = s.Fills.Count - 1
+	}
+	var xf xlsxXf
+	if s.CellXfs == nil {
+		s.CellXfs = &xlsxCellXfs{}
+	}
+	if styleID >= 0 && styleID < len(s.CellXfs.Xf) {
+		xf = s.CellXfs.Xf[styleID]
+	}
+	xf.FillID, xf.ApplyFill = intPtr(fillID), boolPtr(true)
+	for idx, x := range s.CellXfs.Xf {
+		if reflect.DeepEqual(x, xf) {
+			return idx, err
+		}
+	}
+	s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+	s.CellXfs.Count = len(s.CellXfs.Xf)
+	return s.CellXfs.Count - 1, err
+}
+
+// getRangeNumbers returns the numeric cell values in the range by given
+// worksheet name and sorted coordinates, the non-numeric cells are ignored.
+func (f *File) getRangeNumbers(sheet string, coordinates []int) ([]float64, error) {
+	var values []float64
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cell, err := CoordinatesToCellName(col, row)
+			if err != nil {
+				return values, err
+			}
+			val, err := f.GetCellValue(sheet, cell, Options{RawCellValue: true})
+			if err != nil {
+				return values, err
+			}
+			if num, err := strconv.ParseFloat(val, 64); err == nil {
+				values = append(values, num)
+			}
+		}
+	}
+	return values, nil
+}
+
+// getColorScaleStops returns the threshold values and colors of the color
+// scale conditional format by given format settings and data values.
+func getColorScaleStops(format *ConditionalFormatOptions, values []float64) ([]colorScaleStop, error) {
+	vt := validType[format.Type]
+	if vt != "2_color_scale" && vt != "3_color_scale" {
+		return nil, ErrParameterInvalid
+	}
+	if len(values) == 0 {
+		return nil, ErrParameterInvalid
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	cfvo := [][3]string{{format.MinType, format.MinValue, "min"}}
+	colors := []string{format.MinColor}
+	if vt == "3_color_scale" {
+		cfvo = append(cfvo, [3]string{format.MidType, format.MidValue, "percentile"})
+		colors = append(colors, format.MidColor)
+	}
+	cfvo = append(cfvo, [3]string{format.MaxType, format.MaxValue, "max"})
+	colors = append(colors, format.MaxColor)
+	stops := make([]colorScaleStop, len(cfvo))
+	for i, c := range cfvo {
+		value, err := getColorScaleValue(c[0], c[1], c[2], sorted)
+		if err != nil {
+			return stops, err
+		}
+		r, g, b, err := parseHexColor(colors[i])
+		if err != nil {
+			return stops, err
+		}
+		stops[i] = colorScaleStop{value: value, r: r, g: g, b: b}
+		if i > 0 && stops[i].value < stops[i-1].value {
+			stops[i].value = stops[i-1].value
+		}
+	}
+	return stops, nil
+}
+
+// getColorScaleValue returns the threshold value of the color scale by given
+// conditional format value object type, value, default type and sorted data
+// values.
+func getColorScaleValue(typ, val, defaultType string, sorted []float64) (float64, error) {
+	if typ == "" {
+		typ = defaultType
+	}
+	min, max := sorted[0], sorted[len(sorted)-1]
+	switch typ {
+	case "min":
+		return min, nil
+	case "max":
+		return max, nil
+	}
+	num, err := strconv.ParseFloat(val, 64)
+	if val == "" && typ == "percentile" {
+		num, err = 50, nil
+	}
+	if err != nil {
+		return num, ErrParameterInvalid
+	}
+	switch typ {
+	case "num":
+		return num, nil
+	case "percent":
+		return min + (max-min)*num/100, nil
+	case "percentile":
+		rank := math.Max(0, math.Min(100, num)) / 100 * float64(len(sorted)-1)
+		lower := int(math.Floor(rank))
+		if lower+1 >= len(sorted) {
+			return sorted[lower], nil
+		}
+		return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower]), nil
+	}
+	return num, ErrParameterInvalid
+}
+
+// getColorScaleLegendValues returns the label values of the color scale
+// legend by given color scale stops and the number of the legend cells.
+func getColorScaleLegendValues(stops []colorScaleStop, steps int) []float64 {
+	if steps < 2 {
+		steps = 5
+	}
+	segments := len(stops) - 1
+	if (steps-1)%segments != 0 {
+		steps++
+	}
+	var values []float64
+	perSegment := (steps - 1) / segments
+	for s := 0; s < segments; s++ {
+		from, to := stops[s].value, stops[s+1].value
+		for i := 0; i < perSegment; i++ {
+			values = append(values, from+(to-from)*float64(i)/float64(perSegment))
+		}
+	}
+	return append(values, stops[segments].value)
+}
+
+// getColorScaleColor returns the hex RGB color of the given value on the
+// color scale by linear interpolation.
+func getColorScaleColor(stops []colorScaleStop, value float64) string {
+	from, to := stops[0], stops[0]
+	for i := 1; i < len(stops); i++ {
+		if from, to = stops[i-1], stops[i]; value <= to.value {
+			break
+		}
+	}
+	ratio := 0.0
+	if to.value > from.value {
+		ratio = math.Max(0, math.Min(1, (value-from.value)/(to.value-from.value)))
+	}
+	mix := func(a, b float64) uint8 { return uint8(math.Round(a + (b-a)*ratio)) }
+	return fmt.Sprintf("#%02X%02X%02X", mix(from.r, to.r), mix(from.g, to.g), mix(from.b, to.b))
+}
+
+// parseHexColor parses the RGB color components by given hex color string,
+// such as #F8696B or F8696B.
+func parseHexColor(color string) (r, g, b float64, err error) {
+	color = strings.TrimPrefix(color, "#")
+	if len(color) != 6 {
+		return r, g, b, ErrParameterInvalid
+	}
+	rgb, err := strconv.ParseUint(color, 16, 32)
+	if err != nil {
+		return r, g, b, ErrParameterInvalid
+	}
+	return float64(rgb >> 16 & 0xFF), float64(rgb >> 8 & 0xFF), float64(rgb & 0xFF), nil
+}
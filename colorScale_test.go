@@ -0,0 +1,132 @@
+package excel
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddColorScaleLegend(t *testing.T) {
+	f := NewFile()
+	for r := 1; r <= 10; r++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(r), &[]interface{}{r * 10, r*10 + 5, "text"}))
+	}
+	format := ConditionalFormatOptions{
+		Type:     "3_color_scale",
+		Criteria: "=",
+		MinType:  "min",
+		MidType:  "percentile",
+		MaxType:  "max",
+		MinColor: "#F8696B",
+		MidColor: "#FFEB84",
+		MaxColor: "#63BE7B",
+	}
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:C10", []ConditionalFormatOptions{format}))
+	assert.NoError(t, f.AddColorScaleLegend("Sheet1", "C10:A1", &ColorScaleLegendOptions{Format: format, NumFmt: 1}))
+	for cell, expected := range map[string]string{"E1": "10", "E2": "34", "E3": "58", "E4": "81", "E5": "105"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	for cell, expected := range map[string]string{"E1": "FFF8696B", "E3": "FFFFEB84", "E5": "FF63BE7B"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		fillID := *f.Styles.CellXfs.Xf[styleID].FillID
+		assert.Equal(t, expected, f.Styles.Fills.Fill[fillID].PatternFill.FgColor.RGB, cell)
+	}
+	// Test add horizontal legend for 2 color scale with the given cell
+	assert.NoError(t, f.AddColorScaleLegend("Sheet1", "A1:B10", &ColorScaleLegendOptions{
+		Format: ConditionalFormatOptions{
+			Type:     "2_color_scale",
+			MinType:  "num",
+			MinValue: "0",
+			MaxType:  "percent",
+			MaxValue: "50",
+			MinColor: "#FFFFFF",
+			MaxColor: "#000000",
+		},
+		Cell:       "A12",
+		Steps:      3,
+		Horizontal: true,
+	}))
+	for cell, expected := range map[string]string{"A12": "0", "B12": "28.75", "C12": "57.5"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddColorScaleLegend.xlsx")))
+	// Test add legend with invalid parameters
+	assert.EqualError(t, f.AddColorScaleLegend("Sheet1", "A1:B10", nil), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.AddColorScaleLegend("Sheet1", "A1:B10", &ColorScaleLegendOptions{
+		Format: ConditionalFormatOptions{Type: "data_bar"},
+	}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.AddColorScaleLegend("Sheet1", "C1:C10", &ColorScaleLegendOptions{Format: format}), ErrParameterInvalid.Error())
+	for _, opts := range []ConditionalFormatOptions{
+		{Type: "2_color_scale", MinType: "formula", MinValue: "A1", MinColor: "#FFFFFF", MaxColor: "#000000"},
+		{Type: "2_color_scale", MinType: "unknown", MinValue: "1", MinColor: "#FFFFFF", MaxColor: "#000000"},
+		{Type: "2_color_scale", MinColor: "#FFF", MaxColor: "#000000"},
+		{Type: "2_color_scale", MinColor: "#FFFFFF", MaxColor: "#GGGGGG"},
+	} {
+		assert.EqualError(t, f.AddColorScaleLegend("Sheet1", "A1:B10", &ColorScaleLegendOptions{Format: opts}), ErrParameterInvalid.Error())
+	}
+	// Test add legend with invalid range reference and cell reference
+	assert.EqualError(t, f.AddColorScaleLegend("Sheet1", "A:B10", &ColorScaleLegendOptions{Format: format}), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.AddColorScaleLegend("Sheet1", "A1:B10", &ColorScaleLegendOptions{Format: format, Cell: "A"}), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.ErrorIs(t, f.AddColorScaleLegend("Sheet1", "A1:B10", &ColorScaleLegendOptions{Format: format, Cell: "XFD1", Horizontal: true}), ErrColumnNumber)
+	// Test add legend on not exists worksheet
+	assert.EqualError(t, f.AddColorScaleLegend("SheetN", "A1:B10", &ColorScaleLegendOptions{Format: format}), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
+func TestGetColorScaleColor(t *testing.T) {
+	stops := []colorScaleStop{{value: 0, r: 255, g: 255, b: 255}, {value: 10}}
+	assert.Equal(t, "#FFFFFF", getColorScaleColor(stops, -1))
+	assert.Equal(t, "#808080", getColorScaleColor(stops, 5))
+	assert.Equal(t, "#000000", getColorScaleColor(stops, 11))
+	assert.Equal(t, "#FFFFFF", getColorScaleColor([]colorScaleStop{{value: 1, r: 255, g: 255, b: 255}, {value: 1}}, 1))
+}
+
+func TestApplyHeatMap(t *testing.T) {
+	f := NewFile()
+	for r := 1; r <= 5; r++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(r), &[]interface{}{r * 10, r*10 + 50, "text"}))
+	}
+	style, err := f.NewStyle(&Style{NumFmt: 2, Border: []Border{{Type: "left", Color: "0000FF", Style: 1}}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	assert.NoError(t, f.ApplyHeatMap("Sheet1", "C5:A1", []string{"#FF0000", "#FFFF00", "#00FF00"}))
+	for cell, expected := range map[string]string{"A1": "FFFF0000", "B2": "FFAAFF00", "A5": "FFFFE300", "B5": "FF00FF00"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		fillID := *f.Styles.CellXfs.Xf[styleID].FillID
+		assert.Equal(t, expected, f.Styles.Fills.Fill[fillID].PatternFill.FgColor.RGB, cell)
+	}
+	// Test keep the other formats of the cell
+	styleID, err := f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, f.Styles.CellXfs.Xf[style].BorderID, f.Styles.CellXfs.Xf[styleID].BorderID)
+	assert.Equal(t, f.Styles.CellXfs.Xf[style].NumFmtID, f.Styles.CellXfs.Xf[styleID].NumFmtID)
+	// Test the non-numeric cells are ignored
+	styleID, err = f.GetCellStyle("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Zero(t, styleID)
+	// Test apply heat map again without creating duplicate styles
+	count := len(f.Styles.CellXfs.Xf)
+	assert.NoError(t, f.ApplyHeatMap("Sheet1", "A1:C5", []string{"#FF0000", "#FFFF00", "#00FF00"}))
+	assert.Equal(t, count, len(f.Styles.CellXfs.Xf))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestApplyHeatMap.xlsx")))
+	// Test apply heat map with invalid parameters
+	assert.EqualError(t, f.ApplyHeatMap("Sheet1", "A1:C5", []string{"#FF0000"}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.ApplyHeatMap("Sheet1", "A1:C5", []string{"#FF0000", "#GGGGGG"}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.ApplyHeatMap("Sheet1", "C1:C5", []string{"#FF0000", "#00FF00"}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.ApplyHeatMap("Sheet1", "A:C5", []string{"#FF0000", "#00FF00"}), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test apply heat map on not exists worksheet
+	assert.EqualError(t, f.ApplyHeatMap("SheetN", "A1:C5", []string{"#FF0000", "#00FF00"}), "sheet SheetN does not exist")
+	// Test apply heat map with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ApplyHeatMap("Sheet1", "A1:C5", []string{"#FF0000", "#00FF00"}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
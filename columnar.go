@@ -0,0 +1,217 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import "strconv"
+
+// ColumnType is the type of the values in a column of the columnar sheet.
+type ColumnType byte
+
+// Column value types enumeration.
+const (
+	ColumnTypeEmpty ColumnType = iota
+	ColumnTypeNumber
+	ColumnTypeBool
+	ColumnTypeString
+)
+
+// Column directly maps the values of a column in the columnar sheet. The
+// values are stored in one of the typed slices Numbers, Bools or Strings
+// depending on the Type, each slice has the same length as the Len. The
+// Validity specifies the validity bitmap of the values, which bit i (in the
+// least significant bit numbering) is set if the value of the row i is not
+// null, the null values are stored as the zero value in the typed slice.
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Len      int
+	Validity []byte
+	Numbers  []float64
+	Bools    []bool
+	Strings  []string
+}
+
+// ColumnarSheet directly maps the columnar representation of a worksheet,
+// which returned by the GetColumnarSheet function. The Rows specifies the
+// number of the rows in each column.
+type ColumnarSheet struct {
+	Rows    int
+	Columns []*Column
+}
+
+// IsNull returns true if the value of the column at the given zero-based row
+// index is null.
+func (col *Column) IsNull(i int) bool {
+	if i < 0 || i >= col.Len {
+		return true
+	}
+	return col.Validity[i>>3]&(1<<uint(i&7)) == 0
+}
+
+// appendNull provides a function to append a null value to the column.
+func (col *Column) appendNull() {
+	if col.Len>>3 >= len(col.Validity) {
+		col.Validity = append(col.Validity, 0)
+	}
+	col.Len++
+	switch col.Type {
+	case ColumnTypeNumber:
+		col.Numbers = append(col.Numbers, 0)
+	case ColumnTypeBool:
+		col.Bools = append(col.Bools, false)
+	case ColumnTypeString:
+		col.Strings = append(col.Strings, "")
+	}
+}
+
+// appendValue provides a function to append a value with given type to the
+// column. The column will be converted to the string column if the type of
+// the value is different from the type of the column.
+func (col *Column) appendValue(typ ColumnType, val string, num float64) {
+	if col.Type == ColumnTypeEmpty {
+		col.Type = typ
+		switch typ {
+		case ColumnTypeNumber:
+			col.Numbers = make([]float64, col.Len)
+		case ColumnTypeBool:
+			col.Bools = make([]bool, col.Len)
+		default:
+			col.Strings = make([]string, col.Len)
+		}
+	} else if col.Type != typ && col.Type != ColumnTypeString {
+		col.toStrings()
+	}
+	col.appendNull()
+	col.Validity[(col.Len-1)>>3] |= 1 << uint((col.Len-1)&7)
+	switch col.Type {
+	case ColumnTypeNumber:
+		col.Numbers[col.Len-1] = num
+	case ColumnTypeBool:
+		col.Bools[col.Len-1] = val == "1"
+	default:
+		col.Strings[col.Len-1] = val
+	}
+}
+
+// toStrings provides a function to convert the number or boolean column to
+// the string column by the raw cell values.
+func (col *Column) toStrings() {
+	col.Strings = make([]string, col.Len)
+	for i := 0; i < col.Len; i++ {
+		if col.IsNull(i) {
+			continue
+		}
+		if col.Type == ColumnTypeNumber {
+			col.Strings[i] = strconv.FormatFloat(col.Numbers[i], 'f', -1, 64)
+			continue
+		}
+		col.Strings[i] = "0"
+		if col.Bools[i] {
+			col.Strings[i] = "1"
+		}
+	}
+	col.Type, col.Numbers, col.Bools = ColumnTypeString, nil, nil
+}
+
+// GetColumnarSheet provides a function to read the worksheet by given
+// worksheet name into the columnar representation, each column stored as a
+// typed slice with a validity bitmap instead of the rows of string values.
+// The result retains less memory than the rows returned by the GetRows
+// function for the numeric and boolean columns, and is suitable for the
+// vectorized data processing, but reading the worksheet costs about the same
+// allocations as GetRows.
+// The type of a column will be inferred from the raw cell values: the column
+// which only contains the numeric cells will be read as numbers, the column
+// which only contains the boolean cells will be read as booleans, and the
+// others will be read as the raw cell values in strings. The cells without
+// value, include the empty string values, will be read as null and don't
+// affect the type of the column. The trailing empty rows will be trimmed,
+// and the columns and the column limit to be read can be
+// specified by the Columns and ColumnLimit options. For example, sum the
+// numbers in the column B of the worksheet named 'Sheet1':
+//
+//	sheet, err := f.GetColumnarSheet("Sheet1", excelize.Options{Columns: []string{"B"}})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	var sum float64
+//	if col := sheet.Columns[0]; col.Type == excelize.ColumnTypeNumber {
+//	    for i, num := range col.Numbers {
+//	        if !col.IsNull(i) {
+//	            sum += num
+//	        }
+//	    }
+//	}
+func (f *File) GetColumnarSheet(sheet string, opts ...Options) (*ColumnarSheet, error) {
+	options := parseOptions(opts...)
+	readColumns, err := getReadColumns(options.Columns)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	result, columns := &ColumnarSheet{}, map[int]*Column{}
+	for _, col := range readColumns {
+		column := &Column{}
+		column.Name, _ = ColumnNumberToName(col)
+		result.Columns, columns[col] = append(result.Columns, column), column
+	}
+	options.RawCellValue = true
+	for rows.Next() {
+		cells, err := rows.Cells(*options)
+		if err != nil {
+			_ = rows.Close()
+			return result, err
+		}
+		for _, cell := range cells {
+			col, row, err := CellNameToCoordinates(cell.Ref)
+			if err != nil {
+				_ = rows.Close()
+				return result, err
+			}
+			if cell.Value == "" {
+				continue
+			}
+			column, ok := columns[col]
+			if !ok {
+				for c := len(result.Columns) + 1; c <= col; c++ {
+					column = &Column{}
+					column.Name, _ = ColumnNumberToName(c)
+					result.Columns, columns[c] = append(result.Columns, column), column
+				}
+			}
+			for column.Len < row-1 {
+				column.appendNull()
+			}
+			typ, num := ColumnTypeString, 0.0
+			switch cell.Type {
+			case CellTypeBool:
+				typ = ColumnTypeBool
+			case CellTypeUnset, CellTypeNumber:
+				if num, err = strconv.ParseFloat(cell.Value, 64); err == nil {
+					typ = ColumnTypeNumber
+				}
+			}
+			column.appendValue(typ, cell.Value, num)
+			result.Rows = row
+		}
+	}
+	for _, column := range result.Columns {
+		for column.Len < result.Rows {
+			column.appendNull()
+		}
+	}
+	return result, rows.Close()
+}
@@ -0,0 +1,174 @@
+package excel
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetColumnarSheet(t *testing.T) {
+	f := NewFile()
+	for cell, val := range map[string]interface{}{
+		"A1": "Name", "B1": "Score", "C1": "Pass", "D1": "Mixed",
+		"A2": "Alice", "B2": 90.5, "C2": true, "D2": 1,
+		"A3": "Bob", "C3": false, "D3": true,
+		"A4": "Carol", "B4": 70, "D4": "text",
+		"B6": 100,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, val))
+	}
+	sheet, err := f.GetColumnarSheet("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, sheet.Rows)
+	assert.Len(t, sheet.Columns, 4)
+
+	name := sheet.Columns[0]
+	assert.Equal(t, "A", name.Name)
+	assert.Equal(t, ColumnTypeString, name.Type)
+	assert.Equal(t, []string{"Name", "Alice", "Bob", "Carol", "", ""}, name.Strings)
+	assert.Equal(t, []byte{0x0f}, name.Validity)
+
+	score := sheet.Columns[1]
+	assert.Equal(t, ColumnTypeString, score.Type)
+	assert.Equal(t, []string{"Score", "90.5", "", "70", "", "100"}, score.Strings)
+
+	// Test read the columnar sheet with specified columns
+	sheet, err = f.GetColumnarSheet("Sheet1", Options{Columns: []string{"D", "C", "B", "E"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, sheet.Rows)
+	assert.Len(t, sheet.Columns, 4)
+	mixed, pass := sheet.Columns[0], sheet.Columns[1]
+	assert.Equal(t, "D", mixed.Name)
+	assert.Equal(t, ColumnTypeString, mixed.Type)
+	assert.Equal(t, []string{"Mixed", "1", "1", "text", "", ""}, mixed.Strings)
+	assert.Equal(t, "C", pass.Name)
+	assert.Equal(t, ColumnTypeString, pass.Type)
+	assert.Equal(t, []string{"Pass", "1", "0", "", "", ""}, pass.Strings)
+	empty := sheet.Columns[3]
+	assert.Equal(t, "E", empty.Name)
+	assert.Equal(t, ColumnTypeEmpty, empty.Type)
+	assert.Equal(t, 6, empty.Len)
+	assert.True(t, empty.IsNull(0))
+
+	// Test read the typed columns without the header row
+	assert.NoError(t, f.RemoveRow("Sheet1", 1))
+	sheet, err = f.GetColumnarSheet("Sheet1", Options{Columns: []string{"B", "C", "D"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, sheet.Rows)
+	score, pass, mixed = sheet.Columns[0], sheet.Columns[1], sheet.Columns[2]
+	assert.Equal(t, ColumnTypeNumber, score.Type)
+	assert.Equal(t, []float64{90.5, 0, 70, 0, 100}, score.Numbers)
+	assert.Equal(t, []byte{0x15}, score.Validity)
+	for i, null := range []bool{false, true, false, true, false} {
+		assert.Equal(t, null, score.IsNull(i))
+	}
+	assert.True(t, score.IsNull(-1))
+	assert.True(t, score.IsNull(5))
+	assert.Equal(t, ColumnTypeBool, pass.Type)
+	assert.Equal(t, []bool{true, false, false, false, false}, pass.Bools)
+	assert.Equal(t, []byte{0x03}, pass.Validity)
+	assert.Equal(t, ColumnTypeString, mixed.Type)
+	assert.Equal(t, []string{"1", "1", "text", "", ""}, mixed.Strings)
+
+	// Test read the empty cells as null values
+	style, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", style))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B3", ""))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E7", ""))
+	sheet, err = f.GetColumnarSheet("Sheet1", Options{Columns: []string{"B", "E"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, sheet.Rows)
+	score, empty = sheet.Columns[0], sheet.Columns[1]
+	assert.Equal(t, ColumnTypeNumber, score.Type)
+	assert.Equal(t, []float64{90.5, 0, 0, 0, 100}, score.Numbers)
+	assert.Equal(t, []byte{0x11}, score.Validity)
+	assert.Equal(t, ColumnTypeEmpty, empty.Type)
+	assert.Equal(t, 5, empty.Len)
+
+	// Test read the columnar sheet with column limit
+	sheet, err = f.GetColumnarSheet("Sheet1", Options{ColumnLimit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, sheet.Columns, 2)
+	// Test read the columnar sheet with more than 8 rows
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	for row := 1; row <= 10; row++ {
+		assert.NoError(t, f.SetCellValue("Sheet2", "B"+strconv.Itoa(row), row))
+	}
+	sheet, err = f.GetColumnarSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, sheet.Rows)
+	assert.Equal(t, ColumnTypeEmpty, sheet.Columns[0].Type)
+	assert.Equal(t, []byte{0, 0}, sheet.Columns[0].Validity)
+	assert.Equal(t, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, sheet.Columns[1].Numbers)
+	assert.Equal(t, []byte{0xff, 0x03}, sheet.Columns[1].Validity)
+	// Test read the columnar sheet with invalid columns
+	_, err = f.GetColumnarSheet("Sheet1", Options{Columns: []string{"-"}})
+	assert.Equal(t, newInvalidColumnNameError("-"), err)
+	// Test read the columnar sheet on not exists worksheet
+	_, err = f.GetColumnarSheet("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test read the columnar sheet with invalid sheet name
+	_, err = f.GetColumnarSheet("Sheet:1")
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	// Test read the columnar sheet with invalid cell reference
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet><sheetData><row r="1"><c r="A"><v>1</v></c></row></sheetData></worksheet>`))
+	_, err = f.GetColumnarSheet("Sheet1")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	assert.NoError(t, f.Close())
+}
+
+func BenchmarkGetColumnarSheet(b *testing.B) {
+	f := NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			b.Error(err)
+		}
+	}()
+	row := make([]interface{}, 10)
+	for r := 1; r <= 1000; r++ {
+		for c := range row {
+			row[c] = float64(r*c) / 3
+		}
+		cell, _ := CoordinatesToCellName(1, r)
+		if err := f.SetSheetRow("Sheet1", cell, &row); err != nil {
+			b.Fatal(err)
+		}
+	}
+	// Report the heap memory retained by the result besides the allocations
+	retained := func(b *testing.B, read func() (interface{}, error)) {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		result, err := read()
+		if err != nil {
+			b.Fatal(err)
+		}
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		runtime.KeepAlive(result)
+		b.ReportMetric(float64(after.HeapAlloc)-float64(before.HeapAlloc), "retained-B")
+	}
+	b.Run("GetRows", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := f.GetRows("Sheet1"); err != nil {
+				b.Fatal(err)
+			}
+		}
+		retained(b, func() (interface{}, error) { return f.GetRows("Sheet1") })
+	})
+	b.Run("GetColumnarSheet", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := f.GetColumnarSheet("Sheet1"); err != nil {
+				b.Fatal(err)
+			}
+		}
+		retained(b, func() (interface{}, error) { return f.GetColumnarSheet("Sheet1") })
+	})
+}
@@ -53,7 +53,7 @@ func (f *File) GetComments() (map[string][]Comment, error) {
 					if text.T != nil {
 						run := RichTextRun{Text: text.T.Val}
 						if text.RPr != nil {
-							run.Font = newFont(text.RPr)
+							run.Font = f.newRichTextFont(text.RPr)
 						}
 						sheetComment.Runs = append(sheetComment.Runs, run)
 					}
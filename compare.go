@@ -0,0 +1,158 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+// CellChangeType is the type of cell value change.
+type CellChangeType byte
+
+// Cell value change types enumeration.
+const (
+	CellChangeModified CellChangeType = iota
+	CellChangeAdded
+	CellChangeRemoved
+)
+
+// CellChange directly maps the difference of a cell value between two
+// versions of a worksheet.
+type CellChange struct {
+	Type     CellChangeType
+	Cell     string
+	Col, Row int
+	Old, New string
+}
+
+// SheetComparer defines an iterator to the cell value differences between
+// two versions of a worksheet.
+type SheetComparer struct {
+	err              error
+	oldRows, newRows *Rows
+	oldEnd, newEnd   bool
+	row              int
+	cur              CellChange
+	changes          []CellChange
+	opts             []Options
+}
+
+// CompareSheets returns an iterator to the cell value differences between two
+// versions of a worksheet by given old and new workbooks and worksheet names.
+// The comparer walks the row iterators of both worksheets in lock-step, so
+// neither of the worksheets will be loaded fully, this is useful for the
+// delta ingestion of the large workbooks. The differences are yielded in the
+// order of rows and columns, the optional settings will be used to read the
+// cell values, for example, compare the raw cell values of the worksheets
+// named 'Sheet1' in two workbooks:
+//
+//	comparer, err := excelize.CompareSheets(oldFile, "Sheet1", newFile, "Sheet1",
+//	    excelize.Options{RawCellValue: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for comparer.Next() {
+//	    change := comparer.Change()
+//	    fmt.Println(change.Cell, change.Old, change.New)
+//	}
+//	if err = comparer.Error(); err != nil {
+//	    fmt.Println(err)
+//	}
+//	if err = comparer.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+func CompareSheets(oldFile *File, oldSheet string, newFile *File, newSheet string, opts ...Options) (*SheetComparer, error) {
+	oldRows, err := oldFile.Rows(oldSheet)
+	if err != nil {
+		return nil, err
+	}
+	newRows, err := newFile.Rows(newSheet)
+	if err != nil {
+		_ = oldRows.Close()
+		return nil, err
+	}
+	return &SheetComparer{oldRows: oldRows, newRows: newRows, opts: opts}, nil
+}
+
+// Next will return true if find the next cell value difference.
+func (c *SheetComparer) Next() bool {
+	for len(c.changes) == 0 {
+		if c.err != nil || !c.nextRow() {
+			return false
+		}
+	}
+	c.cur, c.changes = c.changes[0], c.changes[1:]
+	return true
+}
+
+// nextRow reads the next row of both worksheets and collects the cell value
+// differences of the row, returns false if no more rows in both worksheets.
+func (c *SheetComparer) nextRow() bool {
+	var oldRow, newRow []string
+	if !c.oldEnd {
+		if c.oldEnd = !c.oldRows.Next(); !c.oldEnd {
+			if oldRow, c.err = c.oldRows.Columns(c.opts...); c.err != nil {
+				return false
+			}
+		}
+	}
+	if !c.newEnd {
+		if c.newEnd = !c.newRows.Next(); !c.newEnd {
+			if newRow, c.err = c.newRows.Columns(c.opts...); c.err != nil {
+				return false
+			}
+		}
+	}
+	if c.oldEnd && c.newEnd {
+		return false
+	}
+	c.row++
+	for col := 1; col <= len(oldRow) || col <= len(newRow); col++ {
+		var oldVal, newVal string
+		if col <= len(oldRow) {
+			oldVal = oldRow[col-1]
+		}
+		if col <= len(newRow) {
+			newVal = newRow[col-1]
+		}
+		if oldVal == newVal {
+			continue
+		}
+		change := CellChange{Col: col, Row: c.row, Old: oldVal, New: newVal}
+		if oldVal == "" {
+			change.Type = CellChangeAdded
+		}
+		if newVal == "" {
+			change.Type = CellChangeRemoved
+		}
+		change.Cell, _ = CoordinatesToCellName(col, c.row)
+		c.changes = append(c.changes, change)
+	}
+	return true
+}
+
+// Change will return the current cell value difference.
+func (c *SheetComparer) Change() CellChange {
+	return c.cur
+}
+
+// Error will return the error when the error occurs.
+func (c *SheetComparer) Error() error {
+	return c.err
+}
+
+// Close closes the row iterators of both worksheets. It is safe to call this
+// function multiple times.
+func (c *SheetComparer) Close() error {
+	err := c.oldRows.Close()
+	if newErr := c.newRows.Close(); err == nil {
+		err = newErr
+	}
+	return err
+}
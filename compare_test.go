@@ -0,0 +1,63 @@
+package excel
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareSheets(t *testing.T) {
+	oldFile, newFile := NewFile(), NewFile()
+	assert.NoError(t, oldFile.SetSheetRow("Sheet1", "A1", &[]interface{}{"ID", "Name", "Score"}))
+	assert.NoError(t, oldFile.SetSheetRow("Sheet1", "A2", &[]interface{}{1, "Alice", 90}))
+	assert.NoError(t, oldFile.SetSheetRow("Sheet1", "A3", &[]interface{}{2, "Bob", 80}))
+	assert.NoError(t, oldFile.SetSheetRow("Sheet1", "A5", &[]interface{}{4, "Dave"}))
+	assert.NoError(t, newFile.SetSheetRow("Sheet1", "A1", &[]interface{}{"ID", "Name", "Score"}))
+	assert.NoError(t, newFile.SetSheetRow("Sheet1", "A2", &[]interface{}{1, "Alice", 95}))
+	assert.NoError(t, newFile.SetSheetRow("Sheet1", "A3", &[]interface{}{2, "Bob"}))
+	assert.NoError(t, newFile.SetSheetRow("Sheet1", "A4", &[]interface{}{3, "Carol", 70}))
+	assert.NoError(t, newFile.SetSheetRow("Sheet1", "A7", &[]interface{}{6}))
+	
+	comparer, err := CompareSheets(oldFile, "Sheet1", newFile, "Sheet1")
+	assert.NoError(t, err)
+	var changes []CellChange
+	for comparer.Next() {
+		changes = append(changes, comparer.Change())
+	}
+	assert.NoError(t, comparer.Error())
+	assert.NoError(t, comparer.Close())
+	assert.NoError(t, comparer.Close())
+	assert.Equal(t, []CellChange{
+		{Type: CellChangeModified, Cell: "C2", Col: 3, Row: 2, Old: "90", New: "95"},
+		{Type: CellChangeRemoved, Cell: "C3", Col: 3, Row: 3, Old: "80"},
+		{Type: CellChangeAdded, Cell: "A4", Col: 1, Row: 4, New: "3"},
+		{Type: CellChangeAdded, Cell: "B4", Col: 2, Row: 4, New: "Carol"},
+		{Type: CellChangeAdded, Cell: "C4", Col: 3, Row: 4, New: "70"},
+		{Type: CellChangeRemoved, Cell: "A5", Col: 1, Row: 5, Old: "4"},
+		{Type: CellChangeRemoved, Cell: "B5", Col: 2, Row: 5, Old: "Dave"},
+		{Type: CellChangeAdded, Cell: "A7", Col: 1, Row: 7, New: "6"},
+	}, changes)
+	
+	// Test compare the same worksheet
+	comparer, err = CompareSheets(oldFile, "Sheet1", oldFile, "Sheet1")
+	assert.NoError(t, err)
+	assert.False(t, comparer.Next())
+	assert.NoError(t, comparer.Close())
+	
+	// Test compare worksheets with not exist worksheet
+	_, err = CompareSheets(oldFile, "SheetN", newFile, "Sheet1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	_, err = CompareSheets(oldFile, "Sheet1", newFile, "SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	
+	// Test compare worksheets with invalid cell reference
+	newFile.Sheet.Delete("xl/worksheets/sheet1.xml")
+	newFile.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A"><v>1</v></c></row></sheetData></worksheet>`))
+	comparer, err = CompareSheets(oldFile, "Sheet1", newFile, "Sheet1")
+	assert.NoError(t, err)
+	assert.False(t, comparer.Next())
+	assert.EqualError(t, comparer.Error(), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.NoError(t, comparer.Close())
+	assert.NoError(t, oldFile.Close())
+	assert.NoError(t, newFile.Close())
+}
@@ -0,0 +1,135 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ConvertError directly maps the error of converting a workbook by the
+// ConvertAll function.
+type ConvertError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the error message of converting the workbook.
+func (err *ConvertError) Error() string {
+	return fmt.Sprintf("%s: %v", err.Path, err.Err)
+}
+
+// Unwrap returns the underlying error of converting the workbook.
+func (err *ConvertError) Unwrap() error {
+	return err.Err
+}
+
+// ConvertErrors directly maps the aggregated errors of the ConvertAll
+// function, the errors are in the same order as the given inputs.
+type ConvertErrors []*ConvertError
+
+// Error returns the aggregated error message of converting the workbooks.
+func (errs ConvertErrors) Error() string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	return fmt.Sprintf("%d workbooks failed to convert, the first error: %v", len(errs), errs[0])
+}
+
+// ConvertAll provides a function to open each of the given workbooks and
+// process it by the given function concurrently with a bounded pool of
+// workers, this is useful for the backfill jobs that convert a large number
+// of workbooks. Each worker opens one workbook at a time with the optional
+// settings and closes it after the given function returns, so the memory
+// usage of each worker is bounded by the single workbook, and the
+// UnzipXMLSizeLimit option can be used to reduce it further. The number of
+// workers default to the number of CPUs if it is less than or equal to 0.
+// The given function should save the workbook itself if needed, for example,
+// set the calculation mode of the workbooks to automatic with 8 workers:
+//
+//	err := excelize.ConvertAll(ctx, paths, func(f *excelize.File) error {
+//	    calcMode := "auto"
+//	    if err := f.SetCalcProps(&excelize.CalcPropsOptions{CalcMode: &calcMode}); err != nil {
+//	        return err
+//	    }
+//	    return f.Save()
+//	}, 8)
+//
+// This function doesn't stop when a workbook failed to convert, all errors
+// will be returned as ConvertErrors, and each error is a *ConvertError with
+// the path of the workbook. When the context was canceled, the workers will
+// stop after finishing the workbooks in processing, and the context error
+// will be reported for the remaining workbooks.
+func ConvertAll(ctx context.Context, inputs []string, fn func(*File) error, workers int, opts ...Options) error {
+	if fn == nil {
+		return ErrParameterRequired
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+	var (
+		wg   sync.WaitGroup
+		jobs = make(chan int)
+		errs = make([]error, len(inputs))
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				errs[idx] = convertFile(inputs[idx], fn, opts...)
+			}
+		}()
+	}
+	for idx := range inputs {
+		if err := ctx.Err(); err != nil {
+			errs[idx] = err
+			continue
+		}
+		select {
+		case jobs <- idx:
+		case <-ctx.Done():
+			errs[idx] = ctx.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	var convertErrs ConvertErrors
+	for idx, err := range errs {
+		if err != nil {
+			convertErrs = append(convertErrs, &ConvertError{Path: inputs[idx], Err: err})
+		}
+	}
+	if len(convertErrs) > 0 {
+		return convertErrs
+	}
+	return nil
+}
+
+// convertFile opens the workbook by given path and optional settings, and
+// process it by the given function.
+func convertFile(path string, fn func(*File) error, opts ...Options) error {
+	f, err := OpenFile(path, opts...)
+	if err != nil {
+		return err
+	}
+	err = fn(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
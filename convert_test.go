@@ -0,0 +1,74 @@
+package excel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertAll(t *testing.T) {
+	var inputs []string
+	for i := 1; i <= 5; i++ {
+		path := filepath.Join("test", fmt.Sprintf("TestConvertAll%d.xlsx", i))
+		f := NewFile()
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", i))
+		assert.NoError(t, f.SaveAs(path))
+		assert.NoError(t, f.Close())
+		inputs = append(inputs, path)
+	}
+	var count int32
+	assert.NoError(t, ConvertAll(context.Background(), inputs, func(f *File) error {
+		atomic.AddInt32(&count, 1)
+		if err := f.SetCellFormula("Sheet1", "B1", "A1*2"); err != nil {
+			return err
+		}
+		return f.Save()
+	}, 2))
+	assert.Equal(t, int32(5), count)
+	f, err := OpenFile(inputs[4])
+	assert.NoError(t, err)
+	formula, err := f.GetCellFormula("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1*2", formula)
+	assert.NoError(t, f.Close())
+	
+	// Test convert workbooks with errors
+	errConvert := errors.New("convert failed")
+	err = ConvertAll(context.Background(), append([]string{filepath.Join("test", "NotExist.xlsx")}, inputs...), func(f *File) error {
+		if f.Path == inputs[1] {
+			return errConvert
+		}
+		return nil
+	}, 0)
+	var convertErrs ConvertErrors
+	assert.True(t, errors.As(err, &convertErrs))
+	assert.Len(t, convertErrs, 2)
+	assert.True(t, os.IsNotExist(errors.Unwrap(convertErrs[0])))
+	assert.Equal(t, inputs[1], convertErrs[1].Path)
+	assert.True(t, errors.Is(convertErrs[1], errConvert))
+	assert.Contains(t, err.Error(), "2 workbooks failed to convert, the first error: ")
+	assert.EqualError(t, convertErrs[1:], inputs[1]+": convert failed")
+	
+	// Test convert workbooks with canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	count = 0
+	err = ConvertAll(ctx, inputs, func(f *File) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}, 1)
+	assert.True(t, errors.As(err, &convertErrs))
+	assert.Len(t, convertErrs, 5)
+	assert.True(t, errors.Is(convertErrs[0], context.Canceled))
+	assert.Equal(t, int32(0), count)
+	
+	// Test convert workbooks without the function
+	assert.Equal(t, ErrParameterRequired, ConvertAll(context.Background(), inputs, nil, 1))
+	assert.NoError(t, ConvertAll(context.Background(), nil, func(f *File) error { return nil }, 1))
+}
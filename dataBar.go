@@ -0,0 +1,167 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DataBarFallbackOptions directly maps the settings of the data bar fallback
+// column.
+//
+// Cell specifies the top cell reference of the bar column, the bar column
+// will be placed at the right side of the data range if this value is empty.
+//
+// Width specifies the maximum number of the bar characters for the maximum
+// value of the data range, the default value is 10.
+//
+// Char specifies the character used to draw the bars, the default value is
+// the full block character "█".
+//
+// Font and Color specifies the monospace font family and font color of the
+// bars, the default values are "Consolas" and "#638EC6".
+//
+// DataBar specifies if add the data bar conditional format on the data range
+// at the same time, so the bars will be shown in both forms.
+type DataBarFallbackOptions struct {
+	Cell    string
+	Width   int
+	Char    string
+	Font    string
+	Color   string
+	DataBar bool
+}
+
+// AddDataBarFallback provides a function to add a bar column beside the data
+// range by given worksheet name, range reference of a single column and
+// options, each cell of the bar column is a REPT formula which repeats the
+// bar character for the times scaled from the minimum to maximum value of
+// the data range, for recipients whose tools drop the data bar conditional
+// formats. The calculated bars will be set as the cached formula results, and
+// the non-numeric cells will be treated as zero. For example, add the bar column
+// at the B1:B10 for the data range A1:A10 on Sheet1, and also add the data bar
+// conditional format for the data range:
+//
+//	err := f.AddDataBarFallback("Sheet1", "A1:A10", &excelize.DataBarFallbackOptions{
+//	    Width:   20,
+//	    DataBar: true,
+//	})
+func (f *File) AddDataBarFallback(sheet, rangeRef string, opts *DataBarFallbackOptions) error {
+	options := parseDataBarFallbackOptions(opts)
+	if options.Width < 0 || options.Width > TotalCellChars {
+		return ErrParameterInvalid
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	if coordinates[0] != coordinates[2] {
+		return ErrParameterInvalid
+	}
+	values, err := f.getRangeNumbers(sheet, coordinates)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return ErrParameterInvalid
+	}
+	min, max := values[0], values[0]
+	for _, value := range values {
+		min, max = math.Min(min, value), math.Max(max, value)
+	}
+	col, row := coordinates[2]+1, coordinates[1]
+	if options.Cell != "" {
+		if col, row, err = CellNameToCoordinates(options.Cell); err != nil {
+			return err
+		}
+	}
+	styleID, err := f.NewStyle(&Style{Font: &Font{Family: options.Font, Color: options.Color}})
+	if err != nil {
+		return err
+	}
+	absRef, _ := f.coordinatesToRangeRef(coordinates, true)
+	char := strings.ReplaceAll(options.Char, "\"", "\"\"")
+	for r := coordinates[1]; r <= coordinates[3]; r++ {
+		dataCell, _ := CoordinatesToCellName(coordinates[0], r)
+		cell, err := CoordinatesToCellName(col, row+r-coordinates[1])
+		if err != nil {
+			return err
+		}
+		formula := "IFERROR(REPT(\"" + char + "\",ROUND((N(" + dataCell + ")-MIN(" + absRef + "))/(MAX(" + absRef + ")-MIN(" + absRef + "))*" + strconv.Itoa(options.Width) + ",0)),\"\")"
+		if err = f.SetCellFormula(sheet, cell, formula); err != nil {
+			return err
+		}
+		val, err := f.GetCellValue(sheet, dataCell, Options{RawCellValue: true})
+		if err != nil {
+			return err
+		}
+		var bar string
+		num, _ := strconv.ParseFloat(val, 64)
+		if n := math.Round((num - min) / (max - min) * float64(options.Width)); max > min && n >= 0 {
+			bar = strings.Repeat(options.Char, int(n))
+		}
+		if err = f.setCellFormulaResult(sheet, cell, bar); err != nil {
+			return err
+		}
+		if err = f.SetCellStyle(sheet, cell, cell, styleID); err != nil {
+			return err
+		}
+	}
+	if options.DataBar {
+		return f.SetConditionalFormat(sheet, rangeRef, []ConditionalFormatOptions{
+			{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: options.Color},
+		})
+	}
+	return err
+}
+
+// parseDataBarFallbackOptions provides a function to parse the data bar
+// fallback settings with default value.
+func parseDataBarFallbackOptions(opts *DataBarFallbackOptions) *DataBarFallbackOptions {
+	options := DataBarFallbackOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Width == 0 {
+		options.Width = 10
+	}
+	if options.Char == "" {
+		options.Char = "█"
+	}
+	if options.Font == "" {
+		options.Font = "Consolas"
+	}
+	if options.Color == "" {
+		options.Color = "#638EC6"
+	}
+	return &options
+}
+
+// setCellFormulaResult provides a function to set the cached string result of
+// the formula cell by given worksheet name and cell reference.
+func (f *File) setCellFormulaResult(sheet, cell, result string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	c, _, _, err := f.prepareCell(ws, cell)
+	if err != nil {
+		return err
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	c.setStr(result)
+	return err
+}
@@ -0,0 +1,58 @@
+package excel
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddDataBarFallback(t *testing.T) {
+	f := NewFile()
+	for r, val := range []interface{}{10, 25, 50, "x", 110} {
+		assert.NoError(t, f.SetCellValue("Sheet1", "A"+strconv.Itoa(r+1), val))
+	}
+	assert.NoError(t, f.AddDataBarFallback("Sheet1", "A5:A1", &DataBarFallbackOptions{Width: 4, DataBar: true}))
+	for cell, expected := range map[string]string{"B1": "", "B2": "█", "B3": "██", "B4": "", "B5": "████"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+	formula, err := f.GetCellFormula("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, `IFERROR(REPT("█",ROUND((N(A2)-MIN($A$1:$A$5))/(MAX($A$1:$A$5)-MIN($A$1:$A$5))*4,0)),"")`, formula)
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Consolas", *f.Styles.Fonts.Font[*f.Styles.CellXfs.Xf[styleID].FontID].Name.Val)
+	opts, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	// The range reference of the conditional format has been normalized
+	assert.Equal(t, "data_bar", opts["A1:A5"][0].Type)
+	// Test add bar column with the given cell and character
+	assert.NoError(t, f.AddDataBarFallback("Sheet1", "A1:A5", &DataBarFallbackOptions{Cell: "D2", Char: `"`}))
+	val, err := f.GetCellValue("Sheet1", "D6")
+	assert.NoError(t, err)
+	assert.Equal(t, `""""""""""`, val)
+	result, err := f.CalcCellValue("Sheet1", "D6")
+	assert.NoError(t, err)
+	assert.Equal(t, val, result)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddDataBarFallback.xlsx")))
+	// Test add bar column with invalid parameters
+	assert.EqualError(t, f.AddDataBarFallback("Sheet1", "A1:B5", nil), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.AddDataBarFallback("Sheet1", "A1:A5", &DataBarFallbackOptions{Width: -1}), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.AddDataBarFallback("Sheet1", "A4:A4", nil), ErrParameterInvalid.Error())
+	assert.EqualError(t, f.AddDataBarFallback("Sheet1", "A:A5", nil), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.AddDataBarFallback("Sheet1", "A1:A5", &DataBarFallbackOptions{Cell: "A"}), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.ErrorIs(t, f.AddDataBarFallback("Sheet1", "A1:A5", &DataBarFallbackOptions{Cell: "A1048576"}), ErrMaxRows)
+	// Test add bar column on not exists worksheet
+	assert.EqualError(t, f.AddDataBarFallback("SheetN", "A1:A5", nil), "sheet SheetN does not exist")
+	// Test add bar column with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AddDataBarFallback("Sheet1", "A1:A5", nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
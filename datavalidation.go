@@ -12,8 +12,12 @@
 package excel
 
 import (
+	"encoding/xml"
 	"fmt"
+	"io"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode/utf16"
 )
@@ -46,6 +50,10 @@ const (
 	DataValidationErrorStyleInformation
 )
 
+// maxDataValidationTitleLength defined the maximum number of characters of
+// the error and input message titles of the data validation.
+const maxDataValidationTitleLength = 32
+
 // Data validation error styles.
 const (
 	styleStop        = "stop"
@@ -78,6 +86,14 @@ var formulaEscaper = strings.NewReplacer(
 	`"`, `""`,
 )
 
+// formulaXMLEscaper escapes the special characters of the data validation
+// criteria formula in the XML element.
+var formulaXMLEscaper = strings.NewReplacer(
+	`&`, `&amp;`,
+	`<`, `&lt;`,
+	`>`, `&gt;`,
+)
+
 // NewDataValidation return data validation struct.
 func NewDataValidation(allowBlank bool) *DataValidation {
 	return &DataValidation{
@@ -87,8 +103,11 @@ func NewDataValidation(allowBlank bool) *DataValidation {
 	}
 }
 
-// SetError set error notice.
+// SetError set error notice. The title will be truncated to 32 characters
+// and the message will be truncated to 255 characters, the characters are
+// counted by UTF-16 code units in the same way as Excel.
 func (dd *DataValidation) SetError(style DataValidationErrorStyle, title, msg string) {
+	msg, title = truncateUTF16(msg, MaxFieldLength), truncateUTF16(title, maxDataValidationTitleLength)
 	dd.Error = &msg
 	dd.ErrorTitle = &title
 	strStyle := styleStop
@@ -105,8 +124,11 @@ func (dd *DataValidation) SetError(style DataValidationErrorStyle, title, msg st
 	dd.ErrorStyle = &strStyle
 }
 
-// SetInput set prompt notice.
+// SetInput set prompt notice. The title will be truncated to 32 characters
+// and the message will be truncated to 255 characters, the characters are
+// counted by UTF-16 code units in the same way as Excel.
 func (dd *DataValidation) SetInput(title, msg string) {
+	msg, title = truncateUTF16(msg, MaxFieldLength), truncateUTF16(title, maxDataValidationTitleLength)
 	dd.ShowInputMessage = true
 	dd.PromptTitle = &title
 	dd.Prompt = &msg
@@ -136,7 +158,7 @@ func (dd *DataValidation) SetRange(f1, f2 interface{}, t DataValidationType, o D
 		}
 		formula1 = fmt.Sprintf("<formula1>%.17g</formula1>", v)
 	case string:
-		formula1 = fmt.Sprintf("<formula1>%s</formula1>", v)
+		formula1 = fmt.Sprintf("<formula1>%s</formula1>", formulaXMLEscaper.Replace(strings.TrimPrefix(v, "=")))
 	default:
 		return ErrParameterInvalid
 	}
@@ -149,7 +171,7 @@ func (dd *DataValidation) SetRange(f1, f2 interface{}, t DataValidationType, o D
 		}
 		formula2 = fmt.Sprintf("<formula2>%.17g</formula2>", v)
 	case string:
-		formula2 = fmt.Sprintf("<formula2>%s</formula2>", v)
+		formula2 = fmt.Sprintf("<formula2>%s</formula2>", formulaXMLEscaper.Replace(strings.TrimPrefix(v, "=")))
 	default:
 		return ErrParameterInvalid
 	}
@@ -170,8 +192,16 @@ func (dd *DataValidation) SetRange(f1, f2 interface{}, t DataValidationType, o D
 //	dvRange.Sqref = "A7:B8"
 //	dvRange.SetSqrefDropList("$E$1:$E$3")
 //	f.AddDataValidation("Sheet1", dvRange)
+//
+// The source can also be a range reference of another worksheet or a defined
+// name, which is not limited by the 255 characters length of the explicit
+// list items, the worksheet name should be quoted if it contains spaces or
+// special characters:
+//
+//	dvRange.SetSqrefDropList("'Lookup Data'!$A$1:$A$500")
+//	dvRange.SetSqrefDropList("CountryList")
 func (dd *DataValidation) SetSqrefDropList(sqref string) {
-	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", sqref)
+	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", formulaXMLEscaper.Replace(strings.TrimPrefix(sqref, "=")))
 	dd.Type = convDataValidationType(typeList)
 }
 
@@ -260,20 +290,109 @@ func (f *File) AddDataValidation(sheet string, dv *DataValidation) error {
 }
 
 // GetDataValidations returns data validations list by given worksheet name.
+// The data validations in the extension list of the worksheet, which are
+// written by Excel 2010 for the list source referencing other worksheets,
+// will also be returned. The omitted operator of the data validation will be
+// returned as "between", and the first and second formulas of the data
+// validation will be returned in the Formula1 and Formula2 fields
+// respectively. The returned data validations are copies, changing them
+// will not affect the worksheet.
 func (f *File) GetDataValidations(sheet string) ([]*DataValidation, error) {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return nil, err
 	}
-	if ws.DataValidations == nil || len(ws.DataValidations.DataValidation) == 0 {
+	var dataValidations []*DataValidation
+	if ws.DataValidations != nil {
+		for _, dv := range ws.DataValidations.DataValidation {
+			dataValidation := *dv
+			prepareDataValidation(&dataValidation)
+			dataValidations = append(dataValidations, &dataValidation)
+		}
+	}
+	extDataValidations, err := f.getExtDataValidations(ws)
+	if err != nil {
 		return nil, err
 	}
-	return ws.DataValidations.DataValidation, err
+	return append(dataValidations, extDataValidations...), err
+}
+
+// prepareDataValidation provides a function to split the first and second
+// formulas of the data validation which decoded from the worksheet, and set
+// the omitted operator of the data validation by the default value.
+func prepareDataValidation(dv *DataValidation) {
+	if idx := strings.Index(dv.Formula1, "<formula2>"); idx != -1 && dv.Formula2 == "" {
+		dv.Formula1, dv.Formula2 = dv.Formula1[:idx], dv.Formula1[idx:]
+	}
+	if dv.Operator == "" && inStrSlice([]string{"whole", "decimal", "date", "time", "textLength"}, dv.Type, true) != -1 {
+		dv.Operator = "between"
+	}
+}
+
+// getExtDataValidations provides a function to get the data validations in
+// the extension list of the worksheet.
+func (f *File) getExtDataValidations(ws *xlsxWorksheet) ([]*DataValidation, error) {
+	var dataValidations []*DataValidation
+	if ws.ExtLst == nil {
+		return dataValidations, nil
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return dataValidations, err
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if !strings.EqualFold(ext.URI, ExtURIDataValidations) {
+			continue
+		}
+		decodeDataValidations := new(decodeX14DataValidations)
+		if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(decodeDataValidations); err != nil && err != io.EOF {
+			return dataValidations, err
+		}
+		for _, v := range decodeDataValidations.DataValidation {
+			dv := &DataValidation{
+				AllowBlank: v.AllowBlank, Error: v.Error, ErrorStyle: v.ErrorStyle, ErrorTitle: v.ErrorTitle,
+				Operator: v.Operator, Prompt: v.Prompt, PromptTitle: v.PromptTitle, ShowDropDown: v.ShowDropDown,
+				ShowErrorMessage: v.ShowErrorMessage, ShowInputMessage: v.ShowInputMessage, Sqref: v.Sqref, Type: v.Type,
+			}
+			if v.Formula1 != nil {
+				dv.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", formulaXMLEscaper.Replace(v.Formula1.F))
+			}
+			if v.Formula2 != nil {
+				dv.Formula2 = fmt.Sprintf("<formula2>%s</formula2>", formulaXMLEscaper.Replace(v.Formula2.F))
+			}
+			prepareDataValidation(dv)
+			dataValidations = append(dataValidations, dv)
+		}
+	}
+	return dataValidations, nil
+}
+
+// truncateUTF16 provides a function to truncate the string to the given
+// number of UTF-16 code units, the surrogate pair will not be split.
+func truncateUTF16(s string, n int) string {
+	var count int
+	for i, r := range s {
+		if count += len(utf16.Encode([]rune{r})); count > n {
+			return s[:i]
+		}
+	}
+	return s
 }
 
 // DeleteDataValidation delete data validation by given worksheet name and
 // reference sequence. All data validations in the worksheet will be deleted
-// if not specify reference sequence parameter.
+// if not specify reference sequence parameter. Only the cells in the given
+// references will be removed from the data validations, the references of
+// the data validation which partially covered by the given references will
+// be split into the remaining ranges. The data validations in the extension
+// list of the worksheet, which returned by the GetDataValidations function
+// for the list source referencing other worksheets, will not be deleted.
+// For example, remove the data validations on the cells A2:A5 and C3 of the
+// worksheet named Sheet1:
+//
+//	err := f.DeleteDataValidation("Sheet1", "A2:A5", "C3")
 func (f *File) DeleteDataValidation(sheet string, sqref ...string) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -286,12 +405,76 @@ func (f *File) DeleteDataValidation(sheet string, sqref ...string) error {
 		ws.DataValidations = nil
 		return nil
 	}
-	delCells, err := f.flatSqref(sqref[0])
+	delCells, err := f.flatSqref(strings.Join(sqref, " "))
 	if err != nil {
 		return err
 	}
-	dv := ws.DataValidations
+	if err = f.deleteDataValidationCells(ws.DataValidations, delCells, nil); err != nil {
+		return err
+	}
+	if ws.DataValidations.Count == 0 {
+		ws.DataValidations = nil
+	}
+	return err
+}
+
+// SetDataValidationRange provides a function to change the reference
+// sequence of the existing data validation by given worksheet name, the
+// current reference sequence and the new reference sequence of the data
+// validation. The cells in the new references will be removed from the other
+// data validations of the worksheet, the data validations on the same cells
+// are not allowed. The data validations in the extension list of the
+// worksheet are not supported, and they will not be changed by the new
+// references. For example, extend the data validation on the cells A1:A10
+// of the worksheet named Sheet1 to the cells A1:A20 and C1:C20:
+//
+//	err := f.SetDataValidationRange("Sheet1", "A1:A10", "A1:A20 C1:C20")
+func (f *File) SetDataValidationRange(sheet, sqref, newSqref string) error {
+	if newSqref = strings.Join(strings.Fields(newSqref), " "); newSqref == "" {
+		return ErrParameterRequired
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	var dataValidation *DataValidation
+	if ws.DataValidations != nil {
+		for _, dv := range ws.DataValidations.DataValidation {
+			if normalizeDataValidationSqref(dv.Sqref) == normalizeDataValidationSqref(sqref) {
+				dataValidation = dv
+				break
+			}
+		}
+	}
+	if dataValidation == nil {
+		return newNoExistDataValidationError(sqref)
+	}
+	cells, err := f.flatSqref(newSqref)
+	if err != nil {
+		return err
+	}
+	if err = f.deleteDataValidationCells(ws.DataValidations, cells, dataValidation); err != nil {
+		return err
+	}
+	dataValidation.Sqref = newSqref
+	return err
+}
+
+// normalizeDataValidationSqref returns the reference sequence of the data
+// validation without absolute reference signs and redundant spaces for
+// comparing.
+func normalizeDataValidationSqref(sqref string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(strings.ReplaceAll(sqref, "$", "")), " "))
+}
+
+// deleteDataValidationCells provides a function to remove the given cells
+// from the data validations except the skipped one, and the data validation
+// without remaining cells will be removed.
+func (f *File) deleteDataValidationCells(dv *xlsxDataValidations, delCells map[int][][]int, skip *DataValidation) error {
 	for i := 0; i < len(dv.DataValidation); i++ {
+		if dv.DataValidation[i] == skip {
+			continue
+		}
 		var applySqref []string
 		colCells, err := f.flatSqref(dv.DataValidation[i].Sqref)
 		if err != nil {
@@ -305,8 +488,13 @@ func (f *File) DeleteDataValidation(sheet string, sqref ...string) error {
 				}
 			}
 		}
-		for _, col := range colCells {
-			applySqref = append(applySqref, f.squashSqref(col)...)
+		cols := make([]int, 0, len(colCells))
+		for col := range colCells {
+			cols = append(cols, col)
+		}
+		sort.Ints(cols)
+		for _, col := range cols {
+			applySqref = append(applySqref, f.squashSqref(colCells[col])...)
 		}
 		dv.DataValidation[i].Sqref = strings.Join(applySqref, " ")
 		if len(applySqref) == 0 {
@@ -315,12 +503,217 @@ func (f *File) DeleteDataValidation(sheet string, sqref ...string) error {
 		}
 	}
 	dv.Count = len(dv.DataValidation)
-	if dv.Count == 0 {
-		ws.DataValidations = nil
-	}
 	return nil
 }
 
+// DataValidationViolation directly maps the cell which violates the data
+// validation of the worksheet.
+type DataValidationViolation struct {
+	Cell           string
+	Value          string
+	DataValidation *DataValidation
+}
+
+// CheckDataValidations provides a function to check the current cell values
+// of the worksheet against the data validations of the worksheet by given
+// worksheet name, and returns the cells which violate the data validations.
+// The criteria formulas and the custom formulas of the data validations will
+// be evaluated by the calculation engine, and the relative references in
+// these formulas are adjusted for each cell in the same way as Excel, which
+// relative to the top-left cell of the data validation range. Only the cells
+// that exist in the worksheet will be checked, and the empty cells are valid
+// if the data validation allows blank. The list items are compared
+// case-insensitively. For example, check the cells in Sheet1:
+//
+//	violations, err := f.CheckDataValidations("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, violation := range violations {
+//	    fmt.Println(violation.Cell, violation.Value)
+//	}
+func (f *File) CheckDataValidations(sheet string) ([]DataValidationViolation, error) {
+	var violations []DataValidationViolation
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return violations, err
+	}
+	if ws.DataValidations == nil {
+		return violations, err
+	}
+	var cells [][]int
+	ws.Lock()
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			col, r, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				ws.Unlock()
+				return violations, err
+			}
+			cells = append(cells, []int{col, r})
+		}
+	}
+	dataValidations := ws.DataValidations.DataValidation
+	ws.Unlock()
+	for _, dv := range dataValidations {
+		var refs [][]int
+		for _, ref := range strings.Fields(dv.Sqref) {
+			if !strings.Contains(ref, ":") {
+				ref += ":" + ref
+			}
+			coordinates, err := rangeRefToCoordinates(ref)
+			if err != nil {
+				return violations, err
+			}
+			_ = sortCoordinates(coordinates)
+			refs = append(refs, coordinates)
+		}
+		if len(refs) == 0 {
+			continue
+		}
+		topLeftCell, _ := CoordinatesToCellName(refs[0][0], refs[0][1])
+		for _, coordinates := range cells {
+			if !inDataValidationRefs(refs, coordinates) {
+				continue
+			}
+			cell, _ := CoordinatesToCellName(coordinates[0], coordinates[1])
+			value, err := f.GetCellValue(sheet, cell, Options{RawCellValue: true})
+			if err != nil {
+				return violations, err
+			}
+			if value == "" && dv.AllowBlank {
+				continue
+			}
+			valid, err := f.checkDataValidation(sheet, cell, topLeftCell, value, dv)
+			if err != nil {
+				return violations, err
+			}
+			if !valid {
+				violations = append(violations, DataValidationViolation{Cell: cell, Value: value, DataValidation: dv})
+			}
+		}
+	}
+	return violations, err
+}
+
+// inDataValidationRefs provides a function to check if the cell coordinates
+// in the ranges coordinates of the data validation.
+func inDataValidationRefs(refs [][]int, coordinates []int) bool {
+	for _, ref := range refs {
+		if coordinates[0] >= ref[0] && coordinates[0] <= ref[2] &&
+			coordinates[1] >= ref[1] && coordinates[1] <= ref[3] {
+			return true
+		}
+	}
+	return false
+}
+
+// getDataValidationFormulas provides a function to get the unescaped first
+// and second formulas of the data validation.
+func getDataValidationFormulas(dv *DataValidation) (string, string) {
+	var formulas struct {
+		Formula1 string `xml:"formula1"`
+		Formula2 string `xml:"formula2"`
+	}
+	_ = xml.Unmarshal([]byte("<dataValidation>"+dv.Formula1+dv.Formula2+"</dataValidation>"), &formulas)
+	return formulas.Formula1, formulas.Formula2
+}
+
+// checkDataValidation provides a function to check the cell value against the
+// data validation by given worksheet name, cell reference, the top-left cell
+// reference of the data validation range, the cell value and the data
+// validation.
+func (f *File) checkDataValidation(sheet, cell, topLeftCell, value string, dv *DataValidation) (bool, error) {
+	formula1, formula2 := getDataValidationFormulas(dv)
+	eval := func(formula string) (formulaArg, error) {
+		ctx := &calcContext{
+			entry:      fmt.Sprintf("%s!%s", sheet, cell),
+			iterations: make(map[string]uint),
+		}
+		return f.evalFormula(ctx, sheet, cell, shiftSharedFormula(strings.TrimPrefix(formula, "="), topLeftCell, cell))
+	}
+	switch dv.Type {
+	case "custom":
+		result, err := eval(formula1)
+		if err != nil {
+			return false, err
+		}
+		result = result.firstElement()
+		if result.Type == ArgString {
+			return strings.EqualFold(result.String, "TRUE"), err
+		}
+		return result.Type == ArgNumber && result.Number != 0, err
+	case "list":
+		if len(formula1) > 1 && strings.HasPrefix(formula1, "\"") && strings.HasSuffix(formula1, "\"") {
+			for _, item := range strings.Split(strings.ReplaceAll(formula1[1:len(formula1)-1], "\"\"", "\""), ",") {
+				if strings.EqualFold(item, value) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		result, err := eval(formula1)
+		if err != nil {
+			return false, err
+		}
+		for _, row := range result.toMatrix() {
+			for _, item := range row {
+				if strings.EqualFold(item.Value(), value) {
+					return true, err
+				}
+			}
+		}
+		return false, err
+	case "whole", "decimal", "date", "time", "textLength":
+		num, err := strconv.ParseFloat(value, 64)
+		if dv.Type == "textLength" {
+			num, err = float64(len(utf16.Encode([]rune(value)))), nil
+		}
+		if err != nil || (dv.Type == "whole" && num != math.Trunc(num)) {
+			return false, nil
+		}
+		var criteria [2]float64
+		for i, formula := range []string{formula1, formula2} {
+			if i == 1 && dv.Operator != "" && dv.Operator != "between" && dv.Operator != "notBetween" {
+				break
+			}
+			result, err := eval(formula)
+			if err != nil {
+				return false, err
+			}
+			if result = result.firstElement().ToNumber(); result.Type != ArgNumber {
+				return false, err
+			}
+			criteria[i] = result.Number
+		}
+		return compareDataValidation(dv.Operator, num, criteria[0], criteria[1]), err
+	}
+	return true, nil
+}
+
+// compareDataValidation provides a function to compare the number with the
+// criteria by given data validation operator.
+func compareDataValidation(operator string, num, criteria1, criteria2 float64) bool {
+	switch operator {
+	case "equal":
+		return num == criteria1
+	case "notEqual":
+		return num != criteria1
+	case "greaterThan":
+		return num > criteria1
+	case "greaterThanOrEqual":
+		return num >= criteria1
+	case "lessThan":
+		return num < criteria1
+	case "lessThanOrEqual":
+		return num <= criteria1
+	case "notBetween":
+		return num < math.Min(criteria1, criteria2) || num > math.Max(criteria1, criteria2)
+	}
+	return num >= math.Min(criteria1, criteria2) && num <= math.Max(criteria1, criteria2)
+}
+
 // squashSqref generates cell reference sequence by given cells coordinates list.
 func (f *File) squashSqref(cells [][]int) []string {
 	if len(cells) == 1 {
@@ -105,6 +105,96 @@ func TestDataValidation(t *testing.T) {
 	assert.Equal(t, []*DataValidation(nil), dataValidations)
 }
 
+func TestGetDataValidations(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("R&D Data")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetCol("R&D Data", "A1", &[]string{"Alpha", "Beta", "Gamma"}))
+	dv := NewDataValidation(true)
+	dv.Sqref = "A1:A10"
+	dv.SetSqrefDropList("='R&D Data'!$A$1:$A$3")
+	assert.Equal(t, "<formula1>'R&amp;D Data'!$A$1:$A$3</formula1>", dv.Formula1)
+	dv.SetInput(strings.Repeat("标", 40), strings.Repeat("😀", 128))
+	dv.SetError(DataValidationErrorStyleStop, "Fehler", "Ungültiger Wert")
+	assert.Len(t, []rune(*dv.PromptTitle), 32)
+	assert.Len(t, []rune(*dv.Prompt), 127)
+	assert.Equal(t, "Ungültiger Wert", *dv.Error)
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	dv = NewDataValidation(false)
+	dv.Sqref = "B1:B10"
+	assert.NoError(t, dv.SetRange("=LEN(B1)", "=MAX(1,\"a\"<\"b\")", DataValidationTypeDecimal, DataValidationOperatorNotBetween))
+	assert.Equal(t, "<formula2>MAX(1,\"a\"&lt;\"b\")</formula2>", dv.Formula2)
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	dv = NewDataValidation(true)
+	dv.Sqref = "C1:C10"
+	assert.NoError(t, dv.SetRange(1, 5, DataValidationTypeWhole, DataValidationOperatorBetween))
+	dv.Operator = ""
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "Beta"))
+	assert.NoError(t, f.SetCellStr("Sheet1", "A2", "Delta"))
+	violations, err := f.CheckDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "A2", violations[0].Cell)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetDataValidations.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestGetDataValidations.xlsx"))
+	assert.NoError(t, err)
+	dataValidations, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dataValidations, 3)
+	assert.Equal(t, "<formula1>'R&amp;D Data'!$A$1:$A$3</formula1>", dataValidations[0].Formula1)
+	assert.Empty(t, dataValidations[0].Formula2)
+	assert.Equal(t, strings.Repeat("😀", 127), *dataValidations[0].Prompt)
+	assert.Equal(t, "Ungültiger Wert", *dataValidations[0].Error)
+	assert.Equal(t, "<formula1>LEN(B1)</formula1>", dataValidations[1].Formula1)
+	assert.Equal(t, "<formula2>MAX(1,\"a\"&lt;\"b\")</formula2>", dataValidations[1].Formula2)
+	assert.Equal(t, "notBetween", dataValidations[1].Operator)
+	assert.Equal(t, "<formula1>1</formula1>", dataValidations[2].Formula1)
+	assert.Equal(t, "<formula2>5</formula2>", dataValidations[2].Formula2)
+	assert.Equal(t, "between", dataValidations[2].Operator)
+	// Test the data validations of the worksheet will not be changed
+	worksheet, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Equal(t, "<formula1>1</formula1><formula2>5</formula2>", worksheet.(*xlsxWorksheet).DataValidations.DataValidation[2].Formula1)
+	assert.Empty(t, worksheet.(*xlsxWorksheet).DataValidations.DataValidation[2].Operator)
+	dataValidations[2].Sqref = "D1"
+	assert.Equal(t, "C1:C10", worksheet.(*xlsxWorksheet).DataValidations.DataValidation[2].Sqref)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetDataValidations2.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestGetDataValidations2.xlsx"))
+	assert.NoError(t, err)
+	dataValidations, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dataValidations, 3)
+	assert.Equal(t, "<formula1>LEN(B1)</formula1>", dataValidations[1].Formula1)
+	assert.NoError(t, f.Close())
+
+	// Test get data validations in the extension list of the worksheet
+	f = NewFile()
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ExtLst = &xlsxExtLst{Ext: `<ext uri="{CCE6A557-97BC-4b89-ADB6-D9C93CAAB3DF}" xmlns:x14="` + NameSpaceSpreadSheetX14.Value +
+		`"><x14:dataValidations count="2" xmlns:xm="` + NameSpaceSpreadSheetExcel2006Main.Value +
+		`"><x14:dataValidation type="list" allowBlank="1" showInputMessage="1" showErrorMessage="1" promptTitle="Region"><x14:formula1><xm:f>'R&amp;D'!$A$1:$A$3</xm:f></x14:formula1><xm:sqref>A1:A10</xm:sqref></x14:dataValidation>` +
+		`<x14:dataValidation type="whole"><x14:formula1><xm:f>Sheet2!$B$1</xm:f></x14:formula1><x14:formula2><xm:f>Sheet2!$B$2</xm:f></x14:formula2><xm:sqref>B1</xm:sqref></x14:dataValidation></x14:dataValidations></ext>`}
+	dataValidations, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*DataValidation{
+		{AllowBlank: true, ShowErrorMessage: true, ShowInputMessage: true, PromptTitle: stringPtr("Region"), Sqref: "A1:A10", Type: "list", Formula1: "<formula1>'R&amp;D'!$A$1:$A$3</formula1>"},
+		{Operator: "between", Sqref: "B1", Type: "whole", Formula1: "<formula1>Sheet2!$B$1</formula1>", Formula2: "<formula2>Sheet2!$B$2</formula2>"},
+	}, dataValidations)
+	// Test get data validations with unsupported charset extension list
+	ws.ExtLst.Ext = `<ext uri="` + ExtURIDataValidations + `">` + string(MacintoshCyrillicCharset) + `</ext>`
+	_, err = f.GetDataValidations("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	ws.ExtLst.Ext = string(MacintoshCyrillicCharset)
+	_, err = f.GetDataValidations("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestDataValidationError(t *testing.T) {
 	resultFile := filepath.Join("test", "TestDataValidationError.xlsx")
 	
@@ -195,6 +285,13 @@ func TestDeleteDataValidation(t *testing.T) {
 	dvRange.SetInput("input title", "input body")
 	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
 	assert.NoError(t, f.DeleteDataValidation("Sheet1", "D3"))
+	// Test delete data validation with multiple references
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "C3", "D4"))
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 2)
+	assert.Equal(t, "C2 C5", dvs[0].Sqref)
+	assert.Equal(t, "D2", dvs[1].Sqref)
 	
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteDataValidation.xlsx")))
 	
@@ -216,3 +313,116 @@ func TestDeleteDataValidation(t *testing.T) {
 	assert.NoError(t, f.DeleteDataValidation("Sheet1"))
 	assert.Nil(t, ws.(*xlsxWorksheet).DataValidations)
 }
+
+func TestSetDataValidationRange(t *testing.T) {
+	f := NewFile()
+	dv1 := NewDataValidation(true)
+	dv1.Sqref = "A1:A10"
+	assert.NoError(t, dv1.SetDropList([]string{"1", "2", "3"}))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv1))
+	dv2 := NewDataValidation(true)
+	dv2.Sqref = "C1:C10 E1"
+	assert.NoError(t, dv2.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv2))
+	// Test retarget the data validation which overlaps other data validation
+	assert.NoError(t, f.SetDataValidationRange("Sheet1", "$A$1:$A$10", " A1:A20  C5:C6 "))
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 2)
+	assert.Equal(t, "A1:A20 C5:C6", dvs[0].Sqref)
+	assert.Equal(t, "C1:C4 C7:C10 E1", dvs[1].Sqref)
+	// Test retarget the data validation which covers other data validation
+	assert.NoError(t, f.SetDataValidationRange("Sheet1", "c1:c4 c7:c10 e1", "A1:E20"))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 1)
+	assert.Equal(t, "A1:E20", dvs[0].Sqref)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetDataValidationRange.xlsx")))
+	// Test retarget data validation with invalid parameters
+	assert.EqualError(t, f.SetDataValidationRange("Sheet1", "A1:E20", " "), ErrParameterRequired.Error())
+	assert.EqualError(t, f.SetDataValidationRange("Sheet1", "A1:A10", "B1"), newNoExistDataValidationError("A1:A10").Error())
+	assert.EqualError(t, f.SetDataValidationRange("Sheet1", "A1:E20", "A1:A"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.SetDataValidationRange("SheetN", "A1:E20", "A1"), "sheet SheetN does not exist")
+	dv2.Sqref = "A"
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv2))
+	assert.EqualError(t, f.SetDataValidationRange("Sheet1", "A1:E20", "A1"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.NoError(t, f.Close())
+	// Test retarget data validation on the worksheet without data validations
+	f = NewFile()
+	assert.EqualError(t, f.SetDataValidationRange("Sheet1", "A1", "A2"), newNoExistDataValidationError("A1").Error())
+	assert.NoError(t, f.Close())
+}
+
+func TestCheckDataValidations(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": 15, "A2": 25, "A3": 12.5,
+		"B1": "Apple", "B2": "grape", "B3": "BANANA",
+		"C1": "East", "C2": "North",
+		"D1": 4, "D2": 5, "D3": 6,
+		"E1": "hello", "E2": "hello world",
+		"F1": "East", "F2": "West",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "G1", 10))
+	dvWhole := NewDataValidation(true)
+	dvWhole.Sqref = "A1:A4"
+	assert.NoError(t, dvWhole.SetRange(10, "$G$1*2", DataValidationTypeWhole, DataValidationOperatorBetween))
+	dvList := NewDataValidation(true)
+	dvList.Sqref = "B1:B3"
+	assert.NoError(t, dvList.SetDropList([]string{"apple", "banana"}))
+	dvSqrefList := NewDataValidation(false)
+	dvSqrefList.Sqref = "C1:C3"
+	dvSqrefList.SetSqrefDropList("$F$1:$F$2")
+	// Test check the custom formula with relative references
+	dvCustom := NewDataValidation(true)
+	dvCustom.Sqref = "D1:D3"
+	dvCustom.Formula1 = "<formula1>MOD(D1,2)=0</formula1>"
+	dvCustom.Type = convDataValidationType(DataValidationTypeCustom)
+	dvTextLength := NewDataValidation(true)
+	dvTextLength.Sqref = "E1:E2"
+	assert.NoError(t, dvTextLength.SetRange(5, 0, DataValidationTypeTextLength, DataValidationOperatorLessThanOrEqual))
+	for _, dv := range []*DataValidation{dvWhole, dvList, dvSqrefList, dvCustom, dvTextLength} {
+		assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", ""))
+	violations, err := f.CheckDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []DataValidationViolation{
+		{Cell: "A2", Value: "25", DataValidation: dvWhole},
+		{Cell: "A3", Value: "12.5", DataValidation: dvWhole},
+		{Cell: "B2", Value: "grape", DataValidation: dvList},
+		{Cell: "C2", Value: "North", DataValidation: dvSqrefList},
+		{Cell: "C3", Value: "", DataValidation: dvSqrefList},
+		{Cell: "D2", Value: "5", DataValidation: dvCustom},
+		{Cell: "E2", Value: "hello world", DataValidation: dvTextLength},
+	}, violations)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCheckDataValidations.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test check data validations after reopening the workbook
+	f, err = OpenFile(filepath.Join("test", "TestCheckDataValidations.xlsx"))
+	assert.NoError(t, err)
+	violations, err = f.CheckDataValidations("Sheet1")
+	assert.NoError(t, err)
+	var cells []string
+	for _, violation := range violations {
+		cells = append(cells, violation.Cell)
+	}
+	assert.Equal(t, []string{"A2", "A3", "B2", "C2", "C3", "D2", "E2"}, cells)
+	// Test check data validations with invalid range reference
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).DataValidations.DataValidation[0].Sqref = "A"
+	_, err = f.CheckDataValidations("Sheet1")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test check data validations with invalid cell reference
+	ws.(*xlsxWorksheet).SheetData.Row = []xlsxRow{{R: 1, C: []xlsxC{{R: "A"}}}}
+	_, err = f.CheckDataValidations("Sheet1")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test check data validations on not exist worksheet
+	_, err = f.CheckDataValidations("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
@@ -16,6 +16,10 @@ import (
 	"encoding/xml"
 	"io"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // SetAppProps provides a function to set document application properties. The
@@ -175,7 +179,6 @@ func (f *File) GetAppProps() (ret *AppProperties, err error) {
 //	})
 func (f *File) SetDocProps(docProperties *DocProperties) error {
 	var (
-		core               *decodeCoreProperties
 		err                error
 		field, val         string
 		fields             []string
@@ -184,12 +187,41 @@ func (f *File) SetDocProps(docProperties *DocProperties) error {
 		output             []byte
 	)
 	
-	core = new(decodeCoreProperties)
-	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(defaultXMLPathDocPropsCore)))).
-		Decode(core); err != nil && err != io.EOF {
+	if newProps, err = f.corePropsReader(); err != nil {
 		return err
 	}
-	newProps = &xlsxCoreProperties{
+	fields = []string{
+		"Category", "ContentStatus", "Creator", "Description", "Identifier", "Keywords",
+		"LastModifiedBy", "Revision", "Subject", "Title", "Language", "Version",
+	}
+	immutable, mutable = reflect.ValueOf(*docProperties), reflect.ValueOf(newProps).Elem()
+	for _, field = range fields {
+		if val = immutable.FieldByName(field).String(); val != "" {
+			mutable.FieldByName(field).SetString(val)
+		}
+	}
+	if docProperties.Created != "" {
+		newProps.Created = &xlsxDcTerms{Type: "dcterms:W3CDTF", Text: docProperties.Created}
+	}
+	if docProperties.Modified != "" {
+		newProps.Modified = &xlsxDcTerms{Type: "dcterms:W3CDTF", Text: docProperties.Modified}
+	}
+	output, err = xml.Marshal(newProps)
+	f.saveFileList(defaultXMLPathDocPropsCore, output)
+	
+	return err
+}
+
+// corePropsReader provides a function to get the pointer to the structure
+// of the document core properties after deserialization of
+// docProps/core.xml.
+func (f *File) corePropsReader() (*xlsxCoreProperties, error) {
+	core := new(decodeCoreProperties)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(defaultXMLPathDocPropsCore)))).
+		Decode(core); err != nil && err != io.EOF {
+		return nil, err
+	}
+	props := &xlsxCoreProperties{
 		Dc:             NameSpaceDublinCore,
 		Dcterms:        NameSpaceDublinCoreTerms,
 		Dcmitype:       NameSpaceDublinCoreMetadataInitiative,
@@ -208,30 +240,67 @@ func (f *File) SetDocProps(docProperties *DocProperties) error {
 		Version:        core.Version,
 	}
 	if core.Created != nil {
-		newProps.Created = &xlsxDcTerms{Type: core.Created.Type, Text: core.Created.Text}
+		props.Created = &xlsxDcTerms{Type: core.Created.Type, Text: core.Created.Text}
 	}
 	if core.Modified != nil {
-		newProps.Modified = &xlsxDcTerms{Type: core.Modified.Type, Text: core.Modified.Text}
+		props.Modified = &xlsxDcTerms{Type: core.Modified.Type, Text: core.Modified.Text}
 	}
-	fields = []string{
-		"Category", "ContentStatus", "Creator", "Description", "Identifier", "Keywords",
-		"LastModifiedBy", "Revision", "Subject", "Title", "Language", "Version",
+	return props, nil
+}
+
+// personalInfoWriter provides a function to remove the personal information
+// from the document core properties and the comments, and mark the workbook
+// to filter the personal information if the StripPersonalInfo option has
+// been enabled.
+func (f *File) personalInfoWriter() error {
+	if f.options == nil || !f.options.StripPersonalInfo {
+		return nil
 	}
-	immutable, mutable = reflect.ValueOf(*docProperties), reflect.ValueOf(newProps).Elem()
-	for _, field = range fields {
-		if val = immutable.FieldByName(field).String(); val != "" {
-			mutable.FieldByName(field).SetString(val)
+	if _, ok := f.Pkg.Load(defaultXMLPathDocPropsCore); ok {
+		props, err := f.corePropsReader()
+		if err != nil {
+			return err
+		}
+		props.Creator, props.LastModifiedBy = "", ""
+		output, err := xml.Marshal(props)
+		if err != nil {
+			return err
 		}
+		f.saveFileList(defaultXMLPathDocPropsCore, output)
 	}
-	if docProperties.Created != "" {
-		newProps.Created = &xlsxDcTerms{Type: "dcterms:W3CDTF", Text: docProperties.Created}
+	var paths []string
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/comments") {
+			paths = append(paths, k.(string))
+		}
+		return true
+	})
+	for path := range f.Comments {
+		if _, ok := f.Pkg.Load(path); !ok {
+			paths = append(paths, path)
+		}
 	}
-	if docProperties.Modified != "" {
-		newProps.Modified = &xlsxDcTerms{Type: "dcterms:W3CDTF", Text: docProperties.Modified}
+	for _, path := range paths {
+		cmts, err := f.commentsReader(path)
+		if err != nil {
+			return err
+		}
+		if cmts == nil {
+			continue
+		}
+		cmts.Authors.Author = []string{"Author"}
+		for i := range cmts.CommentList.Comment {
+			cmts.CommentList.Comment[i].AuthorID = 0
+		}
 	}
-	output, err = xml.Marshal(newProps)
-	f.saveFileList(defaultXMLPathDocPropsCore, output)
-	
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.WorkbookPr == nil {
+		wb.WorkbookPr = &xlsxWorkbookPr{}
+	}
+	wb.WorkbookPr.FilterPrivacy = true
 	return err
 }
 
@@ -265,3 +334,241 @@ func (f *File) GetDocProps() (ret *DocProperties, err error) {
 	}
 	return
 }
+
+// SetCustomProps provides a function to set custom file properties by given
+// property name and value. If the property name already exists, it will be
+// updated, otherwise a new property will be added. The value can be of type
+// int32, float64, bool, string, time.Time or nil. The property will be
+// deleted if the value of it is nil and without link target. Specify the
+// 'LinkTarget' field with a name of the defined name in the workbook scope to
+// link the property to the content of the cell which the defined name refers
+// to, the value of the property will be taken from the cell. For example,
+// set a custom property with the name "Project" and the value linked to the
+// defined name "ProjectName":
+//
+//	err := f.SetCustomProps(excelize.CustomProperty{
+//	    Name:       "Project",
+//	    LinkTarget: "ProjectName",
+//	})
+func (f *File) SetCustomProps(prop CustomProperty) error {
+	if prop.Name == "" {
+		return ErrParameterInvalid
+	}
+	props, err := f.customPropsReader()
+	if err != nil {
+		return err
+	}
+	if err = f.setCustomProp(props, prop); err != nil {
+		return err
+	}
+	return f.customPropsWriter(props)
+}
+
+// SetCustomPropsMap provides a function to set custom file properties in bulk
+// by given map of property names and values. The value can be of type int32,
+// float64, bool, string, time.Time or nil, the property will be deleted if
+// the value of it is nil. The existing properties not in the map will be
+// kept. For example:
+//
+//	err := f.SetCustomPropsMap(map[string]interface{}{
+//	    "Department": "Finance",
+//	    "Approved":   true,
+//	    "Version":    int32(3),
+//	})
+func (f *File) SetCustomPropsMap(props map[string]interface{}) error {
+	customProps, err := f.customPropsReader()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" {
+			return ErrParameterInvalid
+		}
+		if err = f.setCustomProp(customProps, CustomProperty{Name: name, Value: props[name]}); err != nil {
+			return err
+		}
+	}
+	return f.customPropsWriter(customProps)
+}
+
+// GetCustomProps provides a function to get custom file properties. The
+// value of the property linked to the content will be read from the cell
+// which the defined name of the link target refers to.
+func (f *File) GetCustomProps() ([]CustomProperty, error) {
+	var customProps []CustomProperty
+	props, err := f.customPropsReader()
+	if err != nil {
+		return customProps, err
+	}
+	for _, prop := range props.Property {
+		customProp := CustomProperty{Name: prop.Name, LinkTarget: prop.LinkTarget, Value: getCustomPropValue(prop)}
+		if prop.LinkTarget != "" {
+			if value, err := f.getCustomPropLinkValue(prop.LinkTarget); err == nil {
+				customProp.Value = value
+			}
+		}
+		customProps = append(customProps, customProp)
+	}
+	return customProps, err
+}
+
+// GetCustomPropsMap provides a function to get custom file properties as a
+// map of property names and values.
+func (f *File) GetCustomPropsMap() (map[string]interface{}, error) {
+	props := make(map[string]interface{})
+	customProps, err := f.GetCustomProps()
+	for _, prop := range customProps {
+		props[prop.Name] = prop.Value
+	}
+	return props, err
+}
+
+// customPropsReader provides a function to get the pointer to the structure
+// after deserialization of docProps/custom.xml.
+func (f *File) customPropsReader() (*xlsxCustomProperties, error) {
+	decodeProps, props := new(decodeCustomProperties), &xlsxCustomProperties{}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(defaultXMLPathDocPropsCustom)))).
+		Decode(decodeProps); err != nil && err != io.EOF {
+		return props, err
+	}
+	for _, prop := range decodeProps.Property {
+		customProp := xlsxCustomProperty{
+			FmtID: prop.FmtID, PID: prop.PID, Name: prop.Name, LinkTarget: prop.LinkTarget,
+			Lpwstr: prop.Lpwstr, I4: prop.I4, R8: prop.R8, Bool: prop.Bool, FileTime: prop.FileTime,
+		}
+		if prop.Lpstr != nil {
+			customProp.Lpwstr = prop.Lpstr
+		}
+		if customProp.Lpwstr == nil && customProp.I4 == nil && customProp.R8 == nil &&
+			customProp.Bool == nil && customProp.FileTime == nil {
+			customProp.Content = prop.Content
+		}
+		props.Property = append(props.Property, customProp)
+	}
+	return props, nil
+}
+
+// customPropsWriter provides a function to save docProps/custom.xml after
+// serialize structure, the relationship and content type of the custom file
+// properties part will be added if not exist.
+func (f *File) customPropsWriter(props *xlsxCustomProperties) error {
+	if _, ok := f.Pkg.Load(defaultXMLPathDocPropsCustom); !ok {
+		if err := f.setContentTypes("/"+defaultXMLPathDocPropsCustom, ContentTypeCustomProperties); err != nil {
+			return err
+		}
+		f.addRels("_rels/.rels", SourceRelationshipCustomProperties, defaultXMLPathDocPropsCustom, "")
+	}
+	props.Vt = NameSpaceDocumentPropertiesVariantTypes.Value
+	output, err := xml.Marshal(props)
+	f.saveFileList(defaultXMLPathDocPropsCustom, output)
+	return err
+}
+
+// setCustomProp provides a function to add, update or delete the custom
+// property in the custom file properties by given property settings.
+func (f *File) setCustomProp(props *xlsxCustomProperties, prop CustomProperty) error {
+	customProp := xlsxCustomProperty{FmtID: "{D5CDD505-2E9C-101B-9397-08002B2CF9AE}", Name: prop.Name, LinkTarget: prop.LinkTarget}
+	value := prop.Value
+	if prop.LinkTarget != "" {
+		linkValue, err := f.getCustomPropLinkValue(prop.LinkTarget)
+		if err != nil {
+			return err
+		}
+		value = linkValue
+	}
+	switch v := value.(type) {
+	case nil:
+		for idx, p := range props.Property {
+			if p.Name == prop.Name {
+				props.Property = append(props.Property[:idx], props.Property[idx+1:]...)
+				break
+			}
+		}
+		return nil
+	case int32:
+		customProp.I4 = &v
+	case int:
+		i4 := int32(v)
+		customProp.I4 = &i4
+	case float64:
+		customProp.R8 = &v
+	case bool:
+		customProp.Bool = &v
+	case string:
+		customProp.Lpwstr = &v
+	case time.Time:
+		customProp.FileTime = stringPtr(v.UTC().Format(time.RFC3339))
+	default:
+		return ErrParameterInvalid
+	}
+	pid := 1
+	for idx, p := range props.Property {
+		if p.Name == prop.Name {
+			customProp.PID = p.PID
+			props.Property[idx] = customProp
+			return nil
+		}
+		if p.PID > pid {
+			pid = p.PID
+		}
+	}
+	customProp.PID = pid + 1
+	props.Property = append(props.Property, customProp)
+	return nil
+}
+
+// getCustomPropValue provides a function to get the value of the custom
+// property by given property.
+func getCustomPropValue(prop xlsxCustomProperty) interface{} {
+	switch {
+	case prop.Lpwstr != nil:
+		return *prop.Lpwstr
+	case prop.I4 != nil:
+		return *prop.I4
+	case prop.R8 != nil:
+		return *prop.R8
+	case prop.Bool != nil:
+		return *prop.Bool
+	case prop.FileTime != nil:
+		if t, err := time.Parse(time.RFC3339, *prop.FileTime); err == nil {
+			return t
+		}
+		return *prop.FileTime
+	}
+	return nil
+}
+
+// getCustomPropLinkValue provides a function to get the value of the cell
+// which the defined name in the workbook scope refers to by given link
+// target of the custom property.
+func (f *File) getCustomPropLinkValue(linkTarget string) (interface{}, error) {
+	ref := f.getDefinedNameRefTo(linkTarget, "")
+	idx := strings.LastIndex(ref, "!")
+	if idx == -1 {
+		return nil, ErrDefinedNameScope
+	}
+	sheet := strings.ReplaceAll(strings.Trim(ref[:idx], "'"), "''", "'")
+	cell := strings.ReplaceAll(strings.Split(ref[idx+1:], ":")[0], "$", "")
+	cellType, err := f.GetCellType(sheet, cell)
+	if err != nil {
+		return nil, err
+	}
+	value, err := f.GetCellValue(sheet, cell, Options{RawCellValue: true})
+	if err != nil {
+		return nil, err
+	}
+	if cellType == CellTypeBool {
+		return value == "1", err
+	}
+	if cellType == CellTypeUnset || cellType == CellTypeNumber {
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number, nil
+		}
+	}
+	return value, err
+}
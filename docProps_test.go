@@ -14,6 +14,7 @@ package excel
 import (
 	"path/filepath"
 	"testing"
+	"time"
 	
 	"github.com/stretchr/testify/assert"
 )
@@ -116,3 +117,99 @@ func TestGetDocProps(t *testing.T) {
 	_, err = f.GetDocProps()
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
+
+func TestSetCustomProps(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Budget"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 1250.5))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", true))
+	for name, refersTo := range map[string]string{"ProjectName": "Sheet1!$A$1", "ProjectCost": "Sheet1!$A$2", "ProjectDone": "Sheet1!$A$3"} {
+		assert.NoError(t, f.SetDefinedName(&DefinedName{Name: name, RefersTo: refersTo}))
+	}
+	created := time.Date(2023, 5, 1, 8, 30, 0, 0, time.UTC)
+	for _, prop := range []CustomProperty{
+		{Name: "Department", Value: "Finance"},
+		{Name: "Version", Value: int32(3)},
+		{Name: "Rate", Value: 0.75},
+		{Name: "Approved", Value: true},
+		{Name: "Created", Value: created},
+		{Name: "Project", LinkTarget: "ProjectName"},
+		{Name: "Cost", LinkTarget: "ProjectCost"},
+		{Name: "Done", LinkTarget: "ProjectDone"},
+	} {
+		assert.NoError(t, f.SetCustomProps(prop))
+	}
+	// Test update and delete custom property
+	assert.NoError(t, f.SetCustomProps(CustomProperty{Name: "Version", Value: 4}))
+	assert.NoError(t, f.SetCustomProps(CustomProperty{Name: "Rate"}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Forecast"))
+	props, err := f.GetCustomProps()
+	assert.NoError(t, err)
+	assert.Equal(t, []CustomProperty{
+		{Name: "Department", Value: "Finance"},
+		{Name: "Version", Value: int32(4)},
+		{Name: "Approved", Value: true},
+		{Name: "Created", Value: created},
+		{Name: "Project", Value: "Forecast", LinkTarget: "ProjectName"},
+		{Name: "Cost", Value: 1250.5, LinkTarget: "ProjectCost"},
+		{Name: "Done", Value: true, LinkTarget: "ProjectDone"},
+	}, props)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCustomProps.xlsx")))
+	assert.NoError(t, f.Close())
+	// Test get custom properties from saved workbook
+	f, err = OpenFile(filepath.Join("test", "TestSetCustomProps.xlsx"))
+	assert.NoError(t, err)
+	props, err = f.GetCustomProps()
+	assert.NoError(t, err)
+	assert.Len(t, props, 7)
+	assert.Equal(t, "Forecast", props[4].Value)
+	// Test set custom properties in bulk
+	assert.NoError(t, f.SetCustomPropsMap(map[string]interface{}{"Department": "Sales", "Approved": nil, "Owner": "Bob"}))
+	propsMap, err := f.GetCustomPropsMap()
+	assert.NoError(t, err)
+	assert.Equal(t, "Sales", propsMap["Department"])
+	assert.Equal(t, "Bob", propsMap["Owner"])
+	assert.NotContains(t, propsMap, "Approved")
+	assert.Len(t, propsMap, 7)
+	// Test set custom properties with invalid parameters
+	assert.Equal(t, ErrParameterInvalid, f.SetCustomProps(CustomProperty{}))
+	assert.Equal(t, ErrParameterInvalid, f.SetCustomProps(CustomProperty{Name: "Size", Value: []int{1}}))
+	assert.Equal(t, ErrParameterInvalid, f.SetCustomPropsMap(map[string]interface{}{"": "Value"}))
+	assert.Equal(t, ErrParameterInvalid, f.SetCustomPropsMap(map[string]interface{}{"Size": uint8(1)}))
+	assert.Equal(t, ErrDefinedNameScope, f.SetCustomProps(CustomProperty{Name: "Link", LinkTarget: "NotExists"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Invalid", RefersTo: "SheetN!$A$1"}))
+	assert.EqualError(t, f.SetCustomProps(CustomProperty{Name: "Link", LinkTarget: "Invalid"}), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+	// Test set and get custom properties with unsupported charset
+	f = NewFile()
+	f.Pkg.Store(defaultXMLPathDocPropsCustom, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCustomProps(CustomProperty{Name: "Name", Value: "Value"}), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.SetCustomPropsMap(map[string]interface{}{"Name": "Value"}), "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetCustomPropsMap()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test set custom properties with unsupported charset content types
+	f = NewFile()
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCustomProps(CustomProperty{Name: "Name", Value: "Value"}), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetCustomProps(t *testing.T) {
+	f := NewFile()
+	f.Pkg.Store(defaultXMLPathDocPropsCustom, []byte(`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="Text"><vt:lpstr>Value</vt:lpstr></property><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="3" name="Size"><vt:i8>10</vt:i8></property><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="4" name="Date"><vt:filetime>2023-05</vt:filetime></property><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="5" name="Link" linkTarget="NotExists"><vt:lpwstr>Cached</vt:lpwstr></property></Properties>`))
+	props, err := f.GetCustomProps()
+	assert.NoError(t, err)
+	assert.Equal(t, []CustomProperty{
+		{Name: "Text", Value: "Value"},
+		{Name: "Size"},
+		{Name: "Date", Value: "2023-05"},
+		{Name: "Link", Value: "Cached", LinkTarget: "NotExists"},
+	}, props)
+	// Test the property with unsupported value type should be kept
+	assert.NoError(t, f.SetCustomProps(CustomProperty{Name: "Text", Value: "New"}))
+	assert.NoError(t, f.SetCustomProps(CustomProperty{Name: "Extra", Value: "Value"}))
+	content, ok := f.Pkg.Load(defaultXMLPathDocPropsCustom)
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<vt:i8>10</vt:i8>`)
+	assert.Contains(t, string(content.([]byte)), `pid="6"`)
+}
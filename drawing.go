@@ -57,6 +57,10 @@ func (f *File) prepareChartSheetDrawing(cs *xlsxChartsheet, drawingID int, sheet
 // given format sets.
 func (f *File) addChart(opts *Chart, comboCharts []*Chart) {
 	count := f.countCharts()
+	if _, ok := chartExLayoutID[opts.Type]; ok {
+		f.addChartEx(opts, count+1)
+		return
+	}
 	xlsxChartSpace := xlsxChartSpace{
 		XMLNSa:         NameSpaceDrawingML.Value,
 		Date1904:       &attrValBool{Val: boolPtr(false)},
@@ -95,12 +99,14 @@ func (f *File) addChart(opts *Chart, comboCharts []*Chart) {
 									},
 								},
 							},
-							R: &aR{
-								RPr: aRPr{
-									Lang:    "en-US",
-									AltLang: "en-US",
+							R: []*aR{
+								{
+									RPr: aRPr{
+										Lang:    "en-US",
+										AltLang: "en-US",
+									},
+									T: opts.Title.Name,
 								},
-								T: opts.Title.Name,
 							},
 						},
 					},
@@ -254,7 +260,14 @@ func (f *File) addChart(opts *Chart, comboCharts []*Chart) {
 	order := len(opts.Series)
 	for idx := range comboCharts {
 		comboCharts[idx].order = order
-		addChart(xlsxChartSpace.Chart.PlotArea, plotAreaFunc[comboCharts[idx].Type](comboCharts[idx]))
+		plotArea := plotAreaFunc[comboCharts[idx].Type](comboCharts[idx])
+		if comboCharts[idx].YAxis.Secondary {
+			f.drawPlotAreaSecondaryAxis(xlsxChartSpace.Chart.PlotArea, plotArea)
+		}
+		if pa := xlsxChartSpace.Chart.PlotArea; len(pa.CatAx) > 1 && len(pa.ValAx) > 1 {
+			plotArea.CatAx, plotArea.ValAx = pa.CatAx, pa.ValAx
+		}
+		addChart(xlsxChartSpace.Chart.PlotArea, plotArea)
 		order += len(comboCharts[idx].Series)
 	}
 	chart, _ := xml.Marshal(xlsxChartSpace)
@@ -262,6 +275,174 @@ func (f *File) addChart(opts *Chart, comboCharts []*Chart) {
 	f.saveFileList(media, chart)
 }
 
+// addChartEx provides a function to create the extended chart types as
+// xl/charts/chartEx%d.xml by given format sets and chart ID.
+func (f *File) addChartEx(opts *Chart, chartID int) {
+	chartSpace := xlsxChartExSpace{
+		XMLNSa:  NameSpaceDrawingML.Value,
+		XMLNSr:  SourceRelationship.Value,
+		XMLNScx: NameSpaceChartEx,
+	}
+	if strings.TrimSpace(opts.Title.Name) != "" {
+		chartSpace.Chart.Title = &cxTitle{
+			Pos: "t", Align: "ctr",
+			Tx: &cxTx{TxData: &cxTxData{V: opts.Title.Name}},
+		}
+	}
+	for idx, ser := range opts.Series {
+		chartSpace.ChartData.Data = append(chartSpace.ChartData.Data, f.drawChartExData(idx, ser, opts))
+		series := &cxSeries{
+			LayoutID:   chartExLayoutID[opts.Type],
+			DataLabels: f.drawChartExDataLabels(opts),
+			DataID:     &attrValInt{Val: intPtr(idx)},
+			LayoutPr:   f.drawChartExLayoutPr(ser, opts),
+		}
+		if ser.Name != "" {
+			series.Tx = &cxTx{TxData: &cxTxData{F: &cxF{Content: ser.Name}}}
+		}
+		region := &chartSpace.Chart.PlotArea.PlotAreaRegion
+		if opts.Type != Pareto {
+			region.Series = append(region.Series, series)
+			continue
+		}
+		series.AxisID = []*attrValInt{{Val: intPtr(1)}}
+		region.Series = append(region.Series, series, &cxSeries{
+			LayoutID: "paretoLine",
+			OwnerIdx: intPtr(len(region.Series)),
+			AxisID:   []*attrValInt{{Val: intPtr(2)}},
+		})
+	}
+	chartSpace.Chart.PlotArea.Axis = f.drawChartExAxis(opts)
+	if opts.Legend.Position != "none" {
+		pos := chartLegendPosition[opts.Legend.Position]
+		if pos == "tr" {
+			pos = "r"
+		}
+		chartSpace.Chart.Legend = &cxLegend{Pos: pos, Align: "ctr"}
+	}
+	chart, _ := xml.Marshal(chartSpace)
+	f.saveFileList("xl/charts/chartEx"+strconv.Itoa(chartID)+".xml", chart)
+}
+
+// drawChartExData provides a function to draw the cx:data element by given
+// data index, chart series and format sets.
+func (f *File) drawChartExData(idx int, ser ChartSeries, opts *Chart) *cxData {
+	data := &cxData{ID: idx}
+	if ser.Categories != "" {
+		cat := &cxDim{Type: "cat", F: &cxF{Content: ser.Categories}}
+		if opts.Type == Treemap || opts.Type == Sunburst {
+			cat.F.Dir = "row"
+		}
+		data.StrDim = append(data.StrDim, cat)
+	}
+	numDimType := "val"
+	if opts.Type == Treemap || opts.Type == Sunburst {
+		numDimType = "size"
+	}
+	data.NumDim = append(data.NumDim, &cxDim{Type: numDimType, F: &cxF{Content: ser.Values}})
+	return data
+}
+
+// drawChartExDataLabels provides a function to draw the cx:dataLabels
+// element by given format sets.
+func (f *File) drawChartExDataLabels(opts *Chart) *cxDataLabels {
+	if !opts.PlotArea.ShowVal && !opts.PlotArea.ShowCatName && !opts.PlotArea.ShowSerName {
+		return nil
+	}
+	dataLabels := &cxDataLabels{
+		Visibility: &cxDataLabelVisibility{
+			SeriesName:   opts.PlotArea.ShowSerName,
+			CategoryName: opts.PlotArea.ShowCatName,
+			Value:        opts.PlotArea.ShowVal,
+		},
+	}
+	if opts.Type == Waterfall {
+		dataLabels.Pos = "outEnd"
+	}
+	return dataLabels
+}
+
+// drawChartExLayoutPr provides a function to draw the cx:layoutPr element by
+// given chart series and format sets.
+func (f *File) drawChartExLayoutPr(ser ChartSeries, opts *Chart) *cxLayoutPr {
+	switch opts.Type {
+	case Waterfall:
+		if len(opts.Layout.Subtotals) == 0 {
+			return nil
+		}
+		subtotals := &cxSubtotals{}
+		for _, idx := range opts.Layout.Subtotals {
+			subtotals.Idx = append(subtotals.Idx, &attrValInt{Val: intPtr(idx)})
+		}
+		return &cxLayoutPr{Subtotals: subtotals}
+	case Treemap:
+		parentLabelLayout := opts.Layout.ParentLabelLayout
+		if parentLabelLayout == "" {
+			parentLabelLayout = "overlapping"
+		}
+		return &cxLayoutPr{ParentLabelLayout: &attrValString{Val: stringPtr(parentLabelLayout)}}
+	case Histogram, Pareto:
+		if ser.Categories != "" {
+			return &cxLayoutPr{Aggregation: &cxAggregation{}}
+		}
+		binning := &cxBinning{IntervalClosed: "r"}
+		if opts.Layout.BinSize > 0 {
+			binning.BinSize = &attrValFloat{Val: float64Ptr(opts.Layout.BinSize)}
+		} else if opts.Layout.BinCount > 0 {
+			binning.BinCount = &attrValInt{Val: intPtr(opts.Layout.BinCount)}
+		}
+		return &cxLayoutPr{Binning: binning}
+	case BoxWhisker:
+		quartileMethod := opts.Layout.QuartileMethod
+		if quartileMethod == "" {
+			quartileMethod = "exclusive"
+		}
+		return &cxLayoutPr{
+			Visibility: &cxSeriesVisibility{
+				MeanLine:    opts.Layout.ShowMeanLine,
+				MeanMarker:  opts.Layout.ShowMeanMarker == nil || *opts.Layout.ShowMeanMarker,
+				Nonoutliers: opts.Layout.ShowInnerPoints,
+				Outliers:    opts.Layout.ShowOutliers == nil || *opts.Layout.ShowOutliers,
+			},
+			Statistics: &cxStatistics{QuartileMethod: quartileMethod},
+		}
+	}
+	return nil
+}
+
+// drawChartExAxis provides a function to draw the cx:axis elements by given
+// format sets. The treemap and sunburst chart have no axes, and the funnel
+// chart has only the category axis.
+func (f *File) drawChartExAxis(opts *Chart) []*cxAxis {
+	gapWidth := map[string]string{Waterfall: "0.5", Funnel: "0.06", Histogram: "0", Pareto: "0", BoxWhisker: "1"}
+	if _, ok := gapWidth[opts.Type]; !ok {
+		return nil
+	}
+	axis := []*cxAxis{{
+		ID:         0,
+		Hidden:     opts.XAxis.None,
+		CatScaling: &cxCatScaling{GapWidth: gapWidth[opts.Type]},
+		TickLabels: &cxTickLabels{},
+	}}
+	if opts.Type == Funnel {
+		return axis
+	}
+	valAxis := &cxAxis{ID: 1, Hidden: opts.YAxis.None, ValScaling: &cxValScaling{}, TickLabels: &cxTickLabels{}}
+	if opts.YAxis.MajorGridLines {
+		valAxis.MajorGridlines = &cxGridlines{}
+	}
+	axis = append(axis, valAxis)
+	if opts.Type == Pareto {
+		axis = append(axis, &cxAxis{
+			ID:         2,
+			ValScaling: &cxValScaling{Max: "1", Min: "0"},
+			Units:      &cxUnits{Unit: "percentage"},
+			TickLabels: &cxTickLabels{},
+		})
+	}
+	return axis
+}
+
 // drawBaseChart provides a function to draw the c:plotArea element for bar,
 // and column series charts by given format sets.
 func (f *File) drawBaseChart(opts *Chart) *cPlotArea {
@@ -774,8 +955,9 @@ func (f *File) drawChartSeries(opts *Chart) *[]cSer {
 			SpPr:             f.drawChartSeriesSpPr(k, opts),
 			Marker:           f.drawChartSeriesMarker(k, opts),
 			DPt:              f.drawChartSeriesDPt(k, opts),
-			DLbls:            f.drawChartSeriesDLbls(opts),
+			DLbls:            f.drawChartSeriesDLbls(k, opts),
 			InvertIfNegative: &attrValBool{Val: boolPtr(false)},
+			ErrBars:          f.drawChartSeriesErrBars(opts.Series[k], opts),
 			Cat:              f.drawChartSeriesCat(opts.Series[k], opts),
 			Smooth:           &attrValBool{Val: boolPtr(opts.Series[k].Line.Smooth)},
 			Val:              f.drawChartSeriesVal(opts.Series[k], opts),
@@ -783,6 +965,7 @@ func (f *File) drawChartSeries(opts *Chart) *[]cSer {
 			YVal:             f.drawChartSeriesYVal(opts.Series[k], opts),
 			BubbleSize:       f.drawCharSeriesBubbleSize(opts.Series[k], opts),
 			Bubble3D:         f.drawCharSeriesBubble3D(opts),
+			ExtLst:           f.drawChartSeriesExtLst(opts.Series[k]),
 		})
 	}
 	return &ser
@@ -821,6 +1004,49 @@ func (f *File) drawChartSeriesSpPr(i int, opts *Chart) *cSpPr {
 	return chartSeriesSpPr[opts.Type]
 }
 
+// drawChartSeriesErrBars provides a function to draw the c:errBars element
+// by given chart series and format sets.
+func (f *File) drawChartSeriesErrBars(v ChartSeries, opts *Chart) []*cErrBars {
+	var errBars []*cErrBars
+	if !chartErrorBarsSupported[opts.Type] {
+		return errBars
+	}
+	for idx, errBarsOpts := range []ChartErrorBars{v.XErrorBars, v.ErrorBars} {
+		errDir := []string{"x", "y"}[idx]
+		if errBarsOpts.Type == "" || (errDir == "x" && opts.Type != Scatter && opts.Type != Bubble) {
+			continue
+		}
+		direction := errBarsOpts.Direction
+		if direction == "" {
+			direction = "both"
+		}
+		errBar := &cErrBars{
+			ErrBarType: &attrValString{Val: stringPtr(direction)},
+			ErrValType: &attrValString{Val: stringPtr(chartErrorBarsValType[errBarsOpts.Type])},
+			NoEndCap:   &attrValBool{Val: boolPtr(errBarsOpts.NoEndCap)},
+		}
+		if opts.Type == Scatter || opts.Type == Bubble {
+			errBar.ErrDir = &attrValString{Val: stringPtr(errDir)}
+		}
+		if errBarsOpts.Type == "custom" {
+			errBar.Plus = &cVal{NumRef: &cNumRef{F: errBarsOpts.Plus}}
+			errBar.Minus = &cVal{NumRef: &cNumRef{F: errBarsOpts.Minus}}
+		} else if errBarsOpts.Type != "stdErr" {
+			errBar.Val = &attrValFloat{Val: float64Ptr(errBarsOpts.Value)}
+		}
+		if errBarsOpts.Line.Color != "" || errBarsOpts.Line.Width != 0 {
+			errBar.SpPr = &cSpPr{Ln: &aLn{W: f.ptToEMUs(errBarsOpts.Line.Width)}}
+			if errBarsOpts.Line.Color != "" {
+				errBar.SpPr.Ln.SolidFill = &aSolidFill{
+					SrgbClr: &attrValString{Val: stringPtr(strings.TrimPrefix(errBarsOpts.Line.Color, "#"))},
+				}
+			}
+		}
+		errBars = append(errBars, errBar)
+	}
+	return errBars
+}
+
 // drawChartSeriesDPt provides a function to draw the c:dPt element by given
 // data index and format sets.
 func (f *File) drawChartSeriesDPt(i int, opts *Chart) []*cDPt {
@@ -853,26 +1079,46 @@ func (f *File) drawChartSeriesDPt(i int, opts *Chart) []*cDPt {
 // drawChartSeriesCat provides a function to draw the c:cat element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesCat(v ChartSeries, opts *Chart) *cCat {
-	cat := &cCat{
-		StrRef: &cStrRef{
-			F: v.Categories,
-		},
-	}
+	cat := f.drawChartSeriesCatData(v)
 	chartSeriesCat := map[string]*cCat{Scatter: nil, Bubble: nil, Bubble3D: nil}
-	if _, ok := chartSeriesCat[opts.Type]; ok || v.Categories == "" {
+	if _, ok := chartSeriesCat[opts.Type]; ok || (v.Categories == "" && len(v.CategoriesLiteral) == 0) {
 		return nil
 	}
 	return cat
 }
 
+// drawChartSeriesCatData provides a function to draw the category data of
+// the series. The string literals will be used if the categories reference of
+// the series is empty.
+func (f *File) drawChartSeriesCatData(v ChartSeries) *cCat {
+	if v.Categories != "" || len(v.CategoriesLiteral) == 0 {
+		return &cCat{StrRef: &cStrRef{F: v.Categories}}
+	}
+	lit := &cStrLit{PtCount: &attrValInt{Val: intPtr(len(v.CategoriesLiteral))}}
+	for i, category := range v.CategoriesLiteral {
+		lit.Pt = append(lit.Pt, &cPt{IDx: i, V: stringPtr(category)})
+	}
+	return &cCat{StrLit: lit}
+}
+
+// drawChartSeriesValData provides a function to draw the values data of the
+// series. The number literals will be used if the values reference of the
+// series is empty.
+func (f *File) drawChartSeriesValData(v ChartSeries) *cVal {
+	if v.Values != "" || len(v.ValuesLiteral) == 0 {
+		return &cVal{NumRef: &cNumRef{F: v.Values}}
+	}
+	lit := &cNumLit{FormatCode: "General", PtCount: &attrValInt{Val: intPtr(len(v.ValuesLiteral))}}
+	for i, value := range v.ValuesLiteral {
+		lit.Pt = append(lit.Pt, &cPt{IDx: i, V: stringPtr(strconv.FormatFloat(value, 'f', -1, 64))})
+	}
+	return &cVal{NumLit: lit}
+}
+
 // drawChartSeriesVal provides a function to draw the c:val element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesVal(v ChartSeries, opts *Chart) *cVal {
-	val := &cVal{
-		NumRef: &cNumRef{
-			F: v.Values,
-		},
-	}
+	val := f.drawChartSeriesValData(v)
 	chartSeriesVal := map[string]*cVal{Scatter: nil, Bubble: nil, Bubble3D: nil}
 	if _, ok := chartSeriesVal[opts.Type]; ok {
 		return nil
@@ -918,11 +1164,7 @@ func (f *File) drawChartSeriesMarker(i int, opts *Chart) *cMarker {
 // drawChartSeriesXVal provides a function to draw the c:xVal element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesXVal(v ChartSeries, opts *Chart) *cCat {
-	cat := &cCat{
-		StrRef: &cStrRef{
-			F: v.Categories,
-		},
-	}
+	cat := f.drawChartSeriesCatData(v)
 	chartSeriesXVal := map[string]*cCat{Scatter: cat}
 	return chartSeriesXVal[opts.Type]
 }
@@ -930,11 +1172,7 @@ func (f *File) drawChartSeriesXVal(v ChartSeries, opts *Chart) *cCat {
 // drawChartSeriesYVal provides a function to draw the c:yVal element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesYVal(v ChartSeries, opts *Chart) *cVal {
-	val := &cVal{
-		NumRef: &cNumRef{
-			F: v.Values,
-		},
-	}
+	val := f.drawChartSeriesValData(v)
 	chartSeriesYVal := map[string]*cVal{Scatter: val, Bubble: val, Bubble3D: val}
 	return chartSeriesYVal[opts.Type]
 }
@@ -945,11 +1183,7 @@ func (f *File) drawCharSeriesBubbleSize(v ChartSeries, opts *Chart) *cVal {
 	if _, ok := map[string]bool{Bubble: true, Bubble3D: true}[opts.Type]; !ok {
 		return nil
 	}
-	return &cVal{
-		NumRef: &cNumRef{
-			F: v.Values,
-		},
-	}
+	return f.drawChartSeriesValData(v)
 }
 
 // drawCharSeriesBubble3D provides a function to draw the c:bubble3D element
@@ -976,18 +1210,90 @@ func (f *File) drawChartDLbls(opts *Chart) *cDLbls {
 }
 
 // drawChartSeriesDLbls provides a function to draw the c:dLbls element by
-// given format sets.
-func (f *File) drawChartSeriesDLbls(opts *Chart) *cDLbls {
+// given series index and format sets. The data labels of the series will be
+// used if it has been specified, otherwise the data labels of the plot area
+// will be used.
+func (f *File) drawChartSeriesDLbls(i int, opts *Chart) *cDLbls {
+	ser := opts.Series[i]
+	if _, ok := map[string]bool{Surface3D: true, WireframeSurface3D: true, Contour: true, WireframeContour: true}[opts.Type]; ok {
+		return nil
+	}
+	if ser.DataLabel == nil && len(ser.DataPointLabels) == 0 {
+		if _, ok := map[string]bool{Scatter: true, Bubble: true, Bubble3D: true}[opts.Type]; ok {
+			return nil
+		}
+		return f.drawChartDLbls(opts)
+	}
 	dLbls := f.drawChartDLbls(opts)
-	chartSeriesDLbls := map[string]*cDLbls{
-		Scatter: nil, Surface3D: nil, WireframeSurface3D: nil, Contour: nil, WireframeContour: nil, Bubble: nil, Bubble3D: nil,
+	showCellRange := ser.DataLabel != nil && ser.DataLabel.CellRange != ""
+	if ser.DataLabel != nil {
+		dLbl := f.drawChartDLbl(*ser.DataLabel, showCellRange)
+		dLbls = &cDLbls{
+			NumFmt:          dLbl.NumFmt,
+			DLblPos:         dLbl.DLblPos,
+			ShowLegendKey:   dLbl.ShowLegendKey,
+			ShowVal:         dLbl.ShowVal,
+			ShowCatName:     dLbl.ShowCatName,
+			ShowSerName:     dLbl.ShowSerName,
+			ShowPercent:     dLbl.ShowPercent,
+			ShowBubbleSize:  dLbl.ShowBubbleSize,
+			Separator:       dLbl.Separator,
+			ShowLeaderLines: &attrValBool{Val: boolPtr(ser.DataLabel.ShowLeaderLines)},
+			ExtLst:          dLbl.ExtLst,
+		}
 	}
-	if _, ok := chartSeriesDLbls[opts.Type]; ok {
-		return nil
+	for _, pt := range ser.DataPointLabels {
+		dLbl := f.drawChartDLbl(pt.Label, showCellRange)
+		dLbl.IDx = &attrValInt{Val: intPtr(pt.Index)}
+		dLbls.DLbl = append(dLbls.DLbl, dLbl)
 	}
 	return dLbls
 }
 
+// drawChartDLbl provides a function to draw the c:dLbl element by given data
+// label format sets, the values from the cell range will be shown in the data
+// label if the showCellRange is true.
+func (f *File) drawChartDLbl(opts ChartDataLabel, showCellRange bool) *cDLbl {
+	dLbl := &cDLbl{
+		ShowLegendKey:  &attrValBool{Val: boolPtr(opts.ShowLegendKey)},
+		ShowVal:        &attrValBool{Val: boolPtr(opts.ShowVal)},
+		ShowCatName:    &attrValBool{Val: boolPtr(opts.ShowCatName)},
+		ShowSerName:    &attrValBool{Val: boolPtr(opts.ShowSerName)},
+		ShowPercent:    &attrValBool{Val: boolPtr(opts.ShowPercent)},
+		ShowBubbleSize: &attrValBool{Val: boolPtr(opts.ShowBubbleSize)},
+	}
+	if pos, ok := chartDataLabelPosition[opts.Position]; ok {
+		dLbl.DLblPos = &attrValString{Val: stringPtr(pos)}
+	}
+	if opts.NumFmt.CustomNumFmt != "" {
+		dLbl.NumFmt = &cNumFmt{FormatCode: opts.NumFmt.CustomNumFmt, SourceLinked: opts.NumFmt.SourceLinked}
+	}
+	if opts.Separator != "" {
+		dLbl.Separator = stringPtr(opts.Separator)
+	}
+	if showCellRange {
+		dLbl.ExtLst = &xlsxExtLst{
+			Ext: `<ext uri="` + ExtURIChartShowDataLabelsRange + `" xmlns:c15="` + SourceRelationshipChart2012.Value +
+				`"><c15:showDataLabelsRange val="1"/></ext>`,
+		}
+	}
+	return dLbl
+}
+
+// drawChartSeriesExtLst provides a function to draw the c:extLst element of
+// the series by given chart series, which contains the cell range of the
+// values shown in the data labels.
+func (f *File) drawChartSeriesExtLst(ser ChartSeries) *xlsxExtLst {
+	if ser.DataLabel == nil || ser.DataLabel.CellRange == "" {
+		return nil
+	}
+	dataLabelsRange, _ := xml.Marshal(c15DataLabelsRange{F: ser.DataLabel.CellRange})
+	return &xlsxExtLst{
+		Ext: `<ext uri="` + ExtURIChartDataLabelsRange + `" xmlns:c15="` + SourceRelationshipChart2012.Value +
+			`">` + string(dataLabelsRange) + `</ext>`,
+	}
+}
+
 // drawPlotAreaCatAx provides a function to draw the c:catAx element.
 func (f *File) drawPlotAreaCatAx(opts *Chart) []*cAxs {
 	max := &attrValFloat{Val: opts.XAxis.Maximum}
@@ -1034,6 +1340,10 @@ func (f *File) drawPlotAreaCatAx(opts *Chart) []*cAxs {
 	if opts.XAxis.TickLabelSkip != 0 {
 		axs[0].TickLblSkip = &attrValInt{Val: intPtr(opts.XAxis.TickLabelSkip)}
 	}
+	axs[0].Title = f.drawPlotAreaTitle(&opts.XAxis, 0)
+	if opts.XAxis.NumFmt.CustomNumFmt != "" {
+		axs[0].NumFmt = &cNumFmt{FormatCode: opts.XAxis.NumFmt.CustomNumFmt, SourceLinked: opts.XAxis.NumFmt.SourceLinked}
+	}
 	return axs
 }
 
@@ -1088,9 +1398,59 @@ func (f *File) drawPlotAreaValAx(opts *Chart) []*cAxs {
 	if opts.YAxis.MajorUnit != 0 {
 		axs[0].MajorUnit = &attrValFloat{Val: float64Ptr(opts.YAxis.MajorUnit)}
 	}
+	axs[0].Title = f.drawPlotAreaTitle(&opts.YAxis, -5400000)
+	if opts.YAxis.NumFmt.CustomNumFmt != "" {
+		axs[0].NumFmt = &cNumFmt{FormatCode: opts.YAxis.NumFmt.CustomNumFmt, SourceLinked: opts.YAxis.NumFmt.SourceLinked}
+	}
 	return axs
 }
 
+// drawPlotAreaTitle provides a function to draw the c:title element of the
+// axis by given axis format sets and text rotation.
+func (f *File) drawPlotAreaTitle(opts *ChartAxis, rot int) *cTitle {
+	if opts.Title.Name == "" {
+		return nil
+	}
+	return &cTitle{
+		Tx: cTx{
+			Rich: &cRich{
+				BodyPr: aBodyPr{Rot: rot, Vert: "horz"},
+				P: aP{
+					PPr: &aPPr{DefRPr: aRPr{Kern: 1200, Sz: 1000, B: false}},
+					R: []*aR{
+						{
+							RPr: aRPr{Lang: "en-US", AltLang: "en-US"},
+							T:   opts.Title.Name,
+						},
+					},
+				},
+			},
+		},
+		Overlay: &attrValBool{Val: boolPtr(false)},
+	}
+}
+
+// drawPlotAreaSecondaryAxis provides a function to bind the chart group of
+// the combo chart to the secondary axis, the secondary category axis and
+// value axis will be appended to the plot area of the primary chart once.
+func (f *File) drawPlotAreaSecondaryAxis(c, p *cPlotArea) {
+	mutable := reflect.ValueOf(p).Elem()
+	for i := 0; i < mutable.NumField(); i++ {
+		if group, ok := mutable.Field(i).Interface().(*cCharts); ok && group != nil {
+			group.AxID = []*attrValInt{{Val: intPtr(754001153)}, {Val: intPtr(753999905)}}
+		}
+	}
+	if len(c.ValAx) > 1 || len(p.CatAx) == 0 || len(p.ValAx) == 0 {
+		return
+	}
+	catAx, valAx := *p.CatAx[0], *p.ValAx[0]
+	catAx.AxID, catAx.CrossAx = &attrValInt{Val: intPtr(754001153)}, &attrValInt{Val: intPtr(753999905)}
+	catAx.Delete, catAx.MajorGridlines, catAx.MinorGridlines, catAx.Title = &attrValBool{Val: boolPtr(true)}, nil, nil, nil
+	valAx.AxID, valAx.CrossAx = &attrValInt{Val: intPtr(753999905)}, &attrValInt{Val: intPtr(754001153)}
+	valAx.AxPos, valAx.Crosses = &attrValString{Val: stringPtr("r")}, &attrValString{Val: stringPtr("max")}
+	c.CatAx, c.ValAx = append(c.CatAx, &catAx), append(c.ValAx, &valAx)
+}
+
 // drawPlotAreaSerAx provides a function to draw the c:serAx element.
 func (f *File) drawPlotAreaSerAx(opts *Chart) []*cAxs {
 	max := &attrValFloat{Val: opts.YAxis.Maximum}
@@ -1216,6 +1576,12 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int, error) {
 					XMLNSMC: SourceRelationshipCompatibility.Value,
 				})
 			}
+			for _, v := range decodeWsDr.AbsoluteAnchor {
+				content.AbsoluteAnchor = append(content.AbsoluteAnchor, &xdrCellAnchor{
+					EditAs:       v.EditAs,
+					GraphicFrame: v.Content,
+				})
+			}
 			for _, v := range decodeWsDr.OneCellAnchor {
 				content.OneCellAnchor = append(content.OneCellAnchor, &xdrCellAnchor{
 					EditAs:       v.EditAs,
@@ -1237,12 +1603,12 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int, error) {
 	}
 	wsDr.Lock()
 	defer wsDr.Unlock()
-	return wsDr, len(wsDr.OneCellAnchor) + len(wsDr.TwoCellAnchor) + 2, nil
+	return wsDr, len(wsDr.AbsoluteAnchor) + len(wsDr.OneCellAnchor) + len(wsDr.TwoCellAnchor) + 2, nil
 }
 
 // addDrawingChart provides a function to add chart graphic frame by given
-// sheet, drawingXML, cell, width, height, relationship index and format sets.
-func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rID int, opts *GraphicOptions) error {
+// sheet, drawingXML, cell, relationship index and format sets.
+func (f *File) addDrawingChart(sheet, drawingXML, cell string, rID int, opts *Chart) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -1250,20 +1616,20 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 	colIdx := col - 1
 	rowIdx := row - 1
 	
-	width = int(float64(width) * opts.ScaleX)
-	height = int(float64(height) * opts.ScaleY)
-	colStart, rowStart, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, colIdx, rowIdx, opts.OffsetX, opts.OffsetY, width, height)
+	width := int(float64(opts.Dimension.Width) * opts.Format.ScaleX)
+	height := int(float64(opts.Dimension.Height) * opts.Format.ScaleY)
+	colStart, rowStart, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, colIdx, rowIdx, opts.Format.OffsetX, opts.Format.OffsetY, width, height)
 	content, cNvPrID, err := f.drawingParser(drawingXML)
 	if err != nil {
 		return err
 	}
 	twoCellAnchor := xdrCellAnchor{}
-	twoCellAnchor.EditAs = opts.Positioning
+	twoCellAnchor.EditAs = opts.Format.Positioning
 	from := xlsxFrom{}
 	from.Col = colStart
-	from.ColOff = opts.OffsetX * EMU
+	from.ColOff = opts.Format.OffsetX * EMU
 	from.Row = rowStart
-	from.RowOff = opts.OffsetY * EMU
+	from.RowOff = opts.Format.OffsetY * EMU
 	to := xlsxTo{}
 	to.Col = colEnd
 	to.ColOff = x2 * EMU
@@ -1271,30 +1637,10 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 	to.RowOff = y2 * EMU
 	twoCellAnchor.From = &from
 	twoCellAnchor.To = &to
-	
-	graphicFrame := xlsxGraphicFrame{
-		NvGraphicFramePr: xlsxNvGraphicFramePr{
-			CNvPr: &xlsxCNvPr{
-				ID:   cNvPrID,
-				Name: "Chart " + strconv.Itoa(cNvPrID),
-			},
-		},
-		Graphic: &xlsxGraphic{
-			GraphicData: &xlsxGraphicData{
-				URI: NameSpaceDrawingMLChart.Value,
-				Chart: &xlsxChart{
-					C:   NameSpaceDrawingMLChart.Value,
-					R:   SourceRelationship.Value,
-					RID: "rId" + strconv.Itoa(rID),
-				},
-			},
-		},
-	}
-	graphic, _ := xml.Marshal(graphicFrame)
-	twoCellAnchor.GraphicFrame = string(graphic)
+	twoCellAnchor.GraphicFrame = f.drawChartGraphicFrame(cNvPrID, rID, opts)
 	twoCellAnchor.ClientData = &xdrClientData{
-		FLocksWithSheet:  *opts.Locked,
-		FPrintsWithSheet: *opts.PrintObject,
+		FLocksWithSheet:  *opts.Format.Locked,
+		FPrintsWithSheet: *opts.Format.PrintObject,
 	}
 	content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
 	f.Drawings.Store(drawingXML, content)
@@ -1302,24 +1648,42 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 }
 
 // addSheetDrawingChart provides a function to add chart graphic frame for
-// chartsheet by given sheet, drawingXML, width, height, relationship index
-// and format sets.
-func (f *File) addSheetDrawingChart(drawingXML string, rID int, opts *GraphicOptions) error {
+// chartsheet by given drawingXML, relationship index and format sets.
+func (f *File) addSheetDrawingChart(drawingXML string, rID int, opts *Chart) error {
 	content, cNvPrID, err := f.drawingParser(drawingXML)
 	if err != nil {
 		return err
 	}
 	absoluteAnchor := xdrCellAnchor{
-		EditAs: opts.Positioning,
+		EditAs: opts.Format.Positioning,
 		Pos:    &xlsxPoint2D{},
 		Ext:    &xlsxExt{},
 	}
-	
+	absoluteAnchor.GraphicFrame = f.drawChartGraphicFrame(cNvPrID, rID, opts)
+	absoluteAnchor.ClientData = &xdrClientData{
+		FLocksWithSheet:  *opts.Format.Locked,
+		FPrintsWithSheet: *opts.Format.PrintObject,
+	}
+	content.AbsoluteAnchor = append(content.AbsoluteAnchor, &absoluteAnchor)
+	f.Drawings.Store(drawingXML, content)
+	return err
+}
+
+// drawChartGraphicFrame provides a function to draw the graphic frame of the
+// chart by given non-visual drawing properties ID, relationship index and
+// format sets. The graphic frame of the extended chart types will be wrapped
+// in the alternate content with a fallback shape for the applications which
+// don't support these chart types.
+func (f *File) drawChartGraphicFrame(cNvPrID, rID int, opts *Chart) string {
+	name := opts.Name
+	if name == "" {
+		name = "Chart " + strconv.Itoa(cNvPrID)
+	}
 	graphicFrame := xlsxGraphicFrame{
 		NvGraphicFramePr: xlsxNvGraphicFramePr{
 			CNvPr: &xlsxCNvPr{
 				ID:   cNvPrID,
-				Name: "Chart " + strconv.Itoa(cNvPrID),
+				Name: name,
 			},
 		},
 		Graphic: &xlsxGraphic{
@@ -1333,15 +1697,23 @@ func (f *File) addSheetDrawingChart(drawingXML string, rID int, opts *GraphicOpt
 			},
 		},
 	}
-	graphic, _ := xml.Marshal(graphicFrame)
-	absoluteAnchor.GraphicFrame = string(graphic)
-	absoluteAnchor.ClientData = &xdrClientData{
-		FLocksWithSheet:  *opts.Locked,
-		FPrintsWithSheet: *opts.PrintObject,
+	requires, ok := chartExRequires[opts.Type]
+	if !ok {
+		graphic, _ := xml.Marshal(graphicFrame)
+		return string(graphic)
 	}
-	content.AbsoluteAnchor = append(content.AbsoluteAnchor, &absoluteAnchor)
-	f.Drawings.Store(drawingXML, content)
-	return err
+	graphicFrame.Graphic.GraphicData = &xlsxGraphicData{
+		URI: NameSpaceChartEx,
+		ChartEx: &xlsxChartEx{
+			Cx:  NameSpaceChartEx,
+			R:   SourceRelationship.Value,
+			RID: "rId" + strconv.Itoa(rID),
+		},
+	}
+	graphic, _ := xml.Marshal(graphicFrame)
+	return `<mc:AlternateContent xmlns:mc="` + SourceRelationshipCompatibility.Value + `"><mc:Choice xmlns:` +
+		requires.Name.Local + `="` + requires.Value + `" Requires="` + requires.Name.Local + `">` + string(graphic) +
+		`</mc:Choice><mc:Fallback>` + templateChartExFallback + `</mc:Fallback></mc:AlternateContent>`
 }
 
 // deleteDrawing provides a function to delete chart graphic frame by given by
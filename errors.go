@@ -14,6 +14,7 @@ package excel
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // newInvalidColumnNameError defined the error message on receiving the
@@ -22,6 +23,12 @@ func newInvalidColumnNameError(col string) error {
 	return fmt.Errorf("invalid column name %q", col)
 }
 
+// newDuplicateColumnNameError defined the error message on receiving the
+// duplicate column name.
+func newDuplicateColumnNameError(col string) error {
+	return fmt.Errorf("duplicate column name %q", col)
+}
+
 // newInvalidRowNumberError defined the error message on receiving the invalid
 // row number.
 func newInvalidRowNumberError(row int) error {
@@ -70,12 +77,103 @@ func newCellNameToCoordinatesError(cell string, err error) error {
 	return fmt.Errorf("cannot convert cell %q to coordinates: %v", cell, err)
 }
 
+// MergedCellsError defined the error on the range of the table or auto filter
+// contains merged cells, which are not allowed in the range. The MergedCells
+// specifies the range references of the merged cells which overlap the range.
+type MergedCellsError struct {
+	RangeRef    string
+	MergedCells []string
+}
+
+// Error returns the error message of the merged cells in the range.
+func (err *MergedCellsError) Error() string {
+	return fmt.Sprintf("range %s contains merged cells %s", err.RangeRef, strings.Join(err.MergedCells, ", "))
+}
+
 // newNoExistSheetError defined the error message on receiving the non existing
 // sheet name.
 func newNoExistSheetError(name string) error {
 	return fmt.Errorf("sheet %s does not exist", name)
 }
 
+// newNoExistPivotTableError defined the error message on receiving the non
+// existing pivot table name.
+func newNoExistPivotTableError(name string) error {
+	return fmt.Errorf("pivot table %s does not exist", name)
+}
+
+// newNoExistNamedStyleError defined the error message on receiving the non
+// existing named cell style name.
+func newNoExistNamedStyleError(name string) error {
+	return fmt.Errorf("cell style %s does not exist", name)
+}
+
+// newNoExistTableError defined the error message on receiving the non
+// existing table name.
+func newNoExistTableError(name string) error {
+	return fmt.Errorf("table %s does not exist", name)
+}
+
+// newNoExistChartError defined the error message on receiving the non
+// existing chart name.
+func newNoExistChartError(name string) error {
+	return fmt.Errorf("chart %s does not exist", name)
+}
+
+// newNoExistChartIndexError defined the error message on receiving the non
+// existing chart index.
+func newNoExistChartIndexError(index int) error {
+	return fmt.Errorf("chart with index %d does not exist", index)
+}
+
+// newTableOverlapError defined the error message on receiving the range which
+// overlaps the existing table.
+func newTableOverlapError(rangeRef, name string) error {
+	return fmt.Errorf("range %s overlaps the table %s", rangeRef, name)
+}
+
+// newNoExistTableStyleError defined the error message on receiving the non
+// existing table style name.
+func newNoExistTableStyleError(name string) error {
+	return fmt.Errorf("table style %s does not exist", name)
+}
+
+// newNoExistDataValidationError defined the error message on receiving the
+// non existing data validation range.
+func newNoExistDataValidationError(sqref string) error {
+	return fmt.Errorf("data validation on %s does not exist", sqref)
+}
+
+// newNotDateFieldError defined the error message on receiving the field of
+// the pivot table which not a date field.
+func newNotDateFieldError(name string) error {
+	return fmt.Errorf("field %s is not a date field of the pivot table", name)
+}
+
+// newInvalidDefinedNameError defined the error message on receiving the
+// defined name which does not match the naming rules of the defined names.
+func newInvalidDefinedNameError(name string) error {
+	return fmt.Errorf("invalid defined name %q", name)
+}
+
+// newUnsupportedStyleTableVersionError defined the error message on
+// receiving the unsupported version of the JSON form of the style table.
+func newUnsupportedStyleTableVersionError(version int) error {
+	return fmt.Errorf("unsupported style table version %d", version)
+}
+
+// newInvalidThemeColorError defined the error message on receiving the
+// invalid hex RGB color code of the theme color scheme.
+func newInvalidThemeColorError(name, color string) error {
+	return fmt.Errorf("invalid theme color %s value %q", name, color)
+}
+
+// newHiddenSheetError defined the error message on receiving a hidden sheet
+// which can't be printed.
+func newHiddenSheetError(name string) error {
+	return fmt.Errorf("sheet %s is hidden", name)
+}
+
 // newNotWorksheetError defined the error message on receiving a sheet which
 // not a worksheet.
 func newNotWorksheetError(name string) error {
@@ -94,13 +192,52 @@ func newViewIdxError(viewIndex int) error {
 	return fmt.Errorf("view index %d out of range", viewIndex)
 }
 
+// newInvalidOptionalValue defined the error message on receiving the invalid
+// optional value.
+func newInvalidOptionalValue(name, value string, values []string) error {
+	return fmt.Errorf("invalid %s value %q, acceptable value should be one of %s", name, value, strings.Join(values, ", "))
+}
+
+// newBuiltInFunctionError defined the error message on register the custom
+// formula function with the name of the built-in function.
+func newBuiltInFunctionError(name string) error {
+	return fmt.Errorf("function %s is a built-in function", name)
+}
+
+// newHTTPStatusError defined the error message on receive an unexpected HTTP
+// status code.
+func newHTTPStatusError(url, status string) error {
+	return fmt.Errorf("unexpected HTTP status %s on get %s", status, url)
+}
+
+// newAppendExistingPartError defined the error message on appending the part
+// which already exists in the spreadsheet.
+func newAppendExistingPartError(name string) error {
+	return fmt.Errorf("the part %s already exists in the spreadsheet", name)
+}
+
 var (
 	// ErrStreamSetColWidth defined the error message on set column width in
 	// stream writing mode.
+	//
+	// Deprecated: the column width could be set at any time before Flush in
+	// stream writing mode, this error will no longer be returned.
 	ErrStreamSetColWidth = errors.New("must call the SetColWidth function before the SetRow function")
 	// ErrStreamSetPanes defined the error message on set panes in stream
 	// writing mode.
+	//
+	// Deprecated: the panes could be set at any time before Flush in stream
+	// writing mode, this error will no longer be returned.
 	ErrStreamSetPanes = errors.New("must call the SetPanes function before the SetRow function")
+	// ErrStreamBufferSize defined the error message on receive an invalid
+	// buffer size for the stream writer.
+	ErrStreamBufferSize = errors.New("the buffer size of the stream writer must be greater than or equal to 0")
+	// ErrStreamSharedStringsCacheSize defined the error message on receive the
+	// invalid shared strings cache size for the stream writer.
+	ErrStreamSharedStringsCacheSize = errors.New("the shared strings cache size of the stream writer must be greater than or equal to 0")
+	// ErrStreamTableColumns defined the error message on receive the totals
+	// row or column settings of the table in stream writing mode.
+	ErrStreamTableColumns = errors.New("the totals row and column settings of the table are not supported in stream writing mode")
 	// ErrColumnNumber defined the error message on receive an invalid column
 	// number.
 	ErrColumnNumber = fmt.Errorf(`the column number must be greater than or equal to %d and less than or equal to %d`, MinColumns, MaxColumns)
@@ -115,6 +252,15 @@ var (
 	ErrCoordinates = errors.New("coordinates length must be 4")
 	// ErrExistsSheet defined the error message on given sheet already exists.
 	ErrExistsSheet = errors.New("the same name sheet already exists")
+	// ErrExistsNamedStyle defined the error message on given named cell style
+	// already exists.
+	ErrExistsNamedStyle = errors.New("the same name cell style already exists")
+	// ErrExistsTableStyle defined the error message on given table style
+	// already exists.
+	ErrExistsTableStyle = errors.New("the same name table style already exists")
+	// ErrTableTotalsRow defined the error message on the range of the table
+	// with the totals row less than three rows.
+	ErrTableTotalsRow = errors.New("the range of the table with totals row must be at least three rows including the header and the totals row")
 	// ErrTotalSheetHyperlinks defined the error message on hyperlinks count
 	// overflow.
 	ErrTotalSheetHyperlinks = errors.New("over maximum limit hyperlinks in a worksheet")
@@ -132,6 +278,9 @@ var (
 	// ErrImgExt defined the error message on receive an unsupported image
 	// extension.
 	ErrImgExt = errors.New("unsupported image extension")
+	// ErrPictureSize defined the error message on receive a picture which size
+	// exceeds the limit on adding the picture from URL.
+	ErrPictureSize = errors.New("the size of the picture exceeds limit")
 	// ErrWorkbookFileFormat defined the error message on receive an
 	// unsupported workbook file format.
 	ErrWorkbookFileFormat = errors.New("unsupported workbook file format")
@@ -195,6 +344,9 @@ var (
 	// ErrOptionsUnzipSizeLimit defined the error message for receiving
 	// invalid UnzipSizeLimit and UnzipXMLSizeLimit.
 	ErrOptionsUnzipSizeLimit = errors.New("the value of UnzipSizeLimit should be greater than or equal to UnzipXMLSizeLimit")
+	// ErrCompressionLevel defined the error message on receive an invalid
+	// compression level.
+	ErrCompressionLevel = fmt.Errorf("the compression level must be greater than or equal to %d and less than or equal to %d", CompressionNone, CompressionBestCompression)
 	// ErrSave defined the error message for saving file.
 	ErrSave = errors.New("no path defined for file, consider File.WriteTo or File.Write")
 	// ErrAttrValBool defined the error message on marshal and unmarshal
@@ -236,4 +388,10 @@ var (
 	// ErrUnprotectWorkbookPassword defined the error message on remove workbook
 	// protection with password verification failed.
 	ErrUnprotectWorkbookPassword = errors.New("workbook protect password not match")
+	// ErrPivotCacheSource defined the error message on refreshing the pivot
+	// cache which source is not a worksheet range.
+	ErrPivotCacheSource = errors.New("unsupported pivot cache source")
+	// ErrPivotGroupItems defined the error message on receiving the grouping
+	// settings of the pivot field which creates too many group items.
+	ErrPivotGroupItems = errors.New("the number of the pivot field group items exceeds limit")
 )
@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 	
 	"golang.org/x/net/html/charset"
 )
@@ -56,14 +57,98 @@ type File struct {
 	Relationships    sync.Map
 	Pkg              sync.Map
 	CharsetReader    charsetTranscoderFn
+	ExternalReader   externalWorkbookReaderFn
+	externalBooks    sync.Map
+	calcFuncs        sync.Map
+	fontMetrics      sync.Map
+	dirtyCells       sync.Map
+	trackDirtyCells  int32
+	formulaGraph     *formulaGraph
+	sheetDimensions  map[string]SheetDimension
+	closed           bool
+	tracker          *resourceTracker
 }
 
 // charsetTranscoderFn set user-defined codepage transcoder function for open
 // the spreadsheet from non-UTF-8 encoding.
 type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, err error)
 
+// externalWorkbookReaderFn set user-defined external workbook reader function
+// for resolving the external workbook references in the formulas.
+type externalWorkbookReaderFn func(workbook string) (*File, error)
+
 // Options define the options for open and reading spreadsheet.
 //
+// CalcBlankAsZero specifies if treat the blank cells as zero in the formula
+// calculation like Excel, the formula which references a blank cell, such as
+// =A1, will be calculated as 0, and the blank cell compared with a number
+// will be zero, such as =A1=0 will be TRUE. By default, the empty result will
+// be returned and the blank cell will be compared as an empty string.
+//
+// CalcClock specifies the clock function for the volatile formula functions
+// NOW and TODAY in the formula calculation, the current time will be used if
+// this option is not set. Set a fixed clock to make the calculation results
+// deterministic for testing and reproducible report generation.
+//
+// CalcRand specifies the random number generator function for the volatile
+// formula functions RAND and RANDBETWEEN in the formula calculation, the
+// function should return a pseudo-random number in the half-open interval
+// [0.0,1.0), a random number generator seeded by the current time will be
+// used if this option is not set. The functions return the #NUM! error if the
+// function returns a number out of the interval.
+//
+// CalcTextAsZero specifies if treat the text values which can't be converted
+// to numbers as zero in the arithmetic operations of the formula calculation,
+// such as ="abc"+1 will be calculated as 1. By default, the arithmetic
+// operations on the text values will be calculated as errors.
+//
+// CanonicalXML specifies if write the XML parts in the canonical form on
+// saving the spreadsheet, the attributes of each element will be sorted by
+// the name with the namespace declarations first, the empty elements will be
+// written as the self-closing tags, and the parts will be written in the
+// order of the part names, so the output is byte-stable for the same
+// workbook. The text content of the elements including the white spaces is
+// kept as it is, and no indentation will be added. Note that the XML parts
+// will be parsed in memory on saving with this option.
+//
+// ColumnLimit specifies the maximum number of columns to be read in each row
+// by GetRows and the rows iterator, the cells after the column will be
+// skipped, which avoids allocating the huge rows when there is a stray cell
+// at the far right of the worksheet. The default value 0 means no limit.
+//
+// Columns specifies the column names to be read in each row by GetRows and
+// the rows iterator, such as []string{"A", "C"}. The cell values will be
+// returned in the order of the given column names, and the other columns
+// will be skipped, the same column can't be specified more than once. All
+// columns will be read with the default value nil.
+//
+// CompressionLevel specifies the compression level of the parts on saving the
+// spreadsheet by SaveAs, Write and WriteTo, it accepts the levels from
+// CompressionBestSpeed (1) to CompressionBestCompression (9), the
+// CompressionNone (-1) stores the parts without compression, the default
+// value 0 use the default compression level.
+//
+// CompressionWorkers specifies the number of workers for compressing the
+// parts of the spreadsheet concurrently on saving, the default value 0 use the
+// value of GOMAXPROCS, and the value 1 disables the concurrent compression.
+// Note that the compressed parts waiting to be written are buffered, in memory
+// or the system temporary directory for the large parts, the concurrent
+// compression only works with Go 1.17 or later.
+//
+// CultureInfo specifies the country code for applying the language dependent
+// built-in number formats, such as the number format ID 27 to 36 and 50 to
+// 58, on getting the cell values. The cell values with these number formats
+// will be returned as they are with the default value CultureNameUnknown.
+//
+// Date1904 specifies if use the 1904 date system for the workbook created by
+// NewFile, the default value false use the 1900 date system.
+//
+// DefaultFont specifies the default font name of the workbook created by
+// NewFile.
+//
+// DefaultSheetName specifies the name of the default worksheet of the
+// workbook created by NewFile, the default value is Sheet1.
+//
 // MaxCalcIterations specifies the maximum iterations for iterative
 // calculation, the default value is 0.
 //
@@ -72,6 +157,26 @@ type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, e
 // RawCellValue specifies if apply the number format for the cell value or get
 // the raw value.
 //
+// SheetDimensions specifies if write the dimensions of the used cells of the
+// worksheets into the extension list of the workbook on saving the
+// spreadsheet, the GetSheetDimension function will use the dimensions on
+// reading the spreadsheet produced by this library without loading the
+// worksheets. The dimensions will be removed on saving the spreadsheet
+// without this option.
+//
+// SyncOnSave specifies if flush the written spreadsheet to the storage by
+// fsync before renaming the temporary file to the provided path on saving the
+// spreadsheet by Save and SaveAs, and flush the directory after renaming,
+// which makes sure the saved spreadsheet will not be lost or corrupted by the
+// system crash, but costs more time.
+//
+// StripPersonalInfo specifies if remove the personal information on saving
+// the spreadsheet, the creator and last modified by of the document core
+// properties and the authors of the comments will be removed, and the
+// workbook will be marked to filter the personal information on saving by
+// the spreadsheet application, which is like the document inspector of
+// Excel.
+//
 // UnzipSizeLimit specifies the unzip size limit in bytes on open the
 // spreadsheet, this value should be greater than or equal to
 // UnzipXMLSizeLimit, the default size limit is 16GB.
@@ -81,12 +186,39 @@ type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, e
 // temporary directory when the file size is over this value, this value
 // should be less than or equal to UnzipSizeLimit, the default value is
 // 16MB.
+//
+// WriteHook specifies the function which will be called with the statistics
+// of writing on saving the spreadsheet by Save, SaveAs, Write and WriteTo,
+// after each part of the spreadsheet has been written and after the
+// spreadsheet has been written completely, the worksheets generated by the
+// StreamWriter are written from the temporary files without loading into
+// memory. The statistics include the running SHA-256 checksum of the
+// written bytes and the estimated size of the spreadsheet, which could be
+// used to pre-allocate the storage and record the integrity hash without
+// reading the output again.
 type Options struct {
-	MaxCalcIterations uint
-	Password          string
-	RawCellValue      bool
-	UnzipSizeLimit    int64
-	UnzipXMLSizeLimit int64
+	CalcBlankAsZero    bool
+	CalcClock          func() time.Time
+	CalcRand           func() float64
+	CalcTextAsZero     bool
+	CanonicalXML       bool
+	ColumnLimit        int
+	Columns            []string
+	CompressionLevel   int
+	CompressionWorkers int
+	CultureInfo        CultureName
+	Date1904           bool
+	DefaultFont        string
+	DefaultSheetName   string
+	MaxCalcIterations  uint
+	Password           string
+	RawCellValue       bool
+	SheetDimensions    bool
+	StripPersonalInfo  bool
+	SyncOnSave         bool
+	UnzipSizeLimit     int64
+	UnzipXMLSizeLimit  int64
+	WriteHook          func(WriteStats)
 }
 
 // OpenFile take the name of an spreadsheet file and returns a populated
@@ -129,6 +261,7 @@ func newFile() *File {
 		VMLDrawing:       make(map[string]*vmlDrawing),
 		Relationships:    sync.Map{},
 		CharsetReader:    charset.NewReaderLabel,
+		tracker:          newResourceTracker("File"),
 	}
 }
 
@@ -154,7 +287,11 @@ func (f *File) checkOpenReaderOptions() error {
 }
 
 // OpenReader read data stream from io.Reader and return a populated
-// spreadsheet file.
+// spreadsheet file. The legacy binary workbook in the XLS (BIFF8) format is
+// also supported, the sheet names, cell values and number formats will be
+// read into a new workbook, and the formula cells will be read as the cached
+// values. The binary workbook in the XLSB (BIFF12) format will be read in
+// the same way, and the formulas of the cells will be read as well.
 func OpenReader(r io.Reader, opts ...Options) (*File, error) {
 	b, err := io.ReadAll(r)
 	if err != nil {
@@ -166,6 +303,9 @@ func OpenReader(r io.Reader, opts ...Options) (*File, error) {
 		return nil, err
 	}
 	if bytes.Contains(b, oleIdentifier) {
+		if stream, ok := extractXLSWorkbook(b); ok {
+			return openXLS(stream, f.options)
+		}
 		if b, err = Decrypt(b, f.options); err != nil {
 			return nil, ErrWorkbookFileFormat
 		}
@@ -185,12 +325,18 @@ func OpenReader(r io.Reader, opts ...Options) (*File, error) {
 	for k, v := range file {
 		f.Pkg.Store(k, v)
 	}
+	if _, ok := f.Pkg.Load(defaultXLSBPathWorkbook); ok {
+		return openXLSB(f)
+	}
 	if f.CalcChain, err = f.calcChainReader(); err != nil {
 		return f, err
 	}
 	if f.sheetMap, err = f.getSheetMap(); err != nil {
 		return f, err
 	}
+	if f.sheetDimensions, err = f.sheetDimensionsReader(); err != nil {
+		return f, err
+	}
 	if f.Styles, err = f.stylesReader(); err != nil {
 		return f, err
 	}
@@ -212,6 +358,30 @@ func parseOptions(opts ...Options) *Options {
 // XLSX from non UTF-8 encoding.
 func (f *File) CharsetTranscoder(fn charsetTranscoderFn) *File { f.CharsetReader = fn; return f }
 
+// ExternalWorkbookResolver set user-defined external workbook reader function
+// for calculating the formulas with external workbook references, such as
+// [Book2.xlsx]Sheet1!A1 or [1]Sheet1!A1. The function will be called with the
+// workbook name in the reference, or the target path of the external link
+// when the reference use the index of the external workbooks, and should
+// return the opened external workbook. The cached values of the external
+// workbook saved in the spreadsheet will be used if the reader function not
+// be set. For example, resolve the external workbooks in the directory of the
+// spreadsheet:
+//
+//	f.ExternalWorkbookResolver(func(workbook string) (*excelize.File, error) {
+//	    return excelize.OpenFile(filepath.Join("path", filepath.Base(workbook)))
+//	})
+//
+// The returned workbooks will be cached and reused for the following
+// calculations, and closed when the spreadsheet is closed or the resolver
+// function is changed, so the function only be called once for each external
+// workbook.
+func (f *File) ExternalWorkbookResolver(fn externalWorkbookReaderFn) *File {
+	_ = f.closeExternalWorkbooks()
+	f.ExternalReader = fn
+	return f
+}
+
 // Creates new XML decoder with charset reader.
 func (f *File) xmlNewDecoder(rdr io.Reader) (ret *xml.Decoder) {
 	ret = xml.NewDecoder(rdr)
@@ -14,6 +14,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"testing"
@@ -217,6 +218,93 @@ func TestSaveAsWrongPath(t *testing.T) {
 	assert.NoError(t, f.Close())
 }
 
+func TestSaveAsAtomic(t *testing.T) {
+	path := filepath.Join("test", "TestSaveAsAtomic.xlsx")
+	tempFiles := func() []string {
+		matches, err := filepath.Glob(filepath.Join("test", ".TestSaveAsAtomic.xlsx.*.tmp"))
+		assert.NoError(t, err)
+		return matches
+	}
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "A1"))
+	assert.NoError(t, f.SaveAs(path, Options{SyncOnSave: true}))
+	assert.Empty(t, tempFiles())
+	// Test save spreadsheet keeps the file mode of the existing spreadsheet
+	if runtime.GOOS != "windows" {
+		assert.NoError(t, os.Chmod(path, 0o600))
+		assert.NoError(t, f.SaveAs(path))
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	// Test save spreadsheet failed will not truncate the existing spreadsheet
+	assert.EqualError(t, f.SaveAs(path, Options{Password: strings.Repeat("*", MaxFieldLength+1)}), ErrPasswordLengthInvalid.Error())
+	assert.Empty(t, tempFiles())
+	result, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, content, result)
+	assert.NoError(t, f.Close())
+	// Test save spreadsheet to the path of the existing directory
+	f = NewFile()
+	path = filepath.Join("test", "TestSaveAsAtomic.xlsx.dir.xlsx")
+	assert.NoError(t, os.MkdirAll(path, os.ModePerm))
+	defer os.RemoveAll(path)
+	assert.Error(t, f.SaveAs(path))
+	matches, err := filepath.Glob(filepath.Join("test", ".TestSaveAsAtomic.xlsx.dir.xlsx.*.tmp"))
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+	assert.NoError(t, f.Close())
+	if runtime.GOOS == "windows" {
+		return
+	}
+	// Test save spreadsheet to the symbolic link keeps the link
+	f = NewFile()
+	target, link := filepath.Join("test", "TestSaveAsAtomicTarget.xlsx"), filepath.Join("test", "TestSaveAsAtomicLink.xlsx")
+	assert.NoError(t, f.SaveAs(target))
+	_ = os.Remove(link)
+	assert.NoError(t, os.Symlink(filepath.Base(target), link))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Link"))
+	assert.NoError(t, f.SaveAs(link, Options{SyncOnSave: true}))
+	info, err := os.Lstat(link)
+	assert.NoError(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeSymlink)
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(target)
+	assert.NoError(t, err)
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Link", value)
+	assert.NoError(t, f.Close())
+	assert.NoError(t, os.Remove(link))
+	// Test save spreadsheet into the writable file in the read-only directory
+	dir := filepath.Join("test", "TestSaveAsAtomicReadOnly")
+	assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	defer os.RemoveAll(dir)
+	path = filepath.Join(dir, "Book1.xlsx")
+	f = NewFile()
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, os.Chmod(dir, 0o555))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "ReadOnly"))
+	assert.NoError(t, f.SaveAs(path, Options{SyncOnSave: true}))
+	assert.NoError(t, f.Close())
+	assert.NoError(t, os.Chmod(dir, os.ModePerm))
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	value, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ReadOnly", value)
+	assert.NoError(t, f.Close())
+	// Test overwrite the spreadsheet without permission
+	if os.Geteuid() != 0 {
+		assert.NoError(t, os.Chmod(path, 0o444))
+		assert.Error(t, f.overwriteFile(path))
+	}
+	// Test flush the directory which doesn't exist
+	assert.Error(t, syncDir(filepath.Join("test", "TestSaveAsAtomicNotExist")))
+}
+
 func TestCharsetTranscoder(t *testing.T) {
 	f := NewFile()
 	f.CharsetTranscoder(*new(charsetTranscoderFn))
@@ -383,6 +471,42 @@ func TestNewFile(t *testing.T) {
 	assert.NoError(t, f.Save())
 }
 
+func TestNewFileWithOptions(t *testing.T) {
+	f := NewFile(Options{
+		Password:         "password",
+		CultureInfo:      CultureNameZhCN,
+		Date1904:         true,
+		DefaultFont:      "Arial",
+		DefaultSheetName: "Summary",
+	})
+	assert.Equal(t, []string{"Summary"}, f.GetSheetList())
+	fontName, err := f.GetDefaultFont()
+	assert.NoError(t, err)
+	assert.Equal(t, "Arial", fontName)
+	assert.NoError(t, f.SetCellValue("Summary", "A1", time.Date(2019, 3, 4, 0, 0, 0, 0, time.UTC)))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestNewFileWithOptions.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test open the workbook created with password
+	_, err = OpenFile(filepath.Join("test", "TestNewFileWithOptions.xlsx"))
+	assert.EqualError(t, err, zip.ErrFormat.Error())
+	f, err = OpenFile(filepath.Join("test", "TestNewFileWithOptions.xlsx"), Options{Password: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Summary"}, f.GetSheetList())
+	props, err := f.GetWorkbookProps()
+	assert.NoError(t, err)
+	assert.True(t, *props.Date1904)
+	val, err := f.GetCellValue("Summary", "A1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "42066", val)
+	assert.NoError(t, f.Close())
+
+	// Test create workbook with invalid default sheet name
+	f = NewFile(Options{DefaultSheetName: "Sheet:1"})
+	assert.Equal(t, []string{"Sheet1"}, f.GetSheetList())
+	assert.NoError(t, f.Close())
+}
+
 func TestAddDrawingVML(t *testing.T) {
 	// Test addDrawingVML with illegal cell reference
 	f := NewFile()
@@ -750,10 +874,10 @@ func TestSetCellStyleNumberFormat(t *testing.T) {
 	data := []int{0, 1, 2, 3, 4, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49}
 	value := []string{"37947.7500001", "-37947.7500001", "0.007", "2.1", "String"}
 	expected := [][]string{
-		{"37947.7500001", "37948", "37947.75", "37,948", "37947.75", "3794775%", "3794775.00%", "3.79E+04", "37947.7500001", "37947.7500001", "11-22-03", "22-Nov-03", "22-Nov", "Nov-03", "6:00 pm", "6:00:00 pm", "18:00", "18:00:00", "11/22/03 18:00", "37,948 ", "37,948 ", "37,947.75 ", "37,947.75 ", "37947.7500001", "37947.7500001", "37947.7500001", "37947.7500001", "00:00", "910746:00:00", "37947.7500001", "3.79E+04", "37947.7500001"},
-		{"-37947.7500001", "-37948", "-37947.75", "-37,948", "-37947.75", "-3794775%", "-3794775.00%", "-3.79E+04", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "(37,948)", "(37,948)", "(37,947.75)", "(37,947.75)", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-3.79E+04", "-37947.7500001"},
-		{"0.007", "0", "0.01", "0", "0.01", "1%", "0.70%", "7.00E-03", "0.007", "0.007", "12-30-99", "30-Dec-99", "30-Dec", "Dec-99", "0:10 am", "0:10:04 am", "00:10", "00:10:04", "12/30/99 00:10", "0 ", "0 ", "0.01 ", "0.01 ", "0.007", "0.007", "0.007", "0.007", "10:04", "0:10:04", "0.007", "7.00E-03", "0.007"},
-		{"2.1", "2", "2.10", "2", "2.10", "210%", "210.00%", "2.10E+00", "2.1", "2.1", "01-01-00", "1-Jan-00", "1-Jan", "Jan-00", "2:24 am", "2:24:00 am", "02:24", "02:24:00", "1/1/00 02:24", "2 ", "2 ", "2.10 ", "2.10 ", "2.1", "2.1", "2.1", "2.1", "24:00", "50:24:00", "2.1", "2.10E+00", "2.1"},
+		{"37947.7500001", "37948", "37947.75", "37,948", "37947.75", "3794775%", "3794775.00%", "3.79E+04", "37947 3/4", "37947  3/4 ", "11-22-03", "22-Nov-03", "22-Nov", "Nov-03", "6:00 pm", "6:00:00 pm", "18:00", "18:00:00", "11/22/03 18:00", "37,948 ", "37,948 ", "37,947.75 ", "37,947.75 ", "37,948", "$37,948", "37,947.75", "$37,947.75", "00:00", "910746:00:00", "00:00.0", "3.79E+04", "37947.7500001"},
+		{"-37947.7500001", "-37948", "-37947.75", "-37,948", "-37947.75", "-3794775%", "-3794775.00%", "-3.79E+04", "-37947 3/4", "-37947  3/4 ", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-37947.7500001", "(37,948)", "(37,948)", "(37,947.75)", "(37,947.75)", "(37,948)", "$(37,948)", "(37,947.75)", "$(37,947.75)", "-37947.7500001", "-37947.7500001", "-37947.7500001", "-3.79E+04", "-37947.7500001"},
+		{"0.007", "0", "0.01", "0", "0.01", "1%", "0.70%", "7.00E-03", "0    ", "  1/99", "12-30-99", "30-Dec-99", "30-Dec", "Dec-99", "0:10 am", "0:10:05 am", "00:10", "00:10:05", "12/30/99 00:10", "0 ", "0 ", "0.01 ", "0.01 ", "0", "$0", "0.01", "$0.01", "10:05", "0:10:05", "10:04.8", "7.00E-03", "0.007"},
+		{"2.1", "2", "2.10", "2", "2.10", "210%", "210.00%", "2.10E+00", "2 1/9", "2  1/10", "01-01-00", "1-Jan-00", "1-Jan", "Jan-00", "2:24 am", "2:24:00 am", "02:24", "02:24:00", "1/1/00 02:24", "2 ", "2 ", "2.10 ", "2.10 ", "2", "$2", "2.10", "$2.10", "24:00", "50:24:00", "24:00.0", "2.10E+00", "2.1"},
 		{"String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String", "String"},
 	}
 	
@@ -1511,7 +1635,14 @@ func TestDeleteSheetFromWorkbookRels(t *testing.T) {
 	f := NewFile()
 	rels := defaultXMLPathWorkbookRels
 	f.Relationships.Store(rels, nil)
-	assert.Equal(t, f.deleteSheetFromWorkbookRels("rID"), "")
+	target, err := f.deleteSheetFromWorkbookRels("rID")
+	assert.NoError(t, err)
+	assert.Equal(t, "", target)
+	// Test delete sheet from workbook relationships with unsupported charset
+	f.Relationships.Delete(rels)
+	f.Pkg.Store(rels, MacintoshCyrillicCharset)
+	_, err = f.deleteSheetFromWorkbookRels("rID")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestUpdateLinkedValue(t *testing.T) {
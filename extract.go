@@ -0,0 +1,320 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractTextOptions directly maps the settings of the text extraction by the
+// ExtractText function.
+type ExtractTextOptions struct {
+	Sheets           []string
+	RawCellValue     bool
+	SkipComments     bool
+	SkipHeaderFooter bool
+	SkipShapes       bool
+}
+
+// textEscaper escapes the backslash, tab and line breaks in the extracted
+// text, so that each text record could be written in a single line.
+var textEscaper = strings.NewReplacer("\\", `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// ExtractText provides a function to extract all text of the workbook into
+// the writer for building full-text search indexes. The cell values,
+// comments, headers and footers and the text in the shapes will be written
+// line by line in the tab-separated text records with the worksheet name, the
+// kind and the location of the text:
+//
+//	<sheet>\t<kind>\t<location>\t<text>
+//
+// The kind of the text record is one of 'cell', 'comment', 'header', 'footer'
+// and 'shape'. The location is the cell reference for the cells and comments,
+// the top-left cell reference of the anchor for the shapes, and one of 'odd',
+// 'even' and 'first' for the headers and footers. The backslash, tab, carriage
+// return and line feed characters in the text will be escaped as '\\', '\t',
+// '\r' and '\n'. The cell values are read by the rows iterator without
+// loading the whole worksheet into memory, and the formatting codes in the
+// headers and footers will be removed. The options that can be set are:
+//
+//	 Option           | Description
+//	------------------+--------------------------------------------------------
+//	 Sheets           | The worksheets to be extracted in order, the default is
+//	                  | all worksheets of the workbook.
+//	                  |
+//	 RawCellValue     | Specifies if extract the raw cell values without
+//	                  | applying the number format.
+//	                  |
+//	 SkipComments     | Specifies if skip the comments.
+//	                  |
+//	 SkipHeaderFooter | Specifies if skip the headers and footers.
+//	                  |
+//	 SkipShapes       | Specifies if skip the text in the shapes.
+//
+// For example, extract the text of the worksheet named Sheet1 into the file
+// named Book1.txt:
+//
+//	file, err := os.Create("Book1.txt")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	err = f.ExtractText(file, &excelize.ExtractTextOptions{
+//	    Sheets: []string{"Sheet1"},
+//	})
+func (f *File) ExtractText(w io.Writer, opts *ExtractTextOptions) error {
+	if opts == nil {
+		opts = &ExtractTextOptions{}
+	}
+	sheets := opts.Sheets
+	if sheets == nil {
+		sheets = f.GetSheetList()
+	}
+	bw := bufio.NewWriter(w)
+	for _, sheet := range sheets {
+		if err := f.extractSheetText(bw, sheet, opts); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// extractSheetText provides a function to extract the text of the worksheet
+// by given worksheet name and text extraction options.
+func (f *File) extractSheetText(w *bufio.Writer, sheet string, opts *ExtractTextOptions) error {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	for row := 1; rows.Next(); row++ {
+		cols, err := rows.Columns(Options{RawCellValue: opts.RawCellValue})
+		if err != nil {
+			_ = rows.Close()
+			return err
+		}
+		for col, val := range cols {
+			if val == "" {
+				continue
+			}
+			cell, _ := CoordinatesToCellName(col+1, row)
+			writeTextRecord(w, sheet, "cell", cell, val)
+		}
+	}
+	if err = rows.Close(); err != nil {
+		return err
+	}
+	if !opts.SkipHeaderFooter {
+		if err = f.extractHeaderFooterText(w, sheet); err != nil {
+			return err
+		}
+	}
+	if !opts.SkipComments {
+		if err = f.extractCommentsText(w, sheet); err != nil {
+			return err
+		}
+	}
+	if !opts.SkipShapes {
+		err = f.extractShapesText(w, sheet)
+	}
+	return err
+}
+
+// extractHeaderFooterText provides a function to extract the text of the
+// headers and footers by given worksheet name, the sheet data will be skipped
+// on decoding the worksheet.
+func (f *File) extractHeaderFooterText(w *bufio.Writer, sheet string) error {
+	name, _ := f.getSheetXMLPath(sheet)
+	needClose, decoder, tempFile, err := f.xmlDecoder(name)
+	if needClose && err == nil {
+		defer tempFile.Close()
+	}
+	if err != nil {
+		return err
+	}
+	for {
+		token, _ := decoder.Token()
+		if token == nil {
+			return err
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if se.Name.Local == "sheetData" {
+			if err = decoder.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		if se.Name.Local != "headerFooter" {
+			continue
+		}
+		var headerFooter xlsxHeaderFooter
+		if err = decoder.DecodeElement(&headerFooter, &se); err != nil {
+			return err
+		}
+		for _, item := range []struct{ kind, location, text string }{
+			{"header", "odd", headerFooter.OddHeader},
+			{"footer", "odd", headerFooter.OddFooter},
+			{"header", "even", headerFooter.EvenHeader},
+			{"footer", "even", headerFooter.EvenFooter},
+			{"header", "first", headerFooter.FirstHeader},
+			{"footer", "first", headerFooter.FirstFooter},
+		} {
+			if text := headerFooterText(item.text); text != "" {
+				writeTextRecord(w, sheet, item.kind, item.location, text)
+			}
+		}
+		return err
+	}
+}
+
+// extractCommentsText provides a function to extract the text of the
+// comments by given worksheet name.
+func (f *File) extractCommentsText(w *bufio.Writer, sheet string) error {
+	name, _ := f.getSheetXMLPath(sheet)
+	target := f.getSheetComments(filepath.Base(name))
+	if target == "" {
+		return nil
+	}
+	if !strings.HasPrefix(target, "/") {
+		target = "xl" + strings.TrimPrefix(target, "..")
+	}
+	cmts, err := f.commentsReader(strings.TrimPrefix(target, "/"))
+	if err != nil || cmts == nil {
+		return err
+	}
+	for _, comment := range cmts.CommentList.Comment {
+		var text strings.Builder
+		if comment.Text.T != nil {
+			text.WriteString(*comment.Text.T)
+		}
+		for _, r := range comment.Text.R {
+			if r.T != nil {
+				text.WriteString(r.T.Val)
+			}
+		}
+		if text.Len() > 0 {
+			writeTextRecord(w, sheet, "comment", comment.Ref, text.String())
+		}
+	}
+	return err
+}
+
+// extractShapesText provides a function to extract the text in the shapes of
+// the drawing part by given worksheet name.
+func (f *File) extractShapesText(w *bufio.Writer, sheet string) error {
+	name, _ := f.getSheetXMLPath(sheet)
+	rels, err := f.relsReader(path.Dir(name) + "/_rels/" + path.Base(name) + ".rels")
+	if err != nil || rels == nil {
+		return err
+	}
+	var drawings []string
+	rels.Lock()
+	for _, rel := range rels.Relationships {
+		if rel.Type == SourceRelationshipDrawingML {
+			drawings = append(drawings, getRelationshipsPartPath(path.Dir(name), rel.Target))
+		}
+	}
+	rels.Unlock()
+	for _, drawingXML := range drawings {
+		content := f.readXML(drawingXML)
+		if drawing, ok := f.Drawings.Load(drawingXML); ok && drawing != nil {
+			content, _ = xml.Marshal(drawing.(*xlsxWsDr))
+		}
+		wsDr := new(decodeWsDr)
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+			Decode(wsDr); err != nil && err != io.EOF {
+			return err
+		}
+		err = nil
+		anchors := append(append(wsDr.AbsoluteAnchor, wsDr.OneCellAnchor...), wsDr.TwoCellAnchor...)
+		for _, anchor := range anchors {
+			if anchor.Sp == nil || anchor.Sp.TxBody == nil {
+				continue
+			}
+			var paragraphs []string
+			for _, p := range anchor.Sp.TxBody.P {
+				var text strings.Builder
+				for _, r := range p.R {
+					text.WriteString(r.T)
+				}
+				paragraphs = append(paragraphs, text.String())
+			}
+			text := strings.TrimRight(strings.Join(paragraphs, "\n"), "\n")
+			if text == "" {
+				continue
+			}
+			var cell string
+			if anchor.From != nil {
+				cell, _ = CoordinatesToCellName(anchor.From.Col+1, anchor.From.Row+1)
+			}
+			writeTextRecord(w, sheet, "shape", cell, text)
+		}
+	}
+	return err
+}
+
+// headerFooterText provides a function to remove the formatting codes in the
+// header or footer, and returns the plain text. The left, center and right
+// sections will be separated by space.
+func headerFooterText(s string) string {
+	var text strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '&' || i+1 == len(runes) {
+			text.WriteRune(runes[i])
+			continue
+		}
+		i++
+		switch code := runes[i]; {
+		case code == '&':
+			text.WriteRune('&')
+		case code == '"':
+			for i+1 < len(runes) && runes[i+1] != '"' {
+				i++
+			}
+			i++
+		case '0' <= code && code <= '9':
+			for i+1 < len(runes) && '0' <= runes[i+1] && runes[i+1] <= '9' {
+				i++
+			}
+		case code == 'K':
+			i += 6
+		case code == 'L' || code == 'C' || code == 'R':
+			if text.Len() > 0 {
+				text.WriteRune(' ')
+			}
+		}
+	}
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+// writeTextRecord provides a function to write the text record of the text
+// extraction by given worksheet name, kind, location and text.
+func writeTextRecord(w *bufio.Writer, sheet, kind, location, text string) {
+	_, _ = w.WriteString(textEscaper.Replace(sheet))
+	_ = w.WriteByte('\t')
+	_, _ = w.WriteString(kind)
+	_ = w.WriteByte('\t')
+	_, _ = w.WriteString(location)
+	_ = w.WriteByte('\t')
+	_, _ = w.WriteString(textEscaper.Replace(text))
+	_ = w.WriteByte('\n')
+}
@@ -0,0 +1,102 @@
+package excel
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractText(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Amount"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"Line\tone\nLine two", 1.5}))
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "B1", Author: "Author", Text: "Total amount"}))
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &HeaderFooterOptions{
+		OddHeader: `&L&"Arial,Bold"&14Quarterly &&Report&RPage &P of &N`,
+		OddFooter: "&C&KFF0000Confidential",
+	}))
+	assert.NoError(t, f.AddShape("Sheet1", "D2", &Shape{Type: "rect", Paragraph: []ShapeParagraph{{Text: "Note"}, {Text: "Check"}}}))
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet2", "C2", "Sheet2 value"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestExtractText.xlsx")))
+	assert.NoError(t, f.Close())
+
+	expected := strings.Join([]string{
+		"Sheet1\tcell\tA1\tName",
+		"Sheet1\tcell\tB1\tAmount",
+		`Sheet1	cell	A3	Line\tone\nLine two`,
+		"Sheet1\tcell\tB3\t1.5",
+		"Sheet1\theader\todd\tQuarterly &Report Page of",
+		"Sheet1\tfooter\todd\tConfidential",
+		"Sheet1\tcomment\tB1\tTotal amount",
+		`Sheet1	shape	D2	Note\nCheck`,
+		"Sheet2\tcell\tC2\tSheet2 value",
+	}, "\n") + "\n"
+	for _, loaded := range []bool{false, true} {
+		f, err = OpenFile(filepath.Join("test", "TestExtractText.xlsx"))
+		assert.NoError(t, err)
+		if loaded {
+			// Test extract text with the loaded worksheet and drawing
+			_, err = f.workSheetReader("Sheet1")
+			assert.NoError(t, err)
+			_, _, err = f.drawingParser("xl/drawings/drawing1.xml")
+			assert.NoError(t, err)
+		}
+		buf := new(bytes.Buffer)
+		assert.NoError(t, f.ExtractText(buf, nil))
+		assert.Equal(t, expected, buf.String())
+		assert.NoError(t, f.Close())
+	}
+
+	f, err = OpenFile(filepath.Join("test", "TestExtractText.xlsx"))
+	assert.NoError(t, err)
+	// Test extract text with specified worksheets and skip options
+	buf := new(bytes.Buffer)
+	assert.NoError(t, f.ExtractText(buf, &ExtractTextOptions{
+		Sheets:           []string{"Sheet1"},
+		SkipComments:     true,
+		SkipHeaderFooter: true,
+		SkipShapes:       true,
+	}))
+	assert.Equal(t, strings.Join(strings.Split(expected, "\n")[:4], "\n")+"\n", buf.String())
+	// Test extract text with not exist worksheet
+	assert.EqualError(t, f.ExtractText(buf, &ExtractTextOptions{Sheets: []string{"SheetN"}}), "sheet SheetN does not exist")
+	// Test extract text with unsupported charset comments
+	f.Comments["xl/comments1.xml"] = nil
+	f.Pkg.Store("xl/comments1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExtractText(buf, nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestExtractText.xlsx"))
+	assert.NoError(t, err)
+	// Test extract text with unsupported charset drawing
+	f.Pkg.Store("xl/drawings/drawing1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExtractText(buf, nil), "XML syntax error on line 1: invalid UTF-8")
+	// Test extract text with unsupported charset worksheet relationships
+	f.Relationships.Delete("xl/worksheets/_rels/sheet1.xml.rels")
+	f.Pkg.Store("xl/worksheets/_rels/sheet1.xml.rels", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExtractText(buf, &ExtractTextOptions{SkipComments: true}), "XML syntax error on line 1: invalid UTF-8")
+	// Test extract text with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExtractText(buf, nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestHeaderFooterText(t *testing.T) {
+	for text, expected := range map[string]string{
+		"":                            "",
+		"&":                           "&",
+		"Plain text":                  "Plain text",
+		"&CPage &P of &N":             "Page of",
+		`&L&"Times New Roman,Bold"&B`: "",
+		"&LLeft&CCenter&RRight":       "Left Center Right",
+		"&K01+000Theme &U&&Co":        "Theme &Co",
+	} {
+		assert.Equal(t, expected, headerFooterText(text), text)
+	}
+}
@@ -14,19 +14,37 @@ package excel
 import (
 	"archive/zip"
 	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/xml"
+	"hash"
+	"hash/crc32"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// NewFile provides a function to create new file by default template.
-// For example:
+// NewFile provides a function to create new file by default template. The
+// options will be used from the start, the Password will be used on saving,
+// the CultureInfo will be used on getting the cell values, and the
+// DefaultFont, DefaultSheetName and Date1904 will be applied to the new
+// workbook. The invalid default sheet name will be ignored and the default
+// worksheet will be named Sheet1. For example, create a workbook with the
+// default worksheet named Summary and the 1904 date system:
 //
-//	f := NewFile()
-func NewFile() *File {
+//	f := NewFile(excelize.Options{DefaultSheetName: "Summary", Date1904: true})
+func NewFile(opts ...Options) *File {
 	f := newFile()
+	f.options = parseOptions(opts...)
 	f.Pkg.Store("_rels/.rels", []byte(xml.Header+templateRels))
 	f.Pkg.Store(defaultXMLPathDocPropsApp, []byte(xml.Header+templateDocpropsApp))
 	f.Pkg.Store(defaultXMLPathDocPropsCore, []byte(xml.Header+templateDocpropsCore))
@@ -52,6 +70,15 @@ func NewFile() *File {
 	ws, _ := f.workSheetReader("Sheet1")
 	f.Sheet.Store("xl/worksheets/sheet1.xml", ws)
 	f.Theme, _ = f.themeReader()
+	if f.options.DefaultSheetName != "" {
+		_ = f.SetSheetName("Sheet1", f.options.DefaultSheetName)
+	}
+	if f.options.DefaultFont != "" {
+		_ = f.SetDefaultFont(f.options.DefaultFont)
+	}
+	if f.options.Date1904 {
+		f.WorkBook.WorkbookPr.Date1904 = true
+	}
 	return f
 }
 
@@ -67,7 +94,15 @@ func (f *File) Save(opts ...Options) error {
 }
 
 // SaveAs provides a function to create or update to a spreadsheet at the
-// provided path.
+// provided path. The spreadsheet will be written into a temporary file in the
+// same directory first, and be renamed to the provided path after it has been
+// written completely, so the truncated spreadsheet will never be left at the
+// provided path when the writing fails. The file mode of the existing
+// spreadsheet will be kept, and the file which the symbolic link points to
+// will be replaced if the provided path is a symbolic link. The existing
+// spreadsheet will be overwritten directly if the temporary file can't be
+// created in the directory without the write permission. Set the SyncOnSave
+// option to flush the written spreadsheet and the directory to the storage.
 func (f *File) SaveAs(name string, opts ...Options) error {
 	if len(name) > MaxFilePathLength {
 		return ErrMaxFilePathLength
@@ -76,41 +111,225 @@ func (f *File) SaveAs(name string, opts ...Options) error {
 	if _, ok := supportedContentTypes[filepath.Ext(f.Path)]; !ok {
 		return ErrWorkbookFileFormat
 	}
-	file, err := os.OpenFile(filepath.Clean(name), os.O_WRONLY|os.O_TRUNC|os.O_CREATE, os.ModePerm)
+	name = getSavePath(filepath.Clean(name))
+	file, err := createSaveTempFile(name)
+	if err != nil {
+		if info, statErr := os.Stat(name); os.IsPermission(err) && statErr == nil && info.Mode().IsRegular() {
+			return f.overwriteFile(name, opts...)
+		}
+		return err
+	}
+	if err = f.Write(file, opts...); err == nil && f.options != nil && f.options.SyncOnSave {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if f.options != nil && f.options.SyncOnSave {
+		err = syncDir(filepath.Dir(name))
+	}
+	return err
+}
+
+// getSavePath provides a function to get the path of the file to be replaced
+// on saving the spreadsheet by given path, returns the path of the file
+// which the symbolic link points to if the given path is a symbolic link.
+func getSavePath(name string) string {
+	if info, err := os.Lstat(name); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if target, err := filepath.EvalSymlinks(name); err == nil {
+			return target
+		}
+	}
+	return name
+}
+
+// overwriteFile provides a function to write the spreadsheet into the
+// existing file by given path directly.
+func (f *File) overwriteFile(name string, opts ...Options) error {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_TRUNC, os.ModePerm)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	return f.Write(file, opts...)
+	if err = f.Write(file, opts...); err == nil && f.options != nil && f.options.SyncOnSave {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
 }
 
-// Close closes and cleanup the open temporary file for the spreadsheet.
+// syncDir provides a function to flush the directory entries by given
+// directory path to the storage, which makes the renamed file durable. The
+// directory can't be flushed on Windows, so it will be skipped.
+func syncDir(name string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	dir, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	if err = dir.Sync(); err != nil {
+		_ = dir.Close()
+		return err
+	}
+	return dir.Close()
+}
+
+// createSaveTempFile provides a function to create the temporary file in the
+// directory of the given spreadsheet path for saving, and apply the file
+// mode of the existing spreadsheet to it.
+func createSaveTempFile(name string) (*os.File, error) {
+	dir, base := filepath.Split(name)
+	seed := time.Now().UnixNano()
+	for i := 0; ; i++ {
+		file, err := os.OpenFile(filepath.Join(dir, "."+base+"."+strconv.FormatInt(seed+int64(i), 36)+".tmp"),
+			os.O_WRONLY|os.O_CREATE|os.O_EXCL, os.ModePerm)
+		if os.IsExist(err) && i < 10000 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(name); err == nil && info.Mode().IsRegular() {
+			if err = file.Chmod(info.Mode().Perm()); err != nil {
+				_ = file.Close()
+				_ = os.Remove(file.Name())
+				return nil, err
+			}
+		}
+		return file, nil
+	}
+}
+
+// Close closes and cleanup the open temporary file for the spreadsheet. It is
+// safe to call this function multiple times, the subsequent calls will do
+// nothing after the spreadsheet has been closed successfully.
 func (f *File) Close() error {
+	if f.closed {
+		return nil
+	}
 	var err error
 	if f.sharedStringTemp != nil {
 		if err := f.sharedStringTemp.Close(); err != nil {
 			return err
 		}
+		f.sharedStringTemp = nil
 	}
 	f.tempFiles.Range(func(k, v interface{}) bool {
 		if err = os.Remove(v.(string)); err != nil {
 			return false
 		}
+		f.tempFiles.Delete(k)
 		return true
 	})
 	for _, stream := range f.streams {
 		_ = stream.rawData.Close()
 	}
+	if err == nil {
+		err = f.closeExternalWorkbooks()
+	}
+	if err == nil {
+		f.closed = true
+		f.tracker.close()
+	}
 	return err
 }
 
-// Write provides a function to write to an io.Writer.
+// LeakedResource directly maps the information of the resource which has been
+// garbage collected without closing, the Type is the type name of the
+// resource such as "File" or "Rows", and the Stack is the stack trace of the
+// goroutine which created the resource.
+type LeakedResource struct {
+	Type  string
+	Stack string
+}
+
+// finalizerReporter defined the function for reporting the leaked resources
+// in the finalizer mode.
+type finalizerReporter struct {
+	fn func(LeakedResource)
+}
+
+// finalizerMode stores the finalizerReporter of the finalizer mode.
+var finalizerMode atomic.Value
+
+// SetFinalizerMode provides a function to enable the finalizer mode by given
+// report function, the spreadsheets and rows iterators created after that
+// will be tracked, and the report function will be called with the stack
+// trace of creation if any of them has been garbage collected without
+// calling the Close function. This mode is intended to be used for tracing the
+// leaked temporary files in development since capturing the stack traces is
+// expensive, pass nil to disable it. For example:
+//
+//	excelize.SetFinalizerMode(func(r excelize.LeakedResource) {
+//	    log.Printf("unclosed %s created at:\n%s", r.Type, r.Stack)
+//	})
+func SetFinalizerMode(fn func(LeakedResource)) {
+	finalizerMode.Store(finalizerReporter{fn: fn})
+}
+
+// resourceTracker defined the tracker of the closable resource in the
+// finalizer mode. The finalizer is set on the tracker instead of the resource,
+// since the finalizer of the resource in the reference cycles will not run.
+type resourceTracker struct {
+	closed int32
+}
+
+// newResourceTracker create the tracker for the resource by given type name
+// of the resource, returns nil if the finalizer mode has not been enabled.
+func newResourceTracker(name string) *resourceTracker {
+	reporter, _ := finalizerMode.Load().(finalizerReporter)
+	if reporter.fn == nil {
+		return nil
+	}
+	tracker, stack := &resourceTracker{}, string(debug.Stack())
+	runtime.SetFinalizer(tracker, func(t *resourceTracker) {
+		if atomic.LoadInt32(&t.closed) == 0 {
+			reporter.fn(LeakedResource{Type: name, Stack: stack})
+		}
+	})
+	return tracker
+}
+
+// close marks the tracked resource has been closed.
+func (t *resourceTracker) close() {
+	if t != nil {
+		atomic.StoreInt32(&t.closed, 1)
+	}
+}
+
+// Write provides a function to write to an io.Writer. The parts of the
+// spreadsheet will be compressed and written to the writer as soon as they
+// are prepared, without assembling the whole workbook in memory, unless the
+// workbook need to be encrypted with the password. For example, send the
+// spreadsheet directly as the HTTP response:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    f := excelize.NewFile()
+//	    defer f.Close()
+//	    // ...
+//	    w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+//	    w.Header().Set("Content-Disposition", "attachment; filename=Book1.xlsx")
+//	    if err := f.Write(w); err != nil {
+//	        fmt.Println(err)
+//	    }
+//	}
 func (f *File) Write(w io.Writer, opts ...Options) error {
 	_, err := f.WriteTo(w, opts...)
 	return err
 }
 
-// WriteTo implements io.WriterTo to write the file.
+// WriteTo implements io.WriterTo to write the file, and returns the number of
+// bytes written to the writer.
 func (f *File) WriteTo(w io.Writer, opts ...Options) (int64, error) {
 	for i := range opts {
 		f.options = &opts[i]
@@ -124,26 +343,213 @@ func (f *File) WriteTo(w io.Writer, opts ...Options) (int64, error) {
 			return 0, err
 		}
 	}
+	cw := &countWriter{w: w}
+	if f.options != nil && f.options.WriteHook != nil {
+		cw.hash, cw.hook = sha256.New(), f.options.WriteHook
+	}
 	if f.options != nil && f.options.Password != "" {
 		buf, err := f.WriteToBuffer()
 		if err != nil {
 			return 0, err
 		}
-		return buf.WriteTo(w)
+		if _, err = buf.WriteTo(cw); err == nil {
+			cw.report(true)
+		}
+		return cw.n, err
+	}
+	var stats *countWriter
+	if cw.hook != nil {
+		stats = cw
+	}
+	err := f.writeDirectToWriter(cw, stats)
+	if err == nil {
+		cw.report(true)
+	}
+	return cw.n, err
+}
+
+// Chunk directly maps a sequential chunk of the spreadsheet written by the
+// WriteChunks function.
+//
+// Index specifies the zero-based index of the chunk, the part number of the
+// multipart upload APIs is usually equal to the Index plus 1.
+//
+// Data specifies the bytes of the chunk, which will be reused after the
+// callback function returns, copy it if it needs to be retained.
+//
+// SHA256 specifies the hex-encoded SHA-256 checksum of the Data.
+//
+// Last specifies if the chunk is the last chunk of the spreadsheet.
+type Chunk struct {
+	Index  int
+	Data   []byte
+	SHA256 string
+	Last   bool
+}
+
+// WriteChunks provides a function to write the spreadsheet in sequential
+// chunks by given chunk size in bytes, the callback function will be called
+// with each chunk in order, and all chunks except the last one will be the
+// given size. The default chunk size is 5 MiB if the given size is less than
+// or equal to 0, which is the minimum part size of the common multipart
+// upload APIs. Writing will stop and return the error returned by the
+// callback function. For example, upload the spreadsheet to the cloud storage
+// with multipart upload without buffering the whole spreadsheet:
+//
+//	err := f.WriteChunks(0, func(chunk excelize.Chunk) error {
+//	    return uploadPart(uploadID, chunk.Index+1, chunk.Data, chunk.SHA256)
+//	})
+func (f *File) WriteChunks(size int, fn func(chunk Chunk) error, opts ...Options) error {
+	if fn == nil {
+		return ErrParameterRequired
+	}
+	if size <= 0 {
+		size = defaultChunkSize
 	}
-	if err := f.writeDirectToWriter(w); err != nil {
-		return 0, err
+	cw := &chunkWriter{fn: fn, buf: make([]byte, 0, size)}
+	if _, err := f.WriteTo(cw, opts...); err != nil {
+		return err
 	}
-	return 0, nil
+	return cw.flush(true)
+}
+
+// chunkWriter buffers the data written to it, and calls the callback function
+// with the sequential chunks of the given size. The full chunk will be held
+// until more data has been written, so the last chunk can be marked.
+type chunkWriter struct {
+	fn    func(chunk Chunk) error
+	buf   []byte
+	index int
+}
+
+// Write writes the data into the chunks.
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	var n int
+	for len(p) > 0 {
+		if len(cw.buf) == cap(cw.buf) {
+			if err := cw.flush(false); err != nil {
+				return n, err
+			}
+		}
+		l := copy(cw.buf[len(cw.buf):cap(cw.buf)], p)
+		cw.buf, p, n = cw.buf[:len(cw.buf)+l], p[l:], n+l
+	}
+	return n, nil
+}
+
+// flush provides a function to call the callback function with the buffered
+// chunk and reset the buffer.
+func (cw *chunkWriter) flush(last bool) error {
+	checksum := sha256.Sum256(cw.buf)
+	err := cw.fn(Chunk{Index: cw.index, Data: cw.buf, SHA256: hex.EncodeToString(checksum[:]), Last: last})
+	cw.buf, cw.index = cw.buf[:0], cw.index+1
+	return err
+}
+
+// WriteStats directly maps the statistics of writing the spreadsheet, which
+// will be reported by the WriteHook function of the options.
+//
+// Written specifies the number of bytes which have been written to the
+// writer.
+//
+// Uncompressed specifies the total uncompressed size in bytes of the parts
+// which have been written.
+//
+// EstimatedSize specifies the estimated size in bytes of the spreadsheet by
+// the compression ratio of the written parts, which is equal to the Written
+// after the spreadsheet has been written completely.
+//
+// SHA256 specifies the hex-encoded SHA-256 checksum of the written bytes.
+//
+// Done specifies if the spreadsheet has been written completely.
+type WriteStats struct {
+	Written       int64
+	Uncompressed  int64
+	EstimatedSize int64
+	SHA256        string
+	Done          bool
+}
+
+// countWriter wraps the io.Writer and counts the bytes written to it, and
+// calculates the SHA-256 checksum of the written bytes for reporting the
+// statistics of writing when the hook function is specified.
+type countWriter struct {
+	w            io.Writer
+	n            int64
+	hash         hash.Hash
+	hook         func(WriteStats)
+	total        int64
+	uncompressed int64
+	directory    int64
+}
+
+// Write writes the data to the underlying writer and counts the bytes.
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	if cw.hash != nil {
+		_, _ = cw.hash.Write(p[:n])
+	}
+	return n, err
+}
+
+// prepare provides a function to calculate the total uncompressed size of the
+// parts, and the size of the central directory of the ZIP archive for
+// estimating the size of the spreadsheet.
+func (cw *countWriter) prepare(parts []zipPart) {
+	if cw == nil {
+		return
+	}
+	cw.directory = 22
+	for _, part := range parts {
+		cw.total += part.size
+		cw.directory += 46 + int64(len(part.name))
+	}
+}
+
+// partWritten provides a function to report the statistics of writing after
+// the part with the given uncompressed size has been written.
+func (cw *countWriter) partWritten(size int64) {
+	if cw == nil {
+		return
+	}
+	cw.uncompressed += size
+	cw.report(false)
+}
+
+// report provides a function to call the hook function with the statistics
+// of writing.
+func (cw *countWriter) report(done bool) {
+	if cw.hook == nil {
+		return
+	}
+	stats := WriteStats{
+		Written:       cw.n,
+		Uncompressed:  cw.uncompressed,
+		EstimatedSize: cw.n,
+		SHA256:        hex.EncodeToString(cw.hash.Sum(nil)),
+		Done:          done,
+	}
+	if !done && cw.uncompressed > 0 {
+		remaining := cw.total - cw.uncompressed
+		if remaining < 0 {
+			remaining = 0
+		}
+		stats.EstimatedSize += int64(float64(remaining)*float64(cw.n)/float64(cw.uncompressed)) + cw.directory
+	}
+	cw.hook(stats)
 }
 
 // WriteToBuffer provides a function to get bytes.Buffer from the saved file,
 // and it allocates space in memory. Be careful when the file size is large.
 func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
-	zw := zip.NewWriter(buf)
+	zw, err := f.newZipWriter(buf)
+	if err != nil {
+		return buf, err
+	}
 	
-	if err := f.writeToZip(zw); err != nil {
+	if err = f.writeToZip(zw, nil); err != nil {
 		return buf, zw.Close()
 	}
 	
@@ -162,74 +568,445 @@ func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 	return buf, zw.Close()
 }
 
-// writeDirectToWriter provides a function to write to io.Writer.
-func (f *File) writeDirectToWriter(w io.Writer) error {
-	zw := zip.NewWriter(w)
-	if err := f.writeToZip(zw); err != nil {
+// writeDirectToWriter provides a function to write to io.Writer, the
+// statistics of writing will be reported by the given count writer if it
+// isn't nil.
+func (f *File) writeDirectToWriter(w io.Writer, stats *countWriter) error {
+	zw, err := f.newZipWriter(w)
+	if err != nil {
+		return err
+	}
+	if err = f.writeToZip(zw, stats); err != nil {
 		_ = zw.Close()
 		return err
 	}
 	return zw.Close()
 }
 
-// writeToZip provides a function to write to zip.Writer
-func (f *File) writeToZip(zw *zip.Writer) error {
+// compressionLevel returns the compression level of the spreadsheet by given
+// options, and check if the compression level is valid.
+func (f *File) compressionLevel() (int, error) {
+	if f.options == nil {
+		return CompressionDefault, nil
+	}
+	level := f.options.CompressionLevel
+	if level < CompressionNone || level > CompressionBestCompression {
+		return level, ErrCompressionLevel
+	}
+	return level, nil
+}
+
+// newZipWriter provides a function to create the zip.Writer which compress
+// the parts with the compression level in the options.
+func (f *File) newZipWriter(w io.Writer) (*zip.Writer, error) {
+	level, err := f.compressionLevel()
+	if err != nil {
+		return nil, err
+	}
+	zw := zip.NewWriter(w)
+	if level > CompressionDefault {
+		zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(out, level)
+		})
+	}
+	return zw, nil
+}
+
+// createZipPart provides a function to add a part by given path to the
+// zip.Writer, the part will be stored without compression in case the
+// CompressionNone was specified.
+func (f *File) createZipPart(zw *zip.Writer, name string) (io.Writer, error) {
+	method := zip.Deflate
+	if level, _ := f.compressionLevel(); level == CompressionNone {
+		method = zip.Store
+	}
+	return zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+}
+
+// writeToZip provides a function to write to zip.Writer, the statistics of
+// writing will be reported by the given count writer if it isn't nil.
+func (f *File) writeToZip(zw *zip.Writer, stats *countWriter) error {
+	if err := f.personalInfoWriter(); err != nil {
+		return err
+	}
 	f.calcChainWriter()
 	f.commentsWriter()
 	f.contentTypesWriter()
 	f.drawingsWriter()
 	f.vmlDrawingWriter()
+	if err := f.sheetDimensionsWriter(); err != nil {
+		return err
+	}
 	f.workBookWriter()
-	f.workSheetWriter()
+	if err := f.workSheetWriter(); err != nil {
+		return err
+	}
 	f.relsWriter()
 	_ = f.sharedStringsLoader()
 	f.sharedStringsWriter()
 	f.styleSheetWriter()
 	f.themeWriter()
 	
+	var parts []zipPart
 	for path, stream := range f.streams {
-		fi, err := zw.Create(path)
+		stream := stream
+		parts = append(parts, zipPart{name: path, size: stream.size(), open: func() (io.Reader, error) {
+			from, err := stream.reader()
+			if err != nil {
+				_ = stream.rawData.Close()
+			}
+			return from, err
+		}})
+	}
+	f.Pkg.Range(func(path, content interface{}) bool {
+		if _, ok := f.streams[path.(string)]; ok {
+			return true
+		}
+		data, _ := content.([]byte)
+		parts = append(parts, zipPart{name: path.(string), size: int64(len(data)), open: func() (io.Reader, error) {
+			return bytes.NewReader(data), nil
+		}})
+		return true
+	})
+	f.tempFiles.Range(func(path, content interface{}) bool {
+		if _, ok := f.Pkg.Load(path); ok {
+			return true
+		}
+		var size int64
+		if fi, err := os.Stat(content.(string)); err == nil {
+			size = fi.Size()
+		}
+		parts = append(parts, zipPart{name: path.(string), size: size, open: func() (io.Reader, error) {
+			file, err := f.readTemp(path.(string))
+			if err != nil || file == nil {
+				return bytes.NewReader(nil), nil
+			}
+			return file, nil
+		}})
+		return true
+	})
+	if f.options != nil && f.options.CanonicalXML {
+		canonicalZipParts(parts)
+	}
+	stats.prepare(parts)
+	if workers := f.compressionWorkers(); workers > 1 && len(parts) > 1 && supportRawZipPart {
+		return f.writeZipPartsConcurrently(zw, parts, workers, stats)
+	}
+	for _, part := range parts {
+		fi, err := f.createZipPart(zw, part.name)
 		if err != nil {
 			return err
 		}
-		var from io.Reader
-		from, err = stream.rawData.Reader()
+		from, err := part.open()
 		if err != nil {
-			_ = stream.rawData.Close()
 			return err
 		}
-		_, err = io.Copy(fi, from)
+		size, err := io.Copy(fi, from)
+		closeZipPart(from)
 		if err != nil {
 			return err
 		}
+		stats.partWritten(size)
 	}
-	var err error
-	f.Pkg.Range(func(path, content interface{}) bool {
-		if err != nil {
-			return false
+	return nil
+}
+
+// canonicalZipParts provides a function to sort the parts of the spreadsheet
+// by the part names, and convert the XML parts to the canonical form on
+// opening the parts.
+func canonicalZipParts(parts []zipPart) {
+	sort.Slice(parts, func(i, j int) bool { return parts[i].name < parts[j].name })
+	for i := range parts {
+		ext := strings.ToLower(filepath.Ext(parts[i].name))
+		if ext != ".xml" && ext != ".rels" {
+			continue
 		}
-		if _, ok := f.streams[path.(string)]; ok {
-			return true
+		open := parts[i].open
+		parts[i].open = func() (io.Reader, error) {
+			from, err := open()
+			if err != nil {
+				return from, err
+			}
+			defer closeZipPart(from)
+			content, err := io.ReadAll(from)
+			if err != nil {
+				return nil, err
+			}
+			return bytes.NewReader(canonicalXML(content)), nil
+		}
+	}
+}
+
+// canonicalNode defined the element of the XML document in the canonical XML
+// writer, the children could be the nested elements or the other tokens of
+// the element.
+type canonicalNode struct {
+	start    xml.StartElement
+	children []interface{}
+}
+
+// canonicalXML provides a function to convert the XML document to the
+// canonical form, which sorts the attributes of each element, and writes the
+// empty elements as the self-closing tags. The text content including the
+// white spaces between the elements will be kept as it is, and the original
+// content will be returned if the document could not be parsed.
+func canonicalXML(content []byte) []byte {
+	var (
+		decoder = xml.NewDecoder(bytes.NewReader(content))
+		root    = &canonicalNode{}
+		stack   = []*canonicalNode{root}
+	)
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
 		}
-		var fi io.Writer
-		fi, err = zw.Create(path.(string))
 		if err != nil {
-			return false
+			return content
 		}
-		_, err = fi.Write(content.([]byte))
-		return true
-	})
-	f.tempFiles.Range(func(path, content interface{}) bool {
-		if _, ok := f.Pkg.Load(path); ok {
-			return true
+		parent := stack[len(stack)-1]
+		switch element := token.(type) {
+		case xml.StartElement:
+			node := &canonicalNode{start: element.Copy()}
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) == 1 {
+				return content
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			parent.children = append(parent.children, xml.CopyToken(token))
 		}
-		var fi io.Writer
-		fi, err = zw.Create(path.(string))
-		if err != nil {
-			return false
+	}
+	if len(stack) != 1 {
+		return content
+	}
+	var buf bytes.Buffer
+	for _, child := range root.children {
+		// the white spaces outside the root element are insignificant
+		if text, ok := child.(xml.CharData); ok && len(bytes.TrimSpace(text)) == 0 {
+			continue
 		}
-		_, err = fi.Write(f.readBytes(path.(string)))
-		return true
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		writeCanonicalToken(&buf, child)
+	}
+	return buf.Bytes()
+}
+
+// canonicalTextEscaper and canonicalAttrEscaper escape the text content and
+// the attribute values in the canonical XML writer, the carriage returns in
+// the text content and the white spaces in the attribute values should be
+// escaped, otherwise they will be normalized by the XML parser.
+var (
+	canonicalTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	canonicalAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", "\"", "&quot;",
+		"\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+// writeCanonicalToken provides a function to write the token of the XML
+// document in the canonical form by given buffer and token.
+func writeCanonicalToken(buf *bytes.Buffer, token interface{}) {
+	switch element := token.(type) {
+	case *canonicalNode:
+		writeCanonicalNode(buf, element)
+	case xml.CharData:
+		canonicalTextEscaper.WriteString(buf, string(element))
+	case xml.Comment:
+		buf.WriteString("<!--")
+		buf.Write(element)
+		buf.WriteString("-->")
+	case xml.ProcInst:
+		buf.WriteString("<?" + element.Target)
+		if len(element.Inst) > 0 {
+			buf.WriteByte(' ')
+			buf.Write(element.Inst)
+		}
+		buf.WriteString("?>")
+	case xml.Directive:
+		buf.WriteString("<!")
+		buf.Write(element)
+		buf.WriteByte('>')
+	}
+}
+
+// writeCanonicalNode provides a function to write the element of the XML
+// document in the canonical form by given buffer and element.
+func writeCanonicalNode(buf *bytes.Buffer, node *canonicalNode) {
+	qualifiedName := func(name xml.Name) string {
+		if name.Space == "" {
+			return name.Local
+		}
+		return name.Space + ":" + name.Local
+	}
+	attrs := node.start.Attr
+	sort.SliceStable(attrs, func(i, j int) bool {
+		nsI := attrs[i].Name.Space == "xmlns" || (attrs[i].Name.Space == "" && attrs[i].Name.Local == "xmlns")
+		nsJ := attrs[j].Name.Space == "xmlns" || (attrs[j].Name.Space == "" && attrs[j].Name.Local == "xmlns")
+		if nsI != nsJ {
+			return nsI
+		}
+		return qualifiedName(attrs[i].Name) < qualifiedName(attrs[j].Name)
 	})
+	name := qualifiedName(node.start.Name)
+	buf.WriteString("<" + name)
+	for _, attr := range attrs {
+		buf.WriteString(" " + qualifiedName(attr.Name) + "=\"")
+		canonicalAttrEscaper.WriteString(buf, attr.Value)
+		buf.WriteByte('"')
+	}
+	if len(node.children) == 0 {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteByte('>')
+	for _, child := range node.children {
+		writeCanonicalToken(buf, child)
+	}
+	buf.WriteString("</" + name + ">")
+}
+
+// closeZipPart closes the reader of the part if it is closable, such as the
+// temporary file of the worksheet.
+func closeZipPart(from io.Reader) {
+	if c, ok := from.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
+// zipPart defined the part of the spreadsheet to be written into the zip
+// archive, the open function provides read-access to the part content, the
+// parts stored in the temporary files will be read from the files directly
+// without loading into memory.
+type zipPart struct {
+	name string
+	size int64
+	open func() (io.Reader, error)
+}
+
+// compressedZipPart defined the compressed part of the spreadsheet, the
+// compressed data will be stored in the temporary file when the data size
+// over the StreamChunkSize.
+type compressedZipPart struct {
+	header *zip.FileHeader
+	data   bufferedWriter
+	err    error
+}
+
+// compressedZipPartWriter writes the compressed data into the buffered writer
+// and counts the written bytes.
+type compressedZipPartWriter struct {
+	part *compressedZipPart
+	size uint64
+}
+
+// Write the compressed data into the buffered writer, and sync it to the
+// temporary file if the buffered data over the StreamChunkSize.
+func (w *compressedZipPartWriter) Write(p []byte) (int, error) {
+	n, _ := w.part.data.Write(p)
+	w.size += uint64(n)
+	return n, w.part.data.Sync()
+}
+
+// compressionWorkers returns the number of workers for compressing the parts
+// of the spreadsheet concurrently.
+func (f *File) compressionWorkers() int {
+	if f.options == nil || f.options.CompressionWorkers == 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return f.options.CompressionWorkers
+}
+
+// compressZipPart provides a function to compress the part of the
+// spreadsheet with the given compression level, and calculate the CRC-32 and
+// size of the part.
+func compressZipPart(part zipPart, level int) *compressedZipPart {
+	cp := &compressedZipPart{header: &zip.FileHeader{Name: part.name, Method: zip.Deflate}}
+	from, err := part.open()
+	if err != nil {
+		cp.err = err
+		return cp
+	}
+	cw, crc := &compressedZipPartWriter{part: cp}, crc32.NewIEEE()
+	var (
+		to io.Writer = cw
+		fw *flate.Writer
+	)
+	if level == CompressionNone {
+		cp.header.Method = zip.Store
+	} else {
+		if level == CompressionDefault {
+			level = flate.DefaultCompression
+		}
+		fw, _ = flate.NewWriter(cw, level)
+		to = fw
+	}
+	size, err := io.Copy(io.MultiWriter(to, crc), from)
+	closeZipPart(from)
+	if err == nil && fw != nil {
+		err = fw.Close()
+	}
+	if err == nil {
+		err = cp.data.Flush()
+	}
+	cp.err = err
+	cp.header.CRC32 = crc.Sum32()
+	cp.header.CompressedSize64 = cw.size
+	cp.header.UncompressedSize64 = uint64(size)
+	return cp
+}
+
+// writeZipPartsConcurrently provides a function to compress the parts of the
+// spreadsheet concurrently by the given number of workers, and write the
+// compressed parts into the zip.Writer in order. At most the given number of
+// compressed parts are waiting to be written at the same time.
+func (f *File) writeZipPartsConcurrently(zw *zip.Writer, parts []zipPart, workers int, stats *countWriter) error {
+	level, err := f.compressionLevel()
+	if err != nil {
+		return err
+	}
+	results, sem := make([]chan *compressedZipPart, len(parts)), make(chan struct{}, workers)
+	for i := range results {
+		results[i] = make(chan *compressedZipPart, 1)
+	}
+	go func() {
+		for i, part := range parts {
+			sem <- struct{}{}
+			go func(i int, part zipPart) {
+				results[i] <- compressZipPart(part, level)
+			}(i, part)
+		}
+	}()
+	for _, result := range results {
+		cp := <-result
+		if err == nil {
+			err = cp.err
+		}
+		if err == nil {
+			err = writeCompressedZipPart(zw, cp)
+		}
+		if err == nil {
+			stats.partWritten(int64(cp.header.UncompressedSize64))
+		}
+		_ = cp.data.Close()
+		<-sem
+	}
+	return err
+}
+
+// writeCompressedZipPart provides a function to write the compressed part of
+// the spreadsheet into the zip.Writer without compressing again.
+func writeCompressedZipPart(zw *zip.Writer, cp *compressedZipPart) error {
+	fi, err := createRawZipPart(zw, cp.header)
+	if err != nil {
+		return err
+	}
+	from, err := cp.data.Reader()
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fi, from)
 	return err
 }
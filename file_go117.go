@@ -0,0 +1,31 @@
+//go:build go1.17
+// +build go1.17
+
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"archive/zip"
+	"io"
+)
+
+// supportRawZipPart specifies if write the compressed parts into the zip
+// archive without compressing again was supported.
+const supportRawZipPart = true
+
+// createRawZipPart provides a function to add a part by given file header to
+// the zip.Writer, the part content written into the returned writer should be
+// already compressed.
+func createRawZipPart(zw *zip.Writer, fh *zip.FileHeader) (io.Writer, error) {
+	return zw.CreateRaw(fh)
+}
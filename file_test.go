@@ -1,13 +1,21 @@
 package excel
 
 import (
+	"archive/zip"
 	"bufio"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 	
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -88,10 +96,451 @@ func TestWriteTo(t *testing.T) {
 		_, err := f.WriteTo(bufio.NewWriter(&buf))
 		assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 	}
+	// Test write returns the number of bytes written
+	{
+		f, buf := NewFile(), bytes.Buffer{}
+		n, err := f.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+		buf.Reset()
+		n, err = f.WriteTo(&buf, Options{Password: "password"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+		assert.NoError(t, f.Close())
+	}
+	// Test write with worksheets in the temporary files without loading them
+	{
+		f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{UnzipXMLSizeLimit: 128})
+		assert.NoError(t, err)
+		_, ok := f.tempFiles.Load("xl/worksheets/sheet1.xml")
+		assert.True(t, ok)
+		buf := bytes.Buffer{}
+		n, err := f.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+		_, ok = f.Pkg.Load("xl/worksheets/sheet1.xml")
+		assert.False(t, ok)
+		assert.NoError(t, f.Close())
+		f, err = OpenReader(&buf)
+		assert.NoError(t, err)
+		cellValue, err := f.GetCellValue("Sheet1", "A19")
+		assert.NoError(t, err)
+		assert.Equal(t, "Total:", cellValue)
+		assert.NoError(t, f.Close())
+	}
+}
+
+func TestCompressionLevel(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", strings.Repeat("s", 1<<10)))
+	sizes := make(map[int]int)
+	for _, level := range []int{CompressionNone, CompressionDefault, CompressionBestSpeed, CompressionBestCompression} {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, f.Write(buf, Options{CompressionLevel: level}))
+		sizes[level] = buf.Len()
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		for _, file := range zr.File {
+			if level == CompressionNone {
+				assert.Equal(t, zip.Store, file.Method)
+				continue
+			}
+			assert.Equal(t, zip.Deflate, file.Method)
+		}
+	}
+	assert.Greater(t, sizes[CompressionNone], sizes[CompressionDefault])
+	assert.GreaterOrEqual(t, sizes[CompressionBestSpeed], sizes[CompressionBestCompression])
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCompressionLevel.xlsx"), Options{CompressionLevel: CompressionNone}))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestCompressionLevel.xlsx"))
+	assert.NoError(t, err)
+	cellValue, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("s", 1<<10), cellValue)
+	// Test write with invalid compression level
+	for _, level := range []int{CompressionNone - 1, CompressionBestCompression + 1} {
+		assert.ErrorIs(t, f.Write(new(bytes.Buffer), Options{CompressionLevel: level}), ErrCompressionLevel)
+		_, err = f.WriteToBuffer()
+		assert.ErrorIs(t, err, ErrCompressionLevel)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestCanonicalXML(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", " a & b "))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "A1&\"<>\""))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", "a\r\nb\tc"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Note", RefersTo: "Sheet1!$A$1", Comment: "a\tb\r\nc"}))
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	write := func() []*zip.File {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, f.Write(buf, Options{CanonicalXML: true}))
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		return zr.File
+	}
+	readPart := func(files []*zip.File, name string) string {
+		for _, file := range files {
+			if file.Name == name {
+				rc, err := file.Open()
+				assert.NoError(t, err)
+				content, err := io.ReadAll(rc)
+				assert.NoError(t, err)
+				assert.NoError(t, rc.Close())
+				return string(content)
+			}
+		}
+		return ""
+	}
+	// Test write the parts in the order of the part names
+	files := write()
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	assert.True(t, sort.StringsAreSorted(names))
+	// Test write the same content for the unchanged workbook
+	assert.Equal(t, readPart(files, "xl/worksheets/sheet1.xml"), readPart(write(), "xl/worksheets/sheet1.xml"))
+	sheet := readPart(files, "xl/worksheets/sheet1.xml")
+	assert.True(t, strings.HasPrefix(sheet, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<worksheet xmlns="))
+	assert.Contains(t, sheet, "<row r=\"1\"><c r=\"A1\" t=\"s\"><v>0</v></c>")
+	assert.Contains(t, sheet, "<f>A1&amp;\"&lt;&gt;\"</f>")
+	assert.Contains(t, readPart(files, "xl/sharedStrings.xml"), "<t xml:space=\"preserve\"> a &amp; b </t>")
+	// Test read the workbook which written in the canonical form
+	buf := new(bytes.Buffer)
+	assert.NoError(t, f.Write(buf, Options{CanonicalXML: true}))
+	assert.NoError(t, f.Close())
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, " a & b ", value)
+	formula, err := f.GetCellFormula("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1&\"<>\"", formula)
+	value, err = f.GetCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "a\r\nb\tc", value)
+	definedNames := f.GetDefinedName()
+	assert.Len(t, definedNames, 1)
+	assert.Equal(t, "a\tb\r\nc", definedNames[0].Comment)
+	assert.NoError(t, f.Close())
+}
+
+func TestCanonicalXMLContent(t *testing.T) {
+	for content, expected := range map[string]string{
+		`<a z="1" xmlns:b="b" y="2&#xA;&#x9;" xmlns="a"><!--c--><b:c></b:c><d> <e/> </d></a>`: "<a xmlns=\"a\" xmlns:b=\"b\" y=\"2&#xA;&#x9;\" z=\"1\"><!--c--><b:c/><d> <e/> </d></a>",
+		`<a><r>text<b>bold</b> </r><t xml:space="preserve"> <x/> </t></a>`:                    "<a><r>text<b>bold</b> </r><t xml:space=\"preserve\"> <x/> </t></a>",
+		"<a>\n  <t>a&#xD;\nb\tc</t>\n</a>":                                                    "<a>\n  <t>a&#xD;\nb\tc</t>\n</a>",
+		`<!DOCTYPE a><a/>`:                                                                    "<!DOCTYPE a>\n<a/>",
+	} {
+		assert.Equal(t, expected, string(canonicalXML([]byte(content))))
+	}
+	// Test convert the invalid XML content
+	for _, content := range []string{`<a>`, `<a></b></a>`, `</a>`, `<a`} {
+		assert.Equal(t, content, string(canonicalXML([]byte(content))))
+	}
+}
+
+func TestCompressionWorkers(t *testing.T) {
+	f := NewFile()
+	for i := 2; i <= 5; i++ {
+		sheet := "Sheet" + strconv.Itoa(i)
+		_, err := f.NewSheet(sheet)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow(sheet, "A1", &[]interface{}{sheet, i, strings.Repeat("s", i<<10)}))
+	}
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	for row := 1; row <= 100; row++ {
+		cell, _ := CoordinatesToCellName(1, row)
+		assert.NoError(t, sw.SetRow(cell, []interface{}{row, "Data"}))
+	}
+	assert.NoError(t, sw.Flush())
+	readParts := func(b []byte) map[string][]byte {
+		parts := make(map[string][]byte)
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		assert.NoError(t, err)
+		for _, file := range zr.File {
+			rc, err := file.Open()
+			assert.NoError(t, err)
+			content, err := io.ReadAll(rc)
+			assert.NoError(t, err)
+			assert.NoError(t, rc.Close())
+			parts[file.Name] = content
+		}
+		return parts
+	}
+	buf := new(bytes.Buffer)
+	assert.NoError(t, f.Write(buf, Options{CompressionWorkers: 1}))
+	expected := readParts(buf.Bytes())
+	for _, opts := range []Options{
+		{CompressionWorkers: 4},
+		{CompressionWorkers: 2, CompressionLevel: CompressionNone},
+		{CompressionLevel: CompressionBestSpeed},
+	} {
+		buf.Reset()
+		assert.NoError(t, f.Write(buf, opts))
+		assert.Equal(t, expected, readParts(buf.Bytes()))
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCompressionWorkers.xlsx"), Options{CompressionWorkers: 4}))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestCompressionWorkers.xlsx"))
+	assert.NoError(t, err)
+	cellValue, err := f.GetCellValue("Sheet1", "B100")
+	assert.NoError(t, err)
+	assert.Equal(t, "Data", cellValue)
+	cellValue, err = f.GetCellValue("Sheet5", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet5", cellValue)
+	assert.NoError(t, f.Close())
+	// Test concurrent compression with write to directory error
+	f = NewFile()
+	f.Pkg.Store("/d/", []byte("s"))
+	assert.EqualError(t, f.Write(new(bytes.Buffer), Options{CompressionWorkers: 4}), "zip: write to directory")
+	// Test concurrent compression with invalid compression level
+	f.options.CompressionLevel = CompressionBestCompression + 1
+	assert.ErrorIs(t, f.writeZipPartsConcurrently(zip.NewWriter(new(bytes.Buffer)), nil, 4, nil), ErrCompressionLevel)
+	// Test concurrent compression with open part error
+	f.options.CompressionLevel = CompressionDefault
+	assert.EqualError(t, f.writeZipPartsConcurrently(zip.NewWriter(new(bytes.Buffer)), []zipPart{
+		{name: "s", open: func() (io.Reader, error) { return nil, ErrParameterInvalid }},
+		{name: "t", open: func() (io.Reader, error) { return strings.NewReader("t"), nil }},
+	}, 4, nil), ErrParameterInvalid.Error())
+	assert.NoError(t, f.Close())
+}
+
+func TestWriteHook(t *testing.T) {
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	for row := 1; row <= 1000; row++ {
+		cell, _ := CoordinatesToCellName(1, row)
+		assert.NoError(t, sw.SetRow(cell, []interface{}{row, strings.Repeat("Data", row%10)}))
+	}
+	assert.NoError(t, sw.Flush())
+	var stats []WriteStats
+	opts := Options{WriteHook: func(s WriteStats) { stats = append(stats, s) }}
+	for _, workers := range []int{1, 4} {
+		stats, opts.CompressionWorkers = nil, workers
+		buf := new(bytes.Buffer)
+		n, err := f.WriteTo(buf, opts)
+		assert.NoError(t, err)
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		var uncompressed int64
+		for _, file := range zr.File {
+			uncompressed += int64(file.UncompressedSize64)
+		}
+		checksum := sha256.Sum256(buf.Bytes())
+		assert.Len(t, stats, len(zr.File)+1)
+		assert.Equal(t, WriteStats{
+			Written: n, Uncompressed: uncompressed, EstimatedSize: n,
+			SHA256: hex.EncodeToString(checksum[:]), Done: true,
+		}, stats[len(stats)-1])
+		for i, s := range stats[:len(stats)-1] {
+			assert.False(t, s.Done)
+			assert.Greater(t, s.EstimatedSize, s.Written)
+			if i > 0 {
+				assert.GreaterOrEqual(t, s.Written, stats[i-1].Written)
+				assert.Greater(t, s.Uncompressed, stats[i-1].Uncompressed)
+			}
+		}
+	}
+	// Test write hook with password protection
+	stats = nil
+	buf := new(bytes.Buffer)
+	assert.NoError(t, f.Write(buf, Options{Password: "password", WriteHook: opts.WriteHook}))
+	checksum := sha256.Sum256(buf.Bytes())
+	assert.Equal(t, []WriteStats{{
+		Written: int64(buf.Len()), EstimatedSize: int64(buf.Len()),
+		SHA256: hex.EncodeToString(checksum[:]), Done: true,
+	}}, stats)
+	// Test write hook on saving the spreadsheet
+	stats = nil
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestWriteHook.xlsx"), Options{WriteHook: opts.WriteHook}))
+	b, err := os.ReadFile(filepath.Join("test", "TestWriteHook.xlsx"))
+	assert.NoError(t, err)
+	checksum = sha256.Sum256(b)
+	assert.Equal(t, hex.EncodeToString(checksum[:]), stats[len(stats)-1].SHA256)
+	assert.Equal(t, int64(len(b)), stats[len(stats)-1].Written)
+	// Test write hook with write error
+	stats = nil
+	file, err := os.Create(filepath.Join("test", "TestWriteHook.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+	_, err = f.WriteTo(file, Options{WriteHook: opts.WriteHook})
+	assert.Error(t, err)
+	for _, s := range stats {
+		assert.False(t, s.Done)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestWriteChunks(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 100; row++ {
+		cell, _ := CoordinatesToCellName(1, row)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &[]interface{}{row, strings.Repeat("Data", row)}))
+	}
+	expected := new(bytes.Buffer)
+	assert.NoError(t, f.Write(expected))
+	for _, size := range []int{1024, expected.Len(), expected.Len() + 1, 0} {
+		var (
+			chunks []Chunk
+			buf    bytes.Buffer
+		)
+		assert.NoError(t, f.WriteChunks(size, func(chunk Chunk) error {
+			checksum := sha256.Sum256(chunk.Data)
+			assert.Equal(t, hex.EncodeToString(checksum[:]), chunk.SHA256)
+			chunks = append(chunks, Chunk{Index: chunk.Index, Last: chunk.Last})
+			buf.Write(chunk.Data)
+			if !chunk.Last && size > 0 {
+				assert.Len(t, chunk.Data, size)
+			}
+			return nil
+		}))
+		assert.Equal(t, expected.Bytes(), buf.Bytes())
+		for i, chunk := range chunks {
+			assert.Equal(t, i, chunk.Index)
+			assert.Equal(t, i == len(chunks)-1, chunk.Last)
+		}
+		if size == 1024 {
+			assert.Len(t, chunks, (expected.Len()+size-1)/size)
+		} else {
+			assert.Len(t, chunks, 1)
+		}
+	}
+	// Test write chunks with the error returned by the callback function
+	var count int
+	assert.EqualError(t, f.WriteChunks(1024, func(chunk Chunk) error {
+		if count++; chunk.Index == 1 {
+			return ErrParameterInvalid
+		}
+		return nil
+	}), ErrParameterInvalid.Error())
+	assert.Equal(t, 2, count)
+	// Test write chunks with invalid parameters
+	assert.Equal(t, ErrParameterRequired, f.WriteChunks(1024, nil))
+	f.Path = "Book1.xls"
+	assert.Equal(t, ErrWorkbookFileFormat, f.WriteChunks(1024, func(chunk Chunk) error { return nil }))
+	assert.NoError(t, f.Close())
+}
+
+func TestStripPersonalInfo(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetDocProps(&DocProperties{Creator: "Creator", LastModifiedBy: "Modifier", Title: "Title"}))
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A1", Author: "Author A", Text: "Comment A"}))
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "B1", Author: "Author B", Text: "Comment B"}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStripPersonalInfo.xlsx"), Options{StripPersonalInfo: true}))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestStripPersonalInfo.xlsx"))
+	assert.NoError(t, err)
+	props, err := f.GetDocProps()
+	assert.NoError(t, err)
+	assert.Empty(t, props.Creator)
+	assert.Empty(t, props.LastModifiedBy)
+	assert.Equal(t, "Title", props.Title)
+	comments, err := f.GetComments()
+	assert.NoError(t, err)
+	assert.Len(t, comments["Sheet1"], 2)
+	for _, comment := range comments["Sheet1"] {
+		assert.Equal(t, "Author", comment.Author)
+	}
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.True(t, wb.WorkbookPr.FilterPrivacy)
+	assert.NoError(t, f.Close())
+
+	// Test save without the strip personal information option
+	f = NewFile()
+	assert.NoError(t, f.SetDocProps(&DocProperties{Creator: "Creator"}))
+	assert.NoError(t, f.Write(io.Discard))
+	props, err = f.GetDocProps()
+	assert.NoError(t, err)
+	assert.Equal(t, "Creator", props.Creator)
+	// Test strip personal information with unsupported charset core properties
+	f.Pkg.Store(defaultXMLPathDocPropsCore, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Write(io.Discard, Options{StripPersonalInfo: true}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test strip personal information with unsupported charset comments
+	f = NewFile()
+	f.Pkg.Store("xl/comments1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Write(io.Discard, Options{StripPersonalInfo: true}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test strip personal information with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Write(io.Discard, Options{StripPersonalInfo: true}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
 }
 
 func TestClose(t *testing.T) {
 	f := NewFile()
 	f.tempFiles.Store("/d/", "/d/")
 	require.Error(t, f.Close())
+	// Test close the spreadsheet multiple times
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	sw.rawData.chunkSize = 1
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Data"}))
+	assert.NoError(t, sw.Flush())
+	assert.NotNil(t, sw.rawData.tmp)
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.NotNil(t, rows.tempFile)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, rows.Close())
+		assert.NoError(t, f.Close())
+	}
+	assert.Nil(t, rows.tempFile)
+	_, err = os.Stat(sw.rawData.tmp.Name())
+	assert.True(t, os.IsNotExist(err))
+	f.tempFiles.Range(func(k, v interface{}) bool {
+		t.Errorf("temporary file %s has not been removed", v)
+		return true
+	})
+}
+
+func TestSetFinalizerMode(t *testing.T) {
+	leaked := make(chan LeakedResource, 1)
+	SetFinalizerMode(func(r LeakedResource) { leaked <- r })
+	defer SetFinalizerMode(nil)
+	func() {
+		tracker := newResourceTracker("Rows")
+		assert.NotNil(t, tracker)
+	}()
+	var r LeakedResource
+	for i := 0; i < 100 && r.Type == ""; i++ {
+		runtime.GC()
+		select {
+		case r = <-leaked:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	assert.Equal(t, "Rows", r.Type)
+	assert.Contains(t, r.Stack, "TestSetFinalizerMode")
+	// Test close the tracked resources
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.NotNil(t, f.tracker)
+	assert.NotNil(t, rows.tracker)
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, f.Close())
+	assert.Equal(t, int32(1), f.tracker.closed)
+	assert.Equal(t, int32(1), rows.tracker.closed)
+	// Test create the resources without the finalizer mode
+	SetFinalizerMode(nil)
+	assert.Nil(t, newResourceTracker("File"))
+	assert.Nil(t, NewFile().tracker)
 }
@@ -0,0 +1,214 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/width"
+)
+
+// fontMetrics directly maps the advance widths of the glyphs of a font in
+// the font design units. The ascii specifies the widths of the printable
+// ASCII characters from the space (U+0020) to the tilde (U+007E), the narrow
+// specifies the width of the other characters, and the wide specifies the
+// width of the East Asian wide and full-width characters.
+type fontMetrics struct {
+	unitsPerEm int
+	ascii      [95]int
+	narrow     int
+	wide       int
+	advance    func(r rune) (int, bool)
+}
+
+// builtInFontMetrics defined the metrics of the commonly used fonts, which
+// makes the widths of the text are not dependent on the fonts installed on
+// the operating system. The metrics of the East Asian fonts only specify the
+// widths of the half-width and full-width characters.
+var builtInFontMetrics = map[string]*fontMetrics{
+	"calibri": {
+		unitsPerEm: 2048,
+		ascii: [95]int{
+			463, 548, 820, 1038, 1038, 1472, 1432, 451, 621, 621, 1038, 1038, 511, 627, 517, 793,
+			1038, 1038, 1038, 1038, 1038, 1038, 1038, 1038, 1038, 1038, 548, 548, 1038, 1038, 1038, 969,
+			1870, 1185, 1114, 1092, 1260, 1000, 941, 1292, 1276, 516, 653, 1064, 861, 1751, 1322, 1356,
+			1058, 1378, 1112, 941, 998, 1314, 1162, 1822, 1063, 998, 959, 621, 793, 621, 1038, 1018,
+			587, 981, 1076, 866, 1076, 1019, 625, 964, 1076, 470, 490, 931, 470, 1636, 1076, 1080,
+			1076, 1076, 714, 801, 686, 1076, 925, 1464, 887, 927, 809, 640, 943, 640, 1038,
+		},
+		narrow: 1038, wide: 2048,
+	},
+	"arial": {
+		unitsPerEm: 1000,
+		ascii: [95]int{
+			278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+			556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+			1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+			667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+			333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+			556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+		},
+		narrow: 556, wide: 1000,
+	},
+	"times new roman": {
+		unitsPerEm: 1000,
+		ascii: [95]int{
+			250, 333, 408, 500, 500, 833, 778, 180, 333, 333, 500, 564, 250, 333, 250, 278,
+			500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 278, 278, 564, 564, 564, 444,
+			921, 722, 667, 667, 722, 611, 556, 722, 722, 333, 389, 722, 611, 889, 722, 722,
+			556, 722, 667, 556, 611, 722, 722, 944, 722, 722, 611, 333, 278, 333, 469, 500,
+			333, 444, 500, 444, 500, 444, 333, 500, 500, 278, 278, 500, 278, 778, 500, 500,
+			500, 500, 333, 389, 278, 500, 500, 722, 500, 500, 444, 480, 200, 480, 541,
+		},
+		narrow: 500, wide: 1000,
+	},
+	"courier new": newMonospaceFontMetrics(1000, 600, 1000),
+	"ms gothic":   newMonospaceFontMetrics(1000, 500, 1000),
+	"yu gothic":   newMonospaceFontMetrics(1000, 500, 1000),
+}
+
+// builtInFontMetricsAliases defined the localized names of the fonts which
+// have built-in metrics.
+var builtInFontMetricsAliases = map[string]string{
+	"ｍｓ ゴシック": "ms gothic",
+	"游ゴシック":   "yu gothic",
+}
+
+// newMonospaceFontMetrics returns the font metrics which all the printable
+// ASCII characters and the other narrow characters have the same width.
+func newMonospaceFontMetrics(unitsPerEm, narrow, wide int) *fontMetrics {
+	metrics := &fontMetrics{unitsPerEm: unitsPerEm, narrow: narrow, wide: wide}
+	for i := range metrics.ascii {
+		metrics.ascii[i] = narrow
+	}
+	return metrics
+}
+
+// width returns the advance width of the character in the font design units.
+func (m *fontMetrics) width(r rune) int {
+	if m.advance != nil {
+		if advance, ok := m.advance(r); ok {
+			return advance
+		}
+	}
+	if r >= ' ' && r <= '~' {
+		return m.ascii[r-' ']
+	}
+	if kind := width.LookupRune(r).Kind(); kind == width.EastAsianWide || kind == width.EastAsianFullwidth {
+		return m.wide
+	}
+	return m.narrow
+}
+
+// RegisterFontMetrics provides a function to register the metrics of the
+// font by given font name and the content of the TrueType or OpenType font
+// file, the registered metrics will be used instead of the built-in metrics
+// for measuring the width of the text by the MeasureText function and the
+// AutoWidth option of the stream writer. The font name is case-insensitive.
+// The built-in metrics includes the fonts Calibri, Arial, Times New Roman,
+// Courier New, MS Gothic and Yu Gothic. For example, register the metrics of
+// the font "Noto Sans" from the font file:
+//
+//	ttf, err := os.ReadFile("NotoSans-Regular.ttf")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.RegisterFontMetrics("Noto Sans", ttf)
+//
+// Register the font metrics with nil content will remove the registered
+// font metrics.
+func (f *File) RegisterFontMetrics(name string, content []byte) error {
+	if name = strings.ToLower(strings.TrimSpace(name)); name == "" {
+		return ErrParameterRequired
+	}
+	if content == nil {
+		f.fontMetrics.Delete(name)
+		return nil
+	}
+	fnt, err := sfnt.Parse(content)
+	if err != nil {
+		return err
+	}
+	unitsPerEm := int(fnt.UnitsPerEm())
+	var buf sfnt.Buffer
+	notdef, err := fnt.GlyphAdvance(&buf, 0, fixed.I(unitsPerEm), font.HintingNone)
+	if err != nil {
+		return err
+	}
+	metrics := &fontMetrics{unitsPerEm: unitsPerEm, narrow: notdef.Round(), wide: unitsPerEm}
+	metrics.advance = func(r rune) (int, bool) {
+		var buf sfnt.Buffer
+		idx, err := fnt.GlyphIndex(&buf, r)
+		if err != nil || idx == 0 {
+			return 0, false
+		}
+		advance, err := fnt.GlyphAdvance(&buf, idx, fixed.I(unitsPerEm), font.HintingNone)
+		return advance.Round(), err == nil
+	}
+	for i := range metrics.ascii {
+		metrics.ascii[i] = metrics.narrow
+	}
+	f.fontMetrics.Store(name, metrics)
+	return nil
+}
+
+// getFontMetrics provides a function to get the metrics of the font by given
+// font name, the registered font metrics will be used first, and the metrics
+// of the font Calibri will be used if the font has no metrics.
+func (f *File) getFontMetrics(name string) *fontMetrics {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if metrics, ok := f.fontMetrics.Load(name); ok {
+		return metrics.(*fontMetrics)
+	}
+	if alias, ok := builtInFontMetricsAliases[name]; ok {
+		name = alias
+	}
+	if metrics, ok := builtInFontMetrics[name]; ok {
+		return metrics
+	}
+	return builtInFontMetrics["calibri"]
+}
+
+// getDefaultFontMetrics provides a function to get the metrics of the default
+// font of the workbook.
+func (f *File) getDefaultFontMetrics() *fontMetrics {
+	var name string
+	if s, err := f.stylesReader(); err == nil && s.Fonts != nil && len(s.Fonts.Font) > 0 &&
+		s.Fonts.Font[0].Name != nil && s.Fonts.Font[0].Name.Val != nil {
+		name = *s.Fonts.Font[0].Name.Val
+	}
+	return f.getFontMetrics(name)
+}
+
+// MeasureText provides a function to measure the width of the text in points
+// by given font name, font size in points and the text, the text will be
+// measured as a single line, and the width of the characters which not
+// supported by the font metrics will be estimated. The measured width is not
+// dependent on the fonts installed on the operating system. For example,
+// measure the width of the text in 11 points Calibri font:
+//
+//	width, err := f.MeasureText("Calibri", 11, "Hello, world!")
+func (f *File) MeasureText(fontName string, size float64, text string) (float64, error) {
+	if size < MinFontSize || size > MaxFontSize {
+		return 0, ErrFontSize
+	}
+	metrics := f.getFontMetrics(fontName)
+	var units int
+	for _, r := range text {
+		units += metrics.width(r)
+	}
+	return float64(units) * size / float64(metrics.unitsPerEm), nil
+}
@@ -0,0 +1,73 @@
+package excel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestMeasureText(t *testing.T) {
+	f := NewFile()
+	for _, c := range []struct {
+		font     string
+		size     float64
+		text     string
+		expected float64
+	}{
+		{"Calibri", 11, "", 0},
+		{"Calibri", 11, "0123456789", 10 * 1038 * 11 / 2048.0},
+		{"calibri ", 11, "Hello", (1276 + 1019 + 470 + 470 + 1080) * 11 / 2048.0},
+		{"Arial", 10, "Hello, world!", 55.01},
+		{"Times New Roman", 12, "Excel", (611 + 500 + 444 + 444 + 278) * 12 / 1000.0},
+		{"Courier New", 10, "abc é", 30},
+		{"ＭＳ ゴシック", 10, "Aあ", 15},
+		{"游ゴシック", 10, "表計算", 30},
+		{"Yu Gothic", 20, "ｱ漢", 30},
+		{"Unknown", 11, "0", 1038 * 11 / 2048.0},
+		{"Calibri", 11, "漢", 11},
+	} {
+		width, err := f.MeasureText(c.font, c.size, c.text)
+		assert.NoError(t, err)
+		assert.InDelta(t, c.expected, width, 1e-9, c.text)
+	}
+	// Test measure text with invalid font size
+	for _, size := range []float64{0, MaxFontSize + 1} {
+		_, err := f.MeasureText("Calibri", size, "a")
+		assert.Equal(t, ErrFontSize, err)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestRegisterFontMetrics(t *testing.T) {
+	f := NewFile()
+	// Test register font metrics with the monospace font
+	assert.NoError(t, f.RegisterFontMetrics("Go Mono", gomono.TTF))
+	width, err := f.MeasureText("go mono", 10, "il")
+	assert.NoError(t, err)
+	expected, err := f.MeasureText("GO MONO", 10, "MW")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, width)
+	assert.InDelta(t, 12, width, 0.1)
+	// Test override the built-in font metrics
+	assert.NoError(t, f.RegisterFontMetrics("Calibri", goregular.TTF))
+	narrow, err := f.MeasureText("Calibri", 10, "i")
+	assert.NoError(t, err)
+	wide, err := f.MeasureText("Calibri", 10, "W")
+	assert.NoError(t, err)
+	assert.Less(t, narrow, wide)
+	// Test measure the characters which not supported by the font
+	width, err = f.MeasureText("Calibri", 10, "漢")
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, width)
+	// Test remove the registered font metrics
+	assert.NoError(t, f.RegisterFontMetrics("calibri", nil))
+	width, err = f.MeasureText("Calibri", 11, "0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1038*11/2048.0, width)
+	// Test register font metrics with invalid parameters
+	assert.Equal(t, ErrParameterRequired, f.RegisterFontMetrics(" ", goregular.TTF))
+	assert.EqualError(t, f.RegisterFontMetrics("Font", []byte("font")), "sfnt: invalid bounds")
+	assert.NoError(t, f.Close())
+}
@@ -0,0 +1,303 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/xuri/efp"
+)
+
+// FormulaFormatOptions directly maps the settings of the formula formatter.
+//
+// Indent specifies the number of spaces for each indentation level of the
+// nested function arguments, the default value is 4.
+//
+// HeaderRow specifies the row number of the header row. The cell references
+// in the formula will be translated to the header names in this row of the
+// referenced worksheet if it is greater than 0. For example, if the cell B1
+// contains the header "Revenue 2024", the reference B5 will be formatted as
+// Revenue 2024[5], B2:B10 as Revenue 2024[2:10] and B:B as Revenue 2024. The
+// reference will be kept if the header cell is empty.
+type FormulaFormatOptions struct {
+	Indent    int
+	HeaderRow int
+}
+
+// formulaFormatter defined the runtime state of the formula formatter.
+type formulaFormatter struct {
+	f      *File
+	sheet  string
+	opts   *FormulaFormatOptions
+	tokens []efp.Token
+	buf    strings.Builder
+	depth  int
+}
+
+// Formula formatter function call kind enumeration.
+const (
+	formulaFormatInline byte = iota
+	formulaFormatNested
+	formulaFormatArray
+	formulaFormatArrayRow
+)
+
+// FormatFormula provides a function to pretty-print the formula by given
+// worksheet name of the formula and the formula text, the formula something
+// like deeply nested function calls will be split into multiple lines with
+// indentation, and the function calls without nested functions will be kept
+// in one line. The output is designed for displaying the formula, such as in
+// the audit UI, and might not be a valid formula when the cell references be
+// translated to the header names. For example, format the formula of the cell
+// C1 on Sheet1 and translate the cell references to the header names in the
+// first row:
+//
+//	formula, err := f.GetCellFormula("Sheet1", "C1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	result, err := f.FormatFormula("Sheet1", formula, &excelize.FormulaFormatOptions{HeaderRow: 1})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println(result)
+//
+// The formula =IF(SUM(B2:B10)>0,AVERAGE(B2:B10),"") with header "Revenue
+// 2024" in cell B1 will be formatted as:
+//
+//	=IF(
+//	    SUM(Revenue 2024[2:10]) > 0,
+//	    AVERAGE(Revenue 2024[2:10]),
+//	    ""
+//	)
+func (f *File) FormatFormula(sheet, formula string, opts *FormulaFormatOptions) (string, error) {
+	if _, err := f.workSheetReader(sheet); err != nil {
+		return "", err
+	}
+	if opts == nil {
+		opts = &FormulaFormatOptions{}
+	}
+	if opts.Indent <= 0 {
+		opts.Indent = 4
+	}
+	if opts.HeaderRow < 0 {
+		return "", newInvalidRowNumberError(opts.HeaderRow)
+	}
+	if opts.HeaderRow > TotalRows {
+		return "", ErrMaxRows
+	}
+	ff := &formulaFormatter{f: f, sheet: sheet, opts: opts}
+	if strings.HasPrefix(formula, "=") {
+		ff.buf.WriteString("=")
+	}
+	ps := efp.ExcelParser()
+	ff.tokens = ps.Parse(formula)
+	if err := ff.format(); err != nil {
+		return "", err
+	}
+	return ff.buf.String(), nil
+}
+
+// format provides a function to write the formatted formula tokens.
+func (ff *formulaFormatter) format() error {
+	var kinds []byte
+	for i, token := range ff.tokens {
+		var kind byte = formulaFormatInline
+		if len(kinds) > 0 {
+			kind = kinds[len(kinds)-1]
+		}
+		switch {
+		case isFunctionStartToken(token):
+			switch token.TValue {
+			case "ARRAY":
+				ff.buf.WriteString("{")
+				kinds = append(kinds, formulaFormatArray)
+			case "ARRAYROW":
+				kinds = append(kinds, formulaFormatArrayRow)
+			default:
+				ff.buf.WriteString(token.TValue + "(")
+				if ff.isNestedFunction(i) {
+					kinds = append(kinds, formulaFormatNested)
+					ff.depth++
+					ff.newLine()
+					break
+				}
+				kinds = append(kinds, formulaFormatInline)
+			}
+		case isFunctionStopToken(token):
+			if len(kinds) > 0 {
+				kinds = kinds[:len(kinds)-1]
+			}
+			switch kind {
+			case formulaFormatArray:
+				ff.buf.WriteString("}")
+			case formulaFormatNested:
+				ff.depth--
+				ff.newLine()
+				ff.buf.WriteString(")")
+			case formulaFormatInline:
+				ff.buf.WriteString(")")
+			}
+		case token.TType == efp.TokenTypeArgument:
+			switch {
+			case len(kinds) == 0:
+				ff.buf.WriteString(",")
+			case kind == formulaFormatArray:
+				ff.buf.WriteString(";")
+			case kind == formulaFormatArrayRow:
+				ff.buf.WriteString(",")
+			case kind == formulaFormatNested:
+				ff.buf.WriteString(",")
+				ff.newLine()
+			default:
+				ff.buf.WriteString(", ")
+			}
+		case token.TType == efp.TokenTypeOperatorInfix:
+			if token.TSubType == efp.TokenSubTypeIntersection {
+				ff.buf.WriteString(" ")
+				break
+			}
+			ff.buf.WriteString(" " + token.TValue + " ")
+		case token.TType == efp.TokenTypeOperand:
+			if err := ff.formatOperand(token); err != nil {
+				return err
+			}
+		case isBeginParenthesesToken(token):
+			ff.buf.WriteString("(")
+		case isEndParenthesesToken(token):
+			ff.buf.WriteString(")")
+		case token.TType == efp.TokenTypeWhitespace:
+		default:
+			ff.buf.WriteString(token.TValue)
+		}
+	}
+	return nil
+}
+
+// isNestedFunction provides a function to check if the function call which
+// start at the given token index contains other function calls.
+func (ff *formulaFormatter) isNestedFunction(idx int) bool {
+	for i, depth := idx+1, 1; i < len(ff.tokens) && depth > 0; i++ {
+		if isFunctionStartToken(ff.tokens[i]) {
+			if ff.tokens[i].TValue != "ARRAY" && ff.tokens[i].TValue != "ARRAYROW" {
+				return true
+			}
+			depth++
+		}
+		if isFunctionStopToken(ff.tokens[i]) {
+			depth--
+		}
+	}
+	return false
+}
+
+// newLine provides a function to write a line break and indentation of the
+// current depth.
+func (ff *formulaFormatter) newLine() {
+	ff.buf.WriteString("\n" + strings.Repeat(" ", ff.depth*ff.opts.Indent))
+}
+
+// formatOperand provides a function to write the formatted operand token.
+func (ff *formulaFormatter) formatOperand(token efp.Token) error {
+	switch token.TSubType {
+	case efp.TokenSubTypeText:
+		ff.buf.WriteString("\"" + strings.ReplaceAll(token.TValue, "\"", "\"\"") + "\"")
+	case efp.TokenSubTypeRange:
+		ref, err := ff.formatReference(token.TValue)
+		if err != nil {
+			return err
+		}
+		ff.buf.WriteString(ref)
+	default:
+		ff.buf.WriteString(token.TValue)
+	}
+	return nil
+}
+
+// formatReference provides a function to format the reference operand, quote
+// the worksheet name if required and translate the cell references to the
+// header names if the header row has been specified.
+func (ff *formulaFormatter) formatReference(ref string) (string, error) {
+	sheet, cells := ff.sheet, ref
+	var prefix string
+	if idx := strings.LastIndex(ref, "!"); idx != -1 {
+		sheet, cells = ref[:idx], ref[idx+1:]
+		prefix = quoteSheetName(sheet) + "!"
+	}
+	if ff.opts.HeaderRow == 0 || strings.ContainsAny(sheet, "[]:") {
+		return prefix + cells, nil
+	}
+	if idx, _ := ff.f.GetSheetIndex(sheet); idx == -1 {
+		return prefix + cells, nil
+	}
+	name, ok, err := ff.headerReference(sheet, strings.ReplaceAll(cells, "$", ""))
+	if err != nil || !ok {
+		return prefix + cells, err
+	}
+	return prefix + name, nil
+}
+
+// headerReference provides a function to translate the cell reference or
+// range reference to the header names by given worksheet name. The ok result
+// is false if the reference can't be translated.
+func (ff *formulaFormatter) headerReference(sheet, ref string) (name string, ok bool, err error) {
+	parts := strings.Split(ref, ":")
+	if len(parts) > 2 {
+		return
+	}
+	cols, rows, names := make([]int, len(parts)), make([]int, len(parts)), make([]string, len(parts))
+	for i, part := range parts {
+		if cols[i], rows[i], err = CellNameToCoordinates(part); err != nil {
+			if cols[i], err = ColumnNameToNumber(part); err != nil {
+				return "", false, nil
+			}
+			rows[i] = 0
+		}
+		cell, _ := CoordinatesToCellName(cols[i], ff.opts.HeaderRow)
+		if names[i], err = ff.f.GetCellValue(sheet, cell); err != nil || names[i] == "" {
+			return
+		}
+	}
+	if len(parts) == 1 {
+		return names[0] + "[" + strconv.Itoa(rows[0]) + "]", rows[0] > 0, err
+	}
+	if (rows[0] == 0) != (rows[1] == 0) {
+		return
+	}
+	if cols[0] == cols[1] {
+		if rows[0] == 0 {
+			return names[0], true, err
+		}
+		return names[0] + "[" + strconv.Itoa(rows[0]) + ":" + strconv.Itoa(rows[1]) + "]", true, err
+	}
+	if rows[0] == 0 {
+		return names[0] + ":" + names[1], true, err
+	}
+	return names[0] + "[" + strconv.Itoa(rows[0]) + "]:" + names[1] + "[" + strconv.Itoa(rows[1]) + "]", true, err
+}
+
+// quoteSheetName provides a function to quote the worksheet name in the
+// reference if the name contains the characters other than letters, digits,
+// underscores, periods and the brackets of the external workbook, or starts
+// with a digit.
+func quoteSheetName(sheet string) string {
+	for i, r := range sheet {
+		if !(unicode.IsLetter(r) || r == '_' || r == '[' || r == ']' || (i > 0 && (unicode.IsDigit(r) || r == '.'))) {
+			return "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+		}
+	}
+	return sheet
+}
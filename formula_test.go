@@ -0,0 +1,68 @@
+package excel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatFormula(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Region", "Revenue 2024", "Cost 2024"}))
+	_, err := f.NewSheet("Sheet 2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet 2", "B1", "Budget"))
+	for formula, expected := range map[string][]string{
+		"=IF(SUM(B2:B10)>0,AVERAGE(B2:B10),\"\")": {
+			"=IF(\n    SUM(B2:B10) > 0,\n    AVERAGE(B2:B10),\n    \"\"\n)",
+			"=IF(\n  SUM(Revenue 2024[2:10]) > 0,\n  AVERAGE(Revenue 2024[2:10]),\n  \"\"\n)",
+		},
+		"=IF(AND(A2>0,'Sheet 2'!B2<>\"x\"\"y\"),SUM({1,2;3,4}),-C3%)": {
+			"=IF(\n    AND(A2 > 0, 'Sheet 2'!B2 <> \"x\"\"y\"),\n    SUM({1,2;3,4}),\n    -C3%\n)",
+			"=IF(\n  AND(Region[2] > 0, 'Sheet 2'!Budget[2] <> \"x\"\"y\"),\n  SUM({1,2;3,4}),\n  -Cost 2024[3]%\n)",
+		},
+		"=SUMIFS(C:C,A:A,\"East\",B:B,\">\"&MAX($B$2:$C$5))": {
+			"=SUMIFS(\n    C:C,\n    A:A,\n    \"East\",\n    B:B,\n    \">\" & MAX($B$2:$C$5)\n)",
+			"=SUMIFS(\n  Cost 2024,\n  Region,\n  \"East\",\n  Revenue 2024,\n  \">\" & MAX(Revenue 2024[2]:Cost 2024[5])\n)",
+		},
+		"=IFERROR(VLOOKUP(A2,'Sheet 2'!B:B,1,FALSE),[1]Sheet1!A1)": {
+			"=IFERROR(\n    VLOOKUP(A2, 'Sheet 2'!B:B, 1, FALSE),\n    [1]Sheet1!A1\n)",
+			"=IFERROR(\n  VLOOKUP(Region[2], 'Sheet 2'!Budget, 1, FALSE),\n  [1]Sheet1!A1\n)",
+		},
+		"SUM((A2:A3 B2:B4))+D2": {
+			"SUM((A2:A3 B2:B4)) + D2",
+			"SUM((Region[2:3] Revenue 2024[2:4])) + D2",
+		},
+		"=NOW()-SheetN!A1+SUM(1:2,A:C)+Sheet1!1:2+MyName": {
+			"=NOW() - SheetN!A1 + SUM(1:2, A:C) + Sheet1!1:2 + MyName",
+			"=NOW() - SheetN!A1 + SUM(1:2, Region:Cost 2024) + Sheet1!1:2 + MyName",
+		},
+	} {
+		result, err := f.FormatFormula("Sheet1", formula, nil)
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected[0], result, formula)
+		result, err = f.FormatFormula("Sheet1", formula, &FormulaFormatOptions{Indent: 2, HeaderRow: 1})
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected[1], result, formula)
+	}
+	// Test format formula with the reference mixed the cell and column
+	result, err := f.FormatFormula("Sheet1", "=SUM(A1:B)", &FormulaFormatOptions{HeaderRow: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "=SUM(A1:B)", result)
+	// Test format formula with invalid header row
+	_, err = f.FormatFormula("Sheet1", "=A1", &FormulaFormatOptions{HeaderRow: -1})
+	assert.EqualError(t, err, newInvalidRowNumberError(-1).Error())
+	_, err = f.FormatFormula("Sheet1", "=A1", &FormulaFormatOptions{HeaderRow: TotalRows + 1})
+	assert.EqualError(t, err, ErrMaxRows.Error())
+	// Test format formula on not exists worksheet
+	_, err = f.FormatFormula("SheetN", "=A1", nil)
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test format formula with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	_, err = f.FormatFormula("Sheet1", "=A1", &FormulaFormatOptions{HeaderRow: 1})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.Equal(t, "'1Sheet'", quoteSheetName("1Sheet"))
+	assert.Equal(t, "'O''Brien'", quoteSheetName("O'Brien"))
+	assert.Equal(t, "Sheet_1.2", quoteSheetName("Sheet_1.2"))
+}
@@ -95,7 +95,7 @@ func (f *File) readXML(name string) []byte {
 		return content.([]byte)
 	}
 	if content, ok := f.streams[name]; ok {
-		return content.rawData.buf.Bytes()
+		return content.bytes()
 	}
 	return []byte{}
 }
@@ -332,12 +332,12 @@ func (f *File) coordinatesToRangeRef(coordinates []int, abs ...bool) (string, er
 func (f *File) getDefinedNameRefTo(definedNameName string, currentSheet string) (refTo string) {
 	var workbookRefTo, worksheetRefTo string
 	for _, definedName := range f.GetDefinedName() {
-		if definedName.Name == definedNameName {
+		if strings.EqualFold(definedName.Name, definedNameName) {
 			// worksheet scope takes precedence over scope workbook when both definedNames exist
 			if definedName.Scope == "Workbook" {
 				workbookRefTo = definedName.RefersTo
 			}
-			if definedName.Scope == currentSheet {
+			if strings.EqualFold(definedName.Scope, currentSheet) {
 				worksheetRefTo = definedName.RefersTo
 			}
 		}
@@ -346,7 +346,7 @@ func (f *File) getDefinedNameRefTo(definedNameName string, currentSheet string)
 	if worksheetRefTo != "" {
 		refTo = worksheetRefTo
 	}
-	return
+	return strings.TrimPrefix(refTo, "=")
 }
 
 // flatSqref convert reference sequence to cell reference list.
@@ -424,6 +424,9 @@ func intPtr(i int) *int { return &i }
 // uintPtr returns a pointer to an int with the given value.
 func uintPtr(i uint) *uint { return &i }
 
+// uint8Ptr returns a pointer to an uint8 with the given value.
+func uint8Ptr(i uint8) *uint8 { return &i }
+
 // float64Ptr returns a pointer to a float64 with the given value.
 func float64Ptr(f float64) *float64 { return &f }
 
@@ -0,0 +1,213 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import "strings"
+
+// NavigationOptions directly maps the settings of the intra-workbook
+// navigation links built by the AddNavigation function.
+type NavigationOptions struct {
+	Sheets       []string
+	TOCSheet     string
+	TOCCell      string
+	TOCTitle     string
+	Cell         string
+	BackText     string
+	PrevText     string
+	NextText     string
+	ShowPrevNext *bool
+	Style        *Style
+}
+
+// AddNavigation provides a function to build the intra-workbook navigation
+// by hyperlinks without macros. A table of contents listing the links to
+// each worksheet will be written on the table of contents worksheet, and the
+// back to contents link, the previous and next worksheet links will be
+// written on every worksheet at the fixed cells with the consistent style.
+// The options that can be set are:
+//
+//	 Option       | Description
+//	--------------+------------------------------------------------------------
+//	 Sheets       | The worksheets to be navigated in order, the default is all
+//	              | visible worksheets of the workbook except the table of
+//	              | contents worksheet.
+//	              |
+//	 TOCSheet     | The name of the table of contents worksheet, the default
+//	              | is "Contents". The worksheet will be created at the end of
+//	              | the workbook if it doesn't exist.
+//	              |
+//	 TOCCell      | The top-left cell of the table of contents, the default is
+//	              | A1.
+//	              |
+//	 TOCTitle     | The optional title of the table of contents, which will be
+//	              | written on the 'TOCCell' and the links begin from the next
+//	              | row.
+//	              |
+//	 Cell         | The cell of the back to contents link on every worksheet,
+//	              | the previous and next worksheet links will be written on
+//	              | the next two cells on the right. The default is A1.
+//	              |
+//	 BackText     | The display text of the back to contents link, the default
+//	              | is "Back to contents".
+//	              |
+//	 PrevText     | The display text of the previous worksheet link, the
+//	              | default is "Previous".
+//	              |
+//	 NextText     | The display text of the next worksheet link, the default
+//	              | is "Next".
+//	              |
+//	 ShowPrevNext | Specifies if write the previous and next worksheet links,
+//	              | the default value is true.
+//	              |
+//	 Style        | The style of the link cells, the default style is the blue
+//	              | font with single underline.
+//
+// Note that the existing values of the navigation cells will be overwritten.
+// For example, build the navigation for the worksheets named Sheet1 and
+// Sheet2 with the table of contents on the worksheet named Index:
+//
+//	err := f.AddNavigation(&excelize.NavigationOptions{
+//	    Sheets:   []string{"Sheet1", "Sheet2"},
+//	    TOCSheet: "Index",
+//	    TOCTitle: "Index",
+//	    Cell:     "H1",
+//	})
+func (f *File) AddNavigation(opts *NavigationOptions) error {
+	options, err := f.parseNavigationOptions(opts)
+	if err != nil {
+		return err
+	}
+	tocCol, tocRow, err := CellNameToCoordinates(options.TOCCell)
+	if err != nil {
+		return err
+	}
+	col, row, err := CellNameToCoordinates(options.Cell)
+	if err != nil {
+		return err
+	}
+	styleID, err := f.NewStyle(options.Style)
+	if err != nil {
+		return err
+	}
+	idx, err := f.GetSheetIndex(options.TOCSheet)
+	if err != nil {
+		return err
+	}
+	if idx == -1 {
+		if _, err = f.NewSheet(options.TOCSheet); err != nil {
+			return err
+		}
+	}
+	if options.TOCTitle != "" {
+		if err = f.SetCellValue(options.TOCSheet, options.TOCCell, options.TOCTitle); err != nil {
+			return err
+		}
+		tocRow++
+	}
+	for i, sheet := range options.Sheets {
+		cell, _ := CoordinatesToCellName(tocCol, tocRow+i)
+		if err = f.setNavigationLink(options.TOCSheet, cell, sheet, sheet, "A1", styleID); err != nil {
+			return err
+		}
+	}
+	for i, sheet := range options.Sheets {
+		cell, _ := CoordinatesToCellName(col, row)
+		if err = f.setNavigationLink(sheet, cell, options.BackText, options.TOCSheet, options.TOCCell, styleID); err != nil {
+			return err
+		}
+		if !*options.ShowPrevNext {
+			continue
+		}
+		if i > 0 {
+			cell, _ = CoordinatesToCellName(col+1, row)
+			if err = f.setNavigationLink(sheet, cell, options.PrevText, options.Sheets[i-1], "A1", styleID); err != nil {
+				return err
+			}
+		}
+		if i < len(options.Sheets)-1 {
+			cell, _ = CoordinatesToCellName(col+2, row)
+			if err = f.setNavigationLink(sheet, cell, options.NextText, options.Sheets[i+1], "A1", styleID); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// parseNavigationOptions provides a function to parse the settings of the
+// navigation links with default value.
+func (f *File) parseNavigationOptions(opts *NavigationOptions) (*NavigationOptions, error) {
+	if opts == nil {
+		opts = &NavigationOptions{}
+	}
+	options := *opts
+	if options.TOCSheet == "" {
+		options.TOCSheet = "Contents"
+	}
+	if err := checkSheetName(options.TOCSheet); err != nil {
+		return &options, err
+	}
+	if options.TOCCell == "" {
+		options.TOCCell = "A1"
+	}
+	if options.Cell == "" {
+		options.Cell = "A1"
+	}
+	if options.BackText == "" {
+		options.BackText = "Back to contents"
+	}
+	if options.PrevText == "" {
+		options.PrevText = "Previous"
+	}
+	if options.NextText == "" {
+		options.NextText = "Next"
+	}
+	if options.ShowPrevNext == nil {
+		options.ShowPrevNext = boolPtr(true)
+	}
+	if options.Style == nil {
+		options.Style = &Style{Font: &Font{Color: "#0563C1", Underline: "single"}}
+	}
+	var sheets []string
+	if options.Sheets == nil {
+		options.Sheets = f.GetSheetList()
+	}
+	for _, sheet := range options.Sheets {
+		if strings.EqualFold(sheet, options.TOCSheet) {
+			continue
+		}
+		if opts.Sheets == nil {
+			if visible, err := f.GetSheetVisible(sheet); err != nil || !visible {
+				continue
+			}
+			if _, err := f.workSheetReader(sheet); err != nil {
+				continue
+			}
+		}
+		sheets = append(sheets, sheet)
+	}
+	options.Sheets = sheets
+	return &options, nil
+}
+
+// setNavigationLink provides a function to set the navigation link with
+// display text and style on the cell by given worksheet name, cell
+// reference, target worksheet name and cell reference.
+func (f *File) setNavigationLink(sheet, cell, text, targetSheet, targetCell string, styleID int) error {
+	if err := f.SetCellValue(sheet, cell, text); err != nil {
+		return err
+	}
+	if err := f.SetCellHyperLink(sheet, cell, quoteSheetName(targetSheet)+"!"+targetCell, "Location"); err != nil {
+		return err
+	}
+	return f.SetCellStyle(sheet, cell, cell, styleID)
+}
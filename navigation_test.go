@@ -0,0 +1,89 @@
+package excel
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddNavigation(t *testing.T) {
+	f := NewFile()
+	for _, sheet := range []string{"Sales Q1", "Sheet3", "Hidden"} {
+		_, err := f.NewSheet(sheet)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, f.SetSheetVisible("Hidden", false))
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{Type: Col, Series: []ChartSeries{{Values: "Sheet1!$A$1:$A$2"}}}))
+	assert.NoError(t, f.AddNavigation(&NavigationOptions{TOCTitle: "Contents", Cell: "H1"}))
+	assert.Equal(t, []string{"Sheet1", "Sales Q1", "Sheet3", "Hidden", "Chart1", "Contents"}, f.GetSheetList())
+	for cell, expected := range map[string][2]string{
+		"A1": {"Contents", ""},
+		"A2": {"Sheet1", "Sheet1!A1"},
+		"A3": {"Sales Q1", "'Sales Q1'!A1"},
+		"A4": {"Sheet3", "Sheet3!A1"},
+		"A5": {"", ""},
+	} {
+		value, err := f.GetCellValue("Contents", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[0], value)
+		_, target, err := f.GetCellHyperLink("Contents", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[1], target)
+	}
+	for _, expected := range []struct {
+		sheet, cell, value, target string
+	}{
+		{"Sheet1", "H1", "Back to contents", "Contents!A1"},
+		{"Sheet1", "I1", "", ""},
+		{"Sheet1", "J1", "Next", "'Sales Q1'!A1"},
+		{"Sales Q1", "I1", "Previous", "Sheet1!A1"},
+		{"Sales Q1", "J1", "Next", "Sheet3!A1"},
+		{"Sheet3", "I1", "Previous", "'Sales Q1'!A1"},
+		{"Sheet3", "J1", "", ""},
+		{"Hidden", "H1", "", ""},
+	} {
+		value, err := f.GetCellValue(expected.sheet, expected.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.value, value)
+		_, target, err := f.GetCellHyperLink(expected.sheet, expected.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.target, target)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddNavigation.xlsx")))
+	// Test add navigation with specified worksheets and without previous and next links
+	f = NewFile()
+	_, err := f.NewSheet("Index")
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddNavigation(&NavigationOptions{
+		Sheets: []string{"Sheet1", "Index"}, TOCSheet: "Index", TOCCell: "B2", BackText: "Home", ShowPrevNext: boolPtr(false),
+	}))
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Home", value)
+	_, target, err := f.GetCellHyperLink("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Index!B2", target)
+	value, err = f.GetCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Empty(t, value)
+	value, err = f.GetCellValue("Index", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet1", value)
+	// Test add navigation with default options
+	f = NewFile()
+	assert.NoError(t, f.AddNavigation(nil))
+	assert.Equal(t, []string{"Sheet1", "Contents"}, f.GetSheetList())
+	// Test add navigation with invalid options
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{TOCSheet: "Sheet:1"}), ErrSheetNameInvalid.Error())
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{TOCCell: "A"}), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{Cell: "A"}), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{Style: &Style{Font: &Font{Family: strings.Repeat("s", MaxFontFamilyLength+1)}}}), ErrFontLength.Error())
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{Sheets: []string{"SheetN"}}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{Sheets: []string{"Sheet1", "SheetN"}}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{Sheets: []string{"Sheet1", "SheetN"}, ShowPrevNext: boolPtr(false), TOCSheet: "Sheet1"}), "sheet SheetN does not exist")
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{Type: Col, Series: []ChartSeries{{Values: "Sheet1!$A$1:$A$2"}}}))
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{TOCSheet: "Chart1"}), "sheet Chart1 is not a worksheet")
+	assert.EqualError(t, f.AddNavigation(&NavigationOptions{Sheets: []string{"Chart1"}}), "sheet Chart1 is not a worksheet")
+}
@@ -31,12 +31,124 @@ type languageInfo struct {
 // numberFormat directly maps the number format parser runtime required
 // fields.
 type numberFormat struct {
-	section                                        []nfp.Section
-	t                                              time.Time
-	sectionIdx                                     int
-	date1904, isNumeric, hours, seconds            bool
-	number                                         float64
-	ap, localCode, result, value, valueSectionType string
+	section                                                    []nfp.Section
+	t                                                          time.Time
+	sectionIdx                                                 int
+	date1904, isNumeric, hours, negative, seconds              bool
+	elapsed, number                                            float64
+	ap, localCode, result, subSeconds, value, valueSectionType string
+}
+
+// numberSection directly maps the tokens of the number section and the
+// digit placeholders of the integer, decimal, exponent, numerator and
+// denominator parts in order for displaying a number.
+type numberSection struct {
+	tokens                  []nfp.Token
+	placeHolders            [5][]byte
+	parts                   []int
+	skip                    map[int]bool
+	decIdx, expIdx, fracIdx int
+	percents, scaling       int
+	grouping                bool
+}
+
+// NumberFormatCategory is the type of the number format category.
+type NumberFormatCategory byte
+
+// Number format categories enumeration.
+const (
+	NumberFormatCategoryGeneral NumberFormatCategory = iota
+	NumberFormatCategoryNumber
+	NumberFormatCategoryCurrency
+	NumberFormatCategoryDate
+	NumberFormatCategoryTime
+	NumberFormatCategoryPercentage
+	NumberFormatCategoryFraction
+	NumberFormatCategoryScientific
+	NumberFormatCategoryText
+	NumberFormatCategoryCustom
+)
+
+// CultureName is the type of supported language country codes for applying
+// the language dependent built-in number formats.
+type CultureName byte
+
+// Supported language country codes enumeration.
+const (
+	CultureNameUnknown CultureName = iota
+	CultureNameEnUS
+	CultureNameJaJP
+	CultureNameKoKR
+	CultureNameThTH
+	CultureNameZhCN
+	CultureNameZhTW
+)
+
+// Built-in number format IDs enumeration. The number formats from
+// NumFmtLang27 to NumFmtLang36 and from NumFmtLang50 to NumFmtLang58 are
+// language dependent, the format codes of them are decided by the Lang of
+// the style on creating the style, and will be applied on getting the cell
+// values with the CultureInfo option, such as the era date of the ja-JP and
+// the year and month date of the zh-CN.
+const (
+	NumFmtGeneral                   = 0
+	NumFmtNumber                    = 1
+	NumFmtNumberDecimal             = 2
+	NumFmtThousands                 = 3
+	NumFmtThousandsDecimal          = 4
+	NumFmtPercent                   = 9
+	NumFmtPercentDecimal            = 10
+	NumFmtScientific                = 11
+	NumFmtFraction                  = 12
+	NumFmtFractionTwoDigits         = 13
+	NumFmtDate                      = 14
+	NumFmtDayMonthYear              = 15
+	NumFmtDayMonth                  = 16
+	NumFmtMonthYear                 = 17
+	NumFmtTime12Hour                = 18
+	NumFmtTime12HourSeconds         = 19
+	NumFmtTime                      = 20
+	NumFmtTimeSeconds               = 21
+	NumFmtDateTime                  = 22
+	NumFmtLang27                    = 27
+	NumFmtLang28                    = 28
+	NumFmtLang29                    = 29
+	NumFmtLang30                    = 30
+	NumFmtLang31                    = 31
+	NumFmtLang32                    = 32
+	NumFmtLang33                    = 33
+	NumFmtLang34                    = 34
+	NumFmtLang35                    = 35
+	NumFmtLang36                    = 36
+	NumFmtThousandsParens           = 37
+	NumFmtThousandsParensRed        = 38
+	NumFmtThousandsDecimalParens    = 39
+	NumFmtThousandsDecimalParensRed = 40
+	NumFmtAccounting                = 41
+	NumFmtAccountingCurrency        = 42
+	NumFmtAccountingDecimal         = 43
+	NumFmtAccountingCurrencyDecimal = 44
+	NumFmtMinutesSeconds            = 45
+	NumFmtElapsedHours              = 46
+	NumFmtMinutesSecondsTenths      = 47
+	NumFmtEngineering               = 48
+	NumFmtText                      = 49
+	NumFmtLang50                    = 50
+	NumFmtLang51                    = 51
+	NumFmtLang52                    = 52
+	NumFmtLang53                    = 53
+	NumFmtLang54                    = 54
+	NumFmtLang55                    = 55
+	NumFmtLang56                    = 56
+	NumFmtLang57                    = 57
+	NumFmtLang58                    = 58
+)
+
+// localEra defined the start date and the names of an era, the names of the
+// era are in the order of the abbreviated, the short and the full name.
+type localEra struct {
+	start time.Time
+	names []string
 }
 
 var (
@@ -44,14 +156,47 @@ var (
 	supportedTokenTypes = []string{
 		nfp.TokenSubTypeLanguageInfo,
 		nfp.TokenTypeColor,
+		nfp.TokenTypeCondition,
 		nfp.TokenTypeCurrencyLanguage,
 		nfp.TokenTypeDateTimes,
+		nfp.TokenTypeDecimalPoint,
+		nfp.TokenTypeDenominator,
+		nfp.TokenTypeDigitalPlaceHolder,
 		nfp.TokenTypeElapsedDateTimes,
+		nfp.TokenTypeExponential,
+		nfp.TokenTypeFraction,
 		nfp.TokenTypeGeneral,
+		nfp.TokenTypeHashPlaceHolder,
 		nfp.TokenTypeLiteral,
+		nfp.TokenTypePercent,
+		nfp.TokenTypeRepeatsChar,
 		nfp.TokenTypeTextPlaceHolder,
+		nfp.TokenTypeThousandsSeparator,
 		nfp.TokenTypeZeroPlaceHolder,
 	}
+	// numberFormatCategoryNames defined the names of the number format
+	// categories.
+	numberFormatCategoryNames = []string{"General", "Number", "Currency", "Date", "Time", "Percentage", "Fraction", "Scientific", "Text", "Custom"}
+	// cultureLangs defined the language codes of the language dependent
+	// built-in number formats for the supported cultures.
+	cultureLangs = map[CultureName]string{
+		CultureNameJaJP: "ja-jp",
+		CultureNameKoKR: "ko-kr",
+		CultureNameThTH: "th-th",
+		CultureNameZhCN: "zh-cn",
+		CultureNameZhTW: "zh-tw",
+	}
+	// langNumFmtEnUS defined the language dependent built-in number formats
+	// in the English (United States).
+	langNumFmtEnUS = map[int]string{
+		27: "mmm-yy", 28: "d-mmm", 29: "d-mmm", 30: "m/d/yy", 31: "mmmm d, yyyy",
+		32: "h:mm", 33: "h:mm:ss", 34: "h:mm AM/PM", 35: "h:mm:ss AM/PM",
+		36: "mmm-yy", 50: "mmm-yy", 51: "d-mmm", 52: "mmm-yy", 53: "d-mmm",
+		54: "d-mmm", 55: "h:mm AM/PM", 56: "h:mm:ss AM/PM", 57: "mmm-yy", 58: "d-mmm",
+	}
+	// currencySymbols defined the currency symbols used for detecting the
+	// currency number format.
+	currencySymbols = "$¢£¤¥֏؋৲৳฿៛₡₢₣₤₥₦₧₨₩₪₫€₭₮₯₰₱₲₳₴₵₸₹₺₼₽₾"
 	// supportedLanguageInfo directly maps the supported language ID and tags.
 	supportedLanguageInfo = map[string]languageInfo{
 		"36":   {tags: []string{"af"}, localMonth: localMonthsNameAfrikaans, apFmt: apFmtAfrikaans},
@@ -250,6 +395,21 @@ var (
 	monthNamesYi = []string{"\ua2cd", "\ua44d", "\ua315", "\ua1d6", "\ua26c", "\ua0d8", "\ua3c3", "\ua246", "\ua22c", "\ua2b0", "\ua2b0\ua2aa", "\ua2b0\ua44b"}
 	// monthNamesZulu list the month names in the Zulu.
 	monthNamesZulu = []string{"Januwari", "Febhuwari", "Mashi", "Ephreli", "Meyi", "Juni", "Julayi", "Agasti", "Septemba", "Okthoba", "Novemba", "Disemba"}
+	// erasJapanese list the eras of the Japanese calendar in the descending
+	// order of the start date.
+	erasJapanese = []localEra{
+		{time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), []string{"R", "令", "令和"}},
+		{time.Date(1989, 1, 8, 0, 0, 0, 0, time.UTC), []string{"H", "平", "平成"}},
+		{time.Date(1926, 12, 25, 0, 0, 0, 0, time.UTC), []string{"S", "昭", "昭和"}},
+		{time.Date(1912, 7, 30, 0, 0, 0, 0, time.UTC), []string{"T", "大", "大正"}},
+		{time.Date(1868, 9, 8, 0, 0, 0, 0, time.UTC), []string{"M", "明", "明治"}},
+	}
+	// erasTaiwan list the eras of the Republic of China calendar.
+	erasTaiwan = []localEra{
+		{time.Date(1912, 1, 1, 0, 0, 0, 0, time.UTC), []string{"中華民國", "中華民國", "中華民國"}},
+	}
+	// supportedLanguageEras defined the eras of the calendars by language ID.
+	supportedLanguageEras = map[string][]localEra{"411": erasJapanese, "404": erasTaiwan}
 	// apFmtAfrikaans defined the AM/PM name in the Afrikaans.
 	apFmtAfrikaans = "vm./nm."
 	// apFmtCuba defined the AM/PM name in the Cuba.
@@ -289,71 +449,626 @@ func (nf *numberFormat) prepareNumberic(value string) {
 // the original cell value.
 func format(value, numFmt string, date1904 bool) string {
 	p := nfp.NumberFormatParser()
-	nf := numberFormat{section: p.Parse(numFmt), value: value, date1904: date1904}
+	nf := numberFormat{section: p.Parse(normalizeNumFmtCode(numFmt)), value: value, date1904: date1904}
 	nf.number, nf.valueSectionType = nf.getValueSectionType(value)
 	nf.prepareNumberic(value)
+	if !nf.isNumeric {
+		for i, section := range nf.section {
+			if section.Type == nfp.TokenSectionText {
+				nf.sectionIdx = i
+				return nf.textHandler()
+			}
+		}
+		return value
+	}
+	var ok bool
+	if nf.sectionIdx, nf.negative, ok = nf.getNumberSectionIdx(); !ok {
+		return value
+	}
+	for _, token := range nf.section[nf.sectionIdx].Items {
+		if token.TType == nfp.TokenTypeDateTimes || token.TType == nfp.TokenTypeElapsedDateTimes {
+			return nf.dateTimesSectionHandler()
+		}
+	}
+	return nf.numberHandler()
+}
+
+// normalizeNumFmtCode returns the number format code which can be parsed by
+// the number format parser without losing tokens. The repeat characters such
+// as "*-" will be removed, since the width of the cell is unknown and the
+// parser drops the token before them, and the open parenthesis before the
+// "General" will be escaped.
+func normalizeNumFmtCode(numFmt string) string {
+	var (
+		buf               strings.Builder
+		inQuote, inSquare bool
+		runes             = []rune(numFmt)
+	)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case inQuote:
+			inQuote = r != '"'
+		case inSquare:
+			inSquare = r != ']'
+		case r == '"':
+			inQuote = true
+		case r == '[':
+			inSquare = true
+		case r == '\\' && i+1 < len(runes):
+			buf.WriteRune(r)
+			i++
+			r = runes[i]
+		case r == '*':
+			i++
+			continue
+		case r == '(' && strings.HasPrefix(strings.ToLower(string(runes[i+1:])), "general"):
+			buf.WriteRune('\\')
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// getNumberSectionIdx returns the index of the section applicable to the
+// numeric cell value for a number format expression, and whether the minus
+// sign should be displayed for the value. The section with a matched
+// condition takes precedence, the first section without condition will be
+// used for the value which doesn't match any condition. Returns false if
+// none of the sections is applicable to the value.
+func (nf *numberFormat) getNumberSectionIdx() (int, bool, bool) {
+	var (
+		sections    []int
+		conditional bool
+	)
 	for i, section := range nf.section {
-		nf.sectionIdx = i
-		if section.Type != nf.valueSectionType {
+		if section.Type == nfp.TokenSectionText {
 			continue
 		}
-		if nf.isNumeric {
-			switch section.Type {
-			case nfp.TokenSectionPositive:
-				return nf.positiveHandler()
-			case nfp.TokenSectionNegative:
-				return nf.negativeHandler()
-			default:
-				return nf.zeroHandler()
+		sections = append(sections, i)
+		if operator, operand, ok := getSectionCondition(section); ok {
+			conditional = true
+			if matchSectionCondition(nf.number, operator, operand) {
+				negativeOnly := (operator == "<" || operator == "<=") && operand <= 0
+				return i, nf.number < 0 && !negativeOnly, true
 			}
 		}
-		return nf.textHandler()
 	}
-	return value
+	if len(sections) == 0 {
+		return -1, false, false
+	}
+	if conditional {
+		for _, i := range sections {
+			if _, _, ok := getSectionCondition(nf.section[i]); !ok {
+				return i, nf.number < 0, true
+			}
+		}
+		return -1, false, false
+	}
+	if nf.number < 0 && len(sections) > 1 {
+		return sections[1], false, true
+	}
+	if nf.number == 0 && len(sections) > 2 {
+		return sections[2], false, true
+	}
+	return sections[0], nf.number < 0, true
+}
+
+// getSectionCondition returns the comparison operator and operand of the
+// condition in the given number format section, returns false if the section
+// without a valid condition.
+func getSectionCondition(section nfp.Section) (string, float64, bool) {
+	for _, token := range section.Items {
+		if token.TType != nfp.TokenTypeCondition || len(token.Parts) != 2 {
+			continue
+		}
+		operand, err := strconv.ParseFloat(token.Parts[1].Token.TValue, 64)
+		if err != nil {
+			return "", 0, false
+		}
+		return token.Parts[0].Token.TValue, operand, true
+	}
+	return "", 0, false
 }
 
-// positiveHandler will be handling positive selection for a number format
-// expression.
-func (nf *numberFormat) positiveHandler() (result string) {
+// matchSectionCondition checks if the given number matches the condition of
+// a number format section.
+func matchSectionCondition(number float64, operator string, operand float64) bool {
+	switch operator {
+	case "<":
+		return number < operand
+	case "<=":
+		return number <= operand
+	case ">":
+		return number > operand
+	case ">=":
+		return number >= operand
+	case "=":
+		return number == operand
+	case "<>":
+		return number != operand
+	}
+	return false
+}
+
+// getCultureNumFmtCode provides a function to get the number format code of
+// the language dependent built-in number format by given culture and number
+// format ID, returns false if the number format is not language dependent or
+// the culture is unknown.
+func getCultureNumFmtCode(culture CultureName, numFmtID int) (string, bool) {
+	if culture == CultureNameEnUS {
+		fmtCode, ok := langNumFmtEnUS[numFmtID]
+		return fmtCode, ok
+	}
+	fmtCode, ok := langNumFmt[cultureLangs[culture]][numFmtID]
+	return fmtCode, ok
+}
+
+// dateTimesSectionHandler will be handling the date and times section for a
+// number format expression. The value will be rounded to the precision of
+// the seconds which displayed by the section, and the negative value can't
+// be displayed as date and time.
+func (nf *numberFormat) dateTimesSectionHandler() (result string) {
+	if nf.number < 0 {
+		result = nf.value
+		return
+	}
+	items := nf.section[nf.sectionIdx].Items
+	var places int
+	for i, token := range items {
+		if token.TType == nfp.TokenTypeZeroPlaceHolder && i > 0 &&
+			items[i-1].TType == nfp.TokenTypeDecimalPoint && len(token.TValue) > places {
+			places = len(token.TValue)
+		}
+	}
+	scale := math.Pow10(places)
+	units := math.Round(nf.number * 86400 * scale)
+	nf.number, nf.elapsed = units/scale/86400, math.Floor(units/scale)
+	nf.subSeconds = fmt.Sprintf("%0*.f", places, math.Mod(units, scale))
 	nf.t, nf.hours, nf.seconds = timeFromExcelTime(nf.number, nf.date1904), false, false
-	for i, token := range nf.section[nf.sectionIdx].Items {
+	for i, token := range items {
 		if inStrSlice(supportedTokenTypes, token.TType, true) == -1 || token.TType == nfp.TokenTypeGeneral {
 			result = nf.value
 			return
 		}
-		if token.TType == nfp.TokenTypeCurrencyLanguage {
+		switch token.TType {
+		case nfp.TokenTypeCurrencyLanguage:
 			if err := nf.currencyLanguageHandler(i, token); err != nil {
 				result = nf.value
 				return
 			}
-		}
-		if token.TType == nfp.TokenTypeDateTimes {
+		case nfp.TokenTypeDateTimes:
 			nf.dateTimesHandler(i, token)
-		}
-		if token.TType == nfp.TokenTypeElapsedDateTimes {
+		case nfp.TokenTypeElapsedDateTimes:
 			nf.elapsedDateTimesHandler(token)
-		}
-		if token.TType == nfp.TokenTypeLiteral {
+		case nfp.TokenTypeLiteral:
 			nf.result += token.TValue
+		case nfp.TokenTypeDecimalPoint:
+			nf.result += token.TValue
+		case nfp.TokenTypeZeroPlaceHolder:
+			if i > 0 && items[i-1].TType == nfp.TokenTypeDecimalPoint {
+				nf.result += nf.subSeconds[:len(token.TValue)]
+			}
+		}
+	}
+	result = nf.result
+	return
+}
+
+// numberHandler will be handling the number section for a number format
+// expression, the number could be displayed as decimal, scientific notation
+// or fraction.
+func (nf *numberFormat) numberHandler() string {
+	ns := newNumberSection(nf.section[nf.sectionIdx].Items)
+	for _, token := range ns.tokens {
+		if inStrSlice(supportedTokenTypes, token.TType, true) == -1 {
+			return nf.value
+		}
+	}
+	number := math.Abs(nf.number) * math.Pow(100, float64(ns.percents)) / math.Pow(1000, float64(ns.scaling))
+	var (
+		texts     [5][]string
+		expSign   string
+		intDigits string
+		hidden    bool
+	)
+	switch {
+	case ns.fracIdx != -1:
+		texts, hidden = ns.fractionTexts(number)
+	case ns.expIdx != -1:
+		texts, expSign = ns.scientificTexts(number)
+	default:
+		integer, decimal := roundNumber(number, len(ns.placeHolders[1]))
+		intDigits = strings.TrimLeft(integer, "0")
+		texts[0] = fillIntegerPlaceHolders(intDigits, ns.placeHolders[0], ns.grouping)
+		texts[1] = fillDecimalPlaceHolders(decimal, ns.placeHolders[1])
+	}
+	if nf.negative {
+		nf.result = "-"
+	}
+	var counts [5]int
+	for i, token := range ns.tokens {
+		if ns.skip[i] {
 			continue
 		}
-		if token.TType == nfp.TokenTypeZeroPlaceHolder && token.TValue == strings.Repeat("0", len(token.TValue)) {
-			if isNum, precision, decimal := isNumeric(nf.value); isNum {
-				if nf.number < 1 {
-					nf.result += "0"
-					continue
+		switch token.TType {
+		case nfp.TokenTypeZeroPlaceHolder, nfp.TokenTypeHashPlaceHolder, nfp.TokenTypeDigitalPlaceHolder:
+			part := ns.parts[i]
+			for range token.TValue {
+				nf.result += texts[part][counts[part]]
+				counts[part]++
+			}
+		case nfp.TokenTypeExponential:
+			nf.result += strings.ToUpper(token.TValue[:1]) + expSign
+		case nfp.TokenTypeFraction, nfp.TokenTypeDenominator:
+			if hidden {
+				nf.result += strings.Repeat(" ", len(token.TValue))
+				continue
+			}
+			nf.result += token.TValue
+		case nfp.TokenTypeDecimalPoint:
+			if i == ns.decIdx && len(ns.placeHolders[0]) == 0 {
+				nf.result += intDigits
+			}
+			nf.result += token.TValue
+		case nfp.TokenTypeLiteral, nfp.TokenTypePercent:
+			nf.result += token.TValue
+		case nfp.TokenTypeCurrencyLanguage:
+			for _, part := range token.Parts {
+				if part.Token.TType == nfp.TokenSubTypeCurrencyString {
+					nf.result += part.Token.TValue
+				}
+			}
+		case nfp.TokenTypeGeneral:
+			nf.result += strings.TrimPrefix(nf.value, "-")
+		}
+	}
+	return nf.result
+}
+
+// newNumberSection provides a function to create the number section layout by
+// given tokens of the number format section. The digit placeholders which
+// combined with the fraction symbol or the decimal point by the parser will be
+// split, and the digits following the fraction symbol will be combined as the
+// fixed denominator.
+func newNumberSection(items []nfp.Token) *numberSection {
+	ns := &numberSection{decIdx: -1, expIdx: -1, fracIdx: -1, skip: map[int]bool{}}
+	for _, token := range items {
+		if token.TType == nfp.TokenTypeDecimalPoint && len(token.TValue) > 1 {
+			placeHolders := strings.TrimSuffix(token.TValue, ".")
+			ns.tokens = append(ns.tokens, nfp.Token{TType: getPlaceHolderType(placeHolders), TValue: placeHolders})
+			token.TValue = "."
+		}
+		if isDigitPlaceHolder(token) && strings.Contains(token.TValue, "/") {
+			for i, part := range strings.SplitN(token.TValue, "/", 2) {
+				if i > 0 {
+					ns.tokens = append(ns.tokens, nfp.Token{TType: nfp.TokenTypeFraction, TValue: "/"})
 				}
-				if precision > 15 {
-					nf.result += strconv.FormatFloat(decimal, 'f', -1, 64)
-				} else {
-					nf.result += fmt.Sprintf("%.f", nf.number)
+				if part != "" {
+					ns.tokens = append(ns.tokens, nfp.Token{TType: getPlaceHolderType(part), TValue: part})
 				}
+			}
+			continue
+		}
+		if n := len(ns.tokens); n > 0 && strings.Trim(token.TValue, "0123456789") == "" {
+			prev := &ns.tokens[n-1]
+			if prev.TType == nfp.TokenTypeFraction && token.TValue[0] != '0' {
+				token.TType = nfp.TokenTypeDenominator
+			}
+			if prev.TType == nfp.TokenTypeDenominator {
+				prev.TValue += token.TValue
 				continue
 			}
 		}
+		ns.tokens = append(ns.tokens, token)
+	}
+	for i, token := range ns.tokens {
+		switch token.TType {
+		case nfp.TokenTypePercent:
+			ns.percents++
+		case nfp.TokenTypeDecimalPoint:
+			if ns.decIdx == -1 && ns.expIdx == -1 && ns.fracIdx == -1 {
+				ns.decIdx = i
+			}
+		case nfp.TokenTypeExponential:
+			if ns.expIdx == -1 && ns.fracIdx == -1 {
+				ns.expIdx = i
+			}
+		case nfp.TokenTypeFraction:
+			if ns.fracIdx == -1 && ns.expIdx == -1 {
+				ns.fracIdx = i
+			}
+		}
 	}
-	result = nf.result
-	return
+	ns.setParts()
+	return ns
+}
+
+// setParts assign the digit placeholders to the integer, decimal, exponent,
+// numerator and denominator parts of the number section, and detects the
+// thousands separators for grouping the integer digits and the trailing
+// thousands separators for scaling the number by a thousand.
+func (ns *numberSection) setParts() {
+	numIdx := ns.fracIdx
+	for numIdx > 0 && isDigitPlaceHolder(ns.tokens[numIdx-1]) {
+		numIdx--
+	}
+	firstIdx, lastIdx, lastPos := -1, -1, -1
+	ns.parts = make([]int, len(ns.tokens))
+	for i, token := range ns.tokens {
+		if !isDigitPlaceHolder(token) {
+			continue
+		}
+		switch {
+		case ns.fracIdx != -1 && i > ns.fracIdx:
+			ns.parts[i] = 4
+		case ns.fracIdx != -1 && i >= numIdx:
+			ns.parts[i] = 3
+		case ns.expIdx != -1 && i > ns.expIdx:
+			ns.parts[i] = 2
+		case ns.decIdx != -1 && i > ns.decIdx:
+			ns.parts[i] = 1
+		}
+		ns.placeHolders[ns.parts[i]] = append(ns.placeHolders[ns.parts[i]], token.TValue...)
+		if ns.parts[i] == 0 {
+			if firstIdx == -1 {
+				firstIdx = i
+			}
+			lastIdx = i
+		}
+		if ns.parts[i] < 2 {
+			lastPos = i
+		}
+	}
+	for i, token := range ns.tokens {
+		if token.TType == nfp.TokenTypeThousandsSeparator && i > firstIdx && i < lastIdx {
+			ns.grouping, ns.skip[i] = true, true
+		}
+	}
+	for i := lastPos + 1; lastPos != -1 && ns.fracIdx == -1 && i < len(ns.tokens); i++ {
+		if token := ns.tokens[i]; token.TType != nfp.TokenTypeThousandsSeparator &&
+			(token.TType != nfp.TokenTypeLiteral || strings.Trim(token.TValue, ",") != "") {
+			break
+		}
+		ns.scaling, ns.skip[i] = ns.scaling+len(ns.tokens[i].TValue), true
+	}
+}
+
+// scientificTexts returns the displayed texts of the digit placeholders and
+// the exponent sign for the number in scientific notation. The exponent will
+// be a multiple of the number of integer digit placeholders when the integer
+// part contains the "#" placeholder, which known as engineering notation.
+func (ns *numberSection) scientificTexts(number float64) ([5][]string, string) {
+	var (
+		texts  [5][]string
+		sign   string
+		exp    int
+		digits = len(ns.placeHolders[0])
+		step   = 1
+	)
+	if digits == 0 {
+		digits = 1
+	}
+	if len(ns.placeHolders[0]) > 1 && strings.Contains(string(ns.placeHolders[0]), "#") {
+		step = len(ns.placeHolders[0])
+	}
+	if number != 0 {
+		mantissa := strconv.FormatFloat(number, 'e', 14, 64)
+		exp, _ = strconv.Atoi(mantissa[strings.Index(mantissa, "e")+1:])
+		if exp -= digits - 1; step > 1 {
+			exp = int(math.Floor(float64(exp+digits-1)/float64(step))) * step
+		}
+	}
+	integer, decimal := roundNumber(number/math.Pow10(exp), len(ns.placeHolders[1]))
+	if len(integer) > digits {
+		exp += step
+		integer, decimal = roundNumber(number/math.Pow10(exp), len(ns.placeHolders[1]))
+	}
+	texts[0] = fillIntegerPlaceHolders(strings.TrimLeft(integer, "0"), ns.placeHolders[0], ns.grouping)
+	texts[1] = fillDecimalPlaceHolders(decimal, ns.placeHolders[1])
+	texts[2] = make([]string, len(ns.placeHolders[2]))
+	if len(texts[2]) > 0 {
+		texts[2][0] = fmt.Sprintf("%0*d", strings.Count(string(ns.placeHolders[2]), "0"), int(math.Abs(float64(exp))))
+	}
+	if exp < 0 {
+		sign = "-"
+	} else if strings.HasSuffix(ns.tokens[ns.expIdx].TValue, "+") {
+		sign = "+"
+	}
+	return texts, sign
+}
+
+// fractionTexts returns the displayed texts of the digit placeholders for the
+// number in fraction, and whether the fraction will be hidden when the
+// numerator is zero with integer part. The denominator will be the fixed
+// denominator or the best approximation within the digits of the
+// denominator placeholders.
+func (ns *numberSection) fractionTexts(number float64) ([5][]string, bool) {
+	var (
+		texts                  [5][]string
+		integer, fraction      = 0.0, number
+		hasInt                 = len(ns.placeHolders[0]) > 0
+		numerator, denominator int
+	)
+	if hasInt {
+		integer = math.Floor(number)
+		fraction = number - integer
+	}
+	for _, token := range ns.tokens[ns.fracIdx+1:] {
+		if token.TType == nfp.TokenTypeDenominator {
+			denominator, _ = strconv.Atoi(token.TValue)
+		}
+	}
+	if denominator > 0 {
+		numerator = int(math.Round(fraction * float64(denominator)))
+	} else {
+		// the digits of the denominator are limited to avoid overflow
+		digits := len(ns.placeHolders[4])
+		if digits > 9 {
+			digits = 9
+		}
+		numerator, denominator = approximateFraction(fraction, int(math.Pow10(digits))-1)
+	}
+	if hasInt && numerator == denominator {
+		integer, numerator = integer+1, 0
+	}
+	hidden := hasInt && numerator == 0
+	intDigits := strconv.FormatFloat(integer, 'f', 0, 64)
+	if integer == 0 && !hidden {
+		intDigits = ""
+	}
+	texts[0] = fillIntegerPlaceHolders(intDigits, ns.placeHolders[0], ns.grouping)
+	texts[3] = fillIntegerPlaceHolders(strconv.Itoa(numerator), ns.placeHolders[3], false)
+	texts[4] = make([]string, len(ns.placeHolders[4]))
+	digits := strconv.Itoa(denominator)
+	for i, placeHolder := range ns.placeHolders[4] {
+		switch {
+		case i < len(digits) && i == len(texts[4])-1:
+			texts[4][i] = digits[i:]
+		case i < len(digits):
+			texts[4][i] = digits[i : i+1]
+		case placeHolder == '?':
+			texts[4][i] = " "
+		}
+	}
+	if hidden {
+		for _, part := range []int{3, 4} {
+			for i := range texts[part] {
+				texts[part][i] = " "
+			}
+		}
+	}
+	return texts, hidden
+}
+
+// approximateFraction returns the numerator and denominator of the best
+// rational approximation for the given non-negative number by the continued
+// fraction expansion, and the denominator will not be greater than the given
+// maximum denominator.
+func approximateFraction(number float64, maxDenominator int) (int, int) {
+	if maxDenominator < 1 {
+		maxDenominator = 1
+	}
+	p0, q0, p1, q1 := 0, 1, 1, 0
+	for x := number; ; {
+		a := math.Floor(x)
+		if q1 > 0 && a > float64(maxDenominator-q0)/float64(q1) {
+			break
+		}
+		p0, q0, p1, q1 = p1, q1, p0+int(a)*p1, q0+int(a)*q1
+		if x-a == 0 || float64(p1)/float64(q1) == number {
+			return p1, q1
+		}
+		x = 1 / (x - a)
+	}
+	// the best semiconvergent which is within the maximum denominator
+	k := (maxDenominator - q0) / q1
+	p2, q2 := p0+k*p1, q0+k*q1
+	if math.Abs(number-float64(p2)/float64(q2)) < math.Abs(number-float64(p1)/float64(q1)) {
+		return p2, q2
+	}
+	return p1, q1
+}
+
+// roundNumber returns the integer and decimal digits of the given
+// non-negative number, which will be rounded to 15 significant digits and
+// the given decimal places with rounding half away from zero.
+func roundNumber(number float64, places int) (string, string) {
+	number, _ = strconv.ParseFloat(strconv.FormatFloat(number, 'g', 15, 64), 64)
+	parts := strings.SplitN(strconv.FormatFloat(number, 'f', -1, 64), ".", 2)
+	integer, decimal := parts[0], ""
+	if len(parts) == 2 {
+		decimal = parts[1]
+	}
+	if len(decimal) <= places {
+		return integer, decimal + strings.Repeat("0", places-len(decimal))
+	}
+	digits := []byte(integer + decimal[:places])
+	if decimal[places] >= '5' {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i < 0 {
+			digits = append([]byte{'1'}, digits...)
+		} else {
+			digits[i]++
+		}
+	}
+	return string(digits[:len(digits)-places]), string(digits[len(digits)-places:])
+}
+
+// fillIntegerPlaceHolders returns the displayed texts of each integer digit
+// placeholder by given integer digits, the digits will be right-aligned with
+// the placeholders, and the extra digits will be displayed at the first
+// placeholder. The placeholder without digit displayed as zero for "0",
+// space for "?" and nothing for "#".
+func fillIntegerPlaceHolders(digits string, placeHolders []byte, grouping bool) []string {
+	texts, offset := make([]string, len(placeHolders)), len(digits)-len(placeHolders)
+	for i, placeHolder := range placeHolders {
+		if idx := offset + i; idx >= 0 {
+			if texts[i] = digits[idx : idx+1]; i == 0 {
+				texts[i] = digits[:idx+1]
+			}
+			continue
+		}
+		texts[i] = getPlaceHolderPadding(placeHolder)
+	}
+	if grouping && len(texts) > 0 {
+		text := strings.Join(texts, "")
+		digits := strings.TrimLeft(text, " ")
+		texts = make([]string, len(placeHolders))
+		texts[0] = text[:len(text)-len(digits)] + printCommaSep(digits)
+	}
+	return texts
+}
+
+// fillDecimalPlaceHolders returns the displayed texts of each decimal digit
+// placeholder by given decimal digits, the trailing zeros displayed as zero
+// for "0", space for "?" and nothing for "#".
+func fillDecimalPlaceHolders(digits string, placeHolders []byte) []string {
+	texts, last := make([]string, len(placeHolders)), len(strings.TrimRight(digits, "0"))
+	for i, placeHolder := range placeHolders {
+		if i < last {
+			texts[i] = digits[i : i+1]
+			continue
+		}
+		texts[i] = getPlaceHolderPadding(placeHolder)
+	}
+	return texts
+}
+
+// getPlaceHolderPadding returns the displayed text of the digit placeholder
+// without digit.
+func getPlaceHolderPadding(placeHolder byte) string {
+	switch placeHolder {
+	case '0':
+		return "0"
+	case '?':
+		return " "
+	}
+	return ""
+}
+
+// getPlaceHolderType returns the token type of the digit placeholders by
+// given placeholders, the digits will be the denominator.
+func getPlaceHolderType(placeHolders string) string {
+	switch placeHolders[0] {
+	case '0':
+		return nfp.TokenTypeZeroPlaceHolder
+	case '#':
+		return nfp.TokenTypeHashPlaceHolder
+	case '?':
+		return nfp.TokenTypeDigitalPlaceHolder
+	}
+	return nfp.TokenTypeDenominator
+}
+
+// isDigitPlaceHolder checks if the given token is a digit placeholder.
+func isDigitPlaceHolder(token nfp.Token) bool {
+	return token.TType == nfp.TokenTypeZeroPlaceHolder ||
+		token.TType == nfp.TokenTypeHashPlaceHolder ||
+		token.TType == nfp.TokenTypeDigitalPlaceHolder
 }
 
 // currencyLanguageHandler will be handling currency and language types tokens for a number
@@ -703,6 +1418,9 @@ func (nf *numberFormat) dateTimesHandler(i int, token nfp.Token) {
 		nf.result += nf.ap
 		return
 	}
+	if nf.erasHandler(i, token) {
+		return
+	}
 	if strings.Contains(strings.ToUpper(token.TValue), "M") {
 		l := len(token.TValue)
 		if l == 1 && !nf.hours && !nf.secondsNext(i) {
@@ -733,6 +1451,43 @@ func (nf *numberFormat) dateTimesHandler(i int, token nfp.Token) {
 	nf.secondsHandler(token)
 }
 
+// erasHandler will be handling eras and years of the era in the date and
+// times types tokens for a number format expression, such as "ggg" and "e",
+// returns false if the token is not an era token. The years of the era will
+// be the years of the Gregorian calendar for the language without eras.
+func (nf *numberFormat) erasHandler(i int, token nfp.Token) bool {
+	tValue := strings.ToLower(token.TValue)
+	if tValue == "" || (tValue[0] != 'e' && tValue[0] != 'g') {
+		return false
+	}
+	if parts := strings.Split(token.TValue, "."); len(parts) > 1 {
+		for idx, part := range parts {
+			if idx > 0 {
+				nf.result += "."
+			}
+			nf.dateTimesHandler(i, nfp.Token{TType: token.TType, TValue: part})
+		}
+		return true
+	}
+	names, year := []string{"", "", ""}, nf.t.Year()
+	for _, era := range supportedLanguageEras[nf.localCode] {
+		if !nf.t.Before(era.start) {
+			names, year = era.names, nf.t.Year()-era.start.Year()+1
+			break
+		}
+	}
+	if tValue[0] == 'g' {
+		nf.result += names[int(math.Min(float64(len(tValue)), 3))-1]
+		return true
+	}
+	if len(tValue) > 1 {
+		nf.result += fmt.Sprintf("%02d", year)
+		return true
+	}
+	nf.result += strconv.Itoa(year)
+	return true
+}
+
 // yearsHandler will be handling years in the date and times types tokens for a
 // number format expression.
 func (nf *numberFormat) yearsHandler(i int, token nfp.Token) {
@@ -833,17 +1588,15 @@ func (nf *numberFormat) secondsHandler(token nfp.Token) {
 // elapsedDateTimesHandler will be handling elapsed date and times types tokens
 // for a number format expression.
 func (nf *numberFormat) elapsedDateTimesHandler(token nfp.Token) {
-	if strings.Contains(strings.ToUpper(token.TValue), "H") {
-		nf.result += fmt.Sprintf("%.f", nf.t.Sub(excel1900Epoc).Hours())
-		return
-	}
-	if strings.Contains(strings.ToUpper(token.TValue), "M") {
-		nf.result += fmt.Sprintf("%.f", nf.t.Sub(excel1900Epoc).Minutes())
-		return
-	}
-	if strings.Contains(strings.ToUpper(token.TValue), "S") {
-		nf.result += fmt.Sprintf("%.f", nf.t.Sub(excel1900Epoc).Seconds())
-		return
+	for _, unit := range []struct {
+		name    string
+		seconds float64
+	}{{"H", 3600}, {"M", 60}, {"S", 1}} {
+		if strings.Contains(strings.ToUpper(token.TValue), unit.name) {
+			nf.hours = unit.name == "H"
+			nf.result += fmt.Sprintf("%0*.f", len(token.TValue), math.Floor(nf.elapsed/unit.seconds))
+			return
+		}
 	}
 }
 
@@ -889,42 +1642,6 @@ func (nf *numberFormat) secondsNext(i int) bool {
 	return false
 }
 
-// negativeHandler will be handling negative selection for a number format
-// expression.
-func (nf *numberFormat) negativeHandler() (result string) {
-	for _, token := range nf.section[nf.sectionIdx].Items {
-		if inStrSlice(supportedTokenTypes, token.TType, true) == -1 || token.TType == nfp.TokenTypeGeneral {
-			result = nf.value
-			return
-		}
-		if token.TType == nfp.TokenTypeLiteral {
-			nf.result += token.TValue
-			continue
-		}
-		if token.TType == nfp.TokenTypeZeroPlaceHolder && token.TValue == strings.Repeat("0", len(token.TValue)) {
-			if isNum, precision, decimal := isNumeric(nf.value); isNum {
-				if math.Abs(nf.number) < 1 {
-					nf.result += "0"
-					continue
-				}
-				if precision > 15 {
-					nf.result += strings.TrimLeft(strconv.FormatFloat(decimal, 'f', -1, 64), "-")
-				} else {
-					nf.result += fmt.Sprintf("%.f", math.Abs(nf.number))
-				}
-				continue
-			}
-		}
-	}
-	result = nf.result
-	return
-}
-
-// zeroHandler will be handling zero selection for a number format expression.
-func (nf *numberFormat) zeroHandler() string {
-	return nf.value
-}
-
 // textHandler will be handling text selection for a number format expression.
 func (nf *numberFormat) textHandler() (result string) {
 	for _, token := range nf.section[nf.sectionIdx].Items {
@@ -954,3 +1671,107 @@ func (nf *numberFormat) getValueSectionType(value string) (float64, string) {
 	}
 	return number, nfp.TokenSectionZero
 }
+
+// String returns the name of the number format category.
+func (c NumberFormatCategory) String() string {
+	if int(c) < len(numberFormatCategoryNames) {
+		return numberFormatCategoryNames[c]
+	}
+	return ""
+}
+
+// getBuiltInNumFmtCategory provides a function to get the category of the
+// built-in number format ID which has no format code in the English
+// localization. The IDs 27 to 36 and 50 to 81 are East Asian date and time
+// formats, and 32 and 33 are time formats in all of these languages.
+func getBuiltInNumFmtCategory(numFmtID int) NumberFormatCategory {
+	switch {
+	case numFmtID >= 5 && numFmtID <= 8:
+		return NumberFormatCategoryCurrency
+	case numFmtID == 32 || numFmtID == 33:
+		return NumberFormatCategoryTime
+	case (numFmtID >= 27 && numFmtID <= 36) || (numFmtID >= 50 && numFmtID <= 81):
+		return NumberFormatCategoryDate
+	}
+	return NumberFormatCategoryGeneral
+}
+
+// getNumFmtCategory provides a function to detect the category of the number
+// format by given number format code. Only the first section of the number
+// format code that applied for positive numbers will be checked.
+func getNumFmtCategory(numFmt string) NumberFormatCategory {
+	if numFmt == "" || strings.EqualFold(numFmt, "general") {
+		return NumberFormatCategoryGeneral
+	}
+	p := nfp.NumberFormatParser()
+	sections := p.Parse(numFmt)
+	if len(sections) == 0 {
+		return NumberFormatCategoryGeneral
+	}
+	var condition, general, date, clock, month, percent, exponential, fraction, currency, placeholder, text bool
+	for _, token := range sections[0].Items {
+		switch token.TType {
+		case nfp.TokenTypeCondition:
+			condition = true
+		case nfp.TokenTypeGeneral:
+			general = true
+		case nfp.TokenTypeDateTimes:
+			value := strings.ToLower(token.TValue)
+			if strings.ContainsAny(value, "ydeg") || strings.HasPrefix(value, "mmm") {
+				date = true
+				break
+			}
+			if strings.ContainsAny(value, "hs/") {
+				clock = true
+				break
+			}
+			month = true
+		case nfp.TokenTypeElapsedDateTimes:
+			clock = true
+		case nfp.TokenTypePercent:
+			percent = true
+		case nfp.TokenTypeExponential:
+			exponential = true
+		case nfp.TokenTypeFraction:
+			fraction = true
+		case nfp.TokenTypeCurrencyLanguage:
+			for _, part := range token.Parts {
+				if part.Token.TType == nfp.TokenSubTypeCurrencyString && part.Token.TValue != "" {
+					currency = true
+				}
+			}
+		case nfp.TokenTypeLiteral:
+			currency = currency || strings.ContainsAny(token.TValue, currencySymbols)
+		case nfp.TokenTypeDigitalPlaceHolder, nfp.TokenTypeHashPlaceHolder, nfp.TokenTypeZeroPlaceHolder:
+			currency = currency || strings.ContainsAny(token.TValue, currencySymbols)
+			placeholder = true
+		case nfp.TokenTypeTextPlaceHolder:
+			text = true
+		}
+	}
+	switch {
+	case condition:
+		return NumberFormatCategoryCustom
+	case date:
+		return NumberFormatCategoryDate
+	case clock:
+		return NumberFormatCategoryTime
+	case month:
+		return NumberFormatCategoryDate
+	case general:
+		return NumberFormatCategoryGeneral
+	case exponential:
+		return NumberFormatCategoryScientific
+	case fraction:
+		return NumberFormatCategoryFraction
+	case percent:
+		return NumberFormatCategoryPercentage
+	case currency && placeholder:
+		return NumberFormatCategoryCurrency
+	case placeholder:
+		return NumberFormatCategoryNumber
+	case text:
+		return NumberFormatCategoryText
+	}
+	return NumberFormatCategoryCustom
+}
@@ -49,9 +49,9 @@ func TestNumFmt(t *testing.T) {
 		{"43543.086539351854", "AM/PM hh:mm:ss a/p", "AM 02:04:37 a"},
 		{"43528", "YYYY", "2019"},
 		{"43528", "", "43528"},
-		{"43528.2123", "YYYY-MM-DD hh:mm:ss", "2019-03-04 05:05:42"},
-		{"43528.2123", "YYYY-MM-DD hh:mm:ss;YYYY-MM-DD hh:mm:ss", "2019-03-04 05:05:42"},
-		{"43528.2123", "M/D/YYYY h:m:s", "3/4/2019 5:5:42"},
+		{"43528.2123", "YYYY-MM-DD hh:mm:ss", "2019-03-04 05:05:43"},
+		{"43528.2123", "YYYY-MM-DD hh:mm:ss;YYYY-MM-DD hh:mm:ss", "2019-03-04 05:05:43"},
+		{"43528.2123", "M/D/YYYY h:m:s", "3/4/2019 5:5:43"},
 		{"43528.003958333335", "m/d/yyyy h:m:s", "3/4/2019 0:5:42"},
 		{"43528.003958333335", "M/D/YYYY h:mm:s", "3/4/2019 0:05:42"},
 		{"0.64583333333333337", "h:mm:ss am/pm", "3:30:00 pm"},
@@ -63,7 +63,7 @@ func TestNumFmt(t *testing.T) {
 		{"0.97952546296296295", "h:m", "23:30"},
 		{"43528", "mmmm", "March"},
 		{"43528", "dddd", "Monday"},
-		{"0", ";;;", "0"},
+		{"0", ";;;", ""},
 		{"43528", "[$-409]MM/DD/YYYY", "03/04/2019"},
 		{"43528", "[$-409]MM/DD/YYYY am/pm", "03/04/2019 AM"},
 		{"43528", "[$-111]MM/DD/YYYY", "43528"},
@@ -1004,8 +1004,146 @@ func TestNumFmt(t *testing.T) {
 		{"-8.0450685976001E+21", "0_);[Red]\\(0\\)", "(8045068597600100000000)"},
 		{"-8.0450685976001E-21", "0_);[Red]\\(0\\)", "(0)"},
 		{"-8.04506", "0_);[Red]\\(0\\)", "(8)"},
+		{"43528", "e", "2019"},
+		{"43528", "g", ""},
+		{"43528", "[$-411]ge.m.d", "H31.3.4"},
+		{"43528", "[$-411]gge", "平31"},
+		{"43528", "[$-411]ee", "31"},
+		{"43528", `[$-411]ggge"年"m"月"d"日"`, "平成31年3月4日"},
+		{"45200", `[$-411]ggggge"年"`, "令和5年"},
+		{"100", "[$-411]ge", "M33"},
+		{"43528", "[$-404]e/m/d", "108/3/4"},
+		{"43528", "[$-404]gge", "中華民國108"},
+		{"100", "[$-404]e", "1900"},
+		{"1234.5678", "0", "1235"},
+		{"1234.5678", "0.00", "1234.57"},
+		{"0.5", "#.##", ".5"},
+		{"12.5", ".00", "12.50"},
+		{"0.5", ".00", ".50"},
+		{"12.5", "??.??", "12.5 "},
+		{"2.5", "#??.??", " 2.5 "},
+		{"1234.5", "0.0*-", "1234.5"},
+		{"-3", "General;(General)", "(3)"},
+		{"-3", "General;[Red](General)", "(3)"},
+		{"1.5", "0.0#", "1.5"},
+		{"1.25", "0.0#", "1.25"},
+		{"12.5", "??0.0?", " 12.5 "},
+		{"1234567.891", "#,##0.00", "1,234,567.89"},
+		{"-1234.5", "$#,##0.00;($#,##0.00)", "($1,234.50)"},
+		{"1234.5", "[$€-2] #,##0.00", "€ 1,234.50"},
+		{"1234567", "#,##0,", "1,235"},
+		{"1234567", "0.0,,", "1.2"},
+		{"0.1234", "0.0%", "12.3%"},
+		{"123456789", "000-00-0000", "123-45-6789"},
+		{"12", "00000", "00012"},
+		{"-5", "0.00", "-5.00"},
+		{"-5", "General\" units\"", "-5 units"},
+		{"0", "0.00;-0.00;\"zero\"", "zero"},
+		{"0", "0;-0;;@", ""},
+		{"1.5", "# ?/?", "1 1/2"},
+		{"0.75", "# ?/?", " 3/4"},
+		{"5", "# ?/?", "5    "},
+		{"0.999", "# ?/?", "1    "},
+		{"3.14159265358979", "# ??/??", "3 14/99"},
+		{"3.14159265358979", "# ???/???", "3  16/113"},
+		{"-1.5", "# ?/?", "-1 1/2"},
+		{"1.25", "?/?", "5/4"},
+		{"0.3", "?/8", "2/8"},
+		{"1.25", "# #/100", "1 25/100"},
+		{"0.5", "0 0/0", "0 1/2"},
+		{"0.3", "# ?/?", " 2/7"},
+		{"0.6", "?/?", "3/5"},
+		{"12", "?/?", "12/1"},
+		{"3.14159265358979", "# ?/?????????", "3 35580937/251290841"},
+		{"3.14159265358979", "# ?/????????????", "3 35580937/251290841   "},
+		{"12345", "0.00E+00", "1.23E+04"},
+		{"0.000123", "0.00E+00", "1.23E-04"},
+		{"9.999", "0.00E+00", "1.00E+01"},
+		{"0", "0.00E+00", "0.00E+00"},
+		{"-12345", "0.0E+0", "-1.2E+4"},
+		{"12345", "##0.0E+0", "12.3E+3"},
+		{"1234567", "##0.0E+0", "1.2E+6"},
+		{"0.00012345", "##0.0E+0", "123.5E-6"},
+		{"999999", "##0.0E+0", "1.0E+6"},
+		{"-5", "[Red][<0]0.00;[Blue][>=100]0.00;0.0", "5.00"},
+		{"150", "[Red][<0]0.00;[Blue][>=100]0.00;0.0", "150.00"},
+		{"50", "[Red][<0]0.00;[Blue][>=100]0.00;0.0", "50.0"},
+		{"50", "[>100]\"big\";[<=100]\"small\"", "small"},
+		{"-5", "[>100]0;0.0", "-5.0"},
+		{"50", "[>100]0", "50"},
+		{"1.5", "[h]:mm:ss", "36:00:00"},
+		{"1.2345", "[h]:mm:ss", "29:37:41"},
+		{"0.0416666666666667", "[hh]:mm", "01:00"},
+		{"0.0123456", "[mm]:ss.00", "17:46.66"},
+		{"0.0123456", "[ss].0", "1066.7"},
+		{"-1.5", "[h]:mm:ss", "-1.5"},
+		{"123", "@", "123"},
+		{"text", "\"Qty: \"@", "Qty: text"},
+		{"text", "0.00;-0.00;0;\"(\"@\")\"", "(text)"},
+		{"text", "0.00", "text"},
 	} {
 		result := format(item[0], item[1], false)
 		assert.Equal(t, item[2], result, item)
 	}
 }
+
+func TestApproximateFraction(t *testing.T) {
+	for _, c := range []struct {
+		number                 float64
+		maxDenominator         int
+		numerator, denominator int
+	}{
+		{0, 9, 0, 1},
+		{0.6, 0, 1, 1},
+		{0.999, 9, 1, 1},
+		{0.3, 9, 2, 7},
+		{0.14159265358979, 99, 14, 99},
+		{0.14159265358979, 999, 16, 113},
+		{3.141592653589793, 999999999, 245850922, 78256779},
+	} {
+		numerator, denominator := approximateFraction(c.number, c.maxDenominator)
+		assert.Equal(t, c.numerator, numerator, c)
+		assert.Equal(t, c.denominator, denominator, c)
+	}
+}
+
+func TestGetNumFmtCategory(t *testing.T) {
+	for numFmt, expected := range map[string]NumberFormatCategory{
+		"":                    NumberFormatCategoryGeneral,
+		"General":             NumberFormatCategoryGeneral,
+		"[Red]General":        NumberFormatCategoryGeneral,
+		"#,##0.00":            NumberFormatCategoryNumber,
+		"0_);[Red]\\(0\\)":    NumberFormatCategoryNumber,
+		"0 \"kg\"":            NumberFormatCategoryNumber,
+		"$#,##0.00":           NumberFormatCategoryCurrency,
+		"[$€-407]#,##0":       NumberFormatCategoryCurrency,
+		"¥#,##0":              NumberFormatCategoryCurrency,
+		"yyyy-mm-dd":          NumberFormatCategoryDate,
+		"[$-409]mmmm d, yyyy": NumberFormatCategoryDate,
+		"m/d/yy hh:mm":        NumberFormatCategoryDate,
+		"mmm":                 NumberFormatCategoryDate,
+		"mm":                  NumberFormatCategoryDate,
+		"h:mm AM/PM":          NumberFormatCategoryTime,
+		"[h]:mm:ss":           NumberFormatCategoryTime,
+		"0.00%":               NumberFormatCategoryPercentage,
+		"# ??/??":             NumberFormatCategoryFraction,
+		"0.00E+00":            NumberFormatCategoryScientific,
+		"@":                   NumberFormatCategoryText,
+		"\"Qty: \"@":          NumberFormatCategoryText,
+		"[>100]0;0":           NumberFormatCategoryCustom,
+		"\"Total\"":           NumberFormatCategoryCustom,
+	} {
+		assert.Equal(t, expected, getNumFmtCategory(numFmt), numFmt)
+	}
+	for numFmtID, expected := range map[int]NumberFormatCategory{
+		7:   NumberFormatCategoryCurrency,
+		27:  NumberFormatCategoryDate,
+		33:  NumberFormatCategoryTime,
+		58:  NumberFormatCategoryDate,
+		100: NumberFormatCategoryGeneral,
+	} {
+		assert.Equal(t, expected, getBuiltInNumFmtCategory(numFmtID), numFmtID)
+	}
+	assert.Equal(t, "Percentage", NumberFormatCategoryPercentage.String())
+	assert.Empty(t, NumberFormatCategory(100).String())
+}
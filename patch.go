@@ -0,0 +1,419 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	zipCentralHeaderSignature    = 0x02014b50
+	zipEndSignature              = 0x06054b50
+	zipEndLen                    = 22
+	zip64EndSignature            = 0x06064b50
+	zip64EndLen                  = 56
+	zip64EndLocatorSignature     = 0x07064b50
+	zip64EndLocatorLen           = 20
+	zipCentralHeaderLen          = 46
+	zipMaxCommentLen             = 0xFFFF
+	zipUint16Max, zipUint32Max   = 0xFFFF, 0xFFFFFFFF
+	zipCentralHeaderNameLenIndex = 28
+)
+
+// zipDirectory directly maps the central directory of the ZIP archive, which
+// records the offset, the size, and the raw file headers in the central
+// directory.
+type zipDirectory struct {
+	offset  int64
+	size    int64
+	headers [][]byte
+}
+
+// zipPatchWriter is a writer for appending the entries to the ZIP archive,
+// the local file headers and data will be written to the underlying writer,
+// and the rest output including the central directory will be kept in the
+// buffer after switched.
+type zipPatchWriter struct {
+	w        io.Writer
+	written  int64
+	buffered bool
+	buf      bytes.Buffer
+}
+
+// Write implements the io.Writer interface.
+func (pw *zipPatchWriter) Write(p []byte) (int, error) {
+	if pw.buffered {
+		return pw.buf.Write(p)
+	}
+	n, err := pw.w.Write(p)
+	pw.written += int64(n)
+	return n, err
+}
+
+// AppendSheet provides a function to append a new worksheet to the existing
+// spreadsheet file on disk without rewriting the whole spreadsheet. The new
+// worksheet will be generated by the stream writer, and the worksheet, the
+// workbook, the workbook relationships and the content types parts will be
+// appended after the existing data of the ZIP archive, followed by a new
+// central directory, so the time cost is only related to the size of the new
+// worksheet. The part names of the new parts, such as the tables added by
+// the stream writer, will be allocated by all the parts in the archive, and
+// an error will be returned if the given function changes any other existing
+// part. The stream writer will be flushed after the given function returned,
+// so don't call the Flush function in it. Note that the space of the
+// replaced parts will not be reclaimed, the encrypted spreadsheet is not
+// supported, and the style index of the cells should be the existing cell
+// style index in the spreadsheet. The existing data will not be overwritten,
+// the file will be truncated to the original size if appending failed, and
+// if the process is interrupted during appending, the original workbook can
+// be recovered by truncating the file to the original size. For example,
+// append a daily worksheet to the archive workbook:
+//
+//	err := excelize.AppendSheet("Archive.xlsx", "2023-06-01", func(sw *excelize.StreamWriter) error {
+//	    for rowID := 1; rowID <= 10000; rowID++ {
+//	        cell, err := excelize.CoordinatesToCellName(1, rowID)
+//	        if err != nil {
+//	            return err
+//	        }
+//	        if err := sw.SetRow(cell, []interface{}{rowID, "data"}); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	})
+func AppendSheet(path, sheet string, fn func(sw *StreamWriter) error) error {
+	if fn == nil {
+		return ErrParameterRequired
+	}
+	if err := checkSheetName(sheet); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Clean(path), os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	fi, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(file, fi.Size())
+	if err != nil {
+		return err
+	}
+	dir, err := readZipDirectory(file, 0, fi.Size())
+	if err != nil {
+		return err
+	}
+	f := newFile()
+	defer f.Close()
+	if err = f.loadZipParts(zr, defaultXMLPathContentTypes, "_rels/.rels"); err != nil {
+		return err
+	}
+	if err = f.loadZipParts(zr, f.getWorkbookPath(), f.getWorkbookRelsPath()); err != nil {
+		return err
+	}
+	if err = f.appendSheet(zr, sheet); err != nil {
+		return err
+	}
+	// Reserve the names of the other existing parts with the empty content,
+	// so that the part names of the new parts will not conflict with them
+	replaceable := map[string]bool{}
+	f.Pkg.Range(func(k, v interface{}) bool {
+		replaceable[strings.ToLower(k.(string))] = true
+		return true
+	})
+	var reserved []string
+	for _, zf := range zr.File {
+		name := strings.ReplaceAll(zf.Name, "\\", "/")
+		if !replaceable[strings.ToLower(name)] {
+			reserved = append(reserved, name)
+			f.Pkg.Store(name, []byte{})
+		}
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	if err = fn(sw); err != nil {
+		return err
+	}
+	if err = sw.Flush(); err != nil {
+		return err
+	}
+	for _, name := range reserved {
+		if content, ok := f.Pkg.Load(name); ok && len(content.([]byte)) == 0 {
+			f.Pkg.Delete(name)
+		}
+	}
+	if err = f.appendZipParts(file, fi.Size(), dir, replaceable); err != nil {
+		_ = file.Truncate(fi.Size())
+		return err
+	}
+	return nil
+}
+
+// appendZipParts provides a function to write the parts of the workbook after
+// the end of the ZIP archive by given size of the archive, and then write the
+// new central directory which merged the existing central directory. Returns
+// an error if any existing part not in the given replaceable parts will be
+// replaced.
+func (f *File) appendZipParts(file *os.File, offset int64, dir *zipDirectory, replaceable map[string]bool) error {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(file)
+	pw := &zipPatchWriter{w: bw}
+	zw := zip.NewWriter(pw)
+	zw.SetOffset(offset)
+	if err := f.writeToZip(zw, nil); err != nil {
+		return err
+	}
+	if err := zw.Flush(); err != nil {
+		return err
+	}
+	pw.buffered = true
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	base := offset + pw.written
+	appended, err := readZipDirectory(bytes.NewReader(pw.buf.Bytes()), base, base+int64(pw.buf.Len()))
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(dir.headers))
+	for _, header := range dir.headers {
+		existing[strings.ToLower(getZipCentralHeaderName(header))] = true
+	}
+	for _, header := range appended.headers {
+		if name := strings.ToLower(getZipCentralHeaderName(header)); existing[name] && !replaceable[name] {
+			return newAppendExistingPartError(getZipCentralHeaderName(header))
+		}
+	}
+	if _, err = bw.Write(pw.buf.Bytes()[:appended.offset-base]); err != nil {
+		return err
+	}
+	if _, err = writeZipDirectory(bw, appended.offset, mergeZipCentralHeaders(dir.headers, appended.headers)); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// loadZipParts provides a function to load the given parts of the ZIP archive
+// into the package of the workbook, the parts not found will be skipped.
+func (f *File) loadZipParts(zr *zip.Reader, names ...string) error {
+	for _, zf := range zr.File {
+		name := strings.ReplaceAll(zf.Name, "\\", "/")
+		if strings.EqualFold(name, defaultXMLPathContentTypes) {
+			name = defaultXMLPathContentTypes
+		}
+		if inStrSlice(names, name, true) == -1 {
+			continue
+		}
+		content, err := readFile(zf)
+		if err != nil {
+			return err
+		}
+		f.Pkg.Store(name, content)
+	}
+	return nil
+}
+
+// appendSheet provides a function to create a new worksheet in the workbook
+// loaded from the ZIP archive, the worksheet part will be named by the
+// available part name in the archive.
+func (f *File) appendSheet(zr *zip.Reader, sheet string) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if _, err = f.relsReader(f.getWorkbookRelsPath()); err != nil {
+		return err
+	}
+	sheetID := 0
+	for _, v := range wb.Sheets.Sheet {
+		if strings.EqualFold(v.Name, sheet) {
+			return ErrExistsSheet
+		}
+		if v.SheetID > sheetID {
+			sheetID = v.SheetID
+		}
+	}
+	sheetID++
+	names := make(map[string]bool, len(zr.File))
+	for _, zf := range zr.File {
+		names[strings.ToLower(strings.ReplaceAll(zf.Name, "\\", "/"))] = true
+	}
+	idx := sheetID
+	for names["xl/worksheets/sheet"+strconv.Itoa(idx)+".xml"] {
+		idx++
+	}
+	if err = f.setContentTypes("/xl/worksheets/sheet"+strconv.Itoa(idx)+".xml", ContentTypeSpreadSheetMLWorksheet); err != nil {
+		return err
+	}
+	f.SheetCount = len(wb.Sheets.Sheet) + 1
+	f.setSheet(idx, sheet)
+	rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipWorkSheet, "/xl/worksheets/sheet"+strconv.Itoa(idx)+".xml", "")
+	f.setWorkbook(sheet, sheetID, rID)
+	return err
+}
+
+// readZipDirectory provides a function to read the central directory of the
+// ZIP archive by the end of central directory record, and the ZIP64 end of
+// central directory record if exists. The reader contains the data of the
+// archive from the given base offset to the end of the archive.
+func readZipDirectory(r io.ReaderAt, base, size int64) (*zipDirectory, error) {
+	readAt := func(p []byte, off int64) error {
+		if off < base {
+			return zip.ErrFormat
+		}
+		_, err := r.ReadAt(p, off-base)
+		return err
+	}
+	tailLen := int64(zipEndLen + zipMaxCommentLen)
+	if tailLen > size-base {
+		tailLen = size - base
+	}
+	tail := make([]byte, tailLen)
+	if err := readAt(tail, size-tailLen); err != nil && err != io.EOF {
+		return nil, err
+	}
+	pos := bytes.LastIndex(tail, []byte{0x50, 0x4b, 0x05, 0x06})
+	if pos == -1 || len(tail)-pos < zipEndLen {
+		return nil, zip.ErrFormat
+	}
+	end := tail[pos:]
+	count := int64(binary.LittleEndian.Uint16(end[10:]))
+	dir := &zipDirectory{
+		size:   int64(binary.LittleEndian.Uint32(end[12:])),
+		offset: int64(binary.LittleEndian.Uint32(end[16:])),
+	}
+	if count == zipUint16Max || dir.size == zipUint32Max || dir.offset == zipUint32Max {
+		locator := make([]byte, zip64EndLocatorLen)
+		if err := readAt(locator, size-tailLen+int64(pos)-zip64EndLocatorLen); err != nil {
+			return nil, err
+		}
+		if binary.LittleEndian.Uint32(locator) != zip64EndLocatorSignature {
+			return nil, zip.ErrFormat
+		}
+		end = make([]byte, zip64EndLen)
+		if err := readAt(end, int64(binary.LittleEndian.Uint64(locator[8:]))); err != nil {
+			return nil, err
+		}
+		if binary.LittleEndian.Uint32(end) != zip64EndSignature {
+			return nil, zip.ErrFormat
+		}
+		count = int64(binary.LittleEndian.Uint64(end[32:]))
+		dir.size = int64(binary.LittleEndian.Uint64(end[40:]))
+		dir.offset = int64(binary.LittleEndian.Uint64(end[48:]))
+	}
+	if dir.offset < base || dir.size < 0 || dir.offset+dir.size > size {
+		return nil, zip.ErrFormat
+	}
+	data := make([]byte, dir.size)
+	if err := readAt(data, dir.offset); err != nil {
+		return nil, err
+	}
+	var err error
+	dir.headers, err = parseZipCentralHeaders(data, count)
+	return dir, err
+}
+
+// parseZipCentralHeaders provides a function to split the central directory
+// into the file headers by given number of the headers.
+func parseZipCentralHeaders(data []byte, count int64) ([][]byte, error) {
+	var headers [][]byte
+	for int64(len(headers)) < count {
+		if len(data) < zipCentralHeaderLen || binary.LittleEndian.Uint32(data) != zipCentralHeaderSignature {
+			return headers, zip.ErrFormat
+		}
+		idx := zipCentralHeaderNameLenIndex
+		size := zipCentralHeaderLen + int(binary.LittleEndian.Uint16(data[idx:])) +
+			int(binary.LittleEndian.Uint16(data[idx+2:])) + int(binary.LittleEndian.Uint16(data[idx+4:]))
+		if len(data) < size {
+			return headers, zip.ErrFormat
+		}
+		headers, data = append(headers, data[:size]), data[size:]
+	}
+	return headers, nil
+}
+
+// getZipCentralHeaderName returns the file name of the file header in the
+// central directory.
+func getZipCentralHeaderName(header []byte) string {
+	nameLen := int(binary.LittleEndian.Uint16(header[zipCentralHeaderNameLenIndex:]))
+	return strings.ReplaceAll(string(header[zipCentralHeaderLen:zipCentralHeaderLen+nameLen]), "\\", "/")
+}
+
+// mergeZipCentralHeaders provides a function to merge the file headers of the
+// existing central directory and the appended entries, the existing file
+// headers replaced by the appended entries with the same name will be
+// removed. The appended entries should only replace the parts loaded by the
+// AppendSheet function.
+func mergeZipCentralHeaders(headers, appended [][]byte) [][]byte {
+	names := make(map[string]bool, len(appended))
+	for _, header := range appended {
+		names[strings.ToLower(getZipCentralHeaderName(header))] = true
+	}
+	var merged [][]byte
+	for _, header := range headers {
+		if !names[strings.ToLower(getZipCentralHeaderName(header))] {
+			merged = append(merged, header)
+		}
+	}
+	return append(merged, appended...)
+}
+
+// writeZipDirectory provides a function to write the central directory by
+// given offset and file headers, and the end of central directory record,
+// the ZIP64 end of central directory record will be written if needed.
+// Returns the number of bytes written.
+func writeZipDirectory(w io.Writer, offset int64, headers [][]byte) (int64, error) {
+	var size int64
+	for _, header := range headers {
+		n, err := w.Write(header)
+		if size += int64(n); err != nil {
+			return size, err
+		}
+	}
+	var (
+		buf     bytes.Buffer
+		records = uint64(len(headers))
+	)
+	write := func(data ...interface{}) {
+		for _, v := range data {
+			_ = binary.Write(&buf, binary.LittleEndian, v)
+		}
+	}
+	endRecords, endSize, endOffset := records, uint64(size), uint64(offset)
+	if records >= zipUint16Max || size >= zipUint32Max || offset >= zipUint32Max {
+		write(uint32(zip64EndSignature), uint64(zip64EndLen-12), uint16(45), uint16(45),
+			uint32(0), uint32(0), records, records, uint64(size), uint64(offset))
+		write(uint32(zip64EndLocatorSignature), uint32(0), uint64(offset+size), uint32(1))
+		endRecords, endOffset = zipUint16Max, zipUint32Max
+		if endSize > zipUint32Max {
+			endSize = zipUint32Max
+		}
+	}
+	write(uint32(zipEndSignature), uint16(0), uint16(0), uint16(endRecords), uint16(endRecords),
+		uint32(endSize), uint32(endOffset), uint16(0))
+	n, err := buf.WriteTo(w)
+	return size + n, err
+}
@@ -0,0 +1,196 @@
+package excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendSheet(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "original"))
+	styleID, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	path := filepath.Join("test", "TestAppendSheet.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+
+	for _, sheet := range []string{"Day1", "Day2"} {
+		assert.NoError(t, AppendSheet(path, sheet, func(sw *StreamWriter) error {
+			if err := sw.SetRow("A1", []interface{}{Cell{StyleID: styleID, Value: "Date"}, "Sales"}); err != nil {
+				return err
+			}
+			for rowID := 2; rowID <= 1000; rowID++ {
+				cell, _ := CoordinatesToCellName(1, rowID)
+				if err := sw.SetRow(cell, []interface{}{sheet, rowID}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	}
+	zr, err := zip.OpenReader(path)
+	assert.NoError(t, err)
+	names := map[string]int{}
+	for _, zf := range zr.File {
+		names[zf.Name]++
+	}
+	assert.NoError(t, zr.Close())
+	for name, count := range names {
+		assert.Equal(t, 1, count, name)
+	}
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet2", "Day1", "Day2"}, f.GetSheetList())
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "original", val)
+	for _, sheet := range []string{"Day1", "Day2"} {
+		rows, err := f.GetRows(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1000)
+		assert.Equal(t, []string{sheet, "1000"}, rows[999])
+		cellStyle, err := f.GetCellStyle(sheet, "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, styleID, cellStyle)
+	}
+	assert.NoError(t, f.Close())
+
+	// Test append sheet with invalid parameters
+	assert.Equal(t, ErrParameterRequired, AppendSheet(path, "Day3", nil))
+	assert.Equal(t, ErrSheetNameInvalid, AppendSheet(path, "Day:3", func(sw *StreamWriter) error { return nil }))
+	assert.Equal(t, ErrExistsSheet, AppendSheet(path, "day1", func(sw *StreamWriter) error { return nil }))
+	// Test append sheet with the function returns error, the file should not
+	// be changed
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, ErrParameterInvalid, AppendSheet(path, "Day3", func(sw *StreamWriter) error { return ErrParameterInvalid }))
+	assert.Equal(t, ErrMaxRows, AppendSheet(path, "Day3", func(sw *StreamWriter) error {
+		return sw.SetRow("A1048577", []interface{}{1})
+	}))
+	changed, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, content, changed)
+	// Test append sheet with not exists file and invalid file
+	assert.Error(t, AppendSheet(filepath.Join("test", "NotExist.xlsx"), "Day3", func(sw *StreamWriter) error { return nil }))
+	assert.NoError(t, os.WriteFile(filepath.Join("test", "TestAppendSheet.txt"), []byte("text"), 0o644))
+	assert.Equal(t, zip.ErrFormat, AppendSheet(filepath.Join("test", "TestAppendSheet.txt"), "Day3", func(sw *StreamWriter) error { return nil }))
+	// Test append sheet with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	path = filepath.Join("test", "TestAppendSheet2.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+	assert.EqualError(t, AppendSheet(path, "Day1", func(sw *StreamWriter) error { return nil }), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestAppendSheetWithTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Value"}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1:B2", &TableOptions{Name: "Orig"}))
+	path := filepath.Join("test", "TestAppendSheetWithTable.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+	original, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, AppendSheet(path, "Day1", func(sw *StreamWriter) error {
+		if err := sw.SetRow("A1", []interface{}{"Date", "Sales"}); err != nil {
+			return err
+		}
+		return sw.AddTable("A1:B2", &TableOptions{Name: "Added"})
+	}))
+	// Test the existing data of the archive will not be overwritten
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, original, content[:len(original)])
+
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	for sheet, name := range map[string]string{"Sheet1": "Orig", "Day1": "Added"} {
+		tables, err := f.GetTables(sheet)
+		assert.NoError(t, err)
+		if assert.Len(t, tables, 1) {
+			assert.Equal(t, name, tables[0].Name)
+		}
+	}
+	assert.NoError(t, f.Close())
+
+	// Test append sheet with an existing part which can't be replaced, the
+	// file should not be changed
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	zr, err := zip.OpenReader(path)
+	assert.NoError(t, err)
+	for _, zf := range zr.File {
+		w, err := zw.Create(zf.Name)
+		assert.NoError(t, err)
+		content, err := readFile(zf)
+		assert.NoError(t, err)
+		_, err = w.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zr.Close())
+	w, err := zw.Create("xl/worksheets/_rels/sheet3.xml.rels")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	assert.EqualError(t, AppendSheet(path, "Day2", func(sw *StreamWriter) error {
+		if err := sw.SetRow("A1", []interface{}{"Date", "Sales"}); err != nil {
+			return err
+		}
+		return sw.AddTable("A1:B2", nil)
+	}), "the part xl/worksheets/_rels/sheet3.xml.rels already exists in the spreadsheet")
+	content, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), content)
+}
+
+func TestZipDirectory(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	_, err := zw.Create("a.xml")
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	dir, err := readZipDirectory(bytes.NewReader(buf.Bytes()), 0, int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, dir.headers, 1)
+	assert.Equal(t, "a.xml", getZipCentralHeaderName(dir.headers[0]))
+	// Test read central directory with invalid ZIP archive
+	for _, data := range [][]byte{
+		{},
+		append(buf.Bytes()[:dir.offset], buf.Bytes()[dir.offset+dir.size:]...),
+		buf.Bytes()[dir.offset+dir.size:],
+	} {
+		_, err = readZipDirectory(bytes.NewReader(data), 0, int64(len(data)))
+		assert.Equal(t, zip.ErrFormat, err)
+	}
+	_, err = parseZipCentralHeaders(dir.headers[0][:zipCentralHeaderLen+1], 1)
+	assert.Equal(t, zip.ErrFormat, err)
+	// Test write and read central directory with ZIP64 end of central
+	// directory record
+	out := new(bytes.Buffer)
+	size, err := writeZipDirectory(out, zipUint32Max, dir.headers)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(out.Len()), size)
+	assert.Equal(t, int64(len(dir.headers[0])+zip64EndLen+zip64EndLocatorLen+zipEndLen), size)
+	end := out.Bytes()[out.Len()-zipEndLen:]
+	assert.Equal(t, uint32(zipUint32Max), binary.LittleEndian.Uint32(end[16:]))
+	dir, err = readZipDirectory(bytes.NewReader(out.Bytes()), zipUint32Max, zipUint32Max+size)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(zipUint32Max), dir.offset)
+	assert.Equal(t, "a.xml", getZipCentralHeaderName(dir.headers[0]))
+	_, err = readZipDirectory(bytes.NewReader(out.Bytes()), 0, size)
+	assert.EqualError(t, err, "EOF")
+}
@@ -13,14 +13,27 @@ package excel
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/xml"
 	"image"
 	"io"
+	"math"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+)
+
+var (
+	// pictureFetchClient defined the default HTTP client with the timeout for
+	// downloading the pictures by the AddPictureFromURL function.
+	pictureFetchClient = &http.Client{Timeout: 30 * time.Second}
+	// pictureFetchSizeLimit defined the maximum size of the picture content
+	// in bytes for the AddPictureFromURL function.
+	pictureFetchSizeLimit int64 = 100 << 20
 )
 
 // parseGraphicOptions provides a function to parse the format settings of
@@ -145,6 +158,13 @@ func parseGraphicOptions(opts *GraphicOptions) *GraphicOptions {
 //
 // The optional parameter "ScaleY" specifies the vertical scale of images,
 // the default value of that is 1.0 which presents 100%.
+//
+// The optional parameter "FitRange" specifies a cell range reference, such as
+// "B2:D8", the image will be placed at the top-left cell of the range and
+// scaled to fit in the range with the intrinsic aspect ratio kept, the
+// parameters "AutoFit", "ScaleX" and "ScaleY" will be ignored when it is set.
+// The ErrParameterInvalid will be returned if the "OffsetX" or "OffsetY" is
+// not less than the width or height of the range.
 func (f *File) AddPicture(sheet, cell, picture string, opts *GraphicOptions) error {
 	var err error
 	// Check picture exists first.
@@ -240,6 +260,159 @@ func (f *File) AddPictureFromBytes(sheet, cell, name, extension string, file []b
 	return err
 }
 
+// AddPictureFromReader provides the method to add picture in a sheet by given
+// picture format set and the reader of the picture content. The image type
+// will be detected by the content automatically, supported image types: EMF,
+// EMZ, GIF, JPEG, PNG, SVG, TIFF, WMF, and WMZ. For example, add a picture
+// which scaled to fit in the cell range B2:D8:
+//
+//	file, err := os.Open("image.png")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.AddPictureFromReader("Sheet1", "B2", file, &excelize.GraphicOptions{
+//	    FitRange: "B2:D8",
+//	}); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) AddPictureFromReader(sheet, cell string, r io.Reader, opts *GraphicOptions) error {
+	file, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	ext, ok := getImageExtension(file)
+	if !ok {
+		return ErrImgExt
+	}
+	return f.AddPictureFromBytes(sheet, cell, "", ext, file, opts)
+}
+
+// AddPictureFromURL provides the method to add picture in a sheet by given
+// picture format set and the URL of the picture. The optional parameter
+// "fetch" specifies the function to get the picture content by given URL,
+// the picture will be downloaded with the HTTP client which timeout is 30
+// seconds when it is nil. The size of the picture content can't exceed 100
+// MB. The image type will be detected by the content automatically. For
+// example:
+//
+//	err := f.AddPictureFromURL("Sheet1", "A2", "https://example.com/logo.png", nil, nil)
+func (f *File) AddPictureFromURL(sheet, cell, url string, fetch func(url string) (io.ReadCloser, error), opts *GraphicOptions) error {
+	if fetch == nil {
+		fetch = fetchURL
+	}
+	body, err := fetch(url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	file, err := io.ReadAll(io.LimitReader(body, pictureFetchSizeLimit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(file)) > pictureFetchSizeLimit {
+		return ErrPictureSize
+	}
+	ext, ok := getImageExtension(file)
+	if !ok {
+		return ErrImgExt
+	}
+	name := url
+	if idx := strings.IndexAny(name, "?#"); idx != -1 {
+		name = name[:idx]
+	}
+	return f.AddPictureFromBytes(sheet, cell, path.Base(name), ext, file, opts)
+}
+
+// fetchURL provides a function to get the content by given URL with the
+// default HTTP client for fetching pictures.
+func fetchURL(url string) (io.ReadCloser, error) {
+	resp, err := pictureFetchClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, newHTTPStatusError(url, resp.Status)
+	}
+	if resp.ContentLength > pictureFetchSizeLimit {
+		_ = resp.Body.Close()
+		return nil, ErrPictureSize
+	}
+	return resp.Body, err
+}
+
+// getImageExtension provides a function to detect the image type by given
+// image content, and returns the extension name of the image.
+func getImageExtension(file []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(file, []byte("\x89PNG\r\n\x1a\n")):
+		return ".png", true
+	case bytes.HasPrefix(file, []byte{0xFF, 0xD8, 0xFF}):
+		return ".jpeg", true
+	case bytes.HasPrefix(file, []byte("GIF87a")), bytes.HasPrefix(file, []byte("GIF89a")):
+		return ".gif", true
+	case bytes.HasPrefix(file, []byte("II*\x00")), bytes.HasPrefix(file, []byte("MM\x00*")):
+		return ".tiff", true
+	case len(file) >= 44 && bytes.HasPrefix(file, []byte{0x01, 0x00, 0x00, 0x00}) && string(file[40:44]) == " EMF":
+		return ".emf", true
+	case bytes.HasPrefix(file, []byte{0xD7, 0xCD, 0xC6, 0x9A}), bytes.HasPrefix(file, []byte{0x01, 0x00, 0x09, 0x00}):
+		return ".wmf", true
+	case bytes.HasPrefix(file, []byte{0x1F, 0x8B}):
+		reader, err := gzip.NewReader(bytes.NewReader(file))
+		if err != nil {
+			return "", false
+		}
+		header := make([]byte, 44)
+		n, _ := io.ReadFull(reader, header)
+		switch ext, ok := getImageExtension(header[:n]); {
+		case ok && ext == ".emf":
+			return ".emz", true
+		case ok && ext == ".wmf":
+			return ".wmz", true
+		}
+		return "", false
+	}
+	if isSVGImage(file) {
+		return ".svg", true
+	}
+	return "", false
+}
+
+// isSVGImage provides a function to check if the given image content is an
+// SVG image. The svg root element should be in the first 1024 bytes of the
+// content, and it can only be preceded by the byte order mark, white spaces,
+// XML declaration, processing instructions, comments and document type
+// declaration.
+func isSVGImage(file []byte) bool {
+	header := bytes.TrimPrefix(file, []byte("\xEF\xBB\xBF"))
+	if len(header) > 1024 {
+		header = header[:1024]
+	}
+	for {
+		header = bytes.TrimLeft(header, " \t\r\n")
+		var end []byte
+		switch {
+		case bytes.HasPrefix(header, []byte("<svg")):
+			return len(header) > 4 && bytes.IndexByte([]byte(" \t\r\n/>"), header[4]) != -1
+		case bytes.HasPrefix(header, []byte("<?")):
+			end = []byte("?>")
+		case bytes.HasPrefix(header, []byte("<!--")):
+			end = []byte("-->")
+		case bytes.HasPrefix(header, []byte("<!")):
+			end = []byte(">")
+		default:
+			return false
+		}
+		idx := bytes.Index(header, end)
+		if idx == -1 {
+			return false
+		}
+		header = header[idx+len(end):]
+	}
+}
+
 // deleteSheetRelationships provides a function to delete relationships in
 // xl/worksheets/_rels/sheet%d.xml.rels by given worksheet name and
 // relationship index.
@@ -325,7 +498,12 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, file, ext string, rID,
 		return err
 	}
 	width, height := img.Width, img.Height
-	if opts.AutoFit {
+	if opts.FitRange != "" {
+		width, height, col, row, err = f.drawingFitRange(sheet, float64(width), float64(height), opts)
+		if err != nil {
+			return err
+		}
+	} else if opts.AutoFit {
 		width, height, col, row, err = f.drawingResize(sheet, cell, float64(width), float64(height), opts)
 		if err != nil {
 			return err
@@ -489,24 +667,36 @@ func (f *File) addContentTypePart(index int, contentType string) error {
 		"drawings": f.setContentTypePartImageExtensions,
 	}
 	partNames := map[string]string{
-		"chart":         "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
-		"chartsheet":    "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
-		"comments":      "/xl/comments" + strconv.Itoa(index) + ".xml",
-		"drawings":      "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
-		"table":         "/xl/tables/table" + strconv.Itoa(index) + ".xml",
-		"pivotTable":    "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
-		"pivotCache":    "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
-		"sharedStrings": "/xl/sharedStrings.xml",
+		"chart":             "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
+		"chartEx":           "/xl/charts/chartEx" + strconv.Itoa(index) + ".xml",
+		"chartsheet":        "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
+		"comments":          "/xl/comments" + strconv.Itoa(index) + ".xml",
+		"customProperty":    "/xl/customProperty" + strconv.Itoa(index) + ".bin",
+		"drawings":          "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
+		"table":             "/xl/tables/table" + strconv.Itoa(index) + ".xml",
+		"pivotTable":        "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
+		"pivotCache":        "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
+		"pivotCacheRecords": "/xl/pivotCache/pivotCacheRecords" + strconv.Itoa(index) + ".xml",
+		"sharedStrings":     "/xl/sharedStrings.xml",
+		"theme":             "/" + defaultXMLPathTheme,
+		"timeline":          "/xl/timelines/timeline" + strconv.Itoa(index) + ".xml",
+		"timelineCache":     "/xl/timelineCaches/timelineCache" + strconv.Itoa(index) + ".xml",
 	}
 	contentTypes := map[string]string{
-		"chart":         ContentTypeDrawingML,
-		"chartsheet":    ContentTypeSpreadSheetMLChartsheet,
-		"comments":      ContentTypeSpreadSheetMLComments,
-		"drawings":      ContentTypeDrawing,
-		"table":         ContentTypeSpreadSheetMLTable,
-		"pivotTable":    ContentTypeSpreadSheetMLPivotTable,
-		"pivotCache":    ContentTypeSpreadSheetMLPivotCacheDefinition,
-		"sharedStrings": ContentTypeSpreadSheetMLSharedStrings,
+		"chart":             ContentTypeDrawingML,
+		"chartEx":           ContentTypeChartEx,
+		"chartsheet":        ContentTypeSpreadSheetMLChartsheet,
+		"comments":          ContentTypeSpreadSheetMLComments,
+		"customProperty":    ContentTypeSpreadSheetMLCustomProperty,
+		"drawings":          ContentTypeDrawing,
+		"table":             ContentTypeSpreadSheetMLTable,
+		"pivotTable":        ContentTypeSpreadSheetMLPivotTable,
+		"pivotCache":        ContentTypeSpreadSheetMLPivotCacheDefinition,
+		"pivotCacheRecords": ContentTypeSpreadSheetMLPivotCacheRecords,
+		"sharedStrings":     ContentTypeSpreadSheetMLSharedStrings,
+		"theme":             ContentTypeTheme,
+		"timeline":          ContentTypeTimeline,
+		"timelineCache":     ContentTypeTimelineCache,
 	}
 	s, ok := setContentType[contentType]
 	if ok {
@@ -600,6 +790,108 @@ func (f *File) GetPicture(sheet, cell string) (string, []byte, error) {
 	return f.getPicture(row, col, drawingXML, drawingRelationships)
 }
 
+// ExportPictures provides a function to export all pictures in the worksheet
+// by given worksheet name as the standalone files. The pictures will be
+// returned in the order of the drawing objects, with the top-left cell
+// reference, the original file names and content types of the image parts in
+// the workbook package. For example, export the pictures in the worksheet
+// named Sheet1 to the files:
+//
+//	pics, err := f.ExportPictures("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, pic := range pics {
+//	    if err := os.WriteFile(pic.Part.Name, pic.Part.Content, 0644); err != nil {
+//	        fmt.Println(err)
+//	    }
+//	}
+func (f *File) ExportPictures(sheet string) ([]ExportedPicture, error) {
+	var pics []ExportedPicture
+	drawingXML, err := f.getSheetDrawingXML(sheet)
+	if err != nil || drawingXML == "" {
+		return pics, err
+	}
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return pics, err
+	}
+	drawingRels := "xl/drawings/_rels/" + path.Base(drawingXML) + ".rels"
+	wsDr.Lock()
+	defer wsDr.Unlock()
+	for _, anchors := range [][]*xdrCellAnchor{wsDr.TwoCellAnchor, wsDr.OneCellAnchor} {
+		for _, anchor := range anchors {
+			var (
+				col, row int
+				rID      string
+			)
+			if anchor.Pic != nil {
+				if anchor.From != nil {
+					col, row = anchor.From.Col, anchor.From.Row
+				}
+				rID = anchor.Pic.BlipFill.Blip.Embed
+			} else {
+				deAnchor := new(decodeTwoCellAnchor)
+				if err = f.xmlNewDecoder(strings.NewReader("<decodeTwoCellAnchor>" + anchor.GraphicFrame + "</decodeTwoCellAnchor>")).
+					Decode(deAnchor); err != nil && err != io.EOF {
+					return pics, err
+				}
+				if err = nil; deAnchor.Pic == nil {
+					continue
+				}
+				if deAnchor.From != nil {
+					col, row = deAnchor.From.Col, deAnchor.From.Row
+				}
+				rID = deAnchor.Pic.BlipFill.Blip.Embed
+			}
+			drawRel := f.getDrawingRelationships(drawingRels, rID)
+			if drawRel == nil || drawRel.TargetMode == "External" {
+				continue
+			}
+			part, err := f.exportPart(getRelationshipsPartPath(path.Dir(drawingXML), drawRel.Target))
+			if err != nil {
+				return pics, err
+			}
+			cell, _ := CoordinatesToCellName(col+1, row+1)
+			pics = append(pics, ExportedPicture{Cell: cell, Part: part})
+		}
+	}
+	return pics, err
+}
+
+// exportPart provides a function to get the part of the workbook package as
+// the standalone file by given path of the part.
+func (f *File) exportPart(name string) (ExportedPart, error) {
+	contentType, err := f.getContentType(name)
+	return ExportedPart{Name: path.Base(name), ContentType: contentType, Content: f.readBytes(name)}, err
+}
+
+// getContentType provides a function to get the content type of the part by
+// given path of the part in the workbook package. The override content type
+// of the part takes precedence over the default content type of the file
+// extension.
+func (f *File) getContentType(name string) (string, error) {
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return "", err
+	}
+	content.Lock()
+	defer content.Unlock()
+	for _, override := range content.Overrides {
+		if strings.TrimPrefix(override.PartName, "/") == name {
+			return override.ContentType, err
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	for _, def := range content.Defaults {
+		if strings.EqualFold(def.Extension, ext) {
+			return def.ContentType, err
+		}
+	}
+	return "", err
+}
+
 // DeletePicture provides a function to delete charts in spreadsheet by given
 // worksheet name and cell reference. Note that the image file won't be deleted
 // from the document currently.
@@ -771,3 +1063,31 @@ func (f *File) drawingResize(sheet, cell string, width, height float64, opts *Gr
 	w, h = int(width*opts.ScaleX), int(height*opts.ScaleY)
 	return
 }
+
+// drawingFitRange calculate the height and width after scaling to fit in the
+// cell range.
+func (f *File) drawingFitRange(sheet string, width, height float64, opts *GraphicOptions) (w, h, c, r int, err error) {
+	var rng []int
+	if rng, err = rangeRefToCoordinates(opts.FitRange); err != nil {
+		return
+	}
+	_ = sortCoordinates(rng)
+	var rangeWidth, rangeHeight int
+	for col := rng[0]; col <= rng[2]; col++ {
+		rangeWidth += f.getColWidth(sheet, col)
+	}
+	for row := rng[1]; row <= rng[3]; row++ {
+		rangeHeight += f.getRowHeight(sheet, row)
+	}
+	c, r = rng[0], rng[1]
+	if opts.OffsetX >= rangeWidth || opts.OffsetY >= rangeHeight {
+		err = ErrParameterInvalid
+		return
+	}
+	if width == 0 || height == 0 {
+		return
+	}
+	scale := math.Min(float64(rangeWidth-opts.OffsetX)/width, float64(rangeHeight-opts.OffsetY)/height)
+	w, h = int(width*scale), int(height*scale)
+	return
+}
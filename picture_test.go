@@ -1,16 +1,20 @@
 package excel
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 	
 	_ "golang.org/x/image/tiff"
 	
@@ -279,3 +283,155 @@ func TestAddContentTypePart(t *testing.T) {
 	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
 	assert.EqualError(t, f.addContentTypePart(0, "unknown"), "XML syntax error on line 1: invalid UTF-8")
 }
+
+func TestAddPictureFromReader(t *testing.T) {
+	f := NewFile()
+	file, err := os.Open(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	defer file.Close()
+	assert.NoError(t, f.AddPictureFromReader("Sheet1", "A1", file, &GraphicOptions{FitRange: "D8:B2"}))
+	drawing, ok := f.Drawings.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	anchor := drawing.(*xlsxWsDr).TwoCellAnchor[0]
+	assert.Equal(t, 1, anchor.From.Col)
+	assert.Equal(t, 1, anchor.From.Row)
+	assert.Equal(t, []int{4, 0, 7}, []int{anchor.To.Col, anchor.To.ColOff, anchor.To.Row})
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPictureFromReader.xlsx")))
+	// Test add picture with unsupported image content
+	assert.Equal(t, ErrImgExt, f.AddPictureFromReader("Sheet1", "A1", strings.NewReader("text"), nil))
+	// Test add picture with read error
+	assert.Equal(t, iotest.ErrTimeout, f.AddPictureFromReader("Sheet1", "A1", iotest.ErrReader(iotest.ErrTimeout), nil))
+	// Test add picture with invalid fit range
+	assert.NoError(t, file.Close())
+	file, err = os.Open(filepath.Join("test", "images", "excel.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.AddPictureFromReader("Sheet1", "A1", file, &GraphicOptions{FitRange: "A:B"}))
+	// Test add picture with the offset exceeds the fit range
+	img, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	for _, opts := range []*GraphicOptions{
+		{FitRange: "B2:D8", OffsetX: 192},
+		{FitRange: "B2:D8", OffsetY: 200},
+	} {
+		assert.Equal(t, ErrParameterInvalid, f.AddPictureFromReader("Sheet1", "A1", bytes.NewReader(img), opts))
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestAddPictureFromURL(t *testing.T) {
+	png, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/excel.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(png)
+	}))
+	defer server.Close()
+	f := NewFile()
+	assert.NoError(t, f.AddPictureFromURL("Sheet1", "A1", server.URL+"/excel.png?size=1", nil, nil))
+	name, _, err := f.GetPicture("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "image1.png", name)
+	assert.EqualError(t, f.AddPictureFromURL("Sheet1", "A1", server.URL+"/404.png", nil, nil), newHTTPStatusError(server.URL+"/404.png", "404 Not Found").Error())
+	// Test add picture with custom fetch function
+	fetch := func(url string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join("test", "images", strings.TrimPrefix(url, "images://")))
+	}
+	assert.NoError(t, f.AddPictureFromURL("Sheet1", "H1", "images://excel.gif", fetch, nil))
+	assert.Error(t, f.AddPictureFromURL("Sheet1", "H1", "images://missing.gif", fetch, nil))
+	assert.Equal(t, ErrImgExt, f.AddPictureFromURL("Sheet1", "H1", "text", func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("text")), nil
+	}, nil))
+	assert.Equal(t, iotest.ErrTimeout, f.AddPictureFromURL("Sheet1", "H1", "text", func(string) (io.ReadCloser, error) {
+		return io.NopCloser(iotest.ErrReader(iotest.ErrTimeout)), nil
+	}, nil))
+	_, err = fetchURL("http://[::1]:namedport")
+	assert.Error(t, err)
+	// Test add picture with the content exceeds the size limit
+	limit := pictureFetchSizeLimit
+	pictureFetchSizeLimit = int64(len(png) - 1)
+	assert.Equal(t, ErrPictureSize, f.AddPictureFromURL("Sheet1", "H1", server.URL+"/excel.png", nil, nil))
+	assert.Equal(t, ErrPictureSize, f.AddPictureFromURL("Sheet1", "H1", "excel.png", func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(png)), nil
+	}, nil))
+	pictureFetchSizeLimit = limit
+	assert.NoError(t, f.Close())
+}
+
+func TestGetImageExtension(t *testing.T) {
+	for name, expected := range map[string]string{
+		"excel.emf": ".emf", "excel.emz": ".emz", "excel.gif": ".gif",
+		"excel.jpg": ".jpeg", "excel.png": ".png", "excel.tif": ".tiff",
+		"excel.wmf": ".wmf", "excel.wmz": ".wmz",
+	} {
+		file, err := os.ReadFile(filepath.Join("test", "images", name))
+		assert.NoError(t, err)
+		ext, ok := getImageExtension(file)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, ext, name)
+	}
+	file, err := os.ReadFile("excelize.svg")
+	assert.NoError(t, err)
+	ext, ok := getImageExtension(file)
+	assert.True(t, ok)
+	assert.Equal(t, ".svg", ext)
+	for _, file := range []string{
+		"<svg xmlns=\"http://www.w3.org/2000/svg\"/>",
+		"\xEF\xBB\xBF \n<?xml version=\"1.0\"?><!-- <a> --><!DOCTYPE svg><svg>",
+	} {
+		assert.True(t, isSVGImage([]byte(file)), file)
+	}
+	for _, file := range []string{
+		"text <svg>", "<svgx>", "<svg", "<html><svg></svg></html>", "<!-- <svg>",
+		"<?xml version=\"1.0\"?>" + strings.Repeat(" ", 1024) + "<svg>",
+	} {
+		assert.False(t, isSVGImage([]byte(file)), file)
+	}
+	for _, file := range [][]byte{nil, {0x1F, 0x8B}, {0x1F, 0x8B, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}} {
+		_, ok = getImageExtension(file)
+		assert.False(t, ok)
+	}
+}
+
+func TestExportPictures(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddPicture("Sheet1", "B2", filepath.Join("test", "images", "excel.png"), nil))
+	assert.NoError(t, f.AddPicture("Sheet1", "D4", filepath.Join("test", "images", "excel.jpg"), &GraphicOptions{Positioning: "oneCell"}))
+	check := func(pics []ExportedPicture) {
+		assert.Len(t, pics, 2)
+		assert.Equal(t, "B2", pics[0].Cell)
+		assert.Equal(t, ExportedPart{Name: "image1.png", ContentType: "image/png", Content: f.readBytes("xl/media/image1.png")}, pics[0].Part)
+		assert.Equal(t, "D4", pics[1].Cell)
+		assert.Equal(t, "image2.jpeg", pics[1].Part.Name)
+		assert.Equal(t, "image/jpeg", pics[1].Part.ContentType)
+	}
+	pics, err := f.ExportPictures("Sheet1")
+	assert.NoError(t, err)
+	check(pics)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestExportPictures.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test export pictures after reopening the workbook
+	f, err = OpenFile(filepath.Join("test", "TestExportPictures.xlsx"))
+	assert.NoError(t, err)
+	pics, err = f.ExportPictures("Sheet1")
+	assert.NoError(t, err)
+	check(pics)
+	// Test export pictures in the worksheet without pictures
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	pics, err = f.ExportPictures("Sheet2")
+	assert.NoError(t, err)
+	assert.Empty(t, pics)
+	// Test export pictures in not exist worksheet
+	_, err = f.ExportPictures("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test export pictures with unsupported charset drawing
+	f.Drawings.Delete("xl/drawings/drawing1.xml")
+	f.Pkg.Store("xl/drawings/drawing1.xml", MacintoshCyrillicCharset)
+	_, err = f.ExportPictures("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
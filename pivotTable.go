@@ -12,10 +12,15 @@
 package excel
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"math"
+	"path"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // PivotTableOptions directly maps the format settings of the pivot table.
@@ -29,6 +34,7 @@ type PivotTableOptions struct {
 	pivotTableSheetName string
 	DataRange           string
 	PivotTableRange     string
+	Name                string
 	Rows                []PivotTableField
 	Columns             []PivotTableField
 	Data                []PivotTableField
@@ -47,6 +53,8 @@ type PivotTableOptions struct {
 	ShowColStripes      bool
 	ShowLastColumn      bool
 	PivotTableStyleName string
+	CalculatedFields    []PivotTableCalculatedField
+	CalculatedItems     []PivotTableCalculatedItem
 }
 
 // PivotTableField directly maps the field settings of the pivot table.
@@ -68,6 +76,9 @@ type PivotTableOptions struct {
 //
 // Name specifies the name of the data field. Maximum 255 characters
 // are allowed in data field name, excess characters will be truncated.
+//
+// Group specifies the grouping settings of the row or column field, the
+// numeric or date values of the field will be grouped by the range.
 type PivotTableField struct {
 	Compact         bool
 	Data            string
@@ -75,11 +86,62 @@ type PivotTableField struct {
 	Outline         bool
 	Subtotal        string
 	DefaultSubtotal bool
+	Group           *PivotTableFieldGroup
+}
+
+// PivotTableFieldGroup directly maps the grouping settings of the row or
+// column field of the pivot table. By specifies the grouping type, the
+// possible values for this attribute are:
+//
+//	range
+//	months
+//	quarters
+//	years
+//
+// The 'range' groups the numeric values by the fixed-size bins, Start and End
+// specify the range of the bins, the default range is from the minimum to the
+// maximum value of the field, and Interval specifies the size of the bins, the
+// default value is 1. The Interval can't be negative, and the number of the
+// group items can't exceed 1048576. The 'months', 'quarters' and 'years' group
+// the date values of the field, and the range of the groups is from the
+// earliest to the latest date of the field.
+type PivotTableFieldGroup struct {
+	By       string
+	Start    *float64
+	End      *float64
+	Interval float64
+}
+
+// PivotTableCalculatedField directly maps the calculated field settings of
+// the pivot table. Name specifies the name of the calculated field, which can
+// be used as the 'Data' of the data fields, and Formula specifies the formula
+// of the calculated field, which refers to the other fields by the field
+// names, for example "=Revenue-Cost". The field names which contain spaces
+// should be enclosed in single quotes, for example "='Unit Price'*Quantity".
+type PivotTableCalculatedField struct {
+	Name    string
+	Formula string
+}
+
+// PivotTableCalculatedItem directly maps the calculated item settings of the
+// pivot table. Field specifies the name of the row or column field which the
+// calculated item belongs to, Name specifies the name of the calculated item,
+// and Formula specifies the formula of the calculated item, which refers to
+// the other items of the field by the item names, for example "=East+West".
+type PivotTableCalculatedItem struct {
+	Field   string
+	Name    string
+	Formula string
 }
 
 // AddPivotTable provides the method to add pivot table by given pivot table
 // options. Note that the same fields can not in Columns, Rows and Filter
-// fields at the same time.
+// fields at the same time. The optional 'Name' specifies the name of the pivot
+// table, the default name is 'Pivot Table' with a sequence number. The
+// optional 'CalculatedFields' specifies the formula based fields, which will
+// be shown in the field list of the pivot table after the fields of the data
+// source, and the optional 'CalculatedItems' specifies the formula based
+// items of the row or column fields.
 //
 // For example, create a pivot table on the range reference Sheet1!$G$2:$M$34
 // with the range reference Sheet1!$A$1:$E$31 as the data source, summarize by
@@ -134,6 +196,22 @@ type PivotTableField struct {
 //	        fmt.Println(err)
 //	    }
 //	}
+//
+// Add the calculated field named Commission and the calculated item named
+// East+West of the Region field for the pivot table in the example above:
+//
+//	err := f.AddPivotTable(&excelize.PivotTableOptions{
+//	    DataRange:       "Sheet1!$A$1:$E$31",
+//	    PivotTableRange: "Sheet1!$G$2:$M$34",
+//	    Rows:            []excelize.PivotTableField{{Data: "Region"}},
+//	    Data:            []excelize.PivotTableField{{Data: "Commission"}},
+//	    CalculatedFields: []excelize.PivotTableCalculatedField{
+//	        {Name: "Commission", Formula: "=Sales*0.05"},
+//	    },
+//	    CalculatedItems: []excelize.PivotTableCalculatedItem{
+//	        {Field: "Region", Name: "East+West", Formula: "=East+West"},
+//	    },
+//	})
 func (f *File) AddPivotTable(opts *PivotTableOptions) error {
 	// parameter validation
 	_, pivotTableSheetPath, err := f.parseFormatPivotTableSet(opts)
@@ -198,7 +276,45 @@ func (f *File) parseFormatPivotTableSet(opts *PivotTableOptions) (*xlsxWorksheet
 	if !ok {
 		return dataSheet, pivotTableSheetPath, fmt.Errorf("sheet %s does not exist", pivotTableSheetName)
 	}
-	return dataSheet, pivotTableSheetPath, err
+	if err = f.checkPivotTableCalculations(opts); err != nil {
+		return dataSheet, pivotTableSheetPath, err
+	}
+	return dataSheet, pivotTableSheetPath, f.checkPivotTableFieldGroups(opts)
+}
+
+// checkPivotTableFieldGroups provides a function to validate the grouping
+// settings of the fields by given pivot table options. Only the row and column
+// fields of the data source without calculated items can be grouped.
+func (f *File) checkPivotTableFieldGroups(opts *PivotTableOptions) error {
+	// data range has been checked
+	order, _ := f.getPivotFieldsOrder(opts)
+	sourceFields := order[:len(order)-len(opts.CalculatedFields)]
+	for _, fields := range [][]PivotTableField{opts.Filter, opts.Data} {
+		for _, field := range fields {
+			if field.Group != nil {
+				return ErrParameterInvalid
+			}
+		}
+	}
+	for _, fields := range [][]PivotTableField{opts.Rows, opts.Columns} {
+		for _, field := range fields {
+			group := field.Group
+			if group == nil {
+				continue
+			}
+			if inStrSlice(sourceFields, field.Data, true) == -1 ||
+				inStrSlice([]string{"range", "months", "quarters", "years"}, group.By, true) == -1 ||
+				group.Interval < 0 || (group.Start != nil && group.End != nil && *group.Start > *group.End) {
+				return ErrParameterInvalid
+			}
+			for _, item := range opts.CalculatedItems {
+				if item.Field == field.Data {
+					return ErrParameterInvalid
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // adjustRange adjust range, for example: adjust Sheet1!$E$31:$A$1 to Sheet1!$A$1:$E$31
@@ -251,9 +367,292 @@ func (f *File) getPivotFieldsOrder(opts *PivotTableOptions) ([]string, error) {
 		}
 		order = append(order, name)
 	}
+	for _, field := range opts.CalculatedFields {
+		order = append(order, field.Name)
+	}
 	return order, nil
 }
 
+// checkPivotTableCalculations provides a function to validate the calculated
+// fields and the calculated items of the pivot table by given pivot table
+// options.
+func (f *File) checkPivotTableCalculations(opts *PivotTableOptions) error {
+	order, err := f.getPivotFieldsOrder(opts)
+	if err != nil {
+		return err
+	}
+	sourceFields := order[:len(order)-len(opts.CalculatedFields)]
+	for idx, field := range opts.CalculatedFields {
+		if field.Name == "" || field.Formula == "" {
+			return ErrParameterRequired
+		}
+		if inStrSlice(order[:len(sourceFields)+idx], field.Name, false) != -1 {
+			return ErrParameterInvalid
+		}
+	}
+	for _, item := range opts.CalculatedItems {
+		if item.Name == "" || item.Formula == "" {
+			return ErrParameterRequired
+		}
+		if inStrSlice(sourceFields, item.Field, true) == -1 ||
+			(inPivotTableField(opts.Rows, item.Field) == -1 && inPivotTableField(opts.Columns, item.Field) == -1) {
+			return ErrParameterInvalid
+		}
+	}
+	return err
+}
+
+// getPivotSourceValues provides a function to get the values of the cells in
+// the data source range of the pivot table by given pivot table options.
+func (f *File) getPivotSourceValues(opts *PivotTableOptions) ([][]pivotCacheValue, error) {
+	dataRange := f.getDefinedNameRefTo(opts.DataRange, opts.pivotTableSheetName)
+	if dataRange == "" {
+		dataRange = opts.DataRange
+	}
+	dataSheet, coordinates, err := f.adjustRange(dataRange)
+	if err != nil {
+		return nil, err
+	}
+	return f.getPivotCacheValues(dataSheet, coordinates)
+}
+
+// getPivotFieldGroups provides a function to get the cache fields of the
+// grouped row and column fields by given pivot table options and the order of
+// the pivot table fields.
+func (f *File) getPivotFieldGroups(opts *PivotTableOptions, order []string) (map[int]*xlsxCacheField, error) {
+	cacheFields := make(map[int]*xlsxCacheField)
+	var values [][]pivotCacheValue
+	for _, field := range append(append([]PivotTableField{}, opts.Rows...), opts.Columns...) {
+		fieldIdx := inStrSlice(order, field.Data, true)
+		if field.Group == nil || fieldIdx == -1 {
+			continue
+		}
+		if values == nil {
+			var err error
+			if values, err = f.getPivotSourceValues(opts); err != nil {
+				return cacheFields, err
+			}
+		}
+		if len(values) == 0 || fieldIdx >= len(values[0]) {
+			return cacheFields, ErrParameterInvalid
+		}
+		column := make([]pivotCacheValue, len(values)-1)
+		for row := range column {
+			column[row] = values[row+1][fieldIdx]
+		}
+		cacheField := &xlsxCacheField{Name: field.Data}
+		var err error
+		if cacheField.SharedItems, cacheField.FieldGroup, _, err = f.newPivotFieldGroup(column, fieldIdx, *field.Group); err != nil {
+			return cacheFields, err
+		}
+		if field.Group.By != "range" {
+			cacheField.NumFmtID = 14
+		}
+		cacheFields[fieldIdx] = cacheField
+	}
+	return cacheFields, nil
+}
+
+// newPivotFieldGroup provides a function to create the shared items, the
+// field group and the values of the records of the grouped cache field by
+// given values of the field, the index of the field and the grouping
+// settings. The values of the field must be numbers or blanks.
+func (f *File) newPivotFieldGroup(values []pivotCacheValue, fieldIdx int, group PivotTableFieldGroup) (*xlsxSharedItems, *xlsxFieldGroup, []xlsxPivotCacheRecordValue, error) {
+	var (
+		minVal, maxVal float64
+		hasValue       bool
+	)
+	for _, value := range values {
+		if value.blank {
+			continue
+		}
+		if !value.number {
+			return nil, nil, nil, ErrParameterInvalid
+		}
+		num, _ := strconv.ParseFloat(value.value, 64)
+		if !hasValue || num < minVal {
+			minVal = num
+		}
+		if !hasValue || num > maxVal {
+			maxVal = num
+		}
+		hasValue = true
+	}
+	if group.By == "range" {
+		fieldGroup, err := newPivotFieldRangeGroup(fieldIdx, minVal, maxVal, group)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		sharedItems, records := newPivotCacheSharedItems(values)
+		return sharedItems, fieldGroup, records, nil
+	}
+	var date1904 bool
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if wb != nil && wb.WorkbookPr != nil {
+		date1904 = wb.WorkbookPr.Date1904
+	}
+	var (
+		layout      = "2006-01-02T15:04:05"
+		startDate   = timeFromExcelTime(minVal, date1904)
+		endDate     = timeFromExcelTime(maxVal, date1904)
+		sharedItems = &xlsxSharedItems{
+			ContainsSemiMixedTypes: boolPtr(false),
+			ContainsNonDate:        boolPtr(false),
+			ContainsDate:           true,
+			ContainsString:         boolPtr(false),
+			MinDate:                startDate.Format(layout),
+			MaxDate:                endDate.Format(layout),
+		}
+		records = make([]xlsxPivotCacheRecordValue, len(values))
+	)
+	for i, value := range values {
+		if value.blank {
+			sharedItems.ContainsBlank = true
+			records[i] = xlsxPivotCacheRecordValue{XMLName: xml.Name{Local: "m"}}
+			continue
+		}
+		num, _ := strconv.ParseFloat(value.value, 64)
+		records[i] = xlsxPivotCacheRecordValue{XMLName: xml.Name{Local: "d"}, V: timeFromExcelTime(num, date1904).Format(layout)}
+	}
+	endYear, endDate := endDate.Year(), endDate.AddDate(0, 0, 1)
+	items := []string{"<" + startDate.Format("1/2/2006")}
+	switch group.By {
+	case "months":
+		for month := time.January; month <= time.December; month++ {
+			items = append(items, month.String()[:3])
+		}
+	case "quarters":
+		items = append(items, "Qtr1", "Qtr2", "Qtr3", "Qtr4")
+	default:
+		for year := startDate.Year(); year <= endYear; year++ {
+			items = append(items, strconv.Itoa(year))
+		}
+	}
+	items = append(items, ">"+endDate.Format("1/2/2006"))
+	return sharedItems, &xlsxFieldGroup{
+		Base: intPtr(fieldIdx),
+		RangePr: &xlsxRangePr{
+			GroupBy:   group.By,
+			StartDate: startDate.Format(layout),
+			EndDate:   endDate.Format(layout),
+		},
+		GroupItems: newPivotGroupItems(items),
+	}, records, nil
+}
+
+// newPivotFieldRangeGroup provides a function to create the field group which
+// groups the numeric values by the fixed-size bins by given index of the
+// field, the minimum and maximum value of the field and the grouping
+// settings. The number of the group items is limited by the maximum number of
+// the unique items in a pivot field.
+func newPivotFieldRangeGroup(fieldIdx int, minVal, maxVal float64, group PivotTableFieldGroup) (*xlsxFieldGroup, error) {
+	if group.Interval < 0 || math.IsNaN(group.Interval) || math.IsInf(group.Interval, 0) {
+		return nil, ErrParameterInvalid
+	}
+	rangePr := &xlsxRangePr{StartNum: &minVal, EndNum: &maxVal, GroupInterval: group.Interval}
+	if group.Start != nil {
+		rangePr.AutoStart, rangePr.StartNum = boolPtr(false), group.Start
+	}
+	if group.End != nil {
+		rangePr.AutoEnd, rangePr.EndNum = boolPtr(false), group.End
+	}
+	if rangePr.GroupInterval == 0 {
+		rangePr.GroupInterval = 1
+	}
+	start, end, interval := *rangePr.StartNum, *rangePr.EndNum, rangePr.GroupInterval
+	if math.IsNaN(start) || math.IsInf(start, 0) || math.IsNaN(end) || math.IsInf(end, 0) {
+		return nil, ErrParameterInvalid
+	}
+	var bins int
+	if end >= start {
+		count := math.Floor((end-start)/interval) + 1
+		if count+2 > TotalRows {
+			return nil, ErrPivotGroupItems
+		}
+		bins = int(count)
+	}
+	integer := start == math.Trunc(start) && interval == math.Trunc(interval)
+	items := []string{"<" + strconv.FormatFloat(start, 'f', -1, 64)}
+	for i := 0; i < bins; i++ {
+		bin := start + float64(i)*interval
+		upper := bin + interval
+		if integer {
+			upper--
+		}
+		items = append(items, strconv.FormatFloat(bin, 'f', -1, 64)+"-"+strconv.FormatFloat(upper, 'f', -1, 64))
+	}
+	items = append(items, ">"+strconv.FormatFloat(end, 'f', -1, 64))
+	return &xlsxFieldGroup{Base: intPtr(fieldIdx), RangePr: rangePr, GroupItems: newPivotGroupItems(items)}, nil
+}
+
+// newPivotGroupItems provides a function to create the group items of the
+// field group by given names of the group items.
+func newPivotGroupItems(items []string) *xlsxGroupItems {
+	groupItems := &xlsxGroupItems{Count: len(items)}
+	for _, item := range items {
+		groupItems.S = append(groupItems.S, &xlsxString{V: item})
+	}
+	return groupItems
+}
+
+// getPivotFieldGroupOptions provides a function to get the grouping settings
+// of the field by given field group of the cache field.
+func getPivotFieldGroupOptions(fieldGroup *xlsxFieldGroup) *PivotTableFieldGroup {
+	if fieldGroup == nil || fieldGroup.RangePr == nil {
+		return nil
+	}
+	rangePr := fieldGroup.RangePr
+	group := &PivotTableFieldGroup{By: rangePr.GroupBy}
+	if group.By == "" || group.By == "range" {
+		group.By, group.Interval = "range", rangePr.GroupInterval
+		if !pivotTableBoolValue(rangePr.AutoStart, true) {
+			group.Start = rangePr.StartNum
+		}
+		if !pivotTableBoolValue(rangePr.AutoEnd, true) {
+			group.End = rangePr.EndNum
+		}
+	}
+	return group
+}
+
+// getPivotCalculatedSharedItems provides a function to get the shared items of
+// the fields which have calculated items by given pivot table options and
+// the order of the pivot table fields. The items of these fields will be
+// read from the data source as the strings, and the calculated items will be
+// appended with the formula flag.
+func (f *File) getPivotCalculatedSharedItems(opts *PivotTableOptions, order []string) (map[int]*xlsxSharedItems, error) {
+	sharedItems := make(map[int]*xlsxSharedItems)
+	if len(opts.CalculatedItems) == 0 {
+		return sharedItems, nil
+	}
+	values, err := f.getPivotSourceValues(opts)
+	if err != nil {
+		return sharedItems, err
+	}
+	for _, item := range opts.CalculatedItems {
+		fieldIdx := inStrSlice(order[:len(order)-len(opts.CalculatedFields)], item.Field, true)
+		if fieldIdx == -1 || len(values) == 0 || fieldIdx >= len(values[0]) {
+			return sharedItems, ErrParameterInvalid
+		}
+		items, ok := sharedItems[fieldIdx]
+		if !ok {
+			column := make([]pivotCacheValue, len(values)-1)
+			for row := range column {
+				column[row] = values[row+1][fieldIdx]
+				column[row].number = false
+			}
+			items, _ = newPivotCacheSharedItems(column)
+			sharedItems[fieldIdx] = items
+		}
+		items.S = append(items.S, &xlsxString{V: item.Name, F: true})
+		items.Count++
+	}
+	return sharedItems, err
+}
+
 // addPivotCache provides a function to create a pivot cache by given properties.
 func (f *File) addPivotCache(pivotCacheXML string, opts *PivotTableOptions) error {
 	// validate data range
@@ -289,7 +688,35 @@ func (f *File) addPivotCache(pivotCacheXML string, opts *PivotTableOptions) erro
 	if definedNameRef {
 		pc.CacheSource.WorksheetSource = &xlsxWorksheetSource{Name: opts.DataRange}
 	}
-	for _, name := range order {
+	calculatedSharedItems, err := f.getPivotCalculatedSharedItems(opts, order)
+	if err != nil {
+		return err
+	}
+	groupFields, err := f.getPivotFieldGroups(opts, order)
+	if err != nil {
+		return err
+	}
+	fieldsCount := len(order) - len(opts.CalculatedFields)
+	for idx, name := range order {
+		if idx >= fieldsCount {
+			pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
+				Name:          name,
+				Formula:       strings.TrimPrefix(opts.CalculatedFields[idx-fieldsCount].Formula, "="),
+				DatabaseField: boolPtr(false),
+			})
+			continue
+		}
+		if items, ok := calculatedSharedItems[idx]; ok {
+			pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
+				Name:        name,
+				SharedItems: items,
+			})
+			continue
+		}
+		if cacheField, ok := groupFields[idx]; ok {
+			pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, cacheField)
+			continue
+		}
 		rowOptions, rowOk := f.getPivotTableFieldOptions(name, opts.Rows)
 		columnOptions, colOk := f.getPivotTableFieldOptions(name, opts.Columns)
 		sharedItems := xlsxSharedItems{
@@ -301,7 +728,7 @@ func (f *File) addPivotCache(pivotCacheXML string, opts *PivotTableOptions) erro
 				V: "",
 			}
 			sharedItems.Count++
-			sharedItems.S = &s
+			sharedItems.S = []*xlsxString{&s}
 		}
 		
 		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
@@ -310,11 +737,83 @@ func (f *File) addPivotCache(pivotCacheXML string, opts *PivotTableOptions) erro
 		})
 	}
 	pc.CacheFields.Count = len(pc.CacheFields.CacheField)
+	for _, item := range opts.CalculatedItems {
+		if pc.CalculatedItems == nil {
+			pc.CalculatedItems = &xlsxCalculatedItems{}
+		}
+		pc.CalculatedItems.CalculatedItem = append(pc.CalculatedItems.CalculatedItem,
+			newPivotCalculatedItem(inStrSlice(order, item.Field, true), calculatedSharedItems, item))
+		pc.CalculatedItems.Count = len(pc.CalculatedItems.CalculatedItem)
+	}
 	pivotCache, err := xml.Marshal(pc)
 	f.saveFileList(pivotCacheXML, pivotCache)
 	return err
 }
 
+// newPivotCalculatedItem provides a function to create the calculated item of
+// the pivot cache by given field index, the shared items of the fields which
+// have calculated items and the calculated item settings. The pivot area of
+// the calculated item refers to the shared item of the calculated item.
+func newPivotCalculatedItem(fieldIdx int, sharedItems map[int]*xlsxSharedItems, item PivotTableCalculatedItem) *xlsxCalculatedItem {
+	var x int
+	if items := sharedItems[fieldIdx]; items != nil {
+		if items.M != nil {
+			x++
+		}
+		for idx, s := range items.S {
+			if s.F && s.V == item.Name {
+				x += idx
+				break
+			}
+		}
+	}
+	return &xlsxCalculatedItem{
+		Formula: strings.TrimPrefix(item.Formula, "="),
+		PivotArea: &xlsxPivotArea{
+			Outline:       boolPtr(false),
+			FieldPosition: intPtr(0),
+			CacheIndex:    true,
+			References: &xlsxPivotAreaReferences{
+				Count: 1,
+				Reference: []*xlsxPivotAreaReference{
+					{Field: intPtr(fieldIdx), Count: 1, X: []*xlsxX{{V: x}}},
+				},
+			},
+		},
+	}
+}
+
+// pivotCalculatedFieldItems provides a function to get the items of the
+// pivot field by given shared items of the field which has calculated items,
+// the default subtotal item will be appended if it has been enabled.
+func pivotCalculatedFieldItems(sharedItems *xlsxSharedItems, defaultSubtotal bool) []*xlsxItem {
+	var items []*xlsxItem
+	if sharedItems.M != nil {
+		items = append(items, &xlsxItem{X: intPtr(0)})
+	}
+	for _, s := range sharedItems.S {
+		items = append(items, &xlsxItem{X: intPtr(len(items)), F: s.F})
+	}
+	if defaultSubtotal {
+		items = append(items, &xlsxItem{T: "default"})
+	}
+	return items
+}
+
+// pivotGroupFieldItems provides a function to get the items of the pivot
+// field by given field group of the grouped field, the default subtotal item
+// will be appended if it has been enabled.
+func pivotGroupFieldItems(fieldGroup *xlsxFieldGroup, defaultSubtotal bool) []*xlsxItem {
+	var items []*xlsxItem
+	for i := range fieldGroup.GroupItems.S {
+		items = append(items, &xlsxItem{X: intPtr(i)})
+	}
+	if defaultSubtotal {
+		items = append(items, &xlsxItem{T: "default"})
+	}
+	return items
+}
+
 // addPivotTable provides a function to create a pivot table by given pivot
 // table ID and properties.
 func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, opts *PivotTableOptions) error {
@@ -327,6 +826,10 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 	hCell, _ := CoordinatesToCellName(coordinates[0], coordinates[1])
 	vCell, _ := CoordinatesToCellName(coordinates[2], coordinates[3])
 	
+	name := opts.Name
+	if name == "" {
+		name = fmt.Sprintf("Pivot Table%d", pivotTableID)
+	}
 	pivotTableStyle := func() string {
 		if opts.PivotTableStyleName == "" {
 			return "PivotStyleLight16"
@@ -334,7 +837,7 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 		return opts.PivotTableStyleName
 	}
 	pt := xlsxPivotTableDefinition{
-		Name:                  fmt.Sprintf("Pivot Table%d", pivotTableID),
+		Name:                  name,
 		CacheID:               cacheID,
 		RowGrandTotals:        &opts.RowGrandTotals,
 		ColGrandTotals:        &opts.ColGrandTotals,
@@ -532,12 +1035,24 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opts *PivotTableOpti
 	if err != nil {
 		return err
 	}
+	calculatedSharedItems, err := f.getPivotCalculatedSharedItems(opts, order)
+	if err != nil {
+		return err
+	}
+	groupFields, err := f.getPivotFieldGroups(opts, order)
+	if err != nil {
+		return err
+	}
 	x := 0
-	for _, name := range order {
+	for idx, name := range order {
 		if inPivotTableField(opts.Rows, name) != -1 {
 			rowOptions, ok := f.getPivotTableFieldOptions(name, opts.Rows)
 			var items []*xlsxItem
-			if !ok || !rowOptions.DefaultSubtotal {
+			if sharedItems, calculated := calculatedSharedItems[idx]; calculated {
+				items = pivotCalculatedFieldItems(sharedItems, rowOptions.DefaultSubtotal)
+			} else if cacheField, grouped := groupFields[idx]; grouped {
+				items = pivotGroupFieldItems(cacheField.FieldGroup, rowOptions.DefaultSubtotal)
+			} else if !ok || !rowOptions.DefaultSubtotal {
 				items = append(items, &xlsxItem{X: &x})
 			} else {
 				items = append(items, &xlsxItem{T: "default"})
@@ -574,7 +1089,11 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opts *PivotTableOpti
 		if inPivotTableField(opts.Columns, name) != -1 {
 			columnOptions, ok := f.getPivotTableFieldOptions(name, opts.Columns)
 			var items []*xlsxItem
-			if !ok || !columnOptions.DefaultSubtotal {
+			if sharedItems, calculated := calculatedSharedItems[idx]; calculated {
+				items = pivotCalculatedFieldItems(sharedItems, columnOptions.DefaultSubtotal)
+			} else if cacheField, grouped := groupFields[idx]; grouped {
+				items = pivotGroupFieldItems(cacheField.FieldGroup, columnOptions.DefaultSubtotal)
+			} else if !ok || !columnOptions.DefaultSubtotal {
 				items = append(items, &xlsxItem{X: &x})
 			} else {
 				items = append(items, &xlsxItem{T: "default"})
@@ -607,29 +1126,101 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opts *PivotTableOpti
 // countPivotTables provides a function to get drawing files count storage in
 // the folder xl/pivotTables.
 func (f *File) countPivotTables() int {
-	count := 0
-	f.Pkg.Range(func(k, v interface{}) bool {
-		if strings.Contains(k.(string), "xl/pivotTables/pivotTable") {
-			count++
-		}
-		return true
-	})
-	return count
+	return f.countParts("xl/pivotTables/pivotTable")
 }
 
 // countPivotCache provides a function to get drawing files count storage in
 // the folder xl/pivotCache.
 func (f *File) countPivotCache() int {
+	return f.countParts("xl/pivotCache/pivotCacheDefinition")
+}
+
+// countParts provides a function to get the maximum sequence number of the
+// parts which path begins with the given prefix, the parts may be deleted so
+// that the sequence numbers are not continuous.
+func (f *File) countParts(prefix string) int {
 	count := 0
 	f.Pkg.Range(func(k, v interface{}) bool {
-		if strings.Contains(k.(string), "xl/pivotCache/pivotCacheDefinition") {
-			count++
+		if strings.Contains(k.(string), prefix) {
+			id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(k.(string), prefix), ".xml"))
+			if err != nil {
+				id = count + 1
+			}
+			if id > count {
+				count = id
+			}
 		}
 		return true
 	})
 	return count
 }
 
+// getRelationshipsPartPath provides a function to get the path of the part by
+// given relationships target which relative to the given folder.
+func getRelationshipsPartPath(folder, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Clean(folder + "/" + target)
+}
+
+// getPivotTableByCell provides a function to get the pivot table definition
+// and the pivot cache definition of the pivot table which contains the cell by
+// given worksheet name and cell coordinates. The results will be nil if there
+// is no pivot table contains the cell.
+func (f *File) getPivotTableByCell(sheet string, col, row int) (*xlsxPivotTableDefinition, *xlsxPivotCacheDefinition, error) {
+	name, ok := f.getSheetXMLPath(sheet)
+	if !ok {
+		return nil, nil, ErrSheetNotExist{sheet}
+	}
+	sheetRels, err := f.relsReader("xl/worksheets/_rels/" + strings.TrimPrefix(name, "xl/worksheets/") + ".rels")
+	if err != nil || sheetRels == nil {
+		return nil, nil, err
+	}
+	var targets []string
+	sheetRels.Lock()
+	for _, rel := range sheetRels.Relationships {
+		if rel.Type == SourceRelationshipPivotTable {
+			targets = append(targets, getRelationshipsPartPath("xl/worksheets", rel.Target))
+		}
+	}
+	sheetRels.Unlock()
+	for _, pivotTableXML := range targets {
+		pt := &xlsxPivotTableDefinition{}
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(pivotTableXML)))).
+			Decode(pt); err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+		if pt.Location == nil {
+			continue
+		}
+		coordinates, err := rangeRefToCoordinates(pt.Location.Ref)
+		if err != nil || !cellInRange([]int{col, row}, coordinates) {
+			continue
+		}
+		pivotTableRels, err := f.relsReader(strings.Replace(pivotTableXML, "xl/pivotTables/", "xl/pivotTables/_rels/", 1) + ".rels")
+		if err != nil || pivotTableRels == nil {
+			return pt, nil, err
+		}
+		pc := &xlsxPivotCacheDefinition{}
+		pivotTableRels.Lock()
+		defer pivotTableRels.Unlock()
+		for _, rel := range pivotTableRels.Relationships {
+			if rel.Type != SourceRelationshipPivotCache {
+				continue
+			}
+			pivotCacheXML := getRelationshipsPartPath("xl/pivotTables", rel.Target)
+			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(pivotCacheXML)))).
+				Decode(pc); err != nil && err != io.EOF {
+				return pt, nil, err
+			}
+			return pt, pc, nil
+		}
+		return pt, nil, nil
+	}
+	return nil, nil, nil
+}
+
 // getPivotFieldsIndex convert the column of the first row in the data region
 // to a sequential index by given fields and pivot option.
 func (f *File) getPivotFieldsIndex(fields []PivotTableField, opts *PivotTableOptions) ([]int, error) {
@@ -719,3 +1310,742 @@ func (f *File) addWorkbookPivotCache(RID int) int {
 	})
 	return cacheID
 }
+
+// pivotTableRef directly maps the parts of the pivot table in the worksheet.
+type pivotTableRef struct {
+	rID, pivotTableXML, pivotCacheXML string
+	pt                                *xlsxPivotTableDefinition
+	pc                                *xlsxPivotCacheDefinition
+}
+
+// getPivotTableRefs provides a function to get the pivot table definitions
+// and the pivot cache definitions of the pivot tables in the worksheet by
+// given worksheet name.
+func (f *File) getPivotTableRefs(sheet string) ([]*pivotTableRef, error) {
+	var refs []*pivotTableRef
+	name, ok := f.getSheetXMLPath(sheet)
+	if !ok {
+		return refs, ErrSheetNotExist{sheet}
+	}
+	sheetRels, err := f.relsReader("xl/worksheets/_rels/" + strings.TrimPrefix(name, "xl/worksheets/") + ".rels")
+	if err != nil || sheetRels == nil {
+		return refs, err
+	}
+	sheetRels.Lock()
+	for _, rel := range sheetRels.Relationships {
+		if rel.Type == SourceRelationshipPivotTable {
+			refs = append(refs, &pivotTableRef{rID: rel.ID, pivotTableXML: getRelationshipsPartPath("xl/worksheets", rel.Target)})
+		}
+	}
+	sheetRels.Unlock()
+	for _, ref := range refs {
+		ref.pt = &xlsxPivotTableDefinition{}
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(ref.pivotTableXML)))).
+			Decode(ref.pt); err != nil && err != io.EOF {
+			return refs, err
+		}
+		pivotTableRels, err := f.relsReader(strings.Replace(ref.pivotTableXML, "xl/pivotTables/", "xl/pivotTables/_rels/", 1) + ".rels")
+		if err != nil {
+			return refs, err
+		}
+		if pivotTableRels == nil {
+			continue
+		}
+		pivotTableRels.Lock()
+		for _, rel := range pivotTableRels.Relationships {
+			if rel.Type == SourceRelationshipPivotCache {
+				ref.pivotCacheXML = getRelationshipsPartPath("xl/pivotTables", rel.Target)
+				break
+			}
+		}
+		pivotTableRels.Unlock()
+		if ref.pivotCacheXML == "" {
+			continue
+		}
+		ref.pc = &xlsxPivotCacheDefinition{}
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(ref.pivotCacheXML)))).
+			Decode(ref.pc); err != nil && err != io.EOF {
+			return refs, err
+		}
+	}
+	return refs, nil
+}
+
+// GetPivotTables returns all pivot table definitions in a worksheet by given
+// worksheet name. The returned pivot table options could be modified and
+// applied by the UpdatePivotTable function.
+func (f *File) GetPivotTables(sheet string) ([]PivotTableOptions, error) {
+	var pivotTables []PivotTableOptions
+	refs, err := f.getPivotTableRefs(sheet)
+	if err != nil {
+		return pivotTables, err
+	}
+	for _, ref := range refs {
+		opts, err := f.getPivotTableOptions(sheet, ref)
+		if err != nil {
+			return pivotTables, err
+		}
+		pivotTables = append(pivotTables, opts)
+	}
+	return pivotTables, nil
+}
+
+// getPivotTableOptions provides a function to convert the pivot table
+// definition and the pivot cache definition to the pivot table options.
+func (f *File) getPivotTableOptions(sheet string, ref *pivotTableRef) (PivotTableOptions, error) {
+	pt, pc := ref.pt, ref.pc
+	opts := PivotTableOptions{
+		pivotTableSheetName: sheet,
+		Name:                pt.Name,
+		RowGrandTotals:      pivotTableBoolValue(pt.RowGrandTotals, true),
+		ColGrandTotals:      pivotTableBoolValue(pt.ColGrandTotals, true),
+		ShowDrill:           pivotTableBoolValue(pt.ShowDrill, true),
+		UseAutoFormatting:   pivotTableBoolValue(pt.UseAutoFormatting, false),
+		PageOverThenDown:    pivotTableBoolValue(pt.PageOverThenDown, false),
+		MergeItem:           pivotTableBoolValue(pt.MergeItem, false),
+		CompactData:         pivotTableBoolValue(pt.CompactData, true),
+		ShowError:           pivotTableBoolValue(pt.ShowError, false),
+	}
+	if pt.Location != nil {
+		coordinates, err := rangeRefToCoordinates(pt.Location.Ref)
+		if err != nil {
+			return opts, err
+		}
+		rangeRef, _ := f.coordinatesToRangeRef(coordinates, true)
+		opts.PivotTableRange = sheet + "!" + rangeRef
+	}
+	if pt.PivotTableStyleInfo != nil {
+		opts.PivotTableStyleName = pt.PivotTableStyleInfo.Name
+		opts.ShowRowHeaders = pt.PivotTableStyleInfo.ShowRowHeaders
+		opts.ShowColHeaders = pt.PivotTableStyleInfo.ShowColHeaders
+		opts.ShowRowStripes = pt.PivotTableStyleInfo.ShowRowStripes
+		opts.ShowColStripes = pt.PivotTableStyleInfo.ShowColStripes
+		opts.ShowLastColumn = pt.PivotTableStyleInfo.ShowLastColumn
+	}
+	if pc == nil || pc.CacheFields == nil {
+		return opts, nil
+	}
+	if source := pc.CacheSource; source != nil && source.WorksheetSource != nil {
+		opts.DataRange = source.WorksheetSource.Name
+		if opts.DataRange == "" {
+			coordinates, err := rangeRefToCoordinates(source.WorksheetSource.Ref)
+			if err != nil {
+				return opts, err
+			}
+			rangeRef, _ := f.coordinatesToRangeRef(coordinates, true)
+			opts.DataRange = source.WorksheetSource.Sheet + "!" + rangeRef
+		}
+	}
+	fieldName := func(idx int) (string, bool) {
+		if idx < 0 || idx >= len(pc.CacheFields.CacheField) {
+			return "", false
+		}
+		return pc.CacheFields.CacheField[idx].Name, true
+	}
+	axisField := func(idx int) (PivotTableField, bool) {
+		data, ok := fieldName(idx)
+		field := PivotTableField{Data: data}
+		if ok && pt.PivotFields != nil && idx < len(pt.PivotFields.PivotField) {
+			pivotField := pt.PivotFields.PivotField[idx]
+			field.Name = pivotField.Name
+			field.Compact = pivotTableBoolValue(pivotField.Compact, true)
+			field.Outline = pivotTableBoolValue(pivotField.Outline, true)
+			field.DefaultSubtotal = pivotTableBoolValue(pivotField.DefaultSubtotal, true)
+		}
+		if ok {
+			field.Group = getPivotFieldGroupOptions(pc.CacheFields.CacheField[idx].FieldGroup)
+		}
+		return field, ok
+	}
+	if pt.RowFields != nil {
+		for _, fld := range pt.RowFields.Field {
+			if field, ok := axisField(fld.X); ok {
+				opts.Rows = append(opts.Rows, field)
+			}
+		}
+	}
+	if pt.ColFields != nil {
+		for _, fld := range pt.ColFields.Field {
+			if field, ok := axisField(fld.X); ok {
+				opts.Columns = append(opts.Columns, field)
+			}
+		}
+	}
+	if pt.PageFields != nil {
+		for _, fld := range pt.PageFields.PageField {
+			if data, ok := fieldName(fld.Fld); ok {
+				opts.Filter = append(opts.Filter, PivotTableField{Data: data, Name: fld.Name})
+			}
+		}
+	}
+	if pt.DataFields != nil {
+		for _, fld := range pt.DataFields.DataField {
+			if data, ok := fieldName(fld.Fld); ok {
+				subtotal := "Sum"
+				if fld.Subtotal != "" {
+					subtotal = strings.ToUpper(fld.Subtotal[:1]) + fld.Subtotal[1:]
+				}
+				opts.Data = append(opts.Data, PivotTableField{Data: data, Name: fld.Name, Subtotal: subtotal})
+			}
+		}
+	}
+	for _, field := range pc.CacheFields.CacheField {
+		if field.Formula != "" {
+			opts.CalculatedFields = append(opts.CalculatedFields, PivotTableCalculatedField{
+				Name: field.Name, Formula: "=" + field.Formula,
+			})
+		}
+	}
+	if pc.CalculatedItems != nil {
+		for _, item := range pc.CalculatedItems.CalculatedItem {
+			if calculatedItem, ok := getPivotCalculatedItem(pc, item); ok {
+				opts.CalculatedItems = append(opts.CalculatedItems, calculatedItem)
+			}
+		}
+	}
+	return opts, nil
+}
+
+// getPivotCalculatedItem provides a function to convert the calculated item
+// of the pivot cache to the calculated item settings by given pivot cache
+// definition and the calculated item. The field and the name of the
+// calculated item will be read from the shared item which referenced by the
+// pivot area of the calculated item.
+func getPivotCalculatedItem(pc *xlsxPivotCacheDefinition, item *xlsxCalculatedItem) (PivotTableCalculatedItem, bool) {
+	var calculatedItem PivotTableCalculatedItem
+	if item.PivotArea == nil || item.PivotArea.References == nil {
+		return calculatedItem, false
+	}
+	for _, ref := range item.PivotArea.References.Reference {
+		if ref.Field == nil || *ref.Field < 0 || *ref.Field >= len(pc.CacheFields.CacheField) || len(ref.X) == 0 {
+			continue
+		}
+		field := pc.CacheFields.CacheField[*ref.Field]
+		if field.SharedItems == nil {
+			continue
+		}
+		x := ref.X[0].V
+		if field.SharedItems.M != nil {
+			x--
+		}
+		if x < 0 || x >= len(field.SharedItems.S) {
+			continue
+		}
+		calculatedItem.Field = field.Name
+		calculatedItem.Name = field.SharedItems.S[x].V
+		calculatedItem.Formula = "=" + item.Formula
+		return calculatedItem, true
+	}
+	return calculatedItem, false
+}
+
+// pivotTableBoolValue provides a function to get the value of the optional
+// boolean attribute of the pivot table, the default value will be returned
+// if the attribute is omitted.
+func pivotTableBoolValue(val *bool, defaultVal bool) bool {
+	if val == nil {
+		return defaultVal
+	}
+	return *val
+}
+
+// UpdatePivotTable provides a function to modify the existing pivot table by
+// given pivot table options. The pivot table will be found by the 'Name' on
+// the worksheet of the 'PivotTableRange', the row, column, filter and data
+// fields, the data source range and other settings of the pivot table will
+// be replaced, and the pivot cache will be rebuilt and refreshed on loading
+// the workbook. The existing pivot table will be kept if the options are
+// invalid. For example, get the first pivot table in the worksheet named
+// Sheet1, and change the data source range and the data field:
+//
+//	pivotTables, err := f.GetPivotTables("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	opts := pivotTables[0]
+//	opts.DataRange = "Sheet1!$A$1:$E$50"
+//	opts.Data = []excelize.PivotTableField{
+//	    {Data: "Sales", Name: "Average Sales", Subtotal: "Average"},
+//	}
+//	err = f.UpdatePivotTable(&opts)
+func (f *File) UpdatePivotTable(opts *PivotTableOptions) error {
+	if _, _, err := f.parseFormatPivotTableSet(opts); err != nil {
+		return err
+	}
+	order, err := f.getPivotFieldsOrder(opts)
+	if err != nil {
+		return err
+	}
+	// resolve the calculated items and the field groups before deleting the
+	// existing pivot table, so that it will be kept on any error
+	if _, err = f.getPivotCalculatedSharedItems(opts, order); err != nil {
+		return err
+	}
+	if _, err = f.getPivotFieldGroups(opts, order); err != nil {
+		return err
+	}
+	if err = f.DeletePivotTable(opts.pivotTableSheetName, opts.Name); err != nil {
+		return err
+	}
+	return f.AddPivotTable(opts)
+}
+
+// DeletePivotTable provides a function to delete the pivot table by given
+// worksheet name and pivot table name. The pivot cache definition and the
+// pivot cache records of the pivot table will be deleted if the pivot cache
+// is not used by other pivot tables. For example, delete the pivot table
+// named "PivotTable1" in the worksheet named Sheet1:
+//
+//	err := f.DeletePivotTable("Sheet1", "PivotTable1")
+func (f *File) DeletePivotTable(sheet, name string) error {
+	refs, err := f.getPivotTableRefs(sheet)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.pt.Name != name {
+			continue
+		}
+		f.deleteSheetRelationships(sheet, ref.rID)
+		if err = f.deletePart(ref.pivotTableXML); err != nil {
+			return err
+		}
+		if ref.pivotCacheXML == "" {
+			return err
+		}
+		inUse, err := f.isPivotCacheInUse(ref.pt.CacheID)
+		if err != nil || inUse {
+			return err
+		}
+		return f.deletePivotCache(ref.pt.CacheID, ref.pivotCacheXML)
+	}
+	return newNoExistPivotTableError(name)
+}
+
+// isPivotCacheInUse provides a function to check if the pivot cache is used
+// by any pivot table in the workbook by given pivot cache ID.
+func (f *File) isPivotCacheInUse(cacheID int) (bool, error) {
+	var (
+		err   error
+		inUse bool
+	)
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/pivotTables/pivotTable") {
+			pt := &xlsxPivotTableDefinition{}
+			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(v.([]byte)))).
+				Decode(pt); err != nil && err != io.EOF {
+				return false
+			}
+			err = nil
+			inUse = pt.CacheID == cacheID
+		}
+		return !inUse
+	})
+	return inUse, err
+}
+
+// deletePivotCache provides a function to delete the pivot cache definition,
+// the pivot cache records and the pivot cache in the workbook by given pivot
+// cache ID and pivot cache definition path.
+func (f *File) deletePivotCache(cacheID int, pivotCacheXML string) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.PivotCaches != nil {
+		for idx, pivotCache := range wb.PivotCaches.PivotCache {
+			if pivotCache.CacheID == cacheID {
+				if _, err = f.deleteSheetFromWorkbookRels(pivotCache.RID); err != nil {
+					return err
+				}
+				wb.PivotCaches.PivotCache = append(wb.PivotCaches.PivotCache[:idx], wb.PivotCaches.PivotCache[idx+1:]...)
+				break
+			}
+		}
+		if len(wb.PivotCaches.PivotCache) == 0 {
+			wb.PivotCaches = nil
+		}
+	}
+	pivotCacheRels, err := f.relsReader(strings.Replace(pivotCacheXML, "xl/pivotCache/", "xl/pivotCache/_rels/", 1) + ".rels")
+	if err != nil {
+		return err
+	}
+	if pivotCacheRels != nil {
+		for _, rel := range pivotCacheRels.Relationships {
+			if rel.Type == SourceRelationshipPivotCacheRecords {
+				if err = f.deletePart(getRelationshipsPartPath("xl/pivotCache", rel.Target)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return f.deletePart(pivotCacheXML)
+}
+
+// deletePart provides a function to delete the part, the relationships of
+// the part and the content type of the part by given part path.
+func (f *File) deletePart(partName string) error {
+	rels := path.Dir(partName) + "/_rels/" + path.Base(partName) + ".rels"
+	f.Pkg.Delete(partName)
+	f.Pkg.Delete(rels)
+	f.Relationships.Delete(rels)
+	return f.deleteSheetFromContentTypes("/" + partName)
+}
+
+// RefreshPivotCache provides a function to refresh the pivot cache of the
+// pivot table by given pivot table name. The pivot cache records will be
+// rebuilt from the current data of the source range of the pivot cache, and
+// the record count and the shared items of the cache fields in the pivot
+// cache definition will be updated, so that the cached records will not be
+// stale after the source data has been changed. The other pivot tables which
+// share the same pivot cache will also use the refreshed records. The items
+// of the pivot fields in these pivot tables will be rebuilt from the shared
+// items, and the pivot cache will be set to be refreshed on load, so that
+// the layout and location of these pivot tables will be recalculated when
+// opening the workbook in the spreadsheet application. For example, refresh
+// the pivot cache of the pivot table named "PivotTable1" after changing the
+// source data:
+//
+//	err := f.RefreshPivotCache("PivotTable1")
+//
+// Note that only the pivot cache which source is a worksheet range or a
+// defined name refers to a worksheet range is supported.
+func (f *File) RefreshPivotCache(name string) error {
+	for _, sheet := range f.GetSheetList() {
+		refs, err := f.getPivotTableRefs(sheet)
+		if err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			if ref.pt.Name == name && ref.pc != nil {
+				return f.refreshPivotCache(sheet, ref)
+			}
+		}
+	}
+	return newNoExistPivotTableError(name)
+}
+
+// refreshPivotCache provides a function to rebuild the pivot cache records
+// and the cache fields of the pivot cache by given worksheet name of the
+// pivot table and the parts of the pivot table.
+func (f *File) refreshPivotCache(sheet string, ref *pivotTableRef) error {
+	pc := ref.pc
+	values, err := f.getPivotCacheSourceValues(sheet, pc)
+	if err != nil {
+		return err
+	}
+	if pc.CacheFields == nil {
+		pc.CacheFields = &xlsxCacheFields{}
+	}
+	records := &xlsxPivotCacheRecords{Count: len(values) - 1}
+	for i := 1; i < len(values); i++ {
+		records.R = append(records.R, &xlsxPivotCacheRecord{Values: make([]xlsxPivotCacheRecordValue, len(values[0]))})
+	}
+	var (
+		fields          []*xlsxCacheField
+		calculatedItems = make(map[string][]PivotTableCalculatedItem)
+		sharedItems     = make(map[int]*xlsxSharedItems)
+	)
+	if pc.CalculatedItems != nil {
+		for _, item := range pc.CalculatedItems.CalculatedItem {
+			if calculatedItem, ok := getPivotCalculatedItem(pc, item); ok {
+				calculatedItems[calculatedItem.Field] = append(calculatedItems[calculatedItem.Field], calculatedItem)
+			}
+		}
+	}
+	for col, header := range values[0] {
+		field := &xlsxCacheField{}
+		if col < len(pc.CacheFields.CacheField) && pc.CacheFields.CacheField[col].Formula == "" {
+			field = pc.CacheFields.CacheField[col]
+		}
+		column := make([]pivotCacheValue, len(records.R))
+		for row := range column {
+			column[row] = values[row+1][col]
+			if _, ok := calculatedItems[header.value]; ok {
+				column[row].number = false
+			}
+		}
+		var recordValues []xlsxPivotCacheRecordValue
+		field.Name = header.value
+		field.SharedItems, recordValues = newPivotCacheSharedItems(column)
+		if group := getPivotFieldGroupOptions(field.FieldGroup); group != nil {
+			if field.SharedItems, field.FieldGroup, recordValues, err = f.newPivotFieldGroup(column, col, *group); err != nil {
+				return err
+			}
+		}
+		for row, value := range recordValues {
+			records.R[row].Values[col] = value
+		}
+		if items, ok := calculatedItems[header.value]; ok {
+			for _, item := range items {
+				field.SharedItems.S = append(field.SharedItems.S, &xlsxString{V: item.Name, F: true})
+				field.SharedItems.Count++
+			}
+			sharedItems[col] = field.SharedItems
+		}
+		fields = append(fields, field)
+	}
+	for _, field := range pc.CacheFields.CacheField {
+		if field.Formula != "" {
+			fields = append(fields, field)
+		}
+	}
+	if pc.CalculatedItems != nil {
+		pc.CalculatedItems.CalculatedItem = nil
+		for col, header := range values[0] {
+			if _, ok := sharedItems[col]; !ok {
+				continue
+			}
+			for _, item := range calculatedItems[header.value] {
+				pc.CalculatedItems.CalculatedItem = append(pc.CalculatedItems.CalculatedItem,
+					newPivotCalculatedItem(col, sharedItems, item))
+			}
+		}
+		pc.CalculatedItems.Count = len(pc.CalculatedItems.CalculatedItem)
+		if pc.CalculatedItems.Count == 0 {
+			pc.CalculatedItems = nil
+		}
+	}
+	pc.CacheFields.CacheField, pc.CacheFields.Count = fields, len(fields)
+	pc.SaveData, pc.RecordCount, pc.RefreshOnLoad = true, records.Count, true
+	if err = f.savePivotCacheRecords(ref.pivotCacheXML, pc, records); err != nil {
+		return err
+	}
+	if err = f.refreshPivotTableItems(ref.pivotCacheXML, pc, sharedItems); err != nil {
+		return err
+	}
+	pivotCache, err := xml.Marshal(pc)
+	f.saveFileList(ref.pivotCacheXML, pivotCache)
+	return err
+}
+
+// refreshPivotTableItems provides a function to rebuild the items of the
+// pivot fields in the pivot tables which use the given pivot cache by given
+// pivot cache part path, the refreshed pivot cache definition and the shared
+// items of the cache fields which have calculated items. The items of the
+// grouped and calculated fields will be rebuilt from the shared items, the
+// other items refer to the shared items will be cleared and the subtotal
+// items will be kept. The row and column items of the pivot tables will be
+// reset, which will be recalculated on refreshing the pivot cache on load.
+func (f *File) refreshPivotTableItems(pivotCacheXML string, pc *xlsxPivotCacheDefinition, sharedItems map[int]*xlsxSharedItems) error {
+	for _, sheet := range f.GetSheetList() {
+		refs, err := f.getPivotTableRefs(sheet)
+		if err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			if ref.pivotCacheXML != pivotCacheXML {
+				continue
+			}
+			if ref.pt.PivotFields != nil {
+				for col, field := range ref.pt.PivotFields.PivotField {
+					if field.Items == nil {
+						continue
+					}
+					defaultSubtotal := field.DefaultSubtotal == nil || *field.DefaultSubtotal
+					var items []*xlsxItem
+					if calculated, ok := sharedItems[col]; ok {
+						field.Items.Item = pivotCalculatedFieldItems(calculated, defaultSubtotal)
+						field.Items.Count = len(field.Items.Item)
+						continue
+					}
+					if col < len(pc.CacheFields.CacheField) {
+						if group := pc.CacheFields.CacheField[col].FieldGroup; group != nil && group.GroupItems != nil {
+							field.Items.Item = pivotGroupFieldItems(group, defaultSubtotal)
+							field.Items.Count = len(field.Items.Item)
+							continue
+						}
+					}
+					for _, item := range field.Items.Item {
+						if item.X == nil {
+							items = append(items, item)
+						}
+					}
+					if len(items) == 0 {
+						items = append(items, &xlsxItem{X: intPtr(0)})
+					}
+					field.Items.Item, field.Items.Count = items, len(items)
+				}
+			}
+			ref.pt.RowItems = &xlsxRowItems{Count: 1, I: []*xlsxI{{[]*xlsxX{{}, {}}}}}
+			ref.pt.ColItems = &xlsxColItems{Count: 1, I: []*xlsxI{{}}}
+			pivotTable, err := xml.Marshal(ref.pt)
+			if err != nil {
+				return err
+			}
+			f.saveFileList(ref.pivotTableXML, pivotTable)
+		}
+	}
+	return nil
+}
+
+// getPivotCacheSourceValues provides a function to get the values of the
+// cells in the source range of the pivot cache by given worksheet name of the
+// pivot table and the pivot cache definition.
+func (f *File) getPivotCacheSourceValues(sheet string, pc *xlsxPivotCacheDefinition) ([][]pivotCacheValue, error) {
+	if pc.CacheSource == nil || pc.CacheSource.WorksheetSource == nil {
+		return nil, ErrPivotCacheSource
+	}
+	source := pc.CacheSource.WorksheetSource
+	dataRange := source.Sheet + "!" + source.Ref
+	if source.Name != "" {
+		dataRange = f.getDefinedNameRefTo(source.Name, sheet)
+	}
+	dataSheet, coordinates, err := f.adjustRange(dataRange)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(dataSheet, "'") && strings.HasSuffix(dataSheet, "'") {
+		dataSheet = strings.ReplaceAll(dataSheet[1:len(dataSheet)-1], "''", "'")
+	}
+	return f.getPivotCacheValues(dataSheet, coordinates)
+}
+
+// pivotCacheValue directly maps the value of the cell in the source range of
+// the pivot cache.
+type pivotCacheValue struct {
+	value         string
+	number, blank bool
+}
+
+// getPivotCacheValues provides a function to get the values of the cells in
+// the source range of the pivot cache by given worksheet name and range
+// coordinates. The numeric cell values will be read as the raw values.
+func (f *File) getPivotCacheValues(sheet string, coordinates []int) ([][]pivotCacheValue, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]pivotCacheValue, coordinates[3]-coordinates[1]+1)
+	for row := range values {
+		values[row] = make([]pivotCacheValue, coordinates[2]-coordinates[0]+1)
+		for col := range values[row] {
+			values[row][col].blank = true
+		}
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	for _, row := range ws.SheetData.Row {
+		if row.R < coordinates[1] || row.R > coordinates[3] {
+			continue
+		}
+		for _, c := range row.C {
+			col, _, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return values, err
+			}
+			if col < coordinates[0] || col > coordinates[2] || (c.V == "" && c.IS == nil) {
+				continue
+			}
+			value, err := c.getValueFrom(f, sst, c.T != "b")
+			if err != nil {
+				return values, err
+			}
+			isNum, _, _ := isNumeric(value)
+			values[row.R-coordinates[1]][col-coordinates[0]] = pivotCacheValue{
+				value:  value,
+				number: (c.T == "" || c.T == "n") && isNum,
+			}
+		}
+	}
+	return values, err
+}
+
+// newPivotCacheSharedItems provides a function to create the shared items of
+// the cache field and the values of the records by given values of the field.
+// The numeric values of the field which only contains numbers will be stored
+// in the records directly, otherwise the values will be stored as the shared
+// string items and referenced by the index in the records.
+func newPivotCacheSharedItems(values []pivotCacheValue) (*xlsxSharedItems, []xlsxPivotCacheRecordValue) {
+	var (
+		items             = &xlsxSharedItems{}
+		records           = make([]xlsxPivotCacheRecordValue, len(values))
+		numOnly, hasValue = true, false
+	)
+	for _, value := range values {
+		if value.blank {
+			items.ContainsBlank = true
+			continue
+		}
+		numOnly, hasValue = numOnly && value.number, true
+	}
+	if numOnly && hasValue {
+		items.ContainsSemiMixedTypes, items.ContainsString = boolPtr(false), boolPtr(false)
+		items.ContainsNumber, items.ContainsInteger = true, true
+		first := true
+		for i, value := range values {
+			if value.blank {
+				records[i] = xlsxPivotCacheRecordValue{XMLName: xml.Name{Local: "m"}}
+				continue
+			}
+			num, _ := strconv.ParseFloat(value.value, 64)
+			if first || num < items.MinValue {
+				items.MinValue = num
+			}
+			if first || num > items.MaxValue {
+				items.MaxValue = num
+			}
+			first = false
+			items.ContainsInteger = items.ContainsInteger && num == math.Trunc(num)
+			records[i] = xlsxPivotCacheRecordValue{XMLName: xml.Name{Local: "n"}, V: value.value}
+		}
+		return items, records
+	}
+	if items.ContainsBlank {
+		// the missing item will be the first shared item
+		items.M, items.Count = &xlsxMissing{}, 1
+	}
+	index := make(map[string]int)
+	for i, value := range values {
+		idx, ok := 0, true
+		if !value.blank {
+			if idx, ok = index[value.value]; !ok {
+				idx, index[value.value] = items.Count, items.Count
+				items.S = append(items.S, &xlsxString{V: value.value})
+				items.Count++
+			}
+		}
+		records[i] = xlsxPivotCacheRecordValue{XMLName: xml.Name{Local: "x"}, V: strconv.Itoa(idx)}
+	}
+	return items, records
+}
+
+// savePivotCacheRecords provides a function to save the pivot cache records
+// by given pivot cache definition path and the pivot cache definition, the
+// pivot cache records part will be created if it doesn't exist.
+func (f *File) savePivotCacheRecords(pivotCacheXML string, pc *xlsxPivotCacheDefinition, records *xlsxPivotCacheRecords) error {
+	pivotCacheRels := strings.Replace(pivotCacheXML, "xl/pivotCache/", "xl/pivotCache/_rels/", 1) + ".rels"
+	rels, err := f.relsReader(pivotCacheRels)
+	if err != nil {
+		return err
+	}
+	var recordsXML string
+	if rels != nil {
+		rels.Lock()
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipPivotCacheRecords {
+				recordsXML = getRelationshipsPartPath("xl/pivotCache", rel.Target)
+				pc.RID = rel.ID
+				break
+			}
+		}
+		rels.Unlock()
+	}
+	if recordsXML == "" {
+		recordsID := f.countParts("xl/pivotCache/pivotCacheRecords") + 1
+		recordsXML = "xl/pivotCache/pivotCacheRecords" + strconv.Itoa(recordsID) + ".xml"
+		rID := f.addRels(pivotCacheRels, SourceRelationshipPivotCacheRecords, "pivotCacheRecords"+strconv.Itoa(recordsID)+".xml", "")
+		pc.RID = "rId" + strconv.Itoa(rID)
+		if err = f.addContentTypePart(recordsID, "pivotCacheRecords"); err != nil {
+			return err
+		}
+	}
+	output, err := xml.Marshal(records)
+	f.saveFileList(recordsXML, output)
+	return err
+}
@@ -1,11 +1,14 @@
 package excel
 
 import (
+	"encoding/xml"
 	"fmt"
+	"math"
 	"math/rand"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 	
 	"github.com/stretchr/testify/assert"
 )
@@ -277,6 +280,599 @@ func TestAddPivotTable(t *testing.T) {
 	}), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestGetPivotTables(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	for row := 2; row < 32; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{"Jan", 2017 + row%3, "Meat", row * 10, "East"}))
+	}
+	expected := PivotTableOptions{
+		pivotTableSheetName: "Sheet1",
+		DataRange:           "Sheet1!$A$1:$E$31",
+		PivotTableRange:     "Sheet1!$G$2:$M$34",
+		Name:                "PivotTable1",
+		Rows:                []PivotTableField{{Data: "Month", Compact: true, Outline: true, DefaultSubtotal: true}, {Data: "Year"}},
+		Columns:             []PivotTableField{{Data: "Type", DefaultSubtotal: true}},
+		Data:                []PivotTableField{{Data: "Sales", Name: "Summarize by Sum", Subtotal: "Sum"}},
+		Filter:              []PivotTableField{{Data: "Region"}},
+		RowGrandTotals:      true,
+		ColGrandTotals:      true,
+		ShowDrill:           true,
+		ShowRowHeaders:      true,
+		ShowColHeaders:      true,
+		ShowLastColumn:      true,
+		PivotTableStyleName: "PivotStyleLight16",
+	}
+	opts := expected
+	assert.NoError(t, f.AddPivotTable(&opts))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$E$31",
+		PivotTableRange: "Sheet1!$G$40:$M$60",
+		Rows:            []PivotTableField{{Data: "Region"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "average"}, {Data: "Year", Subtotal: "Max"}},
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetPivotTables.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestGetPivotTables.xlsx"))
+	assert.NoError(t, err)
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 2)
+	assert.Equal(t, expected, pivotTables[0])
+	assert.Equal(t, "Pivot Table2", pivotTables[1].Name)
+	assert.Equal(t, []PivotTableField{{Data: "Region"}}, pivotTables[1].Rows)
+	assert.Nil(t, pivotTables[1].Columns)
+	assert.Equal(t, []PivotTableField{{Data: "Sales", Subtotal: "Average"}, {Data: "Year", Subtotal: "Max"}}, pivotTables[1].Data)
+	// Test get pivot tables without pivot tables
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	pivotTables, err = f.GetPivotTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Empty(t, pivotTables)
+	// Test get pivot tables on not exists worksheet
+	_, err = f.GetPivotTables("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get pivot tables with invalid pivot table location
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", []byte(`<pivotTableDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"><location ref="A"/></pivotTableDefinition>`))
+	_, err = f.GetPivotTables("Sheet1")
+	assert.Equal(t, ErrParameterInvalid, err)
+	// Test get pivot tables with invalid pivot cache source range
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", []byte(`<pivotTableDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"/>`))
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"><cacheSource type="worksheet"><worksheetSource ref="A" sheet="Sheet1"/></cacheSource><cacheFields/></pivotCacheDefinition>`))
+	_, err = f.GetPivotTables("Sheet1")
+	assert.Equal(t, ErrParameterInvalid, err)
+	// Test get pivot tables with unsupported charset pivot cache definition
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get pivot tables with unsupported charset pivot table relationships
+	f.Relationships.Delete("xl/pivotTables/_rels/pivotTable1.xml.rels")
+	f.Pkg.Store("xl/pivotTables/_rels/pivotTable1.xml.rels", MacintoshCyrillicCharset)
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get pivot tables with unsupported charset pivot table definition
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get pivot tables with unsupported charset worksheet relationships
+	f.Relationships.Delete("xl/worksheets/_rels/sheet1.xml.rels")
+	f.Pkg.Store("xl/worksheets/_rels/sheet1.xml.rels", MacintoshCyrillicCharset)
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestUpdatePivotTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	for row := 2; row < 42; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{"Jan", 2017 + row%3, "Meat", row * 10, "East"}))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$E$31",
+		PivotTableRange: "Sheet1!$G$2:$M$34",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	opts := pivotTables[0]
+	opts.DataRange = "Sheet1!$A$1:$E$41"
+	opts.Rows = []PivotTableField{{Data: "Year"}}
+	opts.Columns = []PivotTableField{{Data: "Region"}}
+	opts.Data = []PivotTableField{{Data: "Sales", Name: "Average", Subtotal: "Average"}}
+	assert.NoError(t, f.UpdatePivotTable(&opts))
+	pivotTables, err = f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, "PivotTable1", pivotTables[0].Name)
+	assert.Equal(t, "Sheet1!$A$1:$E$41", pivotTables[0].DataRange)
+	assert.Equal(t, []PivotTableField{{Data: "Year"}}, pivotTables[0].Rows)
+	assert.Equal(t, []PivotTableField{{Data: "Region"}}, pivotTables[0].Columns)
+	assert.Equal(t, opts.Data, pivotTables[0].Data)
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Len(t, wb.PivotCaches.PivotCache, 1)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUpdatePivotTable.xlsx")))
+	// Test update pivot table with not exists pivot table
+	opts.Name = "PivotTable2"
+	assert.EqualError(t, f.UpdatePivotTable(&opts), "pivot table PivotTable2 does not exist")
+	// Test update pivot table with invalid options
+	assert.Equal(t, ErrParameterRequired, f.UpdatePivotTable(nil))
+	opts.DataRange = "Sheet1!A1:A1"
+	assert.EqualError(t, f.UpdatePivotTable(&opts), "parameter 'DataRange' parsing error: parameter is invalid")
+	// Test update pivot table with grouping on the text field keeps the pivot table
+	opts.Name, opts.DataRange = "PivotTable1", "Sheet1!$A$1:$E$41"
+	opts.Rows = []PivotTableField{{Data: "Month", Group: &PivotTableFieldGroup{By: "range"}}}
+	assert.Equal(t, ErrParameterInvalid, f.UpdatePivotTable(&opts))
+	pivotTables, err = f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, []PivotTableField{{Data: "Year"}}, pivotTables[0].Rows)
+	assert.NoError(t, f.Close())
+}
+
+func TestDeletePivotTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	for row := 2; row < 32; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{"Jan", 2017 + row%3, "Meat", row * 10, "East"}))
+	}
+	for i, rangeRef := range []string{"Sheet1!$G$2:$M$34", "Sheet1!$O$2:$U$34"} {
+		assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+			DataRange:       "Sheet1!$A$1:$E$31",
+			PivotTableRange: rangeRef,
+			Name:            fmt.Sprintf("PivotTable%d", i+1),
+			Rows:            []PivotTableField{{Data: "Month"}},
+			Data:            []PivotTableField{{Data: "Sales"}},
+		}))
+	}
+	// Test delete pivot table with the pivot cache which is shared by other
+	// pivot tables
+	f.Pkg.Store("xl/pivotTables/pivotTable3.xml", []byte(`<pivotTableDefinition xmlns="`+NameSpaceSpreadSheet.Value+`" cacheId="2"/>`))
+	f.Pkg.Store("xl/pivotCache/_rels/pivotCacheDefinition2.xml.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="`+SourceRelationshipPivotCacheRecords+`" Target="pivotCacheRecords2.xml"/></Relationships>`))
+	f.Pkg.Store("xl/pivotCache/pivotCacheRecords2.xml", []byte(`<pivotCacheRecords xmlns="`+NameSpaceSpreadSheet.Value+`"/>`))
+	assert.NoError(t, f.DeletePivotTable("Sheet1", "PivotTable1"))
+	_, ok := f.Pkg.Load("xl/pivotTables/pivotTable1.xml")
+	assert.False(t, ok)
+	_, ok = f.Pkg.Load("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.True(t, ok)
+	f.Pkg.Delete("xl/pivotTables/pivotTable3.xml")
+	// Test delete the last pivot table with the pivot cache
+	assert.NoError(t, f.DeletePivotTable("Sheet1", "PivotTable2"))
+	for _, part := range []string{
+		"xl/pivotTables/pivotTable2.xml",
+		"xl/pivotCache/pivotCacheDefinition1.xml",
+		"xl/pivotCache/pivotCacheDefinition2.xml",
+		"xl/pivotCache/pivotCacheRecords2.xml",
+	} {
+		_, ok = f.Pkg.Load(part)
+		assert.Equal(t, part == "xl/pivotCache/pivotCacheDefinition1.xml", ok, part)
+	}
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, pivotTables)
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Len(t, wb.PivotCaches.PivotCache, 1)
+	// Test add pivot table after deleted pivot tables
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$E$31",
+		PivotTableRange: "Sheet1!$G$2:$M$34",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	_, ok = f.Pkg.Load("xl/pivotTables/pivotTable1.xml")
+	assert.True(t, ok)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeletePivotTable.xlsx")))
+	// Test delete pivot table with not exists pivot table
+	assert.EqualError(t, f.DeletePivotTable("Sheet1", "PivotTableN"), "pivot table PivotTableN does not exist")
+	// Test delete pivot table on not exists worksheet
+	assert.EqualError(t, f.DeletePivotTable("SheetN", "PivotTable1"), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
+func TestDeletePivotCache(t *testing.T) {
+	// Test delete pivot cache with unsupported charset workbook
+	f := NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.deletePivotCache(1, "xl/pivotCache/pivotCacheDefinition1.xml"), "XML syntax error on line 1: invalid UTF-8")
+	// Test delete pivot cache with unsupported charset workbook relationships
+	f = NewFile()
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.PivotCaches = &xlsxPivotCaches{PivotCache: []xlsxPivotCache{{CacheID: 1, RID: "rId4"}}}
+	f.Relationships.Delete(defaultXMLPathWorkbookRels)
+	f.Pkg.Store(defaultXMLPathWorkbookRels, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.deletePivotCache(1, "xl/pivotCache/pivotCacheDefinition1.xml"), "XML syntax error on line 1: invalid UTF-8")
+	// Test delete pivot cache with unsupported charset pivot cache relationships
+	f = NewFile()
+	f.Pkg.Store("xl/pivotCache/_rels/pivotCacheDefinition1.xml.rels", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.deletePivotCache(1, "xl/pivotCache/pivotCacheDefinition1.xml"), "XML syntax error on line 1: invalid UTF-8")
+	// Test delete pivot cache with unsupported charset content types
+	f = NewFile()
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.deletePivotCache(1, "xl/pivotCache/pivotCacheDefinition1.xml"), "XML syntax error on line 1: invalid UTF-8")
+	// Test check pivot cache in use with unsupported charset pivot table
+	f = NewFile()
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", MacintoshCyrillicCharset)
+	_, err = f.isPivotCacheInUse(1)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestRefreshPivotCache(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Jan", 2017, "Meat", 10.5, "East"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"Feb", 2018, "Dairy", 20, nil}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"Jan", 2019, "Meat", 30, "West"}))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$E$4",
+		PivotTableRange: "Sheet1!$G$2:$M$34",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	// Test refresh pivot cache after changing the source data
+	assert.NoError(t, f.SetCellValue("Sheet1", "D4", 40))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	pc := &xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml"), pc))
+	assert.True(t, pc.SaveData)
+	assert.Equal(t, 3, pc.RecordCount)
+	assert.Equal(t, "rId1", pc.RID)
+	assert.Equal(t, 5, pc.CacheFields.Count)
+	var names []string
+	for _, field := range pc.CacheFields.CacheField {
+		names = append(names, field.Name)
+	}
+	assert.Equal(t, []string{"Month", "Year", "Type", "Sales", "Region"}, names)
+	month := pc.CacheFields.CacheField[0].SharedItems
+	assert.Equal(t, 2, month.Count)
+	assert.Equal(t, []*xlsxString{{V: "Jan"}, {V: "Feb"}}, month.S)
+	sales := pc.CacheFields.CacheField[3].SharedItems
+	assert.Equal(t, boolPtr(false), sales.ContainsString)
+	assert.True(t, sales.ContainsNumber)
+	assert.False(t, sales.ContainsInteger)
+	assert.Equal(t, 10.5, sales.MinValue)
+	assert.Equal(t, 40.0, sales.MaxValue)
+	region := pc.CacheFields.CacheField[4].SharedItems
+	assert.True(t, region.ContainsBlank)
+	assert.NotNil(t, region.M)
+	assert.Equal(t, 3, region.Count)
+	records := &xlsxPivotCacheRecords{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheRecords1.xml"), records))
+	assert.Equal(t, 3, records.Count)
+	var values []string
+	for _, record := range records.R {
+		for _, value := range record.Values {
+			values = append(values, value.XMLName.Local+value.V)
+		}
+	}
+	assert.Equal(t, []string{
+		"x0", "n2017", "x0", "n10.5", "x1",
+		"x1", "n2018", "x1", "n20", "x0",
+		"x0", "n2019", "x0", "n40", "x2",
+	}, values)
+	content, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	assert.Contains(t, content.Overrides, xlsxOverride{
+		PartName:    "/xl/pivotCache/pivotCacheRecords1.xml",
+		ContentType: ContentTypeSpreadSheetMLPivotCacheRecords,
+	})
+	// Test refresh pivot cache with the existing pivot cache records
+	assert.NoError(t, f.SetCellValue("Sheet1", "A4", "Mar"))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	pc = &xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml"), pc))
+	assert.Equal(t, []*xlsxString{{V: "Jan"}, {V: "Feb"}, {V: "Mar"}}, pc.CacheFields.CacheField[0].SharedItems.S)
+	assert.True(t, pc.RefreshOnLoad)
+	_, ok := f.Pkg.Load("xl/pivotCache/pivotCacheRecords2.xml")
+	assert.False(t, ok)
+	// Test refresh pivot cache rebuild the items of the pivot fields
+	pt := &xlsxPivotTableDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotTables/pivotTable1.xml"), pt))
+	pt.PivotFields.PivotField[0].Items = &xlsxItems{Count: 3, Item: []*xlsxItem{{X: intPtr(1)}, {X: intPtr(0), H: true}, {T: "default"}}}
+	pt.RowItems = &xlsxRowItems{Count: 2, I: []*xlsxI{{[]*xlsxX{{V: 1}}}, {[]*xlsxX{{V: 2}}}}}
+	pivotTable, err := xml.Marshal(pt)
+	assert.NoError(t, err)
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", pivotTable)
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	pt = &xlsxPivotTableDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotTables/pivotTable1.xml"), pt))
+	assert.Equal(t, &xlsxItems{Count: 1, Item: []*xlsxItem{{T: "default"}}}, pt.PivotFields.PivotField[0].Items)
+	assert.Equal(t, 1, pt.RowItems.Count)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRefreshPivotCache.xlsx")))
+	// Test refresh pivot cache with not exists pivot table
+	assert.EqualError(t, f.RefreshPivotCache("PivotTableN"), "pivot table PivotTableN does not exist")
+	// Test refresh pivot cache with the defined name source
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "dataRange", RefersTo: "'Sheet1'!$A$1:$E$4"}))
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"><cacheSource type="worksheet"><worksheetSource name="dataRange"/></cacheSource></pivotCacheDefinition>`))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	pc = &xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml"), pc))
+	assert.Equal(t, 3, pc.RecordCount)
+	// Test refresh pivot cache with unsupported pivot cache source
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"><cacheSource type="external"/></pivotCacheDefinition>`))
+	assert.Equal(t, ErrPivotCacheSource, f.RefreshPivotCache("PivotTable1"))
+	// Test refresh pivot cache with invalid pivot cache source range
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"><cacheSource type="worksheet"><worksheetSource ref="A1" sheet="Sheet1"/></cacheSource></pivotCacheDefinition>`))
+	assert.Equal(t, ErrParameterInvalid, f.RefreshPivotCache("PivotTable1"))
+	// Test refresh pivot cache with not exists source worksheet
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"><cacheSource type="worksheet"><worksheetSource ref="A1:B2" sheet="SheetN"/></cacheSource></pivotCacheDefinition>`))
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "sheet SheetN does not exist")
+	// Test refresh pivot cache with unsupported charset pivot cache relationships
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", []byte(`<pivotCacheDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"><cacheSource type="worksheet"><worksheetSource ref="A1:E4" sheet="Sheet1"/></cacheSource></pivotCacheDefinition>`))
+	f.Relationships.Delete("xl/pivotCache/_rels/pivotCacheDefinition1.xml.rels")
+	f.Pkg.Store("xl/pivotCache/_rels/pivotCacheDefinition1.xml.rels", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "XML syntax error on line 1: invalid UTF-8")
+	// Test refresh pivot cache with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "XML syntax error on line 1: invalid UTF-8")
+	// Test refresh pivot cache with unsupported charset pivot table definition
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	f = NewFile()
+	// Test refresh pivot cache with the new pivot cache records and unsupported charset content types
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.savePivotCacheRecords("xl/pivotCache/pivotCacheDefinition1.xml", &xlsxPivotCacheDefinition{}, &xlsxPivotCacheRecords{}), "XML syntax error on line 1: invalid UTF-8")
+	// Test get pivot cache values with invalid cell reference
+	f = NewFile()
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData.Row = []xlsxRow{{R: 1, C: []xlsxC{{R: "A", V: "1"}}}}
+	_, err = f.getPivotCacheValues("Sheet1", []int{1, 1, 2, 2})
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	ws.(*xlsxWorksheet).SheetData.Row = []xlsxRow{{R: 1, C: []xlsxC{{R: "A1", T: "s", V: "1"}}}}
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	_, err = f.getPivotCacheValues("Sheet1", []int{1, 1, 2, 2})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestNewPivotCacheSharedItems(t *testing.T) {
+	// Test create shared items with the blank field
+	items, records := newPivotCacheSharedItems([]pivotCacheValue{{blank: true}})
+	assert.Equal(t, &xlsxSharedItems{ContainsBlank: true, Count: 1, M: &xlsxMissing{}}, items)
+	assert.Equal(t, []xlsxPivotCacheRecordValue{{XMLName: xml.Name{Local: "x"}, V: "0"}}, records)
+	// Test create shared items with numeric and blank values
+	items, records = newPivotCacheSharedItems([]pivotCacheValue{{value: "2", number: true}, {blank: true}, {value: "1", number: true}})
+	assert.True(t, items.ContainsInteger)
+	assert.Equal(t, 1.0, items.MinValue)
+	assert.Equal(t, 2.0, items.MaxValue)
+	assert.Equal(t, "m", records[1].XMLName.Local)
+	// Test create shared items with mixed numeric and string values
+	items, records = newPivotCacheSharedItems([]pivotCacheValue{{value: "1", number: true}, {value: "a"}})
+	assert.Equal(t, []*xlsxString{{V: "1"}, {V: "a"}}, items.S)
+	assert.Equal(t, "x", records[0].XMLName.Local)
+}
+
+func TestPivotTableCalculations(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Region", "Sales"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Jan", "East", 10}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"Feb", "West", 20}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"Mar", nil, 30}))
+	opts := &PivotTableOptions{
+		DataRange:        "Sheet1!$A$1:$C$4",
+		PivotTableRange:  "Sheet1!$E$2:$K$20",
+		Name:             "PivotTable1",
+		Rows:             []PivotTableField{{Data: "Region", DefaultSubtotal: true}},
+		Data:             []PivotTableField{{Data: "Sales"}, {Data: "Commission"}},
+		CalculatedFields: []PivotTableCalculatedField{{Name: "Commission", Formula: "=Sales*0.05"}},
+		CalculatedItems:  []PivotTableCalculatedItem{{Field: "Region", Name: "East and West", Formula: "=East+West"}},
+	}
+	// Test add pivot table with calculated field and calculated item
+	assert.NoError(t, f.AddPivotTable(opts))
+	pc := &xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml"), pc))
+	assert.Equal(t, 4, pc.CacheFields.Count)
+	commission := pc.CacheFields.CacheField[3]
+	assert.Equal(t, "Sales*0.05", commission.Formula)
+	assert.Equal(t, boolPtr(false), commission.DatabaseField)
+	region := pc.CacheFields.CacheField[1].SharedItems
+	assert.Equal(t, 4, region.Count)
+	assert.Equal(t, []*xlsxString{{V: "East"}, {V: "West"}, {V: "East and West", F: true}}, region.S)
+	assert.Equal(t, 1, pc.CalculatedItems.Count)
+	item := pc.CalculatedItems.CalculatedItem[0]
+	assert.Equal(t, "East+West", item.Formula)
+	assert.Equal(t, intPtr(1), item.PivotArea.References.Reference[0].Field)
+	assert.Equal(t, []*xlsxX{{V: 3}}, item.PivotArea.References.Reference[0].X)
+	pt := &xlsxPivotTableDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotTables/pivotTable1.xml"), pt))
+	assert.Equal(t, []*xlsxItem{
+		{X: intPtr(0)}, {X: intPtr(1)}, {X: intPtr(2)}, {X: intPtr(3), F: true}, {T: "default"},
+	}, pt.PivotFields.PivotField[1].Items.Item)
+	// Test get pivot table with calculated field and calculated item
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, opts.CalculatedFields, pivotTables[0].CalculatedFields)
+	assert.Equal(t, opts.CalculatedItems, pivotTables[0].CalculatedItems)
+	// Test refresh pivot cache with calculated field and calculated item
+	assert.NoError(t, f.SetCellValue("Sheet1", "B4", "North"))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	pc = &xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml"), pc))
+	assert.Equal(t, 4, pc.CacheFields.Count)
+	assert.Equal(t, "Sales*0.05", pc.CacheFields.CacheField[3].Formula)
+	region = pc.CacheFields.CacheField[1].SharedItems
+	assert.Nil(t, region.M)
+	assert.Equal(t, []*xlsxString{{V: "East"}, {V: "West"}, {V: "North"}, {V: "East and West", F: true}}, region.S)
+	assert.Equal(t, []*xlsxX{{V: 3}}, pc.CalculatedItems.CalculatedItem[0].PivotArea.References.Reference[0].X)
+	pivotTables, err = f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, opts.CalculatedItems, pivotTables[0].CalculatedItems)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPivotTableCalculations.xlsx")))
+	// Test add pivot table with invalid calculated field and calculated item
+	for _, calcOpts := range []struct {
+		fields []PivotTableCalculatedField
+		items  []PivotTableCalculatedItem
+		err    error
+	}{
+		{fields: []PivotTableCalculatedField{{Name: "Commission"}}, err: ErrParameterRequired},
+		{fields: []PivotTableCalculatedField{{Name: "sales", Formula: "=Sales*2"}}, err: ErrParameterInvalid},
+		{fields: []PivotTableCalculatedField{{Name: "Bonus", Formula: "=1"}, {Name: "Bonus", Formula: "=2"}}, err: ErrParameterInvalid},
+		{items: []PivotTableCalculatedItem{{Field: "Region", Formula: "=East"}}, err: ErrParameterRequired},
+		{items: []PivotTableCalculatedItem{{Field: "Month", Name: "Q1", Formula: "=Jan+Feb"}}, err: ErrParameterInvalid},
+		{items: []PivotTableCalculatedItem{{Field: "Total", Name: "All", Formula: "=1"}}, err: ErrParameterInvalid},
+	} {
+		assert.Equal(t, calcOpts.err, f.AddPivotTable(&PivotTableOptions{
+			DataRange:        "Sheet1!$A$1:$C$4",
+			PivotTableRange:  "Sheet1!$M$2:$Q$20",
+			Rows:             []PivotTableField{{Data: "Region"}},
+			Data:             []PivotTableField{{Data: "Sales"}},
+			CalculatedFields: calcOpts.fields,
+			CalculatedItems:  calcOpts.items,
+		}))
+	}
+	// Test get the shared items with the calculated item out of the data source
+	for _, field := range []string{"Commission", "Total"} {
+		_, err = f.getPivotCalculatedSharedItems(&PivotTableOptions{
+			DataRange:        "Sheet1!$A$1:$C$4",
+			CalculatedFields: []PivotTableCalculatedField{{Name: "Commission", Formula: "=Sales*0.05"}},
+			CalculatedItems:  []PivotTableCalculatedItem{{Field: field, Name: "All", Formula: "=1"}},
+		}, []string{"Month", "Region", "Sales", "Commission"})
+		assert.Equal(t, ErrParameterInvalid, err)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestPivotTableFieldGroups(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Date", "Sales", "Region"}))
+	for row, values := range [][]interface{}{
+		{time.Date(2017, 1, 15, 0, 0, 0, 0, time.UTC), 12, "East"},
+		{time.Date(2017, 5, 3, 0, 0, 0, 0, time.UTC), 25, "West"},
+		{time.Date(2018, 11, 20, 0, 0, 0, 0, time.UTC), 38, "East"},
+		{nil, 7, "West"},
+	} {
+		cell, err := CoordinatesToCellName(1, row+2)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &values))
+	}
+	opts := &PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$C$5",
+		PivotTableRange: "Sheet1!$E$2:$K$20",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Date", Group: &PivotTableFieldGroup{By: "quarters"}}},
+		Columns:         []PivotTableField{{Data: "Sales", DefaultSubtotal: true, Group: &PivotTableFieldGroup{By: "range", Start: float64Ptr(0), Interval: 10}}},
+		Data:            []PivotTableField{{Data: "Region", Subtotal: "Count"}},
+	}
+	// Test add pivot table with the date and numeric field groups
+	assert.NoError(t, f.AddPivotTable(opts))
+	pc := &xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml"), pc))
+	date := pc.CacheFields.CacheField[0]
+	assert.Equal(t, 14, date.NumFmtID)
+	assert.True(t, date.SharedItems.ContainsDate)
+	assert.True(t, date.SharedItems.ContainsBlank)
+	assert.Equal(t, "2017-01-15T00:00:00", date.SharedItems.MinDate)
+	assert.Equal(t, "2018-11-20T00:00:00", date.SharedItems.MaxDate)
+	assert.Equal(t, &xlsxRangePr{GroupBy: "quarters", StartDate: "2017-01-15T00:00:00", EndDate: "2018-11-21T00:00:00"}, date.FieldGroup.RangePr)
+	assert.Equal(t, intPtr(0), date.FieldGroup.Base)
+	assert.Equal(t, &xlsxGroupItems{Count: 6, S: []*xlsxString{
+		{V: "<1/15/2017"}, {V: "Qtr1"}, {V: "Qtr2"}, {V: "Qtr3"}, {V: "Qtr4"}, {V: ">11/21/2018"},
+	}}, date.FieldGroup.GroupItems)
+	sales := pc.CacheFields.CacheField[1]
+	assert.Equal(t, &xlsxRangePr{AutoStart: boolPtr(false), StartNum: float64Ptr(0), EndNum: float64Ptr(38), GroupInterval: 10}, sales.FieldGroup.RangePr)
+	assert.Equal(t, &xlsxGroupItems{Count: 6, S: []*xlsxString{
+		{V: "<0"}, {V: "0-9"}, {V: "10-19"}, {V: "20-29"}, {V: "30-39"}, {V: ">38"},
+	}}, sales.FieldGroup.GroupItems)
+	pt := &xlsxPivotTableDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotTables/pivotTable1.xml"), pt))
+	assert.Len(t, pt.PivotFields.PivotField[0].Items.Item, 6)
+	assert.Len(t, pt.PivotFields.PivotField[1].Items.Item, 7)
+	// Test get pivot table with the field groups
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, &PivotTableFieldGroup{By: "quarters"}, pivotTables[0].Rows[0].Group)
+	assert.Equal(t, &PivotTableFieldGroup{By: "range", Start: float64Ptr(0), Interval: 10}, pivotTables[0].Columns[0].Group)
+	// Test refresh pivot cache with the field groups
+	assert.NoError(t, f.SetCellValue("Sheet1", "B5", 45))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A5", time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	pc = &xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml"), pc))
+	date = pc.CacheFields.CacheField[0]
+	assert.Equal(t, "2019-02-01T00:00:00", date.SharedItems.MaxDate)
+	assert.Equal(t, ">2/2/2019", date.FieldGroup.GroupItems.S[5].V)
+	assert.Equal(t, ">45", pc.CacheFields.CacheField[1].FieldGroup.GroupItems.S[6].V)
+	records := &xlsxPivotCacheRecords{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheRecords1.xml"), records))
+	assert.Equal(t, xlsxPivotCacheRecordValue{XMLName: xml.Name{Space: NameSpaceSpreadSheet.Value, Local: "d"}, V: "2017-01-15T00:00:00"}, records.R[0].Values[0])
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPivotTableFieldGroups.xlsx")))
+	// Test add pivot table with invalid field groups
+	for _, fields := range [][]PivotTableField{
+		{{Data: "Date", Group: &PivotTableFieldGroup{By: "weeks"}}},
+		{{Data: "Sales", Group: &PivotTableFieldGroup{By: "range", Interval: -1}}},
+		{{Data: "Sales", Group: &PivotTableFieldGroup{By: "range", Start: float64Ptr(10), End: float64Ptr(0)}}},
+		{{Data: "Region", Group: &PivotTableFieldGroup{By: "range"}}},
+		{{Data: "Bonus", Group: &PivotTableFieldGroup{By: "range"}}},
+	} {
+		assert.Equal(t, ErrParameterInvalid, f.AddPivotTable(&PivotTableOptions{
+			DataRange:        "Sheet1!$A$1:$C$5",
+			PivotTableRange:  "Sheet1!$M$2:$Q$20",
+			Rows:             fields,
+			Data:             []PivotTableField{{Data: "Sales"}},
+			CalculatedFields: []PivotTableCalculatedField{{Name: "Bonus", Formula: "=Sales*0.1"}},
+		}))
+	}
+	// Test get the field groups with the grouped field out of the data source
+	_, err = f.getPivotFieldGroups(&PivotTableOptions{
+		DataRange: "Sheet1!$A$1:$C$5",
+		Rows:      []PivotTableField{{Data: "Bonus", Group: &PivotTableFieldGroup{By: "range"}}},
+	}, []string{"Date", "Sales", "Region", "Bonus"})
+	assert.Equal(t, ErrParameterInvalid, err)
+	assert.Equal(t, ErrParameterInvalid, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$C$5",
+		PivotTableRange: "Sheet1!$M$2:$Q$20",
+		Rows:            []PivotTableField{{Data: "Region"}},
+		Data:            []PivotTableField{{Data: "Sales", Group: &PivotTableFieldGroup{By: "range"}}},
+	}))
+	assert.Equal(t, ErrParameterInvalid, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$C$5",
+		PivotTableRange: "Sheet1!$M$2:$Q$20",
+		Rows:            []PivotTableField{{Data: "Region", Group: &PivotTableFieldGroup{By: "years"}}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+		CalculatedItems: []PivotTableCalculatedItem{{Field: "Region", Name: "All", Formula: "=East+West"}},
+	}))
+	// Test group the dates by years with the latest date at the end of year
+	_, fieldGroup, _, err := f.newPivotFieldGroup([]pivotCacheValue{
+		{value: "42736", number: true}, {value: "43465", number: true},
+	}, 0, PivotTableFieldGroup{By: "years"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-01-01T00:00:00", fieldGroup.RangePr.EndDate)
+	assert.Equal(t, &xlsxGroupItems{Count: 4, S: []*xlsxString{
+		{V: "<1/1/2017"}, {V: "2017"}, {V: "2018"}, {V: ">1/1/2019"},
+	}}, fieldGroup.GroupItems)
+	// Test group the numeric values with too many or invalid bins
+	_, err = newPivotFieldRangeGroup(0, 0, 1e9, PivotTableFieldGroup{By: "range"})
+	assert.Equal(t, ErrPivotGroupItems, err)
+	_, err = newPivotFieldRangeGroup(0, 0, 1, PivotTableFieldGroup{By: "range", Interval: 1e-9})
+	assert.Equal(t, ErrPivotGroupItems, err)
+	for _, group := range []PivotTableFieldGroup{
+		{By: "range", Interval: -1},
+		{By: "range", Interval: math.NaN()},
+		{By: "range", Start: float64Ptr(math.Inf(-1))},
+	} {
+		_, err = newPivotFieldRangeGroup(0, 0, 10, group)
+		assert.Equal(t, ErrParameterInvalid, err)
+	}
+	assert.NoError(t, f.Close())
+}
+
 func TestAddPivotRowFields(t *testing.T) {
 	f := NewFile()
 	// Test invalid data range
@@ -321,3 +917,26 @@ func TestGetPivotTableFieldName(t *testing.T) {
 	f := NewFile()
 	f.getPivotTableFieldName("-", []PivotTableField{})
 }
+
+func TestGetPivotTableByCell(t *testing.T) {
+	f := NewFile()
+	// Test get pivot table on not exists worksheet
+	_, _, err := f.getPivotTableByCell("SheetN", 1, 1)
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get pivot table without pivot tables
+	pt, pc, err := f.getPivotTableByCell("Sheet1", 1, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, pt)
+	assert.Nil(t, pc)
+	// Test get pivot table with unsupported charset
+	f.Pkg.Store("xl/worksheets/_rels/sheet1.xml.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="`+SourceRelationshipPivotTable+`" Target="../pivotTables/pivotTable1.xml"/></Relationships>`))
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", MacintoshCyrillicCharset)
+	_, _, err = f.getPivotTableByCell("Sheet1", 1, 1)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	f.Pkg.Store("xl/pivotTables/pivotTable1.xml", []byte(`<pivotTableDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"><location ref="A1:B2"/></pivotTableDefinition>`))
+	f.Pkg.Store("xl/pivotTables/_rels/pivotTable1.xml.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="`+SourceRelationshipPivotCache+`" Target="/xl/pivotCache/pivotCacheDefinition1.xml"/></Relationships>`))
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", MacintoshCyrillicCharset)
+	pt, _, err = f.getPivotTableByCell("Sheet1", 1, 1)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NotNil(t, pt)
+}
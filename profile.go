@@ -0,0 +1,120 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"runtime"
+	"time"
+)
+
+// PartProfile directly maps the time and memory cost of parsing a part of
+// the workbook. The Part specifies the part name in the package, the Sheet
+// specifies the worksheet name for the worksheet parts, the Size specifies
+// the uncompressed size of the part in bytes, the Duration specifies the
+// time spent on parsing the part, and the Alloc specifies the bytes of the
+// memory allocated during parsing the part.
+type PartProfile struct {
+	Part     string
+	Sheet    string
+	Size     int
+	Duration time.Duration
+	Alloc    uint64
+}
+
+// OpenProfile directly maps the time and memory cost of opening a workbook,
+// which returned by the Profile function. The Open specifies the cost of
+// reading and unzipping the package, and the Parts specifies the cost of
+// parsing the workbook, the styles, the shared strings and each worksheet
+// in the order of the parsing.
+type OpenProfile struct {
+	Path  string
+	Open  PartProfile
+	Parts []PartProfile
+}
+
+// Profile provides a function to open the workbook by given path and
+// options, and measure the time and memory cost of parsing each part of the
+// workbook, including the workbook, styles, shared strings and each
+// worksheet, to help to identify whether the slow open is due to the large
+// shared strings table, too many styles or a single giant worksheet. The
+// workbook will be closed after profiling. Note that the memory statistics
+// are collected from the Go runtime, so the result will be affected by the
+// other goroutines running in the same time. For example, print the cost of
+// each part of the workbook:
+//
+//	profile, err := excelize.Profile("Book1.xlsx")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, part := range profile.Parts {
+//	    fmt.Println(part.Part, part.Size, part.Duration, part.Alloc)
+//	}
+func Profile(path string, opts ...Options) (*OpenProfile, error) {
+	profile := &OpenProfile{Path: path}
+	var f *File
+	err := profile.measure(&profile.Open, func() (err error) {
+		f, err = OpenFile(path, opts...)
+		return
+	})
+	if err != nil {
+		return profile, err
+	}
+	profile.Open.Part = path
+	// Discard the parts which have been parsed on open, and parse them again
+	// for measuring each part
+	f.WorkBook, f.Styles = nil, nil
+	for _, part := range []struct {
+		name string
+		fn   func() error
+	}{
+		{f.getWorkbookPath(), func() error { _, err := f.workbookReader(); return err }},
+		{defaultXMLPathStyles, func() error { _, err := f.stylesReader(); return err }},
+		{defaultXMLPathSharedStrings, func() error { _, err := f.sharedStringsReader(); return err }},
+	} {
+		partProfile := PartProfile{Part: part.name, Size: len(f.readBytes(part.name))}
+		if err = profile.measure(&partProfile, part.fn); err != nil {
+			_ = f.Close()
+			return profile, err
+		}
+		profile.Parts = append(profile.Parts, partProfile)
+	}
+	for _, sheet := range f.GetSheetList() {
+		name, _ := f.getSheetXMLPath(sheet)
+		partProfile := PartProfile{Part: name, Sheet: sheet, Size: len(f.readBytes(name))}
+		if err = profile.measure(&partProfile, func() error {
+			_, err := f.workSheetReader(sheet)
+			return err
+		}); err != nil {
+			if err.Error() == newNotWorksheetError(sheet).Error() {
+				continue
+			}
+			_ = f.Close()
+			return profile, err
+		}
+		profile.Parts = append(profile.Parts, partProfile)
+	}
+	return profile, f.Close()
+}
+
+// measure provides a function to run the given function and record the time
+// spent and the bytes of the memory allocated into the part profile.
+func (profile *OpenProfile) measure(part *PartProfile, fn func() error) error {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	err := fn()
+	part.Duration = time.Since(start)
+	runtime.ReadMemStats(&after)
+	part.Alloc = after.TotalAlloc - before.TotalAlloc
+	return err
+}
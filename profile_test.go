@@ -0,0 +1,55 @@
+package excel
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Hello"))
+	assert.NoError(t, f.SetCellValue("Sheet2", "B2", 100))
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{
+		Type:   Col,
+		Series: []ChartSeries{{Name: "Sheet1!$A$1", Values: "Sheet2!$B$2"}},
+	}))
+	path := filepath.Join("test", "TestProfile.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+
+	profile, err := Profile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, path, profile.Path)
+	assert.Equal(t, path, profile.Open.Part)
+	assert.Greater(t, profile.Open.Alloc, uint64(0))
+	var parts, sheets []string
+	for _, part := range profile.Parts {
+		parts = append(parts, part.Part)
+		sheets = append(sheets, part.Sheet)
+		assert.Greater(t, part.Size, 0, part.Part)
+		assert.Greater(t, part.Alloc, uint64(0), part.Part)
+	}
+	assert.Equal(t, []string{
+		"xl/workbook.xml", defaultXMLPathStyles, defaultXMLPathSharedStrings,
+		"xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml",
+	}, parts)
+	assert.Equal(t, []string{"", "", "", "Sheet1", "Sheet2"}, sheets)
+
+	// Test profile the workbook with not exist file
+	_, err = Profile(filepath.Join("test", "NotExist.xlsx"))
+	assert.Error(t, err)
+	// Test profile the workbook with unsupported charset worksheet
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	f.Sheet.Delete("xl/worksheets/sheet2.xml")
+	f.Pkg.Store("xl/worksheets/sheet2.xml", MacintoshCyrillicCharset)
+	path = filepath.Join("test", "TestProfileWorksheet.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+	_, err = Profile(path)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
@@ -45,7 +45,18 @@ import (
 //	    }
 //	    fmt.Println()
 //	}
+//
+// Get the values of the columns A and D only, and skip the cells after the
+// column Z in each row:
+//
+//	rows, err := f.GetRows("Sheet1", excelize.Options{
+//	    Columns:     []string{"A", "D"},
+//	    ColumnLimit: 26,
+//	})
 func (f *File) GetRows(sheet string, opts ...Options) ([][]string, error) {
+	if _, err := getReadColumns(parseOptions(opts...).Columns); err != nil {
+		return nil, err
+	}
 	rows, err := f.Rows(sheet)
 	if err != nil {
 		return nil, err
@@ -77,6 +88,7 @@ type Rows struct {
 	decoder                 *xml.Decoder
 	token                   xml.Token
 	curRowOpts, seekRowOpts RowOpts
+	tracker                 *resourceTracker
 }
 
 // Next will return true if find the next row element.
@@ -121,11 +133,15 @@ func (rows *Rows) Error() error {
 }
 
 // Close closes the open worksheet XML file in the system temporary
-// directory.
+// directory. It is safe to call this function multiple times.
 func (rows *Rows) Close() error {
 	if rows.tempFile != nil {
-		return rows.tempFile.Close()
+		if err := rows.tempFile.Close(); err != nil {
+			return err
+		}
+		rows.tempFile = nil
 	}
+	rows.tracker.close()
 	return nil
 }
 
@@ -133,14 +149,55 @@ func (rows *Rows) Close() error {
 // data as a stream, returns each cell in a row as is, and will not skip empty
 // rows in the tail of the worksheet.
 func (rows *Rows) Columns(opts ...Options) ([]string, error) {
+	rowIterator := rows.readRow(false, opts...)
+	return rowIterator.cells, rowIterator.err
+}
+
+// RowCell directly maps the cell reference, value and data type of a cell
+// which actually present in a row of the worksheet.
+type RowCell struct {
+	Ref   string
+	Value string
+	Type  CellType
+}
+
+// Cells return the current row's present cells with the cell reference,
+// value and data type. Unlike Columns, the missing cells in the row will not
+// be padded with empty values, so the consumers of the sparse worksheet can
+// locate each value by its cell reference. The cells are returned in the
+// order of the worksheet even if the Columns option was specified. For
+// example:
+//
+//	for rows.Next() {
+//	    cells, err := rows.Cells()
+//	    if err != nil {
+//	        fmt.Println(err)
+//	    }
+//	    for _, cell := range cells {
+//	        fmt.Println(cell.Ref, cell.Value, cell.Type)
+//	    }
+//	}
+func (rows *Rows) Cells(opts ...Options) ([]RowCell, error) {
+	rowIterator := rows.readRow(true, opts...)
+	return rowIterator.rowCells, rowIterator.err
+}
+
+// readRow provides a function to read the current row's cells by streaming,
+// the present cells with the cell reference will be collected if the
+// withRef is true.
+func (rows *Rows) readRow(withRef bool, opts ...Options) *rowXMLIterator {
+	rowIterator := &rowXMLIterator{withRef: withRef}
 	if rows.curRow > rows.seekRow {
-		return nil, nil
+		return rowIterator
 	}
-	var rowIterator rowXMLIterator
 	var token xml.Token
-	rows.rawCellValue = parseOptions(opts...).RawCellValue
+	options := parseOptions(opts...)
+	rows.rawCellValue, rowIterator.limit = options.RawCellValue, options.ColumnLimit
+	if rowIterator.columns, rowIterator.err = getReadColumns(options.Columns); rowIterator.err != nil {
+		return rowIterator
+	}
 	if rows.sst, rowIterator.err = rows.f.sharedStringsReader(); rowIterator.err != nil {
-		return rowIterator.cells, rowIterator.err
+		return rowIterator
 	}
 	for {
 		if rows.token != nil {
@@ -162,21 +219,21 @@ func (rows *Rows) Columns(opts ...Options) ([]string, error) {
 				rows.seekRowOpts = extractRowOpts(xmlElement.Attr)
 				if rows.curRow > rows.seekRow {
 					rows.token = nil
-					return rowIterator.cells, rowIterator.err
+					return rowIterator
 				}
 			}
-			if rows.rowXMLHandler(&rowIterator, &xmlElement, rows.rawCellValue); rowIterator.err != nil {
+			if rows.rowXMLHandler(rowIterator, &xmlElement, rows.rawCellValue); rowIterator.err != nil {
 				rows.token = nil
-				return rowIterator.cells, rowIterator.err
+				return rowIterator
 			}
 			rows.token = nil
 		case xml.EndElement:
 			if xmlElement.Name.Local == "sheetData" {
-				return rowIterator.cells, rowIterator.err
+				return rowIterator
 			}
 		}
 	}
-	return rowIterator.cells, rowIterator.err
+	return rowIterator
 }
 
 // extractRowOpts extract row element attributes.
@@ -217,6 +274,50 @@ type rowXMLIterator struct {
 	inElement        string
 	cellCol, cellRow int
 	cells            []string
+	withRef          bool
+	rowCells         []RowCell
+	limit            int
+	columns          []int
+}
+
+// getReadColumns converts the column names to be read in each row to the
+// column numbers, returns an error if the column names contain duplicates.
+func getReadColumns(names []string) ([]int, error) {
+	if names == nil {
+		return nil, nil
+	}
+	columns := make([]int, 0, len(names))
+	for _, name := range names {
+		col, err := ColumnNameToNumber(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range columns {
+			if c == col {
+				return nil, newDuplicateColumnNameError(name)
+			}
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// position returns the position of the current cell in the row values, and
+// returns false if the cell should be skipped by the column limit or the
+// column names to be read.
+func (rowIterator *rowXMLIterator) position() (int, bool) {
+	if rowIterator.limit > 0 && rowIterator.cellCol > rowIterator.limit {
+		return 0, false
+	}
+	if rowIterator.columns == nil {
+		return rowIterator.cellCol, true
+	}
+	for idx, col := range rowIterator.columns {
+		if col == rowIterator.cellCol {
+			return idx + 1, true
+		}
+	}
+	return 0, false
 }
 
 // rowXMLHandler parse the row XML element of the worksheet.
@@ -230,9 +331,25 @@ func (rows *Rows) rowXMLHandler(rowIterator *rowXMLIterator, xmlElement *xml.Sta
 				return
 			}
 		}
-		blank := rowIterator.cellCol - len(rowIterator.cells)
+		pos, ok := rowIterator.position()
+		if !ok {
+			return
+		}
+		blank := pos - len(rowIterator.cells)
 		if val, _ := colCell.getValueFrom(rows.f, rows.sst, raw); val != "" || colCell.F != nil {
-			rowIterator.cells = append(appendSpace(blank, rowIterator.cells), val)
+			if rowIterator.columns != nil && blank <= 0 {
+				rowIterator.cells[pos-1] = val
+			} else {
+				rowIterator.cells = append(appendSpace(blank, rowIterator.cells), val)
+			}
+			if rowIterator.withRef {
+				cell, err := CoordinatesToCellName(rowIterator.cellCol, rows.curRow)
+				if err != nil {
+					rowIterator.err = err
+					return
+				}
+				rowIterator.rowCells = append(rowIterator.rowCells, RowCell{Ref: cell, Value: val, Type: cellTypes[colCell.T]})
+			}
 		}
 	}
 }
@@ -278,6 +395,9 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 	var err error
 	rows := Rows{f: f, sheet: name}
 	rows.needClose, rows.decoder, rows.tempFile, err = f.xmlDecoder(name)
+	if rows.tempFile != nil {
+		rows.tracker = newResourceTracker("Rows")
+	}
 	return &rows, err
 }
 
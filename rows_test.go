@@ -60,7 +60,6 @@ func TestRows(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "A19", value)
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetRow.xlsx")))
-	assert.NoError(t, f.Close())
 	
 	// Test rows iterator with unsupported charset shared strings table
 	f.SharedStrings = nil
@@ -69,6 +68,7 @@ func TestRows(t *testing.T) {
 	assert.NoError(t, err)
 	_, err = rows.Columns()
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
 }
 
 func TestRowsIterator(t *testing.T) {
@@ -237,6 +237,110 @@ func TestColumns(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRowsCells(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", "C1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", 100))
+	assert.NoError(t, f.SetCellBool("Sheet1", "B3", true))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D3", "E1*2"))
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var collectedCells [][]RowCell
+	for rows.Next() {
+		cells, err := rows.Cells()
+		assert.NoError(t, err)
+		collectedCells = append(collectedCells, cells)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, [][]RowCell{
+		{{Ref: "C1", Value: "C1", Type: CellTypeSharedString}, {Ref: "E1", Value: "100", Type: CellTypeUnset}},
+		nil,
+		{{Ref: "B3", Value: "TRUE", Type: CellTypeBool}, {Ref: "D3", Value: "", Type: CellTypeFormula}},
+	}, collectedCells)
+
+	// Test get cells with raw cell value and the cells without reference
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	rows.decoder = f.xmlNewDecoder(bytes.NewReader([]byte(`<worksheet><sheetData><row r="2"><c s="1"><v>1</v></c><c r="C2" t="b"><v>0</v></c><c><v>3</v></c></row></sheetData></worksheet>`)))
+	assert.True(t, rows.Next())
+	assert.True(t, rows.Next())
+	cells, err := rows.Cells(Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []RowCell{
+		{Ref: "A2", Value: "1", Type: CellTypeUnset},
+		{Ref: "C2", Value: "0", Type: CellTypeBool},
+		{Ref: "D2", Value: "3", Type: CellTypeUnset},
+	}, cells)
+
+	// Test get cells with invalid cell reference
+	rows.decoder = f.xmlNewDecoder(bytes.NewReader([]byte(`<worksheet><sheetData><row r="1"><c r="A" t="s"><v>1</v></c></row></sheetData></worksheet>`)))
+	rows.curRow, rows.seekRow = 0, 0
+	assert.True(t, rows.Next())
+	_, err = rows.Cells()
+	assert.EqualError(t, err, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	rows.decoder = f.xmlNewDecoder(bytes.NewReader([]byte(`<worksheet><sheetData><row r="1"><c r="XFD1"><v>1</v></c><c><v>2</v></c></row></sheetData></worksheet>`)))
+	rows.curRow, rows.seekRow = 0, 0
+	assert.True(t, rows.Next())
+	_, err = rows.Cells()
+	assert.EqualError(t, err, ErrColumnNumber.Error())
+	assert.NoError(t, rows.Close())
+
+	// Test get cells with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	_, err = rows.Cells()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, f.Close())
+}
+
+func TestGetRowsWithColumns(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"A1", "B1", "C1", "D1"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"A2", nil, "C2"}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "XFD2", "XFD2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D3", "D3"))
+	// Test get rows with column limit
+	rows, err := f.GetRows("Sheet1", Options{ColumnLimit: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1", "B1", "C1"}, {"A2", "", "C2"}}, rows)
+	// Test get rows with the specified columns
+	rows, err = f.GetRows("Sheet1", Options{Columns: []string{"D", "A", "XFD"}})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"D1", "A1"}, {"", "A2", "XFD2"}, {"D3"}}, rows)
+	rows, err = f.GetRows("Sheet1", Options{Columns: []string{"c", "D"}, ColumnLimit: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"C1"}, {"C2"}}, rows)
+	rows, err = f.GetRows("Sheet1", Options{Columns: []string{}})
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+	// Test get cells with the specified columns
+	iter, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, iter.Next())
+	assert.True(t, iter.Next())
+	cells, err := iter.Cells(Options{Columns: []string{"XFD", "A"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []RowCell{
+		{Ref: "A2", Value: "A2", Type: CellTypeSharedString},
+		{Ref: "XFD2", Value: "XFD2", Type: CellTypeSharedString},
+	}, cells)
+	// Test get rows with invalid column name
+	_, err = f.GetRows("Sheet1", Options{Columns: []string{"A", "-"}})
+	assert.EqualError(t, err, newInvalidColumnNameError("-").Error())
+	// Test get rows with duplicate column names
+	_, err = f.GetRows("Sheet1", Options{Columns: []string{"A", "D", "a"}})
+	assert.EqualError(t, err, newDuplicateColumnNameError("a").Error())
+	assert.True(t, iter.Next())
+	_, err = iter.Columns(Options{Columns: []string{"-"}})
+	assert.EqualError(t, err, newInvalidColumnNameError("-").Error())
+	assert.NoError(t, iter.Close())
+	assert.NoError(t, f.Close())
+}
+
 func TestSharedStringsReader(t *testing.T) {
 	f := NewFile()
 	// Test read shared string with unsupported charset
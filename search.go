@@ -0,0 +1,240 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"context"
+	"encoding/xml"
+	"regexp"
+	"strings"
+)
+
+// SearchOptions directly maps the settings of the workbook search by the
+// Search function.
+type SearchOptions struct {
+	Sheets          []string
+	Regexp          bool
+	MatchCase       bool
+	MatchEntireCell bool
+	Formula         bool
+	RawCellValue    bool
+	MaxResults      int
+	SnippetSize     int
+}
+
+// SearchResult directly maps the matched cell of the workbook search.
+type SearchResult struct {
+	Sheet   string
+	Cell    string
+	Value   string
+	Snippet string
+}
+
+// Search provides a function to search the cells in the workbook by given
+// context, pattern and search options. The worksheets will be decoded as the
+// XML token stream without being parsed into memory, and the searching stops
+// when the context is canceled, the matched results and the context error
+// will be returned. By default, the pattern is matched as a case-insensitive
+// substring of the formatted cell values, which is the same as the find
+// feature of Excel. The 'Value' of the result is the whole searched text of
+// the cell, and the 'Snippet' is the first matched text with the characters
+// around it. The options that can be set are:
+//
+//	 Option          | Description
+//	-----------------+---------------------------------------------------------
+//	 Sheets          | The worksheets to be searched in order, the default is
+//	                 | all worksheets of the workbook.
+//	                 |
+//	 Regexp          | Specifies if the pattern is a regular expression.
+//	                 |
+//	 MatchCase       | Specifies if the matching is case-sensitive.
+//	                 |
+//	 MatchEntireCell | Specifies if the pattern must match the entire cell
+//	                 | contents.
+//	                 |
+//	 Formula         | Specifies if search the formulas instead of the values
+//	                 | for the formula cells, the searched text of the formula
+//	                 | cells begins with the equal sign.
+//	                 |
+//	 RawCellValue    | Specifies if search the raw cell values without applying
+//	                 | the number format.
+//	                 |
+//	 MaxResults      | The maximum number of the results, the default value 0
+//	                 | means no limit.
+//	                 |
+//	 SnippetSize     | The maximum number of characters before and after the
+//	                 | matched text in the snippet, the default value is 20.
+//
+// For example, search the cells which contain the SUM function in all
+// worksheets:
+//
+//	results, err := f.Search(context.Background(), `SUM\(`, &excelize.SearchOptions{
+//	    Regexp:  true,
+//	    Formula: true,
+//	})
+func (f *File) Search(ctx context.Context, pattern string, opts *SearchOptions) ([]SearchResult, error) {
+	var results []SearchResult
+	if pattern == "" {
+		return results, ErrParameterRequired
+	}
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	expr := pattern
+	if !opts.Regexp {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if opts.MatchEntireCell {
+		expr = "^(?:" + expr + ")$"
+	}
+	if !opts.MatchCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return results, err
+	}
+	sheets := opts.Sheets
+	if sheets == nil {
+		sheets = f.GetSheetList()
+	}
+	for _, sheet := range sheets {
+		if results, err = f.searchSheetStream(ctx, sheet, re, opts, results); err != nil {
+			return results, err
+		}
+		if opts.MaxResults > 0 && len(results) >= opts.MaxResults {
+			break
+		}
+	}
+	return results, err
+}
+
+// searchSheetStream provides a function to search the cells in the worksheet
+// by given context, worksheet name, regular expression and search options,
+// and append the matched cells to the results.
+func (f *File) searchSheetStream(ctx context.Context, sheet string, re *regexp.Regexp, opts *SearchOptions, results []SearchResult) ([]SearchResult, error) {
+	if err := checkSheetName(sheet); err != nil {
+		return results, err
+	}
+	name, ok := f.getSheetXMLPath(sheet)
+	if !ok {
+		return results, ErrSheetNotExist{sheet}
+	}
+	if ws, ok := f.Sheet.Load(name); ok && ws != nil {
+		// Flush data
+		output, _ := xml.Marshal(ws.(*xlsxWorksheet))
+		f.saveFileList(name, f.replaceNameSpaceBytes(name, output))
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return results, err
+	}
+	needClose, decoder, tempFile, err := f.xmlDecoder(name)
+	if needClose && err == nil {
+		defer tempFile.Close()
+	}
+	if err != nil {
+		return results, err
+	}
+	var (
+		col, row       int
+		sharedFormulas = map[int]*xlsxC{}
+	)
+	for {
+		token, _ := decoder.Token()
+		if token == nil {
+			return results, err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if element.Name.Local == "row" {
+				if err = ctx.Err(); err != nil {
+					return results, err
+				}
+				if r, _ := attrValToInt("r", element.Attr); r > 0 {
+					row = r
+				} else {
+					row++
+				}
+				col = 0
+			}
+			if element.Name.Local != "c" {
+				continue
+			}
+			var c xlsxC
+			if err = decoder.DecodeElement(&c, &element); err != nil {
+				return results, err
+			}
+			col++
+			if c.R != "" {
+				if col, _, err = CellNameToCoordinates(c.R); err != nil {
+					return results, err
+				}
+			}
+			cell, _ := CoordinatesToCellName(col, row)
+			var text string
+			if opts.Formula && c.F != nil {
+				text = "=" + c.F.Content
+				if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+					if c.F.Ref != "" {
+						sharedFormulas[*c.F.Si] = &xlsxC{R: cell, F: c.F}
+					} else if master, ok := sharedFormulas[*c.F.Si]; ok {
+						text = "=" + shiftSharedFormula(master.F.Content, master.R, cell)
+					}
+				}
+			} else if text, err = c.getValueFrom(f, sst, opts.RawCellValue); err != nil {
+				return results, err
+			}
+			loc := re.FindStringIndex(text)
+			if loc == nil {
+				continue
+			}
+			results = append(results, SearchResult{
+				Sheet:   sheet,
+				Cell:    cell,
+				Value:   text,
+				Snippet: searchSnippet(text, loc, opts.SnippetSize),
+			})
+			if opts.MaxResults > 0 && len(results) >= opts.MaxResults {
+				return results, err
+			}
+		case xml.EndElement:
+			if element.Name.Local == "sheetData" {
+				return results, err
+			}
+		}
+	}
+}
+
+// searchSnippet provides a function to get the snippet of the searched text
+// by given text, the location of the matched text and the maximum number of
+// characters around the matched text.
+func searchSnippet(text string, loc []int, size int) string {
+	if size <= 0 {
+		size = 20
+	}
+	var snippet strings.Builder
+	before, after := []rune(text[:loc[0]]), []rune(text[loc[1]:])
+	if len(before) > size {
+		snippet.WriteString("...")
+		before = before[len(before)-size:]
+	}
+	snippet.WriteString(string(before))
+	snippet.WriteString(text[loc[0]:loc[1]])
+	if len(after) > size {
+		snippet.WriteString(string(after[:size]))
+		snippet.WriteString("...")
+		return snippet.String()
+	}
+	snippet.WriteString(string(after))
+	return snippet.String()
+}
@@ -0,0 +1,103 @@
+package excel
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Apple", "pineapple juice", 100}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "The quick brown fox jumps over the lazy dog near the apple tree"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", "SUM(C1,C1)"))
+	formulaType, ref := STCellFormulaTypeShared, "D1:D3"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "C1*2", FormulaOpts{Ref: &ref, Type: &formulaType}))
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet2", "B5", "APPLE"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSearch.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestSearch.xlsx"))
+	assert.NoError(t, err)
+	ctx := context.Background()
+	// Test search the case-insensitive substring in all worksheets
+	results, err := f.Search(ctx, "apple", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchResult{
+		{Sheet: "Sheet1", Cell: "A1", Value: "Apple", Snippet: "Apple"},
+		{Sheet: "Sheet1", Cell: "B1", Value: "pineapple juice", Snippet: "pineapple juice"},
+		{Sheet: "Sheet1", Cell: "A3", Value: "The quick brown fox jumps over the lazy dog near the apple tree", Snippet: "...e lazy dog near the apple tree"},
+		{Sheet: "Sheet2", Cell: "B5", Value: "APPLE", Snippet: "APPLE"},
+	}, results)
+	// Test search with match case, entire cell, specified worksheets and maximum results
+	results, err = f.Search(ctx, "Apple", &SearchOptions{MatchCase: true, MatchEntireCell: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchResult{{Sheet: "Sheet1", Cell: "A1", Value: "Apple", Snippet: "Apple"}}, results)
+	results, err = f.Search(ctx, "apple", &SearchOptions{Sheets: []string{"Sheet2", "Sheet1"}, MaxResults: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchResult{
+		{Sheet: "Sheet2", Cell: "B5", Value: "APPLE", Snippet: "APPLE"},
+		{Sheet: "Sheet1", Cell: "A1", Value: "Apple", Snippet: "Apple"},
+	}, results)
+	// Test search with regular expression and snippet size
+	results, err = f.Search(ctx, `ju\w+`, &SearchOptions{Regexp: true, SnippetSize: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchResult{
+		{Sheet: "Sheet1", Cell: "B1", Value: "pineapple juice", Snippet: "...ple juice"},
+		{Sheet: "Sheet1", Cell: "A3", Value: "The quick brown fox jumps over the lazy dog near the apple tree", Snippet: "...fox jumps ove..."},
+	}, results)
+	// Test search the formulas with shared formula
+	results, err = f.Search(ctx, "C[0-9]", &SearchOptions{Regexp: true, Formula: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchResult{
+		{Sheet: "Sheet1", Cell: "D1", Value: "=C1*2", Snippet: "=C1*2"},
+		{Sheet: "Sheet1", Cell: "B2", Value: "=SUM(C1,C1)", Snippet: "=SUM(C1,C1)"},
+		{Sheet: "Sheet1", Cell: "D2", Value: "=C2*2", Snippet: "=C2*2"},
+		{Sheet: "Sheet1", Cell: "D3", Value: "=C3*2", Snippet: "=C3*2"},
+	}, results)
+	// Test search with the loaded worksheet
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", "Green apple"))
+	results, err = f.Search(ctx, "green", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchResult{{Sheet: "Sheet1", Cell: "E1", Value: "Green apple", Snippet: "Green apple"}}, results)
+	// Test search with canceled context
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	results, err = f.Search(canceled, "apple", nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, results)
+	// Test search with empty pattern
+	_, err = f.Search(ctx, "", nil)
+	assert.Equal(t, ErrParameterRequired, err)
+	// Test search with invalid regular expression
+	_, err = f.Search(ctx, "(", &SearchOptions{Regexp: true})
+	assert.EqualError(t, err, "error parsing regexp: missing closing ): `(?i)(`")
+	// Test search with invalid and not exist worksheet
+	_, err = f.Search(ctx, "apple", &SearchOptions{Sheets: []string{"Sheet:1"}})
+	assert.EqualError(t, err, ErrSheetNameInvalid.Error())
+	_, err = f.Search(ctx, "apple", &SearchOptions{Sheets: []string{"SheetN"}})
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test search with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	_, err = f.Search(ctx, "apple", nil)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	f = NewFile()
+	// Test search with invalid cell reference
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet><sheetData><row><c r="A"><v>1</v></c></row></sheetData></worksheet>`))
+	_, err = f.Search(ctx, "1", nil)
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	assert.NoError(t, f.Close())
+}
+
+func TestSearchSnippet(t *testing.T) {
+	assert.Equal(t, "...é match é...", searchSnippet("ééé match ééé", []int{7, 12}, 2))
+	assert.Equal(t, "match", searchSnippet("match", []int{0, 5}, 0))
+}
@@ -43,6 +43,17 @@ func parseShapeOptions(opts *Shape) (*Shape, error) {
 	if opts.Line.Width == nil {
 		opts.Line.Width = float64Ptr(defaultShapeLineWidth)
 	}
+	if opts.VerticalAlign != "" && inStrSlice(supportedShapeVerticalAlign, opts.VerticalAlign, true) == -1 {
+		return opts, newInvalidOptionalValue("VerticalAlign", opts.VerticalAlign, supportedShapeVerticalAlign)
+	}
+	if opts.AutoFit != "" && inStrSlice(supportedShapeAutoFit, opts.AutoFit, true) == -1 {
+		return opts, newInvalidOptionalValue("AutoFit", opts.AutoFit, supportedShapeAutoFit)
+	}
+	for _, p := range opts.Paragraph {
+		if p.Alignment != "" && inStrSlice(supportedShapeAlignment, p.Alignment, true) == -1 {
+			return opts, newInvalidOptionalValue("Alignment", p.Alignment, supportedShapeAlignment)
+		}
+	}
 	return opts, nil
 }
 
@@ -75,6 +86,45 @@ func parseShapeOptions(opts *Shape) (*Shape, error) {
 //	    },
 //	)
 //
+// The optional parameter "VerticalAlign" specifies the vertical alignment of
+// the text in the shape, the supported values are "top", "center" and
+// "bottom", the default value is "top".
+//
+// The optional parameter "AutoFit" specifies how the text fits in the shape,
+// the supported values are "none" (do not auto-fit), "shape" (resize the shape
+// to fit the text) and "text" (shrink the text on overflow).
+//
+// The optional parameter "WrapText" specifies whether wrap the text in the
+// shape, the default value is false.
+//
+// Each paragraph of the shape text could contains multiple runs with
+// different formats by "Runs", the "Text" of the paragraph will be ignored if
+// the runs are specified, and the runs without font will use the font of the
+// paragraph. The optional parameter "Alignment" of the paragraph specifies the
+// horizontal alignment of the paragraph, the supported values are "left",
+// "center", "right", "justify" and "distributed". For example, add a callout
+// with a bold title and a wrapped description:
+//
+//	err := f.AddShape("Sheet1", "B2", &excelize.Shape{
+//	    Type:          "wedgeRectCallout",
+//	    Width:         240,
+//	    Height:        120,
+//	    VerticalAlign: "center",
+//	    AutoFit:       "text",
+//	    WrapText:      true,
+//	    Paragraph: []excelize.ShapeParagraph{
+//	        {Text: "Note", Font: excelize.Font{Bold: true, Size: 14}, Alignment: "center"},
+//	        {
+//	            Font: excelize.Font{Size: 11},
+//	            Runs: []excelize.RichTextRun{
+//	                {Text: "Sales grew "},
+//	                {Text: "12%", Font: &excelize.Font{Bold: true, Color: "#2E7D32", Size: 11}},
+//	                {Text: " compared to the last quarter."},
+//	            },
+//	        },
+//	    },
+//	})
+//
 // The following shows the type of shape supported by excelize:
 //
 //	accentBorderCallout1 (Callout 1 with Border and Accent Shape)
@@ -389,6 +439,21 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, opts *Shape) erro
 			},
 		},
 	}
+	if opts.VerticalAlign != "" {
+		shape.TxBody.BodyPr.Anchor = map[string]string{"top": "t", "center": "ctr", "bottom": "b"}[opts.VerticalAlign]
+	}
+	if opts.WrapText {
+		shape.TxBody.BodyPr.Wrap = "square"
+	}
+	switch opts.AutoFit {
+	case "none":
+		shape.TxBody.BodyPr.NoAutofit = stringPtr("")
+	case "shape":
+		shape.TxBody.BodyPr.VertOverflow = "overflow"
+		shape.TxBody.BodyPr.SpAutoFit = stringPtr("")
+	case "text":
+		shape.TxBody.BodyPr.NormAutofit = stringPtr("")
+	}
 	if *opts.Line.Width != 1 {
 		shape.SpPr.Ln = xlsxLineProperties{
 			W: f.ptToEMUs(*opts.Line.Width),
@@ -414,39 +479,27 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, opts *Shape) erro
 		}
 	}
 	for _, p := range opts.Paragraph {
-		u := "none"
-		if idx := inStrSlice(supportedDrawingUnderlineTypes, p.Font.Underline, true); idx != -1 {
-			u = supportedDrawingUnderlineTypes[idx]
-		}
-		text := p.Text
-		if text == "" {
-			text = " "
+		runs := p.Runs
+		if len(runs) == 0 {
+			runs = []RichTextRun{{Text: p.Text}}
 		}
 		paragraph := &aP{
-			R: &aR{
-				RPr: aRPr{
-					I:       p.Font.Italic,
-					B:       p.Font.Bold,
-					Lang:    "en-US",
-					AltLang: "en-US",
-					U:       u,
-					Sz:      p.Font.Size * 100,
-					Latin:   &xlsxCTTextFont{Typeface: p.Font.Family},
-				},
-				T: text,
-			},
 			EndParaRPr: &aEndParaRPr{
 				Lang: "en-US",
 			},
 		}
-		srgbClr := strings.ReplaceAll(strings.ToUpper(p.Font.Color), "#", "")
-		if len(srgbClr) == 6 {
-			paragraph.R.RPr.SolidFill = &aSolidFill{
-				SrgbClr: &attrValString{
-					Val: stringPtr(srgbClr),
-				},
+		if p.Alignment != "" {
+			paragraph.PPr = &aPPr{
+				Algn: map[string]string{"left": "l", "center": "ctr", "right": "r", "justify": "just", "distributed": "dist"}[p.Alignment],
 			}
 		}
+		for _, run := range runs {
+			font := p.Font
+			if run.Font != nil {
+				font = *run.Font
+			}
+			paragraph.R = append(paragraph.R, drawShapeRun(font, run.Text))
+		}
 		shape.TxBody.P = append(shape.TxBody.P, paragraph)
 	}
 	twoCellAnchor.Sp = &shape
@@ -459,6 +512,39 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, opts *Shape) erro
 	return err
 }
 
+// drawShapeRun provides a function to draw the a:r element of the shape text
+// by given font and text.
+func drawShapeRun(font Font, text string) *aR {
+	u := "none"
+	if idx := inStrSlice(supportedDrawingUnderlineTypes, font.Underline, true); idx != -1 {
+		u = supportedDrawingUnderlineTypes[idx]
+	}
+	if text == "" {
+		text = " "
+	}
+	run := &aR{
+		RPr: aRPr{
+			I:       font.Italic,
+			B:       font.Bold,
+			Lang:    "en-US",
+			AltLang: "en-US",
+			U:       u,
+			Sz:      font.Size * 100,
+			Latin:   &xlsxCTTextFont{Typeface: font.Family},
+		},
+		T: text,
+	}
+	srgbClr := strings.ReplaceAll(strings.ToUpper(font.Color), "#", "")
+	if len(srgbClr) == 6 {
+		run.RPr.SolidFill = &aSolidFill{
+			SrgbClr: &attrValString{
+				Val: stringPtr(srgbClr),
+			},
+		}
+	}
+	return run
+}
+
 // setShapeRef provides a function to set color with hex model by given actual
 // color value.
 func setShapeRef(color string, i int) *aRef {
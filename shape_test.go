@@ -95,3 +95,62 @@ func TestAddDrawingShape(t *testing.T) {
 		},
 	), "XML syntax error on line 1: invalid UTF-8")
 }
+
+func TestAddShapeWithRichText(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddShape("Sheet1", "B2", &Shape{
+		Type:          "wedgeRectCallout",
+		Width:         240,
+		Height:        120,
+		VerticalAlign: "center",
+		AutoFit:       "shape",
+		WrapText:      true,
+		Paragraph: []ShapeParagraph{
+			{Text: "Note", Font: Font{Bold: true, Size: 14}, Alignment: "center"},
+			{
+				Font: Font{Size: 11, Color: "#333333"},
+				Runs: []RichTextRun{
+					{Text: "Sales grew "},
+					{Text: "12%", Font: &Font{Bold: true, Color: "#2E7D32", Size: 11}},
+					{Text: " compared to the last quarter."},
+				},
+			},
+		},
+	}))
+	drawing, ok := f.Drawings.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	txBody := drawing.(*xlsxWsDr).TwoCellAnchor[0].Sp.TxBody
+	assert.Equal(t, "ctr", txBody.BodyPr.Anchor)
+	assert.Equal(t, "square", txBody.BodyPr.Wrap)
+	assert.Equal(t, "overflow", txBody.BodyPr.VertOverflow)
+	assert.NotNil(t, txBody.BodyPr.SpAutoFit)
+	assert.Len(t, txBody.P, 2)
+	assert.Equal(t, "ctr", txBody.P[0].PPr.Algn)
+	assert.Len(t, txBody.P[0].R, 1)
+	assert.True(t, txBody.P[0].R[0].RPr.B)
+	assert.Nil(t, txBody.P[1].PPr)
+	assert.Len(t, txBody.P[1].R, 3)
+	assert.Equal(t, "12%", txBody.P[1].R[1].T)
+	assert.True(t, txBody.P[1].R[1].RPr.B)
+	assert.Equal(t, "2E7D32", *txBody.P[1].R[1].RPr.SolidFill.SrgbClr.Val)
+	assert.False(t, txBody.P[1].R[2].RPr.B)
+	assert.Equal(t, "333333", *txBody.P[1].R[2].RPr.SolidFill.SrgbClr.Val)
+	assert.NoError(t, f.AddShape("Sheet1", "H2", &Shape{Type: "rect", AutoFit: "none", Paragraph: []ShapeParagraph{{Text: "None"}}}))
+	assert.NoError(t, f.AddShape("Sheet1", "H10", &Shape{Type: "rect", AutoFit: "text", VerticalAlign: "bottom", Paragraph: []ShapeParagraph{{Text: "Text", Alignment: "right"}}}))
+	txBody = drawing.(*xlsxWsDr).TwoCellAnchor[1].Sp.TxBody
+	assert.NotNil(t, txBody.BodyPr.NoAutofit)
+	assert.Equal(t, "none", txBody.BodyPr.Wrap)
+	txBody = drawing.(*xlsxWsDr).TwoCellAnchor[2].Sp.TxBody
+	assert.NotNil(t, txBody.BodyPr.NormAutofit)
+	assert.Equal(t, "b", txBody.BodyPr.Anchor)
+	assert.Equal(t, "r", txBody.P[0].PPr.Algn)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddShapeWithRichText.xlsx")))
+	// Test add shape with invalid options
+	assert.Equal(t, newInvalidOptionalValue("VerticalAlign", "middle", supportedShapeVerticalAlign),
+		f.AddShape("Sheet1", "A1", &Shape{Type: "rect", VerticalAlign: "middle"}))
+	assert.Equal(t, newInvalidOptionalValue("AutoFit", "all", supportedShapeAutoFit),
+		f.AddShape("Sheet1", "A1", &Shape{Type: "rect", AutoFit: "all"}))
+	assert.Equal(t, newInvalidOptionalValue("Alignment", "middle", supportedShapeAlignment),
+		f.AddShape("Sheet1", "A1", &Shape{Type: "rect", Paragraph: []ShapeParagraph{{Alignment: "middle"}}}))
+	assert.NoError(t, f.Close())
+}
@@ -13,6 +13,7 @@ package excel
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -24,6 +25,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf16"
 	"unicode/utf8"
 	
@@ -135,9 +137,10 @@ func (f *File) mergeExpandedCols(ws *xlsxWorksheet) {
 
 // workSheetWriter provides a function to save xl/worksheets/sheet%d.xml after
 // serialize structure.
-func (f *File) workSheetWriter() {
+func (f *File) workSheetWriter() error {
 	var (
 		arr     []byte
+		err     error
 		buffer  = bytes.NewBuffer(arr)
 		encoder = xml.NewEncoder(buffer)
 	)
@@ -150,6 +153,11 @@ func (f *File) workSheetWriter() {
 			if sheet.Cols != nil && len(sheet.Cols.Col) > 0 {
 				f.mergeExpandedCols(sheet)
 			}
+			if sheet.AutoFilter != nil && sheet.AutoFilter.applyFilter {
+				if err = f.applyAutoFilter(sheet); err != nil {
+					return false
+				}
+			}
 			sheet.SheetData.Row = trimRow(&sheet.SheetData)
 			if sheet.SheetPr != nil || sheet.Drawing != nil || sheet.Hyperlinks != nil || sheet.Picture != nil || sheet.TableParts != nil {
 				f.addNameSpaces(p.(string), SourceRelationship)
@@ -173,6 +181,7 @@ func (f *File) workSheetWriter() {
 		}
 		return true
 	})
+	return err
 }
 
 // trimRow provides a function to trim empty rows.
@@ -440,6 +449,88 @@ func (f *File) GetSheetMap() map[int]string {
 	return sheetMap
 }
 
+// GetSheetDimension provides a function to get the dimension of the used
+// cells in the worksheet by given worksheet name. The dimension recorded in
+// the spreadsheet which saved with the SheetDimensions option will be used
+// without loading the worksheet if it has not been modified. For example, get
+// the number of the last row of the used cells on Sheet1:
+//
+//	dimension, err := f.GetSheetDimension("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println(dimension.Rows)
+func (f *File) GetSheetDimension(sheet string) (SheetDimension, error) {
+	if err := checkSheetName(sheet); err != nil {
+		return SheetDimension{}, err
+	}
+	name, ok := f.getSheetXMLPath(sheet)
+	if !ok {
+		return SheetDimension{}, newNoExistSheetError(sheet)
+	}
+	if sw, ok := f.streams[name]; ok {
+		return newSheetDimension(sw.dimension)
+	}
+	if _, ok := f.Sheet.Load(name); !ok {
+		if dimension, ok := f.sheetDimensions[name]; ok {
+			return dimension, nil
+		}
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return SheetDimension{}, err
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	var dimension []int
+	for rowIdx := range ws.SheetData.Row {
+		for colIdx := range ws.SheetData.Row[rowIdx].C {
+			c := &ws.SheetData.Row[rowIdx].C[colIdx]
+			if !c.hasValue() {
+				continue
+			}
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return SheetDimension{}, err
+			}
+			dimension = extendDimension(dimension, col, row)
+		}
+	}
+	return newSheetDimension(dimension)
+}
+
+// extendDimension returns the coordinates of the dimension which extended by
+// given column and row number.
+func extendDimension(dimension []int, col, row int) []int {
+	if dimension == nil {
+		return []int{col, row, col, row}
+	}
+	if col < dimension[0] {
+		dimension[0] = col
+	}
+	if row < dimension[1] {
+		dimension[1] = row
+	}
+	if col > dimension[2] {
+		dimension[2] = col
+	}
+	if row > dimension[3] {
+		dimension[3] = row
+	}
+	return dimension
+}
+
+// newSheetDimension create the dimension of the worksheet by given
+// coordinates of the used cells.
+func newSheetDimension(dimension []int) (SheetDimension, error) {
+	if dimension == nil {
+		return SheetDimension{}, nil
+	}
+	ref, err := (&File{}).coordinatesToRangeRef(dimension)
+	return SheetDimension{Ref: ref, Rows: dimension[3], Cols: dimension[2]}, err
+}
+
 // GetSheetList provides a function to get worksheets, chart sheets, and
 // dialog sheets name list of the workbook.
 func (f *File) GetSheetList() (list []string) {
@@ -575,7 +666,10 @@ func (f *File) DeleteSheet(sheet string) error {
 				}
 			}
 		}
-		target := f.deleteSheetFromWorkbookRels(v.ID)
+		target, err := f.deleteSheetFromWorkbookRels(v.ID)
+		if err != nil {
+			return err
+		}
 		_ = f.deleteSheetFromContentTypes(target)
 		_ = f.deleteCalcChain(f.getSheetID(sheet), "")
 		delete(f.sheetMap, v.Name)
@@ -613,17 +707,20 @@ func deleteAndAdjustDefinedNames(wb *xlsxWorkbook, deleteLocalSheetID int) {
 
 // deleteSheetFromWorkbookRels provides a function to remove worksheet
 // relationships by given relationships ID in the file workbook.xml.rels.
-func (f *File) deleteSheetFromWorkbookRels(rID string) string {
-	rels, _ := f.relsReader(f.getWorkbookRelsPath())
+func (f *File) deleteSheetFromWorkbookRels(rID string) (string, error) {
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil || rels == nil {
+		return "", err
+	}
 	rels.Lock()
 	defer rels.Unlock()
 	for k, v := range rels.Relationships {
 		if v.ID == rID {
 			rels.Relationships = append(rels.Relationships[:k], rels.Relationships[k+1:]...)
-			return v.Target
+			return v.Target, err
 		}
 	}
-	return ""
+	return "", err
 }
 
 // deleteSheetFromContentTypes provides a function to remove worksheet
@@ -748,11 +845,54 @@ func (f *File) SetSheetVisible(sheet string, visible bool, veryHidden ...bool) e
 	return err
 }
 
+// PaneType is the type of the pane in the worksheet view with split or
+// frozen panes, the value of the pane type can be used as the
+// 'Panes.ActivePane' and 'PaneOptions.Pane' by converting it to a string.
+// These settings are still string fields, so an invalid pane will be reported
+// by SetPanes at runtime instead of at compile time.
+type PaneType string
+
+// This section defines the currently supported pane types, these untyped
+// constants can be used as both the pane settings and PaneType.
+const (
+	PaneTopLeft     = "topLeft"
+	PaneTopRight    = "topRight"
+	PaneBottomLeft  = "bottomLeft"
+	PaneBottomRight = "bottomRight"
+)
+
+// supportedPaneTypes defined supported pane types.
+var supportedPaneTypes = []string{"topLeft", "topRight", "bottomLeft", "bottomRight"}
+
+// String returns the name of the pane type.
+func (t PaneType) String() string {
+	return string(t)
+}
+
+// ParsePaneType provides a function to parse the pane type by given pane
+// name, for example, parse the bottom right pane type:
+//
+//	paneType, err := excelize.ParsePaneType("bottomRight")
+func ParsePaneType(name string) (PaneType, error) {
+	if inStrSlice(supportedPaneTypes, name, true) == -1 {
+		return PaneType(name), newInvalidOptionalValue("Pane", name, supportedPaneTypes)
+	}
+	return PaneType(name), nil
+}
+
 // setPanes set create freeze panes and split panes by given options.
 func (ws *xlsxWorksheet) setPanes(panes *Panes) error {
 	if panes == nil {
 		return ErrParameterInvalid
 	}
+	if _, err := ParsePaneType(panes.ActivePane); panes.ActivePane != "" && err != nil {
+		return newInvalidOptionalValue("ActivePane", panes.ActivePane, supportedPaneTypes)
+	}
+	for _, p := range panes.Panes {
+		if _, err := ParsePaneType(p.Pane); p.Pane != "" && err != nil {
+			return err
+		}
+	}
 	p := &xlsxPane{
 		ActivePane:  panes.ActivePane,
 		TopLeftCell: panes.TopLeftCell,
@@ -1538,8 +1678,11 @@ func (f *File) GetPageLayout(sheet string) (PageLayoutOptions, error) {
 }
 
 // SetDefinedName provides a function to set the defined names of the workbook
-// or worksheet. If not specified scope, the default scope is workbook.
-// For example:
+// or worksheet. If not specified scope, the default scope is workbook. The
+// name of the defined name should be matches the naming rules of the defined
+// names, and the error ErrDefinedNameDuplicate will be returned if the same
+// name already exists on the scope, the names are case-insensitive. For
+// example:
 //
 //	err := f.SetDefinedName(&excelize.DefinedName{
 //	    Name:     "Amount",
@@ -1548,38 +1691,203 @@ func (f *File) GetPageLayout(sheet string) (PageLayoutOptions, error) {
 //	    Scope:    "Sheet2",
 //	})
 func (f *File) SetDefinedName(definedName *DefinedName) error {
-	if definedName.Name == "" || definedName.RefersTo == "" {
-		return ErrParameterInvalid
-	}
 	wb, err := f.workbookReader()
 	if err != nil {
 		return err
 	}
+	_, err = f.setDefinedName(wb, definedName, DefinedNameConflictError)
+	return err
+}
+
+// SetDefinedNames provides a function to set the defined names of the
+// workbook or worksheets in bulk by given policy on the names which already
+// exist on the same scope. The conflict policy could be one of the
+// following:
+//
+//	 Policy                       | Description
+//	------------------------------+---------------------------------------------
+//	 DefinedNameConflictError     | Return the error ErrDefinedNameDuplicate
+//	 DefinedNameConflictOverwrite | Overwrite the reference and comment
+//	 DefinedNameConflictRename    | Rename with the suffix such as "Amount_1"
+//
+// This function returns the defined names which have been set, with the new
+// names for the renamed defined names. No defined name will be set if any of
+// the given defined names is invalid. For example, set defined names and
+// rename the names which already exist:
+//
+//	definedNames, err := f.SetDefinedNames([]excelize.DefinedName{
+//	    {Name: "Amount", RefersTo: "Sheet1!$A$2:$D$5"},
+//	    {Name: "Price", RefersTo: "Sheet1!$E$2:$E$5", Scope: "Sheet1"},
+//	}, excelize.DefinedNameConflictRename)
+func (f *File) SetDefinedNames(definedNames []DefinedName, policy DefinedNameConflict) ([]DefinedName, error) {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		results []DefinedName
+		origin  *xlsxDefinedNames
+	)
+	if wb.DefinedNames != nil {
+		origin = &xlsxDefinedNames{DefinedName: append([]xlsxDefinedName{}, wb.DefinedNames.DefinedName...)}
+	}
+	for i := range definedNames {
+		definedName, err := f.setDefinedName(wb, &definedNames[i], policy)
+		if err != nil {
+			wb.DefinedNames = origin
+			return nil, err
+		}
+		results = append(results, definedName)
+	}
+	return results, err
+}
+
+// ExportDefinedNames provides a function to export all defined names of the
+// workbook and worksheets as JSON, which could be imported by the
+// ImportDefinedNames function. For example:
+//
+//	data, err := f.ExportDefinedNames()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := os.WriteFile("names.json", data, 0644); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ExportDefinedNames() ([]byte, error) {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, err
+	}
+	definedNames := []DefinedName{}
+	return json.Marshal(append(definedNames, f.getDefinedNames(wb)...))
+}
+
+// ImportDefinedNames provides a function to import the defined names from
+// JSON exported by the ExportDefinedNames function, by given policy on the
+// names which already exist on the same scope. The scope "Workbook" or empty
+// scope specifies the workbook scope. This function returns the defined
+// names which have been imported. For example, import defined names from the
+// file "names.json" and overwrite the names which already exist:
+//
+//	data, err := os.ReadFile("names.json")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	definedNames, err := f.ImportDefinedNames(data, excelize.DefinedNameConflictOverwrite)
+func (f *File) ImportDefinedNames(data []byte, policy DefinedNameConflict) ([]DefinedName, error) {
+	var definedNames []DefinedName
+	if err := json.Unmarshal(data, &definedNames); err != nil {
+		return nil, err
+	}
+	return f.SetDefinedNames(definedNames, policy)
+}
+
+// setDefinedName set the defined name of the workbook or worksheet by given
+// conflict policy, and returns the defined name which has been set.
+func (f *File) setDefinedName(wb *xlsxWorkbook, definedName *DefinedName, policy DefinedNameConflict) (DefinedName, error) {
+	result := DefinedName{Scope: "Workbook"}
+	if definedName == nil || definedName.Name == "" || definedName.RefersTo == "" {
+		return result, ErrParameterInvalid
+	}
+	if err := checkDefinedName(definedName.Name); err != nil {
+		return result, err
+	}
 	d := xlsxDefinedName{
 		Name:    definedName.Name,
 		Comment: definedName.Comment,
 		Data:    definedName.RefersTo,
 	}
-	if definedName.Scope != "" {
-		if sheetIndex, _ := f.GetSheetIndex(definedName.Scope); sheetIndex >= 0 {
-			d.LocalSheetID = &sheetIndex
+	if definedName.Scope != "" && definedName.Scope != "Workbook" {
+		sheetIndex, err := f.GetSheetIndex(definedName.Scope)
+		if err != nil {
+			return result, err
+		}
+		if sheetIndex == -1 {
+			return result, ErrSheetNotExist{definedName.Scope}
 		}
+		d.LocalSheetID, result.Scope = intPtr(sheetIndex), f.GetSheetName(sheetIndex)
 	}
-	if wb.DefinedNames != nil {
-		for _, dn := range wb.DefinedNames.DefinedName {
-			var scope string
-			if dn.LocalSheetID != nil {
-				scope = f.GetSheetName(*dn.LocalSheetID)
-			}
-			if scope == definedName.Scope && dn.Name == definedName.Name {
-				return ErrDefinedNameDuplicate
-			}
+	if wb.DefinedNames == nil {
+		wb.DefinedNames = &xlsxDefinedNames{}
+	}
+	if idx := getDefinedNameIndex(wb, d.Name, d.LocalSheetID); idx != -1 {
+		switch policy {
+		case DefinedNameConflictOverwrite:
+			wb.DefinedNames.DefinedName[idx].Comment = d.Comment
+			wb.DefinedNames.DefinedName[idx].Data = d.Data
+			result.Name, result.Comment, result.RefersTo = wb.DefinedNames.DefinedName[idx].Name, d.Comment, d.Data
+			return result, nil
+		case DefinedNameConflictRename:
+			d.Name = getUniqueDefinedName(wb, d.Name, d.LocalSheetID)
+		default:
+			return result, ErrDefinedNameDuplicate
 		}
-		wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, d)
-		return nil
 	}
-	wb.DefinedNames = &xlsxDefinedNames{
-		DefinedName: []xlsxDefinedName{d},
+	wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, d)
+	result.Name, result.Comment, result.RefersTo = d.Name, d.Comment, d.Data
+	return result, nil
+}
+
+// getDefinedNameIndex returns the index of the defined name in the workbook
+// by given case-insensitive name and the local sheet ID, and returns -1 if
+// the defined name doesn't exist on the scope.
+func getDefinedNameIndex(wb *xlsxWorkbook, name string, localSheetID *int) int {
+	if wb.DefinedNames == nil {
+		return -1
+	}
+	for idx, dn := range wb.DefinedNames.DefinedName {
+		if (dn.LocalSheetID == nil) != (localSheetID == nil) ||
+			(dn.LocalSheetID != nil && *dn.LocalSheetID != *localSheetID) {
+			continue
+		}
+		if strings.EqualFold(dn.Name, name) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// getUniqueDefinedName returns a name with the number suffix which doesn't
+// exist on the scope of the given local sheet ID.
+func getUniqueDefinedName(wb *xlsxWorkbook, name string, localSheetID *int) string {
+	for i := 1; ; i++ {
+		suffix, base := fmt.Sprintf("_%d", i), []rune(name)
+		if len(base)+len(suffix) > MaxFieldLength {
+			base = base[:MaxFieldLength-len(suffix)]
+		}
+		if uniqueName := string(base) + suffix; getDefinedNameIndex(wb, uniqueName, localSheetID) == -1 {
+			return uniqueName
+		}
+	}
+}
+
+// checkDefinedName check whether the defined name matches the naming rules
+// of the defined names:
+//
+// 1. The name should be no more than 255 characters
+// 2. The first character must be a letter, an underscore or a backslash
+// 3. The remaining characters can be letters, numbers, periods, underscores,
+// backslashes and question marks
+// 4. The name can not be the same as a cell reference, such as "A1" or
+// "R1C1", and can not be the single letter "C", "c", "R" or "r"
+func checkDefinedName(name string) error {
+	if utf8.RuneCountInString(name) > MaxFieldLength {
+		return newInvalidDefinedNameError(name)
+	}
+	for i, r := range name {
+		if unicode.IsLetter(r) || r == '_' || r == '\\' ||
+			(i > 0 && (unicode.IsDigit(r) || r == '.' || r == '?')) {
+			continue
+		}
+		return newInvalidDefinedNameError(name)
+	}
+	if strings.EqualFold(name, "C") || strings.EqualFold(name, "R") || r1c1RefExp.MatchString(name) {
+		return newInvalidDefinedNameError(name)
+	}
+	if _, _, err := CellNameToCoordinates(name); err == nil {
+		return newInvalidDefinedNameError(name)
 	}
 	return nil
 }
@@ -1619,8 +1927,13 @@ func (f *File) DeleteDefinedName(definedName *DefinedName) error {
 // GetDefinedName provides a function to get the defined names of the workbook
 // or worksheet.
 func (f *File) GetDefinedName() []DefinedName {
-	var definedNames []DefinedName
 	wb, _ := f.workbookReader()
+	return f.getDefinedNames(wb)
+}
+
+// getDefinedNames returns the defined names of the workbook and worksheets.
+func (f *File) getDefinedNames(wb *xlsxWorkbook) []DefinedName {
+	var definedNames []DefinedName
 	if wb.DefinedNames != nil {
 		for _, dn := range wb.DefinedNames.DefinedName {
 			definedName := DefinedName{
@@ -1694,6 +2007,111 @@ func (f *File) UngroupSheets() error {
 	return nil
 }
 
+// SetPrintOrder provides a function to set the order of the worksheets to be
+// printed by given worksheets name. The given worksheets will be moved to the
+// front of the workbook in the given order and grouped as the selected sheets
+// with the first one as the active sheet, so printing the active sheets of
+// the workbook outputs these worksheets in the intended order with one
+// command. Worksheets not in the list keep their relative order after them
+// and are excluded from printing. The hidden worksheets can't be printed, and
+// the visibility of the worksheets will not be changed. For example, print
+// the worksheets named Sheet3 and Sheet1 in order:
+//
+//	err := f.SetPrintOrder([]string{"Sheet3", "Sheet1"})
+//
+// Note that this function physically reorders the worksheets in the workbook,
+// the sheet-scoped defined names will be updated, but the 3-D references
+// which across the worksheets in the formulas, such as "Sheet1:Sheet3!A1",
+// will not be updated, and these references will cover the worksheets between
+// the first and last worksheet in the new order.
+func (f *File) SetPrintOrder(sheets []string) error {
+	if len(sheets) == 0 {
+		return ErrParameterInvalid
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	order, included := make([]int, 0, len(wb.Sheets.Sheet)), make(map[int]bool, len(sheets))
+	for _, sheet := range sheets {
+		idx, err := f.GetSheetIndex(sheet)
+		if err != nil {
+			return err
+		}
+		if idx == -1 {
+			return newNoExistSheetError(sheet)
+		}
+		if included[idx] {
+			return ErrParameterInvalid
+		}
+		if _, err = f.workSheetReader(sheet); err != nil {
+			return err
+		}
+		if visible, _ := f.GetSheetVisible(sheet); !visible {
+			return newHiddenSheetError(sheet)
+		}
+		included[idx] = true
+		order = append(order, idx)
+	}
+	for idx := range wb.Sheets.Sheet {
+		if !included[idx] {
+			order = append(order, idx)
+		}
+	}
+	sheetList, localSheetIDs := make([]xlsxSheet, len(order)), make(map[int]int, len(order))
+	for newIdx, oldIdx := range order {
+		sheetList[newIdx], localSheetIDs[oldIdx] = wb.Sheets.Sheet[oldIdx], newIdx
+	}
+	wb.Sheets.Sheet = sheetList
+	if wb.DefinedNames != nil {
+		for idx, dn := range wb.DefinedNames.DefinedName {
+			if dn.LocalSheetID != nil {
+				wb.DefinedNames.DefinedName[idx].LocalSheetID = intPtr(localSheetIDs[*dn.LocalSheetID])
+			}
+		}
+	}
+	if wb.BookViews == nil {
+		wb.BookViews = &xlsxBookViews{}
+	}
+	if len(wb.BookViews.WorkBookView) == 0 {
+		wb.BookViews.WorkBookView = append(wb.BookViews.WorkBookView, xlsxWorkBookView{})
+	}
+	wb.BookViews.WorkBookView[0].ActiveTab, wb.BookViews.WorkBookView[0].FirstSheet = 0, 0
+	for idx, name := range f.GetSheetList() {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			// Chartsheet, macrosheet or dialogsheet
+			continue
+		}
+		if ws.SheetViews == nil {
+			ws.SheetViews = &xlsxSheetViews{SheetView: []xlsxSheetView{{WorkbookViewID: 0}}}
+		}
+		for viewIdx := range ws.SheetViews.SheetView {
+			ws.SheetViews.SheetView[viewIdx].TabSelected = idx < len(sheets)
+		}
+	}
+	return nil
+}
+
+// GetPrintOrder provides a function to get the worksheets name to be printed
+// in order, which are the selected worksheets of the workbook.
+func (f *File) GetPrintOrder() ([]string, error) {
+	var sheets []string
+	for _, name := range f.GetSheetList() {
+		if sheetXMLPath, _ := f.getSheetXMLPath(name); !strings.HasPrefix(sheetXMLPath, "xl/worksheets") {
+			continue
+		}
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			return sheets, err
+		}
+		if ws.SheetViews != nil && len(ws.SheetViews.SheetView) > 0 && ws.SheetViews.SheetView[0].TabSelected {
+			sheets = append(sheets, name)
+		}
+	}
+	return sheets, nil
+}
+
 // InsertPageBreak create a page break to determine where the printed page
 // ends and where begins the next one by given worksheet name and cell
 // reference, so the content before the page break will be printed on one page
@@ -90,6 +90,11 @@ func TestSetPanes(t *testing.T) {
 		},
 	))
 	assert.EqualError(t, f.SetPanes("Panes 4", nil), ErrParameterInvalid.Error())
+	// Test set panes with invalid pane types
+	assert.EqualError(t, f.SetPanes("Panes 4", &Panes{Freeze: true, YSplit: 1, ActivePane: "bottom"}),
+		newInvalidOptionalValue("ActivePane", "bottom", supportedPaneTypes).Error())
+	assert.EqualError(t, f.SetPanes("Panes 4", &Panes{Freeze: true, YSplit: 1, Panes: []PaneOptions{{SQRef: "A2", ActiveCell: "A2", Pane: "left"}}}),
+		newInvalidOptionalValue("Pane", "left", supportedPaneTypes).Error())
 	assert.EqualError(t, f.SetPanes("SheetN", nil), "sheet SheetN does not exist")
 	// Test set panes with invalid sheet name
 	assert.EqualError(t, f.SetPanes("Sheet:1", &Panes{Freeze: false, Split: false}), ErrSheetNameInvalid.Error())
@@ -288,6 +293,134 @@ func TestDefinedName(t *testing.T) {
 		"XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestCheckDefinedName(t *testing.T) {
+	for _, name := range []string{"Amount", "_xlnm.Print_Area", "\\Path", "Tax_Rate.2023", "Rate?", "数量", "XFE1", "RC1A", "ABCD1"} {
+		assert.NoError(t, checkDefinedName(name), name)
+	}
+	for _, name := range []string{"1Amount", ".Amount", "Tax Rate", "Tax-Rate", "C", "r", "A1", "xfd1048576", "R1C1", "rc", "R[1]C", strings.Repeat("a", MaxFieldLength+1)} {
+		assert.EqualError(t, checkDefinedName(name), newInvalidDefinedNameError(name).Error(), name)
+	}
+}
+
+func TestSetDefinedNames(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet2!$A$1", Scope: "Sheet2"}))
+	// Test set defined name which exists with case-insensitive name or
+	// workbook scope
+	for _, definedName := range []*DefinedName{
+		{Name: "AMOUNT", RefersTo: "Sheet1!$B$1"},
+		{Name: "Amount", RefersTo: "Sheet1!$B$1", Scope: "Workbook"},
+		{Name: "amount", RefersTo: "Sheet1!$B$1", Scope: "sheet2"},
+	} {
+		assert.EqualError(t, f.SetDefinedName(definedName), ErrDefinedNameDuplicate.Error())
+	}
+	// Test set defined name with invalid name and scope
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{Name: "A1", RefersTo: "Sheet1!$B$1"}), newInvalidDefinedNameError("A1").Error())
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{Name: "Price", RefersTo: "Sheet1!$B$1", Scope: "SheetN"}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{Name: "Price", RefersTo: "Sheet1!$B$1", Scope: "Sheet:1"}), ErrSheetNameInvalid.Error())
+	assert.EqualError(t, f.SetDefinedName(nil), ErrParameterInvalid.Error())
+
+	definedNames, err := f.SetDefinedNames([]DefinedName{
+		{Name: "amount", RefersTo: "Sheet1!$C$1", Comment: "comment"},
+		{Name: "Amount", RefersTo: "Sheet2!$C$1", Scope: "Sheet2"},
+		{Name: "Price", RefersTo: "Sheet1!$D$1"},
+	}, DefinedNameConflictOverwrite)
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedName{
+		{Name: "Amount", Comment: "comment", RefersTo: "Sheet1!$C$1", Scope: "Workbook"},
+		{Name: "Amount", RefersTo: "Sheet2!$C$1", Scope: "Sheet2"},
+		{Name: "Price", RefersTo: "Sheet1!$D$1", Scope: "Workbook"},
+	}, definedNames)
+	assert.Equal(t, definedNames, f.GetDefinedName())
+
+	definedNames, err = f.SetDefinedNames([]DefinedName{
+		{Name: "Amount", RefersTo: "Sheet1!$E$1"},
+		{Name: "Amount", RefersTo: "Sheet1!$F$1"},
+		{Name: "Amount", RefersTo: "Sheet2!$E$1", Scope: "Sheet2"},
+		{Name: strings.Repeat("a", MaxFieldLength), RefersTo: "Sheet1!$G$1"},
+		{Name: strings.Repeat("a", MaxFieldLength), RefersTo: "Sheet1!$H$1"},
+	}, DefinedNameConflictRename)
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedName{
+		{Name: "Amount_1", RefersTo: "Sheet1!$E$1", Scope: "Workbook"},
+		{Name: "Amount_2", RefersTo: "Sheet1!$F$1", Scope: "Workbook"},
+		{Name: "Amount_1", RefersTo: "Sheet2!$E$1", Scope: "Sheet2"},
+		{Name: strings.Repeat("a", MaxFieldLength), RefersTo: "Sheet1!$G$1", Scope: "Workbook"},
+		{Name: strings.Repeat("a", MaxFieldLength-2) + "_1", RefersTo: "Sheet1!$H$1", Scope: "Workbook"},
+	}, definedNames)
+	assert.Len(t, f.GetDefinedName(), 8)
+
+	// Test set defined names with error policy and invalid defined names, no
+	// defined name should be set
+	for _, definedNames := range [][]DefinedName{
+		{{Name: "Rate", RefersTo: "0.5"}, {Name: "Price", RefersTo: "Sheet1!$A$1"}},
+		{{Name: "Rate", RefersTo: "0.5"}, {Name: "Rate", RefersTo: "0.25"}},
+		{{Name: "Rate", RefersTo: "0.5"}, {Name: "Rate 2", RefersTo: "0.25"}},
+	} {
+		_, err = f.SetDefinedNames(definedNames, DefinedNameConflictError)
+		assert.Error(t, err)
+		assert.Len(t, f.GetDefinedName(), 8)
+	}
+	f = NewFile()
+	_, err = f.SetDefinedNames([]DefinedName{{Name: "Rate", RefersTo: "0.5"}, {Name: "Rate"}}, DefinedNameConflictError)
+	assert.EqualError(t, err, ErrParameterInvalid.Error())
+	assert.Nil(t, f.GetDefinedName())
+	// Test set defined names with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.SetDefinedNames([]DefinedName{{Name: "Rate", RefersTo: "0.5"}}, DefinedNameConflictError)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestImportDefinedNames(t *testing.T) {
+	f := NewFile()
+	data, err := f.ExportDefinedNames()
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	expected := []DefinedName{
+		{Name: "Amount", Comment: "comment", RefersTo: "Sheet1!$A$1", Scope: "Workbook"},
+		{Name: "Amount", RefersTo: "Sheet2!$A$1", Scope: "Sheet2"},
+	}
+	_, err = f.SetDefinedNames(expected, DefinedNameConflictError)
+	assert.NoError(t, err)
+	data, err = f.ExportDefinedNames()
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	f = NewFile()
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	definedNames, err := f.ImportDefinedNames(data, DefinedNameConflictError)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, definedNames)
+	assert.Equal(t, expected, f.GetDefinedName())
+	// Test import defined names which already exist
+	_, err = f.ImportDefinedNames(data, DefinedNameConflictError)
+	assert.EqualError(t, err, ErrDefinedNameDuplicate.Error())
+	definedNames, err = f.ImportDefinedNames(data, DefinedNameConflictRename)
+	assert.NoError(t, err)
+	assert.Equal(t, "Amount_1", definedNames[0].Name)
+	assert.Equal(t, "Amount_1", definedNames[1].Name)
+	// Test import defined names with invalid JSON
+	_, err = f.ImportDefinedNames([]byte("{"), DefinedNameConflictError)
+	assert.EqualError(t, err, "unexpected end of JSON input")
+	// Test import defined names into the workbook without the scope worksheet
+	f = NewFile()
+	_, err = f.ImportDefinedNames(data, DefinedNameConflictError)
+	assert.EqualError(t, err, "sheet Sheet2 does not exist")
+	assert.Nil(t, f.GetDefinedName())
+	// Test export defined names with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.ExportDefinedNames()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestGroupSheets(t *testing.T) {
 	f := NewFile()
 	sheets := []string{"Sheet2", "Sheet3"}
@@ -430,7 +563,7 @@ func TestWorksheetWriter(t *testing.T) {
 	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(worksheet, 1)))
 	f.checked = nil
 	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
-	f.workSheetWriter()
+	assert.NoError(t, f.workSheetWriter())
 	value, ok := f.Pkg.Load("xl/worksheets/sheet1.xml")
 	assert.True(t, ok)
 	assert.Equal(t, fmt.Sprintf(worksheet, 2), string(value.([]byte)))
@@ -641,3 +774,163 @@ func TestCheckSheetName(t *testing.T) {
 	assert.EqualError(t, checkSheetName("'Sheet"), ErrSheetNameSingleQuote.Error())
 	assert.EqualError(t, checkSheetName("Sheet'"), ErrSheetNameSingleQuote.Error())
 }
+
+func TestGetSheetDimension(t *testing.T) {
+	f := NewFile()
+	dimension, err := f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetDimension{}, dimension)
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E10", "text"))
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("B2", []interface{}{nil, 1, 2}))
+	assert.NoError(t, sw.SetRow("A4", []interface{}{Cell{}, "text"}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{Type: Line, Series: []ChartSeries{{Values: "Sheet1!$C$3"}}}))
+	for sheet, expected := range map[string]SheetDimension{
+		"Sheet1": {Ref: "C3:E10", Rows: 10, Cols: 5},
+		"Sheet2": {Ref: "A2:D4", Rows: 4, Cols: 4},
+	} {
+		dimension, err := f.GetSheetDimension(sheet)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, dimension)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetSheetDimension.xlsx"), Options{SheetDimensions: true}))
+	assert.NoError(t, f.Close())
+	// Test get the dimensions recorded in the workbook without loading the worksheets
+	f, err = OpenFile(filepath.Join("test", "TestGetSheetDimension.xlsx"))
+	assert.NoError(t, err)
+	f.sheetDimensions["xl/worksheets/sheet1.xml"] = SheetDimension{Ref: "A1:B2", Rows: 2, Cols: 2}
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetDimension{Ref: "A1:B2", Rows: 2, Cols: 2}, dimension)
+	_, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.False(t, ok)
+	dimension, err = f.GetSheetDimension("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetDimension{Ref: "A2:D4", Rows: 4, Cols: 4}, dimension)
+	// Test get the dimension of the modified worksheet
+	assert.NoError(t, f.SetCellValue("Sheet1", "F12", 1))
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetDimension{Ref: "C3:F12", Rows: 12, Cols: 6}, dimension)
+	// Test save the spreadsheet without the dimensions option
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.ExtLst.Ext = `<ext uri="{7523E5D3-25F3-A5E0-1632-64F254C22452}"></ext>` + wb.ExtLst.Ext
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetSheetDimension.xlsx")))
+	assert.Equal(t, `<ext uri="{7523E5D3-25F3-A5E0-1632-64F254C22452}"></ext>`, wb.ExtLst.Ext)
+	wb.ExtLst.Ext = ""
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetSheetDimension.xlsx")))
+	assert.Nil(t, wb.ExtLst)
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestGetSheetDimension.xlsx"))
+	assert.NoError(t, err)
+	assert.Empty(t, f.sheetDimensions)
+	// Test get the dimension with invalid worksheet
+	_, err = f.GetSheetDimension("Chart1")
+	assert.EqualError(t, err, newNotWorksheetError("Chart1").Error())
+	_, err = f.GetSheetDimension("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	_, err = f.GetSheetDimension("Sheet:1")
+	assert.EqualError(t, err, ErrSheetNameInvalid.Error())
+	// Test save the dimensions with invalid worksheet
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Write(io.Discard, Options{SheetDimensions: true}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test save the dimensions with invalid cell reference
+	f = NewFile()
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData.Row = []xlsxRow{{R: 1, C: []xlsxC{{R: "A", V: "1"}}}}
+	_, err = f.GetSheetDimension("Sheet1")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test save the dimensions with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Write(io.Discard, Options{SheetDimensions: true}), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestSheetDimensionsReader(t *testing.T) {
+	f := NewFile()
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	for _, ext := range []string{
+		"<ext",
+		`<ext uri="` + ExtURISheetDimensions + `"><sheetDimensions><sheet name="Sheet1" rows="x"/></sheetDimensions></ext>`,
+		`<ext uri="` + ExtURISheetDimensions + `"><sheetDimensions><sheet name="SheetN" rows="1" cols="1"/></sheetDimensions></ext>`,
+	} {
+		wb.ExtLst = &xlsxExtLst{Ext: ext}
+		dimensions, err := f.sheetDimensionsReader()
+		assert.NoError(t, err)
+		assert.Empty(t, dimensions)
+	}
+	// Test read the dimensions with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.sheetDimensionsReader()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestSetPrintOrder(t *testing.T) {
+	f := NewFile()
+	for _, sheet := range []string{"Sheet2", "Sheet3", "Sheet4"} {
+		_, err := f.NewSheet(sheet)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, f.SetSheetVisible("Sheet3", false))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet3!$A$1", Scope: "Sheet3"}))
+	assert.NoError(t, f.SetPrintOrder([]string{"Sheet4", "Sheet2"}))
+	assert.Equal(t, []string{"Sheet4", "Sheet2", "Sheet1", "Sheet3"}, f.GetSheetList())
+	assert.Equal(t, 0, f.GetActiveSheetIndex())
+	visible, err := f.GetSheetVisible("Sheet3")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	assert.Equal(t, "Sheet3", f.GetDefinedName()[0].Scope)
+	sheets, err := f.GetPrintOrder()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet4", "Sheet2"}, sheets)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetPrintOrder.xlsx")))
+	// Test set print order with invalid parameters
+	assert.Equal(t, ErrParameterInvalid, f.SetPrintOrder(nil))
+	assert.Equal(t, ErrParameterInvalid, f.SetPrintOrder([]string{"Sheet1", "sheet1"}))
+	assert.EqualError(t, f.SetPrintOrder([]string{"SheetN"}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.SetPrintOrder([]string{"Sheet:1"}), ErrSheetNameInvalid.Error())
+	// Test set print order with hidden worksheet
+	assert.EqualError(t, f.SetPrintOrder([]string{"Sheet1", "Sheet3"}), "sheet Sheet3 is hidden")
+	assert.Equal(t, []string{"Sheet4", "Sheet2", "Sheet1", "Sheet3"}, f.GetSheetList())
+	// Test set print order with chart sheet
+	f = NewFile()
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{Type: Col, Series: []ChartSeries{{Values: "Sheet1!$A$1:$A$2"}}}))
+	assert.EqualError(t, f.SetPrintOrder([]string{"Chart1"}), "sheet Chart1 is not a worksheet")
+	assert.NoError(t, f.SetPrintOrder([]string{"Sheet1"}))
+	sheets, err = f.GetPrintOrder()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1"}, sheets)
+	// Test set and get print order with unsupported charset
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetPrintOrder([]string{"Sheet1"}), "XML syntax error on line 1: invalid UTF-8")
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = nil
+	assert.EqualError(t, f.SetPrintOrder([]string{"Sheet1"}), "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetPrintOrder()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestParsePaneType(t *testing.T) {
+	paneType, err := ParsePaneType("bottomRight")
+	assert.NoError(t, err)
+	assert.Equal(t, PaneType(PaneBottomRight), paneType)
+	assert.Equal(t, "bottomRight", paneType.String())
+	_, err = ParsePaneType("bottom")
+	assert.EqualError(t, err, newInvalidOptionalValue("Pane", "bottom", supportedPaneTypes).Error())
+}
@@ -11,7 +11,13 @@
 
 package excel
 
-import "reflect"
+import (
+	"encoding/binary"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
 
 // SetPageMargins provides a function to set worksheet page margins.
 func (f *File) SetPageMargins(sheet string, opts *PageLayoutMarginsOptions) error {
@@ -103,6 +109,39 @@ func (ws *xlsxWorksheet) setSheetOutlineProps(opts *SheetPropsOptions) {
 		prepareOutlinePr(ws)
 		ws.SheetPr.OutlinePr.SummaryRight = opts.OutlineSummaryRight
 	}
+	if opts.OutlineApplyStyles != nil {
+		prepareOutlinePr(ws)
+		ws.SheetPr.OutlinePr.ApplyStyles = opts.OutlineApplyStyles
+	}
+	if opts.OutlineShowSymbols != nil {
+		prepareOutlinePr(ws)
+		ws.SheetPr.OutlinePr.ShowOutlineSymbols = opts.OutlineShowSymbols
+	}
+}
+
+// setSheetSyncProps set worksheet synchronized scrolling and Lotus
+// compatibility properties by given options.
+func (ws *xlsxWorksheet) setSheetSyncProps(opts *SheetPropsOptions) {
+	if opts.SyncHorizontal != nil {
+		ws.prepareSheetPr()
+		ws.SheetPr.SyncHorizontal = *opts.SyncHorizontal
+	}
+	if opts.SyncVertical != nil {
+		ws.prepareSheetPr()
+		ws.SheetPr.SyncVertical = *opts.SyncVertical
+	}
+	if opts.SyncRef != nil {
+		ws.prepareSheetPr()
+		ws.SheetPr.SyncRef = *opts.SyncRef
+	}
+	if opts.TransitionEvaluation != nil {
+		ws.prepareSheetPr()
+		ws.SheetPr.TransitionEvaluation = *opts.TransitionEvaluation
+	}
+	if opts.TransitionEntry != nil {
+		ws.prepareSheetPr()
+		ws.SheetPr.TransitionEntry = *opts.TransitionEntry
+	}
 }
 
 // setSheetProps set worksheet format properties by given options.
@@ -116,7 +155,7 @@ func (ws *xlsxWorksheet) setSheetProps(opts *SheetPropsOptions) {
 	prepareTabColor := func(ws *xlsxWorksheet) {
 		ws.prepareSheetPr()
 		if ws.SheetPr.TabColor == nil {
-			ws.SheetPr.TabColor = new(xlsxTabColor)
+			ws.SheetPr.TabColor = new(xlsxColor)
 		}
 	}
 	if opts.CodeName != nil {
@@ -127,6 +166,10 @@ func (ws *xlsxWorksheet) setSheetProps(opts *SheetPropsOptions) {
 		ws.prepareSheetPr()
 		ws.SheetPr.EnableFormatConditionsCalculation = opts.EnableFormatConditionsCalculation
 	}
+	if opts.FilterMode != nil {
+		ws.prepareSheetPr()
+		ws.SheetPr.FilterMode = *opts.FilterMode
+	}
 	if opts.Published != nil {
 		ws.prepareSheetPr()
 		ws.SheetPr.Published = opts.Published
@@ -140,12 +183,19 @@ func (ws *xlsxWorksheet) setSheetProps(opts *SheetPropsOptions) {
 		ws.SheetPr.PageSetUpPr.FitToPage = *opts.FitToPage
 	}
 	ws.setSheetOutlineProps(opts)
+	ws.setSheetSyncProps(opts)
 	s := reflect.ValueOf(opts).Elem()
-	for i := 5; i < 9; i++ {
+	for i := 6; i < 10; i++ {
 		if !s.Field(i).IsNil() {
 			prepareTabColor(ws)
 			name := s.Type().Field(i).Name
-			reflect.ValueOf(ws.SheetPr.TabColor).Elem().FieldByName(name[8:]).Set(s.Field(i).Elem())
+			field := reflect.ValueOf(ws.SheetPr.TabColor).Elem().FieldByName(name[8:])
+			if field.Kind() == reflect.Ptr {
+				// The theme color index 0 is valid, keep it by pointer
+				field.Set(reflect.New(field.Type().Elem()))
+				field = field.Elem()
+			}
+			field.Set(s.Field(i).Elem())
 		}
 	}
 }
@@ -160,38 +210,39 @@ func (f *File) SetSheetProps(sheet string, opts *SheetPropsOptions) error {
 		return err
 	}
 	ws.setSheetProps(opts)
-	if ws.SheetFormatPr == nil {
-		ws.SheetFormatPr = &xlsxSheetFormatPr{DefaultRowHeight: defaultRowHeight}
-	}
-	s := reflect.ValueOf(opts).Elem()
-	for i := 11; i < 18; i++ {
-		if !s.Field(i).IsNil() {
-			name := s.Type().Field(i).Name
-			reflect.ValueOf(ws.SheetFormatPr).Elem().FieldByName(name).Set(s.Field(i).Elem())
-		}
-	}
+	var format SheetFormatOptions
+	copySheetFormatOptions(&format, opts)
+	ws.setSheetFormat(&format)
 	return err
 }
 
 // GetSheetProps provides a function to get worksheet properties.
 func (f *File) GetSheetProps(sheet string) (SheetPropsOptions, error) {
-	baseColWidth := uint8(8)
 	opts := SheetPropsOptions{
 		EnableFormatConditionsCalculation: boolPtr(true),
 		Published:                         boolPtr(true),
 		AutoPageBreaks:                    boolPtr(true),
 		OutlineSummaryBelow:               boolPtr(true),
-		BaseColWidth:                      &baseColWidth,
+		OutlineShowSymbols:                boolPtr(true),
 	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
+		opts.BaseColWidth = uint8Ptr(8)
 		return opts, err
 	}
+	format := ws.getSheetFormat()
+	copySheetFormatOptions(&opts, &format)
 	if ws.SheetPr != nil {
 		opts.CodeName = stringPtr(ws.SheetPr.CodeName)
 		if ws.SheetPr.EnableFormatConditionsCalculation != nil {
 			opts.EnableFormatConditionsCalculation = ws.SheetPr.EnableFormatConditionsCalculation
 		}
+		opts.FilterMode = boolPtr(ws.SheetPr.FilterMode)
+		opts.SyncHorizontal = boolPtr(ws.SheetPr.SyncHorizontal)
+		opts.SyncVertical = boolPtr(ws.SheetPr.SyncVertical)
+		opts.SyncRef = stringPtr(ws.SheetPr.SyncRef)
+		opts.TransitionEvaluation = boolPtr(ws.SheetPr.TransitionEvaluation)
+		opts.TransitionEntry = boolPtr(ws.SheetPr.TransitionEntry)
 		if ws.SheetPr.Published != nil {
 			opts.Published = ws.SheetPr.Published
 		}
@@ -202,22 +253,247 @@ func (f *File) GetSheetProps(sheet string) (SheetPropsOptions, error) {
 		if ws.SheetPr.OutlinePr != nil {
 			opts.OutlineSummaryBelow = ws.SheetPr.OutlinePr.SummaryBelow
 			opts.OutlineSummaryRight = ws.SheetPr.OutlinePr.SummaryRight
+			if ws.SheetPr.OutlinePr.ApplyStyles != nil {
+				opts.OutlineApplyStyles = ws.SheetPr.OutlinePr.ApplyStyles
+			}
+			if ws.SheetPr.OutlinePr.ShowOutlineSymbols != nil {
+				opts.OutlineShowSymbols = ws.SheetPr.OutlinePr.ShowOutlineSymbols
+			}
 		}
 		if ws.SheetPr.TabColor != nil {
 			opts.TabColorIndexed = intPtr(ws.SheetPr.TabColor.Indexed)
 			opts.TabColorRGB = stringPtr(ws.SheetPr.TabColor.RGB)
-			opts.TabColorTheme = intPtr(ws.SheetPr.TabColor.Theme)
+			opts.TabColorTheme = intPtr(0)
+			if ws.SheetPr.TabColor.Theme != nil {
+				opts.TabColorTheme = intPtr(*ws.SheetPr.TabColor.Theme)
+			}
 			opts.TabColorTint = float64Ptr(ws.SheetPr.TabColor.Tint)
 		}
 	}
+	return opts, err
+}
+
+// SetSheetCustomProps provides a function to set the custom property of the
+// worksheet by given worksheet name and property. If the property name
+// already exists, the value of it will be updated, otherwise a new property
+// will be added. The worksheet custom properties can be accessed by the
+// Worksheet.CustomProperties collection in VBA, which are often used by the
+// add-ins to keep their state with the worksheet. For example, set a custom
+// property with the name "ReportID" on Sheet1:
+//
+//	err := f.SetSheetCustomProps("Sheet1", excelize.SheetCustomProperty{
+//	    Name:  "ReportID",
+//	    Value: "R-2023-001",
+//	})
+func (f *File) SetSheetCustomProps(sheet string, prop SheetCustomProperty) error {
+	if prop.Name == "" {
+		return ErrParameterInvalid
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ws.CustomProperties == nil {
+		ws.CustomProperties = new(xlsxSheetCustomProperties)
+	}
+	idx := -1
+	for i, customPr := range ws.CustomProperties.CustomPr {
+		if customPr.Name == prop.Name {
+			if target := f.getSheetRelationshipsTargetByID(sheet, customPr.RID); target != "" {
+				f.Pkg.Store(strings.ReplaceAll(target, "..", "xl"), encodeSheetCustomPropValue(prop.Value))
+				return err
+			}
+			idx = i
+		}
+	}
+	partID := 1
+	for ; ; partID++ {
+		if _, ok := f.Pkg.Load("xl/customProperty" + strconv.Itoa(partID) + ".bin"); !ok {
+			break
+		}
+	}
+	if err = f.addContentTypePart(partID, "customProperty"); err != nil {
+		return err
+	}
+	f.Pkg.Store("xl/customProperty"+strconv.Itoa(partID)+".bin", encodeSheetCustomPropValue(prop.Value))
+	sheetXMLPath, _ := f.getSheetXMLPath(sheet)
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(sheetXMLPath, "xl/worksheets/") + ".rels"
+	rID := f.addRels(sheetRels, SourceRelationshipCustomProperty, "../customProperty"+strconv.Itoa(partID)+".bin", "")
+	f.addSheetNameSpace(sheet, SourceRelationship)
+	if idx != -1 {
+		ws.CustomProperties.CustomPr[idx].RID = "rId" + strconv.Itoa(rID)
+		return err
+	}
+	ws.CustomProperties.CustomPr = append(ws.CustomProperties.CustomPr, xlsxCustomPr{
+		Name: prop.Name, RID: "rId" + strconv.Itoa(rID),
+	})
+	return err
+}
+
+// GetSheetCustomProps provides a function to get the custom properties of the
+// worksheet by given worksheet name.
+func (f *File) GetSheetCustomProps(sheet string) ([]SheetCustomProperty, error) {
+	var props []SheetCustomProperty
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.CustomProperties == nil {
+		return props, err
+	}
+	for _, customPr := range ws.CustomProperties.CustomPr {
+		prop := SheetCustomProperty{Name: customPr.Name}
+		if target := f.getSheetRelationshipsTargetByID(sheet, customPr.RID); target != "" {
+			prop.Value = decodeSheetCustomPropValue(f.readBytes(strings.ReplaceAll(target, "..", "xl")))
+		}
+		props = append(props, prop)
+	}
+	return props, err
+}
+
+// DeleteSheetCustomProps provides a function to delete the custom property of
+// the worksheet by given worksheet name and property name.
+func (f *File) DeleteSheetCustomProps(sheet, name string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.CustomProperties == nil {
+		return err
+	}
+	for idx, customPr := range ws.CustomProperties.CustomPr {
+		if customPr.Name != name {
+			continue
+		}
+		target := f.getSheetRelationshipsTargetByID(sheet, customPr.RID)
+		f.deleteSheetRelationships(sheet, customPr.RID)
+		ws.CustomProperties.CustomPr = append(ws.CustomProperties.CustomPr[:idx], ws.CustomProperties.CustomPr[idx+1:]...)
+		if len(ws.CustomProperties.CustomPr) == 0 {
+			ws.CustomProperties = nil
+		}
+		if target != "" {
+			return f.deletePart(strings.ReplaceAll(target, "..", "xl"))
+		}
+		return err
+	}
+	return err
+}
+
+// encodeSheetCustomPropValue encodes the value of the worksheet custom
+// property as the UTF-16LE string.
+func encodeSheetCustomPropValue(value string) []byte {
+	codes := utf16.Encode([]rune(value))
+	buf := make([]byte, len(codes)*2)
+	for i, code := range codes {
+		binary.LittleEndian.PutUint16(buf[i*2:], code)
+	}
+	return buf
+}
+
+// decodeSheetCustomPropValue decodes the UTF-16LE string value of the
+// worksheet custom property, the content will be returned as it is if it's
+// not a valid UTF-16LE string.
+func decodeSheetCustomPropValue(content []byte) string {
+	if len(content)%2 != 0 {
+		return string(content)
+	}
+	codes := make([]uint16, len(content)/2)
+	for i := range codes {
+		codes[i] = binary.LittleEndian.Uint16(content[i*2:])
+	}
+	return strings.TrimRight(string(utf16.Decode(codes)), "\x00")
+}
+
+// copySheetFormatOptions copy the default row and column formatting
+// properties between the SheetFormatOptions and the SheetPropsOptions, both
+// of the source and destination should be pointers to one of them.
+func copySheetFormatOptions(dst, src interface{}) {
+	d, s := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	t := reflect.TypeOf(SheetFormatOptions{})
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		d.FieldByName(name).Set(s.FieldByName(name))
+	}
+}
+
+// getSheetFormat get worksheet default row and column formatting properties.
+func (ws *xlsxWorksheet) getSheetFormat() SheetFormatOptions {
+	opts := SheetFormatOptions{
+		BaseColWidth:     uint8Ptr(8),
+		DefaultColWidth:  float64Ptr(0),
+		DefaultRowHeight: float64Ptr(defaultRowHeight),
+		CustomHeight:     boolPtr(false),
+		ZeroHeight:       boolPtr(false),
+		ThickTop:         boolPtr(false),
+		ThickBottom:      boolPtr(false),
+		OutlineLevelRow:  uint8Ptr(0),
+		OutlineLevelCol:  uint8Ptr(0),
+	}
 	if ws.SheetFormatPr != nil {
-		opts.BaseColWidth = &ws.SheetFormatPr.BaseColWidth
+		if ws.SheetFormatPr.BaseColWidth != 0 {
+			opts.BaseColWidth = uint8Ptr(ws.SheetFormatPr.BaseColWidth)
+		}
 		opts.DefaultColWidth = float64Ptr(ws.SheetFormatPr.DefaultColWidth)
 		opts.DefaultRowHeight = float64Ptr(ws.SheetFormatPr.DefaultRowHeight)
 		opts.CustomHeight = boolPtr(ws.SheetFormatPr.CustomHeight)
 		opts.ZeroHeight = boolPtr(ws.SheetFormatPr.ZeroHeight)
 		opts.ThickTop = boolPtr(ws.SheetFormatPr.ThickTop)
 		opts.ThickBottom = boolPtr(ws.SheetFormatPr.ThickBottom)
+		opts.OutlineLevelRow = uint8Ptr(ws.SheetFormatPr.OutlineLevelRow)
+		opts.OutlineLevelCol = uint8Ptr(ws.SheetFormatPr.OutlineLevelCol)
 	}
-	return opts, err
+	return opts
+}
+
+// setSheetFormat set worksheet default row and column formatting properties
+// by given options.
+func (ws *xlsxWorksheet) setSheetFormat(opts *SheetFormatOptions) {
+	if ws.SheetFormatPr == nil {
+		ws.SheetFormatPr = &xlsxSheetFormatPr{DefaultRowHeight: defaultRowHeight}
+	}
+	s := reflect.ValueOf(opts).Elem()
+	for i := 0; i < s.NumField(); i++ {
+		if !s.Field(i).IsNil() {
+			name := s.Type().Field(i).Name
+			reflect.ValueOf(ws.SheetFormatPr).Elem().FieldByName(name).Set(s.Field(i).Elem())
+		}
+	}
+}
+
+// SetSheetFormat provides a function to set the default row and column
+// formatting properties of the worksheet, such as the default row height,
+// default column width, and whether the rows are hidden by default. The
+// CustomHeight will be set to true if the default row height was specified
+// without the CustomHeight. For example, set the default row height to 20
+// points and the default column width to 12 characters on Sheet1:
+//
+//	rowHeight, colWidth := 20.0, 12.0
+//	err := f.SetSheetFormat("Sheet1", &excelize.SheetFormatOptions{
+//	    DefaultRowHeight: &rowHeight,
+//	    DefaultColWidth:  &colWidth,
+//	})
+func (f *File) SetSheetFormat(sheet string, opts *SheetFormatOptions) error {
+	if opts == nil {
+		return ErrParameterRequired
+	}
+	if opts.DefaultRowHeight != nil && (*opts.DefaultRowHeight < 0 || *opts.DefaultRowHeight > MaxRowHeight) {
+		return ErrMaxRowHeight
+	}
+	if opts.DefaultColWidth != nil && (*opts.DefaultColWidth < 0 || *opts.DefaultColWidth > MaxColumnWidth) {
+		return ErrColumnWidth
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	format := *opts
+	if format.DefaultRowHeight != nil && format.CustomHeight == nil {
+		format.CustomHeight = boolPtr(true)
+	}
+	ws.setSheetFormat(&format)
+	return err
+}
+
+// GetSheetFormat provides a function to get the default row and column
+// formatting properties of the worksheet.
+func (f *File) GetSheetFormat(sheet string) (SheetFormatOptions, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return new(xlsxWorksheet).getSheetFormat(), err
+	}
+	return ws.getSheetFormat(), err
 }
@@ -1,6 +1,8 @@
 package excel
 
 import (
+	"encoding/xml"
+	"path/filepath"
 	"testing"
 	
 	"github.com/stretchr/testify/assert"
@@ -54,6 +56,7 @@ func TestSetSheetProps(t *testing.T) {
 	expected := SheetPropsOptions{
 		CodeName:                          stringPtr("code"),
 		EnableFormatConditionsCalculation: enable,
+		FilterMode:                        enable,
 		Published:                         enable,
 		AutoPageBreaks:                    enable,
 		FitToPage:                         enable,
@@ -70,6 +73,15 @@ func TestSetSheetProps(t *testing.T) {
 		ZeroHeight:                        enable,
 		ThickTop:                          enable,
 		ThickBottom:                       enable,
+		SyncHorizontal:                    enable,
+		SyncVertical:                      enable,
+		SyncRef:                           stringPtr("B2"),
+		TransitionEvaluation:              enable,
+		TransitionEntry:                   enable,
+		OutlineApplyStyles:                enable,
+		OutlineShowSymbols:                boolPtr(false),
+		OutlineLevelRow:                   uint8Ptr(3),
+		OutlineLevelCol:                   uint8Ptr(2),
 	}
 	assert.NoError(t, f.SetSheetProps("Sheet1", &expected))
 	opts, err := f.GetSheetProps("Sheet1")
@@ -84,6 +96,21 @@ func TestSetSheetProps(t *testing.T) {
 	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{TabColorTheme: intPtr(1)}))
 	ws.(*xlsxWorksheet).SheetPr = nil
 	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{TabColorTint: float64Ptr(1)}))
+	// Test set the tab color with the theme color index 0
+	ws.(*xlsxWorksheet).SheetPr = nil
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{TabColorTheme: intPtr(0), TabColorTint: float64Ptr(0.4)}))
+	output, err := xml.Marshal(ws.(*xlsxWorksheet).SheetPr)
+	assert.NoError(t, err)
+	assert.Contains(t, string(output), `<tabColor theme="0" tint="0.4"></tabColor>`)
+	opts, err = f.GetSheetProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *opts.TabColorTheme)
+	assert.Equal(t, 0.4, *opts.TabColorTint)
+	assert.Equal(t, "", *opts.SyncRef)
+	ws.(*xlsxWorksheet).SheetPr.TabColor.Theme = nil
+	opts, err = f.GetSheetProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *opts.TabColorTheme)
 	
 	// Test set worksheet properties on not exists worksheet
 	assert.EqualError(t, f.SetSheetProps("SheetN", nil), "sheet SheetN does not exist")
@@ -100,3 +127,164 @@ func TestGetSheetProps(t *testing.T) {
 	_, err = f.GetSheetProps("Sheet:1")
 	assert.EqualError(t, err, ErrSheetNameInvalid.Error())
 }
+
+func TestSetSheetCustomProps(t *testing.T) {
+	f := NewFile()
+	props, err := f.GetSheetCustomProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, props)
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", SheetCustomProperty{Name: "ReportID", Value: "R-2023-001"}))
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", SheetCustomProperty{Name: "Owner", Value: "财务部"}))
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", SheetCustomProperty{Name: "Empty"}))
+	// Test update the existing custom property
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", SheetCustomProperty{Name: "ReportID", Value: "R-2023-002"}))
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetCustomProps("Sheet2", SheetCustomProperty{Name: "ReportID", Value: "R-2023-003"}))
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{CodeName: stringPtr("Report")}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetSheetCustomProps.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestSetSheetCustomProps.xlsx"))
+	assert.NoError(t, err)
+	props, err = f.GetSheetCustomProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []SheetCustomProperty{
+		{Name: "ReportID", Value: "R-2023-002"}, {Name: "Owner", Value: "财务部"}, {Name: "Empty"},
+	}, props)
+	props, err = f.GetSheetCustomProps("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, []SheetCustomProperty{{Name: "ReportID", Value: "R-2023-003"}}, props)
+	assert.Equal(t, []byte{0x52, 0x0, 0x2d, 0x0, 0x32, 0x0, 0x30, 0x0, 0x32, 0x0, 0x33, 0x0, 0x2d, 0x0, 0x30, 0x0, 0x30, 0x0, 0x33, 0x0}, f.readBytes("xl/customProperty4.bin"))
+	opts, err := f.GetSheetProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Report", *opts.CodeName)
+	// Test delete custom properties
+	assert.NoError(t, f.DeleteSheetCustomProps("Sheet1", "Owner"))
+	assert.NoError(t, f.DeleteSheetCustomProps("Sheet1", "Unknown"))
+	_, ok := f.Pkg.Load("xl/customProperty2.bin")
+	assert.False(t, ok)
+	// Test add custom property reuses the deleted part name
+	assert.NoError(t, f.SetSheetCustomProps("Sheet2", SheetCustomProperty{Name: "Owner", Value: "Sales"}))
+	props, err = f.GetSheetCustomProps("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, []SheetCustomProperty{{Name: "ReportID", Value: "R-2023-003"}, {Name: "Owner", Value: "Sales"}}, props)
+	assert.Equal(t, "../customProperty2.bin", f.getSheetRelationshipsTargetByID("Sheet2", "rId2"))
+	assert.NoError(t, f.DeleteSheetCustomProps("Sheet2", "ReportID"))
+	assert.NoError(t, f.DeleteSheetCustomProps("Sheet2", "Owner"))
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.CustomProperties)
+	assert.NoError(t, f.DeleteSheetCustomProps("Sheet2", "Owner"))
+	contentTypes, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	for _, override := range contentTypes.Overrides {
+		assert.NotContains(t, []string{"/xl/customProperty2.bin", "/xl/customProperty4.bin"}, override.PartName)
+	}
+	// Test set and delete custom property without relationship
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.CustomProperties.CustomPr[0].RID = "rId100"
+	props, err = f.GetSheetCustomProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetCustomProperty{Name: "ReportID"}, props[0])
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", SheetCustomProperty{Name: "ReportID", Value: "R-2023-004"}))
+	props, err = f.GetSheetCustomProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetCustomProperty{Name: "ReportID", Value: "R-2023-004"}, props[0])
+	ws.CustomProperties.CustomPr[1].RID = "rId100"
+	assert.NoError(t, f.DeleteSheetCustomProps("Sheet1", "Empty"))
+	assert.Len(t, ws.CustomProperties.CustomPr, 1)
+	// Test set custom property with invalid name
+	assert.Equal(t, ErrParameterInvalid, f.SetSheetCustomProps("Sheet1", SheetCustomProperty{}))
+	// Test set, get and delete custom properties on not exists worksheet
+	assert.EqualError(t, f.SetSheetCustomProps("SheetN", SheetCustomProperty{Name: "ReportID"}), "sheet SheetN does not exist")
+	_, err = f.GetSheetCustomProps("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.EqualError(t, f.DeleteSheetCustomProps("SheetN", "ReportID"), "sheet SheetN does not exist")
+	// Test set custom property with unsupported charset content types
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetSheetCustomProps("Sheet1", SheetCustomProperty{Name: "Owner"}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	assert.Equal(t, "abc", decodeSheetCustomPropValue([]byte("abc")))
+}
+
+func TestSetSheetFormat(t *testing.T) {
+	f := NewFile()
+	opts, err := f.GetSheetFormat("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetFormatOptions{
+		BaseColWidth:     uint8Ptr(8),
+		DefaultColWidth:  float64Ptr(0),
+		DefaultRowHeight: float64Ptr(defaultRowHeight),
+		CustomHeight:     boolPtr(false),
+		ZeroHeight:       boolPtr(false),
+		ThickTop:         boolPtr(false),
+		ThickBottom:      boolPtr(false),
+		OutlineLevelRow:  uint8Ptr(0),
+		OutlineLevelCol:  uint8Ptr(0),
+	}, opts)
+	width, err := f.GetColWidth("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultColWidth, width)
+
+	expected := SheetFormatOptions{
+		BaseColWidth:     uint8Ptr(10),
+		DefaultColWidth:  float64Ptr(12),
+		DefaultRowHeight: float64Ptr(20),
+		CustomHeight:     boolPtr(true),
+		ZeroHeight:       boolPtr(true),
+		ThickTop:         boolPtr(true),
+		ThickBottom:      boolPtr(true),
+		OutlineLevelRow:  uint8Ptr(2),
+		OutlineLevelCol:  uint8Ptr(1),
+	}
+	assert.NoError(t, f.SetSheetFormat("Sheet1", &SheetFormatOptions{
+		BaseColWidth:     expected.BaseColWidth,
+		DefaultColWidth:  expected.DefaultColWidth,
+		DefaultRowHeight: expected.DefaultRowHeight,
+		ZeroHeight:       expected.ZeroHeight,
+		ThickTop:         expected.ThickTop,
+		ThickBottom:      expected.ThickBottom,
+		OutlineLevelRow:  expected.OutlineLevelRow,
+		OutlineLevelCol:  expected.OutlineLevelCol,
+	}))
+	opts, err = f.GetSheetFormat("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, opts)
+	height, err := f.GetRowHeight("Sheet1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, height)
+	// Test set the default row height without custom height
+	assert.NoError(t, f.SetSheetFormat("Sheet1", &SheetFormatOptions{DefaultRowHeight: float64Ptr(18), CustomHeight: boolPtr(false)}))
+	opts, err = f.GetSheetFormat("Sheet1")
+	assert.NoError(t, err)
+	assert.False(t, *opts.CustomHeight)
+	assert.Equal(t, 18.0, *opts.DefaultRowHeight)
+	// Test set worksheet format properties with invalid settings
+	for _, c := range []struct {
+		opts *SheetFormatOptions
+		err  error
+	}{
+		{nil, ErrParameterRequired},
+		{&SheetFormatOptions{DefaultRowHeight: float64Ptr(MaxRowHeight + 1)}, ErrMaxRowHeight},
+		{&SheetFormatOptions{DefaultRowHeight: float64Ptr(-1)}, ErrMaxRowHeight},
+		{&SheetFormatOptions{DefaultColWidth: float64Ptr(MaxColumnWidth + 1)}, ErrColumnWidth},
+	} {
+		assert.Equal(t, c.err, f.SetSheetFormat("Sheet1", c.opts))
+	}
+	// Test set and get worksheet format properties on not exists worksheet
+	assert.EqualError(t, f.SetSheetFormat("SheetN", &SheetFormatOptions{}), "sheet SheetN does not exist")
+	_, err = f.GetSheetFormat("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get worksheet properties with the format properties
+	props, err := f.GetSheetProps("Sheet1")
+	assert.NoError(t, err)
+	format := SheetFormatOptions{}
+	copySheetFormatOptions(&format, &props)
+	opts, err = f.GetSheetFormat("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, opts, format)
+}
@@ -11,6 +11,8 @@
 
 package excel
 
+import "strings"
+
 // getSheetView returns the SheetView object
 func (f *File) getSheetView(sheet string, viewIndex int) (*xlsxSheetView, error) {
 	ws, err := f.workSheetReader(sheet)
@@ -131,3 +133,110 @@ func (f *File) GetSheetView(sheet string, viewIndex int) (ViewOptions, error) {
 	}
 	return opts, err
 }
+
+// SetSelection provides a function to set the active cell and the selected
+// range of the last view by given worksheet name, active cell reference and
+// selection range reference, so the workbook opens with the cursor at the
+// given cell. The sqref could be a single cell, a range reference or
+// multiple references separated by spaces, and the active cell will be
+// selected if the sqref is empty. The active cell should be located in the
+// selection range. If the worksheet has frozen panes, the selection will be
+// placed in the pane which contains the active cell, and that pane will be
+// activated. If the worksheet has split panes, the selection will be placed
+// in the active pane. For example, freeze the first row and move the cursor
+// to the cell B2 of the worksheet named Sheet1:
+//
+//	if err := f.SetPanes("Sheet1", &excelize.Panes{
+//	    Freeze:      true,
+//	    YSplit:      1,
+//	    TopLeftCell: "A2",
+//	    ActivePane:  "bottomLeft",
+//	}); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err := f.SetSelection("Sheet1", "B2", "B2:C5")
+func (f *File) SetSelection(sheet, activeCell, sqref string) error {
+	col, row, err := CellNameToCoordinates(activeCell)
+	if err != nil {
+		return err
+	}
+	if sqref = strings.Join(strings.Fields(sqref), " "); sqref == "" {
+		sqref = activeCell
+	}
+	var inSelection bool
+	for _, ref := range strings.Split(sqref, " ") {
+		if !strings.Contains(ref, ":") {
+			ref += ":" + ref
+		}
+		coordinates, err := rangeRefToCoordinates(ref)
+		if err != nil {
+			return err
+		}
+		_ = sortCoordinates(coordinates)
+		if col >= coordinates[0] && col <= coordinates[2] && row >= coordinates[1] && row <= coordinates[3] {
+			inSelection = true
+		}
+	}
+	if !inSelection {
+		return ErrParameterInvalid
+	}
+	view, err := f.getSheetView(sheet, -1)
+	if err != nil {
+		return err
+	}
+	selection := &xlsxSelection{ActiveCell: activeCell, SQRef: sqref}
+	if view.Pane == nil {
+		view.Selection = []*xlsxSelection{selection}
+		return err
+	}
+	if view.Pane.State == "frozen" {
+		view.Pane.ActivePane = getFrozenPane(view.Pane, col, row)
+	}
+	if selection.Pane = view.Pane.ActivePane; selection.Pane == "" {
+		selection.Pane = "topLeft"
+	}
+	for idx, s := range view.Selection {
+		if s.Pane == selection.Pane || (s.Pane == "" && selection.Pane == "topLeft") {
+			view.Selection[idx] = selection
+			return err
+		}
+	}
+	view.Selection = append(view.Selection, selection)
+	return err
+}
+
+// GetSelection provides a function to get the active cell and the selected
+// range of the last view by given worksheet name. If the worksheet has
+// panes, the selection of the active pane will be returned.
+func (f *File) GetSelection(sheet string) (string, string, error) {
+	view, err := f.getSheetView(sheet, -1)
+	if err != nil {
+		return "", "", err
+	}
+	activePane := "topLeft"
+	if view.Pane != nil && view.Pane.ActivePane != "" {
+		activePane = view.Pane.ActivePane
+	}
+	for _, s := range view.Selection {
+		if view.Pane == nil || s.Pane == activePane || (s.Pane == "" && activePane == "topLeft") {
+			return s.ActiveCell, s.SQRef, err
+		}
+	}
+	return "", "", err
+}
+
+// getFrozenPane returns the pane name of the frozen panes which contains the
+// cell by given column and row number.
+func getFrozenPane(pane *xlsxPane, col, row int) string {
+	right, bottom := pane.XSplit > 0 && float64(col) > pane.XSplit, pane.YSplit > 0 && float64(row) > pane.YSplit
+	switch {
+	case right && bottom:
+		return PaneBottomRight
+	case right:
+		return PaneTopRight
+	case bottom:
+		return PaneBottomLeft
+	}
+	return PaneTopLeft
+}
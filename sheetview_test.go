@@ -1,6 +1,7 @@
 package excel
 
 import (
+	"path/filepath"
 	"testing"
 	
 	"github.com/stretchr/testify/assert"
@@ -48,3 +49,72 @@ func TestGetView(t *testing.T) {
 	_, err = f.GetSheetView("SheetN", 0)
 	assert.EqualError(t, err, "sheet SheetN does not exist")
 }
+
+func TestSetSelection(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSelection("Sheet1", "C3", ""))
+	activeCell, sqref, err := f.GetSelection("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "C3", activeCell)
+	assert.Equal(t, "C3", sqref)
+	assert.NoError(t, f.SetSelection("Sheet1", "B2", " D5:B2  F1 "))
+	activeCell, sqref, err = f.GetSelection("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2", activeCell)
+	assert.Equal(t, "D5:B2 F1", sqref)
+	// Test set selection with frozen panes
+	assert.NoError(t, f.SetPanes("Sheet1", &Panes{
+		Freeze: true, XSplit: 1, YSplit: 1, TopLeftCell: "B2", ActivePane: "bottomRight",
+		Panes: []PaneOptions{
+			{Pane: "topRight", ActiveCell: "B1", SQRef: "B1"},
+			{Pane: "bottomLeft", ActiveCell: "A2", SQRef: "A2"},
+			{Pane: "bottomRight", ActiveCell: "B2", SQRef: "B2"},
+		},
+	}))
+	for _, c := range []struct {
+		activeCell, pane string
+	}{
+		{"C5", "bottomRight"}, {"A5", "bottomLeft"}, {"C1", "topRight"}, {"A1", "topLeft"},
+	} {
+		assert.NoError(t, f.SetSelection("Sheet1", c.activeCell, ""))
+		activeCell, sqref, err = f.GetSelection("Sheet1")
+		assert.NoError(t, err)
+		assert.Equal(t, c.activeCell, activeCell)
+		assert.Equal(t, c.activeCell, sqref)
+		view, err := f.getSheetView("Sheet1", -1)
+		assert.NoError(t, err)
+		assert.Equal(t, c.pane, view.Pane.ActivePane)
+	}
+	view, err := f.getSheetView("Sheet1", -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*xlsxSelection{
+		{Pane: "topRight", ActiveCell: "C1", SQRef: "C1"},
+		{Pane: "bottomLeft", ActiveCell: "A5", SQRef: "A5"},
+		{Pane: "bottomRight", ActiveCell: "C5", SQRef: "C5"},
+		{Pane: "topLeft", ActiveCell: "A1", SQRef: "A1"},
+	}, view.Selection)
+	// Test set selection with split panes
+	assert.NoError(t, f.SetPanes("Sheet1", &Panes{Split: true, XSplit: 3270, YSplit: 1800, ActivePane: "bottomLeft"}))
+	assert.NoError(t, f.SetSelection("Sheet1", "E10", "E10:F12"))
+	view, err = f.getSheetView("Sheet1", -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*xlsxSelection{{Pane: "bottomLeft", ActiveCell: "E10", SQRef: "E10:F12"}}, view.Selection)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetSelection.xlsx")))
+	// Test set selection with invalid parameters
+	assert.EqualError(t, f.SetSelection("Sheet1", "A", ""), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.SetSelection("Sheet1", "A1", "A1:B"), newCellNameToCoordinatesError("B", newInvalidCellNameError("B")).Error())
+	assert.EqualError(t, f.SetSelection("Sheet1", "C3", "A1:B2"), ErrParameterInvalid.Error())
+	// Test set and get selection on not exists worksheet
+	assert.EqualError(t, f.SetSelection("SheetN", "A1", ""), "sheet SheetN does not exist")
+	_, _, err = f.GetSelection("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get selection without selection of the active pane
+	view, err = f.getSheetView("Sheet1", -1)
+	assert.NoError(t, err)
+	view.Selection = nil
+	activeCell, sqref, err = f.GetSelection("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, activeCell)
+	assert.Empty(t, sqref)
+	assert.NoError(t, f.Close())
+}
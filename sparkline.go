@@ -373,20 +373,44 @@ func (f *File) addSparklineGroupByStyle(ID int) *xlsxX14SparklineGroup {
 //
 // The following shows the formatting options of sparkline supported by excelize:
 //
-//	 Parameter | Description
-//	-----------+--------------------------------------------
-//	 Location  | Required, must have the same number with 'Range' parameter
-//	 Range     | Required, must have the same number with 'Location' parameter
-//	 Type      | Enumeration value: line, column, win_loss
-//	 Style     | Value range: 0 - 35
-//	 Hight     | Toggle sparkline high points
-//	 Low       | Toggle sparkline low points
-//	 First     | Toggle sparkline first points
-//	 Last      | Toggle sparkline last points
-//	 Negative  | Toggle sparkline negative points
-//	 Markers   | Toggle sparkline markers
-//	 ColorAxis | An RGB Color is specified as RRGGBB
-//	 Axis      | Show sparkline axis
+//	 Parameter     | Description
+//	---------------+--------------------------------------------
+//	 Location      | Required, must have the same number with 'Range' parameter
+//	 Range         | Required, must have the same number with 'Location' parameter
+//	 Type          | Enumeration value: line, column, win_loss
+//	 Style         | Value range: 0 - 35
+//	 High          | Toggle sparkline high points
+//	 Low           | Toggle sparkline low points
+//	 First         | Toggle sparkline first points
+//	 Last          | Toggle sparkline last points
+//	 Negative      | Toggle sparkline negative points
+//	 Markers       | Toggle sparkline markers
+//	 Axis          | Show sparkline axis
+//	 Reverse       | Plot the data points from right to left
+//	 Weight        | The line weight of the line sparkline in points
+//	 MaxAxisType   | Enumeration value: individual, group, custom
+//	 CustomMax     | The maximum of the vertical axis for the custom type
+//	 MinAxisType   | Enumeration value: individual, group, custom
+//	 CustomMin     | The minimum of the vertical axis for the custom type
+//	 DateAxis      | Use the date axis for the horizontal axis
+//	 DateRange     | The date range of the date axis, such as Sheet2!A1:J1
+//	 Hidden        | Show the data in hidden rows and columns
+//	 EmptyCells    | Enumeration value: gap, zero, span
+//	 SeriesColor   | An RGB Color is specified as #RRGGBB
+//	 NegativeColor | An RGB Color is specified as #RRGGBB
+//	 MarkersColor  | An RGB Color is specified as #RRGGBB
+//	 FirstColor    | An RGB Color is specified as #RRGGBB
+//	 LastColor     | An RGB Color is specified as #RRGGBB
+//	 HightColor    | An RGB Color is specified as #RRGGBB
+//	 LowColor      | An RGB Color is specified as #RRGGBB
+//
+// The 'MaxAxisType' and 'MinAxisType' specifies the vertical axis maximum
+// and minimum of the sparklines shall be calculated individually, the same
+// for all sparklines in the group, or the custom value. The 'DateRange'
+// enables the date axis, the horizontal positions of the data points will be
+// scaled by the dates in the range. The 'EmptyCells' specifies the empty
+// cells will be shown as gaps, zero values or connected with a line, the
+// default value is 'gap'.
 func (f *File) AddSparkline(sheet string, opts *SparklineOptions) error {
 	var (
 		err                            error
@@ -418,16 +442,49 @@ func (f *File) AddSparkline(sheet string, opts *SparklineOptions) error {
 	group.Type = sparkType
 	group.ColorAxis = &xlsxColor{RGB: "FF000000"}
 	group.DisplayEmptyCellsAs = "gap"
+	if opts.EmptyCells != "" {
+		group.DisplayEmptyCellsAs = opts.EmptyCells
+	}
 	group.High = opts.High
 	group.Low = opts.Low
 	group.First = opts.First
 	group.Last = opts.Last
 	group.Negative = opts.Negative
 	group.DisplayXAxis = opts.Axis
+	group.DisplayHidden = opts.Hidden
 	group.Markers = opts.Markers
-	if opts.SeriesColor != "" {
-		group.ColorSeries = &xlsxTabColor{
-			RGB: getPaletteColor(opts.SeriesColor),
+	group.LineWeight = opts.Weight
+	group.DateAxis, group.F = opts.DateAxis || opts.DateRange != "", opts.DateRange
+	if opts.MaxAxisType != "individual" {
+		group.MaxAxisType = opts.MaxAxisType
+	}
+	if opts.MaxAxisType == "custom" {
+		if group.ManualMax = opts.CustomMax; group.ManualMax == 0 {
+			group.ManualMax = float64(opts.CustMax)
+		}
+	}
+	if opts.MinAxisType != "individual" {
+		group.MinAxisType = opts.MinAxisType
+	}
+	if opts.MinAxisType == "custom" {
+		if group.ManualMin = opts.CustomMin; group.ManualMin == 0 {
+			group.ManualMin = float64(opts.CustMin)
+		}
+	}
+	for _, color := range []struct {
+		value string
+		field **xlsxTabColor
+	}{
+		{opts.SeriesColor, &group.ColorSeries},
+		{opts.NegativeColor, &group.ColorNegative},
+		{opts.MarkersColor, &group.ColorMarkers},
+		{opts.FirstColor, &group.ColorFirst},
+		{opts.LastColor, &group.ColorLast},
+		{opts.HightColor, &group.ColorHigh},
+		{opts.LowColor, &group.ColorLow},
+	} {
+		if color.value != "" {
+			*color.field = &xlsxTabColor{RGB: getPaletteColor(color.value)}
 		}
 	}
 	if opts.Reverse {
@@ -481,6 +538,17 @@ func (f *File) parseFormatAddSparklineSet(sheet string, opts *SparklineOptions)
 	if opts.Style < 0 || opts.Style > 35 {
 		return ws, ErrSparklineStyle
 	}
+	axisTypes := []string{"individual", "group", "custom"}
+	if opts.MaxAxisType != "" && inStrSlice(axisTypes, opts.MaxAxisType, true) == -1 {
+		return ws, newInvalidOptionalValue("MaxAxisType", opts.MaxAxisType, axisTypes)
+	}
+	if opts.MinAxisType != "" && inStrSlice(axisTypes, opts.MinAxisType, true) == -1 {
+		return ws, newInvalidOptionalValue("MinAxisType", opts.MinAxisType, axisTypes)
+	}
+	emptyCells := []string{"gap", "zero", "span"}
+	if opts.EmptyCells != "" && inStrSlice(emptyCells, opts.EmptyCells, true) == -1 {
+		return ws, newInvalidOptionalValue("EmptyCells", opts.EmptyCells, emptyCells)
+	}
 	if ws.ExtLst == nil {
 		ws.ExtLst = &xlsxExtLst{}
 	}
@@ -543,3 +611,183 @@ func (f *File) appendSparkline(ws *xlsxWorksheet, group *xlsxX14SparklineGroup,
 	}
 	return err
 }
+
+// GetSparklines provides a function to get the sparkline groups in the
+// worksheet by given worksheet name, each sparkline group will be returned as
+// the formatting options which could be used to add the sparklines by the
+// AddSparkline function. The style of the sparkline group will be returned by
+// the colors with RGB value. For example, get the sparkline groups in the
+// worksheet named Sheet1:
+//
+//	sparklines, err := f.GetSparklines("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, sparkline := range sparklines {
+//	    fmt.Println(sparkline.Type, sparkline.Location, sparkline.Range)
+//	}
+func (f *File) GetSparklines(sheet string) ([]SparklineOptions, error) {
+	var sparklines []SparklineOptions
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return sparklines, err
+	}
+	_, _, groups, err := f.getSparklineGroups(ws)
+	if err != nil || groups == nil {
+		return sparklines, err
+	}
+	sparkTypes := map[string]string{"": "line", "line": "line", "column": "column", "stacked": "win_loss"}
+	for _, group := range groups.SparklineGroups {
+		opts := SparklineOptions{
+			Type:          sparkTypes[group.Type],
+			Weight:        group.LineWeight,
+			DateAxis:      group.DateAxis,
+			DateRange:     group.F,
+			Markers:       group.Markers,
+			High:          group.High,
+			Low:           group.Low,
+			First:         group.First,
+			Last:          group.Last,
+			Negative:      group.Negative,
+			Axis:          group.DisplayXAxis,
+			Hidden:        group.DisplayHidden,
+			Reverse:       group.RightToLeft,
+			MaxAxisType:   "individual",
+			MinAxisType:   "individual",
+			EmptyCells:    "zero",
+			SeriesColor:   getSparklineColor(group.ColorSeries),
+			NegativeColor: getSparklineColor(group.ColorNegative),
+			MarkersColor:  getSparklineColor(group.ColorMarkers),
+			FirstColor:    getSparklineColor(group.ColorFirst),
+			LastColor:     getSparklineColor(group.ColorLast),
+			HightColor:    getSparklineColor(group.ColorHigh),
+			LowColor:      getSparklineColor(group.ColorLow),
+		}
+		if group.MaxAxisType != "" {
+			opts.MaxAxisType, opts.CustomMax = group.MaxAxisType, group.ManualMax
+		}
+		if group.MinAxisType != "" {
+			opts.MinAxisType, opts.CustomMin = group.MinAxisType, group.ManualMin
+		}
+		if group.DisplayEmptyCellsAs != "" {
+			opts.EmptyCells = group.DisplayEmptyCellsAs
+		}
+		for _, sparkline := range group.Sparklines {
+			opts.Location = append(opts.Location, sparkline.Sqref)
+			opts.Range = append(opts.Range, sparkline.F)
+		}
+		sparklines = append(sparklines, opts)
+	}
+	return sparklines, err
+}
+
+// DeleteSparkline provides a function to delete the sparklines in the
+// worksheet by given worksheet name and range reference. The sparklines
+// located in the range will be removed from the sparkline groups, and the
+// sparkline group without any sparkline will be removed. To update the
+// sparklines, delete them and add them again by the AddSparkline function.
+// For example, delete the sparklines located in the cells A1:A3 on Sheet1:
+//
+//	err := f.DeleteSparkline("Sheet1", "A1:A3")
+func (f *File) DeleteSparkline(sheet, rangeRef string) error {
+	if !strings.Contains(rangeRef, ":") {
+		rangeRef += ":" + rangeRef
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	extLst, idx, groups, err := f.getSparklineGroups(ws)
+	if err != nil || groups == nil {
+		return err
+	}
+	sparklineGroups := &xlsxX14SparklineGroups{XMLNSXM: NameSpaceSpreadSheetExcel2006Main.Value}
+	for _, group := range groups.SparklineGroups {
+		var sparklines []*xlsxX14Sparkline
+		for _, sparkline := range group.Sparklines {
+			col, row, err := CellNameToCoordinates(sparkline.Sqref)
+			if err == nil && cellInRange([]int{col, row}, coordinates) {
+				continue
+			}
+			sparklines = append(sparklines, &xlsxX14Sparkline{F: sparkline.F, Sqref: sparkline.Sqref})
+		}
+		if len(sparklines) == 0 {
+			continue
+		}
+		sparklineGroups.SparklineGroups = append(sparklineGroups.SparklineGroups, &xlsxX14SparklineGroup{
+			ManualMax: group.ManualMax, ManualMin: group.ManualMin, LineWeight: group.LineWeight,
+			Type: group.Type, DateAxis: group.DateAxis, DisplayEmptyCellsAs: group.DisplayEmptyCellsAs,
+			Markers: group.Markers, High: group.High, Low: group.Low, First: group.First,
+			Last: group.Last, Negative: group.Negative, DisplayXAxis: group.DisplayXAxis,
+			DisplayHidden: group.DisplayHidden, MinAxisType: group.MinAxisType,
+			MaxAxisType: group.MaxAxisType, RightToLeft: group.RightToLeft,
+			ColorSeries: group.ColorSeries, ColorNegative: group.ColorNegative,
+			ColorAxis: group.ColorAxis, ColorMarkers: group.ColorMarkers,
+			ColorFirst: group.ColorFirst, ColorLast: group.ColorLast,
+			ColorHigh: group.ColorHigh, ColorLow: group.ColorLow,
+			F: group.F, Sparklines: xlsxX14Sparklines{Sparkline: sparklines},
+		})
+	}
+	if len(sparklineGroups.SparklineGroups) == 0 {
+		extLst.Ext = append(extLst.Ext[:idx], extLst.Ext[idx+1:]...)
+	} else {
+		sparklineGroupsBytes, _ := xml.Marshal(sparklineGroups)
+		extLst.Ext[idx].Content = string(sparklineGroupsBytes)
+	}
+	if len(extLst.Ext) == 0 {
+		ws.ExtLst = nil
+		return err
+	}
+	extLstBytes, _ := xml.Marshal(extLst)
+	ws.ExtLst = &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}
+	f.addSheetNameSpace(sheet, NameSpaceSpreadSheetX14)
+	return err
+}
+
+// getSparklineGroups provides a function to get the decoded extension list,
+// the index of the sparkline groups extension and the sparkline groups by
+// given worksheet. The sparkline groups will be nil if the worksheet has no
+// sparkline.
+func (f *File) getSparklineGroups(ws *xlsxWorksheet) (*decodeWorksheetExt, int, *decodeX14SparklineGroupList, error) {
+	decodeExtLst := new(decodeWorksheetExt)
+	if ws.ExtLst == nil {
+		return decodeExtLst, -1, nil, nil
+	}
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return decodeExtLst, -1, nil, err
+	}
+	for idx, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURISparklineGroups {
+			continue
+		}
+		groups := new(decodeX14SparklineGroupList)
+		if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(groups); err != nil && err != io.EOF {
+			return decodeExtLst, idx, nil, err
+		}
+		return decodeExtLst, idx, groups, nil
+	}
+	return decodeExtLst, -1, nil, nil
+}
+
+// getSparklineColor provides a function to get the RGB color of the
+// sparkline, returns empty string if the color is not specified by RGB value.
+func getSparklineColor(color *xlsxTabColor) string {
+	if color == nil || color.RGB == "" {
+		return ""
+	}
+	rgb := strings.ToUpper(color.RGB)
+	if len(rgb) == 8 {
+		rgb = rgb[2:]
+	}
+	return "#" + rgb
+}
@@ -292,6 +292,144 @@ func TestAppendSparkline(t *testing.T) {
 	assert.EqualError(t, f.appendSparkline(ws, &xlsxX14SparklineGroup{}, &xlsxX14SparklineGroups{}), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestGetSparklines(t *testing.T) {
+	f, err := prepareSparklineDataset()
+	assert.NoError(t, err)
+	sparklines, err := f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, sparklines)
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location: []string{"A1", "A2"},
+		Range:    []string{"Sheet3!A1:J1", "Sheet3!A2:J2"},
+		Markers:  true,
+	}))
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location:      []string{"A3"},
+		Range:         []string{"Sheet3!A3:J3"},
+		Type:          "win_loss",
+		MaxAxisType:   "custom",
+		CustomMax:     1.5,
+		MinAxisType:   "group",
+		DateRange:     "Sheet3!A4:J4",
+		Hidden:        true,
+		EmptyCells:    "span",
+		Weight:        1.25,
+		SeriesColor:   "#FF0000",
+		NegativeColor: "#00FF00",
+		HightColor:    "#0000FF",
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetSparklines.xlsx")))
+	sparklines, err = f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, sparklines, 2)
+	assert.Equal(t, "line", sparklines[0].Type)
+	assert.Equal(t, []string{"A1", "A2"}, sparklines[0].Location)
+	assert.Equal(t, []string{"Sheet3!A1:J1", "Sheet3!A2:J2"}, sparklines[0].Range)
+	assert.True(t, sparklines[0].Markers)
+	assert.Equal(t, "individual", sparklines[0].MaxAxisType)
+	assert.Equal(t, "gap", sparklines[0].EmptyCells)
+	assert.Equal(t, SparklineOptions{
+		Location:      []string{"A3"},
+		Range:         []string{"Sheet3!A3:J3"},
+		Type:          "win_loss",
+		MaxAxisType:   "custom",
+		CustomMax:     1.5,
+		MinAxisType:   "group",
+		DateAxis:      true,
+		DateRange:     "Sheet3!A4:J4",
+		Hidden:        true,
+		EmptyCells:    "span",
+		Weight:        1.25,
+		SeriesColor:   "#FF0000",
+		NegativeColor: "#00FF00",
+		MarkersColor:  "",
+		HightColor:    "#0000FF",
+	}, sparklines[1])
+	// Test get sparkline color with and without the alpha channel
+	assert.Equal(t, "#FF0000", getSparklineColor(&xlsxTabColor{RGB: "FFFF0000"}))
+	assert.Equal(t, "#FF0000", getSparklineColor(&xlsxTabColor{RGB: "ff0000"}))
+	// Test add sparkline with the deprecated custom maximum and minimum
+	assert.NoError(t, f.AddSparkline("Sheet2", &SparklineOptions{
+		Location:    []string{"A1"},
+		Range:       []string{"Sheet3!A1:J1"},
+		MaxAxisType: "custom",
+		CustMax:     3,
+		MinAxisType: "custom",
+		CustMin:     -2,
+	}))
+	sparklines, err = f.GetSparklines("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, sparklines, 1)
+	assert.Equal(t, 3.0, sparklines[0].CustomMax)
+	assert.Equal(t, -2.0, sparklines[0].CustomMin)
+	// Test get sparklines on not exists worksheet
+	_, err = f.GetSparklines("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get sparklines with unsupported charset
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).ExtLst.Ext = string(MacintoshCyrillicCharset)
+	_, err = f.GetSparklines("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	ws.(*xlsxWorksheet).ExtLst.Ext = `<ext uri="` + ExtURISparklineGroups + `">` + string(MacintoshCyrillicCharset) + `</ext>`
+	_, err = f.GetSparklines("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test add sparkline with invalid options
+	for _, c := range []struct {
+		opts     *SparklineOptions
+		expected error
+	}{
+		{&SparklineOptions{MaxAxisType: "unknown"}, newInvalidOptionalValue("MaxAxisType", "unknown", []string{"individual", "group", "custom"})},
+		{&SparklineOptions{MinAxisType: "unknown"}, newInvalidOptionalValue("MinAxisType", "unknown", []string{"individual", "group", "custom"})},
+		{&SparklineOptions{EmptyCells: "unknown"}, newInvalidOptionalValue("EmptyCells", "unknown", []string{"gap", "zero", "span"})},
+	} {
+		c.opts.Location, c.opts.Range = []string{"A1"}, []string{"Sheet3!A1:J1"}
+		assert.EqualError(t, f.AddSparkline("Sheet2", c.opts), c.expected.Error())
+	}
+}
+
+func TestDeleteSparkline(t *testing.T) {
+	f, err := prepareSparklineDataset()
+	assert.NoError(t, err)
+	// Test delete sparkline on the worksheet without sparkline
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "A1"))
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location: []string{"A1", "A2", "A3"},
+		Range:    []string{"Sheet3!A1:J1", "Sheet3!A2:J2", "Sheet3!A3:J3"},
+	}))
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location: []string{"B1"},
+		Range:    []string{"Sheet3!A4:J4"},
+		Type:     "column",
+	}))
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "A3:A2"))
+	sparklines, err := f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, sparklines, 2)
+	assert.Equal(t, []string{"A1"}, sparklines[0].Location)
+	assert.Equal(t, []string{"Sheet3!A1:J1"}, sparklines[0].Range)
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "B1"))
+	sparklines, err = f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, sparklines, 1)
+	assert.Equal(t, []string{"A1"}, sparklines[0].Location)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteSparkline.xlsx")))
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "A1"))
+	sparklines, err = f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, sparklines)
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Nil(t, ws.(*xlsxWorksheet).ExtLst)
+	// Test delete sparkline with invalid range reference
+	assert.EqualError(t, f.DeleteSparkline("Sheet1", "A"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test delete sparkline on not exists worksheet
+	assert.EqualError(t, f.DeleteSparkline("SheetN", "A1"), "sheet SheetN does not exist")
+	// Test delete sparkline with unsupported charset
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
+	assert.EqualError(t, f.DeleteSparkline("Sheet1", "A1"), "XML syntax error on line 1: invalid UTF-8")
+}
+
 func prepareSparklineDataset() (*File, error) {
 	f := NewFile()
 	sheet2 := [][]int{
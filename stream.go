@@ -12,12 +12,17 @@
 package excel
 
 import (
+	"bufio"
 	"bytes"
+	"container/list"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -29,23 +34,68 @@ type StreamWriter struct {
 	Sheet           string
 	SheetID         int
 	sheetWritten    bool
-	cols            strings.Builder
+	cols            []xlsxCol
+	autoWidth       bool
+	colWidths       map[int]float64
+	fontMetrics     *fontMetrics
+	sharedStrings   *sharedStringsCache
 	worksheet       *xlsxWorksheet
+	head            bytes.Buffer
 	rawData         bufferedWriter
 	rows            int
+	outlineLevel    int
+	dimension       []int
 	mergeCellsCount int
 	mergeCells      strings.Builder
 	tableParts      string
 }
 
+// StreamWriterOptions define the options for the stream writer.
+//
+// BufferSize specifies the in-memory buffer size limit in bytes of the stream
+// writer, the streamed data will be written to the temporary file when the
+// in-memory buffer size is over this value, the default value is 16MB.
+//
+// TempDir specifies the directory for creating the temporary file, the
+// default is the system temporary directory.
+//
+// DisableTempFile specifies if keep all the streamed data in memory without
+// using the temporary file, this is useful on the read-only filesystems.
+//
+// AutoWidth specifies if measure the display width of each written cell value
+// and set the column width by the widest cell of each column on Flush, the
+// column width will not exceed the maximum column width 255. The columns
+// width set by the SetColWidth function take precedence over it. The width
+// is measured by the built-in or registered metrics of the default font, so
+// that the result is not dependent on the fonts installed on the system.
+//
+// SharedStrings specifies if write the string cell values into the shared
+// string table of the workbook instead of the inline strings, the repeated
+// values will be stored only once, which reduces the file size for the
+// worksheets with many repeated values.
+//
+// SharedStringsCacheSize specifies the maximum number of distinct strings kept
+// in the in-memory deduplication cache when the SharedStrings enabled, the
+// least recently used strings will be evicted from the cache when it's full,
+// and will be added into the shared string table again when written later.
+// The default value 0 means no limit.
+type StreamWriterOptions struct {
+	BufferSize             int64
+	TempDir                string
+	DisableTempFile        bool
+	AutoWidth              bool
+	SharedStrings          bool
+	SharedStringsCacheSize int
+}
+
 // NewStreamWriter return stream writer struct by given worksheet name for
 // generate new worksheet with large amounts of data. Note that after set
 // rows, you must call the 'Flush' method to end the streaming writing process
 // and ensure that the order of row numbers is ascending, the normal mode
 // functions and stream mode functions can't be work mixed to writing data on
 // the worksheets, you can't get cell value when in-memory chunks data over
-// 16MB. For example, set data for worksheet of size 102400 rows x 50 columns
-// with numbers and style:
+// the buffer size, which is 16MB by default. For example, set data for
+// worksheet of size 102400 rows x 50 columns with numbers and style:
 //
 //	file := excelize.NewFile()
 //	defer func() {
@@ -106,7 +156,23 @@ type StreamWriter struct {
 //	err := streamWriter.SetRow("A1", []interface{}{
 //	    excelize.Cell{Value: 1}},
 //	    excelize.RowOpts{StyleID: styleID, Height: 20, Hidden: false});
-func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
+//
+// Create stream writer which spills the streamed data into the temporary file
+// under the given directory when the in-memory data over 64MB:
+//
+//	streamWriter, err := file.NewStreamWriter("Sheet1", excelize.StreamWriterOptions{
+//	    BufferSize: 64 << 20,
+//	    TempDir:    "/data/tmp",
+//	})
+//
+// Create stream writer which writes the strings into the shared string table
+// and keeps at most 100000 distinct strings in the deduplication cache:
+//
+//	streamWriter, err := file.NewStreamWriter("Sheet1", excelize.StreamWriterOptions{
+//	    SharedStrings:          true,
+//	    SharedStringsCacheSize: 100000,
+//	})
+func (f *File) NewStreamWriter(sheet string, opts ...StreamWriterOptions) (*StreamWriter, error) {
 	if err := checkSheetName(sheet); err != nil {
 		return nil, err
 	}
@@ -114,10 +180,30 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 	if sheetID == -1 {
 		return nil, newNoExistSheetError(sheet)
 	}
+	options := parseStreamWriterOptions(opts...)
+	if options.BufferSize < 0 {
+		return nil, ErrStreamBufferSize
+	}
+	if options.SharedStringsCacheSize < 0 {
+		return nil, ErrStreamSharedStringsCacheSize
+	}
 	sw := &StreamWriter{
-		file:    f,
-		Sheet:   sheet,
-		SheetID: sheetID,
+		file:      f,
+		Sheet:     sheet,
+		SheetID:   sheetID,
+		autoWidth: options.AutoWidth,
+		colWidths: make(map[int]float64),
+		rawData: bufferedWriter{
+			chunkSize:   options.BufferSize,
+			tempDir:     options.TempDir,
+			disableTemp: options.DisableTempFile,
+		},
+	}
+	if options.AutoWidth {
+		sw.fontMetrics = f.getDefaultFontMetrics()
+	}
+	if options.SharedStrings {
+		sw.sharedStrings = newSharedStringsCache(options.SharedStringsCacheSize)
 	}
 	var err error
 	sw.worksheet, err = f.workSheetReader(sheet)
@@ -130,12 +216,160 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 		f.streams = make(map[string]*StreamWriter)
 	}
 	f.streams[sheetXMLPath] = sw
-	
-	_, _ = sw.rawData.WriteString(xml.Header + `<worksheet` + templateNamespaceIDMap)
-	bulkAppendFields(&sw.rawData, sw.worksheet, 2, 3)
 	return sw, err
 }
 
+// parseStreamWriterOptions provides a function to parse the optional settings
+// for creating the stream writer.
+func parseStreamWriterOptions(opts ...StreamWriterOptions) *StreamWriterOptions {
+	options := &StreamWriterOptions{}
+	for _, opt := range opts {
+		options = &opt
+	}
+	return options
+}
+
+// SetSheetProps provides a function to set worksheet properties for the
+// StreamWriter, such as tab color, default column width and default row
+// height. The properties will be written when calling the 'Flush' function,
+// so the 'SetSheetProps' function can be called at any time before 'Flush'.
+// For example, set the tab color and default row height:
+//
+//	tabColor, rowHeight := "FFFF0000", 20.0
+//	err := streamWriter.SetSheetProps(&excelize.SheetPropsOptions{
+//	    TabColorRGB:      &tabColor,
+//	    DefaultRowHeight: &rowHeight,
+//	})
+func (sw *StreamWriter) SetSheetProps(opts *SheetPropsOptions) error {
+	return sw.file.SetSheetProps(sw.Sheet, opts)
+}
+
+// SetSheetFormat provides a function to set the default row and column
+// formatting properties of the worksheet for the StreamWriter, the settings
+// are the same with the SetSheetFormat function of the File. The properties
+// will be written when calling the 'Flush' function. For example, hide the
+// rows without data by default:
+//
+//	zeroHeight := true
+//	err := streamWriter.SetSheetFormat(&excelize.SheetFormatOptions{
+//	    ZeroHeight: &zeroHeight,
+//	})
+func (sw *StreamWriter) SetSheetFormat(opts *SheetFormatOptions) error {
+	return sw.file.SetSheetFormat(sw.Sheet, opts)
+}
+
+// SetSheetView provides a function to set sheet view options for the
+// StreamWriter. The viewIndex may be negative and if so is counted backward
+// (-1 is the last view). The view options will be written when calling the
+// 'Flush' function. For example, hide the gridlines of the first view:
+//
+//	showGridLines := false
+//	err := streamWriter.SetSheetView(0, &excelize.ViewOptions{
+//	    ShowGridLines: &showGridLines,
+//	})
+func (sw *StreamWriter) SetSheetView(viewIndex int, opts *ViewOptions) error {
+	return sw.file.SetSheetView(sw.Sheet, viewIndex, opts)
+}
+
+// SetSheetVisible provides a function to set visibility of the worksheet for
+// the StreamWriter. The optional veryHidden parameter only works when visible
+// was false. See File.SetSheetVisible for details.
+func (sw *StreamWriter) SetSheetVisible(visible bool, veryHidden ...bool) error {
+	return sw.file.SetSheetVisible(sw.Sheet, visible, veryHidden...)
+}
+
+// ProtectSheet provides a function to prevent other users from accidentally or
+// deliberately changing, moving, or deleting data in the worksheet for the
+// StreamWriter. The sheet protection will be written when calling the 'Flush'
+// function. For example, protect the streamed worksheet with password, but
+// allow select locked cells and unlocked cells:
+//
+//	err := streamWriter.ProtectSheet(&excelize.SheetProtectionOptions{
+//	    AlgorithmName:       "SHA-512",
+//	    Password:            "password",
+//	    SelectLockedCells:   true,
+//	    SelectUnlockedCells: true,
+//	})
+//
+// See File.ProtectSheet for details on the protection options.
+func (sw *StreamWriter) ProtectSheet(opts *SheetProtectionOptions) error {
+	return sw.file.ProtectSheet(sw.Sheet, opts)
+}
+
+// SetDefinedName provides a function to set the defined names of the workbook
+// or worksheet for the StreamWriter, if not specified scope, the default
+// scope is workbook. The defined names with the special names such as
+// '_xlnm.Print_Titles' could be used to set the repeat-title rows and columns
+// of the streamed worksheet. For example, set the first row repeat on each
+// printed page:
+//
+//	err := streamWriter.SetDefinedName(&excelize.DefinedName{
+//	    Name:     "_xlnm.Print_Titles",
+//	    RefersTo: "Sheet1!$1:$1",
+//	    Scope:    "Sheet1",
+//	})
+func (sw *StreamWriter) SetDefinedName(definedName *DefinedName) error {
+	if definedName == nil {
+		return ErrParameterInvalid
+	}
+	return sw.file.SetDefinedName(definedName)
+}
+
+// SetPrintArea provides a function to set the print area of the worksheet for
+// the StreamWriter by given range reference, the existing print area of the
+// worksheet will be replaced. For example, set the print area as A1:D20:
+//
+//	err := streamWriter.SetPrintArea("A1:D20")
+func (sw *StreamWriter) SetPrintArea(rangeRef string) error {
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	// Correct reference range, such correct C1:B3 to B1:C3.
+	ref, err := sw.file.coordinatesToRangeRef(coordinates, true)
+	if err != nil {
+		return err
+	}
+	printArea := &DefinedName{Name: "_xlnm.Print_Area", Scope: sw.Sheet}
+	if err = sw.file.DeleteDefinedName(printArea); err != nil && err != ErrDefinedNameScope {
+		return err
+	}
+	printArea.RefersTo = fmt.Sprintf("'%s'!%s", strings.ReplaceAll(sw.Sheet, "'", "''"), ref)
+	return sw.file.SetDefinedName(printArea)
+}
+
+// AddChart provides a function to add chart in the streamed worksheet by
+// given cell reference and chart options, the chart could reference the
+// ranges in the streamed worksheet without reopening the flushed worksheet.
+// The columns width set by the SetColWidth function will be used to position
+// the chart. For example, add a column chart for the data in the range
+// A1:B5 of the worksheet Sheet1 at cell D1:
+//
+//	err := streamWriter.AddChart("D1", &excelize.Chart{
+//	    Type: excelize.Col,
+//	    Series: []excelize.ChartSeries{
+//	        {
+//	            Name:       "Sheet1!$B$1",
+//	            Categories: "Sheet1!$A$2:$A$5",
+//	            Values:     "Sheet1!$B$2:$B$5",
+//	        },
+//	    },
+//	})
+//
+// Note that AddChart must be called before Flush.
+//
+// See File.AddChart for details on the chart options.
+func (sw *StreamWriter) AddChart(cell string, chart *Chart, combo ...*Chart) error {
+	cols := sw.worksheet.Cols
+	if streamCols := sw.getCols(); len(streamCols) > 0 {
+		sw.worksheet.Cols = &xlsxCols{Col: streamCols}
+	}
+	err := sw.file.AddChart(sw.Sheet, cell, chart, combo...)
+	sw.worksheet.Cols = cols
+	return err
+}
+
 // AddTable creates an Excel table for the StreamWriter using the given
 // cell range and format set. For example, create a table of A1:D5:
 //
@@ -157,11 +391,17 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 // header cells must contain strings and must be unique.
 //
 // Currently, only one table is allowed for a StreamWriter. AddTable must be
-// called after the rows are written but before Flush.
+// called after the rows are written but before Flush. The totals row and the
+// column settings of the table are not supported in stream writing mode,
+// since the cells of the table have been written, AddTable will return an
+// error if the ShowTotalsRow or Columns option was specified.
 //
 // See File.AddTable for details on the table format.
 func (sw *StreamWriter) AddTable(rangeRef string, opts *TableOptions) error {
 	options := parseTableOptions(opts)
+	if options.ShowTotalsRow || len(options.Columns) > 0 {
+		return ErrStreamTableColumns
+	}
 	coordinates, err := rangeRefToCoordinates(rangeRef)
 	if err != nil {
 		return err
@@ -247,8 +487,14 @@ func (sw *StreamWriter) getRowValues(hRow, hCol, vCol int) (res []string, err er
 	if err != nil {
 		return nil, err
 	}
+	sst := &xlsxSST{}
+	if sw.sharedStrings != nil {
+		if sst, err = sw.file.sharedStringsReader(); err != nil {
+			return nil, err
+		}
+	}
 	
-	dec := sw.file.xmlNewDecoder(r)
+	dec := sw.file.xmlNewDecoder(io.MultiReader(strings.NewReader(xml.Header+`<worksheet`+templateNamespaceIDMap), r))
 	for {
 		token, err := dec.Token()
 		if err == io.EOF {
@@ -274,7 +520,7 @@ func (sw *StreamWriter) getRowValues(hRow, hCol, vCol int) (res []string, err er
 			if col < hCol || col > vCol {
 				continue
 			}
-			res[col-hCol], _ = c.getValueFrom(sw.file, nil, false)
+			res[col-hCol], _ = c.getValueFrom(sw.file, sst, false)
 		}
 		return res, nil
 	}
@@ -390,6 +636,9 @@ func (sw *StreamWriter) SetRow(cell string, values []interface{}, opts ...RowOpt
 	if err != nil {
 		return err
 	}
+	if options.OutlineLevel > sw.outlineLevel {
+		sw.outlineLevel = options.OutlineLevel
+	}
 	_, _ = sw.rawData.WriteString(`<row r="`)
 	_, _ = sw.rawData.WriteString(strconv.Itoa(row))
 	_, _ = sw.rawData.WriteString(`"`)
@@ -417,6 +666,10 @@ func (sw *StreamWriter) SetRow(cell string, values []interface{}, opts ...RowOpt
 			_, _ = sw.rawData.WriteString(`</row>`)
 			return err
 		}
+		sw.trackColWidth(col+i, val)
+		if c.hasValue() {
+			sw.dimension = extendDimension(sw.dimension, col+i, row)
+		}
 		writeCell(&sw.rawData, c)
 	}
 	_, _ = sw.rawData.WriteString(`</row>`)
@@ -424,15 +677,14 @@ func (sw *StreamWriter) SetRow(cell string, values []interface{}, opts ...RowOpt
 }
 
 // SetColWidth provides a function to set the width of a single column or
-// multiple columns for the StreamWriter. Note that you must call
-// the 'SetColWidth' function before the 'SetRow' function. For example set
-// the width column B:C as 20:
+// multiple columns for the StreamWriter. The columns will be written when
+// calling the 'Flush' function, so the 'SetColWidth' function can be called
+// after the 'SetRow' function, for example, set the column width by the data
+// length after all rows have been written. For example set the width column
+// B:C as 20:
 //
 //	err := streamWriter.SetColWidth(2, 3, 20)
 func (sw *StreamWriter) SetColWidth(min, max int, width float64) error {
-	if sw.sheetWritten {
-		return ErrStreamSetColWidth
-	}
 	if min < MinColumns || min > MaxColumns || max < MinColumns || max > MaxColumns {
 		return ErrColumnNumber
 	}
@@ -443,13 +695,7 @@ func (sw *StreamWriter) SetColWidth(min, max int, width float64) error {
 		min, max = max, min
 	}
 	
-	sw.cols.WriteString(`<col min="`)
-	sw.cols.WriteString(strconv.Itoa(min))
-	sw.cols.WriteString(`" max="`)
-	sw.cols.WriteString(strconv.Itoa(max))
-	sw.cols.WriteString(`" width="`)
-	sw.cols.WriteString(strconv.FormatFloat(width, 'f', -1, 64))
-	sw.cols.WriteString(`" customWidth="1"/>`)
+	sw.cols = append(sw.cols, xlsxCol{Min: min, Max: max, Width: width, CustomWidth: true})
 	return nil
 }
 
@@ -462,12 +708,9 @@ func (sw *StreamWriter) InsertPageBreak(cell string) error {
 }
 
 // SetPanes provides a function to create and remove freeze panes and split
-// panes by giving panes options for the StreamWriter. Note that you must call
-// the 'SetPanes' function before the 'SetRow' function.
+// panes by giving panes options for the StreamWriter. The panes will be
+// written when calling the 'Flush' function.
 func (sw *StreamWriter) SetPanes(panes *Panes) error {
-	if sw.sheetWritten {
-		return ErrStreamSetPanes
-	}
 	return sw.worksheet.setPanes(panes)
 }
 
@@ -523,9 +766,9 @@ func (sw *StreamWriter) setCellValFunc(c *xlsxC, val interface{}) error {
 	case float64:
 		c.T, c.V = setCellFloat(val, -1, 64)
 	case string:
-		c.setCellValue(val)
+		err = sw.setCellStr(c, val)
 	case []byte:
-		c.setCellValue(string(val))
+		err = sw.setCellStr(c, string(val))
 	case time.Duration:
 		c.T, c.V = setCellDuration(val)
 	case time.Time:
@@ -538,11 +781,74 @@ func (sw *StreamWriter) setCellValFunc(c *xlsxC, val interface{}) error {
 		c.T, c.IS = "inlineStr", &xlsxSI{}
 		c.IS.R, err = setRichText(val)
 	default:
-		c.setCellValue(fmt.Sprint(val))
+		err = sw.setCellStr(c, fmt.Sprint(val))
 	}
 	return err
 }
 
+// setCellStr provides a function to set the string cell value, the value will
+// be written into the shared string table if the SharedStrings option of the
+// stream writer enabled.
+func (sw *StreamWriter) setCellStr(c *xlsxC, val string) error {
+	if sw.sharedStrings == nil || c.F != nil || val == "" {
+		c.setCellValue(val)
+		return nil
+	}
+	idx, ok := sw.sharedStrings.get(val)
+	if !ok {
+		var err error
+		if idx, err = sw.file.addSharedString(val); err != nil {
+			return err
+		}
+		sw.sharedStrings.add(val, idx)
+	}
+	c.T, c.V = "s", strconv.Itoa(idx)
+	return nil
+}
+
+// sharedStringsCache is the least recently used cache of the shared string
+// table index for the stream writer.
+type sharedStringsCache struct {
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+// sharedStringsCacheItem defined the cached string and its index in the shared
+// string table.
+type sharedStringsCacheItem struct {
+	val string
+	idx int
+}
+
+// newSharedStringsCache returns the shared strings cache by given maximum
+// number of the cached strings, the value 0 means no limit.
+func newSharedStringsCache(size int) *sharedStringsCache {
+	return &sharedStringsCache{size: size, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get returns the index in the shared string table by given string, and
+// marks it as the most recently used.
+func (c *sharedStringsCache) get(val string) (int, bool) {
+	e, ok := c.items[val]
+	if !ok {
+		return -1, ok
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*sharedStringsCacheItem).idx, ok
+}
+
+// add puts the string and its index in the shared string table into the
+// cache, and evicts the least recently used string when the cache is full.
+func (c *sharedStringsCache) add(val string, idx int) {
+	if c.size > 0 && c.order.Len() >= c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.items, e.Value.(*sharedStringsCacheItem).val)
+	}
+	c.items[val] = c.order.PushFront(&sharedStringsCacheItem{val: val, idx: idx})
+}
+
 // setCellIntFunc is a wrapper of SetCellInt.
 func setCellIntFunc(c *xlsxC, val interface{}) (err error) {
 	switch val := val.(type) {
@@ -629,23 +935,216 @@ func writeCell(buf *bufferedWriter, c xlsxC) {
 	_, _ = buf.WriteString(`</c>`)
 }
 
-// writeSheetData prepares the element preceding sheetData and writes the
-// sheetData XML start element to the buffer.
+// writeSheetData writes the sheetData XML start element to the buffer.
 func (sw *StreamWriter) writeSheetData() {
 	if !sw.sheetWritten {
-		bulkAppendFields(&sw.rawData, sw.worksheet, 4, 5)
-		if sw.cols.Len() > 0 {
-			_, _ = sw.rawData.WriteString("<cols>")
-			_, _ = sw.rawData.WriteString(sw.cols.String())
-			_, _ = sw.rawData.WriteString("</cols>")
-		}
 		_, _ = sw.rawData.WriteString(`<sheetData>`)
 		sw.sheetWritten = true
 	}
 }
 
+// writeSheetHead prepares the worksheet XML start element and the elements
+// preceding sheetData, these elements are written on Flush, so that the
+// worksheet properties could be changed after rows have been written.
+func (sw *StreamWriter) writeSheetHead() {
+	sw.head.Reset()
+	if sw.outlineLevel > 0 && (sw.worksheet.SheetFormatPr == nil || int(sw.worksheet.SheetFormatPr.OutlineLevelRow) < sw.outlineLevel) {
+		sw.worksheet.setSheetFormat(&SheetFormatOptions{OutlineLevelRow: uint8Ptr(uint8(sw.outlineLevel))})
+	}
+	_, _ = sw.head.WriteString(xml.Header + `<worksheet` + templateNamespaceIDMap)
+	bulkAppendFields(&sw.head, sw.worksheet, 2, 5)
+	if cols := sw.getCols(); len(cols) > 0 {
+		_, _ = sw.head.WriteString("<cols>")
+		for _, col := range cols {
+			_, _ = sw.head.WriteString(`<col min="`)
+			_, _ = sw.head.WriteString(strconv.Itoa(col.Min))
+			_, _ = sw.head.WriteString(`" max="`)
+			_, _ = sw.head.WriteString(strconv.Itoa(col.Max))
+			_, _ = sw.head.WriteString(`" width="`)
+			_, _ = sw.head.WriteString(strconv.FormatFloat(col.Width, 'f', -1, 64))
+			_, _ = sw.head.WriteString(`" customWidth="1"/>`)
+		}
+		_, _ = sw.head.WriteString("</cols>")
+	}
+}
+
+// getCols returns the columns of the StreamWriter, which contains the columns
+// width set by the SetColWidth function and the tracked columns width if the
+// AutoWidth option was enabled, the columns width set by SetColWidth take
+// precedence over the tracked columns width.
+func (sw *StreamWriter) getCols() []xlsxCol {
+	cols := append([]xlsxCol{}, sw.cols...)
+	for col, width := range sw.colWidths {
+		var exists bool
+		for _, c := range sw.cols {
+			if exists = c.Min <= col && col <= c.Max; exists {
+				break
+			}
+		}
+		if !exists {
+			cols = append(cols, xlsxCol{Min: col, Max: col, Width: width, CustomWidth: true})
+		}
+	}
+	sort.SliceStable(cols, func(i, j int) bool {
+		return cols[i].Min < cols[j].Min
+	})
+	return cols
+}
+
+// trackColWidth records the display width of the given cell value for the
+// column if the AutoWidth option was enabled. The recorded width of each
+// column is the max width of the cells in this column, and not less than the
+// default column width.
+func (sw *StreamWriter) trackColWidth(col int, val interface{}) {
+	if !sw.autoWidth {
+		return
+	}
+	width := math.Min(cellValueWidth(sw.fontMetrics, val)+autoWidthPadding, MaxColumnWidth)
+	if width <= defaultColWidth {
+		return
+	}
+	if width > sw.colWidths[col] {
+		sw.colWidths[col] = width
+	}
+}
+
+// cellValueWidth returns the display width in characters of the given cell
+// value by given font metrics, the width is measured in the multiples of
+// the width of the digit character and rounded up to two decimal places.
+// For the multiple lines text, the width of the longest line will be
+// returned.
+func cellValueWidth(metrics *fontMetrics, val interface{}) float64 {
+	var text string
+	switch v := val.(type) {
+	case nil:
+		return 0
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case []RichTextRun:
+		var buf strings.Builder
+		for _, run := range v {
+			buf.WriteString(run.Text)
+		}
+		text = buf.String()
+	case time.Time:
+		text = v.Format("1/2/06 15:04")
+	case bool:
+		text = strings.ToUpper(strconv.FormatBool(v))
+	case float32:
+		text = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		text = fmt.Sprint(v)
+	}
+	var width int
+	for _, line := range strings.Split(text, "\n") {
+		var w int
+		for _, r := range line {
+			w += metrics.width(r)
+		}
+		if w > width {
+			width = w
+		}
+	}
+	return math.Ceil(float64(width)/float64(metrics.width('0'))*100) / 100
+}
+
+// reader provides read-access to the worksheet XML generated by the
+// StreamWriter.
+func (sw *StreamWriter) reader() (io.Reader, error) {
+	r, err := sw.rawData.Reader()
+	if err != nil {
+		return nil, err
+	}
+	return io.MultiReader(bytes.NewReader(sw.head.Bytes()), r), nil
+}
+
+// size returns the size in bytes of the worksheet XML generated by the
+// StreamWriter, including the data stored in the temporary file.
+func (sw *StreamWriter) size() int64 {
+	size := int64(sw.head.Len() + sw.rawData.buf.Len())
+	if sw.rawData.tmp != nil {
+		if fi, err := sw.rawData.tmp.Stat(); err == nil {
+			size += fi.Size()
+		}
+	}
+	return size
+}
+
+// streamStyleAttrExp defined the regular expression to match the style
+// attribute of the row and cell elements in the streamed worksheet data.
+var streamStyleAttrExp = regexp.MustCompile(` s="(\d+)"`)
+
+// remapStyles provides a function to remap the style index of the rows and
+// cells in the streamed worksheet data by given function, the streamed data
+// will be rewritten.
+func (sw *StreamWriter) remapStyles(fn func(styleID int) int) error {
+	r, err := sw.rawData.Reader()
+	if err != nil {
+		return err
+	}
+	rawData := bufferedWriter{
+		chunkSize: sw.rawData.chunkSize, tempDir: sw.rawData.tempDir, disableTemp: sw.rawData.disableTemp,
+	}
+	if err = remapStreamStyles(r, &rawData, fn); err != nil {
+		_ = rawData.Close()
+		return err
+	}
+	_ = sw.rawData.Close()
+	sw.rawData = rawData
+	return err
+}
+
+// remapStreamStyles provides a function to copy the streamed worksheet data
+// from reader to writer, and remap the style index of the rows and cells by
+// given function, the buffered writer will be synced to the temporary file
+// during copying. The start tags of the elements could be matched without
+// decoding the XML, because the characters '<' and '>' are always escaped in
+// the streamed text and attribute values.
+func remapStreamStyles(r io.Reader, w io.Writer, fn func(styleID int) int) error {
+	br := bufio.NewReader(r)
+	for {
+		piece, err := br.ReadBytes('>')
+		if idx := bytes.LastIndexByte(piece, '<'); idx != -1 &&
+			(bytes.HasPrefix(piece[idx:], []byte("<c ")) || bytes.HasPrefix(piece[idx:], []byte("<row "))) {
+			tag := streamStyleAttrExp.ReplaceAllFunc(piece[idx:], func(attr []byte) []byte {
+				styleID, _ := strconv.Atoi(string(attr[4 : len(attr)-1]))
+				return []byte(` s="` + strconv.Itoa(fn(styleID)) + `"`)
+			})
+			piece = append(piece[:idx:idx], tag...)
+		}
+		if _, werr := w.Write(piece); werr != nil {
+			return werr
+		}
+		if bw, ok := w.(*bufferedWriter); ok {
+			if werr := bw.Sync(); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// bytes returns the in-memory worksheet XML generated by the StreamWriter,
+// the data stored in the temporary file are not included.
+func (sw *StreamWriter) bytes() []byte {
+	if sw.rawData.tmp != nil {
+		return sw.rawData.buf.Bytes()
+	}
+	return append(append([]byte{}, sw.head.Bytes()...), sw.rawData.buf.Bytes()...)
+}
+
 // Flush ending the streaming writing process.
 func (sw *StreamWriter) Flush() error {
+	sw.writeSheetHead()
 	sw.writeSheetData()
 	_, _ = sw.rawData.WriteString(`</sheetData>`)
 	bulkAppendFields(&sw.rawData, sw.worksheet, 8, 15)
@@ -689,10 +1188,15 @@ func bulkAppendFields(w io.Writer, ws *xlsxWorksheet, from, to int) {
 // bufferedWriter uses a temp file to store an extended buffer. Writes are
 // always made to an in-memory buffer, which will always succeed. The buffer
 // is written to the temp file with Sync, which may return an error.
-// Therefore, Sync should be periodically called and the error checked.
+// Therefore, Sync should be periodically called and the error checked. The
+// zero value of chunkSize and tempDir use the StreamChunkSize and the system
+// temporary directory.
 type bufferedWriter struct {
-	tmp *os.File
-	buf bytes.Buffer
+	tmp         *os.File
+	buf         bytes.Buffer
+	chunkSize   int64
+	tempDir     string
+	disableTemp bool
 }
 
 // Write to the in-memory buffer. The error is always nil.
@@ -724,12 +1228,19 @@ func (bw *bufferedWriter) Reader() (io.Reader, error) {
 // Sync will write the in-memory buffer to a temp file, if the in-memory
 // buffer has grown large enough. Any error will be returned.
 func (bw *bufferedWriter) Sync() (err error) {
+	chunkSize, tempDir := bw.chunkSize, bw.tempDir
+	if chunkSize == 0 {
+		chunkSize = StreamChunkSize
+	}
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
 	// Try to use local storage
-	if bw.buf.Len() < StreamChunkSize {
+	if bw.disableTemp || int64(bw.buf.Len()) < chunkSize {
 		return nil
 	}
 	if bw.tmp == nil {
-		bw.tmp, err = os.CreateTemp(os.TempDir(), "excelize-")
+		bw.tmp, err = os.CreateTemp(tempDir, "excelize-")
 		if err != nil {
 			// can not use local storage
 			return nil
@@ -3,14 +3,17 @@ package excel
 import (
 	"encoding/xml"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 	
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font/gofont/gomono"
 )
 
 func BenchmarkStreamWriter(b *testing.B) {
@@ -162,7 +165,20 @@ func TestStreamSetColWidth(t *testing.T) {
 	assert.ErrorIs(t, streamWriter.SetColWidth(MaxColumns+1, 3, 20), ErrColumnNumber)
 	assert.EqualError(t, streamWriter.SetColWidth(1, 3, MaxColumnWidth+1), ErrColumnWidth.Error())
 	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A", "B", "C"}))
-	assert.ErrorIs(t, streamWriter.SetColWidth(2, 3, 20), ErrStreamSetColWidth)
+	// Test set column width after rows have been written
+	assert.NoError(t, streamWriter.SetColWidth(4, 4, 30))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamSetColWidth.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamSetColWidth.xlsx"))
+	assert.NoError(t, err)
+	width, err := f.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	width, err = f.GetColWidth("Sheet1", "D")
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, width)
+	assert.NoError(t, f.Close())
 }
 
 func TestStreamSetPanes(t *testing.T) {
@@ -185,7 +201,18 @@ func TestStreamSetPanes(t *testing.T) {
 	assert.NoError(t, streamWriter.SetPanes(paneOpts))
 	assert.EqualError(t, streamWriter.SetPanes(nil), ErrParameterInvalid.Error())
 	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A", "B", "C"}))
-	assert.ErrorIs(t, streamWriter.SetPanes(paneOpts), ErrStreamSetPanes)
+	// Test set panes after rows have been written
+	paneOpts.TopLeftCell = "C1"
+	assert.NoError(t, streamWriter.SetPanes(paneOpts))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamSetPanes.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamSetPanes.xlsx"))
+	assert.NoError(t, err)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "C1", ws.SheetViews.SheetView[0].Pane.TopLeftCell)
+	assert.NoError(t, f.Close())
 }
 
 func TestStreamTable(t *testing.T) {
@@ -219,6 +246,9 @@ func TestStreamTable(t *testing.T) {
 	
 	assert.NoError(t, streamWriter.AddTable("A1:C1", nil))
 	
+	// Test add table with the totals row and column settings
+	assert.Equal(t, ErrStreamTableColumns, streamWriter.AddTable("A1:C2", &TableOptions{ShowTotalsRow: true}))
+	assert.Equal(t, ErrStreamTableColumns, streamWriter.AddTable("A1:C2", &TableOptions{Columns: []TableColumnOptions{{Column: "A", TotalsRowLabel: "Total"}}}))
 	// Test add table with illegal cell reference
 	assert.EqualError(t, streamWriter.AddTable("A:B1", nil), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
 	assert.EqualError(t, streamWriter.AddTable("A1:B", nil), newCellNameToCoordinatesError("B", newInvalidCellNameError("B")).Error())
@@ -228,6 +258,49 @@ func TestStreamTable(t *testing.T) {
 	assert.EqualError(t, streamWriter.AddTable("A1:C2", nil), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestStreamAddChart(t *testing.T) {
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetColWidth(5, 10, 20))
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Category", "Value"}))
+	for r := 2; r <= 5; r++ {
+		assert.NoError(t, sw.SetRow(fmt.Sprintf("A%d", r), []interface{}{fmt.Sprintf("C%d", r), r}))
+	}
+	chart := &Chart{
+		Type: Col,
+		Series: []ChartSeries{
+			{Name: "Sheet1!$B$1", Categories: "Sheet1!$A$2:$A$5", Values: "Sheet1!$B$2:$B$5"},
+		},
+	}
+	assert.NoError(t, sw.AddChart("E1", chart))
+	assert.Nil(t, sw.worksheet.Cols)
+	assert.NotNil(t, sw.worksheet.Drawing)
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStreamAddChart.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestStreamAddChart.xlsx"))
+	assert.NoError(t, err)
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	assert.Equal(t, "Sheet1!$B$2:$B$5", charts[0].Series[0].Values)
+	drawing, ok := f.Pkg.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(drawing.([]byte)), "<xdr:to><xdr:col>7</xdr:col>")
+	assert.NoError(t, f.Close())
+
+	// Test add chart with invalid cell reference
+	f = NewFile()
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, sw.AddChart("A", chart), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test add chart with unsupported chart type
+	assert.EqualError(t, sw.AddChart("A1", &Chart{Type: "unknown", Series: chart.Series}), newUnsupportedChartType("unknown").Error())
+	assert.NoError(t, f.Close())
+}
+
 func TestStreamMergeCells(t *testing.T) {
 	file := NewFile()
 	defer func() {
@@ -398,3 +471,350 @@ func TestStreamWriterOutlineLevel(t *testing.T) {
 	assert.Equal(t, uint8(0), level)
 	assert.NoError(t, file.Close())
 }
+
+func TestStreamSetSheetProps(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	_, err := file.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	streamWriter, err := file.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A", "B", "C"}))
+	// Test set worksheet properties after rows have been written
+	assert.NoError(t, streamWriter.SetSheetProps(&SheetPropsOptions{
+		TabColorRGB:      stringPtr("FFFF0000"),
+		DefaultColWidth:  float64Ptr(12),
+		DefaultRowHeight: float64Ptr(20),
+	}))
+	assert.NoError(t, streamWriter.SetSheetFormat(&SheetFormatOptions{ZeroHeight: boolPtr(true), ThickBottom: boolPtr(true)}))
+	assert.Equal(t, ErrColumnWidth, streamWriter.SetSheetFormat(&SheetFormatOptions{DefaultColWidth: float64Ptr(MaxColumnWidth + 1)}))
+	assert.NoError(t, streamWriter.SetSheetView(0, &ViewOptions{ShowGridLines: boolPtr(false)}))
+	assert.NoError(t, streamWriter.SetSheetVisible(false))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamSetSheetProps.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamSetSheetProps.xlsx"))
+	assert.NoError(t, err)
+	sheetXML, ok := f.Pkg.Load("xl/worksheets/sheet2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(sheetXML.([]byte)), `<sheetFormatPr defaultColWidth="12" defaultRowHeight="20" zeroHeight="true" thickBottom="true"></sheetFormatPr>`)
+	opts, err := f.GetSheetProps("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, "FFFF0000", *opts.TabColorRGB)
+	assert.Equal(t, 12.0, *opts.DefaultColWidth)
+	assert.Equal(t, 20.0, *opts.DefaultRowHeight)
+	format, err := f.GetSheetFormat("Sheet2")
+	assert.NoError(t, err)
+	assert.True(t, *format.ZeroHeight)
+	assert.True(t, *format.ThickBottom)
+	assert.False(t, *format.ThickTop)
+	view, err := f.GetSheetView("Sheet2", 0)
+	assert.NoError(t, err)
+	assert.False(t, *view.ShowGridLines)
+	visible, err := f.GetSheetVisible("Sheet2")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	cellValue, err := f.GetCellValue("Sheet2", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "C", cellValue)
+	assert.NoError(t, f.Close())
+	// Test set worksheet view with invalid view index
+	assert.EqualError(t, streamWriter.SetSheetView(1, nil), newViewIdxError(1).Error())
+}
+
+func TestStreamSheetPropsAttributes(t *testing.T) {
+	file := NewFile()
+	// Test the worksheet properties set before creating the stream writer
+	expected := SheetPropsOptions{
+		TabColorTheme:        intPtr(0),
+		TabColorTint:         float64Ptr(-0.25),
+		OutlineSummaryBelow:  boolPtr(false),
+		OutlineSummaryRight:  boolPtr(false),
+		OutlineApplyStyles:   boolPtr(true),
+		OutlineShowSymbols:   boolPtr(false),
+		SyncHorizontal:       boolPtr(true),
+		SyncVertical:         boolPtr(true),
+		SyncRef:              stringPtr("C3"),
+		TransitionEvaluation: boolPtr(true),
+		TransitionEntry:      boolPtr(true),
+		OutlineLevelCol:      uint8Ptr(1),
+	}
+	assert.NoError(t, file.SetSheetProps("Sheet1", &expected))
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A"}))
+	assert.NoError(t, streamWriter.SetRow("A2", []interface{}{"B"}, RowOpts{OutlineLevel: 2}))
+	assert.NoError(t, streamWriter.SetRow("A3", []interface{}{"C"}, RowOpts{OutlineLevel: 1}))
+	assert.NoError(t, streamWriter.Flush())
+	path := filepath.Join("test", "TestStreamSheetPropsAttributes.xlsx")
+	assert.NoError(t, file.SaveAs(path))
+	assert.NoError(t, file.Close())
+
+	f, err := OpenFile(path)
+	assert.NoError(t, err)
+	opts, err := f.GetSheetProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *opts.TabColorTheme)
+	assert.Equal(t, -0.25, *opts.TabColorTint)
+	assert.False(t, *opts.OutlineSummaryBelow)
+	assert.False(t, *opts.OutlineSummaryRight)
+	assert.True(t, *opts.OutlineApplyStyles)
+	assert.False(t, *opts.OutlineShowSymbols)
+	assert.True(t, *opts.SyncHorizontal)
+	assert.True(t, *opts.SyncVertical)
+	assert.Equal(t, "C3", *opts.SyncRef)
+	assert.True(t, *opts.TransitionEvaluation)
+	assert.True(t, *opts.TransitionEntry)
+	assert.Equal(t, uint8(2), *opts.OutlineLevelRow)
+	assert.Equal(t, uint8(1), *opts.OutlineLevelCol)
+	sheetPr, ok := f.Pkg.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(sheetPr.([]byte)), `<tabColor theme="0" tint="-0.25"></tabColor>`)
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamSetDefinedName(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A", "B", "C"}))
+	assert.NoError(t, streamWriter.SetDefinedName(&DefinedName{
+		Name:     "Amount",
+		RefersTo: "Sheet1!$A$1:$C$1",
+	}))
+	assert.NoError(t, streamWriter.SetDefinedName(&DefinedName{
+		Name:     "_xlnm.Print_Titles",
+		RefersTo: "Sheet1!$1:$1",
+		Scope:    "Sheet1",
+	}))
+	assert.NoError(t, streamWriter.SetPrintArea("C10:A1"))
+	// Test replace the existing print area
+	assert.NoError(t, streamWriter.SetPrintArea("A1:D20"))
+	assert.NoError(t, streamWriter.Flush())
+	assert.Equal(t, []DefinedName{
+		{Name: "Amount", RefersTo: "Sheet1!$A$1:$C$1", Scope: "Workbook"},
+		{Name: "_xlnm.Print_Titles", RefersTo: "Sheet1!$1:$1", Scope: "Sheet1"},
+		{Name: "_xlnm.Print_Area", RefersTo: "'Sheet1'!$A$1:$D$20", Scope: "Sheet1"},
+	}, file.GetDefinedName())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamSetDefinedName.xlsx")))
+	// Test set defined name with invalid parameters
+	assert.EqualError(t, streamWriter.SetDefinedName(nil), ErrParameterInvalid.Error())
+	assert.EqualError(t, streamWriter.SetDefinedName(&DefinedName{Name: "Amount"}), ErrParameterInvalid.Error())
+	// Test set print area with illegal cell reference
+	assert.EqualError(t, streamWriter.SetPrintArea("A:B1"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test set print area with unsupported charset workbook
+	file.WorkBook = nil
+	file.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, streamWriter.SetPrintArea("A1:B1"), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestStreamWriterOptions(t *testing.T) {
+	row := make([]interface{}, 50)
+	for colID := 0; colID < 50; colID++ {
+		row[colID] = colID
+	}
+	for _, c := range []struct {
+		opts    StreamWriterOptions
+		tempDir string
+		temp    bool
+	}{
+		{opts: StreamWriterOptions{BufferSize: 1 << 10}, tempDir: os.TempDir(), temp: true},
+		{opts: StreamWriterOptions{BufferSize: 1 << 10, TempDir: "test"}, tempDir: "test", temp: true},
+		{opts: StreamWriterOptions{BufferSize: 1 << 10, DisableTempFile: true}},
+		{opts: StreamWriterOptions{}},
+	} {
+		file := NewFile()
+		streamWriter, err := file.NewStreamWriter("Sheet1", c.opts)
+		assert.NoError(t, err)
+		for rowID := 1; rowID <= 100; rowID++ {
+			cell, _ := CoordinatesToCellName(1, rowID)
+			assert.NoError(t, streamWriter.SetRow(cell, row))
+		}
+		assert.NoError(t, streamWriter.Flush())
+		assert.Equal(t, c.temp, streamWriter.rawData.tmp != nil)
+		if c.temp {
+			assert.Equal(t, filepath.Clean(c.tempDir), filepath.Dir(streamWriter.rawData.tmp.Name()))
+		}
+		assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamWriterOptions.xlsx")))
+		assert.NoError(t, file.Close())
+
+		file, err = OpenFile(filepath.Join("test", "TestStreamWriterOptions.xlsx"))
+		assert.NoError(t, err)
+		cellValue, err := file.GetCellValue("Sheet1", "AX100")
+		assert.NoError(t, err)
+		assert.Equal(t, "49", cellValue)
+		assert.NoError(t, file.Close())
+	}
+	// Test create stream writer with invalid buffer size
+	file := NewFile()
+	_, err := file.NewStreamWriter("Sheet1", StreamWriterOptions{BufferSize: -1})
+	assert.ErrorIs(t, err, ErrStreamBufferSize)
+	assert.NoError(t, file.Close())
+}
+
+func TestStreamAutoWidth(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1", StreamWriterOptions{AutoWidth: true})
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"ID", "Description", "中文字符", "Remark", strings.Repeat("c", 400)}))
+	assert.NoError(t, streamWriter.SetRow("A2", []interface{}{1, "Short\nA much longer line", Cell{Value: "Value"}, nil, true}))
+	assert.NoError(t, streamWriter.SetRow("A3", []interface{}{
+		123456789012.5,
+		[]RichTextRun{{Text: "Rich "}, {Text: "Text"}},
+		time.Date(2023, 1, 2, 15, 4, 0, 0, time.UTC),
+	}))
+	// Test the column width set by SetColWidth take precedence
+	assert.NoError(t, streamWriter.SetColWidth(4, 4, 30))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamAutoWidth.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamAutoWidth.xlsx"))
+	assert.NoError(t, err)
+	for col, expected := range map[string]float64{
+		"A": 15.5, "B": 17.02, "C": 12.51, "D": 30, "E": MaxColumnWidth,
+	} {
+		width, err := f.GetColWidth("Sheet1", col)
+		assert.NoError(t, err)
+		assert.InDelta(t, expected, width, 1e-9, col)
+	}
+	assert.NoError(t, f.Close())
+
+	metrics := builtInFontMetrics["calibri"]
+	assert.Equal(t, 0.0, cellValueWidth(metrics, nil))
+	assert.Equal(t, 3.77, cellValueWidth(metrics, []byte("Data")))
+	assert.Equal(t, 4.75, cellValueWidth(metrics, false))
+	assert.Equal(t, 3.5, cellValueWidth(metrics, float32(12.5)))
+	assert.Equal(t, 3.95, cellValueWidth(metrics, "ＡＢ"))
+	assert.Equal(t, 3.34, cellValueWidth(builtInFontMetrics["courier new"], "ＡＢ"))
+
+	// Test auto width with the registered font metrics of the default font
+	assert.NoError(t, file.Close())
+	file = NewFile()
+	assert.NoError(t, file.SetDefaultFont("Go Mono"))
+	assert.NoError(t, file.RegisterFontMetrics("Go Mono", gomono.TTF))
+	streamWriter, err = file.NewStreamWriter("Sheet1", StreamWriterOptions{AutoWidth: true})
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"iiiiiiiiii", "MMMMMMMMMM"}))
+	assert.NoError(t, streamWriter.Flush())
+	for _, col := range []string{"A", "B"} {
+		width, err := file.GetColWidth("Sheet1", col)
+		assert.NoError(t, err)
+		assert.Equal(t, 12.0, width, col)
+	}
+}
+
+func TestStreamProtectSheet(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A", "B", "C"}))
+	assert.NoError(t, streamWriter.ProtectSheet(&SheetProtectionOptions{
+		AlgorithmName:     "SHA-512",
+		Password:          "password",
+		SelectLockedCells: true,
+	}))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamProtectSheet.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamProtectSheet.xlsx"))
+	assert.NoError(t, err)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, ws.SheetProtection.Sheet)
+	assert.False(t, ws.SheetProtection.SelectLockedCells)
+	assert.Equal(t, "SHA-512", ws.SheetProtection.AlgorithmName)
+	assert.NoError(t, f.UnprotectSheet("Sheet1", "password"))
+	assert.NoError(t, f.Close())
+	// Test protect sheet with invalid parameters
+	assert.EqualError(t, streamWriter.ProtectSheet(nil), ErrParameterInvalid.Error())
+}
+
+func TestStreamSharedStrings(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Exists"))
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	sw, err := f.NewStreamWriter("Sheet2", StreamWriterOptions{SharedStrings: true, SharedStringsCacheSize: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Name", []byte("Value"), "Exists", "", 1}))
+	for row := 2; row <= 11; row++ {
+		cell, _ := CoordinatesToCellName(1, row)
+		assert.NoError(t, sw.SetRow(cell, []interface{}{"Name", "Value", Cell{Formula: "B1", Value: "Formula"}, 2}))
+	}
+	assert.NoError(t, sw.SetRow("A12", []interface{}{"Value", "Name", "Exists"}))
+	// Test add table with the header in the shared string table
+	assert.NoError(t, sw.AddTable("A1:B12", nil))
+	assert.NoError(t, sw.Flush())
+	// Test the repeated strings are stored only once until evicted from cache
+	assert.Equal(t, []string{"Exists", "Name", "Value", "Name", "Value"}, func() (values []string) {
+		for _, si := range f.SharedStrings.SI {
+			values = append(values, si.String())
+		}
+		return
+	}())
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name", "Value", "Exists", "", "1"}, rows[0])
+	assert.Equal(t, []string{"Name", "Value", "Formula", "2"}, rows[1])
+	assert.Equal(t, []string{"Value", "Name", "Exists"}, rows[11])
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStreamSharedStrings.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestStreamSharedStrings.xlsx"))
+	assert.NoError(t, err)
+	cellValue, err := f.GetCellValue("Sheet2", "B12")
+	assert.NoError(t, err)
+	assert.Equal(t, "Name", cellValue)
+	assert.NoError(t, f.Close())
+	// Test create stream writer with invalid shared strings cache size
+	f = NewFile()
+	_, err = f.NewStreamWriter("Sheet1", StreamWriterOptions{SharedStringsCacheSize: -1})
+	assert.Equal(t, ErrStreamSharedStringsCacheSize, err)
+	// Test set row with unsupported charset shared strings table
+	sw, err = f.NewStreamWriter("Sheet1", StreamWriterOptions{SharedStrings: true})
+	assert.NoError(t, err)
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, sw.SetRow("A1", []interface{}{"Data"}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamRemapStyles(t *testing.T) {
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1", StreamWriterOptions{BufferSize: 1 << 10})
+	assert.NoError(t, err)
+	for rowID := 1; rowID <= 100; rowID++ {
+		cell, _ := CoordinatesToCellName(1, rowID)
+		assert.NoError(t, sw.SetRow(cell, []interface{}{Cell{StyleID: 1, Value: rowID}, "s=\"1\""}, RowOpts{StyleID: 2}))
+	}
+	assert.NoError(t, sw.Flush())
+	assert.NotNil(t, sw.rawData.tmp)
+	assert.NoError(t, sw.remapStyles(func(styleID int) int { return styleID + 10 }))
+	assert.NotNil(t, sw.rawData.tmp)
+	r, err := sw.reader()
+	assert.NoError(t, err)
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `<c r="A100" s="11"`)
+	assert.Equal(t, 100, strings.Count(string(data), ` s="12" customFormat="1"`))
+	assert.Equal(t, 100, strings.Count(string(data), `s=&#34;1&#34;`))
+	assert.NoError(t, f.Close())
+	// Test remap styles with the reader and writer returns error
+	assert.Equal(t, ErrParameterInvalid, remapStreamStyles(iotest.ErrReader(ErrParameterInvalid), io.Discard, func(styleID int) int { return styleID }))
+	file, err := os.CreateTemp("test", "excelize-")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+	assert.ErrorIs(t, remapStreamStyles(strings.NewReader(`<c s="1">`), file, func(styleID int) int { return styleID }), os.ErrClosed)
+	assert.NoError(t, os.Remove(file.Name()))
+}
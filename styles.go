@@ -13,13 +13,16 @@ package excel
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"math"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Excel styles can reference number formats that are built-in, all of which
@@ -55,7 +58,7 @@ var builtInNumFmt = map[int]string{
 	44: `_("$"* #,##0.00_);_("$"* \(#,##0.00\);_("$"* "-"??_);_(@_)`,
 	45: "mm:ss",
 	46: "[h]:mm:ss",
-	47: "mmss.0",
+	47: "mm:ss.0",
 	48: "##0.0e+0",
 	49: "@",
 }
@@ -806,9 +809,24 @@ var validType = map[string]string{
 	"2_color_scale": "2_color_scale",
 	"3_color_scale": "3_color_scale",
 	"data_bar":      "dataBar",
+	"icon_set":      "iconSet",
 	"formula":       "expression",
 }
 
+// iconSetStyles defined the list of valid icon set styles of the icon set
+// conditional formatting rule.
+var iconSetStyles = []string{
+	"3Arrows", "3ArrowsGray", "3Flags", "3TrafficLights1", "3TrafficLights2",
+	"3Signs", "3Symbols", "3Symbols2", "4Arrows", "4ArrowsGray", "4RedToBlack",
+	"4Rating", "4TrafficLights", "5Arrows", "5ArrowsGray", "5Rating", "5Quarters",
+}
+
+// condFmtTypesWithoutCriteria defined the list of conditional formatting
+// rule types which don't require the criteria.
+var condFmtTypesWithoutCriteria = []string{
+	"aboveAverage", "duplicateValues", "uniqueValues", "top10", "iconSet", "expression",
+}
+
 // criteriaType defined the list of valid criteria types.
 var criteriaType = map[string]string{
 	"between":                  "between",
@@ -867,6 +885,149 @@ var operatorType = map[string]string{
 	"greaterThanOrEqual": "greater than or equal to",
 }
 
+// styleFillPatterns defined the list of valid pattern types of the fill in
+// the order of the 'Fill.Pattern' index.
+var styleFillPatterns = []string{
+	"none",
+	"solid",
+	"mediumGray",
+	"darkGray",
+	"lightGray",
+	"darkHorizontal",
+	"darkVertical",
+	"darkDown",
+	"darkUp",
+	"darkGrid",
+	"darkTrellis",
+	"lightHorizontal",
+	"lightVertical",
+	"lightDown",
+	"lightUp",
+	"lightGrid",
+	"lightTrellis",
+	"gray125",
+	"gray0625",
+}
+
+// styleFillVariants defined the list of the gradient degrees of the fill in
+// the order of the 'Fill.Shading' index.
+var styleFillVariants = []float64{90, 0, 45, 135}
+
+// styleBorders defined the list of valid border styles in the order of the
+// 'Border.Style' index.
+var styleBorders = []string{
+	"none",
+	"thin",
+	"medium",
+	"dashed",
+	"dotted",
+	"thick",
+	"double",
+	"hair",
+	"mediumDashed",
+	"dashDot",
+	"mediumDashDot",
+	"dashDotDot",
+	"mediumDashDotDot",
+	"slantDashDot",
+}
+
+// BorderStyle is the type of the border style index, the value of the border
+// style can be used as the 'Border.Style' by converting it to an int. Since
+// the 'Border.Style' is an int field, the invalid border style index will not
+// be checked at compile time, and it will be ignored when creating the style.
+type BorderStyle int
+
+// This section defines the currently supported border styles, these untyped
+// constants can be used as both 'Border.Style' and BorderStyle.
+const (
+	BorderStyleNone = iota
+	BorderStyleThin
+	BorderStyleMedium
+	BorderStyleDashed
+	BorderStyleDotted
+	BorderStyleThick
+	BorderStyleDouble
+	BorderStyleHair
+	BorderStyleMediumDashed
+	BorderStyleDashDot
+	BorderStyleMediumDashDot
+	BorderStyleDashDotDot
+	BorderStyleMediumDashDotDot
+	BorderStyleSlantDashDot
+)
+
+// String returns the name of the border style, returns empty string if the
+// border style index is invalid.
+func (s BorderStyle) String() string {
+	if s >= 0 && int(s) < len(styleBorders) {
+		return styleBorders[s]
+	}
+	return ""
+}
+
+// ParseBorderStyle provides a function to parse the border style by given
+// border style name, for example, parse the medium dashed border style:
+//
+//	borderStyle, err := excelize.ParseBorderStyle("mediumDashed")
+func ParseBorderStyle(name string) (BorderStyle, error) {
+	if idx := inStrSlice(styleBorders, name, true); idx != -1 {
+		return BorderStyle(idx), nil
+	}
+	return BorderStyleNone, newInvalidOptionalValue("Style", name, styleBorders)
+}
+
+// UnderlineStyle is the type of the font underline style, the value of the
+// underline style can be used as the 'Font.Underline' by converting it to a
+// string. The 'Font.Underline' accepts any string, use ParseUnderlineStyle to
+// check the underline style name in advance.
+type UnderlineStyle string
+
+// This section defines the currently supported font underline styles. The
+// single and double underline styles are used in cells, and the others are
+// used in the drawing markup language of shapes and charts. These untyped
+// constants can be used as both 'Font.Underline' and UnderlineStyle.
+const (
+	UnderlineNone            = "none"
+	UnderlineSingle          = "single"
+	UnderlineDouble          = "double"
+	UnderlineWords           = "words"
+	UnderlineSng             = "sng"
+	UnderlineDbl             = "dbl"
+	UnderlineHeavy           = "heavy"
+	UnderlineDotted          = "dotted"
+	UnderlineDottedHeavy     = "dottedHeavy"
+	UnderlineDash            = "dash"
+	UnderlineDashHeavy       = "dashHeavy"
+	UnderlineDashLong        = "dashLong"
+	UnderlineDashLongHeavy   = "dashLongHeavy"
+	UnderlineDotDash         = "dotDash"
+	UnderlineDotDashHeavy    = "dotDashHeavy"
+	UnderlineDotDotDash      = "dotDotDash"
+	UnderlineDotDotDashHeavy = "dotDotDashHeavy"
+	UnderlineWavy            = "wavy"
+	UnderlineWavyHeavy       = "wavyHeavy"
+	UnderlineWavyDbl         = "wavyDbl"
+)
+
+// String returns the name of the font underline style.
+func (u UnderlineStyle) String() string {
+	return string(u)
+}
+
+// ParseUnderlineStyle provides a function to parse the font underline style
+// by given underline style name, for example, parse the double underline
+// style:
+//
+//	underline, err := excelize.ParseUnderlineStyle("double")
+func ParseUnderlineStyle(name string) (UnderlineStyle, error) {
+	if inStrSlice(supportedUnderlineTypes, name, true) != -1 ||
+		inStrSlice(supportedDrawingUnderlineTypes, name, true) != -1 {
+		return UnderlineStyle(name), nil
+	}
+	return UnderlineStyle(name), newInvalidOptionalValue("Underline", name, append(supportedUnderlineTypes, supportedDrawingUnderlineTypes[1:]...))
+}
+
 // printCommaSep format number with thousands separator.
 func printCommaSep(text string) string {
 	var (
@@ -1986,10 +2147,9 @@ func parseFormatStyleSet(style *Style) (*Style, error) {
 // Cell Sheet1!A6 in the Excel Application: martes, 04 de Julio de 2017
 func (f *File) NewStyle(style *Style) (int, error) {
 	var (
-		fs                                  *Style
-		font                                *xlsxFont
-		err                                 error
-		cellXfsID, fontID, borderID, fillID int
+		fs        *Style
+		err       error
+		cellXfsID int
 	)
 	if style == nil {
 		return cellXfsID, err
@@ -2011,7 +2171,21 @@ func (f *File) NewStyle(style *Style) (int, error) {
 	if cellXfsID, err = f.getStyleID(s, fs); err != nil || cellXfsID != -1 {
 		return cellXfsID, err
 	}
-	
+	xf := f.newXf(s, fs)
+	xf.XfID = intPtr(0)
+	s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+	s.CellXfs.Count = len(s.CellXfs.Xf)
+	return s.CellXfs.Count - 1, nil
+}
+
+// newXf provides a function to create the formatting record by given style
+// settings, the number format, font, fill and border of the style will be
+// added into the style sheet if they don't exist.
+func (f *File) newXf(s *xlsxStyleSheet, fs *Style) xlsxXf {
+	var (
+		font                     *xlsxFont
+		fontID, borderID, fillID int
+	)
 	numFmtID := newNumFmt(s, fs)
 	
 	if fs.Font != nil {
@@ -2047,8 +2221,7 @@ func (f *File) NewStyle(style *Style) (int, error) {
 	
 	applyAlignment, alignment := fs.Alignment != nil, newAlignment(fs)
 	applyProtection, protection := fs.Protection != nil, newProtection(fs)
-	cellXfsID = setCellXfs(s, fontID, numFmtID, fillID, borderID, applyAlignment, applyProtection, alignment, protection)
-	return cellXfsID, nil
+	return newCellXf(fontID, numFmtID, fillID, borderID, applyAlignment, applyProtection, alignment, protection)
 }
 
 var getXfIDFuncs = map[string]func(int, xlsxXf, *Style) bool{
@@ -2125,232 +2298,1252 @@ func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (int, error) {
 	return styleID, err
 }
 
-// NewConditionalStyle provides a function to create style for conditional
-// format by given style format. The parameters are the same with the NewStyle
-// function.
-func (f *File) NewConditionalStyle(style *Style) (int, error) {
+// GetStyle provides a function to get the style definition by given style
+// index, the style definition could be used to create a new style based on
+// the existing style. This function is concurrency safe. Note that the fill
+// and border colors of the theme colors will be resolved to the RGB colors
+// by the color scheme of the theme. For example, get the style definition of
+// the cell Sheet1!A1 and create a new style with the bold font based on it:
+//
+//	styleID, err := f.GetCellStyle("Sheet1", "A1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	style, err := f.GetStyle(styleID)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if style.Font == nil {
+//	    style.Font = &excelize.Font{}
+//	}
+//	style.Font.Bold = true
+//	styleID, err = f.NewStyle(style)
+func (f *File) GetStyle(styleID int) (*Style, error) {
 	s, err := f.stylesReader()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	fs, err := parseFormatStyleSet(style)
-	if err != nil {
-		return 0, err
+	s.Lock()
+	defer s.Unlock()
+	if styleID < 0 || s.CellXfs == nil || len(s.CellXfs.Xf) <= styleID {
+		return nil, newInvalidStyleID(styleID)
 	}
-	dxf := dxf{
-		Fill: newFills(fs, false),
+	return f.extractStyle(s.CellXfs.Xf[styleID], s), err
+}
+
+// extractStyle provides a function to extract the style definition by given
+// formatting record and style sheet.
+func (f *File) extractStyle(xf xlsxXf, s *xlsxStyleSheet) *Style {
+	style := &Style{}
+	if extractStyleCondFuncs["fill"](xf, s) {
+		f.extractFills(s.Fills.Fill[*xf.FillID], style)
 	}
-	if fs.Alignment != nil {
-		dxf.Alignment = newAlignment(fs)
+	if extractStyleCondFuncs["border"](xf, s) {
+		f.extractBorders(s.Borders.Border[*xf.BorderID], style)
 	}
-	if len(fs.Border) > 0 {
-		dxf.Border = newBorders(fs)
+	if extractStyleCondFuncs["font"](xf, s) {
+		extractFont(s.Fonts.Font[*xf.FontID], style)
 	}
-	if fs.Font != nil {
-		dxf.Font, _ = f.newFont(fs)
+	if extractStyleCondFuncs["alignment"](xf, s) {
+		extractAlignment(xf.Alignment, style)
 	}
-	dxfStr, _ := xml.Marshal(dxf)
-	if s.Dxfs == nil {
-		s.Dxfs = &xlsxDxfs{}
+	if extractStyleCondFuncs["protection"](xf, s) {
+		extractProtection(xf.Protection, style)
 	}
-	s.Dxfs.Count++
-	s.Dxfs.Dxfs = append(s.Dxfs.Dxfs, &xlsxDxf{
-		Dxf: string(dxfStr[5 : len(dxfStr)-6]),
-	})
-	return s.Dxfs.Count - 1, nil
+	extractNumFmt(xf.NumFmtID, s, style)
+	return style
 }
 
-// GetDefaultFont provides the default font name currently set in the
-// workbook. The spreadsheet generated by excelize default font is Calibri.
-func (f *File) GetDefaultFont() (string, error) {
-	font, err := f.readDefaultFont()
-	if err != nil {
-		return "", err
-	}
-	return *font.Name.Val, err
+// extractStyleCondFuncs provides functions to check whether the style
+// settings should be extracted from the cell formats by given cell format
+// record and style sheet. The default font, fill and border with index 0
+// will not be extracted.
+var extractStyleCondFuncs = map[string]func(xlsxXf, *xlsxStyleSheet) bool{
+	"fill": func(xf xlsxXf, s *xlsxStyleSheet) bool {
+		return (xf.ApplyFill == nil || *xf.ApplyFill) && xf.FillID != nil &&
+			s.Fills != nil && *xf.FillID > 0 && *xf.FillID < len(s.Fills.Fill)
+	},
+	"border": func(xf xlsxXf, s *xlsxStyleSheet) bool {
+		return (xf.ApplyBorder == nil || *xf.ApplyBorder) && xf.BorderID != nil &&
+			s.Borders != nil && *xf.BorderID > 0 && *xf.BorderID < len(s.Borders.Border)
+	},
+	"font": func(xf xlsxXf, s *xlsxStyleSheet) bool {
+		return (xf.ApplyFont == nil || *xf.ApplyFont) && xf.FontID != nil &&
+			s.Fonts != nil && *xf.FontID > 0 && *xf.FontID < len(s.Fonts.Font)
+	},
+	"alignment": func(xf xlsxXf, s *xlsxStyleSheet) bool {
+		return (xf.ApplyAlignment == nil || *xf.ApplyAlignment) && xf.Alignment != nil
+	},
+	"protection": func(xf xlsxXf, s *xlsxStyleSheet) bool {
+		return (xf.ApplyProtection == nil || *xf.ApplyProtection) && xf.Protection != nil
+	},
 }
 
-// SetDefaultFont changes the default font in the workbook.
-func (f *File) SetDefaultFont(fontName string) error {
-	font, err := f.readDefaultFont()
-	if err != nil {
-		return err
+// getStyleColor provides a function to get the hex RGB color code with the
+// "#" prefix by given color settings, returns empty string if the color is
+// not specified or can't be resolved by the color scheme of the theme.
+func (f *File) getStyleColor(clr *xlsxColor) string {
+	if clr == nil {
+		return ""
+	}
+	if clr.RGB != "" {
+		rgb := strings.ToUpper(clr.RGB)
+		if len(rgb) == 8 {
+			rgb = rgb[2:]
+		}
+		return "#" + rgb
 	}
-	font.Name.Val = stringPtr(fontName)
-	s, _ := f.stylesReader()
-	s.Fonts.Font[0] = font
-	custom := true
-	s.CellStyles.CellStyle[0].CustomBuiltIn = &custom
-	return err
-}
-
-// readDefaultFont provides an un-marshalled font value.
-func (f *File) readDefaultFont() (*xlsxFont, error) {
-	s, err := f.stylesReader()
-	if err != nil {
-		return nil, err
+	if color := f.getThemeColor(clr); color != "" {
+		return "#" + color
 	}
-	return s.Fonts.Font[0], err
+	return ""
 }
 
-// getFontID provides a function to get font ID.
-// If given font does not exist, will return -1.
-func (f *File) getFontID(styleSheet *xlsxStyleSheet, style *Style) (int, error) {
-	var err error
-	fontID := -1
-	if styleSheet.Fonts == nil || style.Font == nil {
-		return fontID, err
+// extractFills provides a function to extract the fill settings by given
+// fill record of the style sheet.
+func (f *File) extractFills(fill *xlsxFill, style *Style) {
+	if fill == nil {
+		return
 	}
-	for idx, fnt := range styleSheet.Fonts.Font {
-		font, err := f.newFont(style)
-		if err != nil {
-			return fontID, err
-		}
-		if reflect.DeepEqual(*fnt, *font) {
-			fontID = idx
-			return fontID, err
+	if fill.GradientFill != nil {
+		gradient := fill.GradientFill
+		style.Fill.Type = "gradient"
+		switch gradient.Type {
+		case "path":
+			style.Fill.Shading = 4
+			if gradient.Left == 0.5 && gradient.Right == 0.5 && gradient.Top == 0.5 && gradient.Bottom == 0.5 {
+				style.Fill.Shading = 5
+			}
+		default:
+			for idx, degree := range styleFillVariants {
+				if degree == gradient.Degree {
+					style.Fill.Shading = idx
+					break
+				}
+			}
 		}
-	}
-	return fontID, err
-}
-
-// newFontColor set font color by given styles.
-func newFontColor(font *Font) *xlsxColor {
-	var fontColor *xlsxColor
-	prepareFontColor := func() {
-		if fontColor != nil {
-			return
+		for _, stop := range gradient.Stop {
+			style.Fill.Color = append(style.Fill.Color, f.getStyleColor(&stop.Color))
 		}
-		fontColor = &xlsxColor{}
+		return
 	}
-	if font.Color != "" {
-		prepareFontColor()
-		fontColor.RGB = getPaletteColor(font.Color)
+	if fill.PatternFill == nil {
+		return
 	}
-	if font.ColorIndexed >= 0 && font.ColorIndexed <= len(IndexedColorMapping)+1 {
-		prepareFontColor()
-		fontColor.Indexed = font.ColorIndexed
+	idx := inStrSlice(styleFillPatterns, fill.PatternFill.PatternType, true)
+	if idx <= 0 {
+		return
 	}
-	if font.ColorTheme != nil {
-		prepareFontColor()
-		fontColor.Theme = font.ColorTheme
+	style.Fill.Type, style.Fill.Pattern = "pattern", idx
+	color := f.getStyleColor(fill.PatternFill.FgColor)
+	if color == "" {
+		color = f.getStyleColor(fill.PatternFill.BgColor)
 	}
-	if font.ColorTint != 0 {
-		prepareFontColor()
-		fontColor.Tint = font.ColorTint
+	if color != "" {
+		style.Fill.Color = []string{color}
 	}
-	return fontColor
 }
 
-// newFont provides a function to add font style by given cell format
-// settings.
-func (f *File) newFont(style *Style) (*xlsxFont, error) {
-	var err error
-	if style.Font.Size < MinFontSize {
-		style.Font.Size = 11
+// extractBorders provides a function to extract the border settings by given
+// border record of the style sheet.
+func (f *File) extractBorders(border *xlsxBorder, style *Style) {
+	if border == nil {
+		return
 	}
-	fnt := xlsxFont{
-		Sz:     &attrValFloat{Val: float64Ptr(style.Font.Size)},
-		Name:   &attrValString{Val: stringPtr(style.Font.Family)},
-		Family: &attrValInt{Val: intPtr(2)},
+	for _, line := range []struct {
+		typ  string
+		line xlsxLine
+		ok   bool
+	}{
+		{"left", border.Left, true},
+		{"right", border.Right, true},
+		{"top", border.Top, true},
+		{"bottom", border.Bottom, true},
+		{"diagonalUp", border.Diagonal, border.DiagonalUp},
+		{"diagonalDown", border.Diagonal, border.DiagonalDown},
+	} {
+		if idx := inStrSlice(styleBorders, line.line.Style, true); line.ok && idx > 0 {
+			style.Border = append(style.Border, Border{Type: line.typ, Color: f.getStyleColor(line.line.Color), Style: idx})
+		}
 	}
-	fnt.Color = newFontColor(style.Font)
-	if style.Font.Bold {
-		fnt.B = &attrValBool{Val: &style.Font.Bold}
+}
+
+// extractFont provides a function to extract the font settings by given font
+// record of the style sheet.
+func extractFont(fnt *xlsxFont, style *Style) {
+	if fnt == nil {
+		return
 	}
-	if style.Font.Italic {
-		fnt.I = &attrValBool{Val: &style.Font.Italic}
+	font := Font{}
+	if fnt.B != nil {
+		font.Bold = fnt.B.Val == nil || *fnt.B.Val
 	}
-	if *fnt.Name.Val == "" {
-		if *fnt.Name.Val, err = f.GetDefaultFont(); err != nil {
-			return &fnt, err
+	if fnt.I != nil {
+		font.Italic = fnt.I.Val == nil || *fnt.I.Val
+	}
+	if fnt.U != nil {
+		font.Underline = "single"
+		if fnt.U.Val != nil {
+			font.Underline = *fnt.U.Val
 		}
 	}
-	if style.Font.Strike {
-		fnt.Strike = &attrValBool{Val: &style.Font.Strike}
+	if fnt.Name != nil && fnt.Name.Val != nil {
+		font.Family = *fnt.Name.Val
 	}
-	if idx := inStrSlice(supportedUnderlineTypes, style.Font.Underline, true); idx != -1 {
-		fnt.U = &attrValString{Val: stringPtr(supportedUnderlineTypes[idx])}
+	if fnt.Sz != nil && fnt.Sz.Val != nil {
+		font.Size = *fnt.Sz.Val
 	}
-	return &fnt, err
-}
-
-// getNumFmtID provides a function to get number format code ID.
-// If given number format code does not exist, will return -1.
-func getNumFmtID(styleSheet *xlsxStyleSheet, style *Style) (numFmtID int) {
-	numFmtID = -1
-	if _, ok := builtInNumFmt[style.NumFmt]; ok {
-		return style.NumFmt
+	if fnt.Strike != nil {
+		font.Strike = fnt.Strike.Val == nil || *fnt.Strike.Val
 	}
-	for lang, numFmt := range langNumFmt {
-		if _, ok := numFmt[style.NumFmt]; ok && lang == style.Lang {
-			numFmtID = style.NumFmt
-			return
+	for val, attr := range map[*bool]*attrValBool{
+		&font.Condense: fnt.Condense, &font.Extend: fnt.Extend,
+		&font.Outline: fnt.Outline, &font.Shadow: fnt.Shadow,
+	} {
+		if attr != nil {
+			*val = attr.Val == nil || *attr.Val
 		}
 	}
-	if fmtCode, ok := currencyNumFmt[style.NumFmt]; ok {
-		numFmtID = style.NumFmt
-		if styleSheet.NumFmts != nil {
-			for _, numFmt := range styleSheet.NumFmts.NumFmt {
-				if numFmt.FormatCode == fmtCode {
-					numFmtID = numFmt.NumFmtID
-					return
-				}
-			}
+	if fnt.Charset != nil && fnt.Charset.Val != nil {
+		font.Charset = intPtr(*fnt.Charset.Val)
+	}
+	if fnt.Scheme != nil && fnt.Scheme.Val != nil {
+		font.Scheme = *fnt.Scheme.Val
+	}
+	if fnt.Color != nil {
+		if font.Color = strings.ToUpper(fnt.Color.RGB); len(font.Color) == 8 {
+			font.Color = font.Color[2:]
 		}
+		font.ColorIndexed = fnt.Color.Indexed
+		font.ColorTheme = fnt.Color.Theme
+		font.ColorTint = fnt.Color.Tint
 	}
-	return
+	style.Font = &font
 }
 
-// newNumFmt provides a function to check if number format code in the range
-// of built-in values.
-func newNumFmt(styleSheet *xlsxStyleSheet, style *Style) int {
-	dp := "0."
-	numFmtID := 164 // Default custom number format code from 164.
-	if style.DecimalPlaces < 0 || style.DecimalPlaces > 30 {
-		style.DecimalPlaces = 2
+// extractAlignment provides a function to extract the alignment settings by
+// given alignment of the cell format record.
+func extractAlignment(alignment *xlsxAlignment, style *Style) {
+	style.Alignment = &Alignment{
+		Horizontal:      alignment.Horizontal,
+		Indent:          alignment.Indent,
+		JustifyLastLine: alignment.JustifyLastLine,
+		ReadingOrder:    alignment.ReadingOrder,
+		RelativeIndent:  alignment.RelativeIndent,
+		ShrinkToFit:     alignment.ShrinkToFit,
+		TextRotation:    alignment.TextRotation,
+		Vertical:        alignment.Vertical,
+		WrapText:        alignment.WrapText,
 	}
-	for i := 0; i < style.DecimalPlaces; i++ {
-		dp += "0"
+}
+
+// extractProtection provides a function to extract the protection settings by
+// given protection of the cell format record.
+func extractProtection(protection *xlsxProtection, style *Style) {
+	style.Protection = &Protection{Locked: true}
+	if protection.Hidden != nil {
+		style.Protection.Hidden = *protection.Hidden
 	}
-	if style.CustomNumFmt != nil {
-		if customNumFmtID := getCustomNumFmtID(styleSheet, style); customNumFmtID != -1 {
-			return customNumFmtID
-		}
-		return setCustomNumFmt(styleSheet, style)
+	if protection.Locked != nil {
+		style.Protection.Locked = *protection.Locked
 	}
-	_, ok := builtInNumFmt[style.NumFmt]
-	if !ok {
-		fc, currency := currencyNumFmt[style.NumFmt]
-		if !currency {
-			return setLangNumFmt(styleSheet, style)
-		}
-		fc = strings.ReplaceAll(fc, "0.00", dp)
-		if style.NegRed {
-			fc = fc + ";[Red]" + fc
-		}
-		if styleSheet.NumFmts != nil {
-			numFmtID = styleSheet.NumFmts.NumFmt[len(styleSheet.NumFmts.NumFmt)-1].NumFmtID + 1
-			nf := xlsxNumFmt{
-				FormatCode: fc,
-				NumFmtID:   numFmtID,
-			}
-			styleSheet.NumFmts.NumFmt = append(styleSheet.NumFmts.NumFmt, &nf)
-			styleSheet.NumFmts.Count++
-		} else {
-			nf := xlsxNumFmt{
-				FormatCode: fc,
-				NumFmtID:   numFmtID,
-			}
-			numFmts := xlsxNumFmts{
-				NumFmt: []*xlsxNumFmt{&nf},
-				Count:  1,
+}
+
+// extractNumFmt provides a function to extract the number format settings by
+// given number format ID of the cell format record. The built-in number
+// format will be extracted as the 'NumFmt' field, and the custom number
+// format will be extracted as the 'CustomNumFmt' field.
+func extractNumFmt(numFmtID *int, s *xlsxStyleSheet, style *Style) {
+	if numFmtID == nil {
+		return
+	}
+	if _, ok := builtInNumFmt[*numFmtID]; ok {
+		style.NumFmt = *numFmtID
+		return
+	}
+	if s.NumFmts != nil {
+		for _, numFmt := range s.NumFmts.NumFmt {
+			if numFmt.NumFmtID == *numFmtID {
+				style.CustomNumFmt = stringPtr(numFmt.FormatCode)
+				return
 			}
-			styleSheet.NumFmts = &numFmts
 		}
-		return numFmtID
 	}
-	return style.NumFmt
+}
+
+// builtInCellStyles defined the built-in cell style IDs of the named cell
+// styles in the spreadsheet application.
+var builtInCellStyles = map[string]int{
+	"Normal": 0, "Comma": 3, "Currency": 4, "Percent": 5, "Comma [0]": 6,
+	"Currency [0]": 7, "Hyperlink": 8, "Followed Hyperlink": 9, "Note": 10,
+	"Warning Text": 11, "Title": 15, "Heading 1": 16, "Heading 2": 17,
+	"Heading 3": 18, "Heading 4": 19, "Input": 20, "Output": 21,
+	"Calculation": 22, "Check Cell": 23, "Linked Cell": 24, "Total": 25,
+	"Good": 26, "Bad": 27, "Neutral": 28, "Accent1": 29,
+	"20% - Accent1": 30, "40% - Accent1": 31, "60% - Accent1": 32,
+	"Accent2": 33, "20% - Accent2": 34, "40% - Accent2": 35,
+	"60% - Accent2": 36, "Accent3": 37, "20% - Accent3": 38,
+	"40% - Accent3": 39, "60% - Accent3": 40, "Accent4": 41,
+	"20% - Accent4": 42, "40% - Accent4": 43, "60% - Accent4": 44,
+	"Accent5": 45, "20% - Accent5": 46, "40% - Accent5": 47,
+	"60% - Accent5": 48, "Accent6": 49, "20% - Accent6": 50,
+	"40% - Accent6": 51, "60% - Accent6": 52, "Explanatory Text": 53,
+}
+
+// NewNamedStyle provides a function to create the named cell style by given
+// style name and style settings, the named cell style will be listed in the
+// cell styles gallery of the spreadsheet application. The name of the
+// built-in cell styles, such as "Heading 1", "Good" and "Bad", will create
+// the customized built-in cell style, and the other names will create the
+// custom cell style. The style names are case-insensitive and should be
+// unique in the workbook. For example, create the built-in "Good" cell style
+// and a custom cell style, and apply them to the cells:
+//
+//	err := f.NewNamedStyle("Good", &excelize.Style{
+//	    Font: &excelize.Font{Color: "006100"},
+//	    Fill: excelize.Fill{Type: "pattern", Color: []string{"C6EFCE"}, Pattern: 1},
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err = f.NewNamedStyle("Corporate", &excelize.Style{
+//	    Font: &excelize.Font{Bold: true, Family: "Arial", Color: "1F3864"},
+//	}); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err = f.SetCellNamedStyle("Sheet1", "A1", "A1", "Good"); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.SetCellNamedStyle("Sheet1", "B1", "D1", "Corporate")
+func (f *File) NewNamedStyle(name string, style *Style) error {
+	if name == "" {
+		return ErrParameterRequired
+	}
+	if utf8.RuneCountInString(name) > MaxFieldLength {
+		return newFieldLengthError("name")
+	}
+	if style == nil {
+		style = &Style{}
+	}
+	fs, err := parseFormatStyleSet(style)
+	if err != nil {
+		return err
+	}
+	if fs.DecimalPlaces == 0 {
+		fs.DecimalPlaces = 2
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.getCellStyle(name) != nil {
+		return ErrExistsNamedStyle
+	}
+	if s.CellStyleXfs == nil {
+		s.CellStyleXfs = &xlsxCellStyleXfs{}
+	}
+	if s.CellStyles == nil {
+		s.CellStyles = &xlsxCellStyles{}
+	}
+	s.CellStyleXfs.Xf = append(s.CellStyleXfs.Xf, f.newXf(s, fs))
+	s.CellStyleXfs.Count = len(s.CellStyleXfs.Xf)
+	cellStyle := &xlsxCellStyle{Name: name, XfID: s.CellStyleXfs.Count - 1}
+	if builtInID, ok := builtInCellStyles[name]; ok {
+		cellStyle.BuiltInID, cellStyle.CustomBuiltIn = intPtr(builtInID), boolPtr(true)
+	}
+	s.CellStyles.CellStyle = append(s.CellStyles.CellStyle, cellStyle)
+	s.CellStyles.Count = len(s.CellStyles.CellStyle)
+	return err
+}
+
+// SetCellNamedStyle provides a function to apply the named cell style for the
+// cells in the given range by given worksheet name, range reference and style
+// name. The cells will use the formatting of the named cell style, and the
+// named cell style will be shown as the style of the cells in the cell styles
+// gallery of the spreadsheet application. For example, apply the built-in
+// "Normal" cell style for the cells in range A1:D10 on Sheet1:
+//
+//	err := f.SetCellNamedStyle("Sheet1", "A1", "D10", "Normal")
+func (f *File) SetCellNamedStyle(sheet, hCell, vCell, name string) error {
+	styleID, err := f.getNamedStyleID(name)
+	if err != nil {
+		return err
+	}
+	return f.SetCellStyle(sheet, hCell, vCell, styleID)
+}
+
+// GetNamedStyles provides a function to get the named cell styles of the
+// workbook in the order of the cell styles definitions. Note that the fill
+// and border colors of the theme colors will be resolved to the RGB colors
+// by the color scheme of the theme. For example, list the names of the
+// custom cell styles:
+//
+//	styles, err := f.GetNamedStyles()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, style := range styles {
+//	    if !style.BuiltIn {
+//	        fmt.Println(style.Name)
+//	    }
+//	}
+func (f *File) GetNamedStyles() ([]NamedStyle, error) {
+	var styles []NamedStyle
+	s, err := f.stylesReader()
+	if err != nil {
+		return styles, err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.CellStyles == nil {
+		return styles, err
+	}
+	for _, cellStyle := range s.CellStyles.CellStyle {
+		style := NamedStyle{Name: cellStyle.Name, BuiltIn: cellStyle.BuiltInID != nil, Style: &Style{}}
+		if xf, ok := s.getCellStyleXf(cellStyle); ok {
+			style.Style = f.extractStyle(xf, s)
+		}
+		styles = append(styles, style)
+	}
+	return styles, err
+}
+
+// styleTableVersion defined the current version of the JSON form of the style
+// table.
+const styleTableVersion = 1
+
+// ExportStyles provides a function to export the complete style table of the
+// workbook to the given writer in the indented JSON form of the StyleTable,
+// which includes the default font, the cell styles, the named cell styles
+// and the conditional format styles, so that the style table can be shared
+// across workbooks and the changes of the styles can be reviewed by the
+// text diff tools. For example, export the styles of the workbook to the
+// file "styles.json":
+//
+//	file, err := os.Create("styles.json")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ExportStyles(file); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ExportStyles(w io.Writer) error {
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	table := StyleTable{Version: styleTableVersion, Styles: []*Style{}}
+	s.Lock()
+	if s.Fonts != nil && len(s.Fonts.Font) > 0 && s.Fonts.Font[0].Name != nil && s.Fonts.Font[0].Name.Val != nil {
+		table.DefaultFont = *s.Fonts.Font[0].Name.Val
+	}
+	if s.CellXfs != nil {
+		for _, xf := range s.CellXfs.Xf {
+			table.Styles = append(table.Styles, f.extractStyle(xf, s))
+		}
+	}
+	var dxfs int
+	if s.Dxfs != nil {
+		dxfs = len(s.Dxfs.Dxfs)
+	}
+	s.Unlock()
+	if table.NamedStyles, err = f.GetNamedStyles(); err != nil {
+		return err
+	}
+	for idx := 0; idx < dxfs; idx++ {
+		style, err := f.GetConditionalStyle(idx)
+		if err != nil {
+			return err
+		}
+		table.ConditionalStyles = append(table.ConditionalStyles, style)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(table)
+}
+
+// ImportStyles provides a function to import the style table from the given
+// reader in the JSON form exported by the ExportStyles function. The default
+// font will be set first, the cell styles and the conditional format styles
+// will be created in the order of the style table, and the existing cell
+// styles with the same settings will be reused. A copy of the existing cell
+// formatting record will be appended if a cell style of the style table
+// equals to the previous one, so the cell style indexes are the same as the
+// exported workbook when importing the style table into a new workbook which
+// styles have not been changed, otherwise the indexes of the imported cell
+// styles may be different. The named cell styles which already exist in the
+// workbook will be kept. For example, import the styles from the file
+// "styles.json":
+//
+//	file, err := os.Open("styles.json")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ImportStyles(file); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ImportStyles(r io.Reader) error {
+	var table StyleTable
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&table); err != nil {
+		return err
+	}
+	if table.Version != styleTableVersion {
+		return newUnsupportedStyleTableVersionError(table.Version)
+	}
+	if table.DefaultFont != "" {
+		if err := f.SetDefaultFont(table.DefaultFont); err != nil {
+			return err
+		}
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	for idx, style := range table.Styles {
+		if style == nil {
+			style = &Style{}
+		}
+		styleID, err := f.NewStyle(style)
+		if err != nil {
+			return err
+		}
+		if styleID < idx {
+			s.Lock()
+			if len(s.CellXfs.Xf) == idx {
+				s.CellXfs.Xf = append(s.CellXfs.Xf, cloneXf(s.CellXfs.Xf[styleID]))
+				s.CellXfs.Count = len(s.CellXfs.Xf)
+			}
+			s.Unlock()
+		}
+	}
+	for _, namedStyle := range table.NamedStyles {
+		if err := f.NewNamedStyle(namedStyle.Name, namedStyle.Style); err != nil && err != ErrExistsNamedStyle {
+			return err
+		}
+	}
+	for _, style := range table.ConditionalStyles {
+		if style == nil {
+			style = &Style{}
+		}
+		if _, err := f.NewConditionalStyle(style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getNamedStyleID provides a function to get the cell formatting record
+// index which based on the named cell style by given style name, the
+// formatting record will be created if it doesn't exist.
+func (f *File) getNamedStyleID(name string) (int, error) {
+	s, err := f.stylesReader()
+	if err != nil {
+		return 0, err
+	}
+	s.Lock()
+	defer s.Unlock()
+	cellStyle := s.getCellStyle(name)
+	if cellStyle == nil {
+		return 0, newNoExistNamedStyleError(name)
+	}
+	xf, ok := s.getCellStyleXf(cellStyle)
+	if !ok {
+		return 0, newNoExistNamedStyleError(name)
+	}
+	xf.XfID = intPtr(cellStyle.XfID)
+	if s.CellXfs == nil {
+		s.CellXfs = &xlsxCellXfs{}
+	}
+	for styleID, cellXf := range s.CellXfs.Xf {
+		if reflect.DeepEqual(cellXf, xf) {
+			return styleID, err
+		}
+	}
+	s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+	s.CellXfs.Count = len(s.CellXfs.Xf)
+	return s.CellXfs.Count - 1, err
+}
+
+// getCellStyle provides a function to get the named cell style by given
+// case-insensitive style name, returns nil if the cell style doesn't exist.
+func (s *xlsxStyleSheet) getCellStyle(name string) *xlsxCellStyle {
+	if s.CellStyles == nil {
+		return nil
+	}
+	for _, cellStyle := range s.CellStyles.CellStyle {
+		if strings.EqualFold(cellStyle.Name, name) {
+			return cellStyle
+		}
+	}
+	return nil
+}
+
+// getCellStyleXf provides a function to get the master formatting record of
+// the named cell style, returns false if the formatting record doesn't exist.
+func (s *xlsxStyleSheet) getCellStyleXf(cellStyle *xlsxCellStyle) (xlsxXf, bool) {
+	if s.CellStyleXfs == nil || cellStyle.XfID < 0 || cellStyle.XfID >= len(s.CellStyleXfs.Xf) {
+		return xlsxXf{}, false
+	}
+	return s.CellStyleXfs.Xf[cellStyle.XfID], true
+}
+
+// CompactStyles provides a function to remove the duplicate and unused cell
+// formats of the workbook. The identical fonts, fills, borders, custom number
+// formats and cell formats will be merged, the cell formats which not used by
+// any cells, rows or columns will be removed, and the fonts, fills, borders
+// and custom number formats which not used by any cell formats will be
+// removed. The style index of the cells, rows and columns in all worksheets,
+// including the worksheets generated by the stream writer, will be remapped.
+// Note that the style index returned by the NewStyle function before
+// compacting might be changed, get the style index of the cell by the
+// GetCellStyle function after compacting. For example:
+//
+//	if err := f.CompactStyles(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) CompactStyles() error {
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	var (
+		worksheets []*xlsxWorksheet
+		streams    []*StreamWriter
+	)
+	for _, name := range f.GetSheetList() {
+		sheetXMLPath, _ := f.getSheetXMLPath(name)
+		if sw, ok := f.streams[sheetXMLPath]; ok {
+			streams = append(streams, sw)
+			continue
+		}
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(name).Error() {
+				continue
+			}
+			return err
+		}
+		worksheets = append(worksheets, ws)
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.CellXfs == nil || len(s.CellXfs.Xf) == 0 {
+		return err
+	}
+	dedupeStyleParts(s)
+	xfs := getDuplicateStylePartIndexes(len(s.CellXfs.Xf), func(i int) interface{} { return s.CellXfs.Xf[i] })
+	getXfID := func(styleID int) int {
+		if styleID < 0 || styleID >= len(xfs) {
+			return 0
+		}
+		return xfs[styleID]
+	}
+	used := map[int]bool{0: true}
+	remapWorksheetsStyles(worksheets, func(styleID int) int {
+		used[getXfID(styleID)] = true
+		return styleID
+	})
+	for _, sw := range streams {
+		r, err := sw.rawData.Reader()
+		if err != nil {
+			return err
+		}
+		if err = remapStreamStyles(r, io.Discard, func(styleID int) int {
+			used[getXfID(styleID)] = true
+			return styleID
+		}); err != nil {
+			return err
+		}
+	}
+	remap := getCompactStylePartIndexes(len(s.CellXfs.Xf), func(i int) bool { return xfs[i] == i && used[i] })
+	getStyleID := func(styleID int) int { return remap[getXfID(styleID)] }
+	remapWorksheetsStyles(worksheets, getStyleID)
+	for _, sw := range streams {
+		if err = sw.remapStyles(getStyleID); err != nil {
+			return err
+		}
+	}
+	var cellXfs []xlsxXf
+	for i, xf := range s.CellXfs.Xf {
+		if remap[i] != -1 {
+			cellXfs = append(cellXfs, xf)
+		}
+	}
+	s.CellXfs.Xf, s.CellXfs.Count = cellXfs, len(cellXfs)
+	removeUnusedStyleParts(s)
+	return err
+}
+
+// remapWorksheetsStyles provides a function to remap the style index of the
+// cells, rows and columns in the given worksheets by given function.
+func remapWorksheetsStyles(worksheets []*xlsxWorksheet, fn func(styleID int) int) {
+	for _, ws := range worksheets {
+		ws.Lock()
+		if ws.Cols != nil {
+			for i := range ws.Cols.Col {
+				ws.Cols.Col[i].Style = fn(ws.Cols.Col[i].Style)
+			}
+		}
+		for i := range ws.SheetData.Row {
+			row := &ws.SheetData.Row[i]
+			row.S = fn(row.S)
+			for j := range row.C {
+				row.C[j].S = fn(row.C[j].S)
+			}
+		}
+		ws.Unlock()
+	}
+}
+
+// getDuplicateStylePartIndexes provides a function to get the index of the
+// first identical part for each part of the style sheet by given number of
+// the parts and the function to get the part by index.
+func getDuplicateStylePartIndexes(n int, part func(i int) interface{}) []int {
+	keys, indexes := make(map[string]int, n), make([]int, n)
+	for i := 0; i < n; i++ {
+		data, _ := xml.Marshal(part(i))
+		if j, ok := keys[string(data)]; ok {
+			indexes[i] = j
+			continue
+		}
+		keys[string(data)], indexes[i] = i, i
+	}
+	return indexes
+}
+
+// getCompactStylePartIndexes provides a function to get the new index of each
+// part of the style sheet after removing the parts which should not be kept,
+// the new index of the removed parts will be -1.
+func getCompactStylePartIndexes(n int, keep func(i int) bool) []int {
+	indexes, count := make([]int, n), 0
+	for i := 0; i < n; i++ {
+		indexes[i] = -1
+		if keep(i) {
+			indexes[i] = count
+			count++
+		}
+	}
+	return indexes
+}
+
+// remapStylePartIDs provides a function to remap the font, fill, border and
+// number format ID of the master formatting records and the cell style
+// formatting records by given function.
+func remapStylePartIDs(s *xlsxStyleSheet, fn func(xf *xlsxXf)) {
+	for i := range s.CellXfs.Xf {
+		fn(&s.CellXfs.Xf[i])
+	}
+	if s.CellStyleXfs != nil {
+		for i := range s.CellStyleXfs.Xf {
+			fn(&s.CellStyleXfs.Xf[i])
+		}
+	}
+}
+
+// remapStylePartID returns the new ID by given indexes, the ID out of range
+// will not be changed.
+func remapStylePartID(ID *int, indexes []int) *int {
+	if ID != nil && *ID >= 0 && *ID < len(indexes) && indexes[*ID] != -1 {
+		return intPtr(indexes[*ID])
+	}
+	return ID
+}
+
+// dedupeStyleParts provides a function to merge the identical fonts, fills,
+// borders and custom number formats of the style sheet, the duplicate parts
+// will be referenced by the formatting records no longer.
+func dedupeStyleParts(s *xlsxStyleSheet) {
+	var fonts, fills, borders []int
+	if s.Fonts != nil {
+		fonts = getDuplicateStylePartIndexes(len(s.Fonts.Font), func(i int) interface{} { return s.Fonts.Font[i] })
+	}
+	if s.Fills != nil {
+		fills = getDuplicateStylePartIndexes(len(s.Fills.Fill), func(i int) interface{} { return s.Fills.Fill[i] })
+	}
+	if s.Borders != nil {
+		borders = getDuplicateStylePartIndexes(len(s.Borders.Border), func(i int) interface{} { return s.Borders.Border[i] })
+	}
+	numFmts := map[int]int{}
+	if s.NumFmts != nil {
+		codes := map[string]int{}
+		for _, numFmt := range s.NumFmts.NumFmt {
+			if ID, ok := codes[numFmt.FormatCode]; ok {
+				numFmts[numFmt.NumFmtID] = ID
+				continue
+			}
+			codes[numFmt.FormatCode] = numFmt.NumFmtID
+		}
+	}
+	remapStylePartIDs(s, func(xf *xlsxXf) {
+		xf.FontID = remapStylePartID(xf.FontID, fonts)
+		xf.FillID = remapStylePartID(xf.FillID, fills)
+		xf.BorderID = remapStylePartID(xf.BorderID, borders)
+		if xf.NumFmtID != nil {
+			if ID, ok := numFmts[*xf.NumFmtID]; ok {
+				xf.NumFmtID = intPtr(ID)
+			}
+		}
+	})
+}
+
+// removeUnusedStyleParts provides a function to remove the fonts, fills,
+// borders and custom number formats which not referenced by any formatting
+// records of the style sheet. The first font, the first two fills, the first
+// border and the custom number formats used by the differential formatting
+// records will be always kept.
+func removeUnusedStyleParts(s *xlsxStyleSheet) {
+	fonts, fills, borders, numFmts := map[int]bool{0: true}, map[int]bool{0: true, 1: true}, map[int]bool{0: true}, map[int]bool{}
+	if s.Dxfs != nil {
+		for _, d := range s.Dxfs.Dxfs {
+			var dxf dxf
+			if d != nil && xml.Unmarshal([]byte("<dxf>"+d.Dxf+"</dxf>"), &dxf) == nil && dxf.NumFmt != nil {
+				numFmts[dxf.NumFmt.NumFmtID] = true
+			}
+		}
+	}
+	remapStylePartIDs(s, func(xf *xlsxXf) {
+		for ID, used := range map[*int]map[int]bool{xf.FontID: fonts, xf.FillID: fills, xf.BorderID: borders, xf.NumFmtID: numFmts} {
+			if ID != nil {
+				used[*ID] = true
+			}
+		}
+	})
+	var fontIndexes, fillIndexes, borderIndexes []int
+	if s.Fonts != nil {
+		fontIndexes = getCompactStylePartIndexes(len(s.Fonts.Font), func(i int) bool { return fonts[i] })
+		var items []*xlsxFont
+		for i, font := range s.Fonts.Font {
+			if fontIndexes[i] != -1 {
+				items = append(items, font)
+			}
+		}
+		s.Fonts.Font, s.Fonts.Count = items, len(items)
+	}
+	if s.Fills != nil {
+		fillIndexes = getCompactStylePartIndexes(len(s.Fills.Fill), func(i int) bool { return fills[i] })
+		var items []*xlsxFill
+		for i, fill := range s.Fills.Fill {
+			if fillIndexes[i] != -1 {
+				items = append(items, fill)
+			}
+		}
+		s.Fills.Fill, s.Fills.Count = items, len(items)
+	}
+	if s.Borders != nil {
+		borderIndexes = getCompactStylePartIndexes(len(s.Borders.Border), func(i int) bool { return borders[i] })
+		var items []*xlsxBorder
+		for i, border := range s.Borders.Border {
+			if borderIndexes[i] != -1 {
+				items = append(items, border)
+			}
+		}
+		s.Borders.Border, s.Borders.Count = items, len(items)
+	}
+	remapStylePartIDs(s, func(xf *xlsxXf) {
+		xf.FontID = remapStylePartID(xf.FontID, fontIndexes)
+		xf.FillID = remapStylePartID(xf.FillID, fillIndexes)
+		xf.BorderID = remapStylePartID(xf.BorderID, borderIndexes)
+	})
+	if s.NumFmts != nil {
+		var items []*xlsxNumFmt
+		for _, numFmt := range s.NumFmts.NumFmt {
+			if numFmts[numFmt.NumFmtID] {
+				items = append(items, numFmt)
+			}
+		}
+		if s.NumFmts.NumFmt, s.NumFmts.Count = items, len(items); len(items) == 0 {
+			s.NumFmts = nil
+		}
+	}
+}
+
+// NewConditionalStyle provides a function to create style for conditional
+// format by given style format. The parameters are the same with the NewStyle
+// function, the font, fill including the gradient fill, border, alignment,
+// protection and number format settings of the style are supported. The
+// custom number format will be also added to the number formats of the
+// workbook, so that the ID of it will not be used by other custom number
+// formats, and it will be applied to the cells only when the conditional
+// format rule is met. For example, create a
+// conditional format style with the bold font, double bottom border and the
+// percentage number format:
+//
+//	format, err := f.NewConditionalStyle(&excelize.Style{
+//	    Font:   &excelize.Font{Bold: true},
+//	    Border: []excelize.Border{{Type: "bottom", Color: "000000", Style: 6}},
+//	    NumFmt: 10,
+//	})
+func (f *File) NewConditionalStyle(style *Style) (int, error) {
+	s, err := f.stylesReader()
+	if err != nil {
+		return 0, err
+	}
+	fs, err := parseFormatStyleSet(style)
+	if err != nil {
+		return 0, err
+	}
+	s.Lock()
+	defer s.Unlock()
+	return f.newDxf(s, fs)
+}
+
+// newDxf provides a function to create the differential formatting record in
+// the style sheet by given style settings, and returns the index of the
+// record.
+func (f *File) newDxf(s *xlsxStyleSheet, fs *Style) (int, error) {
+	var err error
+	dxf := dxf{
+		Fill: newFills(fs, false),
+	}
+	if fs.Alignment != nil {
+		dxf.Alignment = newAlignment(fs)
+	}
+	if len(fs.Border) > 0 {
+		dxf.Border = newBorders(fs)
+	}
+	if fs.Font != nil {
+		dxf.Font, _ = f.newFont(fs)
+	}
+	if fs.Protection != nil {
+		dxf.Protection = newProtection(fs)
+	}
+	if dxf.NumFmt, err = f.newDxfNumFmt(s, fs); err != nil {
+		return 0, err
+	}
+	dxfStr, _ := xml.Marshal(dxf)
+	if s.Dxfs == nil {
+		s.Dxfs = &xlsxDxfs{}
+	}
+	s.Dxfs.Count++
+	s.Dxfs.Dxfs = append(s.Dxfs.Dxfs, &xlsxDxf{
+		Dxf: string(dxfStr[5 : len(dxfStr)-6]),
+	})
+	return s.Dxfs.Count - 1, nil
+}
+
+// GetConditionalStyle provides a function to get the style definition of the
+// conditional format by given differential formatting record index, which
+// returned by the NewConditionalStyle function or specified by the Format
+// field of the conditional format settings. The style definition could be
+// used to create a new conditional format style based on the existing style.
+// For example, get the style definition of the first conditional format rule
+// on the Sheet1!A1:A10:
+//
+//	formats, err := f.GetConditionalFormats("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	style, err := f.GetConditionalStyle(formats["A1:A10"][0].Format)
+func (f *File) GetConditionalStyle(idx int) (*Style, error) {
+	s, err := f.stylesReader()
+	if err != nil {
+		return nil, err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if idx < 0 || s.Dxfs == nil || len(s.Dxfs.Dxfs) <= idx || s.Dxfs.Dxfs[idx] == nil {
+		return nil, newInvalidStyleID(idx)
+	}
+	dxf, err := f.decodeDxf(s.Dxfs.Dxfs[idx])
+	if err != nil {
+		return nil, err
+	}
+	style := &Style{}
+	f.extractFills(dxf.Fill, style)
+	f.extractBorders(dxf.Border, style)
+	extractFont(dxf.Font, style)
+	if dxf.Alignment != nil {
+		extractAlignment(dxf.Alignment, style)
+	}
+	if dxf.Protection != nil {
+		extractProtection(dxf.Protection, style)
+	}
+	if dxf.NumFmt != nil {
+		if fmtCode, ok := builtInNumFmt[dxf.NumFmt.NumFmtID]; ok && strings.EqualFold(fmtCode, dxf.NumFmt.FormatCode) {
+			style.NumFmt = dxf.NumFmt.NumFmtID
+		} else {
+			style.CustomNumFmt = stringPtr(dxf.NumFmt.FormatCode)
+		}
+	}
+	return style, nil
+}
+
+// newDxfNumFmt provides a function to create the number format of the
+// differential formatting record by given style settings, the custom number
+// format will use the ID of the same number format code in the style sheet,
+// or be added to the number formats of the style sheet with an unused custom
+// number format ID.
+func (f *File) newDxfNumFmt(s *xlsxStyleSheet, style *Style) (*xlsxNumFmt, error) {
+	if style.CustomNumFmt == nil {
+		if fmtCode, ok := builtInNumFmt[style.NumFmt]; ok && style.NumFmt != 0 {
+			return &xlsxNumFmt{NumFmtID: style.NumFmt, FormatCode: fmtCode}, nil
+		}
+		return nil, nil
+	}
+	if numFmtID := getCustomNumFmtID(s, style); numFmtID != -1 {
+		return &xlsxNumFmt{NumFmtID: numFmtID, FormatCode: *style.CustomNumFmt}, nil
+	}
+	numFmtID := 163
+	if s.NumFmts != nil {
+		for _, numFmt := range s.NumFmts.NumFmt {
+			if numFmt.NumFmtID > numFmtID {
+				numFmtID = numFmt.NumFmtID
+			}
+		}
+	}
+	if s.Dxfs != nil {
+		for _, d := range s.Dxfs.Dxfs {
+			if d == nil {
+				continue
+			}
+			dxf, err := f.decodeDxf(d)
+			if err != nil {
+				return nil, err
+			}
+			if dxf.NumFmt != nil && dxf.NumFmt.NumFmtID > numFmtID {
+				numFmtID = dxf.NumFmt.NumFmtID
+			}
+		}
+	}
+	if s.NumFmts == nil {
+		s.NumFmts = &xlsxNumFmts{}
+	}
+	s.NumFmts.NumFmt = append(s.NumFmts.NumFmt, &xlsxNumFmt{NumFmtID: numFmtID + 1, FormatCode: *style.CustomNumFmt})
+	s.NumFmts.Count++
+	return &xlsxNumFmt{NumFmtID: numFmtID + 1, FormatCode: *style.CustomNumFmt}, nil
+}
+
+// decodeDxf provides a function to decode the differential formatting record
+// of the style sheet.
+func (f *File) decodeDxf(d *xlsxDxf) (*dxf, error) {
+	dxf := &dxf{}
+	if err := f.xmlNewDecoder(strings.NewReader("<dxf>" + d.Dxf + "</dxf>")).
+		Decode(dxf); err != nil && err != io.EOF {
+		return dxf, err
+	}
+	return dxf, nil
+}
+
+// GetDefaultFont provides the default font name currently set in the
+// workbook. The spreadsheet generated by excelize default font is Calibri.
+func (f *File) GetDefaultFont() (string, error) {
+	font, err := f.readDefaultFont()
+	if err != nil {
+		return "", err
+	}
+	return *font.Name.Val, err
+}
+
+// SetDefaultFont changes the default font in the workbook.
+func (f *File) SetDefaultFont(fontName string) error {
+	font, err := f.readDefaultFont()
+	if err != nil {
+		return err
+	}
+	font.Name.Val = stringPtr(fontName)
+	s, _ := f.stylesReader()
+	s.Fonts.Font[0] = font
+	custom := true
+	s.CellStyles.CellStyle[0].CustomBuiltIn = &custom
+	return err
+}
+
+// readDefaultFont provides an un-marshalled font value.
+func (f *File) readDefaultFont() (*xlsxFont, error) {
+	s, err := f.stylesReader()
+	if err != nil {
+		return nil, err
+	}
+	return s.Fonts.Font[0], err
+}
+
+// getFontID provides a function to get font ID.
+// If given font does not exist, will return -1.
+func (f *File) getFontID(styleSheet *xlsxStyleSheet, style *Style) (int, error) {
+	var err error
+	fontID := -1
+	if styleSheet.Fonts == nil || style.Font == nil {
+		return fontID, err
+	}
+	for idx, fnt := range styleSheet.Fonts.Font {
+		font, err := f.newFont(style)
+		if err != nil {
+			return fontID, err
+		}
+		if reflect.DeepEqual(*fnt, *font) {
+			fontID = idx
+			return fontID, err
+		}
+	}
+	return fontID, err
+}
+
+// newFontColor set font color by given styles.
+func newFontColor(font *Font) *xlsxColor {
+	var fontColor *xlsxColor
+	prepareFontColor := func() {
+		if fontColor != nil {
+			return
+		}
+		fontColor = &xlsxColor{}
+	}
+	if font.Color != "" {
+		prepareFontColor()
+		fontColor.RGB = getPaletteColor(font.Color)
+	}
+	if font.ColorIndexed >= 0 && font.ColorIndexed <= len(IndexedColorMapping)+1 {
+		prepareFontColor()
+		fontColor.Indexed = font.ColorIndexed
+	}
+	if font.ColorTheme != nil {
+		prepareFontColor()
+		fontColor.Theme = font.ColorTheme
+	}
+	if font.ColorTint != 0 {
+		prepareFontColor()
+		fontColor.Tint = font.ColorTint
+	}
+	return fontColor
+}
+
+// newFont provides a function to add font style by given cell format
+// settings.
+func (f *File) newFont(style *Style) (*xlsxFont, error) {
+	var err error
+	if style.Font.Size < MinFontSize {
+		style.Font.Size = 11
+	}
+	fnt := xlsxFont{
+		Sz:     &attrValFloat{Val: float64Ptr(style.Font.Size)},
+		Name:   &attrValString{Val: stringPtr(style.Font.Family)},
+		Family: &attrValInt{Val: intPtr(2)},
+	}
+	fnt.Color = newFontColor(style.Font)
+	if style.Font.Bold {
+		fnt.B = &attrValBool{Val: &style.Font.Bold}
+	}
+	if style.Font.Italic {
+		fnt.I = &attrValBool{Val: &style.Font.Italic}
+	}
+	if *fnt.Name.Val == "" {
+		if *fnt.Name.Val, err = f.GetDefaultFont(); err != nil {
+			return &fnt, err
+		}
+	}
+	if style.Font.Strike {
+		fnt.Strike = &attrValBool{Val: &style.Font.Strike}
+	}
+	if idx := inStrSlice(supportedUnderlineTypes, style.Font.Underline, true); idx != -1 {
+		fnt.U = &attrValString{Val: stringPtr(supportedUnderlineTypes[idx])}
+	}
+	for val, enable := range map[**attrValBool]bool{
+		&fnt.Condense: style.Font.Condense, &fnt.Extend: style.Font.Extend,
+		&fnt.Outline: style.Font.Outline, &fnt.Shadow: style.Font.Shadow,
+	} {
+		if enable {
+			*val = &attrValBool{Val: boolPtr(true)}
+		}
+	}
+	if style.Font.Charset != nil {
+		fnt.Charset = &attrValInt{Val: intPtr(*style.Font.Charset)}
+	}
+	if style.Font.Scheme != "" {
+		fnt.Scheme = &attrValString{Val: stringPtr(style.Font.Scheme)}
+	}
+	return &fnt, err
+}
+
+// getNumFmtID provides a function to get number format code ID.
+// If given number format code does not exist, will return -1.
+func getNumFmtID(styleSheet *xlsxStyleSheet, style *Style) (numFmtID int) {
+	numFmtID = -1
+	if _, ok := builtInNumFmt[style.NumFmt]; ok {
+		return style.NumFmt
+	}
+	for lang, numFmt := range langNumFmt {
+		if _, ok := numFmt[style.NumFmt]; ok && lang == style.Lang {
+			numFmtID = style.NumFmt
+			return
+		}
+	}
+	if fmtCode, ok := currencyNumFmt[style.NumFmt]; ok {
+		numFmtID = style.NumFmt
+		if styleSheet.NumFmts != nil {
+			for _, numFmt := range styleSheet.NumFmts.NumFmt {
+				if numFmt.FormatCode == fmtCode {
+					numFmtID = numFmt.NumFmtID
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+// newNumFmt provides a function to check if number format code in the range
+// of built-in values.
+func newNumFmt(styleSheet *xlsxStyleSheet, style *Style) int {
+	dp := "0."
+	numFmtID := 164 // Default custom number format code from 164.
+	if style.DecimalPlaces < 0 || style.DecimalPlaces > 30 {
+		style.DecimalPlaces = 2
+	}
+	for i := 0; i < style.DecimalPlaces; i++ {
+		dp += "0"
+	}
+	if style.CustomNumFmt != nil {
+		if customNumFmtID := getCustomNumFmtID(styleSheet, style); customNumFmtID != -1 {
+			return customNumFmtID
+		}
+		return setCustomNumFmt(styleSheet, style)
+	}
+	_, ok := builtInNumFmt[style.NumFmt]
+	if !ok {
+		fc, currency := currencyNumFmt[style.NumFmt]
+		if !currency {
+			return setLangNumFmt(styleSheet, style)
+		}
+		fc = strings.ReplaceAll(fc, "0.00", dp)
+		if style.NegRed {
+			fc = fc + ";[Red]" + fc
+		}
+		if styleSheet.NumFmts != nil {
+			numFmtID = styleSheet.NumFmts.NumFmt[len(styleSheet.NumFmts.NumFmt)-1].NumFmtID + 1
+			nf := xlsxNumFmt{
+				FormatCode: fc,
+				NumFmtID:   numFmtID,
+			}
+			styleSheet.NumFmts.NumFmt = append(styleSheet.NumFmts.NumFmt, &nf)
+			styleSheet.NumFmts.Count++
+		} else {
+			nf := xlsxNumFmt{
+				FormatCode: fc,
+				NumFmtID:   numFmtID,
+			}
+			numFmts := xlsxNumFmts{
+				NumFmt: []*xlsxNumFmt{&nf},
+				Count:  1,
+			}
+			styleSheet.NumFmts = &numFmts
+		}
+		return numFmtID
+	}
+	return style.NumFmt
 }
 
 // setCustomNumFmt provides a function to set custom number format code.
@@ -2438,35 +3631,6 @@ func getFillID(styleSheet *xlsxStyleSheet, style *Style) (fillID int) {
 // newFills provides a function to add fill elements in the styles.xml by
 // given cell format settings.
 func newFills(style *Style, fg bool) *xlsxFill {
-	patterns := []string{
-		"none",
-		"solid",
-		"mediumGray",
-		"darkGray",
-		"lightGray",
-		"darkHorizontal",
-		"darkVertical",
-		"darkDown",
-		"darkUp",
-		"darkGrid",
-		"darkTrellis",
-		"lightHorizontal",
-		"lightVertical",
-		"lightDown",
-		"lightUp",
-		"lightGrid",
-		"lightTrellis",
-		"gray125",
-		"gray0625",
-	}
-	
-	variants := []float64{
-		90,
-		0,
-		45,
-		135,
-	}
-	
 	var fill xlsxFill
 	switch style.Fill.Type {
 	case "gradient":
@@ -2476,7 +3640,7 @@ func newFills(style *Style, fg bool) *xlsxFill {
 		var gradient xlsxGradientFill
 		switch style.Fill.Shading {
 		case 0, 1, 2, 3:
-			gradient.Degree = variants[style.Fill.Shading]
+			gradient.Degree = styleFillVariants[style.Fill.Shading]
 		case 4:
 			gradient.Type = "path"
 		case 5:
@@ -2503,7 +3667,7 @@ func newFills(style *Style, fg bool) *xlsxFill {
 			break
 		}
 		var pattern xlsxPatternFill
-		pattern.PatternType = patterns[style.Fill.Pattern]
+		pattern.PatternType = styleFillPatterns[style.Fill.Pattern]
 		if fg {
 			if pattern.FgColor == nil {
 				pattern.FgColor = new(xlsxColor)
@@ -2572,23 +3736,6 @@ func getBorderID(styleSheet *xlsxStyleSheet, style *Style) (borderID int) {
 // newBorders provides a function to add border elements in the styles.xml by
 // given borders format settings.
 func newBorders(style *Style) *xlsxBorder {
-	styles := []string{
-		"none",
-		"thin",
-		"medium",
-		"dashed",
-		"dotted",
-		"thick",
-		"double",
-		"hair",
-		"mediumDashed",
-		"dashDot",
-		"mediumDashDot",
-		"dashDotDot",
-		"mediumDashDotDot",
-		"slantDashDot",
-	}
-	
 	var border xlsxBorder
 	for _, v := range style.Border {
 		if 0 <= v.Style && v.Style < 14 {
@@ -2596,23 +3743,23 @@ func newBorders(style *Style) *xlsxBorder {
 			color.RGB = getPaletteColor(v.Color)
 			switch v.Type {
 			case "left":
-				border.Left.Style = styles[v.Style]
+				border.Left.Style = styleBorders[v.Style]
 				border.Left.Color = &color
 			case "right":
-				border.Right.Style = styles[v.Style]
+				border.Right.Style = styleBorders[v.Style]
 				border.Right.Color = &color
 			case "top":
-				border.Top.Style = styles[v.Style]
+				border.Top.Style = styleBorders[v.Style]
 				border.Top.Color = &color
 			case "bottom":
-				border.Bottom.Style = styles[v.Style]
+				border.Bottom.Style = styleBorders[v.Style]
 				border.Bottom.Color = &color
 			case "diagonalUp":
-				border.Diagonal.Style = styles[v.Style]
+				border.Diagonal.Style = styleBorders[v.Style]
 				border.Diagonal.Color = &color
 				border.DiagonalUp = true
 			case "diagonalDown":
-				border.Diagonal.Style = styles[v.Style]
+				border.Diagonal.Style = styleBorders[v.Style]
 				border.Diagonal.Color = &color
 				border.DiagonalDown = true
 			}
@@ -2621,9 +3768,9 @@ func newBorders(style *Style) *xlsxBorder {
 	return &border
 }
 
-// setCellXfs provides a function to set describes all of the formatting for a
-// cell.
-func setCellXfs(style *xlsxStyleSheet, fontID, numFmtID, fillID, borderID int, applyAlignment, applyProtection bool, alignment *xlsxAlignment, protection *xlsxProtection) int {
+// newCellXf provides a function to create the formatting record which
+// describes all of the formatting for a cell.
+func newCellXf(fontID, numFmtID, fillID, borderID int, applyAlignment, applyProtection bool, alignment *xlsxAlignment, protection *xlsxProtection) xlsxXf {
 	var xf xlsxXf
 	xf.FontID = intPtr(fontID)
 	if fontID != 0 {
@@ -2641,7 +3788,6 @@ func setCellXfs(style *xlsxStyleSheet, fontID, numFmtID, fillID, borderID int, a
 	if borderID != 0 {
 		xf.ApplyBorder = boolPtr(true)
 	}
-	style.CellXfs.Count = len(style.CellXfs.Xf) + 1
 	xf.Alignment = alignment
 	if alignment != nil {
 		xf.ApplyAlignment = boolPtr(applyAlignment)
@@ -2650,10 +3796,7 @@ func setCellXfs(style *xlsxStyleSheet, fontID, numFmtID, fillID, borderID int, a
 		xf.ApplyProtection = boolPtr(applyProtection)
 		xf.Protection = protection
 	}
-	xfID := 0
-	xf.XfID = &xfID
-	style.CellXfs.Xf = append(style.CellXfs.Xf, xf)
-	return style.CellXfs.Count - 1
+	return xf
 }
 
 // GetCellStyle provides a function to get cell style index by given worksheet
@@ -2673,6 +3816,48 @@ func (f *File) GetCellStyle(sheet, cell string) (int, error) {
 	return f.prepareCellStyle(ws, col, row, ws.SheetData.Row[row-1].C[col-1].S), err
 }
 
+// GetCellNumberFormatCategory provides a function to get the category of the
+// number format applied to the cell by given worksheet name and cell
+// reference. The category is detected from the built-in or custom number
+// format code of the cell style, such as Date for "yyyy-mm-dd" or Currency
+// for "$#,##0.00". For example, get the number format category of the cell
+// A1 on Sheet1:
+//
+//	category, err := f.GetCellNumberFormatCategory("Sheet1", "A1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if category == excelize.NumberFormatCategoryDate {
+//	    fmt.Println("the cell A1 contains a date")
+//	}
+func (f *File) GetCellNumberFormatCategory(sheet, cell string) (NumberFormatCategory, error) {
+	styleIdx, err := f.GetCellStyle(sheet, cell)
+	if err != nil {
+		return NumberFormatCategoryGeneral, err
+	}
+	styleSheet, err := f.stylesReader()
+	if err != nil {
+		return NumberFormatCategoryGeneral, err
+	}
+	if styleSheet.CellXfs == nil || styleIdx < 0 || styleIdx >= len(styleSheet.CellXfs.Xf) ||
+		styleSheet.CellXfs.Xf[styleIdx].NumFmtID == nil {
+		return NumberFormatCategoryGeneral, err
+	}
+	numFmtID := *styleSheet.CellXfs.Xf[styleIdx].NumFmtID
+	if numFmt, ok := builtInNumFmt[numFmtID]; ok {
+		return getNumFmtCategory(numFmt), err
+	}
+	if styleSheet.NumFmts != nil {
+		for _, numFmt := range styleSheet.NumFmts.NumFmt {
+			if numFmt.NumFmtID == numFmtID {
+				return getNumFmtCategory(numFmt.FormatCode), err
+			}
+		}
+	}
+	return getBuiltInNumFmtCategory(numFmtID), err
+}
+
 // SetCellStyle provides a function to add style attribute for cells by given
 // worksheet name, range reference and style ID. This function is concurrency
 // safe. Note that diagonalDown and diagonalUp type border should be use same
@@ -2829,6 +4014,283 @@ func (f *File) SetCellStyle(sheet, hCell, vCell string, styleID int) error {
 	return err
 }
 
+// SetCellNumFmt provides a function to set the number format of the cell or
+// the cells in the range by given worksheet name, cell reference or range
+// reference and number format code, without creating a complete style. The
+// other style settings of each cell will be kept, the derived styles will be
+// created for each distinct existing style and be shared by the cells with
+// the same style, and the built-in number format will be used if the format
+// code is the same as it. For example, set the number format with the
+// thousands separator and two decimal places for the cell A1 on Sheet1:
+//
+//	err := f.SetCellNumFmt("Sheet1", "A1", "#,##0.00")
+//
+// Set the date number format for the cells in range B2:D10 on Sheet1:
+//
+//	err := f.SetCellNumFmt("Sheet1", "B2:D10", "yyyy-mm-dd")
+func (f *File) SetCellNumFmt(sheet, cell, numFmt string) error {
+	if numFmt == "" {
+		return ErrParameterRequired
+	}
+	ref := cell
+	if !strings.Contains(ref, ":") {
+		ref += ":" + ref
+	}
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	styles := make(map[int]int)
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		runs, err := f.getNumFmtStyleRuns(ws, row, coordinates[0], coordinates[2], numFmt, styles)
+		if err != nil {
+			return err
+		}
+		for _, run := range runs {
+			start, _ := CoordinatesToCellName(run[0], row)
+			end, _ := CoordinatesToCellName(run[1], row)
+			if err = f.SetCellStyle(sheet, start, end, run[2]); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// getNumFmtStyleRuns provides a function to get the runs of the contiguous
+// cells which will be set with the same derived number format style by given
+// worksheet, row number, the first and last column number of the range, and
+// the number format code. Each run contains the first and last column number
+// and the derived style index. The derived styles will be cached in the given
+// map by the existing style index of the cells.
+func (f *File) getNumFmtStyleRuns(ws *xlsxWorksheet, row, fromCol, toCol int, numFmt string, styles map[int]int) ([][3]int, error) {
+	ws.Lock()
+	defer ws.Unlock()
+	var runs [][3]int
+	for col := fromCol; col <= toCol; col++ {
+		var styleID int
+		if row <= len(ws.SheetData.Row) && col <= len(ws.SheetData.Row[row-1].C) {
+			styleID = ws.SheetData.Row[row-1].C[col-1].S
+		}
+		styleID = f.prepareCellStyle(ws, col, row, styleID)
+		newStyleID, ok := styles[styleID]
+		if !ok {
+			var err error
+			if newStyleID, err = f.newNumFmtStyle(styleID, numFmt); err != nil {
+				return runs, err
+			}
+			styles[styleID] = newStyleID
+		}
+		if len(runs) > 0 && runs[len(runs)-1][2] == newStyleID {
+			runs[len(runs)-1][1] = col
+			continue
+		}
+		runs = append(runs, [3]int{col, col, newStyleID})
+	}
+	return runs, nil
+}
+
+// newNumFmtStyle provides a function to create the style which derived from
+// the given style with the number format replaced by given number format
+// code, returns the existing style index if the same style exists. The
+// formatting record of the given style will be cloned, and only the number
+// format of it will be changed, so the other settings such as the cell style
+// of the formatting record will be kept.
+func (f *File) newNumFmtStyle(styleID int, numFmt string) (int, error) {
+	s, err := f.stylesReader()
+	if err != nil {
+		return 0, err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if styleID < 0 || s.CellXfs == nil || len(s.CellXfs.Xf) <= styleID {
+		return 0, newInvalidStyleID(styleID)
+	}
+	numFmtID := getBuiltInNumFmtID(numFmt)
+	if numFmtID == -1 {
+		style := &Style{CustomNumFmt: &numFmt}
+		if numFmtID = getCustomNumFmtID(s, style); numFmtID == -1 {
+			numFmtID = setCustomNumFmt(s, style)
+		}
+	}
+	xf := cloneXf(s.CellXfs.Xf[styleID])
+	xf.NumFmtID, xf.ApplyNumberFormat = intPtr(numFmtID), boolPtr(true)
+	for i := range s.CellXfs.Xf {
+		if reflect.DeepEqual(s.CellXfs.Xf[i], xf) {
+			return i, err
+		}
+	}
+	s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+	s.CellXfs.Count = len(s.CellXfs.Xf)
+	return s.CellXfs.Count - 1, err
+}
+
+// cloneXf provides a function to copy the given cell formatting record, the
+// alignment and protection settings of the copy will not be shared with the
+// given formatting record.
+func cloneXf(xf xlsxXf) xlsxXf {
+	if xf.Alignment != nil {
+		alignment := *xf.Alignment
+		xf.Alignment = &alignment
+	}
+	if xf.Protection != nil {
+		protection := *xf.Protection
+		xf.Protection = &protection
+	}
+	return xf
+}
+
+// getBuiltInNumFmtID provides a function to get the built-in number format ID
+// by given number format code, returns -1 if the number format code is not a
+// built-in number format. The date and time number formats depend on the
+// system locale will not be matched.
+func getBuiltInNumFmtID(numFmt string) int {
+	numFmtIDs := make([]int, 0, len(builtInNumFmt))
+	for numFmtID := range builtInNumFmt {
+		numFmtIDs = append(numFmtIDs, numFmtID)
+	}
+	sort.Ints(numFmtIDs)
+	for _, numFmtID := range numFmtIDs {
+		if numFmtID != NumFmtDate && numFmtID != NumFmtDateTime && strings.EqualFold(builtInNumFmt[numFmtID], numFmt) {
+			return numFmtID
+		}
+	}
+	return -1
+}
+
+// CellInfo directly maps the cell information passed to the predicate of the
+// StyleCellsWhere function. The Value is the raw value of the cell without
+// number format applied, and the Type will be CellTypeUnset for blank cells.
+type CellInfo struct {
+	Cell    string
+	Col     int
+	Row     int
+	Type    CellType
+	Value   string
+	Formula string
+	StyleID int
+}
+
+// StyleCellsWhere provides a function to apply the style for the cells in the
+// given range reference which satisfy the predicate. The range will be
+// scanned once, the blank cells in the range will be passed to the predicate
+// too. Note that the predicate should not call the functions which access the
+// same worksheet. For example, highlight negative numbers in range A1:D100 on
+// Sheet1:
+//
+//	style, err := f.NewStyle(&excelize.Style{
+//	    Font: &excelize.Font{Color: "#FF0000"},
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	}
+//	err = f.StyleCellsWhere("Sheet1", "A1:D100", func(c excelize.CellInfo) bool {
+//	    val, err := strconv.ParseFloat(c.Value, 64)
+//	    return err == nil && val < 0
+//	}, style)
+func (f *File) StyleCellsWhere(sheet, rangeRef string, pred func(CellInfo) bool, styleID int) error {
+	if pred == nil {
+		return ErrParameterRequired
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	if styleID < 0 || s.CellXfs == nil || len(s.CellXfs.Xf) <= styleID {
+		s.Unlock()
+		return newInvalidStyleID(styleID)
+	}
+	s.Unlock()
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	matched, err := f.matchCells(ws, sst, coordinates, pred)
+	if err != nil || len(matched) == 0 {
+		return err
+	}
+	maxCol, maxRow := coordinates[0], matched[len(matched)-1][1]
+	for _, cell := range matched {
+		if cell[0] > maxCol {
+			maxCol = cell[0]
+		}
+	}
+	prepareSheetXML(ws, maxCol, maxRow)
+	makeContiguousColumns(ws, matched[0][1], maxRow, maxCol)
+	ws.Lock()
+	defer ws.Unlock()
+	for _, cell := range matched {
+		ws.SheetData.Row[cell[1]-1].C[cell[0]-1].S = styleID
+	}
+	return err
+}
+
+// matchCells scan the cells in the given range coordinates of the worksheet
+// and returns the column and row number of the cells which satisfy the
+// predicate in row order.
+func (f *File) matchCells(ws *xlsxWorksheet, sst *xlsxSST, coordinates []int, pred func(CellInfo) bool) ([][2]int, error) {
+	ws.Lock()
+	defer ws.Unlock()
+	cells := map[[2]int]*xlsxC{}
+	for rowIdx := range ws.SheetData.Row {
+		rowData := &ws.SheetData.Row[rowIdx]
+		if rowData.R < coordinates[1] || rowData.R > coordinates[3] {
+			continue
+		}
+		for colIdx := range rowData.C {
+			c := &rowData.C[colIdx]
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return nil, err
+			}
+			if col >= coordinates[0] && col <= coordinates[2] {
+				cells[[2]int{col, row}] = c
+			}
+		}
+	}
+	var matched [][2]int
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cell, err := CoordinatesToCellName(col, row)
+			if err != nil {
+				return nil, err
+			}
+			info := CellInfo{Cell: cell, Col: col, Row: row}
+			if c, ok := cells[[2]int{col, row}]; ok {
+				if info.Value, err = c.getValueFrom(f, sst, true); err != nil {
+					return nil, err
+				}
+				info.Type, info.StyleID = cellTypes[c.T], c.S
+				if c.F != nil {
+					info.Formula = c.F.Content
+					if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+						info.Formula = getSharedFormula(ws, *c.F.Si, c.R)
+					}
+				}
+			}
+			if pred(info) {
+				matched = append(matched, [2]int{col, row})
+			}
+		}
+	}
+	return matched, nil
+}
+
 // SetConditionalFormat provides a function to create conditional formatting
 // rule for cell value. Conditional formatting is a feature of Excel which
 // allows you to apply a format to a cell or a range of cells based on certain
@@ -2850,13 +4312,15 @@ func (f *File) SetCellStyle(sheet, hCell, vCell string, styleID int) error {
 //	 time_period   | Criteria
 //	 text          | Criteria
 //	               | Value
-//	 average       | Criteria
+//	 average       | AboveAverage
+//	               | StdDev
+//	               | EqualAverage
 //	 duplicate     | (none)
 //	 unique        | (none)
-//	 top           | Criteria
-//	               | Value
-//	 bottom        | Criteria
-//	               | Value
+//	 top           | Value
+//	               | Percent
+//	 bottom        | Value
+//	               | Percent
 //	 blanks        | (none)
 //	 no_blanks     | (none)
 //	 errors        | (none)
@@ -3037,6 +4501,18 @@ func (f *File) SetCellStyle(sheet, hCell, vCell string, styleID int) error {
 //	    },
 //	)
 //
+// StdDev - Used for average, specifies how many standard deviations above or
+// below the average the cell values should be highlighted, the valid range is
+// 1 to 3. The EqualAverage specifies whether the values equal to the average
+// are also highlighted:
+//
+//	// Top/Bottom rules: 1 std dev above average...
+//	err := f.SetConditionalFormat("Sheet1", "C1:C10",
+//	    []excelize.ConditionalFormatOptions{
+//	        {Type: "average", Format: format, AboveAverage: true, StdDev: 1},
+//	    },
+//	)
+//
 // type: duplicate - The duplicate type is used to highlight duplicate cells in a range:
 //
 //	// Highlight cells rules: Duplicate Values...
@@ -3083,6 +4559,21 @@ func (f *File) SetCellStyle(sheet, hCell, vCell string, styleID int) error {
 //	    },
 //	)
 //
+// type: formula - The formula type is used to specify a conditional format
+// rule by the formula, the format will be applied when the formula returns
+// true. The range reference can be a single range or multiple ranges
+// separated by space or comma. The relative references in the formula are
+// relative to the top-left cell of the first range, and will be adjusted for
+// each cell in the ranges, so use the absolute references to refer to the
+// fixed cells. For example, highlight the rows in A1:D10 and F1:I10 whose
+// column A of the same row is greater than the value of the cell K1:
+//
+//	err := f.SetConditionalFormat("Sheet1", "A1:D10 F1:I10",
+//	    []excelize.ConditionalFormatOptions{
+//	        {Type: "formula", Criteria: "$A1>$K$1", Format: format},
+//	    },
+//	)
+//
 // type: 2_color_scale - The 2_color_scale type is used to specify Excel's "2
 // Color Scale" style conditional format:
 //
@@ -3190,6 +4681,62 @@ func (f *File) SetCellStyle(sheet, hCell, vCell string, styleID int) error {
 // MaxColor - Same as MinColor, see above.
 //
 // BarColor - Used for data_bar. Same as MinColor, see above.
+//
+// type: icon_set - The icon_set type is used to specify Excel's "Icon Sets"
+// style conditional format, the icons will be assigned to the values by the
+// evenly divided percent thresholds of the range:
+//
+//	// Icon Sets: 3 Arrows, show icons only.
+//	err := f.SetConditionalFormat("Sheet1", "A1:A10",
+//	    []excelize.ConditionalFormatOptions{
+//	        {
+//	            Type:      "icon_set",
+//	            IconStyle: "3Arrows",
+//	            IconsOnly: true,
+//	        },
+//	    },
+//	)
+//
+// IconStyle - Used for icon_set, the default icon style is 3TrafficLights1.
+// The available icon styles are:
+//
+//	3Arrows
+//	3ArrowsGray
+//	3Flags
+//	3Signs
+//	3Symbols
+//	3Symbols2
+//	3TrafficLights1
+//	3TrafficLights2
+//	4Arrows
+//	4ArrowsGray
+//	4Rating
+//	4RedToBlack
+//	4TrafficLights
+//	5Arrows
+//	5ArrowsGray
+//	5Quarters
+//	5Rating
+//
+// ReverseIcons - Used for icon_set, reverse the order of the icons.
+//
+// IconsOnly - Used for icon_set, show the icons without the cell values.
+//
+// StopIfTrue - The StopIfTrue property is used to stop evaluating the rules
+// with lower priority when the rule is met.
+//
+// Priority - The Priority property is used to specify the priority of the
+// rule, the rule with lower value will be evaluated first. The rules without
+// the priority will be given the unused priorities starting from 1 in the
+// order of the options, so the default priority is the position of the rule
+// in the options if the priority was not specified for any rule:
+//
+//	err := f.SetConditionalFormat("Sheet1", "A1:A10",
+//	    []excelize.ConditionalFormatOptions{
+//	        {Type: "cell", Criteria: ">", Format: format, Value: "6", StopIfTrue: true, Priority: 1},
+//	        {Type: "icon_set", IconStyle: "5Rating", ReverseIcons: true, Priority: 2},
+//	    },
+//	)
 func (f *File) SetConditionalFormat(sheet, rangeRef string, opts []ConditionalFormatOptions) error {
 	drawContFmtFunc := map[string]func(p int, ct string, fmtCond *ConditionalFormatOptions) *xlsxCfRule{
 		"cellIs":          drawCondFmtCellIs,
@@ -3200,6 +4747,7 @@ func (f *File) SetConditionalFormat(sheet, rangeRef string, opts []ConditionalFo
 		"2_color_scale":   drawCondFmtColorScale,
 		"3_color_scale":   drawCondFmtColorScale,
 		"dataBar":         drawCondFmtDataBar,
+		"iconSet":         drawCondFmtIconSet,
 		"expression":      drawCondFmtExp,
 	}
 	
@@ -3207,8 +4755,24 @@ func (f *File) SetConditionalFormat(sheet, rangeRef string, opts []ConditionalFo
 	if err != nil {
 		return err
 	}
-	var cfRule []*xlsxCfRule
-	for p, v := range opts {
+	if rangeRef, err = f.prepareCondFmtRange(rangeRef); err != nil {
+		return err
+	}
+	priorities := map[int]bool{}
+	for _, opt := range opts {
+		if validType[opt.Type] == "iconSet" && opt.IconStyle != "" && inStrSlice(iconSetStyles, opt.IconStyle, true) == -1 {
+			return newInvalidOptionalValue("IconStyle", opt.IconStyle, iconSetStyles)
+		}
+		if opt.Priority > 0 {
+			priorities[opt.Priority] = true
+		}
+	}
+	var (
+		cfRule   []*xlsxCfRule
+		priority int
+	)
+	for p := range opts {
+		v := opts[p]
 		var vt, ct string
 		var ok bool
 		// "type" is a required parameter, check for valid validation types.
@@ -3216,10 +4780,19 @@ func (f *File) SetConditionalFormat(sheet, rangeRef string, opts []ConditionalFo
 		if ok {
 			// Check for valid criteria types.
 			ct, ok = criteriaType[v.Criteria]
-			if ok || vt == "expression" {
+			if ok || inStrSlice(condFmtTypesWithoutCriteria, vt, true) != -1 {
 				drawFunc, ok := drawContFmtFunc[vt]
 				if ok {
-					cfRule = append(cfRule, drawFunc(p, ct, &v))
+					if rule := drawFunc(p, ct, &v); rule != nil {
+						rule.StopIfTrue = v.StopIfTrue
+						if rule.Priority = v.Priority; rule.Priority <= 0 {
+							for priority++; priorities[priority]; {
+								priority++
+							}
+							rule.Priority = priority
+						}
+						cfRule = append(cfRule, rule)
+					}
 				}
 			}
 		}
@@ -3232,6 +4805,38 @@ func (f *File) SetConditionalFormat(sheet, rangeRef string, opts []ConditionalFo
 	return err
 }
 
+// prepareCondFmtRange provides a function to check and normalize the range
+// reference of the conditional formatting by given range reference, which
+// can be a single range or multiple ranges separated by space or comma. The
+// corners of each range will be corrected to make the top-left cell first,
+// because the relative references in the formula of the rule are evaluated
+// relative to the top-left cell of the first range.
+func (f *File) prepareCondFmtRange(rangeRef string) (string, error) {
+	var refs []string
+	for _, ref := range strings.FieldsFunc(rangeRef, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if !strings.Contains(ref, ":") {
+			col, row, err := CellNameToCoordinates(strings.ReplaceAll(ref, "$", ""))
+			if err != nil {
+				return rangeRef, err
+			}
+			ref, _ = CoordinatesToCellName(col, row)
+			refs = append(refs, ref)
+			continue
+		}
+		coordinates, err := rangeRefToCoordinates(ref)
+		if err != nil {
+			return rangeRef, err
+		}
+		_ = sortCoordinates(coordinates)
+		ref, _ = f.coordinatesToRangeRef(coordinates)
+		refs = append(refs, ref)
+	}
+	if len(refs) == 0 {
+		return rangeRef, ErrParameterInvalid
+	}
+	return strings.Join(refs, " "), nil
+}
+
 // extractCondFmtCellIs provides a function to extract conditional format
 // settings for cell value (include between, not between, equal, not equal,
 // greater than and less than) by given conditional formatting rule.
@@ -3266,12 +4871,18 @@ func extractCondFmtTop10(c *xlsxCfRule) ConditionalFormatOptions {
 // settings for above average and below average by given conditional formatting
 // rule.
 func extractCondFmtAboveAverage(c *xlsxCfRule) ConditionalFormatOptions {
-	return ConditionalFormatOptions{
+	format := ConditionalFormatOptions{
 		Type:         "average",
 		Criteria:     "=",
 		Format:       *c.DxfID,
-		AboveAverage: *c.AboveAverage,
+		AboveAverage: true,
+		StdDev:       c.StdDev,
+		EqualAverage: c.EqualAverage,
 	}
+	if c.AboveAverage != nil {
+		format.AboveAverage = *c.AboveAverage
+	}
+	return format
 }
 
 // extractCondFmtDuplicateUniqueValues provides a function to extract
@@ -3291,7 +4902,7 @@ func extractCondFmtDuplicateUniqueValues(c *xlsxCfRule) ConditionalFormatOptions
 // extractCondFmtColorScale provides a function to extract conditional format
 // settings for color scale (include 2 color scale and 3 color scale) by given
 // conditional formatting rule.
-func extractCondFmtColorScale(c *xlsxCfRule) ConditionalFormatOptions {
+func (f *File) extractCondFmtColorScale(c *xlsxCfRule) ConditionalFormatOptions {
 	var format ConditionalFormatOptions
 	format.Type, format.Criteria = "2_color_scale", "="
 	values := len(c.ColorScale.Cfvo)
@@ -3301,12 +4912,12 @@ func extractCondFmtColorScale(c *xlsxCfRule) ConditionalFormatOptions {
 		if c.ColorScale.Cfvo[0].Val != "0" {
 			format.MinValue = c.ColorScale.Cfvo[0].Val
 		}
-		format.MinColor = "#" + strings.TrimPrefix(strings.ToUpper(c.ColorScale.Color[0].RGB), "FF")
+		format.MinColor = f.getCondFmtColor(c.ColorScale.Color[0])
 		format.MaxType = c.ColorScale.Cfvo[1].Type
 		if c.ColorScale.Cfvo[1].Val != "0" {
 			format.MaxValue = c.ColorScale.Cfvo[1].Val
 		}
-		format.MaxColor = "#" + strings.TrimPrefix(strings.ToUpper(c.ColorScale.Color[1].RGB), "FF")
+		format.MaxColor = f.getCondFmtColor(c.ColorScale.Color[1])
 	}
 	if colors == 3 {
 		format.Type = "3_color_scale"
@@ -3314,24 +4925,55 @@ func extractCondFmtColorScale(c *xlsxCfRule) ConditionalFormatOptions {
 		if c.ColorScale.Cfvo[1].Val != "0" {
 			format.MidValue = c.ColorScale.Cfvo[1].Val
 		}
-		format.MidColor = "#" + strings.TrimPrefix(strings.ToUpper(c.ColorScale.Color[1].RGB), "FF")
+		format.MidColor = f.getCondFmtColor(c.ColorScale.Color[1])
 		format.MaxType = c.ColorScale.Cfvo[2].Type
 		if c.ColorScale.Cfvo[2].Val != "0" {
 			format.MaxValue = c.ColorScale.Cfvo[2].Val
 		}
-		format.MaxColor = "#" + strings.TrimPrefix(strings.ToUpper(c.ColorScale.Color[2].RGB), "FF")
+		format.MaxColor = f.getCondFmtColor(c.ColorScale.Color[2])
 	}
 	return format
 }
 
 // extractCondFmtDataBar provides a function to extract conditional format
 // settings for data bar by given conditional formatting rule.
-func extractCondFmtDataBar(c *xlsxCfRule) ConditionalFormatOptions {
+func (f *File) extractCondFmtDataBar(c *xlsxCfRule) ConditionalFormatOptions {
 	format := ConditionalFormatOptions{Type: "data_bar", Criteria: "="}
 	if c.DataBar != nil {
 		format.MinType = c.DataBar.Cfvo[0].Type
 		format.MaxType = c.DataBar.Cfvo[1].Type
-		format.BarColor = "#" + strings.TrimPrefix(strings.ToUpper(c.DataBar.Color[0].RGB), "FF")
+		format.BarColor = f.getCondFmtColor(c.DataBar.Color[0])
+	}
+	return format
+}
+
+// getCondFmtColor provides a function to get the hex RGB color code with the
+// "#" prefix of the conditional formatting rule by given color, the theme
+// color without RGB color will be resolved to RGB color by the color scheme
+// of the theme.
+func (f *File) getCondFmtColor(clr *xlsxColor) string {
+	if clr.RGB == "" {
+		if color := f.getThemeColor(clr); color != "" {
+			return "#" + color
+		}
+	}
+	rgb := strings.ToUpper(clr.RGB)
+	if len(rgb) == 8 {
+		rgb = rgb[2:]
+	}
+	return "#" + rgb
+}
+
+// extractCondFmtIconSet provides a function to extract conditional format
+// settings for icon set by given conditional formatting rule.
+func extractCondFmtIconSet(c *xlsxCfRule) ConditionalFormatOptions {
+	format := ConditionalFormatOptions{Type: "icon_set", IconStyle: "3TrafficLights1"}
+	if c.IconSet != nil {
+		if c.IconSet.IconSet != "" {
+			format.IconStyle = c.IconSet.IconSet
+		}
+		format.ReverseIcons = c.IconSet.Reverse
+		format.IconsOnly = c.IconSet.ShowValue != nil && !*c.IconSet.ShowValue
 	}
 	return format
 }
@@ -3355,8 +4997,9 @@ func (f *File) GetConditionalFormats(sheet string) (map[string][]ConditionalForm
 		"aboveAverage":    extractCondFmtAboveAverage,
 		"duplicateValues": extractCondFmtDuplicateUniqueValues,
 		"uniqueValues":    extractCondFmtDuplicateUniqueValues,
-		"colorScale":      extractCondFmtColorScale,
-		"dataBar":         extractCondFmtDataBar,
+		"colorScale":      f.extractCondFmtColorScale,
+		"dataBar":         f.extractCondFmtDataBar,
+		"iconSet":         extractCondFmtIconSet,
 		"expression":      extractCondFmtExp,
 	}
 	
@@ -3369,7 +5012,9 @@ func (f *File) GetConditionalFormats(sheet string) (map[string][]ConditionalForm
 		var opts []ConditionalFormatOptions
 		for _, cr := range cf.CfRule {
 			if extractFunc, ok := extractContFmtFunc[cr.Type]; ok {
-				opts = append(opts, extractFunc(cr))
+				opt := extractFunc(cr)
+				opt.StopIfTrue, opt.Priority = cr.StopIfTrue, cr.Priority
+				opts = append(opts, opt)
 			}
 		}
 		conditionalFormats[cf.SQRef] = opts
@@ -3378,14 +5023,21 @@ func (f *File) GetConditionalFormats(sheet string) (map[string][]ConditionalForm
 }
 
 // UnsetConditionalFormat provides a function to unset the conditional format
-// by given worksheet name and range reference.
+// by given worksheet name and range reference. The range reference will be
+// normalized in the same way as the SetConditionalFormat function, so the
+// same range reference which used to set the conditional format can be used
+// to unset it, such as "A5:A1" or "$A$1:$B$2,D1:D3".
 func (f *File) UnsetConditionalFormat(sheet, rangeRef string) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
+	sqref, err := f.prepareCondFmtRange(rangeRef)
+	if err != nil {
+		sqref = rangeRef
+	}
 	for i, cf := range ws.ConditionalFormatting {
-		if cf.SQRef == rangeRef {
+		if cf.SQRef == rangeRef || cf.SQRef == sqref {
 			ws.ConditionalFormatting = append(ws.ConditionalFormatting[:i], ws.ConditionalFormatting[i+1:]...)
 			return nil
 		}
@@ -3435,12 +5087,17 @@ func drawCondFmtTop10(p int, ct string, format *ConditionalFormatOptions) *xlsxC
 // formatting rule for above average and below average by given priority,
 // criteria type and format settings.
 func drawCondFmtAboveAverage(p int, ct string, format *ConditionalFormatOptions) *xlsxCfRule {
-	return &xlsxCfRule{
+	c := &xlsxCfRule{
 		Priority:     p + 1,
 		Type:         validType[format.Type],
 		AboveAverage: &format.AboveAverage,
+		EqualAverage: format.EqualAverage,
 		DxfID:        &format.Format,
 	}
+	if format.StdDev > 0 && format.StdDev <= 3 {
+		c.StdDev = format.StdDev
+	}
+	return c
 }
 
 // drawCondFmtDuplicateUniqueValues provides a function to create conditional
@@ -3505,6 +5162,33 @@ func drawCondFmtDataBar(p int, ct string, format *ConditionalFormatOptions) *xls
 	}
 }
 
+// drawCondFmtIconSet provides a function to create conditional formatting
+// rule for icon set by given priority, criteria type and format settings, the
+// icons will be assigned to the values by the evenly divided percent
+// thresholds. Returns nil if the icon set style is invalid.
+func drawCondFmtIconSet(p int, ct string, format *ConditionalFormatOptions) *xlsxCfRule {
+	style := format.IconStyle
+	if style == "" {
+		style = "3TrafficLights1"
+	}
+	if inStrSlice(iconSetStyles, style, true) == -1 {
+		return nil
+	}
+	iconSet := &xlsxIconSet{IconSet: style, Reverse: format.ReverseIcons}
+	if format.IconsOnly {
+		iconSet.ShowValue = boolPtr(false)
+	}
+	icons := int(style[0] - '0')
+	for i := 0; i < icons; i++ {
+		iconSet.Cfvo = append(iconSet.Cfvo, &xlsxCfvo{Type: "percent", Val: strconv.Itoa(int(math.Round(float64(i) * 100 / float64(icons))))})
+	}
+	return &xlsxCfRule{
+		Priority: p + 1,
+		Type:     validType[format.Type],
+		IconSet:  iconSet,
+	}
+}
+
 // drawCondFmtExp provides a function to create conditional formatting rule
 // for expression by given priority, criteria type and format settings.
 func drawCondFmtExp(p int, ct string, format *ConditionalFormatOptions) *xlsxCfRule {
@@ -1,8 +1,10 @@
 package excel
 
 import (
+	"bytes"
 	"math"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	
@@ -173,6 +175,43 @@ func TestSetConditionalFormat(t *testing.T) {
 		assert.Equal(t, rangeRef, cf[0].SQRef, testCase.label)
 		assert.EqualValues(t, testCase.rules, cf[0].CfRule, testCase.label)
 	}
+	// Test set conditional formats without criteria
+	f := NewFile()
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", []ConditionalFormatOptions{
+		{Type: "duplicate", Format: 1},
+		{Type: "unique", Format: 1},
+		{Type: "top", Format: 1, Value: "10", Percent: true},
+		{Type: "average", Format: 1, StdDev: 2, EqualAverage: true},
+		{Type: "average", Format: 1, StdDev: 4},
+	}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	rules := ws.ConditionalFormatting[0].CfRule
+	assert.Len(t, rules, 5)
+	assert.Equal(t, []string{"duplicateValues", "uniqueValues", "top10", "aboveAverage", "aboveAverage"},
+		[]string{rules[0].Type, rules[1].Type, rules[2].Type, rules[3].Type, rules[4].Type})
+	assert.True(t, rules[2].Percent)
+	assert.Equal(t, 2, rules[3].StdDev)
+	assert.True(t, rules[3].EqualAverage)
+	assert.Zero(t, rules[4].StdDev)
+	// Test set conditional formats with multiple and reversed ranges
+	for rangeRef, expected := range map[string]string{
+		"B10:A1":            "A1:B10",
+		"$A$1:$D$10,F1:I10": "A1:D10 F1:I10",
+		"C3  A1:A2":         "C3 A1:A2",
+		"$E$5":              "E5",
+	} {
+		f = NewFile()
+		assert.NoError(t, f.SetConditionalFormat("Sheet1", rangeRef, []ConditionalFormatOptions{{Type: "formula", Criteria: "$A1>$K$1", Format: 1}}))
+		opts, err := f.GetConditionalFormats("Sheet1")
+		assert.NoError(t, err)
+		assert.Equal(t, []ConditionalFormatOptions{{Type: "formula", Criteria: "$A1>$K$1", Format: 1, Priority: 1}}, opts[expected], rangeRef)
+	}
+	// Test set conditional formats with invalid range reference
+	for _, rangeRef := range []string{"", " , ", "A1:B", "A"} {
+		assert.Error(t, f.SetConditionalFormat("Sheet1", rangeRef, []ConditionalFormatOptions{{Type: "formula", Criteria: "A1>1", Format: 1}}), rangeRef)
+	}
+	assert.Equal(t, ErrParameterInvalid, f.SetConditionalFormat("Sheet1", "", nil))
 }
 
 func TestGetConditionalFormats(t *testing.T) {
@@ -182,23 +221,77 @@ func TestGetConditionalFormats(t *testing.T) {
 		{{Type: "top", Format: 1, Criteria: "=", Value: "6"}},
 		{{Type: "bottom", Format: 1, Criteria: "=", Value: "6"}},
 		{{Type: "average", AboveAverage: true, Format: 1, Criteria: "="}},
+		{{Type: "average", AboveAverage: false, StdDev: 1, EqualAverage: true, Format: 1, Criteria: "="}},
+		{{Type: "top", Format: 1, Criteria: "=", Value: "20", Percent: true}},
 		{{Type: "duplicate", Format: 1, Criteria: "="}},
 		{{Type: "unique", Format: 1, Criteria: "="}},
 		{{Type: "3_color_scale", Criteria: "=", MinType: "num", MidType: "num", MaxType: "num", MinValue: "-10", MidValue: "50", MaxValue: "10", MinColor: "#FF0000", MidColor: "#00FF00", MaxColor: "#0000FF"}},
 		{{Type: "2_color_scale", Criteria: "=", MinType: "num", MaxType: "num", MinColor: "#FF0000", MaxColor: "#0000FF"}},
 		{{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: "#638EC6"}},
 		{{Type: "formula", Format: 1, Criteria: "="}},
+		{{Type: "icon_set", IconStyle: "3Arrows"}},
+		{{Type: "icon_set", IconStyle: "5Rating", ReverseIcons: true, IconsOnly: true}},
+		{{Type: "cell", Format: 1, Criteria: "greater than", Value: "6", StopIfTrue: true, Priority: 2}, {Type: "icon_set", IconStyle: "4Arrows", Priority: 1}},
 	} {
 		f := NewFile()
 		err := f.SetConditionalFormat("Sheet1", "A1:A2", format)
 		assert.NoError(t, err)
 		opts, err := f.GetConditionalFormats("Sheet1")
 		assert.NoError(t, err)
+		for i := range format {
+			if format[i].Priority == 0 {
+				format[i].Priority = i + 1
+			}
+		}
 		assert.Equal(t, format, opts["A1:A2"])
 	}
-	// Test get conditional formats on no exists worksheet
+	// Test set and get icon set conditional formats
 	f := NewFile()
-	_, err := f.GetConditionalFormats("SheetN")
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", []ConditionalFormatOptions{
+		{Type: "icon_set"},
+		{Type: "icon_set", IconStyle: "5Quarters", IconsOnly: true},
+	}))
+	// Test set icon set conditional format with invalid icon style
+	assert.Equal(t, newInvalidOptionalValue("IconStyle", "6Arrows", iconSetStyles), f.SetConditionalFormat("Sheet1", "B1:B10", []ConditionalFormatOptions{
+		{Type: "icon_set"}, {Type: "icon_set", IconStyle: "6Arrows"},
+	}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 1)
+	assert.Len(t, ws.ConditionalFormatting[0].CfRule, 2)
+	assert.Equal(t, &xlsxIconSet{IconSet: "3TrafficLights1", Cfvo: []*xlsxCfvo{
+		{Type: "percent", Val: "0"}, {Type: "percent", Val: "33"}, {Type: "percent", Val: "67"},
+	}}, ws.ConditionalFormatting[0].CfRule[0].IconSet)
+	assert.Equal(t, &xlsxIconSet{IconSet: "5Quarters", ShowValue: boolPtr(false), Cfvo: []*xlsxCfvo{
+		{Type: "percent", Val: "0"}, {Type: "percent", Val: "20"}, {Type: "percent", Val: "40"}, {Type: "percent", Val: "60"}, {Type: "percent", Val: "80"},
+	}}, ws.ConditionalFormatting[0].CfRule[1].IconSet)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetConditionalFormats.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestGetConditionalFormats.xlsx"))
+	assert.NoError(t, err)
+	opts, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []ConditionalFormatOptions{
+		{Type: "icon_set", IconStyle: "3TrafficLights1", Priority: 1},
+		{Type: "icon_set", IconStyle: "5Quarters", IconsOnly: true, Priority: 2},
+	}, opts["A1:A10"])
+	assert.NoError(t, f.Close())
+	// Test set conditional formats with the priorities of part of the rules
+	f = NewFile()
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", []ConditionalFormatOptions{
+		{Type: "icon_set"}, {Type: "icon_set", Priority: 1}, {Type: "icon_set"}, {Type: "icon_set", Priority: 3},
+	}))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for i, expected := range []int{2, 1, 4, 3} {
+		assert.Equal(t, expected, ws.ConditionalFormatting[0].CfRule[i].Priority)
+	}
+	assert.NoError(t, f.Close())
+	// Test get icon set conditional format without icon set settings
+	assert.Equal(t, ConditionalFormatOptions{Type: "icon_set", IconStyle: "3TrafficLights1"}, extractCondFmtIconSet(&xlsxCfRule{Type: "iconSet"}))
+	// Test get conditional formats on no exists worksheet
+	f = NewFile()
+	_, err = f.GetConditionalFormats("SheetN")
 	assert.EqualError(t, err, "sheet SheetN does not exist")
 	// Test get conditional formats with invalid sheet name
 	_, err = f.GetConditionalFormats("Sheet:1")
@@ -213,6 +306,22 @@ func TestUnsetConditionalFormat(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", []ConditionalFormatOptions{{Type: "cell", Criteria: ">", Format: format, Value: "6"}}))
 	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "A1:A10"))
+	// Test unset conditional format by the range reference which used to set
+	// the conditional format before normalized
+	for _, rangeRef := range []string{"A5:A1", "$A$1:$B$2", "A1:B2,D1:D3"} {
+		assert.NoError(t, f.SetConditionalFormat("Sheet1", rangeRef, []ConditionalFormatOptions{{Type: "cell", Criteria: ">", Format: format, Value: "6"}}))
+		assert.NoError(t, f.UnsetConditionalFormat("Sheet1", rangeRef))
+		opts, err := f.GetConditionalFormats("Sheet1")
+		assert.NoError(t, err)
+		assert.Empty(t, opts, rangeRef)
+	}
+	// Test unset conditional format with the range reference which can't be
+	// normalized
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ConditionalFormatting = []*xlsxConditionalFormatting{{SQRef: "A1:B"}}
+	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "A1:B"))
+	assert.Empty(t, ws.ConditionalFormatting)
 	// Test unset conditional format on not exists worksheet
 	assert.EqualError(t, f.UnsetConditionalFormat("SheetN", "A1:A10"), "sheet SheetN does not exist")
 	// Test unset conditional format with invalid sheet name
@@ -329,6 +438,266 @@ func TestNewConditionalStyle(t *testing.T) {
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestGetConditionalStyle(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewStyle(&Style{CustomNumFmt: stringPtr("0.000")})
+	assert.NoError(t, err)
+	numFmt := "0.00%;[Red]-0.00%"
+	for _, style := range []*Style{
+		{
+			Border: []Border{
+				{Type: "left", Color: "#0000FF", Style: 3},
+				{Type: "bottom", Color: "#000000", Style: 6},
+			},
+			Fill:       Fill{Type: "pattern", Color: []string{"#FEC7CE"}, Pattern: 1},
+			Font:       &Font{Bold: true, Family: "Arial", Size: 12, Color: "9A0511"},
+			Alignment:  &Alignment{Horizontal: "center", WrapText: true},
+			Protection: &Protection{Hidden: true},
+			NumFmt:     10,
+		},
+		{Fill: Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 1}},
+		{Fill: Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 4}, CustomNumFmt: &numFmt},
+	} {
+		expected := *style
+		idx, err := f.NewConditionalStyle(style)
+		assert.NoError(t, err)
+		result, err := f.GetConditionalStyle(idx)
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, result)
+	}
+	// Test create conditional styles with custom number formats
+	for _, c := range []struct {
+		numFmt   string
+		expected xlsxNumFmt
+	}{
+		{"0.000", xlsxNumFmt{NumFmtID: 164, FormatCode: "0.000"}},
+		{"#,##0.0", xlsxNumFmt{NumFmtID: 166, FormatCode: "#,##0.0"}},
+	} {
+		idx, err := f.NewConditionalStyle(&Style{CustomNumFmt: stringPtr(c.numFmt)})
+		assert.NoError(t, err)
+		dxf, err := f.decodeDxf(f.Styles.Dxfs.Dxfs[idx])
+		assert.NoError(t, err)
+		assert.Equal(t, &c.expected, dxf.NumFmt)
+	}
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: 0, Value: "6"},
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetConditionalStyle.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestGetConditionalStyle.xlsx"))
+	assert.NoError(t, err)
+	formats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	style, err := f.GetConditionalStyle(formats["A1:A10"][0].Format)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, style.NumFmt)
+	assert.Equal(t, &Font{Bold: true, Family: "Arial", Size: 12, Color: "9A0511"}, style.Font)
+	// Test get conditional style with invalid index
+	for _, idx := range []int{-1, 5} {
+		_, err = f.GetConditionalStyle(idx)
+		assert.EqualError(t, err, newInvalidStyleID(idx).Error())
+	}
+	// Test get and create conditional style with unsupported charset
+	// differential formatting record
+	f.Styles.Dxfs.Dxfs[0].Dxf = string(MacintoshCyrillicCharset)
+	_, err = f.GetConditionalStyle(0)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.NewConditionalStyle(&Style{CustomNumFmt: stringPtr("0.0000")})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test create style with custom number format after creating conditional
+	// style with custom number format
+	f = NewFile()
+	idx, err := f.NewConditionalStyle(&Style{CustomNumFmt: stringPtr("0.000")})
+	assert.NoError(t, err)
+	styleID, err := f.NewStyle(&Style{CustomNumFmt: stringPtr("0.0000")})
+	assert.NoError(t, err)
+	dxf, err := f.decodeDxf(f.Styles.Dxfs.Dxfs[idx])
+	assert.NoError(t, err)
+	assert.Equal(t, 164, dxf.NumFmt.NumFmtID)
+	assert.Equal(t, 165, *f.Styles.CellXfs.Xf[styleID].NumFmtID)
+	// Test the custom number format of conditional style keeps after compact
+	// styles
+	assert.NoError(t, f.CompactStyles())
+	assert.Equal(t, []*xlsxNumFmt{{NumFmtID: 164, FormatCode: "0.000"}}, f.Styles.NumFmts.NumFmt)
+	style, err = f.GetConditionalStyle(idx)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000", *style.CustomNumFmt)
+	assert.NoError(t, f.Close())
+
+	// Test get conditional style with unsupported charset style sheet
+	f = NewFile()
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	_, err = f.GetConditionalStyle(0)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetStyle(t *testing.T) {
+	f := NewFile()
+	numFmt := "0.00%;[Red]-0.00%"
+	for _, style := range []*Style{
+		{
+			Border: []Border{
+				{Type: "left", Color: "#0000FF", Style: 3},
+				{Type: "top", Color: "#00FF00", Style: 4},
+				{Type: "diagonalDown", Color: "#A020F0", Style: 7},
+			},
+			Fill:       Fill{Type: "pattern", Color: []string{"#E0EBF5"}, Pattern: 1},
+			Font:       &Font{Bold: true, Italic: true, Underline: "double", Family: "Times New Roman", Size: 36, Strike: true, Color: "777777"},
+			Alignment:  &Alignment{Horizontal: "center", Indent: 1, TextRotation: 45, Vertical: "top", WrapText: true},
+			Protection: &Protection{Hidden: true, Locked: false},
+			NumFmt:     14,
+		},
+		{Fill: Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 2}},
+		{Fill: Fill{Type: "gradient", Color: []string{"#FFFFFF", "#E0EBF5"}, Shading: 5}, CustomNumFmt: &numFmt},
+		{Font: &Font{Family: "Arial", Size: 12, ColorTheme: intPtr(4), ColorTint: 0.5}},
+		{Font: &Font{Family: "SimSun", Size: 11, Charset: intPtr(134), Condense: true, Extend: true, Outline: true, Shadow: true, Scheme: "minor"}},
+	} {
+		expected := *style
+		styleID, err := f.NewStyle(style)
+		assert.NoError(t, err)
+		result, err := f.GetStyle(styleID)
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, result)
+		// Test create style by the style definition
+		ID, err := f.NewStyle(result)
+		assert.NoError(t, err)
+		assert.Equal(t, styleID, ID)
+	}
+	// Test get style with the default style and theme colors
+	style, err := f.GetStyle(0)
+	assert.NoError(t, err)
+	assert.Equal(t, &Style{}, style)
+	f.Styles.Fills.Fill = append(f.Styles.Fills.Fill, &xlsxFill{PatternFill: &xlsxPatternFill{PatternType: "solid", BgColor: &xlsxColor{Theme: intPtr(4)}}})
+	f.Styles.Borders.Border = append(f.Styles.Borders.Border, &xlsxBorder{Bottom: xlsxLine{Style: "thin", Color: &xlsxColor{Theme: intPtr(1)}}})
+	f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, xlsxXf{
+		FillID: intPtr(len(f.Styles.Fills.Fill) - 1), BorderID: intPtr(len(f.Styles.Borders.Border) - 1),
+		FontID: intPtr(len(f.Styles.Fonts.Font)), NumFmtID: intPtr(200),
+	})
+	style, err = f.GetStyle(len(f.Styles.CellXfs.Xf) - 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &Style{
+		Border: []Border{{Type: "bottom", Color: "#000000", Style: 1}},
+		Fill:   Fill{Type: "pattern", Color: []string{"#5B9BD5"}, Pattern: 1},
+	}, style)
+	// Test get style with the font color without alpha channel
+	f.Styles.Fonts.Font = append(f.Styles.Fonts.Font, &xlsxFont{Color: &xlsxColor{RGB: "ff0000"}})
+	f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, xlsxXf{FontID: intPtr(len(f.Styles.Fonts.Font) - 1)})
+	style, err = f.GetStyle(len(f.Styles.CellXfs.Xf) - 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "FF0000", style.Font.Color)
+	// Test get style with invalid style ID
+	for _, styleID := range []int{-1, len(f.Styles.CellXfs.Xf)} {
+		_, err = f.GetStyle(styleID)
+		assert.EqualError(t, err, newInvalidStyleID(styleID).Error())
+	}
+	// Test get style with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	_, err = f.GetStyle(0)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestCompactStyles(t *testing.T) {
+	f := NewFile()
+	numFmt, unusedNumFmt := "0.0000", "0.000"
+	bold, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	_, err = f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"#FF0000"}, Pattern: 1}, CustomNumFmt: &unusedNumFmt})
+	assert.NoError(t, err)
+	italic, err := f.NewStyle(&Style{Font: &Font{Italic: true}, Border: []Border{{Type: "left", Color: "#000000", Style: 1}}})
+	assert.NoError(t, err)
+	number, err := f.NewStyle(&Style{CustomNumFmt: &numFmt})
+	assert.NoError(t, err)
+	// Create the duplicate font, number format and cell formats
+	boldXf, numberXf := f.Styles.CellXfs.Xf[bold], f.Styles.CellXfs.Xf[number]
+	font := *f.Styles.Fonts.Font[*boldXf.FontID]
+	f.Styles.Fonts.Font = append(f.Styles.Fonts.Font, &font)
+	f.Styles.NumFmts.NumFmt = append(f.Styles.NumFmts.NumFmt, &xlsxNumFmt{NumFmtID: 300, FormatCode: numFmt})
+	boldXf.FontID, numberXf.NumFmtID = intPtr(len(f.Styles.Fonts.Font)-1), intPtr(300)
+	f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, boldXf, numberXf)
+	dupBold, dupNumber := len(f.Styles.CellXfs.Xf)-2, len(f.Styles.CellXfs.Xf)-1
+
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", bold))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A3", "A3", dupBold))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C1", "C1", number))
+	assert.NoError(t, f.SetRowStyle("Sheet1", 2, 2, italic))
+	assert.NoError(t, f.SetColStyle("Sheet1", "D", dupNumber))
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{Cell{StyleID: dupBold, Value: 1}, Cell{StyleID: 100, Value: 2}}, RowOpts{StyleID: dupNumber}))
+	assert.NoError(t, sw.SetRow("A2", []interface{}{Cell{StyleID: italic, Value: `<c s="1">`}}))
+	assert.NoError(t, sw.Flush())
+
+	assert.NoError(t, f.CompactStyles())
+	assert.Equal(t, 4, f.Styles.CellXfs.Count)
+	assert.Len(t, f.Styles.CellXfs.Xf, 4)
+	assert.Equal(t, 3, f.Styles.Fonts.Count)
+	assert.Equal(t, 2, f.Styles.Fills.Count)
+	assert.Equal(t, 2, f.Styles.Borders.Count)
+	assert.Equal(t, []*xlsxNumFmt{{NumFmtID: 165, FormatCode: numFmt}}, f.Styles.NumFmts.NumFmt)
+	for cell, expected := range map[string]int{"A1": 1, "A3": 1, "C1": 3, "B2": 2} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	styleID, err := f.GetColStyle("Sheet1", "D")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, styleID)
+	for styleID, expected := range []*Style{
+		{},
+		{Font: &Font{Bold: true, Family: "Calibri", Size: 11}},
+		{Font: &Font{Italic: true, Family: "Calibri", Size: 11}, Border: []Border{{Type: "left", Color: "#000000", Style: 1}}},
+		{CustomNumFmt: &numFmt},
+	} {
+		style, err := f.GetStyle(styleID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, style)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCompactStyles.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestCompactStyles.xlsx"))
+	assert.NoError(t, err)
+	for cell, expected := range map[string]int{"A1": 1, "A2": 2} {
+		styleID, err := f.GetCellStyle("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	val, err := f.GetCellValue("Sheet2", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, `<c s="1">`, val)
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, ws.SheetData.Row[0].S)
+	assert.Equal(t, 0, ws.SheetData.Row[0].C[1].S)
+	// Test compact styles again without changes
+	assert.NoError(t, f.CompactStyles())
+	assert.Equal(t, 4, f.Styles.CellXfs.Count)
+	assert.NoError(t, f.Close())
+
+	// Test compact styles with unsupported charset style sheet
+	f = NewFile()
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.CompactStyles(), "XML syntax error on line 1: invalid UTF-8")
+	// Test compact styles with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = nil
+	assert.EqualError(t, f.CompactStyles(), "XML syntax error on line 1: invalid UTF-8")
+	// Test compact styles with the style sheet without cell formats
+	f = NewFile()
+	f.Styles.CellXfs = nil
+	assert.NoError(t, f.CompactStyles())
+}
+
 func TestGetDefaultFont(t *testing.T) {
 	f := NewFile()
 	s, err := f.GetDefaultFont()
@@ -389,6 +758,122 @@ func TestSetCellStyle(t *testing.T) {
 	assert.EqualError(t, f.SetCellStyle("Sheet1", "A1", "A2", 1), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestSetCellNumFmt(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1234.5))
+	assert.NoError(t, f.SetCellNumFmt("Sheet1", "A1", "0.00"))
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234.50", val)
+	styleID, err := f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, NumFmtNumberDecimal, style.NumFmt)
+	assert.Nil(t, style.CustomNumFmt)
+	// Test set number format keeps the other style settings of the cell
+	boldStyle, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 43528))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", boldStyle))
+	assert.NoError(t, f.SetCellNumFmt("Sheet1", "B1", "yyyy-mm-dd"))
+	val, err = f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-03-04", val)
+	styleID, err = f.GetCellStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.True(t, style.Font.Bold)
+	assert.Equal(t, "yyyy-mm-dd", *style.CustomNumFmt)
+	// Test set number format with the same format code reuse the style
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", boldStyle))
+	assert.NoError(t, f.SetCellNumFmt("Sheet1", "B2", "yyyy-mm-dd"))
+	ID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID, ID)
+	// Test set the system locale dependent date number format
+	assert.NoError(t, f.SetCellNumFmt("Sheet1", "C1", "MM-DD-YY"))
+	styleID, err = f.GetCellStyle("Sheet1", "C1")
+	assert.NoError(t, err)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "MM-DD-YY", *style.CustomNumFmt)
+	// Test set number format for the cells in range with different styles
+	italicStyle, err := f.NewStyle(&Style{Font: &Font{Italic: true}, NumFmt: NumFmtPercent})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "E2", "F3", boldStyle))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "F3", "F3", italicStyle))
+	count := len(f.Styles.CellXfs.Xf)
+	assert.NoError(t, f.SetCellNumFmt("Sheet1", "F3:D2", "0.000"))
+	assert.Equal(t, count+3, len(f.Styles.CellXfs.Xf))
+	styleIDs := make(map[string]int)
+	for _, cell := range []string{"D2", "D3", "E2", "E3", "F2", "F3"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		style, err := f.GetStyle(styleID)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.000", *style.CustomNumFmt)
+		styleIDs[cell] = styleID
+	}
+	assert.Equal(t, styleIDs["D2"], styleIDs["D3"])
+	assert.Equal(t, styleIDs["E2"], styleIDs["F2"])
+	assert.Equal(t, styleIDs["E2"], styleIDs["E3"])
+	style, err = f.GetStyle(styleIDs["F3"])
+	assert.NoError(t, err)
+	assert.True(t, style.Font.Italic)
+	assert.Equal(t, 0, style.NumFmt)
+	// Test set number format keeps the cell style of the formatting record
+	f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, xlsxXf{
+		NumFmtID: intPtr(0), FontID: intPtr(0), FillID: intPtr(0), BorderID: intPtr(0), XfID: intPtr(1),
+		Alignment: &xlsxAlignment{Horizontal: "center"},
+	})
+	f.Styles.CellXfs.Count = len(f.Styles.CellXfs.Xf)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "G1", "G1", f.Styles.CellXfs.Count-1))
+	assert.NoError(t, f.SetCellNumFmt("Sheet1", "G1", "0.0"))
+	styleID, err = f.GetCellStyle("Sheet1", "G1")
+	assert.NoError(t, err)
+	assert.Equal(t, f.Styles.CellXfs.Count-1, styleID)
+	xf := f.Styles.CellXfs.Xf[styleID]
+	assert.Equal(t, 1, *xf.XfID)
+	assert.True(t, *xf.ApplyNumberFormat)
+	assert.Equal(t, "center", xf.Alignment.Horizontal)
+	assert.Equal(t, 0, *f.Styles.CellXfs.Xf[styleID-1].NumFmtID)
+	assert.Equal(t, 1, *f.Styles.CellXfs.Xf[styleID-1].XfID)
+	// Test set number format for the empty cells in range with the column
+	// and row styles
+	assert.NoError(t, f.SetColStyle("Sheet1", "H", boldStyle))
+	assert.NoError(t, f.SetRowStyle("Sheet1", 12, 12, italicStyle))
+	assert.NoError(t, f.SetCellNumFmt("Sheet1", "H10:I12", "0.0"))
+	for cell, expected := range map[string]*Style{
+		"H10": {Font: &Font{Bold: true}}, "I11": {}, "I12": {Font: &Font{Italic: true}},
+	} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		style, err := f.GetStyle(styleID)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.0", *style.CustomNumFmt, cell)
+		assert.Equal(t, expected.Font != nil && expected.Font.Bold, style.Font != nil && style.Font.Bold, cell)
+		assert.Equal(t, expected.Font != nil && expected.Font.Italic, style.Font != nil && style.Font.Italic, cell)
+	}
+	// Test get built-in number format ID
+	assert.Equal(t, NumFmtNumberDecimal, getBuiltInNumFmtID("0.00"))
+	assert.Equal(t, -1, getBuiltInNumFmtID("0.0"))
+	// Test new number format style with invalid style ID
+	_, err = f.newNumFmtStyle(-1, "0.0")
+	assert.EqualError(t, err, newInvalidStyleID(-1).Error())
+	// Test set number format with invalid parameters
+	assert.Equal(t, ErrParameterRequired, f.SetCellNumFmt("Sheet1", "A1", ""))
+	assert.EqualError(t, f.SetCellNumFmt("Sheet1", "A1:B", "0"), newCellNameToCoordinatesError("B", newInvalidCellNameError("B")).Error())
+	assert.EqualError(t, f.SetCellNumFmt("SheetN", "A1", "0"), "sheet SheetN does not exist")
+	assert.EqualError(t, f.SetCellNumFmt("Sheet1", "A", "0"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test set number format with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCellNumFmt("Sheet1", "A1", "0"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestGetStyleID(t *testing.T) {
 	f := NewFile()
 	styleID, err := f.getStyleID(&xlsxStyleSheet{}, nil)
@@ -439,3 +924,344 @@ func TestGetNumFmtID(t *testing.T) {
 	assert.NotEqual(t, id1, id2)
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStyleNumFmt.xlsx")))
 }
+
+func TestGetCellNumberFormatCategory(t *testing.T) {
+	f := NewFile()
+	category, err := f.GetCellNumberFormatCategory("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, NumberFormatCategoryGeneral, category)
+	customNumFmt := "[$$-409]#,##0.00"
+	for cell, style := range map[string]*Style{
+		"A1": {NumFmt: 2},
+		"A2": {NumFmt: 14},
+		"A3": {NumFmt: 21},
+		"A4": {NumFmt: 10},
+		"A5": {NumFmt: 49},
+		"A6": {NumFmt: 165},
+		"A7": {NumFmt: 31, Lang: "zh-cn"},
+		"A8": {CustomNumFmt: &customNumFmt},
+	} {
+		styleID, err := f.NewStyle(style)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellStyle("Sheet1", cell, cell, styleID))
+	}
+	for cell, expected := range map[string]NumberFormatCategory{
+		"A1": NumberFormatCategoryNumber,
+		"A2": NumberFormatCategoryDate,
+		"A3": NumberFormatCategoryTime,
+		"A4": NumberFormatCategoryPercentage,
+		"A5": NumberFormatCategoryText,
+		"A6": NumberFormatCategoryCurrency,
+		"A7": NumberFormatCategoryDate,
+		"A8": NumberFormatCategoryCurrency,
+		"A9": NumberFormatCategoryGeneral,
+	} {
+		category, err := f.GetCellNumberFormatCategory("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, category, cell)
+	}
+	// Test get cell number format category with the column style
+	styleID, err := f.NewStyle(&Style{NumFmt: 22})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetColStyle("Sheet1", "B", styleID))
+	category, err = f.GetCellNumberFormatCategory("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, NumberFormatCategoryDate, category)
+	// Test get cell number format category on not exists worksheet
+	_, err = f.GetCellNumberFormatCategory("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get cell number format category with invalid cell reference
+	_, err = f.GetCellNumberFormatCategory("Sheet1", "A")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test get cell number format category with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	_, err = f.GetCellNumberFormatCategory("Sheet1", "A1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestStyleCellsWhere(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, -2, "text", -4.5}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{-6, nil, true}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D3", "A1*-1"))
+	style, err := f.NewStyle(&Style{Font: &Font{Color: "#FF0000"}})
+	assert.NoError(t, err)
+	var visited []string
+	assert.NoError(t, f.StyleCellsWhere("Sheet1", "D3:A1", func(c CellInfo) bool {
+		visited = append(visited, c.Cell)
+		val, err := strconv.ParseFloat(c.Value, 64)
+		return err == nil && val < 0
+	}, style))
+	assert.Equal(t, []string{"A1", "B1", "C1", "D1", "A2", "B2", "C2", "D2", "A3", "B3", "C3", "D3"}, visited)
+	for cell, expected := range map[string]int{"A1": 0, "B1": style, "C1": 0, "D1": style, "A2": 0, "A3": style, "C3": 0, "D3": 0} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	// Test apply style for the blank and formula cells
+	var formulas []string
+	assert.NoError(t, f.StyleCellsWhere("Sheet1", "A1:E4", func(c CellInfo) bool {
+		if c.Formula != "" {
+			formulas = append(formulas, c.Formula)
+		}
+		return c.Type == CellTypeUnset && c.Value == "" && c.Formula == ""
+	}, style))
+	assert.Equal(t, []string{"A1*-1"}, formulas)
+	for cell, expected := range map[string]int{"A2": style, "B3": style, "E4": style, "D3": 0, "C1": 0} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	// Test apply style without matched cells
+	assert.NoError(t, f.StyleCellsWhere("Sheet1", "F1:G2", func(c CellInfo) bool { return false }, style))
+	// Test apply style with invalid parameters
+	assert.Equal(t, ErrParameterRequired, f.StyleCellsWhere("Sheet1", "A1:B2", nil, style))
+	assert.Equal(t, ErrParameterInvalid, f.StyleCellsWhere("Sheet1", "A1", func(c CellInfo) bool { return true }, style))
+	assert.EqualError(t, f.StyleCellsWhere("Sheet1", "A:B1", func(c CellInfo) bool { return true }, style),
+		newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.StyleCellsWhere("SheetN", "A1:B2", func(c CellInfo) bool { return true }, style), "sheet SheetN does not exist")
+	assert.EqualError(t, f.StyleCellsWhere("Sheet1", "A1:B2", func(c CellInfo) bool { return true }, -1), newInvalidStyleID(-1).Error())
+	// Test apply style with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.StyleCellsWhere("Sheet1", "A1:B2", func(c CellInfo) bool { return true }, style), "XML syntax error on line 1: invalid UTF-8")
+	// Test apply style with unsupported charset shared strings table
+	f = NewFile()
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.StyleCellsWhere("Sheet1", "A1:B2", func(c CellInfo) bool { return true }, 0), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestNamedStyles(t *testing.T) {
+	f := NewFile()
+	styles, err := f.GetNamedStyles()
+	assert.NoError(t, err)
+	assert.Equal(t, []NamedStyle{{Name: "Normal", BuiltIn: true, Style: &Style{}}}, styles)
+	good := &Style{
+		Font: &Font{Color: "006100"},
+		Fill: Fill{Type: "pattern", Color: []string{"#C6EFCE"}, Pattern: 1},
+	}
+	assert.NoError(t, f.NewNamedStyle("Good", good))
+	assert.NoError(t, f.NewNamedStyle("Corporate", &Style{Font: &Font{Bold: true, Family: "Arial"}}))
+	assert.NoError(t, f.NewNamedStyle("Empty", nil))
+	// Test create named styles with invalid settings
+	for _, c := range []struct {
+		name  string
+		style *Style
+		err   string
+	}{
+		{"", nil, ErrParameterRequired.Error()},
+		{strings.Repeat("c", MaxFieldLength+1), nil, newFieldLengthError("name").Error()},
+		{"good", nil, ErrExistsNamedStyle.Error()},
+		{"Bad", &Style{Font: &Font{Family: strings.Repeat("s", MaxFontFamilyLength+1)}}, ErrFontLength.Error()},
+	} {
+		assert.EqualError(t, f.NewNamedStyle(c.name, c.style), c.err)
+	}
+
+	assert.NoError(t, f.SetCellNamedStyle("Sheet1", "A1", "B2", "good"))
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *f.Styles.CellXfs.Xf[styleID].XfID)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "006100", style.Font.Color)
+	assert.Equal(t, []string{"#C6EFCE"}, style.Fill.Color)
+	// Test apply the same named style without creating new formatting record
+	assert.NoError(t, f.SetCellNamedStyle("Sheet1", "C1", "C1", "Good"))
+	assert.Len(t, f.Styles.CellXfs.Xf, 2)
+	cellStyleID, err := f.GetCellStyle("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID, cellStyleID)
+	assert.NoError(t, f.SetCellNamedStyle("Sheet1", "D1", "D1", "Normal"))
+	cellStyleID, err = f.GetCellStyle("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cellStyleID)
+	assert.EqualError(t, f.SetCellNamedStyle("Sheet1", "A1", "A1", "Heading 1"), newNoExistNamedStyleError("Heading 1").Error())
+	assert.EqualError(t, f.SetCellNamedStyle("Sheet1", "A", "A1", "Good"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.EqualError(t, f.SetCellNamedStyle("SheetN", "A1", "A1", "Good"), "sheet SheetN does not exist")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestNamedStyles.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestNamedStyles.xlsx"))
+	assert.NoError(t, err)
+	styles, err = f.GetNamedStyles()
+	assert.NoError(t, err)
+	assert.Len(t, styles, 4)
+	assert.Equal(t, NamedStyle{Name: "Good", BuiltIn: true, Style: &Style{
+		Font: &Font{Family: "Calibri", Size: 11, Color: "006100"},
+		Fill: Fill{Type: "pattern", Color: []string{"#C6EFCE"}, Pattern: 1},
+	}}, styles[1])
+	assert.Equal(t, "Corporate", styles[2].Name)
+	assert.False(t, styles[2].BuiltIn)
+	assert.Equal(t, &Font{Bold: true, Family: "Arial", Size: 11}, styles[2].Style.Font)
+	assert.Equal(t, NamedStyle{Name: "Empty", Style: &Style{}}, styles[3])
+	cellStyle := f.Styles.CellStyles.CellStyle[1]
+	assert.Equal(t, 1, cellStyle.XfID)
+	assert.Equal(t, 26, *cellStyle.BuiltInID)
+	assert.True(t, *cellStyle.CustomBuiltIn)
+	assert.Nil(t, f.Styles.CellStyles.CellStyle[2].BuiltInID)
+	// Test get and apply named style with invalid master formatting record
+	f.Styles.CellStyles.CellStyle[3].XfID = 10
+	styles, err = f.GetNamedStyles()
+	assert.NoError(t, err)
+	assert.Equal(t, NamedStyle{Name: "Empty", Style: &Style{}}, styles[3])
+	assert.EqualError(t, f.SetCellNamedStyle("Sheet1", "A1", "A1", "Empty"), newNoExistNamedStyleError("Empty").Error())
+	assert.NoError(t, f.Close())
+
+	// Test create and apply named styles without cell styles definitions
+	f = NewFile()
+	f.Styles.CellStyles, f.Styles.CellStyleXfs, f.Styles.CellXfs = nil, nil, nil
+	styles, err = f.GetNamedStyles()
+	assert.NoError(t, err)
+	assert.Nil(t, styles)
+	assert.EqualError(t, f.SetCellNamedStyle("Sheet1", "A1", "A1", "Normal"), newNoExistNamedStyleError("Normal").Error())
+	assert.NoError(t, f.NewNamedStyle("Normal", nil))
+	assert.NoError(t, f.SetCellNamedStyle("Sheet1", "A1", "A1", "Normal"))
+	assert.Equal(t, 1, f.Styles.CellXfs.Count)
+	assert.Equal(t, 0, *f.Styles.CellXfs.Xf[0].XfID)
+	assert.NoError(t, f.Close())
+
+	// Test named styles with unsupported charset style sheet
+	f = NewFile()
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.NewNamedStyle("Good", nil), "XML syntax error on line 1: invalid UTF-8")
+	f.Styles = nil
+	assert.EqualError(t, f.SetCellNamedStyle("Sheet1", "A1", "A1", "Good"), "XML syntax error on line 1: invalid UTF-8")
+	f.Styles = nil
+	_, err = f.GetNamedStyles()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestExportImportStyles(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetDefaultFont("Arial"))
+	styleID, err := f.NewStyle(&Style{
+		Font:   &Font{Bold: true, Color: "FF0000"},
+		Fill:   Fill{Type: "pattern", Pattern: 1, Color: []string{"FFFF00"}},
+		Border: []Border{{Type: "left", Color: "0000FF", Style: BorderStyleThin}},
+		NumFmt: NumFmtPercent,
+	})
+	assert.NoError(t, err)
+	customNumFmt := "0.000"
+	_, err = f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "center"}, CustomNumFmt: &customNumFmt})
+	assert.NoError(t, err)
+	assert.NoError(t, f.NewNamedStyle("Corporate", &Style{Font: &Font{Italic: true}}))
+	_, err = f.NewConditionalStyle(&Style{Font: &Font{Color: "9A0511"}})
+	assert.NoError(t, err)
+	// Test export the duplicate cell formatting records
+	f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, f.Styles.CellXfs.Xf[styleID])
+	f.Styles.CellXfs.Count = len(f.Styles.CellXfs.Xf)
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportStyles(&buf))
+	data := buf.String()
+	assert.Contains(t, data, "\n  \"Version\": 1,\n  \"DefaultFont\": \"Arial\",\n")
+	assert.NoError(t, f.Close())
+
+	f = NewFile()
+	assert.NoError(t, f.ImportStyles(strings.NewReader(data)))
+	font, err := f.GetDefaultFont()
+	assert.NoError(t, err)
+	assert.Equal(t, "Arial", font)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.True(t, style.Font.Bold)
+	assert.Equal(t, NumFmtPercent, style.NumFmt)
+	assert.Equal(t, []Border{{Type: "left", Color: "#0000FF", Style: BorderStyleThin}}, style.Border)
+	assert.Len(t, f.Styles.CellXfs.Xf, 4)
+	assert.Equal(t, f.Styles.CellXfs.Xf[styleID], f.Styles.CellXfs.Xf[3])
+	styles, err := f.GetNamedStyles()
+	assert.NoError(t, err)
+	assert.Len(t, styles, 2)
+	assert.Equal(t, "Corporate", styles[1].Name)
+	style, err = f.GetConditionalStyle(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "9A0511", style.Font.Color)
+	buf.Reset()
+	assert.NoError(t, f.ExportStyles(&buf))
+	assert.Equal(t, data, buf.String())
+	// Test import styles into the workbook which contains the same styles
+	assert.NoError(t, f.ImportStyles(strings.NewReader(data)))
+	assert.Len(t, f.Styles.CellXfs.Xf, 4)
+	styles, err = f.GetNamedStyles()
+	assert.NoError(t, err)
+	assert.Len(t, styles, 2)
+	// Test import styles with invalid style table
+	for _, c := range []struct {
+		data string
+		err  string
+	}{
+		{"{", "unexpected EOF"},
+		{`{"Version":1,"Unknown":1}`, "json: unknown field \"Unknown\""},
+		{`{"Version":2}`, newUnsupportedStyleTableVersionError(2).Error()},
+		{`{"Version":1,"Styles":[null,{"Font":{"Size":500}}]}`, ErrFontSize.Error()},
+		{`{"Version":1,"NamedStyles":[{"Name":""}]}`, ErrParameterRequired.Error()},
+		{`{"Version":1,"ConditionalStyles":[null,{"Font":{"Size":500}}]}`, ErrFontSize.Error()},
+	} {
+		assert.EqualError(t, f.ImportStyles(strings.NewReader(c.data)), c.err)
+	}
+	assert.NoError(t, f.Close())
+	// Test export and import styles with unsupported charset style sheet
+	f = NewFile()
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportStyles(&buf), "XML syntax error on line 1: invalid UTF-8")
+	f.Styles = nil
+	assert.EqualError(t, f.ImportStyles(strings.NewReader(`{"Version":1,"DefaultFont":"Arial"}`)), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test export styles with invalid conditional style
+	f = NewFile()
+	f.Styles.Dxfs = &xlsxDxfs{Dxfs: []*xlsxDxf{nil}}
+	assert.EqualError(t, f.ExportStyles(&buf), newInvalidStyleID(0).Error())
+	assert.NoError(t, f.Close())
+	// Test export styles without the optional fields
+	f = NewFile()
+	f.Styles.Fonts.Font[0].Name = nil
+	buf.Reset()
+	assert.NoError(t, f.ExportStyles(&buf))
+	assert.NotContains(t, buf.String(), "DefaultFont")
+	assert.NotContains(t, buf.String(), "ConditionalStyles")
+	assert.NoError(t, f.Close())
+}
+
+func TestParseBorderStyle(t *testing.T) {
+	borderStyle, err := ParseBorderStyle("mediumDashed")
+	assert.NoError(t, err)
+	assert.Equal(t, BorderStyle(BorderStyleMediumDashed), borderStyle)
+	assert.Equal(t, "mediumDashed", borderStyle.String())
+	assert.Equal(t, "slantDashDot", BorderStyle(BorderStyleSlantDashDot).String())
+	assert.Empty(t, BorderStyle(-1).String())
+	assert.Empty(t, BorderStyle(14).String())
+	_, err = ParseBorderStyle("wavy")
+	assert.EqualError(t, err, newInvalidOptionalValue("Style", "wavy", styleBorders).Error())
+	// Test get style with the border and underline style constants and the
+	// parsed border and underline styles
+	f := NewFile()
+	underline, err := ParseUnderlineStyle("double")
+	assert.NoError(t, err)
+	styleID, err := f.NewStyle(&Style{
+		Border: []Border{
+			{Type: "left", Color: "0000FF", Style: BorderStyleDashDot},
+			{Type: "right", Color: "0000FF", Style: int(borderStyle)},
+		},
+		Font: &Font{Underline: underline.String()},
+	})
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, BorderStyleDashDot, style.Border[0].Style)
+	assert.Equal(t, BorderStyleMediumDashed, style.Border[1].Style)
+	assert.Equal(t, UnderlineDouble, style.Font.Underline)
+	assert.NoError(t, f.Close())
+}
+
+func TestParseUnderlineStyle(t *testing.T) {
+	for _, name := range []string{"single", "double", "sng", "wavyDbl"} {
+		underline, err := ParseUnderlineStyle(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, underline.String())
+	}
+	_, err := ParseUnderlineStyle("triple")
+	assert.EqualError(t, err, newInvalidOptionalValue("Underline", "triple", append(supportedUnderlineTypes, supportedDrawingUnderlineTypes[1:]...)).Error())
+}
@@ -12,11 +12,17 @@
 package excel
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"math"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // parseTableOptions provides a function to parse the format settings of the
@@ -54,13 +60,65 @@ func parseTableOptions(opts *TableOptions) *TableOptions {
 // header row data of the table before calling the AddTable function. Multiple
 // tables range reference that can't have an intersection.
 //
+// The table range can't contain merged cells, the MergedCellsError which
+// specifies the merged cells overlapping the range will be returned if the
+// range contains merged cells, or set UnmergeCells to unmerge these merged
+// cells before adding the table.
+//
 // Name: The name of the table, in the same worksheet name of the table should be unique
 //
-// StyleName: The built-in table style names
+// StyleName: The built-in table style names, or the custom table style name
+// created by the NewTableStyle function
 //
 //	TableStyleLight1 - TableStyleLight21
 //	TableStyleMedium1 - TableStyleMedium28
 //	TableStyleDark1 - TableStyleDark11
+//
+// ShowTotalsRow: Specifies if the last row of the table range is the totals
+// row, the table range must be at least three lines including the header and
+// the totals row, otherwise the ErrTableTotalsRow will be returned.
+//
+// Columns: Specifies the settings of the columns in the table by the column
+// name, such as "B". The TotalsRowFunction specifies the function of the
+// totals row, the formula of the totals row cell will be written with the
+// SUBTOTAL function by the structured reference of the column. The custom
+// function uses the TotalsRowFormula as the formula of the totals row cell.
+// The TotalsRowLabel specifies the text of the totals row cell without
+// function. The CalculatedColumnFormula specifies the formula of the
+// calculated column, which will be filled in each data cell of the column,
+// note that the structured reference of the current row should be written
+// in the form like "Table1[[#This Row],[Price]]". The supported functions of
+// the totals row are:
+//
+//	 Function  | Description
+//	-----------+-----------------------------------------
+//	 average   | The average of the values in the column
+//	 count     | The count of the non-empty cells
+//	 countNums | The count of the numeric cells
+//	 custom    | The custom formula by TotalsRowFormula
+//	 max       | The maximum value in the column
+//	 min       | The minimum value in the column
+//	 none      | No function
+//	 stdDev    | The standard deviation of the values
+//	 sum       | The sum of the values in the column
+//	 var       | The variance of the values in the column
+//
+// For example, create a table of A1:C6 on Sheet1 with the calculated column
+// and the totals row:
+//
+//	err := f.AddTable("Sheet1", "A1:C6", &excelize.TableOptions{
+//	    Name:          "Sales",
+//	    ShowTotalsRow: true,
+//	    Columns: []excelize.TableColumnOptions{
+//	        {Column: "A", TotalsRowLabel: "Total"},
+//	        {Column: "B", TotalsRowFunction: "average"},
+//	        {
+//	            Column:                  "C",
+//	            TotalsRowFunction:       "sum",
+//	            CalculatedColumnFormula: "Sales[[#This Row],[Price]]*2",
+//	        },
+//	    },
+//	})
 func (f *File) AddTable(sheet, rangeRef string, opts *TableOptions) error {
 	options := parseTableOptions(opts)
 	// Coordinate conversion, convert C1:B3 to 2,0,1,2.
@@ -70,9 +128,24 @@ func (f *File) AddTable(sheet, rangeRef string, opts *TableOptions) error {
 	}
 	// Correct table reference range, such correct C1:B3 to B1:C3.
 	_ = sortCoordinates(coordinates)
+	if options.ShowTotalsRow && coordinates[3]-coordinates[1] < 2 {
+		return ErrTableTotalsRow
+	}
+	columns, err := checkTableColumnOptions(coordinates[0], coordinates[2], options.Columns)
+	if err != nil {
+		return err
+	}
+	tableOptions := *options
+	tableOptions.Columns = columns
+	options = &tableOptions
+	if err = f.prepareRangeMergedCells(sheet, coordinates, options.UnmergeCells); err != nil {
+		return err
+	}
 	tableID := f.countTables() + 1
 	sheetRelationshipsTableXML := "../tables/table" + strconv.Itoa(tableID) + ".xml"
 	tableXML := strings.ReplaceAll(sheetRelationshipsTableXML, "..", "xl")
+	// The structured references in the formulas may be changed
+	f.formulaGraph = nil
 	// Add first table for given sheet.
 	sheetXMLPath, _ := f.getSheetXMLPath(sheet)
 	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(sheetXMLPath, "xl/worksheets/") + ".rels"
@@ -87,19 +160,487 @@ func (f *File) AddTable(sheet, rangeRef string, opts *TableOptions) error {
 	return f.addContentTypePart(tableID, "table")
 }
 
-// countTables provides a function to get table files count storage in the
-// folder xl/tables.
+// prepareRangeMergedCells provides a function to check the merged cells which
+// overlap the given range by given worksheet name and range coordinates. The
+// overlapped merged cells will be unmerged if unmerge is true, otherwise the
+// MergedCellsError will be returned.
+func (f *File) prepareRangeMergedCells(sheet string, coordinates []int, unmerge bool) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.MergeCells == nil {
+		return err
+	}
+	var mergedCells []string
+	for _, mergeCell := range ws.MergeCells.Cells {
+		if mergeCell == nil {
+			continue
+		}
+		rect, err := mergeCell.Rect()
+		if err != nil {
+			return err
+		}
+		rect = append([]int{}, rect...)
+		_ = sortCoordinates(rect)
+		if rect[0] <= coordinates[2] && coordinates[0] <= rect[2] && rect[1] <= coordinates[3] && coordinates[1] <= rect[3] {
+			mergedCells = append(mergedCells, mergeCell.Ref)
+		}
+	}
+	if len(mergedCells) == 0 {
+		return err
+	}
+	if !unmerge {
+		ref, _ := f.coordinatesToRangeRef(coordinates)
+		return &MergedCellsError{RangeRef: ref, MergedCells: mergedCells}
+	}
+	for _, ref := range mergedCells {
+		cells := strings.Split(ref, ":")
+		if err = f.UnmergeCell(sheet, cells[0], cells[len(cells)-1]); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// tableTotalsRowFunctions defined the supported functions of the totals row
+// of the table, and the function number of the SUBTOTAL function for each
+// function.
+var tableTotalsRowFunctions = map[string]int{
+	"average": 101, "count": 103, "countNums": 102, "custom": 0, "max": 104,
+	"min": 105, "none": 0, "stdDev": 107, "sum": 109, "var": 110,
+}
+
+// checkTableColumnOptions provides a function to check the settings of the
+// columns in the table by given range columns and column options, returns a
+// copy of the column options with the normalized totals row function names.
+func checkTableColumnOptions(x1, x2 int, opts []TableColumnOptions) ([]TableColumnOptions, error) {
+	var functions []string
+	for function := range tableTotalsRowFunctions {
+		functions = append(functions, function)
+	}
+	sort.Strings(functions)
+	columns := make([]TableColumnOptions, len(opts))
+	copy(columns, opts)
+	for i, column := range opts {
+		col, err := ColumnNameToNumber(column.Column)
+		if err != nil {
+			return nil, err
+		}
+		if col < x1 || col > x2 {
+			return nil, fmt.Errorf("incorrect index of column '%s'", column.Column)
+		}
+		if column.TotalsRowFunction != "" {
+			idx := inStrSlice(functions, column.TotalsRowFunction, false)
+			if idx == -1 {
+				return nil, newInvalidOptionalValue("TotalsRowFunction", column.TotalsRowFunction, functions)
+			}
+			columns[i].TotalsRowFunction = functions[idx]
+		}
+		if (columns[i].TotalsRowFunction == "custom") != (column.TotalsRowFormula != "") {
+			return nil, fmt.Errorf("incorrect totals row formula of column '%s'", column.Column)
+		}
+	}
+	return columns, nil
+}
+
+// escapeTableColumnName provides a function to escape the special characters
+// in the column name for the structured reference of the table.
+func escapeTableColumnName(name string) string {
+	return strings.NewReplacer("'", "''", "[", "'[", "]", "']", "#", "'#").Replace(name)
+}
+
+// setTableColumns provides a function to set the totals row and calculated
+// column settings of the table columns, and write the formulas and labels
+// into the cells by given worksheet name, table name, range coordinates
+// without the totals row and table options.
+func (f *File) setTableColumns(sheet, name string, tableColumns []*xlsxTableColumn, x1, y1, y2 int, opts *TableOptions) error {
+	for _, column := range opts.Columns {
+		col, _ := ColumnNameToNumber(column.Column)
+		tableColumn := tableColumns[col-x1]
+		if formula := strings.TrimPrefix(column.CalculatedColumnFormula, "="); formula != "" {
+			tableColumn.CalculatedColumnFormula = &xlsxTableFormula{Content: formula}
+			for row := y1 + 1; row <= y2; row++ {
+				cell, _ := CoordinatesToCellName(col, row)
+				if err := f.SetCellFormula(sheet, cell, formula); err != nil {
+					return err
+				}
+			}
+		}
+		tableColumn.TotalsRowLabel = column.TotalsRowLabel
+		if column.TotalsRowFunction != "none" {
+			tableColumn.TotalsRowFunction = column.TotalsRowFunction
+		}
+		if tableColumn.TotalsRowFunction == "custom" {
+			tableColumn.TotalsRowFormula = &xlsxTableFormula{Content: strings.TrimPrefix(column.TotalsRowFormula, "=")}
+		}
+		if !opts.ShowTotalsRow {
+			continue
+		}
+		if err := f.setTableTotalsRowCell(sheet, name, tableColumn, col, y2+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTableTotalsRowCell provides a function to write the label or the
+// formula of the totals row function into the totals row cell of the table
+// column by given worksheet name, table name, table column and cell
+// coordinates.
+func (f *File) setTableTotalsRowCell(sheet, name string, tableColumn *xlsxTableColumn, col, row int) error {
+	cell, err := CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	switch tableColumn.TotalsRowFunction {
+	case "", "none":
+		if tableColumn.TotalsRowLabel == "" {
+			return err
+		}
+		return f.SetCellStr(sheet, cell, tableColumn.TotalsRowLabel)
+	case "custom":
+		if tableColumn.TotalsRowFormula == nil {
+			return err
+		}
+		return f.SetCellFormula(sheet, cell, tableColumn.TotalsRowFormula.Content)
+	}
+	return f.SetCellFormula(sheet, cell, fmt.Sprintf("SUBTOTAL(%d,%s[%s])",
+		tableTotalsRowFunctions[tableColumn.TotalsRowFunction], name, escapeTableColumnName(tableColumn.Name)))
+}
+
+// countTables provides a function to get the max index of the table files
+// storage in the folder xl/tables, the index of the new table file should be
+// greater than it.
 func (f *File) countTables() int {
 	count := 0
 	f.Pkg.Range(func(k, v interface{}) bool {
-		if strings.Contains(k.(string), "xl/tables/table") {
-			count++
+		if name := k.(string); strings.HasPrefix(name, "xl/tables/table") {
+			idx, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "xl/tables/table"), ".xml"))
+			if err == nil && idx > count {
+				count = idx
+			}
 		}
 		return true
 	})
 	return count
 }
 
+// tableRef directly maps the table definition, the part name and the
+// relationship ID of the table in the worksheet.
+type tableRef struct {
+	rID      string
+	tableXML string
+	table    *xlsxTable
+}
+
+// getTableRefs provides a function to get the table definitions, the part
+// names and the relationship IDs of the tables in the worksheet by given
+// worksheet name.
+func (f *File) getTableRefs(sheet string) ([]*tableRef, error) {
+	var refs []*tableRef
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.TableParts == nil {
+		return refs, err
+	}
+	for _, tbl := range ws.TableParts.TableParts {
+		target := f.getSheetRelationshipsTargetByID(sheet, tbl.RID)
+		tableXML := strings.ReplaceAll(target, "..", "xl")
+		content, ok := f.Pkg.Load(tableXML)
+		if !ok {
+			continue
+		}
+		t := &xlsxTable{}
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(t); err != nil && err != io.EOF {
+			return refs, err
+		}
+		refs = append(refs, &tableRef{rID: tbl.RID, tableXML: tableXML, table: t})
+	}
+	return refs, nil
+}
+
+// getSheetTables provides a function to get the tables in the worksheet by
+// given worksheet name.
+func (f *File) getSheetTables(sheet string) ([]xlsxTable, error) {
+	var tables []xlsxTable
+	refs, err := f.getTableRefs(sheet)
+	for _, ref := range refs {
+		tables = append(tables, *ref.table)
+	}
+	return tables, err
+}
+
+// getSheetTablesByRels provides a function to get the tables in the worksheet
+// by the table relationships of the worksheet, the worksheet will not be
+// loaded.
+func (f *File) getSheetTablesByRels(sheet string) ([]xlsxTable, error) {
+	var tables []xlsxTable
+	sheetXMLPath, ok := f.getSheetXMLPath(sheet)
+	if !ok {
+		return tables, newNoExistSheetError(sheet)
+	}
+	rels, err := f.relsReader("xl/worksheets/_rels/" + strings.TrimPrefix(sheetXMLPath, "xl/worksheets/") + ".rels")
+	if err != nil || rels == nil {
+		return tables, err
+	}
+	rels.Lock()
+	relationships := append([]xlsxRelationship{}, rels.Relationships...)
+	rels.Unlock()
+	for _, rel := range relationships {
+		if rel.Type != SourceRelationshipTable {
+			continue
+		}
+		content, ok := f.Pkg.Load(strings.ReplaceAll(rel.Target, "..", "xl"))
+		if !ok {
+			continue
+		}
+		t := xlsxTable{}
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(&t); err != nil && err != io.EOF {
+			return tables, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+// getTableRef provides a function to get the worksheet name and the table
+// reference by given table name, the table name is case-insensitive.
+func (f *File) getTableRef(name string) (string, *tableRef, error) {
+	for _, sheet := range f.GetSheetList() {
+		refs, err := f.getTableRefs(sheet)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(sheet).Error() {
+				continue
+			}
+			return sheet, nil, err
+		}
+		for _, ref := range refs {
+			if strings.EqualFold(ref.table.Name, name) {
+				return sheet, ref, err
+			}
+		}
+	}
+	return "", nil, newNoExistTableError(name)
+}
+
+// GetTables provides a function to get the tables in the worksheet by given
+// worksheet name. The Columns of each table only contains the columns with
+// the totals row or calculated column settings. For example, get the names
+// and ranges of the tables on Sheet1:
+//
+//	tables, err := f.GetTables("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, table := range tables {
+//	    fmt.Println(table.Name, table.Range)
+//	}
+func (f *File) GetTables(sheet string) ([]Table, error) {
+	var tables []Table
+	refs, err := f.getTableRefs(sheet)
+	if err != nil {
+		return tables, err
+	}
+	for _, ref := range refs {
+		t := ref.table
+		table := Table{Range: t.Ref, Name: t.Name, ShowTotalsRow: t.TotalsRowCount > 0}
+		if t.TableStyleInfo != nil {
+			table.StyleName = t.TableStyleInfo.Name
+			table.ShowFirstColumn = t.TableStyleInfo.ShowFirstColumn
+			table.ShowLastColumn = t.TableStyleInfo.ShowLastColumn
+			table.ShowRowStripes = boolPtr(t.TableStyleInfo.ShowRowStripes)
+			table.ShowColumnStripes = t.TableStyleInfo.ShowColumnStripes
+		}
+		coordinates, err := rangeRefToCoordinates(t.Ref)
+		if err != nil {
+			return tables, err
+		}
+		_ = sortCoordinates(coordinates)
+		if t.TableColumns != nil {
+			for i, column := range t.TableColumns.TableColumn {
+				if column == nil {
+					continue
+				}
+				opts := TableColumnOptions{TotalsRowFunction: column.TotalsRowFunction, TotalsRowLabel: column.TotalsRowLabel}
+				if column.TotalsRowFormula != nil {
+					opts.TotalsRowFormula = column.TotalsRowFormula.Content
+				}
+				if column.CalculatedColumnFormula != nil {
+					opts.CalculatedColumnFormula = column.CalculatedColumnFormula.Content
+				}
+				if opts == (TableColumnOptions{}) {
+					continue
+				}
+				opts.Column, _ = ColumnNumberToName(coordinates[0] + i)
+				table.Columns = append(table.Columns, opts)
+			}
+		}
+		tables = append(tables, table)
+	}
+	return tables, err
+}
+
+// DeleteTable provides a function to delete the table by given table name,
+// the table part and the relationship of the table will be removed, and the
+// cells of the table will be kept. For example, delete the table named
+// "Table1":
+//
+//	err := f.DeleteTable("Table1")
+func (f *File) DeleteTable(name string) error {
+	sheet, ref, err := f.getTableRef(name)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	for idx, tbl := range ws.TableParts.TableParts {
+		if tbl.RID == ref.rID {
+			ws.TableParts.TableParts = append(ws.TableParts.TableParts[:idx], ws.TableParts.TableParts[idx+1:]...)
+			break
+		}
+	}
+	if ws.TableParts.Count = len(ws.TableParts.TableParts); ws.TableParts.Count == 0 {
+		ws.TableParts = nil
+	}
+	f.deleteSheetRelationships(sheet, ref.rID)
+	f.formulaGraph = nil
+	return f.deletePart(ref.tableXML)
+}
+
+// ResizeTable provides a function to change the range of the table by given
+// table name and the new range reference. The header row of the table will
+// be the first row of the new range, the column definitions will be rebuilt
+// by the header cells, the settings of the columns which have the same name
+// will be kept, and the formulas of the calculated columns will be filled in
+// the data cells of the new range. The totals row of the table will be the
+// last row of the new range if the table has the totals row, the cells of the
+// original totals row will be cleared, and the labels and formulas of the
+// totals row will be written into the new totals row. The new range can't
+// overlap the other tables in the worksheet. For example, extend the table
+// named "Table1" to the range A1:E20:
+//
+//	err := f.ResizeTable("Table1", "A1:E20")
+func (f *File) ResizeTable(name, rangeRef string) error {
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	sheet, ref, err := f.getTableRef(name)
+	if err != nil {
+		return err
+	}
+	t := ref.table
+	x1, y1, x2, y2 := coordinates[0], coordinates[1], coordinates[2], coordinates[3]
+	if y2-y1 < 1+t.TotalsRowCount {
+		return ErrParameterInvalid
+	}
+	if err = f.checkTableOverlap(sheet, ref.tableXML, coordinates); err != nil {
+		return err
+	}
+	if t.TotalsRowCount > 0 {
+		if err = f.clearTableTotalsRow(sheet, t.Ref, y1, y2); err != nil {
+			return err
+		}
+	}
+	if t.Ref, err = f.coordinatesToRangeRef(coordinates); err != nil {
+		return err
+	}
+	if t.AutoFilter != nil {
+		t.AutoFilter.Ref, _ = f.coordinatesToRangeRef([]int{x1, y1, x2, y2 - t.TotalsRowCount})
+	}
+	columns := make(map[string]*xlsxTableColumn)
+	if t.TableColumns != nil {
+		for _, column := range t.TableColumns.TableColumn {
+			if column != nil {
+				columns[column.Name] = column
+			}
+		}
+	}
+	tableColumns, err := f.setTableHeader(sheet, x1, y1, x2)
+	if err != nil {
+		return err
+	}
+	for i, column := range tableColumns {
+		if existing, ok := columns[column.Name]; ok {
+			existing.ID = column.ID
+			tableColumns[i] = existing
+		}
+		column = tableColumns[i]
+		if t.TotalsRowCount > 0 {
+			if err = f.setTableTotalsRowCell(sheet, t.Name, column, x1+i, y2); err != nil {
+				return err
+			}
+		}
+		if column.CalculatedColumnFormula == nil {
+			continue
+		}
+		for row := y1 + 1; row <= y2-t.TotalsRowCount; row++ {
+			cell, _ := CoordinatesToCellName(x1+i, row)
+			if err = f.SetCellFormula(sheet, cell, column.CalculatedColumnFormula.Content); err != nil {
+				return err
+			}
+		}
+	}
+	t.TableColumns = &xlsxTableColumns{Count: len(tableColumns), TableColumn: tableColumns}
+	f.formulaGraph = nil
+	table, _ := xml.Marshal(t)
+	f.saveFileList(ref.tableXML, table)
+	return err
+}
+
+// checkTableOverlap provides a function to check if the given range overlaps
+// the tables except the given table part in the worksheet.
+func (f *File) checkTableOverlap(sheet, tableXML string, coordinates []int) error {
+	refs, err := f.getTableRefs(sheet)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.tableXML == tableXML {
+			continue
+		}
+		rect, err := rangeRefToCoordinates(ref.table.Ref)
+		if err != nil {
+			return err
+		}
+		_ = sortCoordinates(rect)
+		if coordinates[0] <= rect[2] && rect[0] <= coordinates[2] &&
+			coordinates[1] <= rect[3] && rect[1] <= coordinates[3] {
+			rangeRef, _ := f.coordinatesToRangeRef(coordinates)
+			return newTableOverlapError(rangeRef, ref.table.Name)
+		}
+	}
+	return err
+}
+
+// clearTableTotalsRow provides a function to clear the values and formulas of
+// the cells in the original totals row of the table by given worksheet name,
+// the original table range reference, and the header and totals row number
+// of the new range. The cells will be kept if the totals row is not moved or
+// the totals row becomes the header row.
+func (f *File) clearTableTotalsRow(sheet, ref string, y1, y2 int) error {
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	if row := coordinates[3]; row != y2 && row != y1 {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cell, _ := CoordinatesToCellName(col, row)
+			if err = f.SetCellFormula(sheet, cell, ""); err != nil {
+				return err
+			}
+			if err = f.SetCellValue(sheet, cell, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
 // addSheetTable provides a function to add tablePart element to
 // xl/worksheets/sheet%d.xml by given worksheet name and relationship index.
 func (f *File) addSheetTable(sheet string, rID int) error {
@@ -154,25 +695,35 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, opts *Tab
 	if y1 == y2 {
 		y2++
 	}
+	var totalsRowCount int
+	if opts != nil && opts.ShowTotalsRow {
+		totalsRowCount = 1
+	}
 	
 	// Correct table range reference, such correct C1:B3 to B1:C3.
 	ref, err := f.coordinatesToRangeRef([]int{x1, y1, x2, y2})
 	if err != nil {
 		return err
 	}
+	filterRef, _ := f.coordinatesToRangeRef([]int{x1, y1, x2, y2 - totalsRowCount})
 	tableColumns, _ := f.setTableHeader(sheet, x1, y1, x2)
 	name := opts.Name
 	if name == "" {
 		name = "Table" + strconv.Itoa(i)
 	}
+	if err = f.setTableColumns(sheet, name, tableColumns, x1, y1, y2-totalsRowCount, opts); err != nil {
+		return err
+	}
 	t := xlsxTable{
-		XMLNS:       NameSpaceSpreadSheet.Value,
-		ID:          i,
-		Name:        name,
-		DisplayName: name,
-		Ref:         ref,
+		XMLNS:          NameSpaceSpreadSheet.Value,
+		ID:             i,
+		Name:           name,
+		DisplayName:    name,
+		Ref:            ref,
+		TotalsRowCount: totalsRowCount,
+		TotalsRowShown: opts.ShowTotalsRow,
 		AutoFilter: &xlsxAutoFilter{
-			Ref: ref,
+			Ref: filterRef,
 		},
 		TableColumns: &xlsxTableColumns{
 			Count:       len(tableColumns),
@@ -191,6 +742,190 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, opts *Tab
 	return nil
 }
 
+// builtInTableStyles defined the maximum number of the built-in table and
+// PivotTable styles by the style name prefix.
+var builtInTableStyles = map[string]int{
+	"TableStyleLight": 21, "TableStyleMedium": 28, "TableStyleDark": 11,
+	"PivotStyleLight": 28, "PivotStyleMedium": 28, "PivotStyleDark": 28,
+}
+
+// NewTableStyle provides a function to create the custom table style by given
+// style name and the styles of the table areas, the custom table style could
+// be used by the StyleName of the table options for the AddTable function.
+// The style names are case-insensitive and should be unique in the workbook,
+// and can't be the same with the built-in table style names. The styles of
+// the table areas are the same with the NewConditionalStyle function. For
+// example, create a table style with the dark blue header row and light blue
+// row stripes, and use it for the table:
+//
+//	err := f.NewTableStyle("Corporate", &excelize.TableStyleOptions{
+//	    HeaderRow: &excelize.Style{
+//	        Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+//	        Fill: excelize.Fill{Type: "pattern", Color: []string{"1F3864"}, Pattern: 1},
+//	    },
+//	    FirstRowStripe: &excelize.Style{
+//	        Fill: excelize.Fill{Type: "pattern", Color: []string{"D9E1F2"}, Pattern: 1},
+//	    },
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.AddTable("Sheet1", "A1:D5", &excelize.TableOptions{StyleName: "Corporate"})
+func (f *File) NewTableStyle(name string, opts *TableStyleOptions) error {
+	if name == "" || opts == nil {
+		return ErrParameterRequired
+	}
+	if utf8.RuneCountInString(name) > MaxFieldLength {
+		return newFieldLengthError("name")
+	}
+	if isBuiltInTableStyle(name) {
+		return ErrExistsTableStyle
+	}
+	elements := getTableStyleElements(opts)
+	for _, element := range elements {
+		if _, err := parseFormatStyleSet(element.style); err != nil {
+			return err
+		}
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.getTableStyle(name) != nil {
+		return ErrExistsTableStyle
+	}
+	var (
+		tableStyleElements strings.Builder
+		tableStyle         = &xlsxTableStyle{Name: name, Count: len(elements)}
+	)
+	for _, element := range elements {
+		dxfID, err := f.newDxf(s, element.style)
+		if err != nil {
+			return err
+		}
+		output, _ := xml.Marshal(xlsxTableStyleElement{Type: element.typ, DxfID: dxfID})
+		tableStyleElements.Write(output)
+	}
+	tableStyle.TableStyleElement = tableStyleElements.String()
+	s.prepareTableStyles()
+	s.TableStyles.TableStyles = append(s.TableStyles.TableStyles, tableStyle)
+	s.TableStyles.Count = len(s.TableStyles.TableStyles)
+	return err
+}
+
+// SetDefaultTableStyle provides a function to set the default table style of
+// the workbook by given built-in or custom table style name, the default
+// table style will be used for the new tables created in the spreadsheet
+// application. For example, set the custom table style named "Corporate" as
+// the default table style:
+//
+//	err := f.SetDefaultTableStyle("Corporate")
+func (f *File) SetDefaultTableStyle(name string) error {
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	tableStyle := s.getTableStyle(name)
+	if !isBuiltInTableStyle(name) && tableStyle == nil {
+		return newNoExistTableStyleError(name)
+	}
+	if tableStyle != nil {
+		name = tableStyle.Name
+	}
+	s.prepareTableStyles()
+	s.TableStyles.DefaultTableStyle = name
+	return err
+}
+
+// GetDefaultTableStyle provides a function to get the default table style
+// name of the workbook.
+func (f *File) GetDefaultTableStyle() (string, error) {
+	s, err := f.stylesReader()
+	if err != nil || s.TableStyles == nil {
+		return "", err
+	}
+	return s.TableStyles.DefaultTableStyle, err
+}
+
+// getTableStyleElements returns the types and styles of the table style
+// elements in the order of the table style definition by given table style
+// settings, the elements without style will be skipped.
+func getTableStyleElements(opts *TableStyleOptions) []struct {
+	typ   string
+	style *Style
+} {
+	var elements []struct {
+		typ   string
+		style *Style
+	}
+	for _, element := range []struct {
+		typ   string
+		style *Style
+	}{
+		{"wholeTable", opts.WholeTable},
+		{"headerRow", opts.HeaderRow},
+		{"totalRow", opts.TotalRow},
+		{"firstColumn", opts.FirstColumn},
+		{"lastColumn", opts.LastColumn},
+		{"firstRowStripe", opts.FirstRowStripe},
+		{"secondRowStripe", opts.SecondRowStripe},
+		{"firstColumnStripe", opts.FirstColumnStripe},
+		{"secondColumnStripe", opts.SecondColumnStripe},
+		{"firstHeaderCell", opts.FirstHeaderCell},
+		{"lastHeaderCell", opts.LastHeaderCell},
+		{"firstTotalCell", opts.FirstTotalCell},
+		{"lastTotalCell", opts.LastTotalCell},
+	} {
+		if element.style != nil {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// isBuiltInTableStyle returns whether the given name is a built-in table or
+// PivotTable style name.
+func isBuiltInTableStyle(name string) bool {
+	for prefix, count := range builtInTableStyles {
+		if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+			if idx, err := strconv.Atoi(name[len(prefix):]); err == nil && idx >= 1 && idx <= count {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getTableStyle provides a function to get the custom table style by given
+// case-insensitive style name, returns nil if the table style doesn't exist.
+func (s *xlsxStyleSheet) getTableStyle(name string) *xlsxTableStyle {
+	if s.TableStyles == nil {
+		return nil
+	}
+	for _, tableStyle := range s.TableStyles.TableStyles {
+		if strings.EqualFold(tableStyle.Name, name) {
+			return tableStyle
+		}
+	}
+	return nil
+}
+
+// prepareTableStyles provides a function to create the table styles
+// collection of the style sheet if it doesn't exist.
+func (s *xlsxStyleSheet) prepareTableStyles() {
+	if s.TableStyles == nil {
+		s.TableStyles = &xlsxTableStyles{
+			DefaultTableStyle: "TableStyleMedium2",
+			DefaultPivotStyle: "PivotStyleLight16",
+		}
+	}
+}
+
 // AutoFilter provides the method to add auto filter in a worksheet by given
 // worksheet name, range reference and settings. An auto filter in Excel is a
 // way of filtering a 2D range of data based on some simple criteria. For
@@ -209,8 +944,15 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, opts *Tab
 //
 // It isn't sufficient to just specify the filter condition. You must also
 // hide any rows that don't match the filter condition. Rows are hidden using
-// the SetRowVisible function. Excelize can't filter rows automatically since
-// this isn't part of the file format.
+// the SetRowVisible function, or set ApplyFilter to evaluate the filter
+// criteria against the cell values when saving the workbook, and hide the
+// rows which don't match the criteria. The color filters and the dynamic
+// filters relative to the current date, such as "today" and "thisMonth",
+// are not evaluated, and always considered matched. The ApplyFilter option
+// isn't stored in the workbook, the rows will be evaluated on each saving of
+// the File which the auto filter was set, but the criteria will not be
+// evaluated again after the workbook was reopened unless the auto filter set
+// again with the ApplyFilter option.
 //
 // Setting a filter criteria for a column:
 //
@@ -262,6 +1004,59 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, opts *Tab
 //	x     < 2000
 //	col   < 2000
 //	Price < 2000
+//
+// The auto filter range can contain merged cells, set UnmergeCells to unmerge
+// the merged cells overlapping the range before applying the auto filter.
+//
+// Columns defines the structured filter criteria of multiple columns in the
+// auto filter range, which has the same structure as the filter criteria
+// returned by the GetAutoFilter function. Each column can specify one kind of
+// the following filter criteria:
+//
+// Values and Blanks specify the list of values to filter by ("equals any
+// of"), and whether the blank cells are included. DateGroups specify the
+// date grouping filters, which can be used together with Values and Blanks,
+// the Grouping of the date group is one of the following values, and the
+// date parts under the grouping level are required:
+//
+//	year
+//	month
+//	day
+//	hour
+//	minute
+//	second
+//
+// CustomFilters specify up to two custom filter criteria, which are joined
+// by 'and' if the And is true, otherwise joined by 'or'. The operator of the
+// custom filter is one of the following values:
+//
+//	equal
+//	lessThan
+//	lessThanOrEqual
+//	notEqual
+//	greaterThanOrEqual
+//	greaterThan
+//
+// ColorFilter specifies the cell fill color or the font color to filter by.
+// DynamicFilter specifies the dynamic filter type, such as "aboveAverage",
+// "belowAverage", "today", "thisMonth", "Q1" and "M1". Top10 specifies the
+// top or bottom N items or percent to filter by, the value should be between
+// 1 and 500 for items, and between 1 and 100 for percent.
+//
+// For example, filter the column B by the values "East" and "West", the
+// column C by the year 2023 and March 2024, and the column D by the top 10
+// percent values:
+//
+//	err := f.AutoFilter("Sheet1", "B1:D20", &excelize.AutoFilterOptions{
+//	    Columns: []excelize.AutoFilterColumn{
+//	        {Column: "B", Values: []string{"East", "West"}},
+//	        {Column: "C", DateGroups: []excelize.AutoFilterDateGroup{
+//	            {Grouping: "year", Year: 2023},
+//	            {Grouping: "month", Year: 2024, Month: 3},
+//	        }},
+//	        {Column: "D", Top10: &excelize.AutoFilterTop10{Top: true, Percent: true, Value: 10}},
+//	    },
+//	})
 func (f *File) AutoFilter(sheet, rangeRef string, opts *AutoFilterOptions) error {
 	coordinates, err := rangeRefToCoordinates(rangeRef)
 	if err != nil {
@@ -270,6 +1065,11 @@ func (f *File) AutoFilter(sheet, rangeRef string, opts *AutoFilterOptions) error
 	_ = sortCoordinates(coordinates)
 	// Correct reference range, such correct C1:B3 to B1:C3.
 	ref, _ := f.coordinatesToRangeRef(coordinates, true)
+	if opts != nil && opts.UnmergeCells {
+		if err = f.prepareRangeMergedCells(sheet, coordinates, true); err != nil {
+			return err
+		}
+	}
 	filterDB := "_xlnm._FilterDatabase"
 	wb, err := f.workbookReader()
 	if err != nil {
@@ -314,18 +1114,39 @@ func (f *File) autoFilter(sheet, ref string, columns, col int, opts *AutoFilterO
 	if err != nil {
 		return err
 	}
-	if ws.SheetPr != nil {
-		ws.SheetPr.FilterMode = true
-	}
-	ws.SheetPr = &xlsxSheetPr{FilterMode: true}
+	ws.prepareSheetPr()
+	ws.SheetPr.FilterMode = true
 	filter := &xlsxAutoFilter{
-		Ref: ref,
+		Ref:         ref,
+		applyFilter: opts != nil && opts.ApplyFilter,
 	}
 	ws.AutoFilter = filter
-	if opts == nil || opts.Column == "" || opts.Expression == "" {
+	if opts == nil {
 		return nil
 	}
-	
+	if opts.Column != "" && opts.Expression != "" {
+		if err = f.autoFilterExpression(filter, columns, col, opts); err != nil {
+			return err
+		}
+	}
+	for _, column := range opts.Columns {
+		filterColumn, err := f.newAutoFilterColumn(columns, col, column)
+		if err != nil {
+			return err
+		}
+		for _, c := range filter.FilterColumn {
+			if c.ColID == filterColumn.ColID {
+				return fmt.Errorf("duplicate filter criteria of column '%s'", column.Column)
+			}
+		}
+		filter.FilterColumn = append(filter.FilterColumn, filterColumn)
+	}
+	return err
+}
+
+// autoFilterExpression provides a function to write the filter criteria of
+// the auto filter column by given filter expression.
+func (f *File) autoFilterExpression(filter *xlsxAutoFilter, columns, col int, opts *AutoFilterOptions) error {
 	fsCol, err := ColumnNameToNumber(opts.Column)
 	if err != nil {
 		return err
@@ -348,10 +1169,238 @@ func (f *File) autoFilter(sheet, ref string, columns, col int, opts *AutoFilterO
 		return err
 	}
 	f.writeAutoFilter(filter, expressions, tokens)
-	ws.AutoFilter = filter
 	return nil
 }
 
+// This section defines the supported values of the auto filter criteria.
+var (
+	autoFilterCustomOperators = []string{
+		"equal", "lessThan", "lessThanOrEqual", "notEqual", "greaterThanOrEqual", "greaterThan",
+	}
+	autoFilterDateGroupings = []string{"year", "month", "day", "hour", "minute", "second"}
+	autoFilterDynamicTypes  = []string{
+		"null", "aboveAverage", "belowAverage", "tomorrow", "today", "yesterday",
+		"nextWeek", "thisWeek", "lastWeek", "nextMonth", "thisMonth", "lastMonth",
+		"nextQuarter", "thisQuarter", "lastQuarter", "nextYear", "thisYear", "lastYear",
+		"yearToDate", "Q1", "Q2", "Q3", "Q4", "M1", "M2", "M3", "M4", "M5", "M6",
+		"M7", "M8", "M9", "M10", "M11", "M12",
+	}
+)
+
+// newAutoFilterColumn provides a function to create the filter column of the
+// auto filter by given number of columns and the first column number of the
+// auto filter range, and the filter criteria of the column.
+func (f *File) newAutoFilterColumn(columns, col int, opts AutoFilterColumn) (*xlsxFilterColumn, error) {
+	fsCol, err := ColumnNameToNumber(opts.Column)
+	if err != nil {
+		return nil, err
+	}
+	offset := fsCol - col
+	if offset < 0 || offset > columns {
+		return nil, fmt.Errorf("incorrect index of column '%s'", opts.Column)
+	}
+	var criteria int
+	for _, ok := range []bool{
+		len(opts.Values) > 0 || opts.Blanks || len(opts.DateGroups) > 0,
+		len(opts.CustomFilters) > 0, opts.ColorFilter != nil,
+		opts.DynamicFilter != "", opts.Top10 != nil,
+	} {
+		if ok {
+			criteria++
+		}
+	}
+	if criteria != 1 {
+		return nil, fmt.Errorf("incorrect number of filter criteria of column '%s'", opts.Column)
+	}
+	filterColumn := &xlsxFilterColumn{ColID: offset}
+	if len(opts.Values) > 0 || opts.Blanks || len(opts.DateGroups) > 0 {
+		filterColumn.Filters = &xlsxFilters{Blank: opts.Blanks}
+		for _, val := range opts.Values {
+			filterColumn.Filters.Filter = append(filterColumn.Filters.Filter, &xlsxFilter{Val: val})
+		}
+		for _, group := range opts.DateGroups {
+			if inStrSlice(autoFilterDateGroupings, group.Grouping, true) == -1 {
+				return nil, newInvalidOptionalValue("Grouping", group.Grouping, autoFilterDateGroupings)
+			}
+			filterColumn.Filters.DateGroupItem = append(filterColumn.Filters.DateGroupItem, &xlsxDateGroupItem{
+				DateTimeGrouping: group.Grouping, Year: group.Year, Month: group.Month, Day: group.Day,
+				Hour: group.Hour, Minute: group.Minute, Second: group.Second,
+			})
+		}
+	}
+	if len(opts.CustomFilters) > 0 {
+		if len(opts.CustomFilters) > 2 {
+			return nil, fmt.Errorf("incorrect number of custom filters of column '%s'", opts.Column)
+		}
+		filterColumn.CustomFilters = &xlsxCustomFilters{And: opts.And}
+		for _, customFilter := range opts.CustomFilters {
+			if inStrSlice(autoFilterCustomOperators, customFilter.Operator, true) == -1 {
+				return nil, newInvalidOptionalValue("Operator", customFilter.Operator, autoFilterCustomOperators)
+			}
+			filterColumn.CustomFilters.CustomFilter = append(filterColumn.CustomFilters.CustomFilter, &xlsxCustomFilter{
+				Operator: customFilter.Operator, Val: customFilter.Value,
+			})
+		}
+	}
+	if opts.ColorFilter != nil {
+		style := &Style{Font: &Font{Color: opts.ColorFilter.Color}}
+		if opts.ColorFilter.CellColor {
+			style = &Style{Fill: Fill{Type: "pattern", Color: []string{opts.ColorFilter.Color}, Pattern: 1}}
+		}
+		dxfID, err := f.NewConditionalStyle(style)
+		if err != nil {
+			return nil, err
+		}
+		filterColumn.ColorFilter = &xlsxColorFilter{CellColor: opts.ColorFilter.CellColor, DxfID: dxfID}
+	}
+	if opts.DynamicFilter != "" {
+		if inStrSlice(autoFilterDynamicTypes, opts.DynamicFilter, true) == -1 {
+			return nil, newInvalidOptionalValue("DynamicFilter", opts.DynamicFilter, autoFilterDynamicTypes)
+		}
+		filterColumn.DynamicFilter = &xlsxDynamicFilter{Type: opts.DynamicFilter}
+	}
+	if opts.Top10 != nil {
+		if maxVal := map[bool]float64{true: 100, false: 500}[opts.Top10.Percent]; opts.Top10.Value < 1 || opts.Top10.Value > maxVal {
+			return nil, fmt.Errorf("incorrect top 10 filter value of column '%s'", opts.Column)
+		}
+		filterColumn.Top10 = &xlsxTop10{Top: opts.Top10.Top, Percent: opts.Top10.Percent, Val: opts.Top10.Value}
+	}
+	return filterColumn, err
+}
+
+// GetAutoFilter provides a function to get the auto filter settings of the
+// worksheet by given worksheet name, including the range reference of the
+// auto filter without the absolute reference symbol and the filter criteria
+// of each filtered column. The Values specifies the selected values of the
+// column, the Blanks specifies whether the blank cells are selected. The
+// CustomFilters specifies up to two custom filter criteria, the operator of
+// the custom filter will be one of the following values, and the And
+// specifies whether the two criteria are joined by 'and':
+//
+//	equal
+//	lessThan
+//	lessThanOrEqual
+//	notEqual
+//	greaterThanOrEqual
+//	greaterThan
+//
+// The ColorFilter specifies the cell fill color or font color to filter by,
+// and the DynamicFilter specifies the type of the dynamic filter, such as
+// "aboveAverage" and "today". The DateGroups specifies the date grouping
+// filters, and the Top10 specifies the top or bottom N items or percent
+// filter. The returned filter criteria could be used in the Columns of the
+// AutoFilterOptions to apply the same auto filter. For example, get the auto filter of Sheet1:
+//
+//	filter, err := f.GetAutoFilter("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, col := range filter.Columns {
+//	    fmt.Println(col.Column, col.Values, col.CustomFilters)
+//	}
+func (f *File) GetAutoFilter(sheet string) (AutoFilterState, error) {
+	var state AutoFilterState
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.AutoFilter == nil {
+		return state, err
+	}
+	state.RangeRef = strings.ReplaceAll(ws.AutoFilter.Ref, "$", "")
+	coordinates, err := rangeRefToCoordinates(ws.AutoFilter.Ref)
+	if err != nil {
+		return state, err
+	}
+	for _, filterColumn := range ws.AutoFilter.FilterColumn {
+		col, err := f.getAutoFilterColumn(coordinates[0], filterColumn)
+		if err != nil {
+			return state, err
+		}
+		state.Columns = append(state.Columns, col)
+	}
+	return state, err
+}
+
+// getAutoFilterColumn provides a function to get the filter criteria of the
+// auto filter column by given first column number of the auto filter range
+// and the filter column settings.
+func (f *File) getAutoFilterColumn(firstCol int, filterColumn *xlsxFilterColumn) (AutoFilterColumn, error) {
+	var col AutoFilterColumn
+	name, err := ColumnNumberToName(firstCol + filterColumn.ColID)
+	if err != nil {
+		return col, err
+	}
+	col.Column = name
+	if filterColumn.Filters != nil {
+		col.Blanks = filterColumn.Filters.Blank
+		for _, filter := range filterColumn.Filters.Filter {
+			col.Values = append(col.Values, filter.Val)
+		}
+		for _, item := range filterColumn.Filters.DateGroupItem {
+			col.DateGroups = append(col.DateGroups, AutoFilterDateGroup{
+				Grouping: item.DateTimeGrouping, Year: item.Year, Month: item.Month, Day: item.Day,
+				Hour: item.Hour, Minute: item.Minute, Second: item.Second,
+			})
+		}
+	}
+	if filterColumn.CustomFilters != nil {
+		col.And = filterColumn.CustomFilters.And
+		for _, customFilter := range filterColumn.CustomFilters.CustomFilter {
+			operator := customFilter.Operator
+			if operator == "" {
+				operator = "equal"
+			}
+			col.CustomFilters = append(col.CustomFilters, AutoFilterCustomFilter{Operator: operator, Value: customFilter.Val})
+		}
+	}
+	if filterColumn.ColorFilter != nil {
+		color, err := f.getAutoFilterColor(filterColumn.ColorFilter)
+		if err != nil {
+			return col, err
+		}
+		col.ColorFilter = &AutoFilterColorFilter{CellColor: filterColumn.ColorFilter.CellColor, Color: color}
+	}
+	if filterColumn.DynamicFilter != nil {
+		col.DynamicFilter = filterColumn.DynamicFilter.Type
+	}
+	if filterColumn.Top10 != nil {
+		col.Top10 = &AutoFilterTop10{Top: filterColumn.Top10.Top, Percent: filterColumn.Top10.Percent, Value: filterColumn.Top10.Val}
+	}
+	return col, err
+}
+
+// getAutoFilterColor provides a function to get the hex RGB color code of the
+// color filter by the differential formatting which the color filter refers
+// to, the fill color will be returned if the color filter filters by the cell
+// color, otherwise the font color will be returned.
+func (f *File) getAutoFilterColor(colorFilter *xlsxColorFilter) (string, error) {
+	s, err := f.stylesReader()
+	if err != nil {
+		return "", err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.Dxfs == nil || colorFilter.DxfID < 0 || len(s.Dxfs.Dxfs) <= colorFilter.DxfID || s.Dxfs.Dxfs[colorFilter.DxfID] == nil {
+		return "", err
+	}
+	dxf, err := f.decodeDxf(s.Dxfs.Dxfs[colorFilter.DxfID])
+	if err != nil {
+		return "", err
+	}
+	if !colorFilter.CellColor {
+		if dxf.Font != nil {
+			return f.getStyleColor(dxf.Font.Color), err
+		}
+		return "", err
+	}
+	if dxf.Fill == nil || dxf.Fill.PatternFill == nil {
+		return "", err
+	}
+	if color := f.getStyleColor(dxf.Fill.PatternFill.BgColor); color != "" {
+		return color, err
+	}
+	return f.getStyleColor(dxf.Fill.PatternFill.FgColor), err
+}
+
 // writeAutoFilter provides a function to check for single or double custom
 // filters as default filters and handle them accordingly.
 func (f *File) writeAutoFilter(filter *xlsxAutoFilter, exp []int, tokens []string) {
@@ -500,3 +1549,230 @@ func (f *File) parseFilterTokens(expression string, tokens []string) ([]int, str
 	}
 	return []int{operator}, token, nil
 }
+
+// applyAutoFilter provides a function to hide the rows which don't match the
+// filter criteria of the auto filter in the worksheet and show the rows which
+// match the criteria, the header row of the auto filter range will not be
+// changed. The color filters and the dynamic filters relative to the current
+// date are not evaluated, and these criteria are always considered matched.
+func (f *File) applyAutoFilter(ws *xlsxWorksheet) error {
+	coordinates, err := rangeRefToCoordinates(ws.AutoFilter.Ref)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	date1904 := wb.WorkbookPr != nil && wb.WorkbookPr.Date1904
+	visible := make([]bool, coordinates[3]-coordinates[1])
+	for i := range visible {
+		visible[i] = true
+	}
+	for _, filterColumn := range ws.AutoFilter.FilterColumn {
+		values, rawValues := make([]string, len(visible)), make([]string, len(visible))
+		var numbers []float64
+		for i := range visible {
+			values[i], rawValues[i] = f.getAutoFilterCellValue(ws, sst, coordinates[0]+filterColumn.ColID, coordinates[1]+i+1)
+			if num, err := strconv.ParseFloat(rawValues[i], 64); err == nil && values[i] != "" {
+				numbers = append(numbers, num)
+			}
+		}
+		for i := range visible {
+			if visible[i] && !matchAutoFilterColumn(filterColumn, values[i], rawValues[i], numbers, date1904) {
+				visible[i] = false
+			}
+		}
+	}
+	for i, ok := range visible {
+		row := coordinates[1] + i + 1
+		if ok && row > len(ws.SheetData.Row) {
+			continue
+		}
+		prepareSheetXML(ws, 0, row)
+		ws.SheetData.Row[row-1].Hidden = !ok
+	}
+	return err
+}
+
+// getAutoFilterCellValue provides a function to get the formatted value and
+// the raw value of the cell by given column and row number.
+func (f *File) getAutoFilterCellValue(ws *xlsxWorksheet, sst *xlsxSST, col, row int) (string, string) {
+	if row > len(ws.SheetData.Row) {
+		return "", ""
+	}
+	cell, _ := CoordinatesToCellName(col, row)
+	for i := range ws.SheetData.Row[row-1].C {
+		if c := &ws.SheetData.Row[row-1].C[i]; c.R == cell {
+			raw, _ := c.getValueFrom(f, sst, true)
+			val, _ := c.getValueFrom(f, sst, false)
+			return val, raw
+		}
+	}
+	return "", ""
+}
+
+// matchAutoFilterColumn provides a function to check if the cell value
+// matches the filter criteria of the auto filter column by given formatted
+// value, raw value and the numeric values of the column.
+func matchAutoFilterColumn(filterColumn *xlsxFilterColumn, val, raw string, numbers []float64, date1904 bool) bool {
+	num, err := strconv.ParseFloat(raw, 64)
+	isNum := err == nil && val != ""
+	if filters := filterColumn.Filters; filters != nil {
+		if val == "" {
+			return filters.Blank
+		}
+		for _, filter := range filters.Filter {
+			if strings.EqualFold(filter.Val, val) {
+				return true
+			}
+		}
+		for _, item := range filters.DateGroupItem {
+			if isNum && matchAutoFilterDateGroup(item, timeFromExcelTime(num, date1904)) {
+				return true
+			}
+		}
+		return false
+	}
+	if customFilters := filterColumn.CustomFilters; customFilters != nil {
+		for _, customFilter := range customFilters.CustomFilter {
+			matched := matchAutoFilterCustomFilter(customFilter, val, raw)
+			if customFilters.And && !matched {
+				return false
+			}
+			if !customFilters.And && matched {
+				return true
+			}
+		}
+		return customFilters.And
+	}
+	if dynamicFilter := filterColumn.DynamicFilter; dynamicFilter != nil {
+		return matchAutoFilterDynamicFilter(dynamicFilter.Type, num, isNum, numbers, date1904)
+	}
+	if top10 := filterColumn.Top10; top10 != nil {
+		if !isNum || len(numbers) == 0 {
+			return false
+		}
+		sorted := append([]float64{}, numbers...)
+		if sort.Float64s(sorted); top10.Top {
+			sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+		}
+		n := int(top10.Val)
+		if top10.Percent {
+			n = int(math.Ceil(top10.Val * float64(len(sorted)) / 100))
+		}
+		if n < 1 {
+			return false
+		}
+		if n > len(sorted) {
+			n = len(sorted)
+		}
+		if top10.Top {
+			return num >= sorted[n-1]
+		}
+		return num <= sorted[n-1]
+	}
+	return true
+}
+
+// matchAutoFilterDateGroup provides a function to check if the date matches
+// the date group item of the auto filter, the date parts under the grouping
+// level of the date group item will be compared.
+func matchAutoFilterDateGroup(item *xlsxDateGroupItem, t time.Time) bool {
+	for _, part := range []struct {
+		grouping string
+		expected int
+		actual   int
+	}{
+		{"year", item.Year, t.Year()},
+		{"month", item.Month, int(t.Month())},
+		{"day", item.Day, t.Day()},
+		{"hour", item.Hour, t.Hour()},
+		{"minute", item.Minute, t.Minute()},
+		{"second", item.Second, t.Second()},
+	} {
+		if part.expected != part.actual {
+			return false
+		}
+		if part.grouping == item.DateTimeGrouping {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAutoFilterCustomFilter provides a function to check if the cell value
+// matches the custom filter criteria. The numeric values are compared
+// numerically, and the other values are compared case-insensitively, the
+// wildcards '*' and '?' are supported in the equal and not equal criteria.
+func matchAutoFilterCustomFilter(customFilter *xlsxCustomFilter, val, raw string) bool {
+	var result int
+	num, err1 := strconv.ParseFloat(raw, 64)
+	criteria, err2 := strconv.ParseFloat(customFilter.Val, 64)
+	if err1 == nil && err2 == nil && val != "" {
+		if num < criteria {
+			result = -1
+		} else if num > criteria {
+			result = 1
+		}
+	} else {
+		lhs, rhs := strings.ToLower(val), strings.ToLower(customFilter.Val)
+		result = strings.Compare(lhs, rhs)
+		if strings.ContainsAny(rhs, "*?") && matchPattern(rhs, lhs) {
+			result = 0
+		}
+	}
+	switch customFilter.Operator {
+	case "lessThan":
+		return result < 0
+	case "lessThanOrEqual":
+		return result <= 0
+	case "notEqual":
+		return result != 0
+	case "greaterThanOrEqual":
+		return result >= 0
+	case "greaterThan":
+		return result > 0
+	}
+	return result == 0
+}
+
+// matchAutoFilterDynamicFilter provides a function to check if the numeric
+// cell value matches the dynamic filter criteria by given dynamic filter type
+// and the numeric values of the column. The dynamic filters relative to the
+// current date are always considered matched.
+func matchAutoFilterDynamicFilter(typ string, num float64, isNum bool, numbers []float64, date1904 bool) bool {
+	switch {
+	case typ == "aboveAverage" || typ == "belowAverage":
+		if !isNum || len(numbers) == 0 {
+			return false
+		}
+		var sum float64
+		for _, n := range numbers {
+			sum += n
+		}
+		if typ == "aboveAverage" {
+			return num > sum/float64(len(numbers))
+		}
+		return num < sum/float64(len(numbers))
+	case len(typ) > 1 && (typ[0] == 'Q' || typ[0] == 'M'):
+		period, err := strconv.Atoi(typ[1:])
+		if err != nil {
+			return true
+		}
+		if !isNum {
+			return false
+		}
+		month := int(timeFromExcelTime(num, date1904).Month())
+		if typ[0] == 'Q' {
+			return (month-1)/3+1 == period
+		}
+		return month == period
+	}
+	return true
+}
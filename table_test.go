@@ -2,8 +2,11 @@ package excel
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 	
 	"github.com/stretchr/testify/assert"
 )
@@ -39,6 +42,374 @@ func TestAddTable(t *testing.T) {
 	assert.EqualError(t, f.addTable("sheet1", "", 1, 1, 0, 0, 0, nil), "invalid cell reference [0, 0]")
 }
 
+func TestAddTableColumns(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Region", "Price", "Qty", "Amount [USD]"}))
+	for row := 2; row <= 4; row++ {
+		cell, err := CoordinatesToCellName(1, row)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &[]interface{}{"East", row * 10, row}))
+	}
+	columns := []TableColumnOptions{
+		{Column: "A", TotalsRowLabel: "Total"},
+		{Column: "B", TotalsRowFunction: "Average"},
+		{Column: "C", TotalsRowFunction: "custom", TotalsRowFormula: "=SUM(Sales[Qty])*2"},
+		{Column: "D", TotalsRowFunction: "sum", CalculatedColumnFormula: "=Sales[[#This Row],[Price]]*Sales[[#This Row],[Qty]]"},
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "A1:D5", &TableOptions{
+		Name:          "Sales",
+		ShowTotalsRow: true,
+		Columns:       columns,
+	}))
+	// Test the column options of the caller will not be changed
+	assert.Equal(t, "Average", columns[1].TotalsRowFunction)
+	for cell, expected := range map[string]string{
+		"B5": "SUBTOTAL(101,Sales[Price])",
+		"C5": "SUM(Sales[Qty])*2",
+		"D2": "Sales[[#This Row],[Price]]*Sales[[#This Row],[Qty]]",
+		"D4": "Sales[[#This Row],[Price]]*Sales[[#This Row],[Qty]]",
+		"D5": "SUBTOTAL(109,Sales[Amount '[USD']])",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	val, err := f.GetCellValue("Sheet1", "A5")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total", val)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddTableColumns.xlsx")))
+	tables, err := f.getSheetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "A1:D5", tables[0].Ref)
+	assert.Equal(t, "A1:D4", tables[0].AutoFilter.Ref)
+	assert.Equal(t, 1, tables[0].TotalsRowCount)
+	assert.True(t, tables[0].TotalsRowShown)
+	assert.Equal(t, []*xlsxTableColumn{
+		{ID: 1, Name: "Region", TotalsRowLabel: "Total"},
+		{ID: 2, Name: "Price", TotalsRowFunction: "average"},
+		{ID: 3, Name: "Qty", TotalsRowFunction: "custom", TotalsRowFormula: &xlsxTableFormula{Content: "SUM(Sales[Qty])*2"}},
+		{ID: 4, Name: "Amount [USD]", TotalsRowFunction: "sum", CalculatedColumnFormula: &xlsxTableFormula{Content: "Sales[[#This Row],[Price]]*Sales[[#This Row],[Qty]]"}},
+	}, tables[0].TableColumns.TableColumn)
+	// Test add table with the totals row which range less than three lines
+	for _, rangeRef := range []string{"F1:G1", "G2:F1"} {
+		assert.Equal(t, ErrTableTotalsRow, f.AddTable("Sheet1", rangeRef, &TableOptions{ShowTotalsRow: true}))
+	}
+	// Test add table with the totals row which range is three lines
+	assert.NoError(t, f.AddTable("Sheet1", "F1:G3", &TableOptions{
+		ShowTotalsRow: true,
+		Columns:       []TableColumnOptions{{Column: "G", TotalsRowFunction: "none"}, {Column: "F", TotalsRowFunction: "count"}},
+	}))
+	tables, err = f.getSheetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "F1:G3", tables[1].Ref)
+	assert.Equal(t, "F1:G2", tables[1].AutoFilter.Ref)
+	assert.Empty(t, tables[1].TableColumns.TableColumn[1].TotalsRowFunction)
+	formula, err := f.GetCellFormula("Sheet1", "F3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUBTOTAL(103,Table2[Column1])", formula)
+	// Test add table without the totals row
+	assert.NoError(t, f.AddTable("Sheet1", "I1:I3", &TableOptions{
+		Columns: []TableColumnOptions{{Column: "I", TotalsRowFunction: "max", TotalsRowLabel: "Max"}},
+	}))
+	tables, err = f.getSheetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "I1:I3", tables[2].Ref)
+	assert.Zero(t, tables[2].TotalsRowCount)
+	assert.Equal(t, "max", tables[2].TableColumns.TableColumn[0].TotalsRowFunction)
+	val, err = f.GetCellValue("Sheet1", "I3")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+	// Test add table with invalid column settings
+	for _, c := range []struct {
+		columns []TableColumnOptions
+		err     string
+	}{
+		{[]TableColumnOptions{{Column: "-"}}, newInvalidColumnNameError("-").Error()},
+		{[]TableColumnOptions{{Column: "C"}}, "incorrect index of column 'C'"},
+		{[]TableColumnOptions{{Column: "A", TotalsRowFunction: "median"}}, newInvalidOptionalValue("TotalsRowFunction", "median", []string{"average", "count", "countNums", "custom", "max", "min", "none", "stdDev", "sum", "var"}).Error()},
+		{[]TableColumnOptions{{Column: "A", TotalsRowFunction: "custom"}}, "incorrect totals row formula of column 'A'"},
+		{[]TableColumnOptions{{Column: "A", TotalsRowFormula: "SUM(1)"}}, "incorrect totals row formula of column 'A'"},
+	} {
+		assert.EqualError(t, f.AddTable("Sheet1", "A10:B12", &TableOptions{ShowTotalsRow: true, Columns: c.columns}), c.err)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestGetTables(t *testing.T) {
+	f := NewFile()
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, tables)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B2", &[]string{"Region", "Sales"}))
+	assert.NoError(t, f.AddTable("Sheet1", "B2:C6", &TableOptions{
+		Name:          "Sales",
+		StyleName:     "TableStyleMedium2",
+		ShowTotalsRow: true,
+		Columns: []TableColumnOptions{
+			{Column: "B", TotalsRowLabel: "Total"},
+			{Column: "C", TotalsRowFunction: "sum", CalculatedColumnFormula: "1"},
+		},
+	}))
+	assert.NoError(t, f.AddTable("Sheet1", "E2:F3", &TableOptions{ShowFirstColumn: true, ShowRowStripes: boolPtr(false)}))
+	tables, err = f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []Table{
+		{
+			Range: "B2:C6", Name: "Sales", StyleName: "TableStyleMedium2", ShowRowStripes: boolPtr(true), ShowTotalsRow: true,
+			Columns: []TableColumnOptions{
+				{Column: "B", TotalsRowLabel: "Total"},
+				{Column: "C", TotalsRowFunction: "sum", CalculatedColumnFormula: "1"},
+			},
+		},
+		{Range: "E2:F3", Name: "Table2", ShowFirstColumn: true, ShowRowStripes: boolPtr(false)},
+	}, tables)
+	// Test get tables with invalid table range reference
+	f.Pkg.Store("xl/tables/table2.xml", []byte(`<table name="Table2" ref="E2:F"><tableColumns count="1"><tableColumn id="1" name="Column1"/></tableColumns></table>`))
+	_, err = f.GetTables("Sheet1")
+	assert.EqualError(t, err, newCellNameToCoordinatesError("F", newInvalidCellNameError("F")).Error())
+	// Test get tables with unsupported charset table
+	f.Pkg.Store("xl/tables/table2.xml", MacintoshCyrillicCharset)
+	_, err = f.GetTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get tables in not exist worksheet
+	_, err = f.GetTables("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
+func TestDeleteTable(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{
+		Type:   Col,
+		Series: []ChartSeries{{Name: "Sheet1!$A$1", Categories: "Sheet1!$B$1:$C$1", Values: "Sheet1!$B$2:$C$2"}},
+	}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1:B3", nil))
+	assert.NoError(t, f.AddTable("Sheet2", "A1:B3", &TableOptions{Name: "Sales"}))
+	assert.NoError(t, f.AddTable("Sheet2", "D1:E3", nil))
+	assert.NoError(t, f.DeleteTable("sales"))
+	tables, err := f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "Table3", tables[0].Name)
+	_, ok := f.Pkg.Load("xl/tables/table2.xml")
+	assert.False(t, ok)
+	// Test add table after deleting the table
+	assert.NoError(t, f.AddTable("Sheet2", "G1:H3", nil))
+	tables, err = f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 2)
+	assert.Equal(t, "Table4", tables[1].Name)
+	assert.NoError(t, f.DeleteTable("Table1"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.TableParts)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteTable.xlsx")))
+	assert.EqualError(t, f.DeleteTable("Table1"), newNoExistTableError("Table1").Error())
+	// Test delete table with unsupported charset table
+	f.Pkg.Store("xl/tables/table3.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.DeleteTable("Table4"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestResizeTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Region", "Price", "Qty"}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1:B4", &TableOptions{
+		Name:          "Sales",
+		ShowTotalsRow: true,
+		Columns: []TableColumnOptions{
+			{Column: "A", TotalsRowLabel: "Total"},
+			{Column: "B", TotalsRowFunction: "sum", CalculatedColumnFormula: "1+1"},
+		},
+	}))
+	assert.NoError(t, f.ResizeTable("SALES", "C6:A1"))
+	tables, err := f.getSheetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:C6", tables[0].Ref)
+	assert.Equal(t, "A1:C5", tables[0].AutoFilter.Ref)
+	assert.Equal(t, []*xlsxTableColumn{
+		{ID: 1, Name: "Region", TotalsRowLabel: "Total"},
+		{ID: 2, Name: "Price", TotalsRowFunction: "sum", CalculatedColumnFormula: &xlsxTableFormula{Content: "1+1"}},
+		{ID: 3, Name: "Qty"},
+	}, tables[0].TableColumns.TableColumn)
+	for cell, expected := range map[string]string{"B2": "1+1", "B5": "1+1", "B6": "SUBTOTAL(109,Sales[Price])", "C6": ""} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	val, err := f.GetCellValue("Sheet1", "A6")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total", val)
+	// Test shrink the table without the totals row
+	assert.NoError(t, f.AddTable("Sheet1", "E1:G3", &TableOptions{Name: "Costs"}))
+	assert.NoError(t, f.ResizeTable("Costs", "E1:E2"))
+	tables, err = f.getSheetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "E1:E2", tables[1].Ref)
+	assert.Equal(t, "E1:E2", tables[1].AutoFilter.Ref)
+	assert.Equal(t, []*xlsxTableColumn{{ID: 1, Name: "Column1"}}, tables[1].TableColumns.TableColumn)
+	// Test grow the table with the totals row, the original totals row should
+	// be cleared
+	assert.NoError(t, f.SetSheetRow("Sheet1", "I1", &[]string{"Item", "Cost", "Note"}))
+	assert.NoError(t, f.AddTable("Sheet1", "I1:K4", &TableOptions{
+		Name:          "Orders",
+		ShowTotalsRow: true,
+		Columns: []TableColumnOptions{
+			{Column: "I", TotalsRowLabel: "Total"},
+			{Column: "J", TotalsRowFunction: "sum"},
+			{Column: "K", TotalsRowFunction: "count"},
+		},
+	}))
+	assert.NoError(t, f.ResizeTable("Orders", "I1:K6"))
+	for cell, expected := range map[string]string{"I4": "", "J4": "", "K4": "", "I6": "Total"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	for cell, expected := range map[string]string{
+		"J4": "", "K4": "", "J6": "SUBTOTAL(109,Orders[Cost])", "K6": "SUBTOTAL(103,Orders[Note])",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	// Test resize table which overlaps another table
+	assert.EqualError(t, f.ResizeTable("Orders", "E1:K6"), newTableOverlapError("E1:K6", "Costs").Error())
+	assert.EqualError(t, f.ResizeTable("Costs", "C5:E7"), newTableOverlapError("C5:E7", "Sales").Error())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestResizeTable.xlsx")))
+	// Test resize table with invalid parameters
+	assert.EqualError(t, f.ResizeTable("Sales", "A1:C"), newCellNameToCoordinatesError("C", newInvalidCellNameError("C")).Error())
+	assert.EqualError(t, f.ResizeTable("Table", "A1:C6"), newNoExistTableError("Table").Error())
+	assert.Equal(t, ErrParameterInvalid, f.ResizeTable("Sales", "A1:C2"))
+	assert.Equal(t, ErrParameterInvalid, f.ResizeTable("Costs", "E1:F1"))
+	assert.NoError(t, f.Close())
+}
+
+func TestRangeMergedCells(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Name", "Region", "Sales"}))
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "A3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "B5", "E5"))
+	assert.NoError(t, f.MergeCell("Sheet1", "G1", "H2"))
+	// Test add table on the range contains merged cells
+	err := f.AddTable("Sheet1", "C4:A1", nil)
+	assert.EqualError(t, err, "range A1:C4 contains merged cells A2:A3")
+	mergedCellsErr, ok := err.(*MergedCellsError)
+	assert.True(t, ok)
+	assert.Equal(t, &MergedCellsError{RangeRef: "A1:C4", MergedCells: []string{"A2:A3"}}, mergedCellsErr)
+	assert.Equal(t, 0, f.countTables())
+	// Test auto filter on the range contains merged cells
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1:C5", nil))
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 3)
+	// Test add table and auto filter with unmerge the merged cells
+	assert.NoError(t, f.AddTable("Sheet1", "A1:C4", &TableOptions{UnmergeCells: true}))
+	assert.NoError(t, f.AutoFilter("Sheet1", "D1:F5", &AutoFilterOptions{UnmergeCells: true}))
+	mergeCells, err = f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "G1:H2", mergeCells[0].GetStartAxis()+":"+mergeCells[0].GetEndAxis())
+	// Test add table on the worksheet with invalid merged cell reference
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).MergeCells.Cells = append(ws.(*xlsxWorksheet).MergeCells.Cells, nil, &xlsxMergeCell{Ref: "J1:J"})
+	assert.EqualError(t, f.AddTable("Sheet1", "J1:K4", nil), newCellNameToCoordinatesError("J", newInvalidCellNameError("J")).Error())
+	assert.NoError(t, f.Close())
+}
+
+func TestNewTableStyle(t *testing.T) {
+	f := NewFile()
+	header := &Style{
+		Font: &Font{Bold: true, Color: "FFFFFF"},
+		Fill: Fill{Type: "pattern", Color: []string{"#1F3864"}, Pattern: 1},
+	}
+	stripe := &Style{Fill: Fill{Type: "pattern", Color: []string{"#D9E1F2"}, Pattern: 1}}
+	assert.NoError(t, f.NewTableStyle("Corporate", &TableStyleOptions{
+		WholeTable:     &Style{Border: []Border{{Type: "top", Color: "1F3864", Style: 1}}},
+		HeaderRow:      header,
+		FirstRowStripe: stripe,
+	}))
+	assert.Equal(t, `<tableStyleElement type="wholeTable" dxfId="0"></tableStyleElement>`+
+		`<tableStyleElement type="headerRow" dxfId="1"></tableStyleElement>`+
+		`<tableStyleElement type="firstRowStripe" dxfId="2"></tableStyleElement>`, f.Styles.TableStyles.TableStyles[0].TableStyleElement)
+	assert.Equal(t, 3, f.Styles.TableStyles.TableStyles[0].Count)
+	style, err := f.GetConditionalStyle(2)
+	assert.NoError(t, err)
+	assert.Equal(t, stripe, style)
+	for i := 1; i <= 5; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i), &[]interface{}{fmt.Sprintf("Col%d", i), i}))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "A1:B5", &TableOptions{StyleName: "Corporate"}))
+	// Test set default table style
+	assert.NoError(t, f.SetDefaultTableStyle("corporate"))
+	name, err := f.GetDefaultTableStyle()
+	assert.NoError(t, err)
+	assert.Equal(t, "Corporate", name)
+	assert.NoError(t, f.SetDefaultTableStyle("TableStyleLight9"))
+	assert.EqualError(t, f.SetDefaultTableStyle("TableStyleLight22"), newNoExistTableStyleError("TableStyleLight22").Error())
+	assert.EqualError(t, f.SetDefaultTableStyle("Branding"), newNoExistTableStyleError("Branding").Error())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestNewTableStyle.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestNewTableStyle.xlsx"))
+	assert.NoError(t, err)
+	name, err = f.GetDefaultTableStyle()
+	assert.NoError(t, err)
+	assert.Equal(t, "TableStyleLight9", name)
+	s, err := f.stylesReader()
+	assert.NoError(t, err)
+	assert.Equal(t, "Corporate", s.TableStyles.TableStyles[0].Name)
+	assert.Equal(t, 1, s.TableStyles.Count)
+	// Test create table style with invalid settings
+	for _, c := range []struct {
+		name string
+		opts *TableStyleOptions
+		err  string
+	}{
+		{"", &TableStyleOptions{}, ErrParameterRequired.Error()},
+		{"Branding", nil, ErrParameterRequired.Error()},
+		{strings.Repeat("s", MaxFieldLength+1), &TableStyleOptions{}, newFieldLengthError("name").Error()},
+		{"CORPORATE", &TableStyleOptions{}, ErrExistsTableStyle.Error()},
+		{"tablestylemedium2", &TableStyleOptions{}, ErrExistsTableStyle.Error()},
+		{"PivotStyleDark28", &TableStyleOptions{}, ErrExistsTableStyle.Error()},
+		{"Branding", &TableStyleOptions{TotalRow: &Style{Font: &Font{Size: MaxFontSize + 1}}}, ErrFontSize.Error()},
+	} {
+		assert.EqualError(t, f.NewTableStyle(c.name, c.opts), c.err)
+	}
+	// Test create table style with unsupported charset differential formatting record
+	f.Styles.Dxfs.Dxfs[0].Dxf = string(MacintoshCyrillicCharset)
+	assert.EqualError(t, f.NewTableStyle("Branding", &TableStyleOptions{TotalRow: &Style{CustomNumFmt: stringPtr("0.0")}}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test create table style and set default table style without table styles
+	f = NewFile()
+	f.Styles.TableStyles = nil
+	name, err = f.GetDefaultTableStyle()
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+	assert.NoError(t, f.SetDefaultTableStyle("TableStyleDark1"))
+	assert.Equal(t, &xlsxTableStyles{DefaultTableStyle: "TableStyleDark1", DefaultPivotStyle: "PivotStyleLight16"}, f.Styles.TableStyles)
+	f.Styles.TableStyles = nil
+	assert.NoError(t, f.NewTableStyle("Branding", &TableStyleOptions{}))
+	assert.Equal(t, &xlsxTableStyles{Count: 1, DefaultTableStyle: "TableStyleMedium2", DefaultPivotStyle: "PivotStyleLight16", TableStyles: []*xlsxTableStyle{{Name: "Branding"}}}, f.Styles.TableStyles)
+	assert.NoError(t, f.Close())
+
+	// Test table styles with unsupported charset style sheet
+	f = NewFile()
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.NewTableStyle("Branding", &TableStyleOptions{}), "XML syntax error on line 1: invalid UTF-8")
+	f.Styles = nil
+	assert.EqualError(t, f.SetDefaultTableStyle("Branding"), "XML syntax error on line 1: invalid UTF-8")
+	f.Styles = nil
+	_, err = f.GetDefaultTableStyle()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestSetTableHeader(t *testing.T) {
 	f := NewFile()
 	_, err := f.setTableHeader("Sheet1", 1, 0, 1)
@@ -77,6 +448,241 @@ func TestAutoFilter(t *testing.T) {
 	assert.EqualError(t, f.AutoFilter("Sheet1", "D4:B1", nil), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestGetAutoFilter(t *testing.T) {
+	f := NewFile()
+	state, err := f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, AutoFilterState{}, state)
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{CodeName: stringPtr("Report")}))
+	assert.NoError(t, f.AutoFilter("Sheet1", "D4:B1", &AutoFilterOptions{Column: "C", Expression: "x == 1 or x == 2"}))
+	opts, err := f.GetSheetProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Report", *opts.CodeName)
+	assert.True(t, *opts.FilterMode)
+	state, err = f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, AutoFilterState{RangeRef: "B1:D4", Columns: []AutoFilterColumn{{Column: "C", Values: []string{"1", "2"}}}}, state)
+	assert.NoError(t, f.AutoFilter("Sheet1", "B1:D4", &AutoFilterOptions{Column: "D", Expression: "x > 1 and x <= 5"}))
+	state, err = f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []AutoFilterColumn{{Column: "D", And: true, CustomFilters: []AutoFilterCustomFilter{
+		{Operator: "greaterThan", Value: "1"}, {Operator: "lessThanOrEqual", Value: "5"},
+	}}}, state.Columns)
+	// Test get auto filter with color, dynamic and blank filters
+	fillStyle, err := f.NewConditionalStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"FFFF00"}, Pattern: 1}})
+	assert.NoError(t, err)
+	fontStyle, err := f.NewConditionalStyle(&Style{Font: &Font{Color: "FF0000"}})
+	assert.NoError(t, err)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.AutoFilter.FilterColumn = []*xlsxFilterColumn{
+		{ColID: 0, ColorFilter: &xlsxColorFilter{CellColor: true, DxfID: fillStyle}},
+		{ColID: 1, ColorFilter: &xlsxColorFilter{DxfID: fontStyle}, DynamicFilter: &xlsxDynamicFilter{Type: "aboveAverage"}},
+		{ColID: 2, Filters: &xlsxFilters{Blank: true}, CustomFilters: &xlsxCustomFilters{CustomFilter: []*xlsxCustomFilter{{Val: "a*"}}}},
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetAutoFilter.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestGetAutoFilter.xlsx"))
+	assert.NoError(t, err)
+	state, err = f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, AutoFilterState{RangeRef: "B1:D4", Columns: []AutoFilterColumn{
+		{Column: "B", ColorFilter: &AutoFilterColorFilter{CellColor: true, Color: "#FFFF00"}},
+		{Column: "C", ColorFilter: &AutoFilterColorFilter{Color: "#FF0000"}, DynamicFilter: "aboveAverage"},
+		{Column: "D", Blanks: true, CustomFilters: []AutoFilterCustomFilter{{Operator: "equal", Value: "a*"}}},
+	}}, state)
+	// Test get auto filter with the color filter which refers to an invalid
+	// differential formatting or a differential formatting without colors
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.AutoFilter.FilterColumn[0].ColorFilter.DxfID = 100
+	ws.AutoFilter.FilterColumn[1].ColorFilter.DxfID = fillStyle
+	ws.AutoFilter.FilterColumn[2].ColorFilter = &xlsxColorFilter{CellColor: true, DxfID: fontStyle}
+	state, err = f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	for _, col := range state.Columns {
+		assert.Empty(t, col.ColorFilter.Color)
+	}
+	// Test get auto filter with invalid range reference and column index
+	ws.AutoFilter.Ref = "B1:D"
+	_, err = f.GetAutoFilter("Sheet1")
+	assert.EqualError(t, err, newCellNameToCoordinatesError("D", newInvalidCellNameError("D")).Error())
+	ws.AutoFilter.Ref = "B1:D4"
+	ws.AutoFilter.FilterColumn[0].ColID = MaxColumns
+	_, err = f.GetAutoFilter("Sheet1")
+	assert.EqualError(t, err, ErrColumnNumber.Error())
+	// Test get auto filter with unsupported charset differential formatting
+	ws.AutoFilter.FilterColumn[0].ColID = 0
+	f.Styles.Dxfs.Dxfs[fontStyle].Dxf = string(MacintoshCyrillicCharset)
+	_, err = f.GetAutoFilter("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get auto filter with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	_, err = f.GetAutoFilter("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get auto filter on not exists worksheet
+	_, err = f.GetAutoFilter("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
+func TestAutoFilterColumns(t *testing.T) {
+	f := NewFile()
+	columns := []AutoFilterColumn{
+		{Column: "B", Values: []string{"East", "West"}, Blanks: true},
+		{Column: "C", DateGroups: []AutoFilterDateGroup{
+			{Grouping: "year", Year: 2023}, {Grouping: "month", Year: 2024, Month: 3},
+		}},
+		{Column: "D", And: true, CustomFilters: []AutoFilterCustomFilter{
+			{Operator: "greaterThan", Value: "10"}, {Operator: "lessThanOrEqual", Value: "50"},
+		}},
+		{Column: "E", ColorFilter: &AutoFilterColorFilter{CellColor: true, Color: "#FFFF00"}},
+		{Column: "F", ColorFilter: &AutoFilterColorFilter{Color: "#FF0000"}},
+		{Column: "G", DynamicFilter: "thisMonth"},
+		{Column: "H", Top10: &AutoFilterTop10{Top: true, Percent: true, Value: 10}},
+	}
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1:H20", &AutoFilterOptions{
+		Column: "A", Expression: "x == 1", Columns: columns,
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAutoFilterColumns.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestAutoFilterColumns.xlsx"))
+	assert.NoError(t, err)
+	state, err := f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:H20", state.RangeRef)
+	assert.Equal(t, append([]AutoFilterColumn{{Column: "A", Values: []string{"1"}}}, columns...), state.Columns)
+	// Test apply the auto filter by the filter criteria read back
+	assert.NoError(t, f.AutoFilter("Sheet1", state.RangeRef, &AutoFilterOptions{Columns: state.Columns}))
+	restored, err := f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, state, restored)
+	// Test apply the auto filter with invalid filter criteria
+	for _, c := range []struct {
+		column AutoFilterColumn
+		err    string
+	}{
+		{AutoFilterColumn{Column: "-", Values: []string{"1"}}, newInvalidColumnNameError("-").Error()},
+		{AutoFilterColumn{Column: "J", Values: []string{"1"}}, "incorrect index of column 'J'"},
+		{AutoFilterColumn{Column: "B"}, "incorrect number of filter criteria of column 'B'"},
+		{AutoFilterColumn{Column: "B", Values: []string{"1"}, DynamicFilter: "today"}, "incorrect number of filter criteria of column 'B'"},
+		{AutoFilterColumn{Column: "B", DateGroups: []AutoFilterDateGroup{{Grouping: "week"}}}, newInvalidOptionalValue("Grouping", "week", autoFilterDateGroupings).Error()},
+		{AutoFilterColumn{Column: "B", CustomFilters: make([]AutoFilterCustomFilter, 3)}, "incorrect number of custom filters of column 'B'"},
+		{AutoFilterColumn{Column: "B", CustomFilters: []AutoFilterCustomFilter{{Operator: "contains"}}}, newInvalidOptionalValue("Operator", "contains", autoFilterCustomOperators).Error()},
+		{AutoFilterColumn{Column: "B", DynamicFilter: "nextDay"}, newInvalidOptionalValue("DynamicFilter", "nextDay", autoFilterDynamicTypes).Error()},
+		{AutoFilterColumn{Column: "B", Top10: &AutoFilterTop10{Percent: true, Value: 101}}, "incorrect top 10 filter value of column 'B'"},
+		{AutoFilterColumn{Column: "B", Top10: &AutoFilterTop10{Value: 0}}, "incorrect top 10 filter value of column 'B'"},
+		{AutoFilterColumn{Column: "B", ColorFilter: &AutoFilterColorFilter{Color: "#FF0000"}, Top10: &AutoFilterTop10{Value: 1}}, "incorrect number of filter criteria of column 'B'"},
+	} {
+		assert.EqualError(t, f.AutoFilter("Sheet1", "A1:H20", &AutoFilterOptions{Columns: []AutoFilterColumn{c.column}}), c.err)
+	}
+	assert.EqualError(t, f.AutoFilter("Sheet1", "A1:H20", &AutoFilterOptions{Columns: []AutoFilterColumn{
+		{Column: "B", Values: []string{"1"}}, {Column: "B", DynamicFilter: "today"},
+	}}), "duplicate filter criteria of column 'B'")
+	assert.EqualError(t, f.AutoFilter("Sheet1", "A1:H20", &AutoFilterOptions{
+		Column: "A", Expression: "x -- y", Columns: columns,
+	}), "unknown operator: --")
+	// Test apply the color filter with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AutoFilter("Sheet1", "A1:H20", &AutoFilterOptions{Columns: columns[3:4]}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestAutoFilterApplyFilter(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Region", "Sales", "Date", "Name"}))
+	for row, values := range [][]interface{}{
+		{"East", 10, time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), "apple"},
+		{"West", 20, time.Date(2023, 5, 3, 0, 0, 0, 0, time.UTC), "banana"},
+		{"East", 30, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), "cherry"},
+		{"North", 40, time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), "apricot"},
+		{nil, 50, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), "grape"},
+	} {
+		cell, err := CoordinatesToCellName(1, row+2)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &values))
+	}
+	for _, c := range []struct {
+		opts     *AutoFilterOptions
+		expected []bool
+	}{
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "A", Values: []string{"east"}, Blanks: true}}}, []bool{true, false, true, false, true}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "C", DateGroups: []AutoFilterDateGroup{{Grouping: "month", Year: 2024, Month: 3}}}}}, []bool{false, false, true, true, false}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "B", And: true, CustomFilters: []AutoFilterCustomFilter{
+			{Operator: "greaterThan", Value: "15"}, {Operator: "lessThanOrEqual", Value: "40"},
+		}}}}, []bool{false, true, true, true, false}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "D", CustomFilters: []AutoFilterCustomFilter{
+			{Operator: "equal", Value: "AP*"}, {Operator: "greaterThanOrEqual", Value: "grape"},
+		}}}}, []bool{true, false, false, true, true}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "D", CustomFilters: []AutoFilterCustomFilter{
+			{Operator: "notEqual", Value: "*an*"}, {Operator: "lessThan", Value: "b"},
+		}, And: true}}}, []bool{true, false, false, true, false}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "B", Top10: &AutoFilterTop10{Top: true, Value: 2}}}}, []bool{false, false, false, true, true}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "B", Top10: &AutoFilterTop10{Percent: true, Value: 40}}}}, []bool{true, true, false, false, false}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "B", DynamicFilter: "aboveAverage"}}}, []bool{false, false, false, true, true}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "B", DynamicFilter: "belowAverage"}}}, []bool{true, true, false, false, false}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "C", DynamicFilter: "M3"}}}, []bool{false, false, true, true, false}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "C", DynamicFilter: "Q1"}}}, []bool{true, false, true, true, false}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "C", DynamicFilter: "today"}}}, []bool{true, true, true, true, true}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{{Column: "A", ColorFilter: &AutoFilterColorFilter{CellColor: true, Color: "FFFF00"}}}}, []bool{true, true, true, true, true}},
+		{&AutoFilterOptions{Columns: []AutoFilterColumn{
+			{Column: "A", Values: []string{"East"}},
+			{Column: "B", CustomFilters: []AutoFilterCustomFilter{{Operator: "greaterThan", Value: "15"}}},
+		}}, []bool{false, false, true, false, false}},
+		{&AutoFilterOptions{Column: "B", Expression: "x > 25"}, []bool{false, false, true, true, true}},
+	} {
+		c.opts.ApplyFilter = true
+		assert.NoError(t, f.AutoFilter("Sheet1", "A1:D6", c.opts))
+		assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAutoFilterApplyFilter.xlsx")))
+		for i, expected := range c.expected {
+			visible, err := f.GetRowVisible("Sheet1", i+2)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, visible, "row %d", i+2)
+		}
+		visible, err := f.GetRowVisible("Sheet1", 1)
+		assert.NoError(t, err)
+		assert.True(t, visible)
+	}
+	// Test apply filter on the rows without cells
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1:D8", &AutoFilterOptions{ApplyFilter: true, Columns: []AutoFilterColumn{{Column: "A", Values: []string{"East"}}}}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAutoFilterApplyFilter.xlsx")))
+	visible, err := f.GetRowVisible("Sheet1", 8)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	assert.NoError(t, f.Close())
+	// Test match the filter criteria with edge cases
+	assert.False(t, matchAutoFilterColumn(&xlsxFilterColumn{Top10: &xlsxTop10{Val: 1}}, "a", "a", nil, false))
+	assert.False(t, matchAutoFilterColumn(&xlsxFilterColumn{Top10: &xlsxTop10{Percent: true}}, "1", "1", []float64{1}, false))
+	assert.True(t, matchAutoFilterColumn(&xlsxFilterColumn{Top10: &xlsxTop10{Top: true, Val: 5}}, "1", "1", []float64{1, 2}, false))
+	assert.False(t, matchAutoFilterColumn(&xlsxFilterColumn{DynamicFilter: &xlsxDynamicFilter{Type: "aboveAverage"}}, "a", "a", nil, false))
+	assert.False(t, matchAutoFilterColumn(&xlsxFilterColumn{DynamicFilter: &xlsxDynamicFilter{Type: "Q1"}}, "a", "a", nil, false))
+	assert.True(t, matchAutoFilterColumn(&xlsxFilterColumn{DynamicFilter: &xlsxDynamicFilter{Type: "Mx"}}, "1", "1", nil, false))
+	assert.False(t, matchAutoFilterDateGroup(&xlsxDateGroupItem{DateTimeGrouping: "week", Year: 2024, Month: 1, Day: 1}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	// Test apply filter with invalid range reference
+	f = NewFile()
+	assert.EqualError(t, f.applyAutoFilter(&xlsxWorksheet{AutoFilter: &xlsxAutoFilter{Ref: "A1:B"}}), newCellNameToCoordinatesError("B", newInvalidCellNameError("B")).Error())
+	// Test apply filter with unsupported charset workbook and shared strings
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.applyAutoFilter(&xlsxWorksheet{AutoFilter: &xlsxAutoFilter{Ref: "A1:B2"}}), "XML syntax error on line 1: invalid UTF-8")
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.applyAutoFilter(&xlsxWorksheet{AutoFilter: &xlsxAutoFilter{Ref: "A1:B2"}}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test save the workbook with the apply filter error
+	f = NewFile()
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1:B2", &AutoFilterOptions{ApplyFilter: true}))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).AutoFilter.Ref = "A1:B"
+	assert.EqualError(t, f.Write(io.Discard), newCellNameToCoordinatesError("B", newInvalidCellNameError("B")).Error())
+	assert.NoError(t, f.Close())
+}
+
 func TestAutoFilterError(t *testing.T) {
 	outFile := filepath.Join("test", "TestAutoFilterError%d.xlsx")
 	f, err := prepareTestBook1()
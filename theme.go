@@ -0,0 +1,304 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GetTheme provides a function to get the theme settings of the workbook,
+// including the color scheme, font scheme and format scheme. For example,
+// get the first accent color and the body font of the workbook:
+//
+//	theme, err := f.GetTheme()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println(theme.ColorScheme.Accent1, theme.FontScheme.MinorFont.Latin)
+func (f *File) GetTheme() (*Theme, error) {
+	theme, err := f.getTheme()
+	if err != nil || theme == nil {
+		return &Theme{}, err
+	}
+	opts := &Theme{
+		Name: theme.Name,
+		ColorScheme: ThemeColorScheme{
+			Name: theme.ThemeElements.ClrScheme.Name,
+		},
+		FontScheme: ThemeFontScheme{
+			Name:      theme.ThemeElements.FontScheme.Name,
+			MajorFont: getThemeFont(&theme.ThemeElements.FontScheme.MajorFont),
+			MinorFont: getThemeFont(&theme.ThemeElements.FontScheme.MinorFont),
+		},
+		FormatScheme: ThemeFormatScheme{
+			Name:                 theme.ThemeElements.FmtScheme.Name,
+			FillStyles:           theme.ThemeElements.FmtScheme.FillStyleLst.FillStyleLst,
+			LineStyles:           theme.ThemeElements.FmtScheme.LnStyleLst.LnStyleLst,
+			EffectStyles:         theme.ThemeElements.FmtScheme.EffectStyleLst.EffectStyleLst,
+			BackgroundFillStyles: theme.ThemeElements.FmtScheme.BgFillStyleLst.BgFillStyleLst,
+		},
+	}
+	colors := getThemeColorSchemeColors(&opts.ColorScheme)
+	for idx, clr := range theme.ThemeElements.ClrScheme.colors() {
+		*colors[idx].value = getSchemeColor(clr)
+	}
+	return opts, err
+}
+
+// SetTheme provides a function to set the theme settings of the workbook,
+// including the color scheme, font scheme and format scheme. The empty
+// settings will keep the current settings of the theme. The colors are
+// specified by the hex RGB color code, and the fill styles, line styles,
+// effect styles and background fill styles of the format scheme are
+// specified by the DrawingML XML fragments. The theme colors of the cell
+// styles will be changed with the color scheme, and the theme fonts of the
+// cell styles will be changed with the font scheme. For example, apply the
+// corporate colors and fonts to the workbook:
+//
+//	err := f.SetTheme(&excelize.Theme{
+//	    Name: "Corporate",
+//	    ColorScheme: excelize.ThemeColorScheme{
+//	        Name:    "Corporate",
+//	        Dark2:   "1F3864",
+//	        Accent1: "C00000",
+//	        Accent2: "FFC000",
+//	    },
+//	    FontScheme: excelize.ThemeFontScheme{
+//	        Name:      "Corporate",
+//	        MajorFont: excelize.ThemeFont{Latin: "Georgia"},
+//	        MinorFont: excelize.ThemeFont{Latin: "Arial"},
+//	    },
+//	})
+func (f *File) SetTheme(opts *Theme) error {
+	if opts == nil {
+		return ErrParameterRequired
+	}
+	colors := getThemeColorSchemeColors(&opts.ColorScheme)
+	for _, clr := range colors {
+		if err := checkThemeColor(clr.name, *clr.value); err != nil {
+			return err
+		}
+	}
+	for _, fragment := range []string{
+		opts.FormatScheme.FillStyles, opts.FormatScheme.LineStyles,
+		opts.FormatScheme.EffectStyles, opts.FormatScheme.BackgroundFillStyles,
+	} {
+		if err := checkThemeXMLFragment(fragment); err != nil {
+			return err
+		}
+	}
+	theme, err := f.prepareTheme()
+	if err != nil {
+		return err
+	}
+	if opts.Name != "" {
+		theme.Name = opts.Name
+	}
+	clrScheme := &theme.ThemeElements.ClrScheme
+	if opts.ColorScheme.Name != "" {
+		clrScheme.Name = opts.ColorScheme.Name
+	}
+	for idx, clr := range clrScheme.colors() {
+		if val := *colors[idx].value; val != "" {
+			*clr = xlsxCTColor{SrgbClr: &attrValString{Val: stringPtr(strings.ToUpper(strings.TrimPrefix(val, "#")))}}
+		}
+	}
+	fontScheme := &theme.ThemeElements.FontScheme
+	if opts.FontScheme.Name != "" {
+		fontScheme.Name = opts.FontScheme.Name
+	}
+	setThemeFont(&fontScheme.MajorFont, opts.FontScheme.MajorFont)
+	setThemeFont(&fontScheme.MinorFont, opts.FontScheme.MinorFont)
+	fmtScheme := &theme.ThemeElements.FmtScheme
+	for val, fragment := range map[*string]string{
+		&fmtScheme.Name:                          opts.FormatScheme.Name,
+		&fmtScheme.FillStyleLst.FillStyleLst:     opts.FormatScheme.FillStyles,
+		&fmtScheme.LnStyleLst.LnStyleLst:         opts.FormatScheme.LineStyles,
+		&fmtScheme.EffectStyleLst.EffectStyleLst: opts.FormatScheme.EffectStyles,
+		&fmtScheme.BgFillStyleLst.BgFillStyleLst: opts.FormatScheme.BackgroundFillStyles,
+	} {
+		if fragment != "" {
+			*val = fragment
+		}
+	}
+	return err
+}
+
+// getTheme provides a function to get the theme of the workbook, returns nil
+// if the workbook doesn't contain the theme part.
+func (f *File) getTheme() (*xlsxTheme, error) {
+	if f.Theme != nil {
+		return f.Theme, nil
+	}
+	theme, err := f.themeReader()
+	if err != nil {
+		return nil, err
+	}
+	f.Theme = theme
+	return f.Theme, err
+}
+
+// prepareTheme provides a function to get the theme of the workbook, and
+// create the theme part by the default theme if the workbook doesn't contain
+// the theme part.
+func (f *File) prepareTheme() (*xlsxTheme, error) {
+	theme, err := f.getTheme()
+	if err != nil || theme != nil {
+		return theme, err
+	}
+	theme = &xlsxTheme{XMLNSa: NameSpaceDrawingML.Value, XMLNSr: SourceRelationship.Value}
+	if err = f.xmlNewDecoder(bytes.NewReader([]byte(templateTheme))).
+		Decode(theme); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err = f.addContentTypePart(0, "theme"); err != nil {
+		return nil, err
+	}
+	f.Theme = theme
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return nil, err
+	}
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipTheme {
+				return f.Theme, err
+			}
+		}
+	}
+	f.addRels(f.getWorkbookRelsPath(), SourceRelationshipTheme, strings.TrimPrefix(defaultXMLPathTheme, "xl/"), "")
+	return f.Theme, err
+}
+
+// colors returns the colors of the color scheme in the order of the theme
+// color index, which used in the color settings of the cell styles.
+func (clrScheme *xlsxColorScheme) colors() []*xlsxCTColor {
+	return []*xlsxCTColor{
+		&clrScheme.Lt1, &clrScheme.Dk1, &clrScheme.Lt2, &clrScheme.Dk2,
+		&clrScheme.Accent1, &clrScheme.Accent2, &clrScheme.Accent3,
+		&clrScheme.Accent4, &clrScheme.Accent5, &clrScheme.Accent6,
+		&clrScheme.Hlink, &clrScheme.FolHlink,
+	}
+}
+
+// getThemeColorSchemeColors returns the names and the pointers of the colors
+// in the color scheme settings in the order of the theme color index.
+func getThemeColorSchemeColors(opts *ThemeColorScheme) []struct {
+	name  string
+	value *string
+} {
+	return []struct {
+		name  string
+		value *string
+	}{
+		{"Light1", &opts.Light1}, {"Dark1", &opts.Dark1},
+		{"Light2", &opts.Light2}, {"Dark2", &opts.Dark2},
+		{"Accent1", &opts.Accent1}, {"Accent2", &opts.Accent2},
+		{"Accent3", &opts.Accent3}, {"Accent4", &opts.Accent4},
+		{"Accent5", &opts.Accent5}, {"Accent6", &opts.Accent6},
+		{"Hyperlink", &opts.Hyperlink}, {"FollowedHyperlink", &opts.FollowedHyperlink},
+	}
+}
+
+// getSchemeColor returns the hex RGB color code of the given color in the
+// color scheme, returns empty string if the color is not specified by the
+// RGB color or system color.
+func getSchemeColor(clr *xlsxCTColor) string {
+	if clr.SrgbClr != nil && clr.SrgbClr.Val != nil {
+		return strings.ToUpper(*clr.SrgbClr.Val)
+	}
+	if clr.SysClr != nil {
+		return strings.ToUpper(clr.SysClr.LastClr)
+	}
+	return ""
+}
+
+// getThemeColor provides a function to get the hex RGB color code of the
+// theme color with tint by given color settings of the cell styles, returns
+// empty string if the color is not a theme color or can't be resolved by the
+// color scheme of the theme.
+func (f *File) getThemeColor(clr *xlsxColor) string {
+	if clr == nil || clr.Theme == nil || f.Theme == nil {
+		return ""
+	}
+	colors := f.Theme.ThemeElements.ClrScheme.colors()
+	if *clr.Theme < 0 || *clr.Theme >= len(colors) {
+		return ""
+	}
+	if val := getSchemeColor(colors[*clr.Theme]); val != "" && checkThemeColor("", val) == nil {
+		return strings.TrimPrefix(ThemeColor(val, clr.Tint), "FF")
+	}
+	return ""
+}
+
+// getThemeFont returns the font settings by given font collection of the
+// font scheme.
+func getThemeFont(fc *xlsxFontCollection) ThemeFont {
+	var font ThemeFont
+	for val, textFont := range map[*string]*xlsxCTTextFont{
+		&font.Latin: fc.Latin, &font.EastAsian: fc.Ea, &font.ComplexScript: fc.Cs,
+	} {
+		if textFont != nil {
+			*val = textFont.Typeface
+		}
+	}
+	for _, scriptFont := range fc.Font {
+		font.Scripts = append(font.Scripts, ThemeScriptFont{Script: scriptFont.Script, Typeface: scriptFont.Typeface})
+	}
+	return font
+}
+
+// setThemeFont provides a function to set the font collection of the font
+// scheme by given font settings. The empty typeface and nil script fonts
+// will keep the current settings.
+func setThemeFont(fc *xlsxFontCollection, font ThemeFont) {
+	for textFont, typeface := range map[**xlsxCTTextFont]string{
+		&fc.Latin: font.Latin, &fc.Ea: font.EastAsian, &fc.Cs: font.ComplexScript,
+	} {
+		if typeface != "" && (*textFont == nil || (*textFont).Typeface != typeface) {
+			*textFont = &xlsxCTTextFont{Typeface: typeface}
+		}
+	}
+	if font.Scripts != nil {
+		fc.Font = nil
+		for _, scriptFont := range font.Scripts {
+			fc.Font = append(fc.Font, xlsxCTSupplementalFont{Script: scriptFont.Script, Typeface: scriptFont.Typeface})
+		}
+	}
+}
+
+// checkThemeColor checks whether the color of the color scheme is a valid hex
+// RGB color code, the empty color is valid.
+func checkThemeColor(name, color string) error {
+	if color == "" {
+		return nil
+	}
+	val := strings.TrimPrefix(color, "#")
+	if _, err := strconv.ParseUint(val, 16, 32); err != nil || len(val) != 6 {
+		return newInvalidThemeColorError(name, color)
+	}
+	return nil
+}
+
+// checkThemeXMLFragment checks whether the DrawingML XML fragment of the
+// format scheme is well-formed, the empty fragment is valid.
+func checkThemeXMLFragment(fragment string) error {
+	if fragment == "" {
+		return nil
+	}
+	return xml.Unmarshal([]byte(`<a:fmt xmlns:a="`+NameSpaceDrawingML.Value+`">`+fragment+`</a:fmt>`), new(struct{}))
+}
@@ -0,0 +1,188 @@
+package excel
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTheme(t *testing.T) {
+	f := NewFile()
+	theme, err := f.GetTheme()
+	assert.NoError(t, err)
+	assert.Equal(t, "Office Theme", theme.Name)
+	assert.Equal(t, ThemeColorScheme{
+		Name: "Office", Dark1: "000000", Light1: "FFFFFF", Dark2: "44546A", Light2: "E7E6E6",
+		Accent1: "5B9BD5", Accent2: "ED7D31", Accent3: "A5A5A5", Accent4: "FFC000", Accent5: "4472C4", Accent6: "70AD47",
+		Hyperlink: "0563C1", FollowedHyperlink: "954F72",
+	}, theme.ColorScheme)
+	assert.Equal(t, "Calibri Light", theme.FontScheme.MajorFont.Latin)
+	assert.Equal(t, "Calibri", theme.FontScheme.MinorFont.Latin)
+	assert.Equal(t, ThemeScriptFont{Script: "Jpan", Typeface: "游ゴシック"}, theme.FontScheme.MinorFont.Scripts[0])
+	assert.Equal(t, "Office", theme.FormatScheme.Name)
+	assert.True(t, strings.HasPrefix(theme.FormatScheme.FillStyles, "<a:solidFill>"))
+
+	fillStyles := `<a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:solidFill><a:schemeClr val="phClr"/></a:solidFill>`
+	assert.NoError(t, f.SetTheme(&Theme{
+		Name: "Corporate",
+		ColorScheme: ThemeColorScheme{
+			Name:    "Corporate",
+			Dark1:   "#1f3864",
+			Accent1: "C00000",
+		},
+		FontScheme: ThemeFontScheme{
+			Name:      "Corporate",
+			MajorFont: ThemeFont{Latin: "Georgia", EastAsian: "SimHei", Scripts: []ThemeScriptFont{{Script: "Hans", Typeface: "SimHei"}}},
+			MinorFont: ThemeFont{Latin: "Arial"},
+		},
+		FormatScheme: ThemeFormatScheme{Name: "Corporate", FillStyles: fillStyles},
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetTheme.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestSetTheme.xlsx"))
+	assert.NoError(t, err)
+	theme, err = f.GetTheme()
+	assert.NoError(t, err)
+	assert.Equal(t, "Corporate", theme.Name)
+	assert.Equal(t, ThemeColorScheme{
+		Name: "Corporate", Dark1: "1F3864", Light1: "FFFFFF", Dark2: "44546A", Light2: "E7E6E6",
+		Accent1: "C00000", Accent2: "ED7D31", Accent3: "A5A5A5", Accent4: "FFC000", Accent5: "4472C4", Accent6: "70AD47",
+		Hyperlink: "0563C1", FollowedHyperlink: "954F72",
+	}, theme.ColorScheme)
+	assert.Equal(t, ThemeFontScheme{
+		Name:      "Corporate",
+		MajorFont: ThemeFont{Latin: "Georgia", EastAsian: "SimHei", Scripts: []ThemeScriptFont{{Script: "Hans", Typeface: "SimHei"}}},
+		MinorFont: theme.FontScheme.MinorFont,
+	}, theme.FontScheme)
+	assert.Equal(t, "Arial", theme.FontScheme.MinorFont.Latin)
+	assert.Len(t, theme.FontScheme.MinorFont.Scripts, 30)
+	assert.Equal(t, "Corporate", theme.FormatScheme.Name)
+	assert.Equal(t, fillStyles, theme.FormatScheme.FillStyles)
+	assert.True(t, strings.HasPrefix(theme.FormatScheme.LineStyles, "<a:ln "))
+
+	// Test set theme with invalid settings
+	for _, c := range []struct {
+		opts *Theme
+		err  string
+	}{
+		{nil, ErrParameterRequired.Error()},
+		{&Theme{ColorScheme: ThemeColorScheme{Accent2: "GGGGGG"}}, newInvalidThemeColorError("Accent2", "GGGGGG").Error()},
+		{&Theme{ColorScheme: ThemeColorScheme{Hyperlink: "#12345"}}, newInvalidThemeColorError("Hyperlink", "#12345").Error()},
+		{&Theme{FormatScheme: ThemeFormatScheme{LineStyles: "<a:ln>"}}, "XML syntax error on line 1: element <ln> closed by </fmt>"},
+	} {
+		assert.EqualError(t, f.SetTheme(c.opts), c.err)
+	}
+	assert.NoError(t, f.Close())
+
+	// Test set theme for the workbook without theme part
+	f = NewFile()
+	f.Theme = nil
+	f.Pkg.Delete(defaultXMLPathTheme)
+	f.Relationships.Delete(f.getWorkbookRelsPath())
+	f.Pkg.Store(f.getWorkbookRelsPath(), []byte(xml.Header+`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`))
+	theme, err = f.GetTheme()
+	assert.NoError(t, err)
+	assert.Equal(t, &Theme{}, theme)
+	assert.NoError(t, f.SetTheme(&Theme{ColorScheme: ThemeColorScheme{Accent1: "C00000"}}))
+	theme, err = f.GetTheme()
+	assert.NoError(t, err)
+	assert.Equal(t, "C00000", theme.ColorScheme.Accent1)
+	assert.Equal(t, "Office Theme", theme.Name)
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	assert.NoError(t, err)
+	assert.Equal(t, xlsxRelationship{ID: "rId2", Type: SourceRelationshipTheme, Target: "theme/theme1.xml"}, rels.Relationships[1])
+	assert.NoError(t, f.SetTheme(&Theme{ColorScheme: ThemeColorScheme{Accent2: "FFC000"}}))
+	assert.Len(t, rels.Relationships, 2)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetTheme2.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestSetTheme2.xlsx"))
+	assert.NoError(t, err)
+	theme, err = f.GetTheme()
+	assert.NoError(t, err)
+	assert.Equal(t, "FFC000", theme.ColorScheme.Accent2)
+	assert.NoError(t, f.Close())
+
+	// Test get and set theme with unsupported charset theme
+	f = NewFile()
+	f.Theme = nil
+	f.Pkg.Store(defaultXMLPathTheme, MacintoshCyrillicCharset)
+	_, err = f.GetTheme()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.SetTheme(&Theme{}), "XML syntax error on line 1: invalid UTF-8")
+	// Test set theme with unsupported charset content types and workbook
+	// relationships
+	f.Pkg.Delete(defaultXMLPathTheme)
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetTheme(&Theme{}), "XML syntax error on line 1: invalid UTF-8")
+	f = NewFile()
+	f.Theme = nil
+	f.Pkg.Delete(defaultXMLPathTheme)
+	f.Relationships.Delete(f.getWorkbookRelsPath())
+	f.Pkg.Store(f.getWorkbookRelsPath(), MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetTheme(&Theme{}), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetThemeColor(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetTheme(&Theme{ColorScheme: ThemeColorScheme{Accent1: "C00000"}}))
+	for _, c := range []struct {
+		clr      *xlsxColor
+		expected string
+	}{
+		{nil, ""},
+		{&xlsxColor{RGB: "FF0000FF"}, ""},
+		{&xlsxColor{Theme: intPtr(0)}, "FFFFFF"},
+		{&xlsxColor{Theme: intPtr(1)}, "000000"},
+		{&xlsxColor{Theme: intPtr(4)}, "C00000"},
+		{&xlsxColor{Theme: intPtr(4), Tint: 0.5}, strings.TrimPrefix(ThemeColor("C00000", 0.5), "FF")},
+		{&xlsxColor{Theme: intPtr(-1)}, ""},
+		{&xlsxColor{Theme: intPtr(12)}, ""},
+	} {
+		assert.Equal(t, c.expected, f.getThemeColor(c.clr))
+	}
+	f.Theme.ThemeElements.ClrScheme.Accent2 = xlsxCTColor{PrstClr: &xlsxInnerXML{}}
+	assert.Empty(t, f.getThemeColor(&xlsxColor{Theme: intPtr(5)}))
+	f.Theme = nil
+	assert.Empty(t, f.getThemeColor(&xlsxColor{Theme: intPtr(4)}))
+
+	// Test get rich text and conditional formats with theme colors
+	f = NewFile()
+	assert.NoError(t, f.SetTheme(&Theme{ColorScheme: ThemeColorScheme{Accent1: "C00000", Accent2: "00B050"}}))
+	assert.NoError(t, f.SetCellRichText("Sheet1", "A1", []RichTextRun{
+		{Text: "a", Font: &Font{ColorTheme: intPtr(4)}},
+		{Text: "b", Font: &Font{ColorTheme: intPtr(4), ColorTint: -0.25}},
+		{Text: "c", Font: &Font{Color: "0000FF", ColorTheme: intPtr(4)}},
+	}))
+	runs, err := f.GetCellRichText("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "C00000", runs[0].Font.Color)
+	assert.Equal(t, strings.TrimPrefix(ThemeColor("C00000", -0.25), "FF"), runs[1].Font.Color)
+	assert.Equal(t, "0000FF", runs[2].Font.Color)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ConditionalFormatting = []*xlsxConditionalFormatting{
+		{SQRef: "A1:A10", CfRule: []*xlsxCfRule{{Type: "colorScale", ColorScale: &xlsxColorScale{
+			Cfvo:  []*xlsxCfvo{{Type: "min"}, {Type: "max"}},
+			Color: []*xlsxColor{{Theme: intPtr(4)}, {RGB: "FF0000FF"}},
+		}}}},
+		{SQRef: "B1:B10", CfRule: []*xlsxCfRule{{Type: "dataBar", DataBar: &xlsxDataBar{
+			Cfvo:  []*xlsxCfvo{{Type: "min"}, {Type: "max"}},
+			Color: []*xlsxColor{{Theme: intPtr(5)}},
+		}}}},
+		{SQRef: "C1:C10", CfRule: []*xlsxCfRule{{Type: "dataBar", DataBar: &xlsxDataBar{
+			Cfvo:  []*xlsxCfvo{{Type: "min"}, {Type: "max"}},
+			Color: []*xlsxColor{{RGB: "ff0000"}},
+		}}}},
+	}
+	formats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "#C00000", formats["A1:A10"][0].MinColor)
+	assert.Equal(t, "#0000FF", formats["A1:A10"][0].MaxColor)
+	assert.Equal(t, "#00B050", formats["B1:B10"][0].BarColor)
+	assert.Equal(t, "#FF0000", formats["C1:C10"][0].BarColor)
+}
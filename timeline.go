@@ -0,0 +1,484 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timelineLevels defined the time levels of the timeline in the order of the
+// level values.
+var timelineLevels = []string{"years", "quarters", "months", "days"}
+
+// AddTimeline provides the method to add a timeline for the date field of the
+// pivot table by given worksheet name and timeline options. The timeline
+// provides the date scrubbing for filtering the pivot table by the date
+// ranges in Excel 2013 or later. The timeline cache will be created for the
+// date field, and the range of the timeline will be from the first day of
+// the year of the earliest date to the last day of the year of the latest
+// date of the field. For example, add a timeline for the Date field of the
+// pivot table named PivotTable1 in the worksheet named Sheet1 at the cell
+// H2 of the worksheet:
+//
+//	err := f.AddTimeline("Sheet1", &excelize.TimelineOptions{
+//	    Name:       "Date",
+//	    Cell:       "H2",
+//	    TableSheet: "Sheet1",
+//	    TableName:  "PivotTable1",
+//	    Level:      "months",
+//	})
+//
+// Note that the values of the date field should be the dates stored as
+// numbers, and the pivot table filtered by the timeline will be refreshed by
+// the spreadsheet application on loading.
+func (f *File) AddTimeline(sheet string, opts *TimelineOptions) error {
+	opts, err := parseTimelineOptions(opts)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if _, _, err = CellNameToCoordinates(opts.Cell); err != nil {
+		return err
+	}
+	refs, err := f.getPivotTableRefs(opts.TableSheet)
+	if err != nil {
+		return err
+	}
+	var ref *pivotTableRef
+	for _, r := range refs {
+		if r.pt.Name == opts.TableName && r.pc != nil {
+			ref = r
+			break
+		}
+	}
+	if ref == nil {
+		return newNoExistPivotTableError(opts.TableName)
+	}
+	bounds, err := f.getTimelineBounds(opts, ref)
+	if err != nil {
+		return err
+	}
+	pivotCacheID, err := f.getPivotCacheExtID(ref)
+	if err != nil {
+		return err
+	}
+	cacheName, err := f.addTimelineCache(opts, ref, pivotCacheID, bounds)
+	if err != nil {
+		return err
+	}
+	name, err := f.addTimeline(sheet, ws, cacheName, bounds.StartDate, opts)
+	if err != nil {
+		return err
+	}
+	drawingID := f.countDrawings() + 1
+	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
+	drawingID, drawingXML = f.prepareDrawing(ws, drawingID, sheet, drawingXML)
+	if err = f.addDrawingTimeline(sheet, drawingXML, name, opts); err != nil {
+		return err
+	}
+	f.addSheetNameSpace(sheet, SourceRelationship)
+	return f.addContentTypePart(drawingID, "drawings")
+}
+
+// parseTimelineOptions provides a function to validate the timeline options
+// and set the default values.
+func parseTimelineOptions(opts *TimelineOptions) (*TimelineOptions, error) {
+	if opts == nil {
+		return nil, ErrParameterRequired
+	}
+	if opts.Name == "" || opts.Cell == "" || opts.TableSheet == "" || opts.TableName == "" {
+		return opts, ErrParameterRequired
+	}
+	if opts.Level == "" {
+		opts.Level = "months"
+	}
+	if inStrSlice(timelineLevels, opts.Level, true) == -1 {
+		return opts, newInvalidOptionalValue("Level", opts.Level, timelineLevels)
+	}
+	if opts.Caption == "" {
+		opts.Caption = opts.Name
+	}
+	if opts.Width == 0 {
+		opts.Width = defaultTimelineWidth
+	}
+	if opts.Height == 0 {
+		opts.Height = defaultTimelineHeight
+	}
+	if opts.Format.Positioning == "" {
+		opts.Format.Positioning = "oneCell"
+	}
+	opts.Format = *parseGraphicOptions(&opts.Format)
+	return opts, nil
+}
+
+// getTimelineBounds provides a function to get the date range bounds of the
+// timeline by given timeline options and the parts of the pivot table. The
+// bounds will be from the first day of the year of the earliest date to the
+// first day of the next year of the latest date of the date field.
+func (f *File) getTimelineBounds(opts *TimelineOptions, ref *pivotTableRef) (*xlsxTimelineRange, error) {
+	values, err := f.getPivotCacheSourceValues(opts.TableSheet, ref.pc)
+	if err != nil {
+		return nil, err
+	}
+	col := -1
+	if len(values) > 0 {
+		for idx, header := range values[0] {
+			if header.value == opts.Name {
+				col = idx
+				break
+			}
+		}
+	}
+	if col == -1 {
+		return nil, newNotDateFieldError(opts.Name)
+	}
+	minVal, maxVal := math.Inf(1), math.Inf(-1)
+	for _, row := range values[1:] {
+		if row[col].blank {
+			continue
+		}
+		if !row[col].number {
+			return nil, newNotDateFieldError(opts.Name)
+		}
+		num, _ := strconv.ParseFloat(row[col].value, 64)
+		minVal, maxVal = math.Min(minVal, num), math.Max(maxVal, num)
+	}
+	if minVal > maxVal {
+		return nil, newNotDateFieldError(opts.Name)
+	}
+	var date1904 bool
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, err
+	}
+	if wb.WorkbookPr != nil {
+		date1904 = wb.WorkbookPr.Date1904
+	}
+	layout := "2006-01-02T15:04:05"
+	startYear := timeFromExcelTime(minVal, date1904).Year()
+	endYear := timeFromExcelTime(maxVal, date1904).Year() + 1
+	return &xlsxTimelineRange{
+		StartDate: time.Date(startYear, time.January, 1, 0, 0, 0, 0, time.UTC).Format(layout),
+		EndDate:   time.Date(endYear, time.January, 1, 0, 0, 0, 0, time.UTC).Format(layout),
+	}, nil
+}
+
+// getPivotCacheExtID provides a function to get the pivot cache ID in the
+// extension list of the pivot cache definition by given parts of the pivot
+// table, which is referenced by the timeline caches. The pivot cache ID will
+// be created as one greater than the maximum pivot cache ID in the workbook
+// if it doesn't exist.
+func (f *File) getPivotCacheExtID(ref *pivotTableRef) (int, error) {
+	pivotCacheID, ok, err := f.decodePivotCacheExtID(ref.pc)
+	if err != nil || ok {
+		return pivotCacheID, err
+	}
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if !strings.HasPrefix(k.(string), "xl/pivotCache/pivotCacheDefinition") {
+			return true
+		}
+		pc := &xlsxPivotCacheDefinition{}
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(v.([]byte)))).
+			Decode(pc); err != nil && err != io.EOF {
+			return false
+		}
+		var ID int
+		if ID, _, err = f.decodePivotCacheExtID(pc); err != nil {
+			return false
+		}
+		if ID > pivotCacheID {
+			pivotCacheID = ID
+		}
+		return true
+	})
+	if err != nil && err != io.EOF {
+		return pivotCacheID, err
+	}
+	pivotCacheID++
+	content, err := xml.Marshal(xlsxX14PivotCacheDefinition{PivotCacheID: pivotCacheID})
+	if err != nil {
+		return pivotCacheID, err
+	}
+	if ref.pc.ExtLst == nil {
+		ref.pc.ExtLst = new(xlsxExtLst)
+	}
+	ref.pc.ExtLst.Ext += fmt.Sprintf("<ext uri=\"%s\" xmlns:x14=\"%s\">%s</ext>",
+		ExtURIPivotCacheDefinition, NameSpaceSpreadSheetX14.Value, content)
+	pivotCache, err := xml.Marshal(ref.pc)
+	f.saveFileList(ref.pivotCacheXML, pivotCache)
+	return pivotCacheID, err
+}
+
+// decodePivotCacheExtID provides a function to decode the pivot cache ID in
+// the extension list of the pivot cache definition, the ok result will be
+// false if the pivot cache ID doesn't exist.
+func (f *File) decodePivotCacheExtID(pc *xlsxPivotCacheDefinition) (int, bool, error) {
+	if pc.ExtLst == nil {
+		return 0, false, nil
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + pc.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return 0, false, err
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURIPivotCacheDefinition {
+			continue
+		}
+		decodePivotCache := new(decodeX14PivotCacheDefinition)
+		if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(decodePivotCache); err != nil && err != io.EOF {
+			return 0, false, err
+		}
+		return decodePivotCache.PivotCacheID, true, nil
+	}
+	return 0, false, nil
+}
+
+// addTimelineCache provides a function to create the timeline cache part,
+// the relationship and the defined name of the timeline cache in the
+// workbook by given timeline options, the parts of the pivot table, the
+// pivot cache ID and the date range bounds, and returns the name of the
+// timeline cache.
+func (f *File) addTimelineCache(opts *TimelineOptions, ref *pivotTableRef, pivotCacheID int, bounds *xlsxTimelineRange) (string, error) {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return "", err
+	}
+	if wb.DefinedNames == nil {
+		wb.DefinedNames = &xlsxDefinedNames{}
+	}
+	prefix := "NativeTimeline_" + strings.Map(func(r rune) rune {
+		if r == '_' || r == '.' || ('0' <= r && r <= '9') || ('A' <= r && r <= 'Z') || ('a' <= r && r <= 'z') {
+			return r
+		}
+		return '_'
+	}, opts.Name)
+	name, definedNames := prefix, make(map[string]bool)
+	for _, dn := range wb.DefinedNames.DefinedName {
+		definedNames[strings.ToLower(dn.Name)] = true
+	}
+	for i := 1; definedNames[strings.ToLower(name)]; i++ {
+		name = prefix + strconv.Itoa(i)
+	}
+	timelineCache := xlsxTimelineCacheDefinition{
+		Name:       name,
+		SourceName: opts.Name,
+		PivotTables: &xlsxTimelineCachePivotTables{
+			PivotTable: []*xlsxTimelineCachePivotTable{{TabID: f.getSheetID(opts.TableSheet), Name: ref.pt.Name}},
+		},
+		State: &xlsxTimelineState{
+			MinimalRefreshVersion: 6,
+			LastRefreshVersion:    6,
+			PivotCacheID:          pivotCacheID,
+			FilterType:            "unknown",
+			Bounds:                bounds,
+		},
+	}
+	content, err := xml.Marshal(timelineCache)
+	if err != nil {
+		return name, err
+	}
+	timelineCacheID := f.countParts("xl/timelineCaches/timelineCache") + 1
+	f.saveFileList("xl/timelineCaches/timelineCache"+strconv.Itoa(timelineCacheID)+".xml", content)
+	rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipTimelineCache,
+		fmt.Sprintf("/xl/timelineCaches/timelineCache%d.xml", timelineCacheID), "")
+	wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, xlsxDefinedName{Name: name, Data: formulaErrorNA})
+	if wb.ExtLst, err = f.addTimelineRefs(wb.ExtLst, ExtURITimelineCacheRefs, "timelineCacheRef", rID); err != nil {
+		return name, err
+	}
+	return name, f.addContentTypePart(timelineCacheID, "timelineCache")
+}
+
+// addTimeline provides a function to add the timeline into the timeline part
+// of the worksheet by given worksheet name, the worksheet, the name of the
+// timeline cache, the scroll position and the timeline options. The timeline
+// part will be created if it doesn't exist, and the name of the timeline
+// which is unique in the workbook will be returned.
+func (f *File) addTimeline(sheet string, ws *xlsxWorksheet, cacheName, scrollPosition string, opts *TimelineOptions) (string, error) {
+	var (
+		err         error
+		names       = make(map[string]bool)
+		timelineXML string
+		timelines   = &xlsxTimelines{}
+	)
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/timelines/timeline") {
+			parts := &xlsxTimelines{}
+			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(v.([]byte)))).
+				Decode(parts); err != nil && err != io.EOF {
+				return false
+			}
+			err = nil
+			for _, timeline := range parts.Timeline {
+				names[timeline.Name] = true
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	name := opts.Name
+	for i := 1; names[name]; i++ {
+		name = opts.Name + " " + strconv.Itoa(i)
+	}
+	sheetXMLPath, _ := f.getSheetXMLPath(sheet)
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(sheetXMLPath, "xl/worksheets/") + ".rels"
+	rels, err := f.relsReader(sheetRels)
+	if err != nil {
+		return name, err
+	}
+	if rels != nil {
+		rels.Lock()
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipTimeline {
+				timelineXML = getRelationshipsPartPath("xl/worksheets", rel.Target)
+				break
+			}
+		}
+		rels.Unlock()
+	}
+	if timelineXML != "" {
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(timelineXML)))).
+			Decode(timelines); err != nil && err != io.EOF {
+			return name, err
+		}
+	}
+	level := inStrSlice(timelineLevels, opts.Level, true)
+	timelines.Timeline = append(timelines.Timeline, &xlsxTimeline{
+		Name:                    name,
+		Cache:                   cacheName,
+		Caption:                 opts.Caption,
+		ShowHeader:              opts.ShowHeader,
+		ShowSelectionLabel:      opts.ShowSelectionLabel,
+		ShowTimeLevel:           opts.ShowTimeLevel,
+		ShowHorizontalScrollbar: opts.ShowHorizontalScrollbar,
+		Level:                   level,
+		SelectionLevel:          level,
+		ScrollPosition:          scrollPosition,
+	})
+	content, err := xml.Marshal(timelines)
+	if err != nil {
+		return name, err
+	}
+	if timelineXML != "" {
+		f.saveFileList(timelineXML, content)
+		return name, err
+	}
+	timelineID := f.countParts("xl/timelines/timeline") + 1
+	f.saveFileList("xl/timelines/timeline"+strconv.Itoa(timelineID)+".xml", content)
+	rID := f.addRels(sheetRels, SourceRelationshipTimeline, "../timelines/timeline"+strconv.Itoa(timelineID)+".xml", "")
+	if ws.ExtLst, err = f.addTimelineRefs(ws.ExtLst, ExtURITimelineRefs, "timelineRef", rID); err != nil {
+		return name, err
+	}
+	return name, f.addContentTypePart(timelineID, "timeline")
+}
+
+// addTimelineRefs provides a function to add the relationship reference of
+// the timeline part or the timeline cache part into the extension list by
+// given extension list, URI of the extension, element name of the reference
+// and relationship index. The existing references of the extension will be
+// kept.
+func (f *File) addTimelineRefs(extLst *xlsxExtLst, URI, element string, rID int) (*xlsxExtLst, error) {
+	if extLst == nil {
+		extLst = new(xlsxExtLst)
+	}
+	refs := xlsxX15TimelineRefs{XMLName: xml.Name{Local: "x15:" + element + "s"}}
+	before, after := extLst.Ext, ""
+	if idx := strings.Index(extLst.Ext, fmt.Sprintf("uri=\"%s\"", URI)); idx != -1 {
+		start := strings.LastIndex(extLst.Ext[:idx], "<")
+		if end := strings.Index(extLst.Ext[idx:], "</ext>"); start != -1 && end != -1 {
+			ext := new(xlsxWorksheetExt)
+			if err := f.xmlNewDecoder(strings.NewReader(extLst.Ext[start : idx+end+len("</ext>")])).
+				Decode(ext); err != nil && err != io.EOF {
+				return extLst, err
+			}
+			decodeRefs := new(decodeX15TimelineRefs)
+			if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+				Decode(decodeRefs); err != nil && err != io.EOF {
+				return extLst, err
+			}
+			for _, ref := range decodeRefs.Ref {
+				refs.Ref = append(refs.Ref, xlsxX15TimelineRef{XMLName: xml.Name{Local: "x15:" + element}, RID: ref.RID})
+			}
+			before, after = extLst.Ext[:start], extLst.Ext[idx+end+len("</ext>"):]
+		}
+	}
+	refs.Ref = append(refs.Ref, xlsxX15TimelineRef{XMLName: xml.Name{Local: "x15:" + element}, RID: "rId" + strconv.Itoa(rID)})
+	content, err := xml.Marshal(refs)
+	if err != nil {
+		return extLst, err
+	}
+	extLst.Ext = before + fmt.Sprintf("<ext uri=\"%s\" xmlns:x15=\"%s\">%s</ext>",
+		URI, NameSpaceSpreadSheetX15.Value, content) + after
+	return extLst, err
+}
+
+// addDrawingTimeline provides a function to add the graphic frame of the
+// timeline into the drawing by given worksheet name, drawing XML path, name
+// of the timeline and the timeline options. The graphic frame will be
+// wrapped in the alternate content with a fallback shape for the
+// applications which don't support timelines.
+func (f *File) addDrawingTimeline(sheet, drawingXML, name string, opts *TimelineOptions) error {
+	col, row, err := CellNameToCoordinates(opts.Cell)
+	if err != nil {
+		return err
+	}
+	width := int(float64(opts.Width) * opts.Format.ScaleX)
+	height := int(float64(opts.Height) * opts.Format.ScaleY)
+	colStart, rowStart, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, col-1, row-1, opts.Format.OffsetX, opts.Format.OffsetY, width, height)
+	content, cNvPrID, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return err
+	}
+	graphicFrame := xlsxGraphicFrame{
+		NvGraphicFramePr: xlsxNvGraphicFramePr{
+			CNvPr: &xlsxCNvPr{ID: cNvPrID, Name: name},
+		},
+		Graphic: &xlsxGraphic{
+			GraphicData: &xlsxGraphicData{
+				URI:        NameSpaceDrawingMLTimeslicer.Value,
+				Timeslicer: &xlsxTimeslicer{Tsle: NameSpaceDrawingMLTimeslicer.Value, Name: name},
+			},
+		},
+	}
+	graphic, _ := xml.Marshal(graphicFrame)
+	content.TwoCellAnchor = append(content.TwoCellAnchor, &xdrCellAnchor{
+		EditAs: opts.Format.Positioning,
+		From: &xlsxFrom{
+			Col: colStart, ColOff: opts.Format.OffsetX * EMU,
+			Row: rowStart, RowOff: opts.Format.OffsetY * EMU,
+		},
+		To: &xlsxTo{Col: colEnd, ColOff: x2 * EMU, Row: rowEnd, RowOff: y2 * EMU},
+		GraphicFrame: `<mc:AlternateContent xmlns:mc="` + SourceRelationshipCompatibility.Value + `"><mc:Choice xmlns:` +
+			NameSpaceDrawingMLTimeslicer.Name.Local + `="` + NameSpaceDrawingMLTimeslicer.Value + `" Requires="` +
+			NameSpaceDrawingMLTimeslicer.Name.Local + `">` + string(graphic) + `</mc:Choice><mc:Fallback>` +
+			templateTimelineFallback + `</mc:Fallback></mc:AlternateContent>`,
+		ClientData: &xdrClientData{
+			FLocksWithSheet:  *opts.Format.Locked,
+			FPrintsWithSheet: *opts.Format.PrintObject,
+		},
+	})
+	f.Drawings.Store(drawingXML, content)
+	return err
+}
@@ -0,0 +1,168 @@
+package excel
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTimeline(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Order Date", "Sales", "Region"}))
+	for row, values := range [][]interface{}{
+		{time.Date(2017, 1, 15, 0, 0, 0, 0, time.UTC), 12, "East"},
+		{time.Date(2017, 5, 3, 0, 0, 0, 0, time.UTC), 25, "West"},
+		{time.Date(2018, 11, 20, 0, 0, 0, 0, time.UTC), 38, "East"},
+		{nil, 7, "West"},
+	} {
+		cell, err := CoordinatesToCellName(1, row+2)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &values))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$C$5",
+		PivotTableRange: "Sheet1!$E$2:$K$20",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Region"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	opts := &TimelineOptions{
+		Name:       "Order Date",
+		Cell:       "B2",
+		TableSheet: "Sheet1",
+		TableName:  "PivotTable1",
+		Level:      "quarters",
+		ShowHeader: boolPtr(false),
+	}
+	// Test add timelines for the same date field in the same worksheet
+	assert.NoError(t, f.AddTimeline("Sheet2", opts))
+	assert.NoError(t, f.AddTimeline("Sheet2", &TimelineOptions{
+		Name: "Order Date", Cell: "B12", TableSheet: "Sheet1", TableName: "PivotTable1",
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddTimeline.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestAddTimeline.xlsx"))
+	assert.NoError(t, err)
+	timelines := &xlsxTimelines{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/timelines/timeline1.xml"), timelines))
+	assert.Equal(t, []*xlsxTimeline{
+		{
+			Name: "Order Date", Cache: "NativeTimeline_Order_Date", Caption: "Order Date", ShowHeader: boolPtr(false),
+			Level: 1, SelectionLevel: 1, ScrollPosition: "2017-01-01T00:00:00",
+		},
+		{
+			Name: "Order Date 1", Cache: "NativeTimeline_Order_Date1", Caption: "Order Date",
+			Level: 2, SelectionLevel: 2, ScrollPosition: "2017-01-01T00:00:00",
+		},
+	}, timelines.Timeline)
+	timelineCache := &xlsxTimelineCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/timelineCaches/timelineCache2.xml"), timelineCache))
+	assert.Equal(t, "NativeTimeline_Order_Date1", timelineCache.Name)
+	assert.Equal(t, "Order Date", timelineCache.SourceName)
+	assert.Equal(t, []*xlsxTimelineCachePivotTable{{TabID: 1, Name: "PivotTable1"}}, timelineCache.PivotTables.PivotTable)
+	assert.Equal(t, &xlsxTimelineState{
+		MinimalRefreshVersion: 6, LastRefreshVersion: 6, PivotCacheID: 1, FilterType: "unknown",
+		Bounds: &xlsxTimelineRange{StartDate: "2017-01-01T00:00:00", EndDate: "2019-01-01T00:00:00"},
+	}, timelineCache.State)
+	pc := &xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheDefinition1.xml"), pc))
+	ID, ok, err := f.decodePivotCacheExtID(pc)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, ID)
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Equal(t, []xlsxDefinedName{
+		{Name: "NativeTimeline_Order_Date", Data: "#N/A"}, {Name: "NativeTimeline_Order_Date1", Data: "#N/A"},
+	}, wb.DefinedNames.DefinedName)
+	assert.Equal(t, 1, strings.Count(wb.ExtLst.Ext, ExtURITimelineCacheRefs))
+	assert.Equal(t, 2, strings.Count(wb.ExtLst.Ext, "<x15:timelineCacheRef "))
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(ws.ExtLst.Ext, "<x15:timelineRef "))
+	drawing, _, err := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, drawing.TwoCellAnchor, 2)
+	assert.Contains(t, drawing.TwoCellAnchor[1].GraphicFrame, `<tsle:timeslicer xmlns:tsle="http://schemas.microsoft.com/office/drawing/2012/timeslicer" name="Order Date 1">`)
+	contentTypes, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	var overrides []string
+	for _, override := range contentTypes.Overrides {
+		overrides = append(overrides, override.PartName)
+	}
+	assert.Subset(t, overrides, []string{"/xl/timelines/timeline1.xml", "/xl/timelineCaches/timelineCache1.xml", "/xl/timelineCaches/timelineCache2.xml"})
+	// Test add timeline for the pivot table which the pivot cache ID exists
+	assert.NoError(t, f.AddTimeline("Sheet1", &TimelineOptions{
+		Name: "Order Date", Cell: "M2", TableSheet: "Sheet1", TableName: "PivotTable1",
+	}))
+	timelineCache = &xlsxTimelineCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/timelineCaches/timelineCache3.xml"), timelineCache))
+	assert.Equal(t, 1, timelineCache.State.PivotCacheID)
+	timelines = &xlsxTimelines{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/timelines/timeline2.xml"), timelines))
+	assert.Equal(t, "Order Date 2", timelines.Timeline[0].Name)
+	// Test add timeline with invalid options
+	for _, c := range []struct {
+		opts *TimelineOptions
+		err  string
+	}{
+		{nil, ErrParameterRequired.Error()},
+		{&TimelineOptions{Name: "Order Date"}, ErrParameterRequired.Error()},
+		{&TimelineOptions{Name: "Order Date", Cell: "B2", TableSheet: "Sheet1", TableName: "PivotTable1", Level: "weeks"}, newInvalidOptionalValue("Level", "weeks", timelineLevels).Error()},
+		{&TimelineOptions{Name: "Order Date", Cell: "A", TableSheet: "Sheet1", TableName: "PivotTable1"}, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error()},
+		{&TimelineOptions{Name: "Order Date", Cell: "B2", TableSheet: "SheetN", TableName: "PivotTable1"}, "sheet SheetN does not exist"},
+		{&TimelineOptions{Name: "Order Date", Cell: "B2", TableSheet: "Sheet1", TableName: "PivotTableN"}, newNoExistPivotTableError("PivotTableN").Error()},
+		{&TimelineOptions{Name: "Region", Cell: "B2", TableSheet: "Sheet1", TableName: "PivotTable1"}, newNotDateFieldError("Region").Error()},
+		{&TimelineOptions{Name: "Date", Cell: "B2", TableSheet: "Sheet1", TableName: "PivotTable1"}, newNotDateFieldError("Date").Error()},
+	} {
+		assert.EqualError(t, f.AddTimeline("Sheet2", c.opts), c.err)
+	}
+	assert.EqualError(t, f.AddTimeline("SheetN", opts), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+	// Test add timeline for the field without date values
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Date", "Sales"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{nil, 1}))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!$A$1:$B$2",
+		PivotTableRange: "Sheet1!$D$2:$F$10",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Date"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{
+		Name: "Date", Cell: "H2", TableSheet: "Sheet1", TableName: "PivotTable1",
+	}), newNotDateFieldError("Date").Error())
+	// Test add timeline with unsupported charset timeline part
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", time.Date(2017, 1, 15, 0, 0, 0, 0, time.UTC)))
+	f.Pkg.Store("xl/timelines/timeline1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{
+		Name: "Date", Cell: "H2", TableSheet: "Sheet1", TableName: "PivotTable1",
+	}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestAddTimelineRefs(t *testing.T) {
+	f := NewFile()
+	extLst, err := f.addTimelineRefs(&xlsxExtLst{Ext: `<ext uri="{A}"><a/></ext>`}, ExtURITimelineRefs, "timelineRef", 1)
+	assert.NoError(t, err)
+	extLst, err = f.addTimelineRefs(extLst, ExtURITimelineRefs, "timelineRef", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, `<ext uri="{A}"><a/></ext><ext uri="`+ExtURITimelineRefs+`" xmlns:x15="`+NameSpaceSpreadSheetX15.Value+
+		`"><x15:timelineRefs><x15:timelineRef r:id="rId1"></x15:timelineRef><x15:timelineRef r:id="rId2"></x15:timelineRef></x15:timelineRefs></ext>`, extLst.Ext)
+	// Test add timeline references with unsupported charset extension
+	_, err = f.addTimelineRefs(&xlsxExtLst{Ext: `<ext uri="` + ExtURITimelineRefs + `">` + string(MacintoshCyrillicCharset) + `</ext>`}, ExtURITimelineRefs, "timelineRef", 1)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test decode pivot cache ID with unsupported charset extension
+	_, _, err = f.decodePivotCacheExtID(&xlsxPivotCacheDefinition{ExtLst: &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, _, err = f.decodePivotCacheExtID(&xlsxPivotCacheDefinition{ExtLst: &xlsxExtLst{Ext: `<ext uri="` + ExtURIPivotCacheDefinition + `">` + string(MacintoshCyrillicCharset) + `</ext>`}})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
@@ -14,6 +14,7 @@ package excel
 import (
 	"bytes"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"path/filepath"
 	"strconv"
@@ -59,6 +60,139 @@ func (f *File) GetWorkbookProps() (WorkbookPropsOptions, error) {
 	return opts, err
 }
 
+// SetCalcProps provides a function to sets calculation properties. The
+// iterative calculation will be used for the circular references on
+// calculating formulas by CalcCellValue if the Iterate property is enabled,
+// the calculation will be stop after the IterateCount times iterations or the
+// maximum change of the values between the iterations is less than the
+// IterateDelta. The default iterations count is 100, and the default maximum
+// change is 0.001. For example, enable iterative calculation with maximum 50
+// iterations:
+//
+//	iterate, iterateCount := true, uint(50)
+//	err := f.SetCalcProps(&excelize.CalcPropsOptions{
+//	    Iterate:      &iterate,
+//	    IterateCount: &iterateCount,
+//	})
+//
+// Optional value of "CalcMode" property is: "manual", "auto" or
+// "autoNoTable", and optional value of "RefMode" property is: "A1" or "R1C1".
+func (f *File) SetCalcProps(opts *CalcPropsOptions) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.CalcPr == nil {
+		wb.CalcPr = new(xlsxCalcPr)
+	}
+	if opts == nil {
+		return nil
+	}
+	if opts.CalcMode != nil {
+		if inStrSlice(supportedCalcMode, *opts.CalcMode, true) == -1 {
+			return newInvalidOptionalValue("CalcMode", *opts.CalcMode, supportedCalcMode)
+		}
+		wb.CalcPr.CalcMode = *opts.CalcMode
+	}
+	if opts.RefMode != nil {
+		if inStrSlice(supportedRefMode, *opts.RefMode, true) == -1 {
+			return newInvalidOptionalValue("RefMode", *opts.RefMode, supportedRefMode)
+		}
+		wb.CalcPr.RefMode = *opts.RefMode
+	}
+	if opts.IterateDelta != nil {
+		if *opts.IterateDelta < 0 {
+			return ErrParameterInvalid
+		}
+		wb.CalcPr.IterateDelta = *opts.IterateDelta
+	}
+	if opts.CalcID != nil {
+		wb.CalcPr.CalcID = strconv.FormatUint(uint64(*opts.CalcID), 10)
+	}
+	if opts.FullCalcOnLoad != nil {
+		wb.CalcPr.FullCalcOnLoad = *opts.FullCalcOnLoad
+	}
+	if opts.Iterate != nil {
+		wb.CalcPr.Iterate = *opts.Iterate
+	}
+	if opts.IterateCount != nil {
+		wb.CalcPr.IterateCount = int(*opts.IterateCount)
+	}
+	if opts.FullPrecision != nil {
+		wb.CalcPr.FullPrecision = boolPtr(*opts.FullPrecision)
+	}
+	if opts.CalcCompleted != nil {
+		wb.CalcPr.CalcCompleted = boolPtr(*opts.CalcCompleted)
+	}
+	if opts.CalcOnSave != nil {
+		wb.CalcPr.CalcOnSave = boolPtr(*opts.CalcOnSave)
+	}
+	if opts.ConcurrentCalc != nil {
+		wb.CalcPr.ConcurrentCalc = boolPtr(*opts.ConcurrentCalc)
+	}
+	if opts.ConcurrentManualCount != nil {
+		wb.CalcPr.ConcurrentManualCount = int(*opts.ConcurrentManualCount)
+	}
+	if opts.ForceFullCalc != nil {
+		wb.CalcPr.ForceFullCalc = *opts.ForceFullCalc
+	}
+	return nil
+}
+
+// GetCalcProps provides a function to gets calculation properties. The
+// default value will be returned for the properties which not be set in the
+// workbook.
+func (f *File) GetCalcProps() (CalcPropsOptions, error) {
+	opts := CalcPropsOptions{
+		CalcMode:      stringPtr("auto"),
+		RefMode:       stringPtr("A1"),
+		Iterate:       boolPtr(false),
+		IterateCount:  uintPtr(100),
+		IterateDelta:  float64Ptr(0.001),
+		FullPrecision: boolPtr(true),
+		CalcCompleted: boolPtr(true),
+		CalcOnSave:    boolPtr(true),
+	}
+	wb, err := f.workbookReader()
+	if err != nil || wb.CalcPr == nil {
+		return opts, err
+	}
+	if wb.CalcPr.CalcID != "" {
+		if calcID, err := strconv.ParseUint(wb.CalcPr.CalcID, 10, 32); err == nil {
+			opts.CalcID = uintPtr(uint(calcID))
+		}
+	}
+	if wb.CalcPr.CalcMode != "" {
+		opts.CalcMode = stringPtr(wb.CalcPr.CalcMode)
+	}
+	if wb.CalcPr.RefMode != "" {
+		opts.RefMode = stringPtr(wb.CalcPr.RefMode)
+	}
+	if wb.CalcPr.IterateCount > 0 {
+		opts.IterateCount = uintPtr(uint(wb.CalcPr.IterateCount))
+	}
+	if wb.CalcPr.IterateDelta > 0 {
+		opts.IterateDelta = float64Ptr(wb.CalcPr.IterateDelta)
+	}
+	if wb.CalcPr.FullPrecision != nil {
+		opts.FullPrecision = boolPtr(*wb.CalcPr.FullPrecision)
+	}
+	if wb.CalcPr.CalcCompleted != nil {
+		opts.CalcCompleted = boolPtr(*wb.CalcPr.CalcCompleted)
+	}
+	if wb.CalcPr.CalcOnSave != nil {
+		opts.CalcOnSave = boolPtr(*wb.CalcPr.CalcOnSave)
+	}
+	if wb.CalcPr.ConcurrentCalc != nil {
+		opts.ConcurrentCalc = boolPtr(*wb.CalcPr.ConcurrentCalc)
+	}
+	opts.FullCalcOnLoad = boolPtr(wb.CalcPr.FullCalcOnLoad)
+	opts.Iterate = boolPtr(wb.CalcPr.Iterate)
+	opts.ConcurrentManualCount = uintPtr(uint(wb.CalcPr.ConcurrentManualCount))
+	opts.ForceFullCalc = boolPtr(wb.CalcPr.ForceFullCalc)
+	return opts, err
+}
+
 // ProtectWorkbook provides a function to prevent other users from viewing
 // hidden worksheets, adding, moving, deleting, or hiding worksheets, and
 // renaming worksheets in a workbook. The optional field AlgorithmName
@@ -205,3 +339,193 @@ func (f *File) workBookWriter() {
 		f.saveFileList(f.getWorkbookPath(), replaceRelationshipsBytes(f.replaceNameSpaceBytes(f.getWorkbookPath(), output)))
 	}
 }
+
+// sheetDimensionsReader provides a function to get the dimensions of the
+// worksheets recorded in the extension list of the workbook, the dimensions
+// will be ignored if it can't be parsed.
+func (f *File) sheetDimensionsReader() (map[string]SheetDimension, error) {
+	dimensions := make(map[string]SheetDimension)
+	wb, err := f.workbookReader()
+	if err != nil || wb.ExtLst == nil {
+		return dimensions, err
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if err = f.xmlNewDecoder(strings.NewReader("<extLst>" + wb.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return dimensions, nil
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURISheetDimensions {
+			continue
+		}
+		decodeDimensions := new(decodeSheetDimensions)
+		if err = f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(decodeDimensions); err != nil && err != io.EOF {
+			return dimensions, nil
+		}
+		for _, sheet := range decodeDimensions.Sheet {
+			if name, ok := f.getSheetXMLPath(sheet.Name); ok {
+				dimensions[name] = SheetDimension{Ref: sheet.Ref, Rows: sheet.Rows, Cols: sheet.Cols}
+			}
+		}
+	}
+	return dimensions, nil
+}
+
+// sheetDimensionsWriter provides a function to write the dimensions of the
+// worksheets into the extension list of the workbook if the SheetDimensions
+// option has been enabled, the recorded dimensions will be removed otherwise.
+func (f *File) sheetDimensionsWriter() error {
+	enabled := f.options != nil && f.options.SheetDimensions
+	if f.WorkBook == nil && !enabled {
+		return nil
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.ExtLst != nil {
+		prefix := fmt.Sprintf("<ext uri=\"%s\">", ExtURISheetDimensions)
+		if idx := strings.Index(wb.ExtLst.Ext, prefix); idx != -1 {
+			if end := strings.Index(wb.ExtLst.Ext[idx:], "</ext>"); end != -1 {
+				wb.ExtLst.Ext = wb.ExtLst.Ext[:idx] + wb.ExtLst.Ext[idx+end+len("</ext>"):]
+			}
+		}
+		if wb.ExtLst.Ext == "" {
+			wb.ExtLst = nil
+		}
+	}
+	if !enabled {
+		return nil
+	}
+	dimensions := xlsxSheetDimensions{XMLNSED: NameSpaceSheetDimensions.Value}
+	for _, name := range f.GetSheetList() {
+		dimension, err := f.GetSheetDimension(name)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(name).Error() {
+				continue
+			}
+			return err
+		}
+		dimensions.Sheet = append(dimensions.Sheet, xlsxSheetDimension{
+			Name: name, Ref: dimension.Ref, Rows: dimension.Rows, Cols: dimension.Cols,
+		})
+	}
+	content, err := xml.Marshal(dimensions)
+	if err != nil {
+		return err
+	}
+	if wb.ExtLst == nil {
+		wb.ExtLst = new(xlsxExtLst)
+	}
+	wb.ExtLst.Ext += fmt.Sprintf("<ext uri=\"%s\">%s</ext>", ExtURISheetDimensions, content)
+	return err
+}
+
+// externalBook defined the target path and the external link part with the
+// cached data of the external workbook referenced by the workbook.
+type externalBook struct {
+	target string
+	link   *xlsxExternalLink
+}
+
+// getExternalBooks provides a function to get the external workbooks
+// referenced by the workbook in the order of the external references. The
+// index in the formula external reference such as [1]Sheet1!A1 is the
+// one-based index of the external workbooks.
+func (f *File) getExternalBooks() ([]externalBook, error) {
+	var books []externalBook
+	wb, err := f.workbookReader()
+	if err != nil || wb.ExternalReferences == nil {
+		return books, err
+	}
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil || rels == nil {
+		return books, err
+	}
+	for _, ref := range wb.ExternalReferences.ExternalReference {
+		var book externalBook
+		for _, rel := range rels.Relationships {
+			if rel.ID != ref.RID {
+				continue
+			}
+			linkPath := f.getWorksheetPath(rel.Target)
+			book.link = new(xlsxExternalLink)
+			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(linkPath)))).
+				Decode(book.link); err != nil && err != io.EOF {
+				return books, err
+			}
+			if book.link.ExternalBook == nil {
+				break
+			}
+			linkRels, err := f.relsReader(strings.TrimPrefix(filepath.Dir(linkPath)+"/_rels/"+filepath.Base(linkPath)+".rels", "/"))
+			if err != nil {
+				return books, err
+			}
+			if linkRels == nil {
+				break
+			}
+			for _, linkRel := range linkRels.Relationships {
+				if linkRel.ID == book.link.ExternalBook.RID {
+					book.target = linkRel.Target
+				}
+			}
+		}
+		books = append(books, book)
+	}
+	return books, nil
+}
+
+// newExternalBookFile provides a function to create a workbook with the cached
+// worksheet names and cell values of the external workbook by given external
+// link part.
+func newExternalBookFile(link *xlsxExternalLink) (*File, error) {
+	f := NewFile()
+	f.tracker.close() // the workbook in memory needn't be closed
+	if link == nil || link.ExternalBook == nil || link.ExternalBook.SheetNames == nil {
+		return f, nil
+	}
+	sheets := link.ExternalBook.SheetNames.SheetName
+	for idx, sheet := range sheets {
+		if idx == 0 {
+			if err := f.SetSheetName("Sheet1", sheet.Val); err != nil {
+				return f, err
+			}
+			continue
+		}
+		if _, err := f.NewSheet(sheet.Val); err != nil {
+			return f, err
+		}
+	}
+	if link.ExternalBook.SheetDataSet == nil {
+		return f, nil
+	}
+	for _, sheetData := range link.ExternalBook.SheetDataSet.SheetData {
+		if sheetData.SheetID < 0 || sheetData.SheetID >= len(sheets) {
+			continue
+		}
+		for _, row := range sheetData.Row {
+			for _, c := range row.Cell {
+				if err := f.setExternalCellValue(sheets[sheetData.SheetID].Val, c); err != nil {
+					return f, err
+				}
+			}
+		}
+	}
+	return f, nil
+}
+
+// setExternalCellValue provides a function to set the cached cell value of the
+// external workbook by given worksheet name and cached cell.
+func (f *File) setExternalCellValue(sheet string, c xlsxExternalCell) error {
+	switch c.T {
+	case "b":
+		return f.SetCellBool(sheet, c.R, c.V == "1")
+	case "e", "s", "str":
+		return f.SetCellStr(sheet, c.R, c.V)
+	}
+	if num, err := strconv.ParseFloat(c.V, 64); err == nil {
+		return f.SetCellFloat(sheet, c.R, num, -1, 64)
+	}
+	return f.SetCellStr(sheet, c.R, c.V)
+}
@@ -31,3 +31,57 @@ func TestWorkbookProps(t *testing.T) {
 	_, err = f.GetWorkbookProps()
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
+
+func TestCalcProps(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCalcProps(nil))
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.CalcPr = nil
+	opts, err := f.GetCalcProps()
+	assert.NoError(t, err)
+	assert.Equal(t, CalcPropsOptions{
+		CalcMode:      stringPtr("auto"),
+		RefMode:       stringPtr("A1"),
+		Iterate:       boolPtr(false),
+		IterateCount:  uintPtr(100),
+		IterateDelta:  float64Ptr(0.001),
+		FullPrecision: boolPtr(true),
+		CalcCompleted: boolPtr(true),
+		CalcOnSave:    boolPtr(true),
+	}, opts)
+	expected := CalcPropsOptions{
+		CalcID:                uintPtr(191029),
+		CalcMode:              stringPtr("manual"),
+		FullCalcOnLoad:        boolPtr(true),
+		RefMode:               stringPtr("R1C1"),
+		Iterate:               boolPtr(true),
+		IterateCount:          uintPtr(50),
+		IterateDelta:          float64Ptr(0.0001),
+		FullPrecision:         boolPtr(false),
+		CalcCompleted:         boolPtr(false),
+		CalcOnSave:            boolPtr(false),
+		ConcurrentCalc:        boolPtr(false),
+		ConcurrentManualCount: uintPtr(4),
+		ForceFullCalc:         boolPtr(true),
+	}
+	assert.NoError(t, f.SetCalcProps(&expected))
+	opts, err = f.GetCalcProps()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, opts)
+	// Test set calculation properties with invalid options
+	assert.EqualError(t, f.SetCalcProps(&CalcPropsOptions{CalcMode: stringPtr("unknown")}),
+		newInvalidOptionalValue("CalcMode", "unknown", supportedCalcMode).Error())
+	assert.EqualError(t, f.SetCalcProps(&CalcPropsOptions{RefMode: stringPtr("unknown")}),
+		newInvalidOptionalValue("RefMode", "unknown", supportedRefMode).Error())
+	assert.Equal(t, ErrParameterInvalid, f.SetCalcProps(&CalcPropsOptions{IterateDelta: float64Ptr(-1)}))
+	// Test set calculation properties with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCalcProps(&expected), "XML syntax error on line 1: invalid UTF-8")
+	// Test get calculation properties with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.GetCalcProps()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
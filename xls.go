@@ -0,0 +1,549 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// Define the record types of the BIFF8 workbook stream used by the XLS
+// reader.
+const (
+	xlsRecordFormula    = 0x0006
+	xlsRecordEOF        = 0x000A
+	xlsRecordFilePass   = 0x002F
+	xlsRecordDateMode   = 0x0022
+	xlsRecordContinue   = 0x003C
+	xlsRecordBoundSheet = 0x0085
+	xlsRecordMulRK      = 0x00BD
+	xlsRecordXF         = 0x00E0
+	xlsRecordSST        = 0x00FC
+	xlsRecordLabelSST   = 0x00FD
+	xlsRecordNumber     = 0x0203
+	xlsRecordLabel      = 0x0204
+	xlsRecordBoolErr    = 0x0205
+	xlsRecordString     = 0x0207
+	xlsRecordRK         = 0x027E
+	xlsRecordArray      = 0x0221
+	xlsRecordTable      = 0x0236
+	xlsRecordShrFmla    = 0x04BC
+	xlsRecordFormat     = 0x041E
+	xlsRecordBOF        = 0x0809
+	xlsBIFF8Version     = 0x0600
+)
+
+// xlsErrorValues defined the error values of the BIFF8 error codes.
+var xlsErrorValues = map[byte]string{
+	0x00: formulaErrorNULL,
+	0x07: formulaErrorDIV,
+	0x0F: formulaErrorVALUE,
+	0x17: formulaErrorREF,
+	0x1D: formulaErrorNAME,
+	0x24: formulaErrorNUM,
+	0x2A: formulaErrorNA,
+}
+
+// xlsRecord directly maps the record in the BIFF8 workbook stream, the
+// continues specifies the data of the CONTINUE records following the record.
+type xlsRecord struct {
+	sid       uint16
+	data      []byte
+	continues [][]byte
+}
+
+// xlsBoundSheet directly maps the sheet information in the BOUNDSHEET record.
+type xlsBoundSheet struct {
+	offset int
+	state  byte
+	typ    byte
+	name   string
+}
+
+// xlsReader directly maps the reader of the BIFF8 workbook stream.
+type xlsReader struct {
+	f       *File
+	stream  []byte
+	sheets  []xlsBoundSheet
+	sst     []string
+	xfs     []int
+	formats map[int]string
+	styles  map[int]int
+}
+
+// xlsDataReader directly maps the reader of the data in a record and the
+// following CONTINUE records, the segments will be read as a continuous byte
+// sequence except the character data of the strings, which has an option
+// flags byte at the beginning of each continued segment.
+type xlsDataReader struct {
+	segments [][]byte
+	seg, pos int
+	err      bool
+}
+
+// extractXLSWorkbook provides a function to extract the BIFF8 workbook
+// stream from the compound file, returns false if the compound file is not
+// a workbook in the XLS format.
+func extractXLSWorkbook(raw []byte) ([]byte, bool) {
+	doc, err := mscfb.New(bytes.NewReader(raw))
+	if err != nil {
+		return nil, false
+	}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name == "Workbook" && len(entry.Path) == 0 {
+			// The stream can't be larger than the compound file
+			size := entry.Size
+			if size > int64(len(raw)) {
+				size = int64(len(raw))
+			}
+			buf := make([]byte, size)
+			n, _ := doc.Read(buf)
+			return buf[:n], true
+		}
+	}
+	return nil, false
+}
+
+// openXLS provides a function to create a workbook by given BIFF8 workbook
+// stream, the sheet names, cell values and the number formats of the cells
+// will be read, and the formula cells will be read as the cached values.
+func openXLS(stream []byte, opts *Options) (*File, error) {
+	r := &xlsReader{f: NewFile(*opts), stream: stream, formats: map[int]string{}, styles: map[int]int{}}
+	if err := r.readGlobals(); err != nil {
+		_ = r.f.Close()
+		return nil, err
+	}
+	if err := r.readSheets(); err != nil {
+		_ = r.f.Close()
+		return nil, err
+	}
+	return r.f, nil
+}
+
+// record provides a function to read the record at the given offset of the
+// workbook stream with the following CONTINUE records, and returns the offset
+// of the next record.
+func (r *xlsReader) record(offset int) (*xlsRecord, int, error) {
+	header := func(offset int) (uint16, int, bool) {
+		if offset+4 > len(r.stream) {
+			return 0, 0, false
+		}
+		size := int(binary.LittleEndian.Uint16(r.stream[offset+2:]))
+		return binary.LittleEndian.Uint16(r.stream[offset:]), size, offset+4+size <= len(r.stream)
+	}
+	sid, size, ok := header(offset)
+	if !ok {
+		return nil, offset, ErrWorkbookFileFormat
+	}
+	rec := &xlsRecord{sid: sid, data: r.stream[offset+4 : offset+4+size]}
+	offset += 4 + size
+	for {
+		sid, size, ok := header(offset)
+		if !ok || sid != xlsRecordContinue {
+			return rec, offset, nil
+		}
+		rec.continues = append(rec.continues, r.stream[offset+4:offset+4+size])
+		offset += 4 + size
+	}
+}
+
+// readGlobals provides a function to read the workbook globals substream,
+// including the sheet information, shared strings table, number formats and
+// cell formats.
+func (r *xlsReader) readGlobals() error {
+	rec, offset, err := r.record(0)
+	if err != nil {
+		return err
+	}
+	if rec.sid != xlsRecordBOF || len(rec.data) < 2 || binary.LittleEndian.Uint16(rec.data) != xlsBIFF8Version {
+		return ErrWorkbookFileFormat
+	}
+	for rec.sid != xlsRecordEOF {
+		if rec, offset, err = r.record(offset); err != nil {
+			return err
+		}
+		d := &xlsDataReader{segments: append([][]byte{rec.data}, rec.continues...)}
+		switch rec.sid {
+		case xlsRecordFilePass:
+			return ErrWorkbookFileFormat
+		case xlsRecordDateMode:
+			if d.uint16() == 1 {
+				err = r.f.SetWorkbookProps(&WorkbookPropsOptions{Date1904: boolPtr(true)})
+			}
+		case xlsRecordBoundSheet:
+			sheet := xlsBoundSheet{offset: int(d.uint32()), state: d.byte() & 0x03, typ: d.byte()}
+			sheet.name = d.chars(int(d.byte()))
+			r.sheets = append(r.sheets, sheet)
+		case xlsRecordFormat:
+			id := int(d.uint16())
+			r.formats[id] = d.string(int(d.uint16()))
+		case xlsRecordXF:
+			d.skip(2)
+			r.xfs = append(r.xfs, int(d.uint16()))
+		case xlsRecordSST:
+			d.skip(4)
+			// Each string takes at least 3 bytes, don't trust the count of the
+			// strings for preallocation
+			count, capacity := int(d.uint32()), d.remaining()/3
+			if count < capacity {
+				capacity = count
+			}
+			r.sst = make([]string, 0, capacity)
+			for i := 0; i < count && !d.err; i++ {
+				r.sst = append(r.sst, d.string(int(d.uint16())))
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if d.err {
+			return ErrWorkbookFileFormat
+		}
+	}
+	return nil
+}
+
+// readSheets provides a function to create the worksheets by the sheet
+// information and read the cells of each worksheet substream.
+func (r *xlsReader) readSheets() error {
+	var count int
+	for _, sheet := range r.sheets {
+		if sheet.typ != 0 {
+			continue
+		}
+		if count == 0 {
+			if err := r.f.SetSheetName(r.f.GetSheetName(0), sheet.name); err != nil {
+				return err
+			}
+		} else if _, err := r.f.NewSheet(sheet.name); err != nil {
+			return err
+		}
+		count++
+		if err := r.readSheet(sheet); err != nil {
+			return err
+		}
+	}
+	for _, sheet := range r.sheets {
+		if sheet.typ == 0 && sheet.state != 0 {
+			if err := r.f.SetSheetVisible(sheet.name, false, sheet.state == 2); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readSheet provides a function to read the cells of the worksheet
+// substream by given sheet information.
+func (r *xlsReader) readSheet(sheet xlsBoundSheet) error {
+	rec, offset, err := r.record(sheet.offset)
+	if err != nil {
+		return err
+	}
+	if rec.sid != xlsRecordBOF {
+		return ErrWorkbookFileFormat
+	}
+	for rec.sid != xlsRecordEOF {
+		if rec, offset, err = r.record(offset); err != nil {
+			return err
+		}
+		d := &xlsDataReader{segments: [][]byte{rec.data}}
+		switch rec.sid {
+		case xlsRecordLabelSST:
+			row, col, xf := int(d.uint16()), int(d.uint16()), int(d.uint16())
+			if idx := int(d.uint32()); idx < len(r.sst) {
+				err = r.setCell(sheet.name, row, col, xf, "s", r.sst[idx])
+			}
+		case xlsRecordLabel:
+			row, col, xf := int(d.uint16()), int(d.uint16()), int(d.uint16())
+			err = r.setCell(sheet.name, row, col, xf, "s", d.string(int(d.uint16())))
+		case xlsRecordNumber:
+			row, col, xf := int(d.uint16()), int(d.uint16()), int(d.uint16())
+			err = r.setCell(sheet.name, row, col, xf, "", formatXLSNumber(d.float64()))
+		case xlsRecordRK:
+			row, col, xf := int(d.uint16()), int(d.uint16()), int(d.uint16())
+			err = r.setCell(sheet.name, row, col, xf, "", formatXLSNumber(decodeXLSRK(d.uint32())))
+		case xlsRecordMulRK:
+			row, col := int(d.uint16()), int(d.uint16())
+			for i := 0; i < (len(rec.data)-6)/6 && err == nil; i++ {
+				xf := int(d.uint16())
+				err = r.setCell(sheet.name, row, col+i, xf, "", formatXLSNumber(decodeXLSRK(d.uint32())))
+			}
+		case xlsRecordBoolErr:
+			row, col, xf := int(d.uint16()), int(d.uint16()), int(d.uint16())
+			val, isErr := d.byte(), d.byte()
+			err = r.setCell(sheet.name, row, col, xf, boolErrType(isErr == 1), boolErrValue(isErr == 1, val))
+		case xlsRecordFormula:
+			row, col, xf := int(d.uint16()), int(d.uint16()), int(d.uint16())
+			result := d.bytes(8)
+			if d.err {
+				break
+			}
+			if result[6] != 0xFF || result[7] != 0xFF {
+				err = r.setCell(sheet.name, row, col, xf, "", formatXLSNumber(math.Float64frombits(binary.LittleEndian.Uint64(result))))
+				break
+			}
+			switch result[0] {
+			case 0x00:
+				if str, ok := r.formulaString(offset); ok {
+					err = r.setCell(sheet.name, row, col, xf, "str", str)
+				}
+			case 0x01, 0x02:
+				err = r.setCell(sheet.name, row, col, xf, boolErrType(result[0] == 0x02), boolErrValue(result[0] == 0x02, result[2]))
+			case 0x03:
+				err = r.setCell(sheet.name, row, col, xf, "str", "")
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if d.err {
+			return ErrWorkbookFileFormat
+		}
+	}
+	return nil
+}
+
+// formulaString provides a function to read the string result of the
+// formula in the STRING record following the FORMULA record at the given
+// offset, the shared, array and table formula records between them will be
+// skipped.
+func (r *xlsReader) formulaString(offset int) (string, bool) {
+	for {
+		rec, next, err := r.record(offset)
+		if err != nil {
+			return "", false
+		}
+		switch rec.sid {
+		case xlsRecordString:
+			d := &xlsDataReader{segments: append([][]byte{rec.data}, rec.continues...)}
+			str := d.string(int(d.uint16()))
+			return str, !d.err
+		case xlsRecordShrFmla, xlsRecordArray, xlsRecordTable:
+			offset = next
+		default:
+			return "", false
+		}
+	}
+}
+
+// setCell provides a function to set the value, data type and the style of
+// the number format of the cell by given zero-based row and column number.
+func (r *xlsReader) setCell(sheet string, row, col, xf int, typ, val string) error {
+	cell, err := CoordinatesToCellName(col+1, row+1)
+	if err != nil {
+		return err
+	}
+	ws, err := r.f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	c, _, _, err := r.f.prepareCell(ws, cell)
+	if err != nil {
+		return err
+	}
+	if c.S, err = r.getStyle(xf); err != nil {
+		return err
+	}
+	if c.T, c.V = typ, val; typ == "s" {
+		c.T, c.V, err = r.f.setCellString(val)
+	}
+	return err
+}
+
+// getStyle provides a function to get the style index of the cell by given
+// cell format index, the style will be created with the number format of the
+// cell format at the first time.
+func (r *xlsReader) getStyle(xf int) (int, error) {
+	if xf >= len(r.xfs) || r.xfs[xf] == 0 {
+		return 0, nil
+	}
+	if styleID, ok := r.styles[xf]; ok {
+		return styleID, nil
+	}
+	style := &Style{NumFmt: r.xfs[xf]}
+	if code, ok := r.formats[r.xfs[xf]]; ok {
+		style = &Style{CustomNumFmt: &code}
+	}
+	styleID, err := r.f.NewStyle(style)
+	r.styles[xf] = styleID
+	return styleID, err
+}
+
+// decodeXLSRK provides a function to decode the RK number.
+func decodeXLSRK(rk uint32) float64 {
+	var num float64
+	if rk&0x02 != 0 {
+		num = float64(int32(rk) >> 2)
+	} else {
+		num = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		num /= 100
+	}
+	return num
+}
+
+// formatXLSNumber provides a function to format the number as the cell value.
+func formatXLSNumber(num float64) string {
+	return strconv.FormatFloat(num, 'f', -1, 64)
+}
+
+// boolErrType returns the cell data type of the boolean or error value.
+func boolErrType(isErr bool) string {
+	if isErr {
+		return "e"
+	}
+	return "b"
+}
+
+// boolErrValue returns the cell value of the boolean or error value.
+func boolErrValue(isErr bool, val byte) string {
+	if isErr {
+		return xlsErrorValues[val]
+	}
+	if val != 0 {
+		return "1"
+	}
+	return "0"
+}
+
+// remaining provides a function to get the number of the unread bytes across
+// the segments.
+func (d *xlsDataReader) remaining() int {
+	n := len(d.segments[d.seg]) - d.pos
+	for _, segment := range d.segments[d.seg+1:] {
+		n += len(segment)
+	}
+	return n
+}
+
+// bytes provides a function to read the given number of bytes across the
+// segments, the zero bytes will be returned if there are not enough bytes.
+func (d *xlsDataReader) bytes(n int) []byte {
+	if n > d.remaining() {
+		d.err = true
+	}
+	if d.err {
+		return make([]byte, n)
+	}
+	buf := make([]byte, 0, n)
+	for len(buf) < n && !d.err {
+		if d.pos >= len(d.segments[d.seg]) {
+			if d.seg+1 >= len(d.segments) {
+				d.err = true
+				break
+			}
+			d.seg, d.pos = d.seg+1, 0
+			continue
+		}
+		end := d.pos + n - len(buf)
+		if end > len(d.segments[d.seg]) {
+			end = len(d.segments[d.seg])
+		}
+		buf = append(buf, d.segments[d.seg][d.pos:end]...)
+		d.pos = end
+	}
+	return buf
+}
+
+// skip provides a function to skip the given number of bytes across the
+// segments.
+func (d *xlsDataReader) skip(n int) {
+	for n > 0 && !d.err {
+		if d.pos >= len(d.segments[d.seg]) {
+			if d.seg+1 >= len(d.segments) {
+				d.err = true
+				break
+			}
+			d.seg, d.pos = d.seg+1, 0
+			continue
+		}
+		step := len(d.segments[d.seg]) - d.pos
+		if step > n {
+			step = n
+		}
+		d.pos, n = d.pos+step, n-step
+	}
+}
+
+// byte provides a function to read an unsigned 8-bit integer.
+func (d *xlsDataReader) byte() byte { return d.bytes(1)[0] }
+
+// uint16 provides a function to read a little-endian unsigned 16-bit integer.
+func (d *xlsDataReader) uint16() uint16 { return binary.LittleEndian.Uint16(d.bytes(2)) }
+
+// uint32 provides a function to read a little-endian unsigned 32-bit integer.
+func (d *xlsDataReader) uint32() uint32 { return binary.LittleEndian.Uint32(d.bytes(4)) }
+
+// float64 provides a function to read a little-endian IEEE 754 number.
+func (d *xlsDataReader) float64() float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(d.bytes(8)))
+}
+
+// chars provides a function to read the option flags and the character data
+// of the string by given number of characters, which is the ShortXLUnicode
+// string without the count of characters.
+func (d *xlsDataReader) chars(cch int) string {
+	high := d.byte()&0x01 != 0
+	return d.characters(cch, high)
+}
+
+// characters provides a function to read the character data by given number
+// of characters, the new option flags will be read if the character data
+// continued in the next segment.
+func (d *xlsDataReader) characters(cch int, high bool) string {
+	capacity := d.remaining()
+	if cch < capacity {
+		capacity = cch
+	}
+	units := make([]uint16, 0, capacity)
+	for len(units) < cch && !d.err {
+		if d.pos >= len(d.segments[d.seg]) {
+			if d.seg+1 >= len(d.segments) {
+				d.err = true
+				break
+			}
+			d.seg, d.pos = d.seg+1, 0
+			high = d.byte()&0x01 != 0
+			continue
+		}
+		if high {
+			units = append(units, d.uint16())
+			continue
+		}
+		units = append(units, uint16(d.byte()))
+	}
+	return string(utf16.Decode(units))
+}
+
+// string provides a function to read the XLUnicodeRichExtendedString by
+// given number of characters, the formatting runs and the phonetic data of
+// the rich string will be skipped.
+func (d *xlsDataReader) string(cch int) string {
+	flags := d.byte()
+	var runs, ext int
+	if flags&0x08 != 0 {
+		runs = int(d.uint16())
+	}
+	if flags&0x04 != 0 {
+		ext = int(d.uint32())
+	}
+	str := d.characters(cch, flags&0x01 != 0)
+	d.skip(runs*4 + ext)
+	return str
+}
@@ -0,0 +1,223 @@
+package excel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// xlsTestRecord returns the BIFF8 record by given record type and data.
+func xlsTestRecord(sid uint16, data ...[]byte) []byte {
+	content := bytes.Join(data, nil)
+	buf := make([]byte, 4, 4+len(content))
+	binary.LittleEndian.PutUint16(buf, sid)
+	binary.LittleEndian.PutUint16(buf[2:], uint16(len(content)))
+	return append(buf, content...)
+}
+
+func xlsTestUint16(values ...int) []byte {
+	buf := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(buf[2*i:], uint16(v))
+	}
+	return buf
+}
+
+func xlsTestUint32(value uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, value)
+	return buf
+}
+
+func xlsTestFloat64(value float64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, math.Float64bits(value))
+	return buf
+}
+
+func xlsTestUTF16(value string) []byte {
+	return xlsTestUint16(func() (units []int) {
+		for _, u := range utf16.Encode([]rune(value)) {
+			units = append(units, int(u))
+		}
+		return
+	}()...)
+}
+
+// xlsTestWorkbook returns the XLS workbook by given records of the workbook
+// globals and each sheet, the offsets of the BOUNDSHEET records will be
+// updated by the position of the sheet substreams.
+func xlsTestWorkbook(globals [][]byte, sheets ...[][]byte) []byte {
+	stream := bytes.Join(globals, nil)
+	var boundSheets []int
+	for offset := 0; offset < len(stream); offset += 4 + int(binary.LittleEndian.Uint16(stream[offset+2:])) {
+		if binary.LittleEndian.Uint16(stream[offset:]) == xlsRecordBoundSheet {
+			boundSheets = append(boundSheets, offset+4)
+		}
+	}
+	for i, sheet := range sheets {
+		binary.LittleEndian.PutUint32(stream[boundSheets[i]:], uint32(len(stream)))
+		stream = append(stream, bytes.Join(sheet, nil)...)
+	}
+	compoundFile := &cfb{
+		paths:   []string{"Root Entry/"},
+		sectors: []sector{{name: "Root Entry", typeID: 5}},
+	}
+	compoundFile.put("Workbook", stream)
+	return compoundFile.write()
+}
+
+func TestOpenXLS(t *testing.T) {
+	bof := func(dt int) []byte {
+		return xlsTestRecord(xlsRecordBOF, xlsTestUint16(xlsBIFF8Version, dt), make([]byte, 12))
+	}
+	eof := xlsTestRecord(xlsRecordEOF)
+	xf := func(ifmt int) []byte { return xlsTestRecord(xlsRecordXF, xlsTestUint16(0, ifmt), make([]byte, 16)) }
+	boundSheet := func(state, dt byte, name string) []byte {
+		return xlsTestRecord(xlsRecordBoundSheet, xlsTestUint32(0), []byte{state, dt, byte(len(name)), 0}, []byte(name))
+	}
+	cell := func(sid uint16, row, col, xf int, data ...[]byte) []byte {
+		return xlsTestRecord(sid, append([][]byte{xlsTestUint16(row, col, xf)}, data...)...)
+	}
+	formula := func(row, col int, result []byte) []byte {
+		return cell(xlsRecordFormula, row, col, 0, result, make([]byte, 6), xlsTestUint16(0))
+	}
+	raw := xlsTestWorkbook([][]byte{
+		bof(0x0005),
+		xlsTestRecord(xlsRecordFormat, xlsTestUint16(164, 5), []byte{0}, []byte("0.000")),
+		xf(0), xf(14), xf(164),
+		boundSheet(0, 0, "Data"), boundSheet(0, 2, "Chart"), boundSheet(1, 0, "Hidden"),
+		// Shared strings table with the compressed, rich and continued strings
+		xlsTestRecord(xlsRecordSST, xlsTestUint32(4), xlsTestUint32(4),
+			xlsTestUint16(5), []byte{0}, []byte("Hello"),
+			xlsTestUint16(3), []byte{0x08}, xlsTestUint16(1), []byte("Run"), make([]byte, 4),
+			xlsTestUint16(2), []byte{0x01}, xlsTestUTF16("表計"),
+			xlsTestUint16(5), []byte{0}, []byte("ab")),
+		xlsTestRecord(xlsRecordContinue, []byte{0x01}, xlsTestUTF16("c日本")),
+		eof,
+	}, [][]byte{
+		bof(0x0010),
+		cell(xlsRecordLabelSST, 0, 0, 0, xlsTestUint32(0)),
+		cell(xlsRecordLabelSST, 0, 1, 0, xlsTestUint32(1)),
+		cell(xlsRecordLabelSST, 0, 2, 0, xlsTestUint32(2)),
+		cell(xlsRecordLabelSST, 0, 3, 0, xlsTestUint32(3)),
+		cell(xlsRecordLabelSST, 0, 4, 0, xlsTestUint32(4)),
+		cell(xlsRecordNumber, 1, 0, 2, xlsTestFloat64(math.Pi)),
+		cell(xlsRecordRK, 1, 1, 1, xlsTestUint32(45000<<2|0x02)),
+		cell(xlsRecordRK, 1, 2, 0, xlsTestUint32(123<<2|0x03)),
+		xlsTestRecord(xlsRecordMulRK, xlsTestUint16(2, 0),
+			xlsTestUint16(0), xlsTestUint32(1<<2|0x02),
+			xlsTestUint16(0), xlsTestUint32(uint32(math.Float64bits(2.5)>>32)),
+			xlsTestUint16(1)),
+		cell(xlsRecordBoolErr, 3, 0, 0, []byte{1, 0}),
+		cell(xlsRecordBoolErr, 3, 1, 0, []byte{0x07, 1}),
+		formula(4, 0, xlsTestFloat64(10)),
+		formula(4, 1, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+		xlsTestRecord(xlsRecordShrFmla, make([]byte, 8)),
+		xlsTestRecord(xlsRecordString, xlsTestUint16(3), []byte{0}, []byte("sum")),
+		formula(4, 2, []byte{1, 0, 1, 0, 0, 0, 0xFF, 0xFF}),
+		formula(4, 3, []byte{2, 0, 0x2A, 0, 0, 0, 0xFF, 0xFF}),
+		formula(4, 4, []byte{3, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+		cell(xlsRecordLabel, 5, 0, 0, xlsTestUint16(5), []byte{0}, []byte("Label")),
+		eof,
+	}, [][]byte{bof(0x0020), eof}, [][]byte{
+		bof(0x0010),
+		cell(xlsRecordNumber, 0, 0, 0, xlsTestFloat64(1)),
+		eof,
+	})
+
+	f, err := OpenReader(bytes.NewReader(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data", "Hidden"}, f.GetSheetList())
+	visible, err := f.GetSheetVisible("Hidden")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	rows, err := f.GetRows("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Hello", "Run", "表計", "abc日本"},
+		{"3.142", "03-15-23", "1.23"},
+		{"1", "2.5"},
+		{"TRUE", "#DIV/0!"},
+		{"10", "sum", "TRUE", "#N/A"},
+		{"Label"},
+	}, rows)
+	for cell, expected := range map[string]CellType{
+		"A1": CellTypeSharedString, "A2": CellTypeUnset, "A4": CellTypeBool,
+		"B4": CellTypeError, "B5": CellTypeFormula, "E5": CellTypeFormula,
+	} {
+		typ, err := f.GetCellType("Data", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, typ, cell)
+	}
+	val, err := f.GetCellValue("Hidden", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", val)
+	path := filepath.Join("test", "TestOpenXLS.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Data", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.142", val)
+	assert.NoError(t, f.Close())
+
+	// Test open the XLS workbook with 1904 date system
+	raw = xlsTestWorkbook([][]byte{
+		bof(0x0005), xlsTestRecord(xlsRecordDateMode, xlsTestUint16(1)), eof,
+	})
+	f, err = OpenReader(bytes.NewReader(raw))
+	assert.NoError(t, err)
+	props, err := f.GetWorkbookProps()
+	assert.NoError(t, err)
+	assert.True(t, *props.Date1904)
+	assert.Equal(t, []string{"Sheet1"}, f.GetSheetList())
+	assert.NoError(t, f.Close())
+
+	// Test open the XLS workbook with unsupported or corrupted stream
+	for _, raw := range [][]byte{
+		xlsTestWorkbook([][]byte{xlsTestRecord(xlsRecordBOF, xlsTestUint16(0x0500, 0x0005))}),
+		xlsTestWorkbook([][]byte{bof(0x0005), xlsTestRecord(xlsRecordFilePass, make([]byte, 6)), eof}),
+		xlsTestWorkbook([][]byte{bof(0x0005)}),
+		xlsTestWorkbook([][]byte{bof(0x0005), xlsTestRecord(xlsRecordSST, xlsTestUint32(1), xlsTestUint32(1)), eof}),
+		xlsTestWorkbook([][]byte{bof(0x0005), xlsTestRecord(xlsRecordSST, xlsTestUint32(1), xlsTestUint32(0x7FFFFFFF)), eof}),
+		xlsTestWorkbook([][]byte{bof(0x0005), boundSheet(0, 0, "Sheet1"), eof}, [][]byte{eof}),
+		xlsTestWorkbook([][]byte{bof(0x0005), boundSheet(0, 0, "Sheet1"), eof}, [][]byte{bof(0x0010), xlsTestRecord(xlsRecordNumber, xlsTestUint16(0)), eof}),
+		xlsTestWorkbook([][]byte{bof(0x0005), boundSheet(0, 0, "Sheet1"), eof}, [][]byte{bof(0x0010)}),
+	} {
+		_, err = OpenReader(bytes.NewReader(raw))
+		assert.Equal(t, ErrWorkbookFileFormat, err)
+	}
+	// Test open the XLS workbook with invalid sheet name
+	_, err = OpenReader(bytes.NewReader(xlsTestWorkbook([][]byte{bof(0x0005), boundSheet(0, 0, "Sheet:1"), eof}, [][]byte{bof(0x0010), eof})))
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	// Test open the XLS workbook with invalid cell reference
+	_, err = OpenReader(bytes.NewReader(xlsTestWorkbook([][]byte{bof(0x0005), boundSheet(0, 0, "Sheet1"), eof}, [][]byte{
+		bof(0x0010), cell(xlsRecordNumber, 0, 0x4000, 0, xlsTestFloat64(1)), eof,
+	})))
+	assert.Equal(t, ErrColumnNumber, err)
+}
+
+func TestXLSDataReader(t *testing.T) {
+	// Test read the workbook stream with a huge count of shared strings
+	stream := bytes.Join([][]byte{
+		xlsTestRecord(xlsRecordBOF, xlsTestUint16(xlsBIFF8Version, 0x0005)),
+		xlsTestRecord(xlsRecordSST, xlsTestUint32(1), xlsTestUint32(0x7FFFFFFF)),
+	}, nil)
+	_, err := openXLS(stream, &Options{})
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+	// Test read the data with insufficient bytes
+	d := &xlsDataReader{segments: [][]byte{{0x01}, {0x02, 0x03}}}
+	assert.Equal(t, 3, d.remaining())
+	assert.Equal(t, []byte{0, 0, 0, 0}, d.bytes(4))
+	assert.True(t, d.err)
+	d = &xlsDataReader{segments: [][]byte{{0x02, 0x00}}}
+	assert.Equal(t, "\x02\x00", d.characters(0x7FFFFFFF, false))
+	assert.True(t, d.err)
+}
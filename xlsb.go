@@ -0,0 +1,446 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Define the record types and the part names of the BIFF12 binary workbook
+// used by the XLSB reader.
+const (
+	xlsbRecordRowHdr        = 0x0000
+	xlsbRecordCellRk        = 0x0002
+	xlsbRecordCellError     = 0x0003
+	xlsbRecordCellBool      = 0x0004
+	xlsbRecordCellReal      = 0x0005
+	xlsbRecordCellSt        = 0x0006
+	xlsbRecordCellIsst      = 0x0007
+	xlsbRecordFmlaString    = 0x0008
+	xlsbRecordFmlaNum       = 0x0009
+	xlsbRecordFmlaBool      = 0x000A
+	xlsbRecordFmlaError     = 0x000B
+	xlsbRecordSSTItem       = 0x0013
+	xlsbRecordFmt           = 0x002C
+	xlsbRecordXF            = 0x002F
+	xlsbRecordCellRString   = 0x003E
+	xlsbRecordWbProp        = 0x0099
+	xlsbRecordBundleSh      = 0x009C
+	xlsbRecordBeginCellXFs  = 0x0269
+	xlsbRecordEndCellXFs    = 0x026A
+	defaultXLSBPathWorkbook = "xl/workbook.bin"
+	defaultXLSBPathWbRels   = "xl/_rels/workbook.bin.rels"
+	defaultXLSBPathSST      = "xl/sharedStrings.bin"
+	defaultXLSBPathStyles   = "xl/styles.bin"
+	xlsbFormulaFuncVariadic = -1
+)
+
+// xlsbFormulaFuncs defined the names and the number of the arguments of the
+// commonly used built-in functions by the function index of the parsed
+// formula tokens, the functions take a variable number of arguments marked
+// as variadic.
+var xlsbFormulaFuncs = map[int]struct {
+	name string
+	argc int
+}{
+	0: {"COUNT", -1}, 1: {"IF", -1}, 2: {"ISNA", 1}, 3: {"ISERROR", 1},
+	4: {"SUM", -1}, 5: {"AVERAGE", -1}, 6: {"MIN", -1}, 7: {"MAX", -1},
+	8: {"ROW", -1}, 9: {"COLUMN", -1}, 10: {"NA", 0}, 11: {"NPV", -1},
+	12: {"STDEV", -1}, 13: {"DOLLAR", -1}, 14: {"FIXED", -1}, 15: {"SIN", 1},
+	16: {"COS", 1}, 17: {"TAN", 1}, 18: {"ATAN", 1}, 19: {"PI", 0},
+	20: {"SQRT", 1}, 21: {"EXP", 1}, 22: {"LN", 1}, 23: {"LOG10", 1},
+	24: {"ABS", 1}, 25: {"INT", 1}, 26: {"SIGN", 1}, 27: {"ROUND", 2},
+	28: {"LOOKUP", -1}, 29: {"INDEX", -1}, 30: {"REPT", 2}, 31: {"MID", 3},
+	32: {"LEN", 1}, 33: {"VALUE", 1}, 34: {"TRUE", 0}, 35: {"FALSE", 0},
+	36: {"AND", -1}, 37: {"OR", -1}, 38: {"NOT", 1}, 39: {"MOD", 2},
+	48: {"TEXT", 2}, 63: {"RAND", 0}, 65: {"DATE", 3}, 66: {"TIME", 3},
+	67: {"DAY", 1}, 68: {"MONTH", 1}, 69: {"YEAR", 1}, 70: {"WEEKDAY", -1},
+	71: {"HOUR", 1}, 72: {"MINUTE", 1}, 73: {"SECOND", 1}, 74: {"NOW", 0},
+	76: {"ROWS", 1}, 77: {"COLUMNS", 1}, 78: {"OFFSET", -1}, 82: {"SEARCH", -1},
+	97: {"ATAN2", 2}, 100: {"CHOOSE", -1}, 101: {"HLOOKUP", -1}, 102: {"VLOOKUP", -1},
+	109: {"LOG", -1}, 111: {"CHAR", 1}, 112: {"LOWER", 1}, 113: {"UPPER", 1},
+	114: {"PROPER", 1}, 115: {"LEFT", -1}, 116: {"RIGHT", -1}, 117: {"EXACT", 2},
+	118: {"TRIM", 1}, 119: {"REPLACE", 4}, 120: {"SUBSTITUTE", -1}, 121: {"CODE", 1},
+	124: {"FIND", -1}, 127: {"ISTEXT", 1}, 128: {"ISNUMBER", 1}, 129: {"ISBLANK", 1},
+	130: {"T", 1}, 131: {"N", 1}, 140: {"DATEVALUE", 1}, 141: {"TIMEVALUE", 1},
+	169: {"COUNTA", -1}, 183: {"PRODUCT", -1}, 184: {"FACT", 1}, 197: {"TRUNC", -1},
+	212: {"ROUNDUP", 2}, 213: {"ROUNDDOWN", 2}, 221: {"TODAY", 0}, 227: {"MEDIAN", -1},
+	228: {"SUMPRODUCT", -1}, 336: {"CONCATENATE", -1}, 337: {"POWER", 2}, 342: {"RADIANS", 1},
+	343: {"DEGREES", 1}, 344: {"SUBTOTAL", -1}, 345: {"SUMIF", -1}, 346: {"COUNTIF", 2},
+	347: {"COUNTBLANK", 1}, 480: {"IFERROR", 2}, 481: {"COUNTIFS", -1}, 482: {"SUMIFS", -1},
+	483: {"AVERAGEIF", -1}, 484: {"AVERAGEIFS", -1},
+}
+
+// xlsbFormulaOperators defined the binary operators of the parsed formula
+// tokens.
+var xlsbFormulaOperators = map[byte]string{
+	0x03: "+", 0x04: "-", 0x05: "*", 0x06: "/", 0x07: "^", 0x08: "&",
+	0x09: "<", 0x0A: "<=", 0x0B: "=", 0x0C: ">=", 0x0D: ">", 0x0E: "<>",
+	0x0F: " ", 0x10: ",", 0x11: ":",
+}
+
+// xlsbSheet directly maps the sheet information in the BrtBundleSh record.
+type xlsbSheet struct {
+	state uint32
+	rID   string
+	name  string
+}
+
+// xlsbReader directly maps the reader of the BIFF12 binary workbook, the
+// cells will be set to the new workbook by the XLS reader.
+type xlsbReader struct {
+	*xlsReader
+	src *File
+}
+
+// openXLSB provides a function to create a workbook by given binary workbook
+// package, the sheet names, cell values, formulas and the number formats of
+// the cells will be read. The formula which contains the unsupported tokens,
+// such as the defined names, external references and the shared formulas,
+// will be read as the cached value only.
+func openXLSB(src *File) (*File, error) {
+	r := &xlsbReader{
+		xlsReader: &xlsReader{f: NewFile(*src.options), formats: map[int]string{}, styles: map[int]int{}},
+		src:       src,
+	}
+	err := r.read()
+	if closeErr := src.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = r.f.Close()
+		return nil, err
+	}
+	return r.f, nil
+}
+
+// read provides a function to read the shared strings, styles, and each
+// worksheet of the binary workbook.
+func (r *xlsbReader) read() error {
+	forEachXLSBRecord(r.src.readBytes(defaultXLSBPathSST), func(typ int, d *xlsDataReader) {
+		if typ == xlsbRecordSSTItem {
+			d.skip(1)
+			r.sst = append(r.sst, d.wideString())
+		}
+	})
+	var inCellXFs bool
+	forEachXLSBRecord(r.src.readBytes(defaultXLSBPathStyles), func(typ int, d *xlsDataReader) {
+		switch typ {
+		case xlsbRecordFmt:
+			id := int(d.uint16())
+			r.formats[id] = d.wideString()
+		case xlsbRecordBeginCellXFs, xlsbRecordEndCellXFs:
+			inCellXFs = typ == xlsbRecordBeginCellXFs
+		case xlsbRecordXF:
+			if inCellXFs {
+				d.skip(2)
+				r.xfs = append(r.xfs, int(d.uint16()))
+			}
+		}
+	})
+	var (
+		sheets []xlsbSheet
+		err    error
+	)
+	if !forEachXLSBRecord(r.src.readBytes(defaultXLSBPathWorkbook), func(typ int, d *xlsDataReader) {
+		switch typ {
+		case xlsbRecordWbProp:
+			if d.uint32()&0x01 != 0 && err == nil {
+				err = r.f.SetWorkbookProps(&WorkbookPropsOptions{Date1904: boolPtr(true)})
+			}
+		case xlsbRecordBundleSh:
+			sheet := xlsbSheet{state: d.uint32()}
+			d.skip(4)
+			sheet.rID, sheet.name = d.wideString(), d.wideString()
+			if !d.err {
+				sheets = append(sheets, sheet)
+			}
+		}
+	}) {
+		return ErrWorkbookFileFormat
+	}
+	if err != nil {
+		return err
+	}
+	return r.readSheets(sheets)
+}
+
+// readSheets provides a function to create the worksheets by the sheet
+// information and read the cells of each worksheet part.
+func (r *xlsbReader) readSheets(sheets []xlsbSheet) error {
+	rels, err := r.src.relsReader(defaultXLSBPathWbRels)
+	if err != nil {
+		return err
+	}
+	var count int
+	for _, sheet := range sheets {
+		var target string
+		if rels != nil {
+			for _, rel := range rels.Relationships {
+				if rel.ID == sheet.rID && rel.Type == SourceRelationshipWorkSheet {
+					target = rel.Target
+				}
+			}
+		}
+		if target == "" {
+			continue
+		}
+		if count == 0 {
+			err = r.f.SetSheetName(r.f.GetSheetName(0), sheet.name)
+		} else {
+			_, err = r.f.NewSheet(sheet.name)
+		}
+		if err != nil {
+			return err
+		}
+		count++
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = "xl/" + target
+		}
+		if err = r.readSheet(sheet.name, r.src.readBytes(target)); err != nil {
+			return err
+		}
+	}
+	for _, sheet := range sheets {
+		if sheet.state == 0 || r.f.getSheetID(sheet.name) == -1 {
+			continue
+		}
+		if err = r.f.SetSheetVisible(sheet.name, false, sheet.state == 2); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// readSheet provides a function to read the cells of the worksheet by given
+// worksheet name and the content of the binary worksheet part.
+func (r *xlsbReader) readSheet(sheet string, content []byte) error {
+	var row int
+	var err error
+	if !forEachXLSBRecord(content, func(typ int, d *xlsDataReader) {
+		if err != nil {
+			return
+		}
+		if typ == xlsbRecordRowHdr {
+			row = int(d.uint32())
+			return
+		}
+		if typ < xlsbRecordCellRk || (typ > xlsbRecordFmlaError && typ != xlsbRecordCellRString) {
+			return
+		}
+		col, xf := int(d.uint32()), int(d.uint32()&0xFFFFFF)
+		var cellType, val string
+		switch typ {
+		case xlsbRecordCellRk:
+			val = formatXLSNumber(decodeXLSRK(d.uint32()))
+		case xlsbRecordCellReal, xlsbRecordFmlaNum:
+			val = formatXLSNumber(d.float64())
+		case xlsbRecordCellError, xlsbRecordFmlaError:
+			cellType, val = boolErrType(true), boolErrValue(true, d.byte())
+		case xlsbRecordCellBool, xlsbRecordFmlaBool:
+			cellType, val = boolErrType(false), boolErrValue(false, d.byte())
+		case xlsbRecordCellSt:
+			cellType, val = "s", d.wideString()
+		case xlsbRecordCellRString:
+			d.skip(1)
+			cellType, val = "s", d.wideString()
+		case xlsbRecordCellIsst:
+			if idx := int(d.uint32()); idx < len(r.sst) {
+				cellType, val = "s", r.sst[idx]
+			}
+		case xlsbRecordFmlaString:
+			cellType, val = "str", d.wideString()
+		}
+		var rgce []byte
+		if typ >= xlsbRecordFmlaString && typ <= xlsbRecordFmlaError {
+			d.skip(2)
+			if cce := int64(d.uint32()); cce <= int64(d.remaining()) {
+				rgce = d.bytes(int(cce))
+			} else {
+				d.err = true
+			}
+		}
+		if d.err {
+			err = ErrWorkbookFileFormat
+			return
+		}
+		// Set the formula before the cached value, since setting the formula
+		// will reset the type of the cell
+		if formula, ok := decodeXLSBFormula(rgce); ok {
+			cell, _ := CoordinatesToCellName(col+1, row+1)
+			if err = r.f.SetCellFormula(sheet, cell, formula); err != nil {
+				return
+			}
+		}
+		err = r.setCell(sheet, row, col, xf, cellType, val)
+	}) {
+		return ErrWorkbookFileFormat
+	}
+	return err
+}
+
+// forEachXLSBRecord provides a function to call the given function with the
+// type and data of each record in the binary part, returns false if the
+// records are corrupted.
+func forEachXLSBRecord(content []byte, fn func(typ int, d *xlsDataReader)) bool {
+	varint := func(pos, max int) (int, int, bool) {
+		var val int
+		for i := 0; i < max; i++ {
+			if pos+i >= len(content) {
+				return 0, pos, false
+			}
+			val |= int(content[pos+i]&0x7F) << uint(7*i)
+			if content[pos+i]&0x80 == 0 {
+				return val, pos + i + 1, true
+			}
+		}
+		return val, pos + max, true
+	}
+	for pos := 0; pos < len(content); {
+		typ, next, ok := varint(pos, 2)
+		if !ok {
+			return false
+		}
+		size, next, ok := varint(next, 4)
+		if !ok || next+size > len(content) {
+			return false
+		}
+		fn(typ, &xlsDataReader{segments: [][]byte{content[next : next+size]}})
+		pos = next + size
+	}
+	return true
+}
+
+// decodeXLSBFormula provides a function to decode the parsed formula tokens
+// of the cell into the formula text, returns false if the tokens contain the
+// unsupported tokens.
+func decodeXLSBFormula(rgce []byte) (string, bool) {
+	var stack []string
+	d := &xlsDataReader{segments: [][]byte{rgce}}
+	pop := func(n int) []string {
+		if n > len(stack) {
+			d.err = true
+			return make([]string, n)
+		}
+		args := append([]string{}, stack[len(stack)-n:]...)
+		stack = stack[:len(stack)-n]
+		return args
+	}
+	ref := func(row uint32, col uint16) string {
+		cell, _ := ColumnNumberToName(int(col&0x3FFF) + 1)
+		if col&0x4000 == 0 {
+			cell = "$" + cell
+		}
+		if col&0x8000 == 0 {
+			return cell + "$" + strconv.Itoa(int(row)+1)
+		}
+		return cell + strconv.Itoa(int(row)+1)
+	}
+	for d.pos < len(rgce) && !d.err {
+		ptg := d.byte()
+		if ptg&0x60 != 0 {
+			ptg = ptg&0x1F | 0x20
+		}
+		if op, ok := xlsbFormulaOperators[ptg]; ok {
+			args := pop(2)
+			stack = append(stack, args[0]+op+args[1])
+			continue
+		}
+		switch ptg {
+		case 0x12, 0x13:
+			stack = append(stack, map[byte]string{0x12: "+", 0x13: "-"}[ptg]+pop(1)[0])
+		case 0x14:
+			stack = append(stack, pop(1)[0]+"%")
+		case 0x15:
+			stack = append(stack, "("+pop(1)[0]+")")
+		case 0x16:
+			stack = append(stack, "")
+		case 0x17:
+			stack = append(stack, `"`+strings.ReplaceAll(d.characters(int(d.uint16()), true), `"`, `""`)+`"`)
+		case 0x19:
+			attr := d.byte()
+			switch {
+			case attr&0x04 != 0:
+				d.skip(int(d.uint16())*2 + 2)
+			case attr&0x10 != 0:
+				d.skip(2)
+				stack = append(stack, "SUM("+pop(1)[0]+")")
+			default:
+				d.skip(2)
+			}
+		case 0x1C:
+			stack = append(stack, xlsErrorValues[d.byte()])
+		case 0x1D:
+			stack = append(stack, map[bool]string{true: "TRUE", false: "FALSE"}[d.byte() != 0])
+		case 0x1E:
+			stack = append(stack, strconv.Itoa(int(d.uint16())))
+		case 0x1F:
+			num := d.float64()
+			if math.IsInf(num, 0) || math.IsNaN(num) {
+				return "", false
+			}
+			stack = append(stack, formatXLSNumber(num))
+		case 0x21, 0x22:
+			argc := xlsbFormulaFuncVariadic
+			if ptg == 0x22 {
+				argc = int(d.byte())
+			}
+			fn, ok := xlsbFormulaFuncs[int(d.uint16()&0x7FFF)]
+			if !ok {
+				return "", false
+			}
+			if argc == xlsbFormulaFuncVariadic {
+				if argc = fn.argc; argc == xlsbFormulaFuncVariadic {
+					return "", false
+				}
+			}
+			stack = append(stack, fn.name+"("+strings.Join(pop(argc), ",")+")")
+		case 0x24:
+			row, col := d.uint32(), d.uint16()
+			stack = append(stack, ref(row, col))
+		case 0x25:
+			rowFirst, rowLast, colFirst, colLast := d.uint32(), d.uint32(), d.uint16(), d.uint16()
+			stack = append(stack, ref(rowFirst, colFirst)+":"+ref(rowLast, colLast))
+		case 0x2A:
+			d.skip(6)
+			stack = append(stack, formulaErrorREF)
+		case 0x2B:
+			d.skip(12)
+			stack = append(stack, formulaErrorREF)
+		default:
+			return "", false
+		}
+	}
+	if d.err || len(stack) != 1 {
+		return "", false
+	}
+	return stack[0], true
+}
+
+// wideString provides a function to read the XLWideString, which is the
+// Unicode string with the count of characters in a 32-bit integer, the
+// null string of the XLNullableWideString will be read as the empty string.
+func (d *xlsDataReader) wideString() string {
+	cch := d.uint32()
+	if cch == math.MaxUint32 {
+		return ""
+	}
+	if int64(cch)*2 > int64(d.remaining()) {
+		d.err = true
+		return ""
+	}
+	return d.characters(int(cch), true)
+}
@@ -0,0 +1,218 @@
+package excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// xlsbTestRecord returns the BIFF12 record by given record type and data.
+func xlsbTestRecord(typ int, data ...[]byte) []byte {
+	content := bytes.Join(data, nil)
+	var buf []byte
+	for _, v := range []struct{ val, max int }{{typ, 2}, {len(content), 4}} {
+		for i := 0; i < v.max; i++ {
+			b := byte(v.val>>uint(7*i)) & 0x7F
+			if v.val>>uint(7*(i+1)) != 0 {
+				b |= 0x80
+			}
+			buf = append(buf, b)
+			if b&0x80 == 0 {
+				break
+			}
+		}
+	}
+	return append(buf, content...)
+}
+
+func xlsbTestWideString(value string) []byte {
+	return append(xlsTestUint32(uint32(len([]rune(value)))), xlsTestUTF16(value)...)
+}
+
+func xlsbTestCell(col, xf int) []byte {
+	return append(xlsTestUint32(uint32(col)), xlsTestUint32(uint32(xf))...)
+}
+
+func xlsbTestFormula(rgce ...[]byte) []byte {
+	tokens := bytes.Join(rgce, nil)
+	return bytes.Join([][]byte{xlsTestUint16(0), xlsTestUint32(uint32(len(tokens))), tokens, xlsTestUint32(0)}, nil)
+}
+
+func xlsbTestRef(ptg byte, row, col int) []byte {
+	return append(append([]byte{ptg}, xlsTestUint32(uint32(row))...), xlsTestUint16(col)...)
+}
+
+// xlsbTestWorkbook returns the XLSB workbook package by given parts.
+func xlsbTestWorkbook(t *testing.T, parts map[string][]byte) []byte {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestOpenXLSB(t *testing.T) {
+	rels := []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="` + SourceRelationshipWorkSheet + `" Target="worksheets/sheet1.bin"/>` +
+		`<Relationship Id="rId2" Type="` + SourceRelationshipWorkSheet + `" Target="/xl/worksheets/sheet2.bin"/>` +
+		`<Relationship Id="rId3" Type="` + SourceRelationshipChartsheet + `" Target="chartsheets/sheet1.bin"/></Relationships>`)
+	bundleSh := func(state int, rID, name string) []byte {
+		return xlsbTestRecord(xlsbRecordBundleSh, xlsTestUint32(uint32(state)), xlsTestUint32(1), xlsbTestWideString(rID), xlsbTestWideString(name))
+	}
+	rowHdr := func(row int) []byte {
+		return xlsbTestRecord(xlsbRecordRowHdr, xlsTestUint32(uint32(row)), make([]byte, 13))
+	}
+	parts := map[string][]byte{
+		defaultXLSBPathWbRels: rels,
+		defaultXLSBPathWorkbook: bytes.Join([][]byte{
+			xlsbTestRecord(xlsbRecordWbProp, xlsTestUint32(0), xlsTestUint32(0), xlsbTestWideString("")),
+			bundleSh(0, "rId1", "Data"), bundleSh(1, "rId2", "Hidden"), bundleSh(0, "rId3", "Chart"),
+		}, nil),
+		defaultXLSBPathSST: bytes.Join([][]byte{
+			xlsbTestRecord(xlsbRecordSSTItem, []byte{0}, xlsbTestWideString("Hello")),
+			xlsbTestRecord(xlsbRecordSSTItem, []byte{0}, xlsbTestWideString("表計算")),
+		}, nil),
+		defaultXLSBPathStyles: bytes.Join([][]byte{
+			xlsbTestRecord(xlsbRecordFmt, xlsTestUint16(164), xlsbTestWideString("0.000")),
+			xlsbTestRecord(xlsbRecordXF, xlsTestUint16(0, 0), make([]byte, 12)),
+			xlsbTestRecord(xlsbRecordBeginCellXFs, xlsTestUint32(3)),
+			xlsbTestRecord(xlsbRecordXF, xlsTestUint16(0, 0), make([]byte, 12)),
+			xlsbTestRecord(xlsbRecordXF, xlsTestUint16(0, 14), make([]byte, 12)),
+			xlsbTestRecord(xlsbRecordXF, xlsTestUint16(0, 164), make([]byte, 12)),
+			xlsbTestRecord(xlsbRecordEndCellXFs),
+		}, nil),
+		"xl/worksheets/sheet1.bin": bytes.Join([][]byte{
+			rowHdr(0),
+			xlsbTestRecord(xlsbRecordCellIsst, xlsbTestCell(0, 0), xlsTestUint32(0)),
+			xlsbTestRecord(xlsbRecordCellSt, xlsbTestCell(1, 0), xlsbTestWideString("Inline")),
+			xlsbTestRecord(xlsbRecordCellRString, xlsbTestCell(2, 0), []byte{0}, xlsbTestWideString("Rich")),
+			xlsbTestRecord(xlsbRecordCellReal, xlsbTestCell(3, 2), xlsTestFloat64(math.Pi)),
+			rowHdr(1),
+			xlsbTestRecord(xlsbRecordCellRk, xlsbTestCell(0, 1), xlsTestUint32(45000<<2|0x02)),
+			xlsbTestRecord(xlsbRecordCellBool, xlsbTestCell(1, 0), []byte{1}),
+			xlsbTestRecord(xlsbRecordCellError, xlsbTestCell(2, 0), []byte{0x07}),
+			xlsbTestRecord(xlsbRecordCellIsst, xlsbTestCell(3, 0), xlsTestUint32(1)),
+			rowHdr(2),
+			xlsbTestRecord(xlsbRecordFmlaNum, xlsbTestCell(0, 0), xlsTestFloat64(7.28), xlsbTestFormula(
+				xlsbTestRef(0x24, 0, 3), xlsbTestRef(0x24, 0, 3|0xC000), []byte{0x22, 2}, xlsTestUint16(4),
+				[]byte{0x1E}, xlsTestUint16(2), []byte{0x05}, []byte{0x1E}, xlsTestUint16(1), []byte{0x03},
+			)),
+			xlsbTestRecord(xlsbRecordFmlaString, xlsbTestCell(1, 0), xlsbTestWideString(`a"bHello`), xlsbTestFormula(
+				[]byte{0x17}, xlsTestUint16(3), xlsTestUTF16(`a"b`), xlsbTestRef(0x44, 0, 0xC000),
+				[]byte{0x42, 2}, xlsTestUint16(336),
+			)),
+			xlsbTestRecord(xlsbRecordFmlaBool, xlsbTestCell(2, 0), []byte{1}, xlsbTestFormula(
+				[]byte{0x1D, 1}, []byte{0x1D, 0}, []byte{0x41}, xlsTestUint16(38), []byte{0x22, 2}, xlsTestUint16(36),
+			)),
+			xlsbTestRecord(xlsbRecordFmlaError, xlsbTestCell(3, 0), []byte{0x1D}, xlsbTestFormula(
+				[]byte{0x23}, xlsTestUint32(1),
+			)),
+			xlsbTestRecord(xlsbRecordFmlaNum, xlsbTestCell(4, 0), xlsTestFloat64(-0.5), xlsbTestFormula(
+				[]byte{0x25}, xlsTestUint32(0), xlsTestUint32(1), xlsTestUint16(0xC000, 0xC001),
+				[]byte{0x19, 0x10}, xlsTestUint16(0), []byte{0x15, 0x13, 0x14},
+			)),
+		}, nil),
+		"xl/worksheets/sheet2.bin": bytes.Join([][]byte{
+			rowHdr(1048575),
+			xlsbTestRecord(xlsbRecordCellReal, xlsbTestCell(16383, 0), xlsTestFloat64(1)),
+		}, nil),
+	}
+	f, err := OpenReader(bytes.NewReader(xlsbTestWorkbook(t, parts)))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data", "Hidden"}, f.GetSheetList())
+	visible, err := f.GetSheetVisible("Hidden")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	rows, err := f.GetRows("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Hello", "Inline", "Rich", "3.142"},
+		{"03-15-23", "TRUE", "#DIV/0!", "表計算"},
+		{"7.28", `a"bHello`, "TRUE", "#NAME?", "-0.5"},
+	}, rows)
+	for cell, expected := range map[string]string{
+		"A3": "SUM($D$1,D1)*2+1", "B3": `CONCATENATE("a""b",A1)`,
+		"C3": "AND(TRUE,NOT(FALSE))", "D3": "", "E3": "-(SUM(A1:B2))%",
+	} {
+		formula, err := f.GetCellFormula("Data", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	val, err := f.GetCellValue("Hidden", "XFD1048576")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", val)
+	assert.NoError(t, f.Close())
+
+	// Test open the XLSB workbook with 1904 date system and without worksheets
+	f, err = OpenReader(bytes.NewReader(xlsbTestWorkbook(t, map[string][]byte{
+		defaultXLSBPathWorkbook: xlsbTestRecord(xlsbRecordWbProp, xlsTestUint32(1), xlsTestUint32(0), xlsbTestWideString("")),
+	})))
+	assert.NoError(t, err)
+	props, err := f.GetWorkbookProps()
+	assert.NoError(t, err)
+	assert.True(t, *props.Date1904)
+	assert.Equal(t, []string{"Sheet1"}, f.GetSheetList())
+	assert.NoError(t, f.Close())
+
+	// Test open the XLSB workbook with corrupted records
+	for _, parts := range []map[string][]byte{
+		{defaultXLSBPathWorkbook: {0x9C}},
+		{defaultXLSBPathWorkbook: {0x9C, 0x01, 0x10}},
+		{defaultXLSBPathWorkbook: bundleSh(0, "rId1", "Data"), defaultXLSBPathWbRels: rels, "xl/worksheets/sheet1.bin": {0x80}},
+		{defaultXLSBPathWorkbook: bundleSh(0, "rId1", "Data"), defaultXLSBPathWbRels: rels, "xl/worksheets/sheet1.bin": xlsbTestRecord(xlsbRecordCellSt, xlsbTestCell(0, 0), xlsTestUint32(10))},
+		{defaultXLSBPathWorkbook: bundleSh(0, "rId1", "Data"), defaultXLSBPathWbRels: rels, "xl/worksheets/sheet1.bin": xlsbTestRecord(xlsbRecordFmlaNum, xlsbTestCell(0, 0), xlsTestFloat64(1), xlsTestUint16(0), xlsTestUint32(math.MaxUint32))},
+	} {
+		_, err = OpenReader(bytes.NewReader(xlsbTestWorkbook(t, parts)))
+		assert.Equal(t, ErrWorkbookFileFormat, err)
+	}
+	// Test open the XLSB workbook with invalid sheet name
+	_, err = OpenReader(bytes.NewReader(xlsbTestWorkbook(t, map[string][]byte{
+		defaultXLSBPathWorkbook: bundleSh(0, "rId1", "Sheet:1"), defaultXLSBPathWbRels: rels,
+	})))
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	// Test open the XLSB workbook with invalid cell reference
+	_, err = OpenReader(bytes.NewReader(xlsbTestWorkbook(t, map[string][]byte{
+		defaultXLSBPathWorkbook: bundleSh(0, "rId1", "Data"), defaultXLSBPathWbRels: rels,
+		"xl/worksheets/sheet1.bin": xlsbTestRecord(xlsbRecordCellReal, xlsbTestCell(16384, 0), xlsTestFloat64(1)),
+	})))
+	assert.Equal(t, ErrColumnNumber, err)
+	// Test open the XLSB workbook with unsupported charset workbook relationships
+	_, err = OpenReader(bytes.NewReader(xlsbTestWorkbook(t, map[string][]byte{
+		defaultXLSBPathWorkbook: bundleSh(0, "rId1", "Data"), defaultXLSBPathWbRels: MacintoshCyrillicCharset,
+	})))
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestDecodeXLSBFormula(t *testing.T) {
+	for _, c := range []struct {
+		rgce     []byte
+		expected string
+		ok       bool
+	}{
+		{bytes.Join([][]byte{{0x16, 0x1E}, xlsTestUint16(1), {0x22, 2}, xlsTestUint16(1)}, nil), "IF(,1)", true},
+		{bytes.Join([][]byte{{0x2A}, make([]byte, 6), {0x2B}, make([]byte, 12), {0x10}}, nil), "#REF!,#REF!", true},
+		{bytes.Join([][]byte{{0x1C, 0x2A, 0x12}}, nil), "+#N/A", true},
+		{bytes.Join([][]byte{{0x19, 0x04}, xlsTestUint16(1, 0, 0), {0x1E}, xlsTestUint16(5)}, nil), "5", true},
+		{bytes.Join([][]byte{{0x19, 0x40}, xlsTestUint16(0), {0x1F}, xlsTestFloat64(0.25)}, nil), "0.25", true},
+		{bytes.Join([][]byte{{0x1F}, xlsTestFloat64(math.NaN())}, nil), "", false},
+		{bytes.Join([][]byte{{0x21}, xlsTestUint16(4)}, nil), "", false},
+		{bytes.Join([][]byte{{0x21}, xlsTestUint16(1000)}, nil), "", false},
+		{bytes.Join([][]byte{{0x1E}, xlsTestUint16(1), {0x1E}, xlsTestUint16(2)}, nil), "", false},
+		{[]byte{0x03}, "", false},
+		{[]byte{0x01}, "", false},
+		{[]byte{0x1E}, "", false},
+		{nil, "", false},
+	} {
+		formula, ok := decodeXLSBFormula(c.rgce)
+		assert.Equal(t, c.ok, ok, c.expected)
+		assert.Equal(t, c.expected, formula)
+	}
+}
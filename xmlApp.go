@@ -73,3 +73,61 @@ type xlsxVectorLpstr struct {
 type xlsxDigSig struct {
 	Content string `xml:",innerxml"`
 }
+
+// CustomProperty directly maps the custom property of the workbook. The
+// value data type may be one of the following: int32, float64, string, bool,
+// time.Time or nil. The LinkTarget specifies the name of a defined name in
+// the workbook scope which the value of the property linked to.
+type CustomProperty struct {
+	Name       string
+	Value      interface{}
+	LinkTarget string
+}
+
+// xlsxCustomProperties is the root element of the custom file properties
+// part, which contains all the custom properties of the document.
+type xlsxCustomProperties struct {
+	XMLName  xml.Name             `xml:"http://schemas.openxmlformats.org/officeDocument/2006/custom-properties Properties"`
+	Vt       string               `xml:"xmlns:vt,attr"`
+	Property []xlsxCustomProperty `xml:"property"`
+}
+
+// xlsxCustomProperty directly maps the property element, which specifies a
+// single custom file property with the format identifier, unique property
+// identifier, name, the optional linked defined name and the value in one
+// of the variant types.
+type xlsxCustomProperty struct {
+	FmtID      string   `xml:"fmtid,attr"`
+	PID        int      `xml:"pid,attr"`
+	Name       string   `xml:"name,attr"`
+	LinkTarget string   `xml:"linkTarget,attr,omitempty"`
+	Lpwstr     *string  `xml:"vt:lpwstr"`
+	I4         *int32   `xml:"vt:i4"`
+	R8         *float64 `xml:"vt:r8"`
+	Bool       *bool    `xml:"vt:bool"`
+	FileTime   *string  `xml:"vt:filetime"`
+	Content    string   `xml:",innerxml"`
+}
+
+// decodeCustomProperties defines the structure used to parse the custom file
+// properties part.
+type decodeCustomProperties struct {
+	XMLName  xml.Name               `xml:"http://schemas.openxmlformats.org/officeDocument/2006/custom-properties Properties"`
+	Property []decodeCustomProperty `xml:"property"`
+}
+
+// decodeCustomProperty defines the structure used to parse the property
+// element of the custom file properties part.
+type decodeCustomProperty struct {
+	FmtID      string   `xml:"fmtid,attr"`
+	PID        int      `xml:"pid,attr"`
+	Name       string   `xml:"name,attr"`
+	LinkTarget string   `xml:"linkTarget,attr,omitempty"`
+	Lpwstr     *string  `xml:"http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes lpwstr"`
+	Lpstr      *string  `xml:"http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes lpstr"`
+	I4         *int32   `xml:"http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes i4"`
+	R8         *float64 `xml:"http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes r8"`
+	Bool       *bool    `xml:"http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes bool"`
+	FileTime   *string  `xml:"http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes filetime"`
+	Content    string   `xml:",innerxml"`
+}
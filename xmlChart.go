@@ -99,13 +99,16 @@ type aBodyPr struct {
 	Vert             string  `xml:"vert,attr,omitempty"`
 	VertOverflow     string  `xml:"vertOverflow,attr,omitempty"`
 	Wrap             string  `xml:"wrap,attr,omitempty"`
+	NoAutofit        *string `xml:"a:noAutofit"`
+	NormAutofit      *string `xml:"a:normAutofit"`
+	SpAutoFit        *string `xml:"a:spAutoFit"`
 }
 
 // aP (Paragraph) directly maps the a:p element. This element specifies a
 // paragraph of content in the document.
 type aP struct {
 	PPr        *aPPr        `xml:"a:pPr"`
-	R          *aR          `xml:"a:r"`
+	R          []*aR        `xml:"a:r"`
 	EndParaRPr *aEndParaRPr `xml:"a:endParaRPr"`
 }
 
@@ -116,7 +119,8 @@ type aP struct {
 // formatting, since they are directly applied to the paragraph and supersede
 // any formatting from styles.
 type aPPr struct {
-	DefRPr aRPr `xml:"a:defRPr"`
+	Algn   string `xml:"algn,attr,omitempty"`
+	DefRPr aRPr   `xml:"a:defRPr"`
 }
 
 // aSolidFill (Solid Fill) directly maps the solidFill element. This element
@@ -350,6 +354,7 @@ type cAxs struct {
 	AxPos          *attrValString `xml:"axPos"`
 	MajorGridlines *cChartLines   `xml:"majorGridlines"`
 	MinorGridlines *cChartLines   `xml:"minorGridlines"`
+	Title          *cTitle        `xml:"title"`
 	NumFmt         *cNumFmt       `xml:"numFmt"`
 	MajorTickMark  *attrValString `xml:"majorTickMark"`
 	MinorTickMark  *attrValString `xml:"minorTickMark"`
@@ -401,6 +406,7 @@ type cSer struct {
 	DLbls            *cDLbls      `xml:"dLbls"`
 	Marker           *cMarker     `xml:"marker"`
 	InvertIfNegative *attrValBool `xml:"invertIfNegative"`
+	ErrBars          []*cErrBars  `xml:"errBars"`
 	Cat              *cCat        `xml:"cat"`
 	Val              *cVal        `xml:"val"`
 	XVal             *cCat        `xml:"xVal"`
@@ -408,6 +414,7 @@ type cSer struct {
 	Smooth           *attrValBool `xml:"smooth"`
 	BubbleSize       *cVal        `xml:"bubbleSize"`
 	Bubble3D         *attrValBool `xml:"bubble3D"`
+	ExtLst           *xlsxExtLst  `xml:"extLst"`
 }
 
 // cMarker (Marker) directly maps the marker element. This element specifies a
@@ -418,6 +425,20 @@ type cMarker struct {
 	SpPr   *cSpPr         `xml:"spPr"`
 }
 
+// cErrBars (Error Bars) directly maps the errBars element. This element
+// specifies the error bars of the series, including the direction, the type
+// of the error bars and the values of the error amount.
+type cErrBars struct {
+	ErrDir     *attrValString `xml:"errDir"`
+	ErrBarType *attrValString `xml:"errBarType"`
+	ErrValType *attrValString `xml:"errValType"`
+	NoEndCap   *attrValBool   `xml:"noEndCap"`
+	Plus       *cVal          `xml:"plus"`
+	Minus      *cVal          `xml:"minus"`
+	Val        *attrValFloat  `xml:"val"`
+	SpPr       *cSpPr         `xml:"spPr"`
+}
+
 // cDPt (Data Point) directly maps the dPt element. This element specifies a
 // single data point.
 type cDPt struct {
@@ -430,6 +451,7 @@ type cDPt struct {
 // specifies the data used for the category axis.
 type cCat struct {
 	StrRef *cStrRef `xml:"strRef"`
+	StrLit *cStrLit `xml:"strLit"`
 }
 
 // cStrRef (String Reference) directly maps the strRef element. This element
@@ -447,6 +469,14 @@ type cStrCache struct {
 	PtCount *attrValInt `xml:"ptCount"`
 }
 
+// cStrLit (String Literal) directly maps the strLit element. This element
+// specifies a set of string literals used for a chart without a reference
+// to the worksheet.
+type cStrLit struct {
+	PtCount *attrValInt `xml:"ptCount"`
+	Pt      []*cPt      `xml:"pt"`
+}
+
 // cPt directly maps the pt element. This element specifies data for a
 // particular data point.
 type cPt struct {
@@ -458,6 +488,7 @@ type cPt struct {
 // which shall be used to define the location of data markers on a chart.
 type cVal struct {
 	NumRef *cNumRef `xml:"numRef"`
+	NumLit *cNumLit `xml:"numLit"`
 }
 
 // cNumRef directly maps the numRef element. This element specifies a
@@ -475,18 +506,56 @@ type cNumCache struct {
 	PtCount    *attrValInt `xml:"ptCount"`
 }
 
+// cNumLit (Number Literal) directly maps the numLit element. This element
+// specifies a set of numbers used for a chart without a reference to the
+// worksheet.
+type cNumLit struct {
+	FormatCode string      `xml:"formatCode,omitempty"`
+	PtCount    *attrValInt `xml:"ptCount"`
+	Pt         []*cPt      `xml:"pt"`
+}
+
 // cDLbls (Data Labels) directly maps the dLbls element. This element serves
 // as a root element that specifies the settings for the data labels for an
 // entire series or the entire chart. It contains child elements that specify
 // the specific formatting and positioning settings.
 type cDLbls struct {
-	ShowLegendKey   *attrValBool `xml:"showLegendKey"`
-	ShowVal         *attrValBool `xml:"showVal"`
-	ShowCatName     *attrValBool `xml:"showCatName"`
-	ShowSerName     *attrValBool `xml:"showSerName"`
-	ShowPercent     *attrValBool `xml:"showPercent"`
-	ShowBubbleSize  *attrValBool `xml:"showBubbleSize"`
-	ShowLeaderLines *attrValBool `xml:"showLeaderLines"`
+	DLbl            []*cDLbl       `xml:"dLbl"`
+	NumFmt          *cNumFmt       `xml:"numFmt"`
+	DLblPos         *attrValString `xml:"dLblPos"`
+	ShowLegendKey   *attrValBool   `xml:"showLegendKey"`
+	ShowVal         *attrValBool   `xml:"showVal"`
+	ShowCatName     *attrValBool   `xml:"showCatName"`
+	ShowSerName     *attrValBool   `xml:"showSerName"`
+	ShowPercent     *attrValBool   `xml:"showPercent"`
+	ShowBubbleSize  *attrValBool   `xml:"showBubbleSize"`
+	Separator       *string        `xml:"separator"`
+	ShowLeaderLines *attrValBool   `xml:"showLeaderLines"`
+	ExtLst          *xlsxExtLst    `xml:"extLst"`
+}
+
+// cDLbl (Data Label) directly maps the dLbl element. This element specifies
+// the settings of the data label for a single data point.
+type cDLbl struct {
+	IDx            *attrValInt    `xml:"idx"`
+	NumFmt         *cNumFmt       `xml:"numFmt"`
+	DLblPos        *attrValString `xml:"dLblPos"`
+	ShowLegendKey  *attrValBool   `xml:"showLegendKey"`
+	ShowVal        *attrValBool   `xml:"showVal"`
+	ShowCatName    *attrValBool   `xml:"showCatName"`
+	ShowSerName    *attrValBool   `xml:"showSerName"`
+	ShowPercent    *attrValBool   `xml:"showPercent"`
+	ShowBubbleSize *attrValBool   `xml:"showBubbleSize"`
+	Separator      *string        `xml:"separator"`
+	ExtLst         *xlsxExtLst    `xml:"extLst"`
+}
+
+// c15DataLabelsRange directly maps the c15:datalabelsRange element. This
+// element specifies the cell range of the values shown in the data labels
+// of the series.
+type c15DataLabelsRange struct {
+	XMLName xml.Name `xml:"c15:datalabelsRange"`
+	F       string   `xml:"c15:f"`
 }
 
 // cLegend (Legend) directly maps the legend element. This element specifies
@@ -530,6 +599,15 @@ type ChartAxis struct {
 	Minimum        *float64
 	Font           Font
 	LogBase        float64
+	Secondary      bool
+	Title          ChartTitle
+	NumFmt         ChartNumFmt
+}
+
+// ChartNumFmt directly maps the number format settings of the chart axis.
+type ChartNumFmt struct {
+	CustomNumFmt string
+	SourceLinked bool
 }
 
 // ChartDimension directly maps the dimension of the chart.
@@ -562,9 +640,36 @@ type Chart struct {
 	PlotArea     ChartPlotArea
 	ShowBlanksAs string
 	HoleSize     int
+	Name         string
+	Layout       ChartLayout
 	order        int
 }
 
+// ExportedChart directly maps the chart exported from the worksheet or
+// chartsheet. The Chart is the format settings of the chart, the Part is the
+// chart part, and the Images are the image parts referenced by the chart.
+type ExportedChart struct {
+	Chart  Chart
+	Part   ExportedPart
+	Images []ExportedPart
+}
+
+// ChartLayout directly maps the layout properties of the extended chart
+// types, such as the subtotals of the waterfall chart, the binning of the
+// histogram and pareto chart, the statistics of the box and whisker chart
+// and the parent label layout of the treemap chart.
+type ChartLayout struct {
+	Subtotals         []int
+	BinSize           float64
+	BinCount          int
+	QuartileMethod    string
+	ShowMeanLine      bool
+	ShowMeanMarker    *bool
+	ShowInnerPoints   bool
+	ShowOutliers      *bool
+	ParentLabelLayout string
+}
+
 // ChartLegend directly maps the format settings of the chart legend.
 type ChartLegend struct {
 	Position      string
@@ -586,11 +691,66 @@ type ChartLine struct {
 
 // ChartSeries directly maps the format settings of the chart series.
 type ChartSeries struct {
-	Name       string
-	Categories string
-	Values     string
-	Line       ChartLine
-	Marker     ChartMarker
+	Name              string
+	Categories        string
+	CategoriesLiteral []string
+	Values            string
+	ValuesLiteral     []float64
+	Line              ChartLine
+	Marker            ChartMarker
+	ErrorBars         ChartErrorBars
+	XErrorBars        ChartErrorBars
+	DataLabel         *ChartDataLabel
+	DataPointLabels   []ChartDataPointLabel
+}
+
+// ChartDataLabel directly maps the format settings of the data labels of the
+// chart series or data point.
+type ChartDataLabel struct {
+	Position        string
+	NumFmt          ChartNumFmt
+	CellRange       string
+	Separator       string
+	ShowLegendKey   bool
+	ShowVal         bool
+	ShowCatName     bool
+	ShowSerName     bool
+	ShowPercent     bool
+	ShowBubbleSize  bool
+	ShowLeaderLines bool
+}
+
+// ChartDataPointLabel directly maps the format settings of the data label of
+// a single data point in the chart series.
+type ChartDataPointLabel struct {
+	Index int
+	Label ChartDataLabel
+}
+
+// ChartErrorBars directly maps the format settings of the error bars of the
+// chart series.
+type ChartErrorBars struct {
+	Type      string
+	Direction string
+	Value     float64
+	Plus      string
+	Minus     string
+	NoEndCap  bool
+	Line      ChartLine
+}
+
+// ChartData directly maps the categories and series of the chart data which
+// stored in the chart data worksheet.
+type ChartData struct {
+	Categories []string
+	Series     []ChartDataSeries
+}
+
+// ChartDataSeries directly maps the name and values of a series in the chart
+// data.
+type ChartDataSeries struct {
+	Name   string
+	Values []float64
 }
 
 // ChartTitle directly maps the format settings of the chart title.
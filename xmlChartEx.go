@@ -0,0 +1,218 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import "encoding/xml"
+
+// xlsxChartExSpace directly maps the cx:chartSpace element. The chart space
+// is the root element of the chartEx part, which specifies the data and the
+// chart of the extended chart types, such as waterfall, funnel, treemap,
+// sunburst, histogram, pareto and box and whisker charts.
+type xlsxChartExSpace struct {
+	XMLName   xml.Name    `xml:"cx:chartSpace"`
+	XMLNSa    string      `xml:"xmlns:a,attr"`
+	XMLNSr    string      `xml:"xmlns:r,attr"`
+	XMLNScx   string      `xml:"xmlns:cx,attr"`
+	ChartData cxChartData `xml:"cx:chartData"`
+	Chart     cxChart     `xml:"cx:chart"`
+}
+
+// cxChartData directly maps the cx:chartData element. This element specifies
+// the data sets used by the series of the chart.
+type cxChartData struct {
+	Data []*cxData `xml:"cx:data"`
+}
+
+// cxData directly maps the cx:data element. This element specifies a data
+// set referenced by the series with the data identifier.
+type cxData struct {
+	ID     int      `xml:"id,attr"`
+	StrDim []*cxDim `xml:"cx:strDim"`
+	NumDim []*cxDim `xml:"cx:numDim"`
+}
+
+// cxDim directly maps the cx:strDim and cx:numDim element. This element
+// specifies a dimension of the data set by the formula.
+type cxDim struct {
+	Type string `xml:"type,attr"`
+	F    *cxF   `xml:"cx:f"`
+}
+
+// cxF directly maps the cx:f element. This element specifies the formula
+// reference of the data.
+type cxF struct {
+	Dir     string `xml:"dir,attr,omitempty"`
+	Content string `xml:",chardata"`
+}
+
+// cxChart directly maps the cx:chart element. This element specifies the
+// title, plot area and legend of the chart.
+type cxChart struct {
+	Title    *cxTitle   `xml:"cx:title"`
+	PlotArea cxPlotArea `xml:"cx:plotArea"`
+	Legend   *cxLegend  `xml:"cx:legend"`
+}
+
+// cxTitle directly maps the cx:title element. This element specifies the
+// title of the chart.
+type cxTitle struct {
+	Pos     string `xml:"pos,attr"`
+	Align   string `xml:"align,attr"`
+	Overlay bool   `xml:"overlay,attr"`
+	Tx      *cxTx  `xml:"cx:tx"`
+}
+
+// cxTx directly maps the cx:tx element. This element specifies the text of
+// the title or the series.
+type cxTx struct {
+	TxData *cxTxData `xml:"cx:txData"`
+}
+
+// cxTxData directly maps the cx:txData element. This element specifies the
+// formula reference or the text value.
+type cxTxData struct {
+	F *cxF   `xml:"cx:f"`
+	V string `xml:"cx:v,omitempty"`
+}
+
+// cxPlotArea directly maps the cx:plotArea element. This element specifies
+// the plot area region and the axes of the chart.
+type cxPlotArea struct {
+	PlotAreaRegion cxPlotAreaRegion `xml:"cx:plotAreaRegion"`
+	Axis           []*cxAxis        `xml:"cx:axis"`
+}
+
+// cxPlotAreaRegion directly maps the cx:plotAreaRegion element. This element
+// specifies the series of the chart.
+type cxPlotAreaRegion struct {
+	Series []*cxSeries `xml:"cx:series"`
+}
+
+// cxSeries directly maps the cx:series element. This element specifies a
+// series with the layout of the chart type.
+type cxSeries struct {
+	LayoutID   string        `xml:"layoutId,attr"`
+	OwnerIdx   *int          `xml:"ownerIdx,attr"`
+	FormatIdx  *int          `xml:"formatIdx,attr"`
+	Tx         *cxTx         `xml:"cx:tx"`
+	DataLabels *cxDataLabels `xml:"cx:dataLabels"`
+	DataID     *attrValInt   `xml:"cx:dataId"`
+	LayoutPr   *cxLayoutPr   `xml:"cx:layoutPr"`
+	AxisID     []*attrValInt `xml:"cx:axisId"`
+}
+
+// cxDataLabels directly maps the cx:dataLabels element. This element
+// specifies the data labels of the series.
+type cxDataLabels struct {
+	Pos        string                 `xml:"pos,attr,omitempty"`
+	Visibility *cxDataLabelVisibility `xml:"cx:visibility"`
+}
+
+// cxDataLabelVisibility directly maps the cx:visibility element of the data
+// labels. This element specifies the contents shown in the data labels.
+type cxDataLabelVisibility struct {
+	SeriesName   bool `xml:"seriesName,attr"`
+	CategoryName bool `xml:"categoryName,attr"`
+	Value        bool `xml:"value,attr"`
+}
+
+// cxLayoutPr directly maps the cx:layoutPr element. This element specifies
+// the layout properties of the series by the chart type.
+type cxLayoutPr struct {
+	ParentLabelLayout *attrValString      `xml:"cx:parentLabelLayout"`
+	Visibility        *cxSeriesVisibility `xml:"cx:visibility"`
+	Aggregation       *cxAggregation      `xml:"cx:aggregation"`
+	Binning           *cxBinning          `xml:"cx:binning"`
+	Statistics        *cxStatistics       `xml:"cx:statistics"`
+	Subtotals         *cxSubtotals        `xml:"cx:subtotals"`
+}
+
+// cxSeriesVisibility directly maps the cx:visibility element of the series
+// layout properties. This element specifies the visibility of the elements
+// of the box and whisker chart.
+type cxSeriesVisibility struct {
+	MeanLine    bool `xml:"meanLine,attr"`
+	MeanMarker  bool `xml:"meanMarker,attr"`
+	Nonoutliers bool `xml:"nonoutliers,attr"`
+	Outliers    bool `xml:"outliers,attr"`
+}
+
+// cxAggregation directly maps the cx:aggregation element. This element
+// specifies the data points with the same category shall be aggregated.
+type cxAggregation struct{}
+
+// cxBinning directly maps the cx:binning element. This element specifies the
+// binning of the histogram and pareto chart.
+type cxBinning struct {
+	IntervalClosed string        `xml:"intervalClosed,attr,omitempty"`
+	BinSize        *attrValFloat `xml:"cx:binSize"`
+	BinCount       *attrValInt   `xml:"cx:binCount"`
+}
+
+// cxStatistics directly maps the cx:statistics element. This element
+// specifies the quartile calculation method of the box and whisker chart.
+type cxStatistics struct {
+	QuartileMethod string `xml:"quartileMethod,attr"`
+}
+
+// cxSubtotals directly maps the cx:subtotals element. This element specifies
+// the indexes of the data points which are subtotals of the waterfall chart.
+type cxSubtotals struct {
+	Idx []*attrValInt `xml:"cx:idx"`
+}
+
+// cxAxis directly maps the cx:axis element. This element specifies an axis of
+// the chart.
+type cxAxis struct {
+	ID             int           `xml:"id,attr"`
+	Hidden         bool          `xml:"hidden,attr,omitempty"`
+	CatScaling     *cxCatScaling `xml:"cx:catScaling"`
+	ValScaling     *cxValScaling `xml:"cx:valScaling"`
+	Units          *cxUnits      `xml:"cx:units"`
+	MajorGridlines *cxGridlines  `xml:"cx:majorGridlines"`
+	TickLabels     *cxTickLabels `xml:"cx:tickLabels"`
+}
+
+// cxCatScaling directly maps the cx:catScaling element. This element
+// specifies the scaling of the category axis.
+type cxCatScaling struct {
+	GapWidth string `xml:"gapWidth,attr,omitempty"`
+}
+
+// cxValScaling directly maps the cx:valScaling element. This element
+// specifies the scaling of the value axis.
+type cxValScaling struct {
+	Max string `xml:"max,attr,omitempty"`
+	Min string `xml:"min,attr,omitempty"`
+}
+
+// cxUnits directly maps the cx:units element. This element specifies the
+// display units of the value axis.
+type cxUnits struct {
+	Unit string `xml:"unit,attr"`
+}
+
+// cxGridlines directly maps the cx:majorGridlines element. This element
+// specifies the major gridlines of the axis.
+type cxGridlines struct{}
+
+// cxTickLabels directly maps the cx:tickLabels element. This element
+// specifies the tick labels of the axis shall be shown.
+type cxTickLabels struct{}
+
+// cxLegend directly maps the cx:legend element. This element specifies the
+// legend of the chart.
+type cxLegend struct {
+	Pos     string `xml:"pos,attr"`
+	Align   string `xml:"align,attr"`
+	Overlay bool   `xml:"overlay,attr"`
+}
@@ -36,6 +36,7 @@ type decodeCellAnchor struct {
 type decodeSp struct {
 	NvSpPr *decodeNvSpPr `xml:"nvSpPr"`
 	SpPr   *decodeSpPr   `xml:"spPr"`
+	TxBody *decodeTxBody `xml:"txBody"`
 }
 
 // decodeSp (Non-Visual Properties for a Shape) directly maps the nvSpPr
@@ -68,6 +69,7 @@ type decodeWsDr struct {
 	Xdr              string              `xml:"xmlns xdr,attr"`
 	R                string              `xml:"xmlns r,attr"`
 	AlternateContent []*xlsxInnerXML     `xml:"http://schemas.openxmlformats.org/markup-compatibility/2006 AlternateContent"`
+	AbsoluteAnchor   []*decodeCellAnchor `xml:"absoluteAnchor,omitempty"`
 	OneCellAnchor    []*decodeCellAnchor `xml:"oneCellAnchor,omitempty"`
 	TwoCellAnchor    []*decodeCellAnchor `xml:"twoCellAnchor,omitempty"`
 }
@@ -232,3 +234,91 @@ type decodeClientData struct {
 	FLocksWithSheet  bool `xml:"fLocksWithSheet,attr"`
 	FPrintsWithSheet bool `xml:"fPrintsWithSheet,attr"`
 }
+
+// decodeChartAnchor defines the structure used to parse the cell anchor which
+// contains a chart graphic frame.
+type decodeChartAnchor struct {
+	From         *decodeFrom         `xml:"from"`
+	To           *decodeTo           `xml:"to"`
+	GraphicFrame *decodeGraphicFrame `xml:"graphicFrame"`
+	ClientData   *decodeClientData   `xml:"clientData"`
+}
+
+// decodeGraphicFrame directly maps the graphicFrame element. This element
+// specifies the existence of a graphics frame.
+type decodeGraphicFrame struct {
+	NvGraphicFramePr decodeNvGraphicFramePr `xml:"nvGraphicFramePr"`
+	Graphic          decodeGraphic          `xml:"graphic"`
+}
+
+// decodeNvGraphicFramePr directly maps the nvGraphicFramePr element. This
+// element specifies all non-visual properties for a graphic frame.
+type decodeNvGraphicFramePr struct {
+	CNvPr decodeCNvPr `xml:"cNvPr"`
+}
+
+// decodeGraphic directly maps the graphic element. This element specifies the
+// existence of a single graphic object.
+type decodeGraphic struct {
+	GraphicData decodeGraphicData `xml:"graphicData"`
+}
+
+// decodeGraphicData directly maps the graphicData element. This element
+// specifies the reference to a graphic object within the document.
+type decodeGraphicData struct {
+	Chart *decodeChart `xml:"chart"`
+}
+
+// decodeChart directly maps the chart element in the graphic data.
+type decodeChart struct {
+	RID string `xml:"id,attr"`
+}
+
+// decodeChartSpace defines the structure used to parse the title and shape
+// properties which with the DrawingML namespace prefix in the chartSpace
+// element.
+type decodeChartSpace struct {
+	Title    *decodeChartTitle    `xml:"chart>title"`
+	PlotArea *decodeChartPlotArea `xml:"chart>plotArea"`
+}
+
+// decodeChartTitle directly maps the title element of the chart.
+type decodeChartTitle struct {
+	P []decodeChartP `xml:"tx>rich>p"`
+}
+
+// decodeTxBody directly maps the txBody element of the shape, which contains
+// the paragraphs of the rich text.
+type decodeTxBody struct {
+	P []decodeChartP `xml:"p"`
+}
+
+// decodeChartP directly maps the paragraph of the rich text.
+type decodeChartP struct {
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// decodeChartPlotArea directly maps the plotArea element of the chart.
+type decodeChartPlotArea struct {
+	Charts []decodeChartGroup `xml:",any"`
+}
+
+// decodeChartGroup directly maps the chart group element in the plot area,
+// such as barChart, lineChart and pieChart.
+type decodeChartGroup struct {
+	XMLName xml.Name
+	Ser     []decodeChartSer `xml:"ser"`
+}
+
+// decodeChartSer directly maps the ser element of the chart group.
+type decodeChartSer struct {
+	Ln *decodeChartLn `xml:"spPr>ln"`
+}
+
+// decodeChartLn directly maps the ln element of the series shape properties.
+type decodeChartLn struct {
+	W       int            `xml:"w,attr"`
+	SrgbClr *attrValString `xml:"solidFill>srgbClr"`
+}
@@ -19,18 +19,23 @@ import (
 // Source relationship and namespace list, associated prefixes and schema in which it was
 // introduced.
 var (
+	NameSpaceChartEx2015                    = xml.Attr{Name: xml.Name{Local: "cx1", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2015/9/8/chartex"}
+	NameSpaceChartEx201510                  = xml.Attr{Name: xml.Name{Local: "cx2", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2015/10/21/chartex"}
 	NameSpaceDocumentPropertiesVariantTypes = xml.Attr{Name: xml.Name{Local: "vt", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"}
 	NameSpaceDrawing2016SVG                 = xml.Attr{Name: xml.Name{Local: "asvg", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2016/SVG/main"}
 	NameSpaceDrawingML                      = xml.Attr{Name: xml.Name{Local: "a", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/drawingml/2006/main"}
 	NameSpaceDrawingMLChart                 = xml.Attr{Name: xml.Name{Local: "c", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/drawingml/2006/chart"}
 	NameSpaceDrawingMLSpreadSheet           = xml.Attr{Name: xml.Name{Local: "xdr", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing"}
+	NameSpaceDrawingMLTimeslicer            = xml.Attr{Name: xml.Name{Local: "tsle", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2012/timeslicer"}
 	NameSpaceMacExcel2008Main               = xml.Attr{Name: xml.Name{Local: "mx", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/mac/excel/2008/main"}
+	NameSpaceSheetDimensions                = xml.Attr{Name: xml.Name{Local: "ed", Space: "xmlns"}, Value: "https://github.com/gozelle/excel/sheetDimensions"}
 	NameSpaceSpreadSheet                    = xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: "http://schemas.openxmlformats.org/spreadsheetml/2006/main"}
 	NameSpaceSpreadSheetExcel2006Main       = xml.Attr{Name: xml.Name{Local: "xne", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/excel/2006/main"}
 	NameSpaceSpreadSheetX14                 = xml.Attr{Name: xml.Name{Local: "x14", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"}
 	NameSpaceSpreadSheetX15                 = xml.Attr{Name: xml.Name{Local: "x15", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"}
 	SourceRelationship                      = xml.Attr{Name: xml.Name{Local: "r", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/officeDocument/2006/relationships"}
 	SourceRelationshipChart20070802         = xml.Attr{Name: xml.Name{Local: "c14", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2007/8/2/chart"}
+	SourceRelationshipChart2012             = xml.Attr{Name: xml.Name{Local: "c15", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2012/chart"}
 	SourceRelationshipChart2014             = xml.Attr{Name: xml.Name{Local: "c16", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2014/chart"}
 	SourceRelationshipChart201506           = xml.Attr{Name: xml.Name{Local: "c16r2", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2015/06/chart"}
 	SourceRelationshipCompatibility         = xml.Attr{Name: xml.Name{Local: "mc", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/markup-compatibility/2006"}
@@ -39,22 +44,31 @@ var (
 // Source relationship and namespace.
 const (
 	ContentTypeAddinMacro                         = "application/vnd.ms-excel.addin.macroEnabled.main+xml"
+	ContentTypeChartEx                            = "application/vnd.ms-office.chartex+xml"
+	ContentTypeCustomProperties                   = "application/vnd.openxmlformats-officedocument.custom-properties+xml"
 	ContentTypeDrawing                            = "application/vnd.openxmlformats-officedocument.drawing+xml"
 	ContentTypeDrawingML                          = "application/vnd.openxmlformats-officedocument.drawingml.chart+xml"
 	ContentTypeMacro                              = "application/vnd.ms-excel.sheet.macroEnabled.main+xml"
 	ContentTypeSheetML                            = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"
 	ContentTypeSpreadSheetMLChartsheet            = "application/vnd.openxmlformats-officedocument.spreadsheetml.chartsheet+xml"
 	ContentTypeSpreadSheetMLComments              = "application/vnd.openxmlformats-officedocument.spreadsheetml.comments+xml"
+	ContentTypeSpreadSheetMLCustomProperty        = "application/vnd.openxmlformats-officedocument.spreadsheetml.customProperty"
 	ContentTypeSpreadSheetMLPivotCacheDefinition  = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheDefinition+xml"
+	ContentTypeSpreadSheetMLPivotCacheRecords     = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheRecords+xml"
 	ContentTypeSpreadSheetMLPivotTable            = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotTable+xml"
 	ContentTypeSpreadSheetMLSharedStrings         = "application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml"
 	ContentTypeSpreadSheetMLTable                 = "application/vnd.openxmlformats-officedocument.spreadsheetml.table+xml"
 	ContentTypeSpreadSheetMLWorksheet             = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
 	ContentTypeTemplate                           = "application/vnd.openxmlformats-officedocument.spreadsheetml.template.main+xml"
 	ContentTypeTemplateMacro                      = "application/vnd.ms-excel.template.macroEnabled.main+xml"
+	ContentTypeTheme                              = "application/vnd.openxmlformats-officedocument.theme+xml"
+	ContentTypeTimeline                           = "application/vnd.ms-excel.timeline+xml"
+	ContentTypeTimelineCache                      = "application/vnd.ms-excel.timelineCache+xml"
 	ContentTypeVBA                                = "application/vnd.ms-office.vbaProject"
 	ContentTypeVML                                = "application/vnd.openxmlformats-officedocument.vmlDrawing"
 	NameSpaceDrawingMLMain                        = "http://schemas.openxmlformats.org/drawingml/2006/main"
+	NameSpaceChartEx                              = "http://schemas.microsoft.com/office/drawing/2014/chartex"
+	NameSpaceCustomProperties                     = "http://schemas.openxmlformats.org/officeDocument/2006/custom-properties"
 	NameSpaceDublinCore                           = "http://purl.org/dc/elements/1.1/"
 	NameSpaceDublinCoreMetadataInitiative         = "http://purl.org/dc/dcmitype/"
 	NameSpaceDublinCoreTerms                      = "http://purl.org/dc/terms/"
@@ -62,8 +76,11 @@ const (
 	NameSpaceXML                                  = "http://www.w3.org/XML/1998/namespace"
 	NameSpaceXMLSchemaInstance                    = "http://www.w3.org/2001/XMLSchema-instance"
 	SourceRelationshipChart                       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chart"
+	SourceRelationshipChartEx                     = "http://schemas.microsoft.com/office/2014/relationships/chartEx"
 	SourceRelationshipChartsheet                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chartsheet"
 	SourceRelationshipComments                    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
+	SourceRelationshipCustomProperties            = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+	SourceRelationshipCustomProperty              = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customProperty"
 	SourceRelationshipDialogsheet                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/dialogsheet"
 	SourceRelationshipDrawingML                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/drawing"
 	SourceRelationshipDrawingVML                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/vmlDrawing"
@@ -72,9 +89,13 @@ const (
 	SourceRelationshipImage                       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
 	SourceRelationshipOfficeDocument              = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
 	SourceRelationshipPivotCache                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheDefinition"
+	SourceRelationshipPivotCacheRecords           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheRecords"
 	SourceRelationshipPivotTable                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotTable"
 	SourceRelationshipSharedStrings               = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings"
 	SourceRelationshipTable                       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/table"
+	SourceRelationshipTheme                       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+	SourceRelationshipTimeline                    = "http://schemas.microsoft.com/office/2011/relationships/timeline"
+	SourceRelationshipTimelineCache               = "http://schemas.microsoft.com/office/2011/relationships/timelineCache"
 	SourceRelationshipVBAProject                  = "http://schemas.microsoft.com/office/2006/relationships/vbaProject"
 	SourceRelationshipWorkSheet                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"
 	StrictNameSpaceDocumentPropertiesVariantTypes = "http://purl.oclc.org/ooxml/officeDocument/docPropsVTypes"
@@ -87,6 +108,8 @@ const (
 	StrictSourceRelationshipExtendProperties      = "http://purl.oclc.org/ooxml/officeDocument/relationships/extendedProperties"
 	StrictSourceRelationshipImage                 = "http://purl.oclc.org/ooxml/officeDocument/relationships/image"
 	StrictSourceRelationshipOfficeDocument        = "http://purl.oclc.org/ooxml/officeDocument/relationships/officeDocument"
+	ExtURIChartDataLabelsRange                    = "{02D57815-91ED-43cb-92C2-25804820EDAC}"
+	ExtURIChartShowDataLabelsRange                = "{CE6537A1-D6FC-4f65-9D91-7224C49458BB}"
 	// ExtURIConditionalFormattings is the extLst child element
 	// ([ISO/IEC29500-1:2016] section 18.2.10) of the worksheet element
 	// ([ISO/IEC29500-1:2016] section 18.3.1.99) is extended by the addition of
@@ -96,16 +119,27 @@ const (
 	ExtURIDrawingBlip            = "{28A0092B-C50C-407E-A947-70E740481C1C}"
 	ExtURIIgnoredErrors          = "{01252117-D84E-4E92-8308-4BE1C098FCBB}"
 	ExtURIMacExcelMX             = "{64002731-A6B0-56B0-2670-7721B7C09600}"
+	ExtURIPivotCacheDefinition   = "{725AE2AE-9491-48be-B2B4-4EB974FC3084}"
 	ExtURIProtectedRanges        = "{FC87AEE6-9EDD-4A0A-B7FB-166176984837}"
+	ExtURISheetDimensions        = "{5D2A9C4E-7B1F-4E3A-9F6C-8D0B2E4A6C1F}"
 	ExtURISlicerCachesListX14    = "{BBE1A952-AA13-448e-AADC-164F8A28A991}"
 	ExtURISlicerListX14          = "{A8765BA9-456A-4DAB-B4F3-ACF838C121DE}"
 	ExtURISlicerListX15          = "{3A4CF648-6AED-40f4-86FF-DC5316D8AED3}"
 	ExtURISparklineGroups        = "{05C60535-1F16-4fd2-B633-F4F36F0B64E0}"
 	ExtURISVG                    = "{96DAC541-7B7A-43D3-8B79-37D633B846F1}"
+	ExtURITimelineCacheRefs      = "{D0CA8CA8-9F24-4464-BF8E-62219DCF47F9}"
 	ExtURITimelineRefs           = "{7E03D99C-DC04-49d9-9315-930204A7B6E9}"
 	ExtURIWebExtensions          = "{F7C9EE02-42E1-4005-9D12-6889AFFD525C}"
 )
 
+// Define the compression levels for saving the spreadsheet.
+const (
+	CompressionNone            = -1
+	CompressionDefault         = 0
+	CompressionBestSpeed       = 1
+	CompressionBestCompression = 9
+)
+
 // Excel specifications and limits
 const (
 	MaxCellStyles        = 64000
@@ -136,6 +170,8 @@ const (
 	defaultChartShowBlanksAs    = "gap"
 	defaultShapeSize            = 160
 	defaultShapeLineWidth       = 1
+	defaultTimelineWidth        = 432
+	defaultTimelineHeight       = 144
 )
 
 // ColorMappingType is the type of color transformation.
@@ -205,6 +241,23 @@ var supportedDrawingUnderlineTypes = []string{
 	"wavyDbl",
 }
 
+// supportedShapeVerticalAlign defined supported vertical alignment types of
+// the text in shape.
+var supportedShapeVerticalAlign = []string{"top", "center", "bottom"}
+
+// supportedShapeAutoFit defined supported auto-fit types of the text in shape.
+var supportedShapeAutoFit = []string{"none", "shape", "text"}
+
+// supportedShapeAlignment defined supported horizontal alignment types of the
+// paragraph in shape.
+var supportedShapeAlignment = []string{"left", "center", "right", "justify", "distributed"}
+
+// supportedCalcMode defined supported formula calculation mode.
+var supportedCalcMode = []string{"manual", "auto", "autoNoTable"}
+
+// supportedRefMode defined supported formula reference mode.
+var supportedRefMode = []string{"A1", "R1C1"}
+
 // xlsxCNvPr directly maps the cNvPr (Non-Visual Drawing Properties). This
 // element specifies non-visual canvas properties. This allows for additional
 // information that does not affect the appearance of the picture to be stored.
@@ -476,8 +529,10 @@ type xlsxGraphic struct {
 // document. This graphic object is provided entirely by the document authors
 // who choose to persist this data within the document.
 type xlsxGraphicData struct {
-	URI   string     `xml:"uri,attr"`
-	Chart *xlsxChart `xml:"c:chart,omitempty"`
+	URI        string          `xml:"uri,attr"`
+	Chart      *xlsxChart      `xml:"c:chart,omitempty"`
+	ChartEx    *xlsxChartEx    `xml:"cx:chart,omitempty"`
+	Timeslicer *xlsxTimeslicer `xml:"tsle:timeslicer,omitempty"`
 }
 
 // xlsxChart (Chart) directly maps the c:chart element.
@@ -487,6 +542,14 @@ type xlsxChart struct {
 	R   string `xml:"xmlns:r,attr"`
 }
 
+// xlsxChartEx (Chart) directly maps the cx:chart element, which references
+// the chartEx part of the extended chart types.
+type xlsxChartEx struct {
+	Cx  string `xml:"xmlns:cx,attr"`
+	RID string `xml:"r:id,attr"`
+	R   string `xml:"xmlns:r,attr"`
+}
+
 // xdrSp (Shape) directly maps the xdr:sp element. This element specifies the
 // existence of a single shape. A shape can either be a preset or a custom
 // geometry, defined using the SpreadsheetDrawingML framework. In addition to a
@@ -581,25 +644,47 @@ type GraphicOptions struct {
 	Hyperlink       string
 	HyperlinkType   string
 	Positioning     string
+	FitRange        string
+}
+
+// ExportedPart directly maps the part of the workbook package which exported
+// as a standalone file. The Name is the original file name of the part in the
+// workbook package, and the ContentType is the content type of the part.
+type ExportedPart struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// ExportedPicture directly maps the picture exported from the worksheet, the
+// Cell is the top-left cell reference of the picture.
+type ExportedPicture struct {
+	Cell string
+	Part ExportedPart
 }
 
 // Shape directly maps the format settings of the shape.
 type Shape struct {
-	Macro     string
-	Type      string
-	Width     uint
-	Height    uint
-	Format    GraphicOptions
-	Color     ShapeColor
-	Line      ShapeLine
-	Paragraph []ShapeParagraph
+	Macro         string
+	Type          string
+	Width         uint
+	Height        uint
+	Format        GraphicOptions
+	Color         ShapeColor
+	Line          ShapeLine
+	Paragraph     []ShapeParagraph
+	VerticalAlign string
+	AutoFit       string
+	WrapText      bool
 }
 
 // ShapeParagraph directly maps the format settings of the paragraph in
 // the shape.
 type ShapeParagraph struct {
-	Font Font
-	Text string
+	Font      Font
+	Text      string
+	Runs      []RichTextRun
+	Alignment string
 }
 
 // ShapeColor directly maps the color settings of the shape.
@@ -104,7 +104,7 @@ type xlsxCacheField struct {
 	SQLType             int              `xml:"sqlType,attr,omitempty"`
 	Hierarchy           int              `xml:"hierarchy,attr,omitempty"`
 	Level               int              `xml:"level,attr,omitempty"`
-	DatabaseField       bool             `xml:"databaseField,attr,omitempty"`
+	DatabaseField       *bool            `xml:"databaseField,attr"`
 	MappingCount        int              `xml:"mappingCount,attr,omitempty"`
 	MemberPropertyField bool             `xml:"memberPropertyField,attr,omitempty"`
 	SharedItems         *xlsxSharedItems `xml:"sharedItems"`
@@ -120,10 +120,10 @@ type xlsxCacheField struct {
 // those values that are referenced in multiple places across all the
 // PivotTable parts.
 type xlsxSharedItems struct {
-	ContainsSemiMixedTypes bool          `xml:"containsSemiMixedTypes,attr,omitempty"`
-	ContainsNonDate        bool          `xml:"containsNonDate,attr,omitempty"`
+	ContainsSemiMixedTypes *bool         `xml:"containsSemiMixedTypes,attr"`
+	ContainsNonDate        *bool         `xml:"containsNonDate,attr"`
 	ContainsDate           bool          `xml:"containsDate,attr,omitempty"`
-	ContainsString         bool          `xml:"containsString,attr,omitempty"`
+	ContainsString         *bool         `xml:"containsString,attr"`
 	ContainsBlank          bool          `xml:"containsBlank,attr,omitempty"`
 	ContainsMixedTypes     bool          `xml:"containsMixedTypes,attr,omitempty"`
 	ContainsNumber         bool          `xml:"containsNumber,attr,omitempty"`
@@ -138,7 +138,7 @@ type xlsxSharedItems struct {
 	N                      *xlsxNumber   `xml:"n"`
 	B                      *xlsxBoolean  `xml:"b"`
 	E                      *xlsxError    `xml:"e"`
-	S                      *xlsxString   `xml:"s"`
+	S                      []*xlsxString `xml:"s"`
 	D                      *xlsxDateTime `xml:"d"`
 }
 
@@ -197,7 +197,31 @@ type xlsxString struct {
 type xlsxDateTime struct{}
 
 // xlsxFieldGroup represents the collection of properties for a field group.
-type xlsxFieldGroup struct{}
+type xlsxFieldGroup struct {
+	Par        *int            `xml:"par,attr"`
+	Base       *int            `xml:"base,attr"`
+	RangePr    *xlsxRangePr    `xml:"rangePr"`
+	GroupItems *xlsxGroupItems `xml:"groupItems"`
+}
+
+// xlsxRangePr represents the properties of a range grouping, which groups
+// the numeric or date values of the field by the range.
+type xlsxRangePr struct {
+	AutoStart     *bool    `xml:"autoStart,attr"`
+	AutoEnd       *bool    `xml:"autoEnd,attr"`
+	GroupBy       string   `xml:"groupBy,attr,omitempty"`
+	StartNum      *float64 `xml:"startNum,attr"`
+	EndNum        *float64 `xml:"endNum,attr"`
+	StartDate     string   `xml:"startDate,attr,omitempty"`
+	EndDate       string   `xml:"endDate,attr,omitempty"`
+	GroupInterval float64  `xml:"groupInterval,attr,omitempty"`
+}
+
+// xlsxGroupItems represents the collection of the items in a grouped field.
+type xlsxGroupItems struct {
+	Count int           `xml:"count,attr"`
+	S     []*xlsxString `xml:"s"`
+}
 
 // xlsxCacheHierarchies represents the collection of OLAP hierarchies in the
 // PivotCache.
@@ -211,7 +235,45 @@ type xlsxKpis struct{}
 type xlsxTupleCache struct{}
 
 // xlsxCalculatedItems represents the collection of calculated items.
-type xlsxCalculatedItems struct{}
+type xlsxCalculatedItems struct {
+	Count          int                   `xml:"count,attr"`
+	CalculatedItem []*xlsxCalculatedItem `xml:"calculatedItem"`
+}
+
+// xlsxCalculatedItem represents a calculated item defined by the formula. The
+// pivot area specifies the field and the shared item of the calculated item.
+type xlsxCalculatedItem struct {
+	Field     *int           `xml:"field,attr"`
+	Formula   string         `xml:"formula,attr,omitempty"`
+	PivotArea *xlsxPivotArea `xml:"pivotArea"`
+}
+
+// xlsxPivotArea represents the rule to describe PivotTable selection.
+type xlsxPivotArea struct {
+	Field         *int                     `xml:"field,attr"`
+	Type          string                   `xml:"type,attr,omitempty"`
+	DataOnly      *bool                    `xml:"dataOnly,attr"`
+	LabelOnly     bool                     `xml:"labelOnly,attr,omitempty"`
+	Outline       *bool                    `xml:"outline,attr"`
+	FieldPosition *int                     `xml:"fieldPosition,attr"`
+	CacheIndex    bool                     `xml:"cacheIndex,attr,omitempty"`
+	References    *xlsxPivotAreaReferences `xml:"references"`
+}
+
+// xlsxPivotAreaReferences represents the set of selected fields and item
+// indexes of the pivot area.
+type xlsxPivotAreaReferences struct {
+	Count     int                       `xml:"count,attr"`
+	Reference []*xlsxPivotAreaReference `xml:"reference"`
+}
+
+// xlsxPivotAreaReference represents the set of selected items of a field in
+// the pivot area.
+type xlsxPivotAreaReference struct {
+	Field *int     `xml:"field,attr"`
+	Count int      `xml:"count,attr"`
+	X     []*xlsxX `xml:"x"`
+}
 
 // xlsxCalculatedMembers represents the collection of calculated members in an
 // OLAP PivotTable.
@@ -226,3 +288,27 @@ type xlsxMeasureGroups struct{}
 
 // xlsxMaps represents the PivotTable OLAP measure group - Dimension maps.
 type xlsxMaps struct{}
+
+// xlsxPivotCacheRecords represents the collection of records in the
+// PivotCache. This part stores the underlying source data that the PivotTable
+// aggregates.
+type xlsxPivotCacheRecords struct {
+	XMLName xml.Name                `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main pivotCacheRecords"`
+	Count   int                     `xml:"count,attr"`
+	R       []*xlsxPivotCacheRecord `xml:"r"`
+}
+
+// xlsxPivotCacheRecord represents a single record of data in the PivotCache.
+// The values of the record are in the order of the cache fields.
+type xlsxPivotCacheRecord struct {
+	Values []xlsxPivotCacheRecordValue `xml:",any"`
+}
+
+// xlsxPivotCacheRecordValue directly maps the value of the record in the
+// PivotCache. The element name specifies the type of the value: x for the
+// index of the shared item, n for the numeric value, s for the character
+// value and m for the missing value.
+type xlsxPivotCacheRecordValue struct {
+	XMLName xml.Name
+	V       string `xml:"v,attr,omitempty"`
+}
@@ -287,6 +287,16 @@ type xlsxTableStyle struct {
 	TableStyleElement string `xml:",innerxml"`
 }
 
+// xlsxTableStyleElement directly maps the tableStyleElement element. This
+// element specifies the formatting by the differential formatting record for
+// a particular area of the table or PivotTable.
+type xlsxTableStyleElement struct {
+	XMLName xml.Name `xml:"tableStyleElement"`
+	Type    string   `xml:"type,attr"`
+	Size    int      `xml:"size,attr,omitempty"`
+	DxfID   int      `xml:"dxfId,attr"`
+}
+
 // xlsxNumFmts directly maps the numFmts element. This element defines the
 // number formats in this workbook, consisting of a sequence of numFmt records,
 // where each numFmt record defines a particular number format, indicating how
@@ -345,6 +355,12 @@ type Font struct {
 	ColorTheme   *int
 	ColorTint    float64
 	VertAlign    string
+	Charset      *int
+	Condense     bool
+	Extend       bool
+	Outline      bool
+	Shadow       bool
+	Scheme       string
 }
 
 // Fill directly maps the fill settings of the cells.
@@ -374,3 +390,31 @@ type Style struct {
 	Lang          string
 	NegRed        bool
 }
+
+// NamedStyle directly maps the settings of the named cell style, the BuiltIn
+// specifies if the style is one of the built-in cell styles of the
+// spreadsheet application, such as "Heading 1", "Good" and "Bad".
+type NamedStyle struct {
+	Name    string `json:"Name"`
+	BuiltIn bool   `json:"BuiltIn,omitempty"`
+	Style   *Style `json:"Style"`
+}
+
+// StyleTable directly maps the JSON form of the complete style table of the
+// workbook, which used by the ExportStyles and ImportStyles functions. The
+// Version specifies the version of the JSON form, the DefaultFont specifies
+// the font family of the default font, the Styles specifies the cell styles
+// in the order of the style index, the NamedStyles specifies the named cell
+// styles and the ConditionalStyles specifies the conditional format styles
+// in the order of the conditional format style index. The style table only
+// includes the settings of the Style, so the named cell style which a cell
+// style based on (the xfId of the cell formatting record) will not be kept,
+// and the theme colors of the fills and borders will be exported as the RGB
+// colors.
+type StyleTable struct {
+	Version           int          `json:"Version"`
+	DefaultFont       string       `json:"DefaultFont,omitempty"`
+	Styles            []*Style     `json:"Styles"`
+	NamedStyles       []NamedStyle `json:"NamedStyles,omitempty"`
+	ConditionalStyles []*Style     `json:"ConditionalStyles,omitempty"`
+}
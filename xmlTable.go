@@ -49,6 +49,7 @@ type xlsxAutoFilter struct {
 	XMLName      xml.Name            `xml:"autoFilter"`
 	Ref          string              `xml:"ref,attr"`
 	FilterColumn []*xlsxFilterColumn `xml:"filterColumn"`
+	applyFilter  bool
 }
 
 // xlsxFilterColumn directly maps the filterColumn element. The filterColumn
@@ -171,18 +172,29 @@ type xlsxTableColumns struct {
 // xlsxTableColumn directly maps the element representing a single column for
 // this table.
 type xlsxTableColumn struct {
-	DataCellStyle      string `xml:"dataCellStyle,attr,omitempty"`
-	DataDxfID          int    `xml:"dataDxfId,attr,omitempty"`
-	HeaderRowCellStyle string `xml:"headerRowCellStyle,attr,omitempty"`
-	HeaderRowDxfID     int    `xml:"headerRowDxfId,attr,omitempty"`
-	ID                 int    `xml:"id,attr"`
-	Name               string `xml:"name,attr"`
-	QueryTableFieldID  int    `xml:"queryTableFieldId,attr,omitempty"`
-	TotalsRowCellStyle string `xml:"totalsRowCellStyle,attr,omitempty"`
-	TotalsRowDxfID     int    `xml:"totalsRowDxfId,attr,omitempty"`
-	TotalsRowFunction  string `xml:"totalsRowFunction,attr,omitempty"`
-	TotalsRowLabel     string `xml:"totalsRowLabel,attr,omitempty"`
-	UniqueName         string `xml:"uniqueName,attr,omitempty"`
+	DataCellStyle           string            `xml:"dataCellStyle,attr,omitempty"`
+	DataDxfID               int               `xml:"dataDxfId,attr,omitempty"`
+	HeaderRowCellStyle      string            `xml:"headerRowCellStyle,attr,omitempty"`
+	HeaderRowDxfID          int               `xml:"headerRowDxfId,attr,omitempty"`
+	ID                      int               `xml:"id,attr"`
+	Name                    string            `xml:"name,attr"`
+	QueryTableFieldID       int               `xml:"queryTableFieldId,attr,omitempty"`
+	TotalsRowCellStyle      string            `xml:"totalsRowCellStyle,attr,omitempty"`
+	TotalsRowDxfID          int               `xml:"totalsRowDxfId,attr,omitempty"`
+	TotalsRowFunction       string            `xml:"totalsRowFunction,attr,omitempty"`
+	TotalsRowLabel          string            `xml:"totalsRowLabel,attr,omitempty"`
+	UniqueName              string            `xml:"uniqueName,attr,omitempty"`
+	CalculatedColumnFormula *xlsxTableFormula `xml:"calculatedColumnFormula"`
+	TotalsRowFormula        *xlsxTableFormula `xml:"totalsRowFormula"`
+}
+
+// xlsxTableFormula directly maps the calculatedColumnFormula and
+// totalsRowFormula elements. These elements specify the formula used to
+// calculate the cells in the column of the table, and the custom formula of
+// the totals row.
+type xlsxTableFormula struct {
+	Array   bool   `xml:"array,attr,omitempty"`
+	Content string `xml:",chardata"`
 }
 
 // xlsxTableStyleInfo directly maps the tableStyleInfo element. This element
@@ -204,6 +216,52 @@ type TableOptions struct {
 	ShowLastColumn    bool
 	ShowRowStripes    *bool
 	ShowColumnStripes bool
+	ShowTotalsRow     bool
+	UnmergeCells      bool
+	Columns           []TableColumnOptions
+}
+
+// Table directly maps the settings of the table in the worksheet, which
+// returned by the GetTables function.
+type Table struct {
+	Range             string
+	Name              string
+	StyleName         string
+	ShowFirstColumn   bool
+	ShowLastColumn    bool
+	ShowRowStripes    *bool
+	ShowColumnStripes bool
+	ShowTotalsRow     bool
+	Columns           []TableColumnOptions
+}
+
+// TableColumnOptions directly maps the settings of a column in the table,
+// including the function, custom formula and label of the totals row, and
+// the formula of the calculated column.
+type TableColumnOptions struct {
+	Column                  string
+	TotalsRowFunction       string
+	TotalsRowFormula        string
+	TotalsRowLabel          string
+	CalculatedColumnFormula string
+}
+
+// TableStyleOptions directly maps the settings of the custom table style,
+// each field specifies the style of a particular area of the table.
+type TableStyleOptions struct {
+	WholeTable         *Style
+	HeaderRow          *Style
+	TotalRow           *Style
+	FirstColumn        *Style
+	LastColumn         *Style
+	FirstRowStripe     *Style
+	SecondRowStripe    *Style
+	FirstColumnStripe  *Style
+	SecondColumnStripe *Style
+	FirstHeaderCell    *Style
+	LastHeaderCell     *Style
+	FirstTotalCell     *Style
+	LastTotalCell      *Style
 }
 
 // AutoFilterListOptions directly maps the auto filter list settings.
@@ -214,7 +272,65 @@ type AutoFilterListOptions struct {
 
 // AutoFilterOptions directly maps the auto filter settings.
 type AutoFilterOptions struct {
-	Column     string
-	Expression string
-	FilterList []AutoFilterListOptions
+	Column       string
+	Expression   string
+	FilterList   []AutoFilterListOptions
+	Columns      []AutoFilterColumn
+	UnmergeCells bool
+	ApplyFilter  bool
+}
+
+// AutoFilterCustomFilter directly maps the custom filter criteria of the auto
+// filter column.
+type AutoFilterCustomFilter struct {
+	Operator string
+	Value    string
+}
+
+// AutoFilterColorFilter directly maps the color filter criteria of the auto
+// filter column.
+type AutoFilterColorFilter struct {
+	CellColor bool
+	Color     string
+}
+
+// AutoFilterDateGroup directly maps the date grouping filter criteria of the
+// auto filter column.
+type AutoFilterDateGroup struct {
+	Grouping string
+	Year     int
+	Month    int
+	Day      int
+	Hour     int
+	Minute   int
+	Second   int
+}
+
+// AutoFilterTop10 directly maps the top or bottom N items or percent filter
+// criteria of the auto filter column.
+type AutoFilterTop10 struct {
+	Top     bool
+	Percent bool
+	Value   float64
+}
+
+// AutoFilterColumn directly maps the filter criteria of a column in the auto
+// filter.
+type AutoFilterColumn struct {
+	Column        string
+	Values        []string
+	Blanks        bool
+	DateGroups    []AutoFilterDateGroup
+	And           bool
+	CustomFilters []AutoFilterCustomFilter
+	ColorFilter   *AutoFilterColorFilter
+	DynamicFilter string
+	Top10         *AutoFilterTop10
+}
+
+// AutoFilterState directly maps the range reference and the filter criteria
+// of the columns of the auto filter in the worksheet.
+type AutoFilterState struct {
+	RangeRef string
+	Columns  []AutoFilterColumn
 }
@@ -161,3 +161,64 @@ type xlsxSysClr struct {
 	Val     string `xml:"val,attr"`
 	LastClr string `xml:"lastClr,attr"`
 }
+
+// ThemeColorScheme directly maps the color scheme of the theme. The colors
+// are specified by the hex RGB color code, such as "4472C4".
+type ThemeColorScheme struct {
+	Name              string
+	Dark1             string
+	Light1            string
+	Dark2             string
+	Light2            string
+	Accent1           string
+	Accent2           string
+	Accent3           string
+	Accent4           string
+	Accent5           string
+	Accent6           string
+	Hyperlink         string
+	FollowedHyperlink string
+}
+
+// ThemeScriptFont directly maps the font which is used for the specific
+// language script, such as "Jpan" or "Hans".
+type ThemeScriptFont struct {
+	Script   string
+	Typeface string
+}
+
+// ThemeFont directly maps the major or minor font of the font scheme.
+type ThemeFont struct {
+	Latin         string
+	EastAsian     string
+	ComplexScript string
+	Scripts       []ThemeScriptFont
+}
+
+// ThemeFontScheme directly maps the font scheme of the theme, the major font
+// is used for the headings and the minor font is used for the body text.
+type ThemeFontScheme struct {
+	Name      string
+	MajorFont ThemeFont
+	MinorFont ThemeFont
+}
+
+// ThemeFormatScheme directly maps the format scheme of the theme. The fill
+// styles, line styles, effect styles and background fill styles are
+// specified by the DrawingML XML fragments with the "a" namespace prefix,
+// such as `<a:solidFill><a:schemeClr val="phClr"/></a:solidFill>`.
+type ThemeFormatScheme struct {
+	Name                 string
+	FillStyles           string
+	LineStyles           string
+	EffectStyles         string
+	BackgroundFillStyles string
+}
+
+// Theme directly maps the settings of the workbook theme.
+type Theme struct {
+	Name         string
+	ColorScheme  ThemeColorScheme
+	FontScheme   ThemeFontScheme
+	FormatScheme ThemeFormatScheme
+}
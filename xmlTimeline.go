@@ -0,0 +1,185 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excel
+
+import "encoding/xml"
+
+// xlsxTimelines directly maps the timelines element. This element is the root
+// element of the timeline part, which specifies the timelines in the
+// worksheet.
+type xlsxTimelines struct {
+	XMLName  xml.Name        `xml:"http://schemas.microsoft.com/office/spreadsheetml/2010/11/main timelines"`
+	Timeline []*xlsxTimeline `xml:"timeline"`
+}
+
+// xlsxTimeline directly maps the timeline element. This element specifies
+// the timeline view of the timeline cache, which is used to filter the pivot
+// tables by the date ranges.
+type xlsxTimeline struct {
+	Name                    string `xml:"name,attr"`
+	Cache                   string `xml:"cache,attr"`
+	Caption                 string `xml:"caption,attr,omitempty"`
+	ShowHeader              *bool  `xml:"showHeader,attr"`
+	ShowSelectionLabel      *bool  `xml:"showSelectionLabel,attr"`
+	ShowTimeLevel           *bool  `xml:"showTimeLevel,attr"`
+	ShowHorizontalScrollbar *bool  `xml:"showHorizontalScrollbar,attr"`
+	Level                   int    `xml:"level,attr"`
+	SelectionLevel          int    `xml:"selectionLevel,attr"`
+	ScrollPosition          string `xml:"scrollPosition,attr,omitempty"`
+	Style                   string `xml:"style,attr,omitempty"`
+}
+
+// xlsxTimelineCacheDefinition directly maps the timelineCacheDefinition
+// element. This element is the root element of the timeline cache part,
+// which specifies the date field of the pivot cache and the pivot tables
+// filtered by the timeline.
+type xlsxTimelineCacheDefinition struct {
+	XMLName     xml.Name                      `xml:"http://schemas.microsoft.com/office/spreadsheetml/2010/11/main timelineCacheDefinition"`
+	Name        string                        `xml:"name,attr"`
+	SourceName  string                        `xml:"sourceName,attr"`
+	PivotTables *xlsxTimelineCachePivotTables `xml:"pivotTables"`
+	State       *xlsxTimelineState            `xml:"state"`
+}
+
+// xlsxTimelineCachePivotTables directly maps the pivotTables element of the
+// timeline cache definition.
+type xlsxTimelineCachePivotTables struct {
+	PivotTable []*xlsxTimelineCachePivotTable `xml:"pivotTable"`
+}
+
+// xlsxTimelineCachePivotTable directly maps the pivotTable element of the
+// timeline cache definition, which specifies the pivot table filtered by the
+// timeline by the name of the pivot table and the sheet ID of the worksheet.
+type xlsxTimelineCachePivotTable struct {
+	TabID int    `xml:"tabId,attr"`
+	Name  string `xml:"name,attr"`
+}
+
+// xlsxTimelineState directly maps the state element. This element specifies
+// the filter state and the date range bounds of the timeline cache.
+type xlsxTimelineState struct {
+	SingleRangeFilterState bool               `xml:"singleRangeFilterState,attr,omitempty"`
+	MinimalRefreshVersion  int                `xml:"minimalRefreshVersion,attr"`
+	LastRefreshVersion     int                `xml:"lastRefreshVersion,attr"`
+	PivotCacheID           int                `xml:"pivotCacheId,attr"`
+	FilterType             string             `xml:"filterType,attr"`
+	Selection              *xlsxTimelineRange `xml:"selection"`
+	Bounds                 *xlsxTimelineRange `xml:"bounds"`
+}
+
+// xlsxTimelineRange directly maps the selection and bounds element of the
+// timeline state, which specifies the date range.
+type xlsxTimelineRange struct {
+	StartDate string `xml:"startDate,attr"`
+	EndDate   string `xml:"endDate,attr"`
+}
+
+// xlsxX14PivotCacheDefinition directly maps the x14:pivotCacheDefinition
+// element in the extension list of the pivot cache definition, which
+// specifies the pivot cache ID referenced by the timeline caches.
+type xlsxX14PivotCacheDefinition struct {
+	XMLName      xml.Name `xml:"x14:pivotCacheDefinition"`
+	PivotCacheID int      `xml:"pivotCacheId,attr"`
+}
+
+// decodeX14PivotCacheDefinition directly maps the x14:pivotCacheDefinition
+// element in the extension list of the pivot cache definition.
+type decodeX14PivotCacheDefinition struct {
+	XMLName      xml.Name `xml:"pivotCacheDefinition"`
+	PivotCacheID int      `xml:"pivotCacheId,attr"`
+}
+
+// xlsxX15TimelineRefs directly maps the x15:timelineRefs element in the
+// extension list of the worksheet and the x15:timelineCacheRefs element in
+// the extension list of the workbook.
+type xlsxX15TimelineRefs struct {
+	XMLName xml.Name             `xml:""`
+	Ref     []xlsxX15TimelineRef `xml:""`
+}
+
+// xlsxX15TimelineRef directly maps the x15:timelineRef and
+// x15:timelineCacheRef element, which references the timeline part or the
+// timeline cache part by the relationship ID.
+type xlsxX15TimelineRef struct {
+	XMLName xml.Name `xml:""`
+	RID     string   `xml:"r:id,attr"`
+}
+
+// decodeX15TimelineRefs directly maps the x15:timelineRefs and
+// x15:timelineCacheRefs element.
+type decodeX15TimelineRefs struct {
+	Ref []struct {
+		RID string `xml:"id,attr"`
+	} `xml:",any"`
+}
+
+// xlsxTimeslicer directly maps the tsle:timeslicer element in the graphic
+// frame of the drawing, which references the timeline by name.
+type xlsxTimeslicer struct {
+	Tsle string `xml:"xmlns:tsle,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// TimelineOptions represents the settings of the timeline.
+//
+// Name specifies the name of the date field of the pivot table which will
+// be filtered by the timeline, this setting is required.
+//
+// Cell specifies the left top cell coordinates the position for inserting
+// the timeline, this setting is required.
+//
+// TableSheet specifies the worksheet name of the pivot table, this setting
+// is required.
+//
+// TableName specifies the name of the pivot table, this setting is required.
+//
+// Caption specifies the caption of the timeline, the default caption is the
+// name of the date field.
+//
+// Level specifies the time level of the timeline, the possible values are
+// 'years', 'quarters', 'months' and 'days', the default value is 'months'.
+//
+// ShowHeader specifies if display the header of the timeline, the default
+// value is true.
+//
+// ShowSelectionLabel specifies if display the label of the selected date
+// range, the default value is true.
+//
+// ShowTimeLevel specifies if display the time level selector of the
+// timeline, the default value is true.
+//
+// ShowHorizontalScrollbar specifies if display the horizontal scrollbar of
+// the timeline, the default value is true.
+//
+// Width specifies the width of the timeline in pixels, the default value is
+// 432.
+//
+// Height specifies the height of the timeline in pixels, the default value
+// is 144.
+//
+// Format specifies the format of the timeline, such as offset and the
+// positioning of the timeline.
+type TimelineOptions struct {
+	Name                    string
+	Cell                    string
+	TableSheet              string
+	TableName               string
+	Caption                 string
+	Level                   string
+	ShowHeader              *bool
+	ShowSelectionLabel      *bool
+	ShowTimeLevel           *bool
+	ShowHorizontalScrollbar *bool
+	Width                   uint
+	Height                  uint
+	Format                  GraphicOptions
+}
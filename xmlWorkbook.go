@@ -183,6 +183,64 @@ type xlsxExternalReference struct {
 	RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
 }
 
+// xlsxExternalLink directly maps the externalLink element of the external
+// workbook references part, the part contains the cached data of the
+// referenced external workbook.
+type xlsxExternalLink struct {
+	XMLName      xml.Name          `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main externalLink"`
+	ExternalBook *xlsxExternalBook `xml:"externalBook"`
+}
+
+// xlsxExternalBook directly maps the externalBook element. This element
+// specifies the relationship ID of the external workbook path and the cached
+// worksheet names and cell values of the external workbook.
+type xlsxExternalBook struct {
+	RID          string                    `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
+	SheetNames   *xlsxExternalSheetNames   `xml:"sheetNames"`
+	SheetDataSet *xlsxExternalSheetDataSet `xml:"sheetDataSet"`
+}
+
+// xlsxExternalSheetNames directly maps the sheetNames element of the cached
+// worksheet names of the external workbook.
+type xlsxExternalSheetNames struct {
+	SheetName []xlsxExternalSheetName `xml:"sheetName"`
+}
+
+// xlsxExternalSheetName directly maps the sheetName element of the cached
+// worksheet name of the external workbook.
+type xlsxExternalSheetName struct {
+	Val string `xml:"val,attr"`
+}
+
+// xlsxExternalSheetDataSet directly maps the sheetDataSet element of the cached
+// worksheets data of the external workbook.
+type xlsxExternalSheetDataSet struct {
+	SheetData []xlsxExternalSheetData `xml:"sheetData"`
+}
+
+// xlsxExternalSheetData directly maps the sheetData element of the cached
+// worksheet data of the external workbook, the sheetId attribute is the
+// zero-based index of the worksheet in the cached worksheet names.
+type xlsxExternalSheetData struct {
+	SheetID int               `xml:"sheetId,attr"`
+	Row     []xlsxExternalRow `xml:"row"`
+}
+
+// xlsxExternalRow directly maps the row element of the cached worksheet data
+// of the external workbook.
+type xlsxExternalRow struct {
+	R    int                `xml:"r,attr"`
+	Cell []xlsxExternalCell `xml:"cell"`
+}
+
+// xlsxExternalCell directly maps the cell element of the cached worksheet data
+// of the external workbook.
+type xlsxExternalCell struct {
+	R string `xml:"r,attr"`
+	T string `xml:"t,attr,omitempty"`
+	V string `xml:"v,omitempty"`
+}
+
 // xlsxPivotCaches element enumerates pivot cache definition parts used by pivot
 // tables and formulas in this workbook.
 type xlsxPivotCaches struct {
@@ -207,6 +265,31 @@ type xlsxExtLst struct {
 	Ext string `xml:",innerxml"`
 }
 
+// xlsxSheetDimensions directly maps the sheetDimensions element in the
+// extension list of the workbook, which is written by this library for
+// recording the dimensions of the worksheets.
+type xlsxSheetDimensions struct {
+	XMLName xml.Name             `xml:"ed:sheetDimensions"`
+	XMLNSED string               `xml:"xmlns:ed,attr"`
+	Sheet   []xlsxSheetDimension `xml:"ed:sheet"`
+}
+
+// xlsxSheetDimension directly maps the sheet element in the sheetDimensions
+// element.
+type xlsxSheetDimension struct {
+	Name string `xml:"name,attr"`
+	Ref  string `xml:"ref,attr,omitempty"`
+	Rows int    `xml:"rows,attr"`
+	Cols int    `xml:"cols,attr"`
+}
+
+// decodeSheetDimensions directly maps the sheetDimensions element in the
+// extension list of the workbook.
+type decodeSheetDimensions struct {
+	XMLName xml.Name             `xml:"sheetDimensions"`
+	Sheet   []xlsxSheetDimension `xml:"sheet"`
+}
+
 // xlsxDefinedNames directly maps the definedNames element. This element defines
 // the collection of defined names for this workbook. Defined names are
 // descriptive names to represent cells, ranges of cells, formulas, or constant
@@ -244,15 +327,15 @@ type xlsxDefinedName struct {
 // and details. Calculation is the process of computing formulas and then
 // displaying the results as values in the cells that contain the formulas.
 type xlsxCalcPr struct {
-	CalcCompleted         bool    `xml:"calcCompleted,attr,omitempty"`
+	CalcCompleted         *bool   `xml:"calcCompleted,attr"`
 	CalcID                string  `xml:"calcId,attr,omitempty"`
 	CalcMode              string  `xml:"calcMode,attr,omitempty"`
-	CalcOnSave            bool    `xml:"calcOnSave,attr,omitempty"`
+	CalcOnSave            *bool   `xml:"calcOnSave,attr"`
 	ConcurrentCalc        *bool   `xml:"concurrentCalc,attr"`
 	ConcurrentManualCount int     `xml:"concurrentManualCount,attr,omitempty"`
 	ForceFullCalc         bool    `xml:"forceFullCalc,attr,omitempty"`
 	FullCalcOnLoad        bool    `xml:"fullCalcOnLoad,attr,omitempty"`
-	FullPrecision         bool    `xml:"fullPrecision,attr,omitempty"`
+	FullPrecision         *bool   `xml:"fullPrecision,attr"`
 	Iterate               bool    `xml:"iterate,attr,omitempty"`
 	IterateCount          int     `xml:"iterateCount,attr,omitempty"`
 	IterateDelta          float64 `xml:"iterateDelta,attr,omitempty"`
@@ -314,6 +397,17 @@ type DefinedName struct {
 	Scope    string
 }
 
+// DefinedNameConflict is the type of the policy on setting the defined name
+// which already exists on the same scope.
+type DefinedNameConflict byte
+
+// Defined name conflict policies enumeration.
+const (
+	DefinedNameConflictError DefinedNameConflict = iota
+	DefinedNameConflictOverwrite
+	DefinedNameConflictRename
+)
+
 // WorkbookPropsOptions directly maps the settings of workbook proprieties.
 type WorkbookPropsOptions struct {
 	Date1904      *bool
@@ -321,6 +415,24 @@ type WorkbookPropsOptions struct {
 	CodeName      *string
 }
 
+// CalcPropsOptions defines the collection of properties the application uses
+// to record calculation status and details.
+type CalcPropsOptions struct {
+	CalcID                *uint
+	CalcMode              *string
+	FullCalcOnLoad        *bool
+	RefMode               *string
+	Iterate               *bool
+	IterateCount          *uint
+	IterateDelta          *float64
+	FullPrecision         *bool
+	CalcCompleted         *bool
+	CalcOnSave            *bool
+	ConcurrentCalc        *bool
+	ConcurrentManualCount *uint
+	ForceFullCalc         *bool
+}
+
 // WorkbookProtectionOptions directly maps the settings of workbook protection.
 type WorkbookProtectionOptions struct {
 	AlgorithmName string
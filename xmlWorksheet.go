@@ -46,7 +46,7 @@ type xlsxWorksheet struct {
 	HeaderFooter           *xlsxHeaderFooter            `xml:"headerFooter"`
 	RowBreaks              *xlsxRowBreaks               `xml:"rowBreaks"`
 	ColBreaks              *xlsxColBreaks               `xml:"colBreaks"`
-	CustomProperties       *xlsxInnerXML                `xml:"customProperties"`
+	CustomProperties       *xlsxSheetCustomProperties   `xml:"customProperties"`
 	CellWatches            *xlsxInnerXML                `xml:"cellWatches"`
 	IgnoredErrors          *xlsxInnerXML                `xml:"ignoredErrors"`
 	SmartTags              *xlsxInnerXML                `xml:"smartTags"`
@@ -241,11 +241,26 @@ type xlsxSheetPr struct {
 	CodeName                          string           `xml:"codeName,attr,omitempty"`
 	FilterMode                        bool             `xml:"filterMode,attr,omitempty"`
 	EnableFormatConditionsCalculation *bool            `xml:"enableFormatConditionsCalculation,attr"`
-	TabColor                          *xlsxTabColor    `xml:"tabColor"`
+	TabColor                          *xlsxColor       `xml:"tabColor"`
 	OutlinePr                         *xlsxOutlinePr   `xml:"outlinePr"`
 	PageSetUpPr                       *xlsxPageSetUpPr `xml:"pageSetUpPr"`
 }
 
+// xlsxSheetCustomProperties directly maps the customProperties element. This
+// collection represents the custom properties of the worksheet, the value of
+// each property is stored in a separate binary custom property part.
+type xlsxSheetCustomProperties struct {
+	CustomPr []xlsxCustomPr `xml:"customPr"`
+}
+
+// xlsxCustomPr directly maps the customPr element. This element specifies a
+// single custom property of the worksheet by the name and relationship ID of
+// the custom property part.
+type xlsxCustomPr struct {
+	Name string `xml:"name,attr"`
+	RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+}
+
 // xlsxOutlinePr maps to the outlinePr element. SummaryBelow allows you to
 // adjust the direction of grouper controls.
 type xlsxOutlinePr struct {
@@ -455,6 +470,39 @@ type DataValidation struct {
 	Formula2         string  `xml:",innerxml"`
 }
 
+// decodeX14DataValidations directly maps the dataValidations element in the
+// extension list of the worksheet.
+type decodeX14DataValidations struct {
+	XMLName        xml.Name                   `xml:"dataValidations"`
+	DataValidation []*decodeX14DataValidation `xml:"dataValidation"`
+}
+
+// decodeX14DataValidation directly maps the dataValidation element in the
+// extension list of the worksheet, which the formulas and the reference
+// sequence are specified by the child elements.
+type decodeX14DataValidation struct {
+	AllowBlank       bool                            `xml:"allowBlank,attr"`
+	Error            *string                         `xml:"error,attr"`
+	ErrorStyle       *string                         `xml:"errorStyle,attr"`
+	ErrorTitle       *string                         `xml:"errorTitle,attr"`
+	Operator         string                          `xml:"operator,attr"`
+	Prompt           *string                         `xml:"prompt,attr"`
+	PromptTitle      *string                         `xml:"promptTitle,attr"`
+	ShowDropDown     bool                            `xml:"showDropDown,attr"`
+	ShowErrorMessage bool                            `xml:"showErrorMessage,attr"`
+	ShowInputMessage bool                            `xml:"showInputMessage,attr"`
+	Type             string                          `xml:"type,attr"`
+	Formula1         *decodeX14DataValidationFormula `xml:"formula1"`
+	Formula2         *decodeX14DataValidationFormula `xml:"formula2"`
+	Sqref            string                          `xml:"sqref"`
+}
+
+// decodeX14DataValidationFormula directly maps the formula1 and formula2
+// element of the data validation in the extension list of the worksheet.
+type decodeX14DataValidationFormula struct {
+	F string `xml:"f"`
+}
+
 // xlsxC collection represents a cell in the worksheet. Information about the
 // cell's location (reference), value, data type, formatting, and formula is
 // expressed here.
@@ -601,7 +649,7 @@ type xlsxDataBar struct {
 type xlsxIconSet struct {
 	Cfvo      []*xlsxCfvo `xml:"cfvo"`
 	IconSet   string      `xml:"iconSet,attr,omitempty"`
-	ShowValue bool        `xml:"showValue,attr,omitempty"`
+	ShowValue *bool       `xml:"showValue,attr"`
 	Percent   bool        `xml:"percent,attr,omitempty"`
 	Reverse   bool        `xml:"reverse,attr,omitempty"`
 }
@@ -742,6 +790,50 @@ type decodeX14SparklineGroups struct {
 	Content string   `xml:",innerxml"`
 }
 
+// decodeX14SparklineGroupList directly maps the sparklineGroups element for
+// reading the sparkline groups in the worksheet.
+type decodeX14SparklineGroupList struct {
+	XMLName         xml.Name                   `xml:"sparklineGroups"`
+	SparklineGroups []*decodeX14SparklineGroup `xml:"sparklineGroup"`
+}
+
+// decodeX14SparklineGroup directly maps the sparklineGroup element.
+type decodeX14SparklineGroup struct {
+	ManualMax           float64               `xml:"manualMax,attr"`
+	ManualMin           float64               `xml:"manualMin,attr"`
+	LineWeight          float64               `xml:"lineWeight,attr"`
+	Type                string                `xml:"type,attr"`
+	DateAxis            bool                  `xml:"dateAxis,attr"`
+	DisplayEmptyCellsAs string                `xml:"displayEmptyCellsAs,attr"`
+	Markers             bool                  `xml:"markers,attr"`
+	High                bool                  `xml:"high,attr"`
+	Low                 bool                  `xml:"low,attr"`
+	First               bool                  `xml:"first,attr"`
+	Last                bool                  `xml:"last,attr"`
+	Negative            bool                  `xml:"negative,attr"`
+	DisplayXAxis        bool                  `xml:"displayXAxis,attr"`
+	DisplayHidden       bool                  `xml:"displayHidden,attr"`
+	MinAxisType         string                `xml:"minAxisType,attr"`
+	MaxAxisType         string                `xml:"maxAxisType,attr"`
+	RightToLeft         bool                  `xml:"rightToLeft,attr"`
+	ColorSeries         *xlsxTabColor         `xml:"colorSeries"`
+	ColorNegative       *xlsxTabColor         `xml:"colorNegative"`
+	ColorAxis           *xlsxColor            `xml:"colorAxis"`
+	ColorMarkers        *xlsxTabColor         `xml:"colorMarkers"`
+	ColorFirst          *xlsxTabColor         `xml:"colorFirst"`
+	ColorLast           *xlsxTabColor         `xml:"colorLast"`
+	ColorHigh           *xlsxTabColor         `xml:"colorHigh"`
+	ColorLow            *xlsxTabColor         `xml:"colorLow"`
+	F                   string                `xml:"f"`
+	Sparklines          []*decodeX14Sparkline `xml:"sparklines>sparkline"`
+}
+
+// decodeX14Sparkline directly maps the sparkline element.
+type decodeX14Sparkline struct {
+	F     string `xml:"f"`
+	Sqref string `xml:"sqref"`
+}
+
 // xlsxX14SparklineGroups directly maps the sparklineGroups element.
 type xlsxX14SparklineGroups struct {
 	XMLName         xml.Name                 `xml:"x14:sparklineGroups"`
@@ -753,8 +845,8 @@ type xlsxX14SparklineGroups struct {
 // xlsxX14SparklineGroup directly maps the sparklineGroup element.
 type xlsxX14SparklineGroup struct {
 	XMLName             xml.Name          `xml:"x14:sparklineGroup"`
-	ManualMax           int               `xml:"manualMax,attr,omitempty"`
-	ManualMin           int               `xml:"manualMin,attr,omitempty"`
+	ManualMax           float64           `xml:"manualMax,attr,omitempty"`
+	ManualMin           float64           `xml:"manualMin,attr,omitempty"`
 	LineWeight          float64           `xml:"lineWeight,attr,omitempty"`
 	Type                string            `xml:"type,attr,omitempty"`
 	DateAxis            bool              `xml:"dateAxis,attr,omitempty"`
@@ -778,6 +870,7 @@ type xlsxX14SparklineGroup struct {
 	ColorLast           *xlsxTabColor     `xml:"x14:colorLast"`
 	ColorHigh           *xlsxTabColor     `xml:"x14:colorHigh"`
 	ColorLow            *xlsxTabColor     `xml:"x14:colorLow"`
+	F                   string            `xml:"xm:f,omitempty"`
 	Sparklines          xlsxX14Sparklines `xml:"x14:sparklines"`
 }
 
@@ -794,15 +887,26 @@ type xlsxX14Sparkline struct {
 
 // SparklineOptions directly maps the settings of the sparkline.
 type SparklineOptions struct {
-	Location      []string
-	Range         []string
-	Max           int
-	CustMax       int
-	Min           int
+	Location []string
+	Range    []string
+	// Deprecated: Max has no effect, use MaxAxisType instead.
+	Max int
+	// Deprecated: Use CustomMax instead, CustMax will be used as the custom
+	// maximum of the vertical axis if CustomMax is zero.
+	CustMax int
+	// Deprecated: Min has no effect, use MinAxisType instead.
+	Min int
+	// Deprecated: Use CustomMin instead, CustMin will be used as the custom
+	// minimum of the vertical axis if CustomMin is zero.
 	CustMin       int
+	MaxAxisType   string
+	CustomMax     float64
+	MinAxisType   string
+	CustomMin     float64
 	Type          string
 	Weight        float64
 	DateAxis      bool
+	DateRange     string
 	Markers       bool
 	High          bool
 	Low           bool
@@ -845,6 +949,8 @@ type Panes struct {
 type ConditionalFormatOptions struct {
 	Type         string
 	AboveAverage bool
+	StdDev       int
+	EqualAverage bool
 	Percent      bool
 	Format       int
 	Criteria     string
@@ -863,6 +969,11 @@ type ConditionalFormatOptions struct {
 	MinLength    string
 	MaxLength    string
 	BarColor     string
+	IconStyle    string
+	ReverseIcons bool
+	IconsOnly    bool
+	StopIfTrue   bool
+	Priority     int
 }
 
 // SheetProtectionOptions directly maps the settings of worksheet protection.
@@ -933,6 +1044,16 @@ type PageLayoutOptions struct {
 	BlackAndWhite *bool
 }
 
+// SheetDimension directly maps the dimension of the used cells in the
+// worksheet. The Ref is the range reference of the used cells such as
+// "A1:D10", which will be empty for the blank worksheet, the Rows and Cols are
+// the number of the last row and column of the used cells.
+type SheetDimension struct {
+	Ref  string
+	Rows int
+	Cols int
+}
+
 // ViewOptions directly maps the settings of sheet view.
 type ViewOptions struct {
 	// DefaultGridColor indicating that the consuming application should use
@@ -983,6 +1104,9 @@ type SheetPropsOptions struct {
 	// rules shall not be updated. Essentially the conditional
 	// formatting "calc" is off.
 	EnableFormatConditionsCalculation *bool
+	// FilterMode indicating whether the worksheet has one or more autofilters
+	// or advanced filters on.
+	FilterMode *bool
 	// Published indicating whether the worksheet is published.
 	Published *bool
 	// AutoPageBreaks indicating whether the sheet displays Automatic Page
@@ -1027,4 +1151,62 @@ type SheetPropsOptions struct {
 	ThickTop *bool
 	// ThickBottom specifies if rows have a thick bottom border by default.
 	ThickBottom *bool
+	// SyncHorizontal indicating whether the worksheet is horizontally
+	// synchronized with the SyncRef when scrolling.
+	SyncHorizontal *bool
+	// SyncVertical indicating whether the worksheet is vertically
+	// synchronized with the SyncRef when scrolling.
+	SyncVertical *bool
+	// SyncRef specifies the anchor cell reference of the synchronized
+	// scrolling.
+	SyncRef *string
+	// TransitionEvaluation indicating whether the Lotus compatible formula
+	// evaluation rules shall be used on this worksheet.
+	TransitionEvaluation *bool
+	// TransitionEntry indicating whether the Lotus compatible formula entry
+	// rules shall be used on this worksheet.
+	TransitionEntry *bool
+	// OutlineApplyStyles indicating whether the outline styles are applied
+	// automatically.
+	OutlineApplyStyles *bool
+	// OutlineShowSymbols indicating whether the outline symbols are shown.
+	OutlineShowSymbols *bool
+	// OutlineLevelRow specifies the highest outline level of the rows.
+	OutlineLevelRow *uint8
+	// OutlineLevelCol specifies the highest outline level of the columns.
+	OutlineLevelCol *uint8
+}
+
+// SheetCustomProperty directly maps the custom property of the worksheet,
+// which is stored as a Unicode string.
+type SheetCustomProperty struct {
+	Name  string
+	Value string
+}
+
+// SheetFormatOptions directly maps the default row and column formatting
+// settings of the worksheet.
+type SheetFormatOptions struct {
+	// BaseColWidth specifies the number of characters of the maximum digit
+	// width of the normal style's font, the default value is 8.
+	BaseColWidth *uint8
+	// DefaultColWidth specifies the default column width measured as the
+	// number of characters of the maximum digit width of the normal style's
+	// font.
+	DefaultColWidth *float64
+	// DefaultRowHeight specifies the default row height measured in point
+	// size.
+	DefaultRowHeight *float64
+	// CustomHeight specifies if the default row height has been manually set.
+	CustomHeight *bool
+	// ZeroHeight specifies if rows are hidden by default.
+	ZeroHeight *bool
+	// ThickTop specifies if rows have a thick top border by default.
+	ThickTop *bool
+	// ThickBottom specifies if rows have a thick bottom border by default.
+	ThickBottom *bool
+	// OutlineLevelRow specifies the highest outline level of the rows.
+	OutlineLevelRow *uint8
+	// OutlineLevelCol specifies the highest outline level of the columns.
+	OutlineLevelCol *uint8
 }